@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package addstakeholdercmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	gojose "github.com/square/go-jose/v3"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	domainFlagName  = "stakeholder-domain"
+	domainEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_DOMAIN"
+	domainFlagUsage = "Domain of the stakeholder to add." +
+		" Alternatively, this can be set with the following environment variable: " + domainEnvKey
+
+	didFlagName  = "stakeholder-did"
+	didEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_DID"
+	didFlagUsage = "DID of the stakeholder to add." +
+		" Alternatively, this can be set with the following environment variable: " + didEnvKey
+
+	endpointsFlagName  = "stakeholder-endpoints"
+	endpointsEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_ENDPOINTS"
+	endpointsFlagUsage = "Comma-Separated list of the sidetree endpoints of the stakeholder to add." +
+		" Alternatively, this can be set with the following environment variable: " + endpointsEnvKey
+
+	jwkFileFlagName  = "stakeholder-jwk-file"
+	jwkFileEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_JWK_FILE"
+	jwkFileFlagUsage = "The file that contains the JWK of the key the stakeholder to add signs its configs with." +
+		" If the JWK is a private key, the stakeholder config is signed with it, otherwise the stakeholder config is" +
+		" written unsigned, for the stakeholder to sign it with sign-config." +
+		" Alternatively, this can be set with the following environment variable: " + jwkFileEnvKey
+
+	maxAgeFlagName  = "stakeholder-max-age"
+	maxAgeEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_MAX_AGE"
+	maxAgeFlagUsage = "Cache lifetime in seconds of the stakeholder config." +
+		" Defaults to the cache lifetime of the consortium config." +
+		" Alternatively, this can be set with the following environment variable: " + maxAgeEnvKey
+)
+
+type parameters struct {
+	domain    string
+	did       string
+	endpoints []string
+	jwk       *gojose.JSONWebKey
+	maxAge    string
+}
+
+// GetAddStakeholderCmd returns the Cobra add stakeholder command.
+func GetAddStakeholderCmd() *cobra.Command {
+	addStakeholderCmd := createAddStakeholderCmd()
+
+	createFlags(addStakeholderCmd)
+
+	return addStakeholderCmd
+}
+
+func createAddStakeholderCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-stakeholder",
+		Short: "Add a stakeholder to a consortium",
+		Long: "Create the next version of a consortium config, with a stakeholder added to its members and signed by" +
+			" a current member, and the config of the stakeholder. The next version has to be endorsed by as many" +
+			" current members as the current consortium policy requires before it can be published.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parameters, err := getParameters(cmd)
+			if err != nil {
+				return err
+			}
+
+			current, currentBytes, err := configcommon.ReadConsortium(cmd)
+			if err != nil {
+				return err
+			}
+
+			next, err := addMember(current.Config, parameters)
+			if err != nil {
+				return err
+			}
+
+			stakeholderData, err := stakeholderConfig(parameters, current.Config)
+			if err != nil {
+				return err
+			}
+
+			err = configcommon.WriteSuccessor(cmd, current, currentBytes, next,
+				map[string][]byte{parameters.domain: stakeholderData})
+			if err != nil {
+				return err
+			}
+
+			if parameters.jwk.IsPublic() {
+				fmt.Fprintf(cmd.OutOrStdout(), "stakeholder config %s is unsigned, the stakeholder has to sign it with"+
+					" sign-config before it can be published\n", parameters.domain)
+			}
+
+			return nil
+		},
+	}
+}
+
+func getParameters(cmd *cobra.Command) (*parameters, error) {
+	domain, err := cmdutils.GetUserSetVarFromString(cmd, domainFlagName, domainEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	did, err := cmdutils.GetUserSetVarFromString(cmd, didFlagName, didEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := cmdutils.GetUserSetVarFromArrayString(cmd, endpointsFlagName, endpointsEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	jwkFile, err := cmdutils.GetUserSetVarFromString(cmd, jwkFileFlagName, jwkFileEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	jwkBytes, err := ioutil.ReadFile(filepath.Clean(jwkFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwk file '%s' : %w", jwkFile, err)
+	}
+
+	jwk := &gojose.JSONWebKey{}
+	if err := jwk.UnmarshalJSON(jwkBytes); err != nil {
+		return nil, fmt.Errorf("failed to parse jwk file '%s' : %w", jwkFile, err)
+	}
+
+	maxAge := cmdutils.GetUserSetOptionalVarFromString(cmd, maxAgeFlagName, maxAgeEnvKey)
+
+	return &parameters{domain: domain, did: did, endpoints: endpoints, jwk: jwk, maxAge: maxAge}, nil
+}
+
+func addMember(current *models.Consortium, parameters *parameters) (*models.Consortium, error) {
+	for _, member := range current.Members {
+		if member.Domain == parameters.domain {
+			return nil, fmt.Errorf("stakeholder %s is already a member of the consortium", parameters.domain)
+		}
+	}
+
+	publicKey, err := parameters.jwk.Public().MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stakeholder public key: %w", err)
+	}
+
+	next := *current
+	next.Members = append(append([]*models.StakeholderListElement{}, current.Members...),
+		&models.StakeholderListElement{Domain: parameters.domain, DID: parameters.did,
+			PublicKey: models.PublicKey{ID: parameters.did + "#" + parameters.jwk.KeyID, JWK: publicKey}})
+
+	return &next, nil
+}
+
+func stakeholderConfig(parameters *parameters, consortium *models.Consortium) ([]byte, error) {
+	stakeholder := models.Stakeholder{Domain: parameters.domain, DID: parameters.did,
+		Policy: models.StakeholderSettings{Cache: consortium.Policy.Cache}, Endpoints: parameters.endpoints}
+
+	if parameters.maxAge != "" {
+		maxAge, err := strconv.ParseUint(parameters.maxAge, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", maxAgeFlagName, err)
+		}
+
+		stakeholder.Policy.Cache.MaxAge = uint32(maxAge)
+	}
+
+	stakeholderBytes, err := json.Marshal(stakeholder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stakeholder config: %w", err)
+	}
+
+	if parameters.jwk.IsPublic() {
+		return stakeholderBytes, nil
+	}
+
+	signingKey, err := configcommon.SigningKey(parameters.jwk.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stakeholder signing key: %w", err)
+	}
+
+	jws, err := configcommon.AddSignature(stakeholderBytes, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(jws), nil
+}
+
+func createFlags(startCmd *cobra.Command) {
+	configcommon.AddSuccessionFlags(startCmd)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFlagUsage)
+	startCmd.Flags().StringP(didFlagName, "", "", didFlagUsage)
+	startCmd.Flags().StringArrayP(endpointsFlagName, "", []string{}, endpointsFlagUsage)
+	startCmd.Flags().StringP(jwkFileFlagName, "", "", jwkFileFlagUsage)
+	startCmd.Flags().StringP(maxAgeFlagName, "", "", maxAgeFlagUsage)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package addstakeholdercmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test stakeholder domain is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetAddStakeholderCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither stakeholder-domain (command line flag) nor "+
+			"DID_METHOD_CLI_STAKEHOLDER_DOMAIN (environment variable) have been set.")
+	})
+
+	t.Run("test consortium file is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetAddStakeholderCmd()
+
+		jwkFile := writeFile(t, jwk(t, generateKey(t), false))
+		defer func() { require.NoError(t, os.Remove(jwkFile)) }()
+
+		cmd.SetArgs([]string{flag + domainFlagName, "stakeholder.three", flag + didFlagName, "did:trustbloc:3",
+			flag + endpointsFlagName, "https://stakeholder.three/sidetree", flag + jwkFileFlagName, jwkFile})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither consortium-file (command line flag) nor "+
+			"DID_METHOD_CLI_CONSORTIUM_FILE (environment variable) have been set.")
+	})
+}
+
+func TestAddStakeholder(t *testing.T) {
+	key1 := generateKey(t)
+	key2 := generateKey(t)
+	key3 := generateKey(t)
+
+	consortiumBytes := consortiumConfig(t, key1, key2)
+
+	consortiumFile := writeFile(t, string(consortiumBytes))
+	defer func() { require.NoError(t, os.Remove(consortiumFile)) }()
+
+	publicJWKFile := writeFile(t, jwk(t, key3, true))
+	defer func() { require.NoError(t, os.Remove(publicJWKFile)) }()
+
+	privateJWKFile := writeFile(t, jwk(t, key3, false))
+	defer func() { require.NoError(t, os.Remove(privateJWKFile)) }()
+
+	args := func(dir, jwkFile string) []string {
+		return []string{flag + configcommon.ConsortiumFileFlagName, consortiumFile,
+			flag + configcommon.OutputDirectoryFlagName, dir, flag + domainFlagName, "stakeholder.three",
+			flag + didFlagName, "did:trustbloc:3", flag + endpointsFlagName, "https://stakeholder.three/sidetree",
+			flag + jwkFileFlagName, jwkFile, flag + common.SigningKeyJWKFlagName, jwk(t, key1, false)}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		dir := tempDir(t)
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetAddStakeholderCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(args(dir, publicJWKFile))
+		require.NoError(t, cmd.Execute())
+
+		require.Contains(t, out.String(), "endorsed by stakeholder.one (1 of the 2 endorsements required)")
+		require.Contains(t, out.String(), "1 more members of the current consortium have to sign the config")
+		require.Contains(t, out.String(), "stakeholder config stakeholder.three is unsigned")
+
+		nextBytes, err := ioutil.ReadFile(filepath.Join(dir, "did-trustbloc", "consortium.net.json"))
+		require.NoError(t, err)
+
+		next, err := models.ParseConsortium(nextBytes)
+		require.NoError(t, err)
+		require.Len(t, next.Config.Members, 3)
+		require.Equal(t, "stakeholder.three", next.Config.Members[2].Domain)
+		require.Equal(t, configcommon.ConfigHash(consortiumBytes), next.Config.Previous)
+
+		_, err = next.JWS.Verify(key1.Public())
+		require.NoError(t, err)
+
+		history, err := ioutil.ReadFile(filepath.Join(dir, "history", next.Config.Previous+".json"))
+		require.NoError(t, err)
+		require.Equal(t, consortiumBytes, history)
+
+		stakeholderBytes, err := ioutil.ReadFile(filepath.Join(dir, "did-trustbloc", "stakeholder.three.json"))
+		require.NoError(t, err)
+
+		stakeholder := models.Stakeholder{}
+		require.NoError(t, json.Unmarshal(stakeholderBytes, &stakeholder))
+		require.Equal(t, "did:trustbloc:3", stakeholder.DID)
+		require.Equal(t, []string{"https://stakeholder.three/sidetree"}, stakeholder.Endpoints)
+	})
+
+	t.Run("success with the stakeholder private key", func(t *testing.T) {
+		os.Clearenv()
+
+		dir := tempDir(t)
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetAddStakeholderCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs(append(args(dir, privateJWKFile), flag+maxAgeFlagName, "600"))
+		require.NoError(t, cmd.Execute())
+
+		stakeholderBytes, err := ioutil.ReadFile(filepath.Join(dir, "did-trustbloc", "stakeholder.three.json"))
+		require.NoError(t, err)
+
+		stakeholder, err := models.ParseStakeholder(stakeholderBytes)
+		require.NoError(t, err)
+		require.Equal(t, uint32(600), stakeholder.Config.Policy.Cache.MaxAge)
+
+		_, err = stakeholder.JWS.Verify(key3.Public())
+		require.NoError(t, err)
+	})
+
+	t.Run("test stakeholder is already a member", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetAddStakeholderCmd()
+
+		arguments := args("", publicJWKFile)
+		arguments[5] = "stakeholder.two"
+		cmd.SetArgs(arguments)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stakeholder stakeholder.two is already a member of the consortium")
+	})
+
+	t.Run("test invalid max age", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetAddStakeholderCmd()
+		cmd.SetArgs(append(args("", publicJWKFile), flag+maxAgeFlagName, "forever"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for stakeholder-max-age")
+	})
+
+	t.Run("test jwk file not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetAddStakeholderCmd()
+		cmd.SetArgs(args("", "wrong"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read jwk file 'wrong'")
+	})
+}
+
+func consortiumConfig(t *testing.T, keys ...ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	consortium := models.Consortium{Domain: "consortium.net",
+		Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 2419200}, NumQueries: 2}}
+
+	var signingKeys []jose.SigningKey
+
+	for i, key := range keys {
+		domain := []string{"stakeholder.one", "stakeholder.two"}[i]
+
+		consortium.Members = append(consortium.Members, &models.StakeholderListElement{Domain: domain,
+			DID: "did:trustbloc:" + domain, PublicKey: models.PublicKey{JWK: json.RawMessage(jwk(t, key, true))}})
+
+		signingKeys = append(signingKeys, jose.SigningKey{Key: key, Algorithm: jose.EdDSA})
+	}
+
+	consortiumBytes, err := json.Marshal(consortium)
+	require.NoError(t, err)
+
+	jws, err := configcommon.SignConfig(consortiumBytes, signingKeys)
+	require.NoError(t, err)
+
+	return []byte(jws)
+}
+
+func generateKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return privateKey
+}
+
+func jwk(t *testing.T, privateKey ed25519.PrivateKey, public bool) string {
+	t.Helper()
+
+	key := &jose.JSONWebKey{Key: privateKey, KeyID: "key1"}
+	if public {
+		key = &jose.JSONWebKey{Key: privateKey.Public(), KeyID: "key1"}
+	}
+
+	jwkBytes, err := key.MarshalJSON()
+	require.NoError(t, err)
+
+	return string(jwkBytes)
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	return dir
+}
+
+func writeFile(t *testing.T, data string) string {
+	t.Helper()
+
+	file, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+
+	return file.Name()
+}
@@ -0,0 +1,305 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bootstraptestnetcmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gojose "github.com/square/go-jose/v3"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	outputDirectoryFlagName  = "output-directory"
+	outputDirectoryEnvKey    = "DID_METHOD_CLI_OUTPUT_DIRECTORY"
+	outputDirectoryFlagUsage = "Directory to write the testnet to. It must not exist yet." +
+		" Alternatively, this can be set with the following environment variable: " + outputDirectoryEnvKey
+
+	domainFlagName  = "domain"
+	domainEnvKey    = "DID_METHOD_CLI_DOMAIN"
+	domainFlagUsage = "Domain of the consortium, served on port 443 unless the domain has a port." +
+		" Defaults to " + defaultDomain + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + domainEnvKey
+
+	stakeholderDomainFlagName  = "stakeholder-domain"
+	stakeholderDomainEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_DOMAIN"
+	stakeholderDomainFlagUsage = "Domain of the single stakeholder of the consortium, served on port 443 unless" +
+		" the domain has a port. Defaults to " + defaultStakeholderDomain + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + stakeholderDomainEnvKey
+
+	sidetreeMockImageFlagName  = "sidetree-mock-image"
+	sidetreeMockImageEnvKey    = "DID_METHOD_CLI_SIDETREE_MOCK_IMAGE"
+	sidetreeMockImageFlagUsage = "Docker image of the sidetree node of the testnet." +
+		" Defaults to " + defaultSidetreeMockImage + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeMockImageEnvKey
+
+	sidetreeWriteTokenFlagName  = "sidetree-write-token"
+	sidetreeWriteTokenEnvKey    = "DID_METHOD_CLI_SIDETREE_WRITE_TOKEN" //nolint: gosec
+	sidetreeWriteTokenFlagUsage = "The sidetree write token of the sidetree node of the testnet." +
+		" Defaults to " + defaultSidetreeWriteToken + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeWriteTokenEnvKey
+
+	defaultDomain             = "testnet.trustbloc.local"
+	defaultStakeholderDomain  = "stakeholder.one:8088"
+	defaultSidetreeMockImage  = "docker.pkg.github.com/trustbloc-cicd/snapshot/sidetree-mock:0.1.5-snapshot-09e7b37"
+	defaultSidetreeWriteToken = "rw_token"
+
+	defaultPort  = "443"
+	sidetreePort = "48326"
+
+	consortiumMaxAge  = 2419200
+	stakeholderMaxAge = 604800
+
+	stakeholderKeyID   = "key1"
+	stakeholderKeyFile = "keys/stakeholder_jwk.json"
+	configFileName     = "config.json"
+	composeFileName    = "docker-compose.yml"
+	scriptFileName     = "bootstrap.sh"
+
+	dirPermissions        = 0700
+	filePermissions       = 0644
+	privateKeyPermissions = 0600
+	scriptPermissions     = 0755
+)
+
+// testnet is a single-stakeholder development consortium
+type testnet struct {
+	OutputDirectory    string
+	Domain             string
+	DomainHost         string
+	DomainPort         string
+	Stakeholder        string
+	StakeholderHost    string
+	StakeholderPort    string
+	SidetreeURL        string
+	SidetreePort       string
+	SidetreeMockImage  string
+	SidetreeWriteToken string
+}
+
+// createConfigInput is the create-config config file of the testnet
+type createConfigInput struct {
+	ConsortiumData configcommon.ConsortiumData `json:"consortiumData"`
+	MembersData    []*memberInput              `json:"membersData"`
+}
+
+type memberInput struct {
+	Domain            string                     `json:"domain"`
+	Policy            models.StakeholderSettings `json:"policy"`
+	Endpoints         []string                   `json:"endpoints"`
+	PrivateKeyJwkPath string                     `json:"privateKeyJwkPath"`
+}
+
+// GetBootstrapTestnetCmd returns the Cobra bootstrap testnet command.
+func GetBootstrapTestnetCmd() *cobra.Command {
+	bootstrapTestnetCmd := createBootstrapTestnetCmd()
+
+	createFlags(bootstrapTestnetCmd)
+
+	return bootstrapTestnetCmd
+}
+
+func createBootstrapTestnetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bootstrap-testnet",
+		Short: "Bootstrap a local single-stakeholder testnet",
+		Long: "Write the keys, the create-config config file, a docker-compose file and a bootstrap script of a" +
+			" local development consortium with a single stakeholder and a sidetree mock node. Running the" +
+			" bootstrap script starts the testnet and creates its consortium and stakeholder configs.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := getTestnet(cmd)
+			if err != nil {
+				return err
+			}
+
+			if _, err = os.Stat(t.OutputDirectory); err == nil {
+				return fmt.Errorf("output directory '%s' already exists", t.OutputDirectory)
+			}
+
+			if err = writeKeys(cmd.OutOrStdout(), t); err != nil {
+				return err
+			}
+
+			if err = writeTestnetFiles(cmd.OutOrStdout(), t); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), nextSteps, t.DomainHost, t.StakeholderHost,
+				filepath.Join(t.OutputDirectory, scriptFileName), t.Domain,
+				filepath.Join(t.OutputDirectory, tlsCertFile), t.SidetreeWriteToken)
+
+			return nil
+		},
+	}
+}
+
+const nextSteps = `
+testnet written. Next steps:
+  1. add the testnet domains to /etc/hosts: 127.0.0.1 %s %s
+  2. start the testnet: %s
+  3. create a DID, with keys from generate-keys:
+     create-did --domain %s --tls-cacerts %s --sidetree-write-token %s
+     --publickey-file ./keys/publickeys.json
+     --recoverykey-file ./keys/recover/public.pem --updatekey-file ./keys/update/public.pem
+`
+
+func getTestnet(cmd *cobra.Command) (*testnet, error) {
+	outputDirectory, err := cmdutils.GetUserSetVarFromString(cmd, outputDirectoryFlagName, outputDirectoryEnvKey,
+		false)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &testnet{
+		OutputDirectory: outputDirectory,
+		Domain:          getOptional(cmd, domainFlagName, domainEnvKey, defaultDomain),
+		Stakeholder:     getOptional(cmd, stakeholderDomainFlagName, stakeholderDomainEnvKey, defaultStakeholderDomain),
+		SidetreePort:    sidetreePort,
+		SidetreeURL:     "https://localhost:" + sidetreePort + "/sidetree/0.0.1",
+		SidetreeMockImage: getOptional(cmd, sidetreeMockImageFlagName, sidetreeMockImageEnvKey,
+			defaultSidetreeMockImage),
+		SidetreeWriteToken: getOptional(cmd, sidetreeWriteTokenFlagName, sidetreeWriteTokenEnvKey,
+			defaultSidetreeWriteToken),
+	}
+
+	t.DomainHost, t.DomainPort = splitDomain(t.Domain)
+	t.StakeholderHost, t.StakeholderPort = splitDomain(t.Stakeholder)
+
+	if t.DomainPort == t.StakeholderPort {
+		return nil, fmt.Errorf("the consortium and the stakeholder are both served on port %s:"+
+			" set a different port in --%s", t.DomainPort, stakeholderDomainFlagName)
+	}
+
+	if t.DomainPort == sidetreePort || t.StakeholderPort == sidetreePort {
+		return nil, fmt.Errorf("port %s is used by the sidetree node of the testnet", sidetreePort)
+	}
+
+	return t, nil
+}
+
+func getOptional(cmd *cobra.Command, flagName, envKey, defaultValue string) string {
+	value := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// splitDomain splits a domain into its host and the port it is served on
+func splitDomain(domain string) (string, string) {
+	i := strings.LastIndex(domain, ":")
+	if i < 0 {
+		return domain, defaultPort
+	}
+
+	return domain[:i], domain[i+1:]
+}
+
+// writeKeys writes the signing key of the stakeholder, the recovery and update keys of its DID
+// and the TLS certificate of the testnet
+func writeKeys(out io.Writer, t *testnet) error {
+	keysDirectory := filepath.Join(t.OutputDirectory, "keys")
+
+	if err := os.MkdirAll(keysDirectory, dirPermissions); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	jwk, err := (&gojose.JSONWebKey{Key: privateKey, KeyID: stakeholderKeyID}).MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal stakeholder key: %w", err)
+	}
+
+	err = common.WriteKeyFile(out, filepath.Join(t.OutputDirectory, stakeholderKeyFile), jwk, privateKeyPermissions)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{"recover", "update"} {
+		_, err = common.GeneratePEMKeyPair(out, filepath.Join(keysDirectory, dir), doc.P256KeyType, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTLSCertificate(out, t)
+}
+
+// writeTestnetFiles writes the create-config config file, the docker-compose file and the bootstrap script
+func writeTestnetFiles(out io.Writer, t *testnet) error {
+	config := &createConfigInput{
+		ConsortiumData: configcommon.ConsortiumData{Domain: t.Domain,
+			Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: consortiumMaxAge}, NumQueries: 1}},
+		MembersData: []*memberInput{{Domain: t.Stakeholder,
+			Policy:            models.StakeholderSettings{Cache: models.CacheControl{MaxAge: stakeholderMaxAge}},
+			Endpoints:         []string{t.SidetreeURL},
+			PrivateKeyJwkPath: stakeholderKeyFile}},
+	}
+
+	configBytes, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(out, filepath.Join(t.OutputDirectory, configFileName), configBytes, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	compose, err := render(composeTemplate, t)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(out, filepath.Join(t.OutputDirectory, composeFileName), compose, filePermissions)
+	if err != nil {
+		return err
+	}
+
+	script, err := render(scriptTemplate, t)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(out, filepath.Join(t.OutputDirectory, scriptFileName), script, scriptPermissions)
+}
+
+func writeFile(out io.Writer, path string, data []byte, perm os.FileMode) error {
+	if err := ioutil.WriteFile(filepath.Clean(path), data, perm); err != nil {
+		return fmt.Errorf("failed to write file '%s': %w", path, err)
+	}
+
+	fmt.Fprintf(out, "wrote %s\n", path)
+
+	return nil
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(outputDirectoryFlagName, "", "", outputDirectoryFlagUsage)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFlagUsage)
+	startCmd.Flags().StringP(stakeholderDomainFlagName, "", "", stakeholderDomainFlagUsage)
+	startCmd.Flags().StringP(sidetreeMockImageFlagName, "", "", sidetreeMockImageFlagUsage)
+	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bootstraptestnetcmd
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gojose "github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test output directory is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetBootstrapTestnetCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither output-directory (command line flag) nor "+
+			"DID_METHOD_CLI_OUTPUT_DIRECTORY (environment variable) have been set.")
+	})
+}
+
+func TestBootstrapTestnet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		outputDirectory := filepath.Join(dir, "testnet")
+
+		cmd := GetBootstrapTestnetCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + outputDirectoryFlagName, outputDirectory})
+		require.NoError(t, cmd.Execute())
+
+		require.Contains(t, out.String(), "127.0.0.1 testnet.trustbloc.local stakeholder.one")
+		require.Contains(t, out.String(), "create-did --domain testnet.trustbloc.local")
+
+		configBytes, err := ioutil.ReadFile(filepath.Join(outputDirectory, configFileName))
+		require.NoError(t, err)
+
+		config := createConfigInput{}
+		require.NoError(t, json.Unmarshal(configBytes, &config))
+		require.Equal(t, "testnet.trustbloc.local", config.ConsortiumData.Domain)
+		require.Equal(t, 1, config.ConsortiumData.Policy.NumQueries)
+		require.Len(t, config.MembersData, 1)
+		require.Equal(t, "stakeholder.one:8088", config.MembersData[0].Domain)
+		require.Equal(t, []string{"https://localhost:48326/sidetree/0.0.1"}, config.MembersData[0].Endpoints)
+
+		jwkBytes, err := ioutil.ReadFile(filepath.Join(outputDirectory, config.MembersData[0].PrivateKeyJwkPath))
+		require.NoError(t, err)
+
+		jwk := gojose.JSONWebKey{}
+		require.NoError(t, jwk.UnmarshalJSON(jwkBytes))
+		require.False(t, jwk.IsPublic())
+		require.Equal(t, "key1", jwk.KeyID)
+
+		require.FileExists(t, filepath.Join(outputDirectory, "keys", "recover", "public.pem"))
+		require.FileExists(t, filepath.Join(outputDirectory, "keys", "update", "private.pem"))
+
+		certBytes, err := ioutil.ReadFile(filepath.Join(outputDirectory, tlsCertFile))
+		require.NoError(t, err)
+
+		block, _ := pem.Decode(certBytes)
+		require.NotNil(t, block)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		require.NoError(t, cert.VerifyHostname("testnet.trustbloc.local"))
+		require.NoError(t, cert.VerifyHostname("stakeholder.one"))
+
+		compose, err := ioutil.ReadFile(filepath.Join(outputDirectory, composeFileName))
+		require.NoError(t, err)
+		require.Contains(t, string(compose), `"8088:8088"`)
+		require.Contains(t, string(compose), "./well-known/testnet.trustbloc.local:/web")
+		require.Contains(t, string(compose), "SIDETREE_MOCK_API_TOKEN=rw_token")
+
+		script, err := ioutil.ReadFile(filepath.Join(outputDirectory, scriptFileName))
+		require.NoError(t, err)
+		require.Contains(t, string(script), "create-config --sidetree-url https://localhost:48326/sidetree/0.0.1")
+	})
+
+	t.Run("test output directory already exists", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetBootstrapTestnetCmd()
+		cmd.SetArgs([]string{flag + outputDirectoryFlagName, dir})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("test consortium and stakeholder on the same port", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetBootstrapTestnetCmd()
+		cmd.SetArgs([]string{flag + outputDirectoryFlagName, filepath.Join(dir, "other"),
+			flag + stakeholderDomainFlagName, "stakeholder.one"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "both served on port 443")
+	})
+}
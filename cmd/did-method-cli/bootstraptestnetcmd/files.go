@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bootstraptestnetcmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+)
+
+const (
+	tlsCertFile = "keys/tls/cert.pem"
+	tlsKeyFile  = "keys/tls/key.pem"
+
+	certificateValidity = 365 * 24 * time.Hour
+	serialNumberBits    = 128
+)
+
+// writeTLSCertificate writes a self-signed TLS certificate for the hosts of the testnet, which is also the CA
+// certificate to pass to the CLI commands with --tls-cacerts
+func writeTLSCertificate(out io.Writer, t *testnet) error {
+	if err := os.MkdirAll(filepath.Join(t.OutputDirectory, filepath.Dir(tlsCertFile)), dirPermissions); err != nil {
+		return fmt.Errorf("failed to create tls directory: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	certTemplate := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{Organization: []string{"TrustBloc testnet"}, CommonName: t.DomainHost},
+		NotBefore:             now,
+		NotAfter:              now.Add(certificateValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{t.DomainHost, t.StakeholderHost, "localhost", "sidetree-mock"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("failed to create tls certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tls key: %w", err)
+	}
+
+	err = writeFile(out, filepath.Join(t.OutputDirectory, tlsKeyFile),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), privateKeyPermissions)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(out, filepath.Join(t.OutputDirectory, tlsCertFile),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), filePermissions)
+}
+
+func render(text string, t *testnet) ([]byte, error) {
+	tmpl, err := template.New("").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bytes.Buffer{}
+
+	if err = tmpl.Execute(b, t); err != nil {
+		return nil, fmt.Errorf("failed to render testnet file: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+const composeTemplate = `#
+# Local single-stakeholder TrustBloc testnet, written by did-method-cli bootstrap-testnet.
+# Start it with bootstrap.sh, which creates the consortium and stakeholder configs first.
+#
+version: '3'
+
+services:
+
+  sidetree:
+    container_name: sidetree-mock
+    image: {{.SidetreeMockImage}}
+    environment:
+      - SIDETREE_MOCK_TLS_CERTIFICATE=/etc/sidetree/tls/cert.pem
+      - SIDETREE_MOCK_TLS_KEY=/etc/sidetree/tls/key.pem
+      - SIDETREE_MOCK_HOST=0.0.0.0
+      - SIDETREE_MOCK_PORT={{.SidetreePort}}
+      - SIDETREE_MOCK_API_TOKEN={{.SidetreeWriteToken}}
+      - SIDETREE_MOCK_DID_NAMESPACE=did:trustbloc:{{.Domain}}
+    ports:
+      - {{.SidetreePort}}:{{.SidetreePort}}
+    volumes:
+      - ./keys/tls:/etc/sidetree/tls
+
+  consortium:
+    container_name: {{.DomainHost}}
+    image: halverneus/static-file-server:latest
+    environment:
+      - PORT={{.DomainPort}}
+      - FOLDER=/web
+      - CORS=true
+      - TLS_CERT=/etc/tls/cert.pem
+      - TLS_KEY=/etc/tls/key.pem
+    ports:
+      - "{{.DomainPort}}:{{.DomainPort}}"
+    volumes:
+      - ./well-known/{{.Domain}}:/web
+      - ./keys/tls:/etc/tls
+
+  stakeholder:
+    container_name: {{.StakeholderHost}}
+    image: halverneus/static-file-server:latest
+    environment:
+      - PORT={{.StakeholderPort}}
+      - FOLDER=/web
+      - CORS=true
+      - TLS_CERT=/etc/tls/cert.pem
+      - TLS_KEY=/etc/tls/key.pem
+    ports:
+      - "{{.StakeholderPort}}:{{.StakeholderPort}}"
+    volumes:
+      - ./well-known/{{.Stakeholder}}:/web
+      - ./keys/tls:/etc/tls
+`
+
+const scriptTemplate = `#!/bin/sh
+#
+# Starts the local single-stakeholder TrustBloc testnet written by did-method-cli bootstrap-testnet.
+# The did-method-cli binary is taken from the DID_METHOD_CLI environment variable, or else from the PATH.
+#
+set -e
+
+cd "$(dirname "$0")"
+
+DID_METHOD_CLI=${DID_METHOD_CLI:-did-method-cli}
+
+docker-compose up -d sidetree
+
+echo "waiting for the sidetree node"
+until curl -s -o /dev/null --cacert keys/tls/cert.pem https://localhost:{{.SidetreePort}}; do
+  sleep 1
+done
+
+if [ ! -d well-known ]; then
+  echo "creating the consortium and stakeholder configs"
+  $DID_METHOD_CLI create-config --sidetree-url {{.SidetreeURL}} \
+    --tls-cacerts keys/tls/cert.pem --sidetree-write-token {{.SidetreeWriteToken}} \
+    --config-file config.json --recoverykey-file keys/recover/public.pem --updatekey-file keys/update/public.pem \
+    --output-directory well-known --well-known true
+fi
+
+docker-compose up -d
+
+echo "testnet {{.Domain}} is up"
+`
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/awskms"
+)
+
+const (
+	// AWSKMSKeyARNFlagName is the flag name of the AWS KMS signing key ARN
+	AWSKMSKeyARNFlagName = "aws-kms-key-arn"
+	// AWSKMSKeyARNEnvKey is the environment variable of the AWS KMS signing key ARN
+	AWSKMSKeyARNEnvKey = "DID_METHOD_CLI_AWS_KMS_KEY_ARN"
+	// AWSKMSKeyARNFlagUsage is the usage of the AWS KMS signing key ARN flag
+	AWSKMSKeyARNFlagUsage = "ARN (or ID or alias) of the ECC_NIST_P256 AWS KMS key used for signing the request." +
+		" When set, the request is signed by AWS KMS and the private key never leaves it. The AWS credentials" +
+		" are read from the environment, the shared credentials file or the instance role." +
+		" Alternatively, this can be set with the following environment variable: " + AWSKMSKeyARNEnvKey
+
+	// AWSRegionFlagName is the flag name of the AWS region
+	AWSRegionFlagName = "aws-region"
+	// AWSRegionEnvKey is the environment variable of the AWS region
+	AWSRegionEnvKey = "DID_METHOD_CLI_AWS_REGION"
+	// AWSRegionFlagUsage is the usage of the AWS region flag
+	AWSRegionFlagUsage = "AWS region of the KMS signing key. Defaults to the region of the key ARN." +
+		" Alternatively, this can be set with the following environment variable: " + AWSRegionEnvKey
+
+	// AWSKMSEndpointFlagName is the flag name of the AWS KMS endpoint
+	AWSKMSEndpointFlagName = "aws-kms-endpoint"
+	// AWSKMSEndpointEnvKey is the environment variable of the AWS KMS endpoint
+	AWSKMSEndpointEnvKey = "DID_METHOD_CLI_AWS_KMS_ENDPOINT"
+	// AWSKMSEndpointFlagUsage is the usage of the AWS KMS endpoint flag
+	AWSKMSEndpointFlagUsage = "AWS KMS endpoint URL, such as a VPC endpoint. Defaults to the endpoint of the region." +
+		" Alternatively, this can be set with the following environment variable: " + AWSKMSEndpointEnvKey
+)
+
+// AddAWSKMSFlags adds the AWS KMS signing flags to the command
+func AddAWSKMSFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(AWSKMSKeyARNFlagName, "", "", AWSKMSKeyARNFlagUsage)
+	cmd.Flags().StringP(AWSRegionFlagName, "", "", AWSRegionFlagUsage)
+	cmd.Flags().StringP(AWSKMSEndpointFlagName, "", "", AWSKMSEndpointFlagUsage)
+}
+
+// getAWSKMSSigningKey returns the signer of the AWS KMS key set with --aws-kms-key-arn
+func getAWSKMSSigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	keyARN := cmdutils.GetUserSetOptionalVarFromString(cmd, AWSKMSKeyARNFlagName, AWSKMSKeyARNEnvKey)
+
+	var opts []awskms.Option
+
+	if region := cmdutils.GetUserSetOptionalVarFromString(cmd, AWSRegionFlagName, AWSRegionEnvKey); region != "" {
+		opts = append(opts, awskms.WithRegion(region))
+	}
+
+	endpoint := cmdutils.GetUserSetOptionalVarFromString(cmd, AWSKMSEndpointFlagName, AWSKMSEndpointEnvKey)
+	if endpoint != "" {
+		opts = append(opts, awskms.WithEndpoint(endpoint))
+	}
+
+	return awskms.New(keyARN, opts...)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAWSKMSSigningKey(t *testing.T) {
+	t.Run("test aws kms key and pem are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(AWSKMSKeyARNFlagName, "alias/update-key"))
+		require.NoError(t, cmd.Flags().Set(signingKeyFileFlag, "private.pem"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of")
+	})
+
+	t.Run("test aws kms key not reachable", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(AWSRegionEnvKey, "us-east-1"))
+		require.NoError(t, os.Setenv("AWS_ACCESS_KEY_ID", "id"))
+		require.NoError(t, os.Setenv("AWS_SECRET_ACCESS_KEY", "secret"))
+
+		cmd := newAWSKMSCmd()
+		require.NoError(t, cmd.Flags().Set(AWSKMSKeyARNFlagName, "alias/update-key"))
+		require.NoError(t, cmd.Flags().Set(AWSKMSEndpointFlagName, "http://localhost:1"))
+
+		_, err := getAWSKMSSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get public key of aws kms key alias/update-key")
+	})
+}
+
+func newAWSKMSCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddAWSKMSFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/azurekv"
+)
+
+const (
+	// AzureKeyIDFlagName is the flag name of the Azure Key Vault signing key identifier
+	AzureKeyIDFlagName = "azure-key-id"
+	// AzureKeyIDEnvKey is the environment variable of the Azure Key Vault signing key identifier
+	AzureKeyIDEnvKey = "DID_METHOD_CLI_AZURE_KEY_ID"
+	// AzureKeyIDFlagUsage is the usage of the Azure Key Vault signing key identifier flag
+	AzureKeyIDFlagUsage = "Identifier of the P-256 Azure Key Vault key used for signing the request" +
+		" (https://{vault}.vault.azure.net/keys/{name}[/{version}]). When set, the request is signed by the key vault" +
+		" and the private key never leaves it. The credentials of the service principal are read from the" +
+		" AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET environment variables, unless" +
+		" --azure-managed-identity is set." +
+		" Alternatively, this can be set with the following environment variable: " + AzureKeyIDEnvKey
+
+	// AzureManagedIdentityFlagName is the flag name of the Azure managed identity authentication
+	AzureManagedIdentityFlagName = "azure-managed-identity"
+	// AzureManagedIdentityEnvKey is the environment variable of the Azure managed identity authentication
+	AzureManagedIdentityEnvKey = "DID_METHOD_CLI_AZURE_MANAGED_IDENTITY"
+	// AzureManagedIdentityFlagUsage is the usage of the Azure managed identity authentication flag
+	AzureManagedIdentityFlagUsage = "Set to true to authenticate to the key vault with the managed identity of the" +
+		" Azure resource the CLI runs on. Possible values [true] [false]. Defaults to false." +
+		" Alternatively, this can be set with the following environment variable: " + AzureManagedIdentityEnvKey
+
+	// AzureManagedIdentityClientIDFlagName is the flag name of the Azure user-assigned managed identity client ID
+	AzureManagedIdentityClientIDFlagName = "azure-managed-identity-client-id"
+	// AzureManagedIdentityClientIDEnvKey is the environment variable of the Azure user-assigned managed identity
+	// client ID
+	AzureManagedIdentityClientIDEnvKey = "DID_METHOD_CLI_AZURE_MANAGED_IDENTITY_CLIENT_ID"
+	// AzureManagedIdentityClientIDFlagUsage is the usage of the Azure user-assigned managed identity client ID flag
+	AzureManagedIdentityClientIDFlagUsage = "Client ID of the user-assigned managed identity to authenticate" +
+		" with. Implies --azure-managed-identity. Defaults to the system-assigned managed identity." +
+		" Alternatively, this can be set with the following environment variable: " +
+		AzureManagedIdentityClientIDEnvKey
+)
+
+// AddAzureKeyVaultFlags adds the Azure Key Vault signing flags to the command
+func AddAzureKeyVaultFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(AzureKeyIDFlagName, "", "", AzureKeyIDFlagUsage)
+	cmd.Flags().StringP(AzureManagedIdentityFlagName, "", "", AzureManagedIdentityFlagUsage)
+	cmd.Flags().StringP(AzureManagedIdentityClientIDFlagName, "", "", AzureManagedIdentityClientIDFlagUsage)
+}
+
+// getAzureKeyVaultSigningKey returns the signer of the Azure Key Vault key set with --azure-key-id
+func getAzureKeyVaultSigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	opts, err := getAzureKeyVaultOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return azurekv.New(cmdutils.GetUserSetOptionalVarFromString(cmd, AzureKeyIDFlagName, AzureKeyIDEnvKey),
+		opts...)
+}
+
+func getAzureKeyVaultOptions(cmd *cobra.Command) ([]azurekv.Option, error) {
+	clientID := cmdutils.GetUserSetOptionalVarFromString(cmd, AzureManagedIdentityClientIDFlagName,
+		AzureManagedIdentityClientIDEnvKey)
+	if clientID != "" {
+		return []azurekv.Option{azurekv.WithManagedIdentity(clientID)}, nil
+	}
+
+	managedIdentityString := cmdutils.GetUserSetOptionalVarFromString(cmd, AzureManagedIdentityFlagName,
+		AzureManagedIdentityEnvKey)
+	if managedIdentityString == "" {
+		return nil, nil
+	}
+
+	managedIdentity, err := strconv.ParseBool(managedIdentityString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", AzureManagedIdentityFlagName, err)
+	}
+
+	if !managedIdentity {
+		return nil, nil
+	}
+
+	return []azurekv.Option{azurekv.WithManagedIdentity("")}, nil
+}
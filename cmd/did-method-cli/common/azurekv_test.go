@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAzureKeyVaultSigningKey(t *testing.T) {
+	t.Run("test azure key and jwk are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(AzureKeyIDFlagName, "https://trustbloc.vault.azure.net/keys/update-key"))
+		require.NoError(t, cmd.Flags().Set(SigningKeyJWKFlagName, "{}"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "--azure-key-id, --gcp-kms-key")
+	})
+
+	t.Run("test invalid key id", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newAzureKeyVaultCmd()
+		require.NoError(t, cmd.Flags().Set(AzureKeyIDFlagName, "update-key"))
+
+		_, err := getAzureKeyVaultSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid azure key vault key id")
+	})
+
+	t.Run("test invalid managed identity value", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newAzureKeyVaultCmd()
+		require.NoError(t, cmd.Flags().Set(AzureKeyIDFlagName, "https://trustbloc.vault.azure.net/keys/update-key"))
+		require.NoError(t, cmd.Flags().Set(AzureManagedIdentityFlagName, "maybe"))
+
+		_, err := getAzureKeyVaultSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for azure-managed-identity")
+	})
+}
+
+func TestGetAzureKeyVaultOptions(t *testing.T) {
+	os.Clearenv()
+
+	cmd := newAzureKeyVaultCmd()
+
+	opts, err := getAzureKeyVaultOptions(cmd)
+	require.NoError(t, err)
+	require.Empty(t, opts)
+
+	require.NoError(t, cmd.Flags().Set(AzureManagedIdentityFlagName, "false"))
+
+	opts, err = getAzureKeyVaultOptions(cmd)
+	require.NoError(t, err)
+	require.Empty(t, opts)
+
+	require.NoError(t, cmd.Flags().Set(AzureManagedIdentityFlagName, "true"))
+
+	opts, err = getAzureKeyVaultOptions(cmd)
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+
+	require.NoError(t, cmd.Flags().Set(AzureManagedIdentityClientIDFlagName, "client1"))
+
+	opts, err = getAzureKeyVaultOptions(cmd)
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+}
+
+func newAzureKeyVaultCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddAzureKeyVaultFlags(cmd)
+
+	return cmd
+}
@@ -135,19 +135,22 @@ func GetKey(cmd *cobra.Command, keyFlagName, keyEnvKey, keyFileFlagName, keyFile
 		return nil, fmt.Errorf("only one of key (--%s) or key file (--%s) may be specified", keyFlagName, keyFileFlagName)
 	}
 
-	if privateKey {
-		if keyFile != "" {
-			return PrivateKeyFromFile(keyFile, password)
-		}
+	keyBytes := []byte(keyString)
 
-		return PrivateKeyFromPEM([]byte(keyString), password)
+	if keyFile != "" {
+		var err error
+
+		keyBytes, err = ReadKeyFile(cmd, keyFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if keyFile != "" {
-		return PublicKeyFromFile(keyFile)
+	if privateKey {
+		return PrivateKeyFromPEM(keyBytes, password)
 	}
 
-	return PublicKeyFromPEM([]byte(keyString))
+	return PublicKeyFromPEM(keyBytes)
 }
 
 // GetPublicKeysFromFile get public keys from file
@@ -162,6 +165,11 @@ func GetPublicKeysFromFile(publicKeyFilePath string) ([]doc.PublicKey, error) {
 		return nil, err
 	}
 
+	return GetPublicKeys(publicKeys)
+}
+
+// GetPublicKeys reads the JWK files of the public keys
+func GetPublicKeys(publicKeys []PublicKey) ([]doc.PublicKey, error) {
 	var keys []doc.PublicKey
 
 	for _, v := range publicKeys {
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ConfigFlagName is the flag name of the config file
+	ConfigFlagName = "config"
+	// ConfigEnvKey is the environment variable of the config file
+	ConfigEnvKey = "DID_METHOD_CLI_CONFIG"
+	// ConfigFlagUsage is the usage of the config file flag
+	ConfigFlagUsage = "YAML or JSON config file with named profiles of flag values, used for the flags" +
+		" that are not set on the command line or in the environment." +
+		" Alternatively, this can be set with the following environment variable: " + ConfigEnvKey
+
+	// ProfileFlagName is the flag name of the config profile
+	ProfileFlagName = "profile"
+	// ProfileEnvKey is the environment variable of the config profile
+	ProfileEnvKey = "DID_METHOD_CLI_PROFILE"
+	// ProfileFlagUsage is the usage of the config profile flag
+	ProfileFlagUsage = "Name of the profile to use from the config file. Defaults to " + defaultProfile + "." +
+		" Alternatively, this can be set with the following environment variable: " + ProfileEnvKey
+
+	defaultProfile = "default"
+	envKeyPrefix   = "DID_METHOD_CLI_"
+)
+
+// Config is the CLI config file. Each profile maps flag names to their values.
+type Config struct {
+	Profiles map[string]map[string]interface{} `yaml:"profiles"`
+}
+
+// ReadConfig reads a YAML or JSON config file
+func ReadConfig(configFile string) (*Config, error) {
+	bytes, err := ioutil.ReadFile(filepath.Clean(configFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file '%s': %w", configFile, err)
+	}
+
+	config := &Config{}
+
+	if err := yaml.Unmarshal(bytes, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %w", configFile, err)
+	}
+
+	return config, nil
+}
+
+// ApplyConfig reads the config file set with --config, if any, and uses the values of the selected profile for
+// the flags of the command that were not set on the command line or in the environment.
+// Values for flags the command does not have are ignored, so that a profile can be shared by all commands.
+func ApplyConfig(cmd *cobra.Command) error {
+	configFile := cmdutils.GetUserSetOptionalVarFromString(cmd, ConfigFlagName, ConfigEnvKey)
+	if configFile == "" {
+		return nil
+	}
+
+	config, err := ReadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	profileName := cmdutils.GetUserSetOptionalVarFromString(cmd, ProfileFlagName, ProfileEnvKey)
+	if profileName == "" {
+		profileName = defaultProfile
+	}
+
+	profile, ok := config.Profiles[profileName]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found in config file '%s'", profileName, configFile)
+	}
+
+	for name, value := range profile {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(flagEnvKey(name)); ok {
+			continue
+		}
+
+		if err := setFlag(cmd.Flags(), flag, value); err != nil {
+			return fmt.Errorf("failed to set %s from profile '%s': %w", name, profileName, err)
+		}
+	}
+
+	return nil
+}
+
+// ApplyDefaults fills in the flags of the command that were not set on the command line or in the environment,
+// first from the state file set with --state-in and then from the config file profile.
+func ApplyDefaults(cmd *cobra.Command, envKeys map[string]string) error {
+	if err := ApplyStateIn(cmd, envKeys); err != nil {
+		return err
+	}
+
+	return ApplyConfig(cmd)
+}
+
+// flagEnvKey returns the environment variable of a flag, following the naming convention of the CLI flags
+func flagEnvKey(flagName string) string {
+	return envKeyPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func setFlag(flags *pflag.FlagSet, flag *pflag.Flag, value interface{}) error {
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+
+	for _, v := range values {
+		if err := flags.Set(flag.Name, fmt.Sprint(v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+const configYAML = `
+profiles:
+  default:
+    domain: testnet.trustbloc.local
+    sidetree-url:
+      - https://sidetree1.example.com
+      - https://sidetree2.example.com
+    signingkey-file: update.pem
+    unknown-flag: value
+  prod:
+    domain: trustbloc.dev
+`
+
+const configJSON = `{"profiles": {"default": {"domain": "testnet.trustbloc.local", "tls-systemcertpool": true}}}`
+
+func TestApplyConfig(t *testing.T) {
+	t.Run("test apply default profile", func(t *testing.T) {
+		os.Clearenv()
+
+		file := configFile(t, configYAML)
+		defer func() { require.NoError(t, os.Remove(file)) }()
+
+		cmd := newConfigCmd()
+		require.NoError(t, cmd.Flags().Set(ConfigFlagName, file))
+
+		require.NoError(t, ApplyConfig(cmd))
+
+		domain, err := cmd.Flags().GetString(domainFlagName)
+		require.NoError(t, err)
+		require.Equal(t, "testnet.trustbloc.local", domain)
+
+		urls, err := cmd.Flags().GetStringArray(sidetreeURLFlagName)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://sidetree1.example.com", "https://sidetree2.example.com"}, urls)
+
+		signingKeyFile, err := cmd.Flags().GetString("signingkey-file")
+		require.NoError(t, err)
+		require.Equal(t, "update.pem", signingKeyFile)
+	})
+
+	t.Run("test apply named profile", func(t *testing.T) {
+		os.Clearenv()
+
+		file := configFile(t, configYAML)
+		defer func() { require.NoError(t, os.Remove(file)) }()
+
+		require.NoError(t, os.Setenv(ConfigEnvKey, file))
+		require.NoError(t, os.Setenv(ProfileEnvKey, "prod"))
+
+		cmd := newConfigCmd()
+		require.NoError(t, ApplyConfig(cmd))
+
+		domain, err := cmd.Flags().GetString(domainFlagName)
+		require.NoError(t, err)
+		require.Equal(t, "trustbloc.dev", domain)
+
+		signingKeyFile, err := cmd.Flags().GetString("signingkey-file")
+		require.NoError(t, err)
+		require.Empty(t, signingKeyFile)
+	})
+
+	t.Run("test flags and environment take precedence", func(t *testing.T) {
+		os.Clearenv()
+
+		file := configFile(t, configYAML)
+		defer func() { require.NoError(t, os.Remove(file)) }()
+
+		require.NoError(t, os.Setenv("DID_METHOD_CLI_SIGNINGKEY_FILE", "env.pem"))
+
+		cmd := newConfigCmd()
+		require.NoError(t, cmd.Flags().Set(ConfigFlagName, file))
+		require.NoError(t, cmd.Flags().Set(domainFlagName, "flag.domain"))
+
+		require.NoError(t, ApplyConfig(cmd))
+
+		domain, err := cmd.Flags().GetString(domainFlagName)
+		require.NoError(t, err)
+		require.Equal(t, "flag.domain", domain)
+
+		require.False(t, cmd.Flags().Changed("signingkey-file"))
+	})
+
+	t.Run("test apply json config", func(t *testing.T) {
+		os.Clearenv()
+
+		file := configFile(t, configJSON)
+		defer func() { require.NoError(t, os.Remove(file)) }()
+
+		cmd := newConfigCmd()
+		require.NoError(t, cmd.Flags().Set(ConfigFlagName, file))
+
+		require.NoError(t, ApplyConfig(cmd))
+
+		domain, err := cmd.Flags().GetString(domainFlagName)
+		require.NoError(t, err)
+		require.Equal(t, "testnet.trustbloc.local", domain)
+
+		systemCertPool, err := cmd.Flags().GetString("tls-systemcertpool")
+		require.NoError(t, err)
+		require.Equal(t, "true", systemCertPool)
+	})
+
+	t.Run("test no config file", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newConfigCmd()
+		require.NoError(t, ApplyConfig(cmd))
+		require.False(t, cmd.Flags().Changed(domainFlagName))
+	})
+
+	t.Run("test profile not found", func(t *testing.T) {
+		os.Clearenv()
+
+		file := configFile(t, configYAML)
+		defer func() { require.NoError(t, os.Remove(file)) }()
+
+		cmd := newConfigCmd()
+		require.NoError(t, cmd.Flags().Set(ConfigFlagName, file))
+		require.NoError(t, cmd.Flags().Set(ProfileFlagName, "staging"))
+
+		err := ApplyConfig(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "profile 'staging' not found")
+	})
+
+	t.Run("test invalid config file", func(t *testing.T) {
+		os.Clearenv()
+
+		file := configFile(t, "profiles: [")
+		defer func() { require.NoError(t, os.Remove(file)) }()
+
+		cmd := newConfigCmd()
+		require.NoError(t, cmd.Flags().Set(ConfigFlagName, file))
+
+		err := ApplyConfig(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse config file")
+	})
+
+	t.Run("test config file not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newConfigCmd()
+		require.NoError(t, cmd.Flags().Set(ConfigFlagName, "invalid"))
+
+		err := ApplyConfig(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read config file")
+	})
+}
+
+func configFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "*.yaml")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+
+	require.NoError(t, file.Close())
+
+	return file.Name()
+}
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(ConfigFlagName, "", "")
+	cmd.Flags().String(ProfileFlagName, "", "")
+	cmd.Flags().String(domainFlagName, "", "")
+	cmd.Flags().StringArray(sidetreeURLFlagName, []string{}, "")
+	cmd.Flags().String("signingkey-file", "", "")
+	cmd.Flags().String("tls-systemcertpool", "", "")
+
+	return cmd
+}
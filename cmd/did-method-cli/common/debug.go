@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+const (
+	// DebugFlagName is the flag name of the debug HTTP mode
+	DebugFlagName = "debug"
+	// DebugEnvKey is the environment variable of the debug HTTP mode
+	DebugEnvKey = "DID_METHOD_CLI_DEBUG"
+	// DebugFlagUsage is the usage of the debug HTTP mode flag
+	DebugFlagUsage = "Log the sidetree requests and their responses on stderr, with the credentials of their headers" +
+		" redacted and their JSON bodies indented, to troubleshoot the requests rejected by an endpoint." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + DebugEnvKey
+)
+
+// AddDebugFlag adds the debug HTTP mode flag to the command and its subcommands
+func AddDebugFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(DebugFlagName, "", "", DebugFlagUsage)
+	cmd.PersistentFlags().Lookup(DebugFlagName).NoOptDefVal = "true"
+}
+
+// GetDebugOption returns the did.WithDebugHTTP client option if --debug is set, or else an option which does nothing
+func GetDebugOption(cmd *cobra.Command) (did.Option, error) {
+	debugString := cmdutils.GetUserSetOptionalVarFromString(cmd, DebugFlagName, DebugEnvKey)
+	if debugString == "" {
+		return func(*did.Client) {}, nil
+	}
+
+	debug, err := strconv.ParseBool(debugString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", DebugFlagName, err)
+	}
+
+	if !debug {
+		return func(*did.Client) {}, nil
+	}
+
+	return did.WithDebugHTTP(), nil
+}
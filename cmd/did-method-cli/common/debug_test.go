@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDebugOption(t *testing.T) {
+	for _, args := range [][]string{nil, {"--" + DebugFlagName}, {"--" + DebugFlagName + "=false"}} {
+		os.Clearenv()
+
+		cmd := newDebugCmd()
+		require.NoError(t, cmd.ParseFlags(args))
+
+		opt, err := GetDebugOption(cmd)
+		require.NoError(t, err)
+		require.NotNil(t, opt)
+	}
+
+	t.Run("test invalid value", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newDebugCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--" + DebugFlagName + "=maybe"}))
+
+		_, err := GetDebugOption(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for debug")
+	})
+}
+
+func newDebugCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddDebugFlag(cmd)
+
+	return cmd
+}
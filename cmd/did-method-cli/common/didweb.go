@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/didweb"
+)
+
+const (
+	// DIDWebFlagName is the flag name of the did:web domain path
+	DIDWebFlagName = "did-web"
+	// DIDWebEnvKey is the environment variable of the did:web domain path
+	DIDWebEnvKey = "DID_METHOD_CLI_DID_WEB"
+	// DIDWebFlagUsage is the usage of the did:web domain path flag
+	DIDWebFlagUsage = "Domain path, such as example.com/users/alice, to also publish the equivalent did:web document" +
+		" of the DID for, as a did.json file under the did:web root." +
+		" Alternatively, this can be set with the following environment variable: " + DIDWebEnvKey
+
+	// DIDWebRootFlagName is the flag name of the did:web root
+	DIDWebRootFlagName = "did-web-root"
+	// DIDWebRootEnvKey is the environment variable of the did:web root
+	DIDWebRootEnvKey = "DID_METHOD_CLI_DID_WEB_ROOT"
+	// DIDWebRootFlagUsage is the usage of the did:web root flag
+	DIDWebRootFlagUsage = "Web root directory of the domain of the did:web domain path, under which the did.json file" +
+		" is written. Defaults to the current directory." +
+		" Alternatively, this can be set with the following environment variable: " + DIDWebRootEnvKey
+)
+
+// AddDIDWebFlags adds the --did-web and --did-web-root flags to the command
+func AddDIDWebFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(DIDWebFlagName, "", "", DIDWebFlagUsage)
+	cmd.Flags().StringP(DIDWebRootFlagName, "", "", DIDWebRootFlagUsage)
+}
+
+// GetDIDWeb returns the did:web domain path of the command, or an empty path if --did-web is not set, with the
+// client option writing the did.json files under --did-web-root
+func GetDIDWeb(cmd *cobra.Command) (string, did.Option) {
+	domainPath := cmdutils.GetUserSetOptionalVarFromString(cmd, DIDWebFlagName, DIDWebEnvKey)
+	if domainPath == "" {
+		return "", func(*did.Client) {}
+	}
+
+	rootDir := cmdutils.GetUserSetOptionalVarFromString(cmd, DIDWebRootFlagName, DIDWebRootEnvKey)
+	if rootDir == "" {
+		rootDir = "."
+	}
+
+	return domainPath, did.WithDIDWebPublisher(didweb.NewFilePublisher(rootDir))
+}
+
+// GetDIDWebWait returns how many times the updated DID is resolved, and how often, before its did:web document is
+// published, from --wait-timeout and --wait-interval whether or not --wait is set
+func GetDIDWebWait(cmd *cobra.Command) (int, time.Duration, error) {
+	timeout, err := getDuration(cmd, WaitTimeoutFlagName, WaitTimeoutEnvKey, defaultWaitTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	interval, err := getDuration(cmd, WaitIntervalFlagName, WaitIntervalEnvKey, defaultWaitInterval)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(timeout/interval) + 1, interval, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDIDWeb(t *testing.T) {
+	t.Run("test not set", func(t *testing.T) {
+		os.Clearenv()
+
+		domainPath, opt := GetDIDWeb(newDIDWebCmd())
+		require.Empty(t, domainPath)
+		require.NotNil(t, opt)
+	})
+
+	t.Run("test set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newDIDWebCmd()
+		require.NoError(t, cmd.Flags().Set(DIDWebFlagName, "example.com/users/alice"))
+		require.NoError(t, cmd.Flags().Set(DIDWebRootFlagName, "/var/www"))
+
+		domainPath, opt := GetDIDWeb(cmd)
+		require.Equal(t, "example.com/users/alice", domainPath)
+		require.NotNil(t, opt)
+	})
+}
+
+func TestGetDIDWebWait(t *testing.T) {
+	t.Run("test defaults", func(t *testing.T) {
+		os.Clearenv()
+
+		attempts, interval, err := GetDIDWebWait(newDIDWebCmd())
+		require.NoError(t, err)
+		require.Equal(t, 61, attempts)
+		require.Equal(t, time.Second, interval)
+	})
+
+	t.Run("test wait flags", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newDIDWebCmd()
+		require.NoError(t, cmd.Flags().Set(WaitTimeoutFlagName, "10s"))
+		require.NoError(t, cmd.Flags().Set(WaitIntervalFlagName, "500ms"))
+
+		attempts, interval, err := GetDIDWebWait(cmd)
+		require.NoError(t, err)
+		require.Equal(t, 21, attempts)
+		require.Equal(t, 500*time.Millisecond, interval)
+	})
+
+	t.Run("test invalid values", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newDIDWebCmd()
+		require.NoError(t, cmd.Flags().Set(WaitTimeoutFlagName, "soon"))
+
+		_, _, err := GetDIDWebWait(cmd)
+		require.Error(t, err)
+
+		cmd = newDIDWebCmd()
+		require.NoError(t, cmd.Flags().Set(WaitIntervalFlagName, "-1s"))
+
+		_, _, err = GetDIDWebWait(cmd)
+		require.Error(t, err)
+	})
+}
+
+func newDIDWebCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddDIDWebFlags(cmd)
+	AddWaitFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+)
+
+const (
+	// DryRunFlagName is the flag name of the dry run
+	DryRunFlagName = "dry-run"
+	// DryRunEnvKey is the environment variable of the dry run
+	DryRunEnvKey = "DID_METHOD_CLI_DRY_RUN"
+	// DryRunFlagUsage is the usage of the dry run flag
+	DryRunFlagUsage = "Print the sidetree request that would be submitted and exit without sending it." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + DryRunEnvKey
+)
+
+// GetDryRun returns whether --dry-run is set
+func GetDryRun(cmd *cobra.Command) (bool, error) {
+	dryRunString := cmdutils.GetUserSetOptionalVarFromString(cmd, DryRunFlagName, DryRunEnvKey)
+	if dryRunString == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(dryRunString)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", DryRunFlagName, err)
+	}
+
+	return dryRun, nil
+}
+
+// DryRunCallback returns the did.WithDryRun callback that prints the request to stdout, as is, and the endpoint
+// to stderr. It returns nil if dryRun is false, so that the request is sent.
+func DryRunCallback(cmd *cobra.Command, dryRun bool) func(endpoint string, request []byte) {
+	if !dryRun {
+		return nil
+	}
+
+	return func(endpoint string, request []byte) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "dry run: request to %s/operations was not sent\n", endpoint)
+		fmt.Fprintln(cmd.OutOrStdout(), string(request))
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+const (
+	// FIPSFlagName is the flag name of the FIPS mode
+	FIPSFlagName = "fips"
+	// FIPSEnvKey is the environment variable of the FIPS mode
+	FIPSEnvKey = "DID_METHOD_CLI_FIPS"
+	// FIPSFlagUsage is the usage of the FIPS mode flag
+	FIPSFlagUsage = "Restrict the request to FIPS-approved algorithms: P-256 keys signing with ES256 and SHA-2" +
+		" multihashes. The request fails if a key is an Ed25519 key." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + FIPSEnvKey
+)
+
+// GetFIPSOption returns the did.WithFIPSMode client option if --fips is set, or else an option which does nothing
+func GetFIPSOption(cmd *cobra.Command) (did.Option, error) {
+	fipsString := cmdutils.GetUserSetOptionalVarFromString(cmd, FIPSFlagName, FIPSEnvKey)
+	if fipsString == "" {
+		return func(*did.Client) {}, nil
+	}
+
+	fips, err := strconv.ParseBool(fipsString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", FIPSFlagName, err)
+	}
+
+	if !fips {
+		return func(*did.Client) {}, nil
+	}
+
+	return did.WithFIPSMode(), nil
+}
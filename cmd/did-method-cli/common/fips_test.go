@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFIPSOption(t *testing.T) {
+	for _, value := range []string{"", "true", "false"} {
+		os.Clearenv()
+
+		cmd := newFIPSCmd()
+		require.NoError(t, cmd.Flags().Set(FIPSFlagName, value))
+
+		opt, err := GetFIPSOption(cmd)
+		require.NoError(t, err)
+		require.NotNil(t, opt)
+	}
+
+	t.Run("test invalid value", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newFIPSCmd()
+		require.NoError(t, cmd.Flags().Set(FIPSFlagName, "maybe"))
+
+		_, err := GetFIPSOption(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for fips")
+	})
+}
+
+func newFIPSCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP(FIPSFlagName, "", "", FIPSFlagUsage)
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/gcpkms"
+)
+
+const (
+	// GCPKMSKeyFlagName is the flag name of the Cloud KMS signing key version
+	GCPKMSKeyFlagName = "gcp-kms-key"
+	// GCPKMSKeyEnvKey is the environment variable of the Cloud KMS signing key version
+	GCPKMSKeyEnvKey = "DID_METHOD_CLI_GCP_KMS_KEY"
+	// GCPKMSKeyFlagUsage is the usage of the Cloud KMS signing key version flag
+	GCPKMSKeyFlagUsage = "Resource name of the EC_SIGN_P256_SHA256 Google Cloud KMS key version used for signing" +
+		" the request (projects/{project}/locations/{location}/keyRings/{keyRing}/cryptoKeys/{key}/" +
+		"cryptoKeyVersions/{version}). When set, the request is signed by Cloud KMS and the private key never" +
+		" leaves it. The application default credentials are used, unless --gcp-credentials-file is set." +
+		" Alternatively, this can be set with the following environment variable: " + GCPKMSKeyEnvKey
+
+	// GCPCredentialsFileFlagName is the flag name of the Google Cloud credentials file
+	GCPCredentialsFileFlagName = "gcp-credentials-file"
+	// GCPCredentialsFileEnvKey is the environment variable of the Google Cloud credentials file
+	GCPCredentialsFileEnvKey = "DID_METHOD_CLI_GCP_CREDENTIALS_FILE"
+	// GCPCredentialsFileFlagUsage is the usage of the Google Cloud credentials file flag
+	GCPCredentialsFileFlagUsage = "Service account key file to authenticate to Cloud KMS with." +
+		" Defaults to the application default credentials." +
+		" Alternatively, this can be set with the following environment variable: " + GCPCredentialsFileEnvKey
+)
+
+// AddGCPKMSFlags adds the Cloud KMS signing flags to the command
+func AddGCPKMSFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(GCPKMSKeyFlagName, "", "", GCPKMSKeyFlagUsage)
+	cmd.Flags().StringP(GCPCredentialsFileFlagName, "", "", GCPCredentialsFileFlagUsage)
+}
+
+// getGCPKMSSigningKey returns the signer of the Cloud KMS key version set with --gcp-kms-key
+func getGCPKMSSigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	var opts []gcpkms.Option
+
+	credentialsFile := cmdutils.GetUserSetOptionalVarFromString(cmd, GCPCredentialsFileFlagName,
+		GCPCredentialsFileEnvKey)
+	if credentialsFile != "" {
+		opts = append(opts, gcpkms.WithCredentialsFile(credentialsFile))
+	}
+
+	return gcpkms.New(cmdutils.GetUserSetOptionalVarFromString(cmd, GCPKMSKeyFlagName, GCPKMSKeyEnvKey), opts...)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGCPKMSSigningKey(t *testing.T) {
+	t.Run("test cloud kms key and aws kms key are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(GCPKMSKeyFlagName, "projects/p/locations/l/keyRings/r/cryptoKeys/k"+
+			"/cryptoKeyVersions/1"))
+		require.NoError(t, cmd.Flags().Set(AWSKMSKeyARNFlagName, "alias/update-key"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "--gcp-kms-key, --vault-key")
+	})
+
+	t.Run("test credentials file not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newGCPKMSCmd()
+		require.NoError(t, cmd.Flags().Set(GCPKMSKeyFlagName, "projects/p/locations/l/keyRings/r/cryptoKeys/k"+
+			"/cryptoKeyVersions/1"))
+		require.NoError(t, cmd.Flags().Set(GCPCredentialsFileFlagName, "invalid.json"))
+
+		_, err := getGCPKMSSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create cloud kms client")
+	})
+}
+
+func newGCPKMSCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddGCPKMSFlags(cmd)
+
+	return cmd
+}
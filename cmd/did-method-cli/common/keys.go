@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gojose "github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+const (
+	// Ed25519VerificationKey2018 is the public key type of Ed25519 verification keys
+	Ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+
+	// PublicKeyFileName is the name of the public key PEM file of a generated key pair
+	PublicKeyFileName = "public.pem"
+	// PrivateKeyFileName is the name of the private key PEM file of a generated key pair
+	PrivateKeyFileName = "private.pem"
+
+	jwkFileSuffix        = "_jwk.json"
+	privateKeyFileSuffix = "_private.pem"
+
+	keyDirPermissions     = 0700
+	privateKeyPermissions = 0600
+	publicKeyPermissions  = 0644
+
+	privateKeyPEMType = "PRIVATE KEY"
+	publicKeyPEMType  = "PUBLIC KEY"
+)
+
+// GenerateKeyPair generates a key pair of the given key type (Ed25519 or P256)
+func GenerateKeyPair(keyType string) (crypto.PublicKey, crypto.PrivateKey, error) {
+	switch {
+	case strings.EqualFold(keyType, doc.Ed25519KeyType):
+		return ed25519.GenerateKey(rand.Reader)
+	case strings.EqualFold(keyType, doc.P256KeyType):
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &privateKey.PublicKey, privateKey, nil
+	default:
+		return nil, nil, fmt.Errorf("key type not supported: %s", keyType)
+	}
+}
+
+// PublicKeyType returns the DID document public key type for the given public key
+func PublicKeyType(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey.(type) {
+	case ed25519.PublicKey:
+		return Ed25519VerificationKey2018, nil
+	case *ecdsa.PublicKey:
+		return doc.JWSVerificationKey2020, nil
+	default:
+		return "", fmt.Errorf("key not supported")
+	}
+}
+
+// PrivateKeyToPEM encodes the private key as a PKCS#8 PEM, encrypted with the password if it is set
+func PrivateKeyToPEM(privateKey crypto.PrivateKey, password []byte) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	block := &pem.Block{Type: privateKeyPEMType, Bytes: der}
+
+	if len(password) != 0 {
+		block, err = x509.EncryptPEMBlock(rand.Reader, privateKeyPEMType, der, password, //nolint: staticcheck
+			x509.PEMCipherAES256)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt private key: %w", err)
+		}
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// PublicKeyToPEM encodes the public key as a PKIX PEM
+func PublicKeyToPEM(publicKey crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMType, Bytes: der}), nil
+}
+
+// PublicKeyToJWK encodes the public key as a JWK
+func PublicKeyToJWK(publicKey crypto.PublicKey) ([]byte, error) {
+	jwk, err := (&gojose.JSONWebKey{Key: publicKey}).MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key to jwk: %w", err)
+	}
+
+	return jwk, nil
+}
+
+// GeneratePEMKeyPair generates a key pair and writes it to the public.pem and private.pem files of dir
+func GeneratePEMKeyPair(out io.Writer, dir, keyType string, password []byte) (crypto.PublicKey, error) {
+	publicKey, privateKey, err := GenerateKeyPair(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := WritePEMKeyPair(out, dir, publicKey, privateKey, password); err != nil {
+		return nil, err
+	}
+
+	return publicKey, nil
+}
+
+// WritePEMKeyPair writes the key pair to the public.pem and private.pem files of dir, the private key being encrypted
+// with the password if set
+func WritePEMKeyPair(out io.Writer, dir string, publicKey crypto.PublicKey, privateKey crypto.PrivateKey,
+	password []byte) error {
+	publicKeyPEM, err := PublicKeyToPEM(publicKey)
+	if err != nil {
+		return err
+	}
+
+	privateKeyPEM, err := PrivateKeyToPEM(privateKey, password)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, keyDirPermissions); err != nil {
+		return fmt.Errorf("failed to create key dir: %w", err)
+	}
+
+	if err := WriteKeyFile(out, filepath.Join(dir, PublicKeyFileName), publicKeyPEM, publicKeyPermissions); err != nil {
+		return err
+	}
+
+	return WriteKeyFile(out, filepath.Join(dir, PrivateKeyFileName), privateKeyPEM, privateKeyPermissions)
+}
+
+// GenerateVerificationKey generates a verification key with the given ID, writes its public JWK and private key PEM
+// to dir and returns the public key entry referencing the JWK file
+func GenerateVerificationKey(out io.Writer, dir, id, keyType string, password []byte) (*PublicKey, error) {
+	publicKey, privateKey, err := GenerateKeyPair(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyType, err := PublicKeyType(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk, err := PublicKeyToJWK(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeyPEM, err := PrivateKeyToPEM(privateKey, password)
+	if err != nil {
+		return nil, err
+	}
+
+	jwkPath := filepath.Join(dir, id+jwkFileSuffix)
+
+	if err := WriteKeyFile(out, jwkPath, jwk, publicKeyPermissions); err != nil {
+		return nil, err
+	}
+
+	if err := WriteKeyFile(out, VerificationPrivateKeyFile(dir, id), privateKeyPEM,
+		privateKeyPermissions); err != nil {
+		return nil, err
+	}
+
+	return &PublicKey{ID: id, Type: publicKeyType, JWKPath: jwkPath}, nil
+}
+
+// VerificationPrivateKeyFile returns the path of the private key PEM file of a verification key generated
+// with GenerateVerificationKey
+func VerificationPrivateKeyFile(dir, id string) string {
+	return filepath.Join(dir, id+privateKeyFileSuffix)
+}
+
+// WriteKeyFile writes a new key file, refusing to overwrite an existing one, and reports the path to out
+func WriteKeyFile(out io.Writer, path string, data []byte, perm os.FileMode) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("file '%s' already exists", path)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(path), data, perm); err != nil {
+		return fmt.Errorf("failed to write file '%s': %w", path, err)
+	}
+
+	fmt.Fprintf(out, "wrote %s\n", path)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+func TestKeys(t *testing.T) {
+	t.Run("test key pair round trip", func(t *testing.T) {
+		for _, keyType := range []string{doc.Ed25519KeyType, doc.P256KeyType} {
+			publicKey, privateKey, err := GenerateKeyPair(keyType)
+			require.NoError(t, err)
+
+			publicKeyPEM, err := PublicKeyToPEM(publicKey)
+			require.NoError(t, err)
+
+			parsedPublicKey, err := PublicKeyFromPEM(publicKeyPEM)
+			require.NoError(t, err)
+			require.Equal(t, publicKey, parsedPublicKey)
+
+			privateKeyPEM, err := PrivateKeyToPEM(privateKey, []byte("123"))
+			require.NoError(t, err)
+
+			parsedPrivateKey, err := PrivateKeyFromPEM(privateKeyPEM, []byte("123"))
+			require.NoError(t, err)
+			require.Equal(t, privateKey, parsedPrivateKey)
+		}
+	})
+
+	t.Run("test unsupported keys", func(t *testing.T) {
+		_, _, err := GenerateKeyPair("rsa")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported")
+
+		_, err = PublicKeyType("invalid")
+		require.Error(t, err)
+
+		_, err = PublicKeyToPEM("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to marshal public key")
+
+		_, err = PrivateKeyToPEM("invalid", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to marshal private key")
+	})
+
+	t.Run("test generate verification key", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "keys")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		out := &bytes.Buffer{}
+
+		publicKey, err := GenerateVerificationKey(out, dir, "key1", doc.Ed25519KeyType, nil)
+		require.NoError(t, err)
+		require.Equal(t, Ed25519VerificationKey2018, publicKey.Type)
+		require.Equal(t, filepath.Join(dir, "key1_jwk.json"), publicKey.JWKPath)
+		require.Contains(t, out.String(), "key1_private.pem")
+
+		privateKey, err := PrivateKeyFromFile(filepath.Join(dir, "key1_private.pem"), nil)
+		require.NoError(t, err)
+		require.IsType(t, ed25519.PrivateKey{}, privateKey)
+
+		_, err = GenerateVerificationKey(out, dir, "key1", doc.Ed25519KeyType, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists")
+	})
+}
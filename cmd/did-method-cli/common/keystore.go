@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+const (
+	// KeystoreFlagName is the flag name of the keystore file
+	KeystoreFlagName = "keystore"
+	// KeystoreEnvKey is the environment variable of the keystore file
+	KeystoreEnvKey = "DID_METHOD_CLI_KEYSTORE"
+	// KeystoreFlagUsage is the usage of the keystore file flag
+	KeystoreFlagUsage = "The encrypted keystore file created with keystore init." +
+		" Alternatively, this can be set with the following environment variable: " + KeystoreEnvKey
+
+	// KeystorePasswordFlagName is the flag name of the keystore password
+	KeystorePasswordFlagName = "keystore-password"
+	// KeystorePasswordEnvKey is the environment variable of the keystore password
+	KeystorePasswordEnvKey = "DID_METHOD_CLI_KEYSTORE_PASSWORD" //nolint: gosec
+	// KeystorePasswordFlagUsage is the usage of the keystore password flag
+	KeystorePasswordFlagUsage = "The password the keystore is encrypted with." +
+		" Alternatively, this can be set with the following environment variable: " + KeystorePasswordEnvKey
+
+	// KeyAliasFlagName is the flag name of the key alias
+	KeyAliasFlagName = "key-alias"
+	// KeyAliasEnvKey is the environment variable of the key alias
+	KeyAliasEnvKey = "DID_METHOD_CLI_KEY_ALIAS"
+	// KeyAliasFlagUsage is the usage of the key alias flag
+	KeyAliasFlagUsage = "Alias, or DID, of the keys of the DID in the keystore. The keys of the alias are used" +
+		" instead of the key flags." +
+		" Alternatively, this can be set with the following environment variable: " + KeyAliasEnvKey
+)
+
+// AddKeyAliasFlags adds the --key-alias, --keystore and --keystore-password flags to the command
+func AddKeyAliasFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(KeyAliasFlagName, "", "", KeyAliasFlagUsage)
+	AddKeystoreFlags(cmd)
+}
+
+// AddKeystoreFlags adds the --keystore and --keystore-password flags to the command
+func AddKeystoreFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(KeystoreFlagName, "", "", KeystoreFlagUsage)
+	cmd.Flags().StringP(KeystorePasswordFlagName, "", "", KeystorePasswordFlagUsage)
+}
+
+// GetKeystore opens the keystore set with --keystore and --keystore-password
+func GetKeystore(cmd *cobra.Command) (*keystore.Keystore, error) {
+	path, err := cmdutils.GetUserSetVarFromString(cmd, KeystoreFlagName, KeystoreEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := cmdutils.GetUserSetVarFromString(cmd, KeystorePasswordFlagName, KeystorePasswordEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return keystore.Open(path, []byte(password))
+}
+
+// GetKeySet returns the keystore and the key set of the alias or DID set with --key-alias, or nil if it isn't set
+func GetKeySet(cmd *cobra.Command) (*keystore.Keystore, *keystore.KeySet, error) {
+	alias := cmdutils.GetUserSetOptionalVarFromString(cmd, KeyAliasFlagName, KeyAliasEnvKey)
+	if alias == "" {
+		return nil, nil, nil
+	}
+
+	ks, err := GetKeystore(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keySet := ks.KeySet(alias)
+	if keySet == nil {
+		keySet = ks.KeySetByDID(alias)
+	}
+
+	if keySet == nil {
+		return nil, nil, fmt.Errorf("key alias '%s' not found in keystore", alias)
+	}
+
+	return ks, keySet, nil
+}
+
+// GetPublicKey returns the public key of the purpose from the key set, or else the public key PEM set with the
+// given key and key file flags
+func GetPublicKey(cmd *cobra.Command, keySet *keystore.KeySet, purpose, keyFlagName, keyEnvKey, keyFileFlagName,
+	keyFileEnvKey string) (interface{}, error) {
+	publicKey, err := keySet.PublicKey(purpose)
+	if err != nil || publicKey != nil {
+		return publicKey, err
+	}
+
+	return GetKey(cmd, keyFlagName, keyEnvKey, keyFileFlagName, keyFileEnvKey, nil, false)
+}
+
+// GetSigningKeyFromKeySet returns the private key of the purpose from the key set, or else the signing key set
+// with the signing key flags, as GetSigningKey does
+func GetSigningKeyFromKeySet(cmd *cobra.Command, keySet *keystore.KeySet, purpose, keyFlagName, keyEnvKey,
+	keyFileFlagName, keyFileEnvKey, passwordFlagName, passwordEnvKey string) (interface{}, error) {
+	privateKey, err := keySet.PrivateKey(purpose)
+	if err != nil || privateKey != nil {
+		return privateKey, err
+	}
+
+	return GetSigningKey(cmd, keyFlagName, keyEnvKey, keyFileFlagName, keyFileEnvKey, passwordFlagName,
+		passwordEnvKey)
+}
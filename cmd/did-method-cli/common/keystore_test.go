@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+func TestGetKeySet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "keystore.json")
+
+	ks, err := keystore.Create(path, []byte("password"))
+	require.NoError(t, err)
+
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, ks.AddKey("alias1", "did:ex:123", keystore.PurposeRecovery, privateKey))
+	require.NoError(t, ks.Save())
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newKeyAliasCmd()
+		require.NoError(t, cmd.Flags().Set(KeyAliasFlagName, "alias1"))
+		require.NoError(t, cmd.Flags().Set(KeystoreFlagName, path))
+		require.NoError(t, os.Setenv(KeystorePasswordEnvKey, "password"))
+
+		_, keySet, err := GetKeySet(cmd)
+		require.NoError(t, err)
+		require.Equal(t, "alias1", keySet.Alias)
+
+		signingKey, err := GetSigningKeyFromKeySet(cmd, keySet, keystore.PurposeRecovery, "", "", "", "", "", "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey, signingKey)
+
+		publicKey, err := GetPublicKey(cmd, keySet, keystore.PurposeRecovery, "", "", "", "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey.Public(), publicKey)
+	})
+
+	t.Run("test did as key alias", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newKeyAliasCmd()
+		require.NoError(t, cmd.Flags().Set(KeyAliasFlagName, "did:ex:123"))
+		require.NoError(t, cmd.Flags().Set(KeystoreFlagName, path))
+		require.NoError(t, cmd.Flags().Set(KeystorePasswordFlagName, "password"))
+
+		_, keySet, err := GetKeySet(cmd)
+		require.NoError(t, err)
+		require.Equal(t, "alias1", keySet.Alias)
+	})
+
+	t.Run("test key alias not set", func(t *testing.T) {
+		os.Clearenv()
+
+		ks, keySet, err := GetKeySet(newKeyAliasCmd())
+		require.NoError(t, err)
+		require.Nil(t, ks)
+		require.Nil(t, keySet)
+	})
+
+	t.Run("test key alias not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newKeyAliasCmd()
+		require.NoError(t, cmd.Flags().Set(KeyAliasFlagName, "alias2"))
+		require.NoError(t, cmd.Flags().Set(KeystoreFlagName, path))
+		require.NoError(t, cmd.Flags().Set(KeystorePasswordFlagName, "password"))
+
+		_, _, err := GetKeySet(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key alias 'alias2' not found in keystore")
+	})
+
+	t.Run("test keystore password not set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newKeyAliasCmd()
+		require.NoError(t, cmd.Flags().Set(KeyAliasFlagName, "alias1"))
+		require.NoError(t, cmd.Flags().Set(KeystoreFlagName, path))
+
+		_, _, err := GetKeySet(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither keystore-password (command line flag) nor "+
+			"DID_METHOD_CLI_KEYSTORE_PASSWORD (environment variable) have been set.")
+	})
+}
+
+func newKeyAliasCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddKeyAliasFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+)
+
+const (
+	// LogLevelFlagName is the flag name of the log level
+	LogLevelFlagName = "log-level"
+	// LogLevelEnvKey is the environment variable of the log level
+	LogLevelEnvKey = "DID_METHOD_CLI_LOG_LEVEL"
+	// LogLevelFlagUsage is the usage of the log level flag
+	LogLevelFlagUsage = "Level of the messages logged on stderr by the DID client, the resolver and the endpoint" +
+		" discovery. Possible values [debug] [info] [warn] [error]. Defaults to info if not set." +
+		" Alternatively, this can be set with the following environment variable: " + LogLevelEnvKey
+)
+
+// AddLogLevelFlag adds the log level flag to the command and its subcommands
+func AddLogLevelFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(LogLevelFlagName, "", "", LogLevelFlagUsage)
+}
+
+// GetLogger returns the logger of the clients of the command, logging on stderr at the level set with --log-level
+func GetLogger(cmd *cobra.Command) (logger.Logger, error) {
+	l := logrus.New()
+	l.SetOutput(cmd.ErrOrStderr())
+
+	if levelString := cmdutils.GetUserSetOptionalVarFromString(cmd, LogLevelFlagName, LogLevelEnvKey); levelString != "" {
+		level, err := logrus.ParseLevel(levelString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", LogLevelFlagName, err)
+		}
+
+		l.SetLevel(level)
+	}
+
+	return logger.NewLogrus(l), nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLogger(t *testing.T) {
+	t.Run("test log level", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newLoggerCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--" + LogLevelFlagName, "debug"}))
+
+		stderr := &bytes.Buffer{}
+		cmd.SetErr(stderr)
+
+		l, err := GetLogger(cmd)
+		require.NoError(t, err)
+
+		l.Debug("discovered sidetree endpoints", "domain", "testnet.trustbloc.local")
+		require.Contains(t, stderr.String(), "discovered sidetree endpoints")
+		require.Contains(t, stderr.String(), "domain=testnet.trustbloc.local")
+	})
+
+	t.Run("test default level", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newLoggerCmd()
+
+		stderr := &bytes.Buffer{}
+		cmd.SetErr(stderr)
+
+		l, err := GetLogger(cmd)
+		require.NoError(t, err)
+
+		l.Debug("discovered sidetree endpoints")
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("test invalid log level", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newLoggerCmd()
+		require.NoError(t, os.Setenv(LogLevelEnvKey, "verbose"))
+
+		_, err := GetLogger(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for log-level")
+	})
+}
+
+func newLoggerCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddLogLevelFlag(cmd)
+
+	return cmd
+}
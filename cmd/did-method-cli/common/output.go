@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	"gopkg.in/yaml.v2"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+const (
+	// OutputFlagName is the flag name of the output format
+	OutputFlagName = "output"
+	// OutputEnvKey is the environment variable of the output format
+	OutputEnvKey = "DID_METHOD_CLI_OUTPUT"
+	// OutputFlagUsage is the usage of the output format flag
+	OutputFlagUsage = "Output format. Possible values [text] [json] [yaml]. Defaults to text if not set." +
+		" Alternatively, this can be set with the following environment variable: " + OutputEnvKey
+
+	// OutputText prints human readable text
+	OutputText = "text"
+	// OutputJSON prints the result as JSON
+	OutputJSON = "json"
+	// OutputYAML prints the result as YAML
+	OutputYAML = "yaml"
+)
+
+// Result is the structured result of a command, printed with --output json or --output yaml
+type Result struct {
+	Operation   string            `json:"operation" yaml:"operation"`
+	DID         string            `json:"did" yaml:"did"`
+	LongFormDID string            `json:"longFormDID,omitempty" yaml:"longFormDID,omitempty"`
+	Endpoint    string            `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	RequestHash string            `json:"requestHash,omitempty" yaml:"requestHash,omitempty"`
+	Keys        map[string]string `json:"keys,omitempty" yaml:"keys,omitempty"`
+	DIDDocument interface{}       `json:"didDocument,omitempty" yaml:"didDocument,omitempty"`
+
+	request []byte
+}
+
+// NewResult returns a new Result for the given operation
+func NewResult(operation, didURI string) *Result {
+	return &Result{Operation: operation, DID: didURI}
+}
+
+// SetOperationInfo records the endpoint and request hash of the operation. It is meant to be passed to
+// did.WithOperationCallback.
+func (r *Result) SetOperationInfo(info *did.OperationInfo) {
+	r.Endpoint = info.Endpoint
+	r.RequestHash = info.RequestHash
+	r.request = info.Request
+}
+
+// SetLongFormDID records the long-form DID of the DID created by the recorded create request
+func (r *Result) SetLongFormDID() error {
+	longFormDID, err := did.LongFormDID(r.DID, r.request)
+	if err != nil {
+		return fmt.Errorf("failed to get long-form did: %w", err)
+	}
+
+	r.LongFormDID = longFormDID
+
+	return nil
+}
+
+// SetDIDDocument records the DID document
+func (r *Result) SetDIDDocument(docBytes []byte) error {
+	var doc interface{}
+
+	if err := json.Unmarshal(docBytes, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshal did document: %w", err)
+	}
+
+	r.DIDDocument = doc
+
+	return nil
+}
+
+// GetOutputFormat returns the output format set with --output
+func GetOutputFormat(cmd *cobra.Command) (string, error) {
+	output := strings.ToLower(cmdutils.GetUserSetOptionalVarFromString(cmd, OutputFlagName, OutputEnvKey))
+
+	switch output {
+	case "":
+		return OutputText, nil
+	case OutputText, OutputJSON, OutputYAML:
+		return output, nil
+	default:
+		return "", fmt.Errorf("invalid value for %s: %s", OutputFlagName, output)
+	}
+}
+
+// InfoWriter returns the writer for informational messages. These go to stderr when a structured output
+// format is used, so that stdout can be piped into other tools.
+func InfoWriter(cmd *cobra.Command, output string) io.Writer {
+	if output == OutputText {
+		return cmd.OutOrStdout()
+	}
+
+	return cmd.ErrOrStderr()
+}
+
+// PrintResult prints the result in the given output format, or the text if the output format is text
+func PrintResult(cmd *cobra.Command, output string, result interface{}, text string) error {
+	var (
+		bytes []byte
+		err   error
+	)
+
+	switch output {
+	case OutputJSON:
+		bytes, err = json.MarshalIndent(result, "", "  ")
+	case OutputYAML:
+		bytes, err = yaml.Marshal(result)
+	default:
+		bytes = []byte(text)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSuffix(string(bytes), "\n"))
+
+	return nil
+}
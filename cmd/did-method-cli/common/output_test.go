@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+func TestGetOutputFormat(t *testing.T) {
+	t.Run("test default", func(t *testing.T) {
+		os.Clearenv()
+
+		output, err := GetOutputFormat(newOutputCmd())
+		require.NoError(t, err)
+		require.Equal(t, OutputText, output)
+	})
+
+	t.Run("test flag and env", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newOutputCmd()
+		require.NoError(t, cmd.Flags().Set(OutputFlagName, "JSON"))
+
+		output, err := GetOutputFormat(cmd)
+		require.NoError(t, err)
+		require.Equal(t, OutputJSON, output)
+
+		require.NoError(t, os.Setenv(OutputEnvKey, "yaml"))
+
+		output, err = GetOutputFormat(newOutputCmd())
+		require.NoError(t, err)
+		require.Equal(t, OutputYAML, output)
+	})
+
+	t.Run("test invalid output", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newOutputCmd()
+		require.NoError(t, cmd.Flags().Set(OutputFlagName, "xml"))
+
+		_, err := GetOutputFormat(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for output: xml")
+	})
+}
+
+func TestPrintResult(t *testing.T) {
+	result := NewResult("update", "did:ex:123")
+	result.SetOperationInfo(&did.OperationInfo{Endpoint: "https://sidetree", RequestHash: "hash"})
+	require.NoError(t, result.SetDIDDocument([]byte(`{"id":"did:ex:123"}`)))
+
+	t.Run("test text", func(t *testing.T) {
+		cmd := newOutputCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		require.NoError(t, PrintResult(cmd, OutputText, result, "successfully updated DID did:ex:123"))
+		require.Equal(t, "successfully updated DID did:ex:123\n", out.String())
+		require.Equal(t, out, InfoWriter(cmd, OutputText))
+	})
+
+	t.Run("test json", func(t *testing.T) {
+		cmd := newOutputCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		require.NoError(t, PrintResult(cmd, OutputJSON, result, ""))
+
+		printed := &Result{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), printed))
+		require.Equal(t, "update", printed.Operation)
+		require.Equal(t, "https://sidetree", printed.Endpoint)
+		require.Equal(t, "hash", printed.RequestHash)
+		require.Equal(t, map[string]interface{}{"id": "did:ex:123"}, printed.DIDDocument)
+		require.NotEqual(t, out, InfoWriter(cmd, OutputJSON))
+	})
+
+	t.Run("test yaml", func(t *testing.T) {
+		cmd := newOutputCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		require.NoError(t, PrintResult(cmd, OutputYAML, result, ""))
+
+		printed := &Result{}
+		require.NoError(t, yaml.Unmarshal(out.Bytes(), printed))
+		require.Equal(t, "did:ex:123", printed.DID)
+		require.Equal(t, "hash", printed.RequestHash)
+	})
+
+	t.Run("test invalid did document", func(t *testing.T) {
+		require.Error(t, NewResult("create", "").SetDIDDocument([]byte("{")))
+	})
+}
+
+func TestSetLongFormDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		result := NewResult("create", "did:ex:123")
+		result.SetOperationInfo(&did.OperationInfo{Request: []byte(`{"type":"create","delta":{}}`)})
+
+		require.NoError(t, result.SetLongFormDID())
+		require.Equal(t, "did:ex:123:eyJkZWx0YSI6e319", result.LongFormDID)
+	})
+
+	t.Run("test request is not a create request", func(t *testing.T) {
+		result := NewResult("update", "did:ex:123")
+		result.SetOperationInfo(&did.OperationInfo{Request: []byte(`{"type":"update"}`)})
+
+		err := result.SetLongFormDID()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get long-form did")
+	})
+}
+
+func newOutputCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(OutputFlagName, "", "")
+
+	return cmd
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/piv"
+)
+
+const (
+	// PIVSlotFlagName is the flag name of the PIV slot of the signing key
+	PIVSlotFlagName = "piv-slot"
+	// PIVSlotEnvKey is the environment variable of the PIV slot of the signing key
+	PIVSlotEnvKey = "DID_METHOD_CLI_PIV_SLOT"
+	// PIVSlotFlagUsage is the usage of the PIV slot flag
+	PIVSlotFlagUsage = "Slot of the P-256 key of the PIV token (such as a YubiKey) used for signing the request, in hex:" +
+		" 9a, 9c, 9d, 9e or 82 to 95. When set, the request is signed inside the token, which waits to be touched if" +
+		" the touch policy of the key requires it. Requires a CLI built with -tags piv." +
+		" Alternatively, this can be set with the following environment variable: " + PIVSlotEnvKey
+
+	// PIVCardFlagName is the flag name of the PIV token
+	PIVCardFlagName = "piv-card"
+	// PIVCardEnvKey is the environment variable of the PIV token
+	PIVCardEnvKey = "DID_METHOD_CLI_PIV_CARD"
+	// PIVCardFlagUsage is the usage of the PIV token flag
+	PIVCardFlagUsage = "Part of the reader name of the PIV token, such as yubikey. Defaults to the first token." +
+		" Alternatively, this can be set with the following environment variable: " + PIVCardEnvKey
+
+	// PIVPinFlagName is the flag name of the PIV PIN
+	PIVPinFlagName = "piv-pin"
+	// PIVPinEnvKey is the environment variable of the PIV PIN
+	PIVPinEnvKey = "DID_METHOD_CLI_PIV_PIN"
+	// PIVPinFlagUsage is the usage of the PIV PIN flag
+	PIVPinFlagUsage = "PIN of the PIV token, if the PIN policy of the key requires it." +
+		" Alternatively, this can be set with the following environment variable: " + PIVPinEnvKey
+)
+
+// AddPIVFlags adds the PIV token signing flags to the command
+func AddPIVFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(PIVSlotFlagName, "", "", PIVSlotFlagUsage)
+	cmd.Flags().StringP(PIVCardFlagName, "", "", PIVCardFlagUsage)
+	cmd.Flags().StringP(PIVPinFlagName, "", "", PIVPinFlagUsage)
+}
+
+// getPIVSigningKey returns the signer of the key of the PIV slot set with --piv-slot. The user is asked to touch
+// the token before each signature.
+func getPIVSigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	return piv.New(cmdutils.GetUserSetOptionalVarFromString(cmd, PIVSlotFlagName, PIVSlotEnvKey),
+		piv.WithCard(cmdutils.GetUserSetOptionalVarFromString(cmd, PIVCardFlagName, PIVCardEnvKey)),
+		piv.WithPIN(cmdutils.GetUserSetOptionalVarFromString(cmd, PIVPinFlagName, PIVPinEnvKey)),
+		piv.WithTouchPrompt(func() {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Touch the PIV token to confirm the signature if it is blinking")
+		}))
+}
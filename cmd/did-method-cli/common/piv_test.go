@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPIVSigningKey(t *testing.T) {
+	t.Run("test piv slot and ssh-agent key are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(PIVSlotFlagName, "9c"))
+		require.NoError(t, cmd.Flags().Set(SSHAgentKeyFlagName, "SHA256:key"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "--ssh-agent-key or --piv-slot may be specified")
+	})
+
+	t.Run("test invalid slot", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newPIVCmd()
+		require.NoError(t, cmd.Flags().Set(PIVSlotFlagName, "9b"))
+
+		_, err := getPIVSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "piv slot '9b' is not a key slot")
+	})
+}
+
+func newPIVCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddPIVFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+)
+
+const (
+	// PKCS11ModuleFlagName is the flag name of the PKCS#11 module
+	PKCS11ModuleFlagName = "pkcs11-module"
+	// PKCS11ModuleEnvKey is the environment variable of the PKCS#11 module
+	PKCS11ModuleEnvKey = "DID_METHOD_CLI_PKCS11_MODULE"
+	// PKCS11ModuleFlagUsage is the usage of the PKCS#11 module flag
+	PKCS11ModuleFlagUsage = "Path to the PKCS#11 module (shared library) of the HSM or smartcard holding the" +
+		" signing key. When set, the request is signed inside the device with the key labelled --key-label." +
+		" Requires a CLI built with -tags pkcs11." +
+		" Alternatively, this can be set with the following environment variable: " + PKCS11ModuleEnvKey
+
+	// PKCS11SlotFlagName is the flag name of the PKCS#11 slot
+	PKCS11SlotFlagName = "pkcs11-slot"
+	// PKCS11SlotEnvKey is the environment variable of the PKCS#11 slot
+	PKCS11SlotEnvKey = "DID_METHOD_CLI_PKCS11_SLOT"
+	// PKCS11SlotFlagUsage is the usage of the PKCS#11 slot flag
+	PKCS11SlotFlagUsage = "Number of the PKCS#11 slot holding the signing key." +
+		" Alternatively, this can be set with the following environment variable: " + PKCS11SlotEnvKey
+
+	// PKCS11PinFlagName is the flag name of the PKCS#11 user PIN
+	PKCS11PinFlagName = "pkcs11-pin"
+	// PKCS11PinEnvKey is the environment variable of the PKCS#11 user PIN
+	PKCS11PinEnvKey = "DID_METHOD_CLI_PKCS11_PIN"
+	// PKCS11PinFlagUsage is the usage of the PKCS#11 user PIN flag
+	PKCS11PinFlagUsage = "User PIN of the PKCS#11 slot." +
+		" Alternatively, this can be set with the following environment variable: " + PKCS11PinEnvKey
+
+	// KeyLabelFlagName is the flag name of the PKCS#11 signing key label
+	KeyLabelFlagName = "key-label"
+	// KeyLabelEnvKey is the environment variable of the PKCS#11 signing key label
+	KeyLabelEnvKey = "DID_METHOD_CLI_KEY_LABEL"
+	// KeyLabelFlagUsage is the usage of the PKCS#11 signing key label flag
+	KeyLabelFlagUsage = "Label of the signing key in the PKCS#11 slot." +
+		" Alternatively, this can be set with the following environment variable: " + KeyLabelEnvKey
+)
+
+// PKCS11Config is the configuration of a signing key held in a PKCS#11 device
+type PKCS11Config struct {
+	Module   string
+	Slot     int
+	Pin      string
+	KeyLabel string
+}
+
+// AddPKCS11Flags adds the PKCS#11 signing flags to the command
+func AddPKCS11Flags(cmd *cobra.Command) {
+	cmd.Flags().StringP(PKCS11ModuleFlagName, "", "", PKCS11ModuleFlagUsage)
+	cmd.Flags().StringP(PKCS11SlotFlagName, "", "", PKCS11SlotFlagUsage)
+	cmd.Flags().StringP(PKCS11PinFlagName, "", "", PKCS11PinFlagUsage)
+	cmd.Flags().StringP(KeyLabelFlagName, "", "", KeyLabelFlagUsage)
+}
+
+// getPKCS11Config returns the PKCS#11 configuration, or nil if --pkcs11-module is not set
+func getPKCS11Config(cmd *cobra.Command) (*PKCS11Config, error) {
+	module := cmdutils.GetUserSetOptionalVarFromString(cmd, PKCS11ModuleFlagName, PKCS11ModuleEnvKey)
+	if module == "" {
+		return nil, nil
+	}
+
+	slotString, err := cmdutils.GetUserSetVarFromString(cmd, PKCS11SlotFlagName, PKCS11SlotEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := strconv.Atoi(slotString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", PKCS11SlotFlagName, err)
+	}
+
+	keyLabel, err := cmdutils.GetUserSetVarFromString(cmd, KeyLabelFlagName, KeyLabelEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PKCS11Config{
+		Module:   module,
+		Slot:     slot,
+		Pin:      cmdutils.GetUserSetOptionalVarFromString(cmd, PKCS11PinFlagName, PKCS11PinEnvKey),
+		KeyLabel: keyLabel,
+	}, nil
+}
+
+// getPKCS11SigningKey returns the signing key held in the PKCS#11 device, or nil if --pkcs11-module is not set.
+// The private key never leaves the device; the returned crypto.Signer sends the data to sign to it.
+func getPKCS11SigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	config, err := getPKCS11Config(cmd)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	return newPKCS11Signer(config)
+}
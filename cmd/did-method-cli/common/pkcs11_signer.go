@@ -0,0 +1,42 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/ThalesIgnite/crypto11"
+)
+
+// newPKCS11Signer logs into the PKCS#11 slot and finds the signing key by its label. The session is kept open
+// for the lifetime of the CLI process.
+func newPKCS11Signer(config *PKCS11Config) (crypto.Signer, error) {
+	slot := config.Slot
+
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:       config.Module,
+		SlotNumber: &slot,
+		Pin:        config.Pin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pkcs11 module '%s': %w", config.Module, err)
+	}
+
+	signer, err := ctx.FindKeyPair(nil, []byte(config.KeyLabel))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pkcs11 key '%s': %w", config.KeyLabel, err)
+	}
+
+	if signer == nil {
+		return nil, fmt.Errorf("pkcs11 key '%s' not found in slot %d", config.KeyLabel, config.Slot)
+	}
+
+	return signer, nil
+}
@@ -0,0 +1,18 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"fmt"
+)
+
+func newPKCS11Signer(*PKCS11Config) (crypto.Signer, error) {
+	return nil, fmt.Errorf("pkcs11 signing is not supported by this build of the CLI, rebuild it with -tags pkcs11")
+}
@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPKCS11Config(t *testing.T) {
+	t.Run("test pkcs11 not configured", func(t *testing.T) {
+		os.Clearenv()
+
+		config, err := getPKCS11Config(newPKCS11Cmd())
+		require.NoError(t, err)
+		require.Nil(t, config)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		require.NoError(t, os.Setenv(PKCS11PinEnvKey, "1234"))
+
+		cmd := newPKCS11Cmd()
+		require.NoError(t, cmd.Flags().Set(PKCS11ModuleFlagName, "/usr/lib/softhsm/libsofthsm2.so"))
+		require.NoError(t, cmd.Flags().Set(PKCS11SlotFlagName, "1"))
+		require.NoError(t, cmd.Flags().Set(KeyLabelFlagName, "update-key"))
+
+		config, err := getPKCS11Config(cmd)
+		require.NoError(t, err)
+		require.Equal(t, &PKCS11Config{Module: "/usr/lib/softhsm/libsofthsm2.so", Slot: 1, Pin: "1234",
+			KeyLabel: "update-key"}, config)
+	})
+
+	t.Run("test slot is missing", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newPKCS11Cmd()
+		require.NoError(t, cmd.Flags().Set(PKCS11ModuleFlagName, "module.so"))
+
+		_, err := getPKCS11Config(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither pkcs11-slot (command line flag) nor "+
+			"DID_METHOD_CLI_PKCS11_SLOT (environment variable) have been set.")
+	})
+
+	t.Run("test invalid slot", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newPKCS11Cmd()
+		require.NoError(t, cmd.Flags().Set(PKCS11ModuleFlagName, "module.so"))
+		require.NoError(t, cmd.Flags().Set(PKCS11SlotFlagName, "one"))
+
+		_, err := getPKCS11Config(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for pkcs11-slot")
+	})
+
+	t.Run("test key label is missing", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newPKCS11Cmd()
+		require.NoError(t, cmd.Flags().Set(PKCS11ModuleFlagName, "module.so"))
+		require.NoError(t, cmd.Flags().Set(PKCS11SlotFlagName, "0"))
+
+		_, err := getPKCS11Config(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither key-label (command line flag) nor "+
+			"DID_METHOD_CLI_KEY_LABEL (environment variable) have been set.")
+	})
+
+	t.Run("test module can't be loaded", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newPKCS11Cmd()
+		require.NoError(t, cmd.Flags().Set(PKCS11ModuleFlagName, "invalid.so"))
+		require.NoError(t, cmd.Flags().Set(PKCS11SlotFlagName, "0"))
+		require.NoError(t, cmd.Flags().Set(KeyLabelFlagName, "update-key"))
+
+		_, err := getPKCS11SigningKey(cmd)
+		require.Error(t, err)
+	})
+}
+
+func newPKCS11Cmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddPKCS11Flags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,244 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gojose "github.com/square/go-jose/v3"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+)
+
+const (
+	// SigningKeyJWKFlagName is the flag name of the signing key JWK
+	SigningKeyJWKFlagName = "signingkey-jwk"
+	// SigningKeyJWKEnvKey is the environment variable of the signing key JWK
+	SigningKeyJWKEnvKey = "DID_METHOD_CLI_SIGNINGKEY_JWK"
+	// SigningKeyJWKFlagUsage is the usage of the signing key JWK flag
+	SigningKeyJWKFlagUsage = "The private key JWK used for signing the request, as an alternative to the PEM" +
+		" signing key." +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyJWKEnvKey
+
+	// SigningKeyJWKFileFlagName is the flag name of the signing key JWK file
+	SigningKeyJWKFileFlagName = "signingkey-jwk-file"
+	// SigningKeyJWKFileEnvKey is the environment variable of the signing key JWK file
+	SigningKeyJWKFileEnvKey = "DID_METHOD_CLI_SIGNINGKEY_JWK_FILE"
+	// SigningKeyJWKFileFlagUsage is the usage of the signing key JWK file flag
+	SigningKeyJWKFileFlagUsage = "The file that contains the private key JWK used for signing the request," +
+		" as an alternative to the PEM signing key file." +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyJWKFileEnvKey
+
+	// SigningKeyEnvFlagName is the flag name of the environment variable to read the signing key from
+	SigningKeyEnvFlagName = "signingkey-env"
+	// SigningKeyEnvEnvKey is the environment variable of the signing key environment variable flag
+	SigningKeyEnvEnvKey = "DID_METHOD_CLI_SIGNINGKEY_ENV"
+	// SigningKeyEnvFlagUsage is the usage of the signing key environment variable flag
+	SigningKeyEnvFlagUsage = "Name of an environment variable to read the private key PEM used for signing from," +
+		" so that the key doesn't show up in the shell history or process listing." +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyEnvEnvKey
+
+	// SigningKeyPasswordEnvFlagName is the flag name of the environment variable to read the signing key
+	// password from
+	SigningKeyPasswordEnvFlagName = "signingkey-password-env"
+	// SigningKeyPasswordEnvEnvKey is the environment variable of the signing key password environment variable flag
+	SigningKeyPasswordEnvEnvKey = "DID_METHOD_CLI_SIGNINGKEY_PASSWORD_ENV" //nolint: gosec
+	// SigningKeyPasswordEnvFlagUsage is the usage of the signing key password environment variable flag
+	SigningKeyPasswordEnvFlagUsage = "Name of an environment variable to read the signing key PEM password from," +
+		" so that the password doesn't show up in the shell history or process listing." +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyPasswordEnvEnvKey
+
+	// Stdin is the file name and password value that reads the key or password from stdin
+	Stdin = "-"
+)
+
+// PrivateKeyFromJWK parses a private key JWK (Ed25519 or P-256)
+func PrivateKeyFromJWK(jwkBytes []byte) (interface{}, error) {
+	var jwk gojose.JSONWebKey
+
+	if err := jwk.UnmarshalJSON(jwkBytes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal private key jwk: %w", err)
+	}
+
+	if jwk.IsPublic() {
+		return nil, fmt.Errorf("jwk is not a private key")
+	}
+
+	switch key := jwk.Key.(type) {
+	case ed25519.PrivateKey, *ecdsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("key not supported")
+	}
+}
+
+// remoteSigningKey is a signing key held outside of the CLI, such as in an HSM or a cloud KMS, which is used when its
+// flag is set
+type remoteSigningKey struct {
+	flagName string
+	envKey   string
+	get      func(cmd *cobra.Command) (crypto.Signer, error)
+}
+
+func remoteSigningKeys() []*remoteSigningKey {
+	return []*remoteSigningKey{
+		{flagName: PKCS11ModuleFlagName, envKey: PKCS11ModuleEnvKey, get: getPKCS11SigningKey},
+		{flagName: AWSKMSKeyARNFlagName, envKey: AWSKMSKeyARNEnvKey, get: getAWSKMSSigningKey},
+		{flagName: AzureKeyIDFlagName, envKey: AzureKeyIDEnvKey, get: getAzureKeyVaultSigningKey},
+		{flagName: GCPKMSKeyFlagName, envKey: GCPKMSKeyEnvKey, get: getGCPKMSSigningKey},
+		{flagName: VaultKeyFlagName, envKey: VaultKeyEnvKey, get: getVaultSigningKey},
+		{flagName: SSHAgentKeyFlagName, envKey: SSHAgentKeyEnvKey, get: getSSHAgentSigningKey},
+		{flagName: PIVSlotFlagName, envKey: PIVSlotEnvKey, get: getPIVSigningKey},
+	}
+}
+
+// GetSigningKey returns the signing key set with one of the remote signing key flags (such as --pkcs11-module or
+// --aws-kms-key-arn), --signingkey-jwk, --signingkey-jwk-file or --signingkey-env, or else the PEM signing key set
+// with the given key and key file flags. Key files and the password may be read from stdin by setting them to "-".
+func GetSigningKey(cmd *cobra.Command, keyFlagName, keyEnvKey, keyFileFlagName, keyFileEnvKey, passwordFlagName,
+	passwordEnvKey string) (interface{}, error) {
+	key := cmdutils.GetUserSetOptionalVarFromString(cmd, keyFlagName, keyEnvKey)
+	keyFile := cmdutils.GetUserSetOptionalVarFromString(cmd, keyFileFlagName, keyFileEnvKey)
+	jwk := cmdutils.GetUserSetOptionalVarFromString(cmd, SigningKeyJWKFlagName, SigningKeyJWKEnvKey)
+	jwkFile := cmdutils.GetUserSetOptionalVarFromString(cmd, SigningKeyJWKFileFlagName, SigningKeyJWKFileEnvKey)
+	keyEnv := cmdutils.GetUserSetOptionalVarFromString(cmd, SigningKeyEnvFlagName, SigningKeyEnvEnvKey)
+
+	remote, err := getRemoteSigningKey(cmd, countSet(key, keyFile, jwk, jwkFile, keyEnv), keyFlagName,
+		keyFileFlagName, SigningKeyJWKFlagName, SigningKeyJWKFileFlagName, SigningKeyEnvFlagName)
+	if err != nil || remote != nil {
+		return remote, err
+	}
+
+	if (keyFile == Stdin || jwkFile == Stdin) &&
+		cmdutils.GetUserSetOptionalVarFromString(cmd, passwordFlagName, passwordEnvKey) == Stdin {
+		return nil, fmt.Errorf("only one of the signing key and its password may be read from stdin")
+	}
+
+	password, err := getSigningKeyPassword(cmd, passwordFlagName, passwordEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case jwk != "":
+		return PrivateKeyFromJWK([]byte(jwk))
+	case jwkFile != "":
+		jwkBytes, err := ReadKeyFile(cmd, jwkFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return PrivateKeyFromJWK(jwkBytes)
+	case keyEnv != "":
+		keyPEM, ok := os.LookupEnv(keyEnv)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", keyEnv)
+		}
+
+		return PrivateKeyFromPEM([]byte(keyPEM), password)
+	default:
+		return GetKey(cmd, keyFlagName, keyEnvKey, keyFileFlagName, keyFileEnvKey, password, true)
+	}
+}
+
+// ReadKeyFile reads a key file, or stdin if the file name is "-"
+func ReadKeyFile(cmd *cobra.Command, file string) ([]byte, error) {
+	if file == Stdin {
+		keyBytes, err := ioutil.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key from stdin: %w", err)
+		}
+
+		return keyBytes, nil
+	}
+
+	keyBytes, err := ioutil.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file '%s': %w", file, err)
+	}
+
+	return keyBytes, nil
+}
+
+// getSigningKeyPassword returns the signing key password set with the password flag, or read from stdin if
+// it is "-", or read from the environment variable named with --signingkey-password-env
+func getSigningKeyPassword(cmd *cobra.Command, passwordFlagName, passwordEnvKey string) ([]byte, error) {
+	password := cmdutils.GetUserSetOptionalVarFromString(cmd, passwordFlagName, passwordEnvKey)
+	passwordEnv := cmdutils.GetUserSetOptionalVarFromString(cmd, SigningKeyPasswordEnvFlagName,
+		SigningKeyPasswordEnvEnvKey)
+
+	if password != "" && passwordEnv != "" {
+		return nil, fmt.Errorf("only one of --%s or --%s may be specified", passwordFlagName,
+			SigningKeyPasswordEnvFlagName)
+	}
+
+	if passwordEnv != "" {
+		value, ok := os.LookupEnv(passwordEnv)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", passwordEnv)
+		}
+
+		return []byte(value), nil
+	}
+
+	if password != Stdin {
+		return []byte(password), nil
+	}
+
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
+
+// getRemoteSigningKey returns the remote signing key whose flag is set, or nil if none is set. count is the number of
+// local signing key flags set, out of the given flags: at most one local or remote signing key flag may be set.
+func getRemoteSigningKey(cmd *cobra.Command, count int, flagNames ...string) (crypto.Signer, error) {
+	var remote *remoteSigningKey
+
+	for _, r := range remoteSigningKeys() {
+		flagNames = append(flagNames, r.flagName)
+
+		if cmdutils.GetUserSetOptionalVarFromString(cmd, r.flagName, r.envKey) != "" {
+			remote = r
+			count++
+		}
+	}
+
+	if count > 1 {
+		return nil, fmt.Errorf("only one of --%s or --%s may be specified",
+			strings.Join(flagNames[:len(flagNames)-1], ", --"), flagNames[len(flagNames)-1])
+	}
+
+	if remote == nil {
+		return nil, nil
+	}
+
+	return remote.get(cmd)
+}
+
+func countSet(values ...string) int {
+	count := 0
+
+	for _, v := range values {
+		if v != "" {
+			count++
+		}
+	}
+
+	return count
+}
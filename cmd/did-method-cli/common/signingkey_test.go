@@ -0,0 +1,246 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	gojose "github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	signingKeyFlag     = "signingkey"
+	signingKeyFileFlag = "signingkey-file"
+
+	signingKeyPasswordFlag = "signingkey-password"
+)
+
+func TestPrivateKeyFromJWK(t *testing.T) {
+	t.Run("test ed25519 key", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		key, err := PrivateKeyFromJWK(privateKeyJWK(t, privateKey))
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test P-256 key", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		key, err := PrivateKeyFromJWK(privateKeyJWK(t, privateKey))
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test public key jwk", func(t *testing.T) {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = PrivateKeyFromJWK(privateKeyJWK(t, publicKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "jwk is not a private key")
+	})
+
+	t.Run("test invalid jwk", func(t *testing.T) {
+		_, err := PrivateKeyFromJWK([]byte("{}"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal private key jwk")
+	})
+}
+
+func TestGetSigningKey(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwk := privateKeyJWK(t, privateKey)
+
+	t.Run("test signing key jwk", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(SigningKeyJWKFlagName, string(jwk)))
+
+		key, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test signing key jwk file", func(t *testing.T) {
+		os.Clearenv()
+
+		file, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(file.Name())) }()
+
+		_, err = file.Write(jwk)
+		require.NoError(t, err)
+
+		require.NoError(t, os.Setenv(SigningKeyJWKFileEnvKey, file.Name()))
+
+		key, err := GetSigningKey(newSigningKeyCmd(), signingKeyFlag, "", signingKeyFileFlag, "",
+			signingKeyPasswordFlag, "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test signing key jwk file not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(SigningKeyJWKFileFlagName, "invalid"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read key file")
+	})
+
+	t.Run("test jwk and pem are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(SigningKeyJWKFlagName, string(jwk)))
+		require.NoError(t, cmd.Flags().Set(signingKeyFileFlag, "private.pem"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of --signingkey, --signingkey-file, --signingkey-jwk, "+
+			"--signingkey-jwk-file, --signingkey-env, --pkcs11-module, --aws-kms-key-arn, --azure-key-id, "+
+			"--gcp-kms-key, --vault-key, --ssh-agent-key or --piv-slot may be specified")
+	})
+
+	t.Run("test signing key jwk file from stdin", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		cmd.SetIn(bytes.NewReader(jwk))
+		require.NoError(t, cmd.Flags().Set(SigningKeyJWKFileFlagName, Stdin))
+
+		key, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test encrypted signing key from stdin and password from env", func(t *testing.T) {
+		os.Clearenv()
+
+		keyPEM, err := PrivateKeyToPEM(privateKey, []byte("secret"))
+		require.NoError(t, err)
+
+		require.NoError(t, os.Setenv("MY_PASSWORD", "secret"))
+
+		cmd := newSigningKeyCmd()
+		cmd.SetIn(bytes.NewReader(keyPEM))
+		require.NoError(t, cmd.Flags().Set(signingKeyFileFlag, Stdin))
+		require.NoError(t, cmd.Flags().Set(SigningKeyPasswordEnvFlagName, "MY_PASSWORD"))
+
+		key, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test signing key from env and password from stdin", func(t *testing.T) {
+		os.Clearenv()
+
+		keyPEM, err := PrivateKeyToPEM(privateKey, []byte("secret"))
+		require.NoError(t, err)
+
+		require.NoError(t, os.Setenv("MY_KEY", string(keyPEM)))
+
+		cmd := newSigningKeyCmd()
+		cmd.SetIn(strings.NewReader("secret\n"))
+		require.NoError(t, cmd.Flags().Set(SigningKeyEnvFlagName, "MY_KEY"))
+		require.NoError(t, cmd.Flags().Set(signingKeyPasswordFlag, Stdin))
+
+		key, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+	})
+
+	t.Run("test signing key env not set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(SigningKeyEnvFlagName, "MY_KEY"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "environment variable MY_KEY is not set")
+	})
+
+	t.Run("test signing key and password both from stdin", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(signingKeyFileFlag, Stdin))
+		require.NoError(t, cmd.Flags().Set(signingKeyPasswordFlag, Stdin))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of the signing key and its password may be read from stdin")
+	})
+
+	t.Run("test password and password env both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(signingKeyFileFlag, "private.pem"))
+		require.NoError(t, cmd.Flags().Set(signingKeyPasswordFlag, "secret"))
+		require.NoError(t, cmd.Flags().Set(SigningKeyPasswordEnvFlagName, "MY_PASSWORD"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of --signingkey-password or --signingkey-password-env")
+	})
+
+	t.Run("test no signing key", func(t *testing.T) {
+		os.Clearenv()
+
+		_, err := GetSigningKey(newSigningKeyCmd(), signingKeyFlag, "", signingKeyFileFlag, "",
+			signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "either key (--signingkey) or key file (--signingkey-file) is required")
+	})
+}
+
+func privateKeyJWK(t *testing.T, key interface{}) []byte {
+	jwk, err := (&gojose.JSONWebKey{Key: key}).MarshalJSON()
+	require.NoError(t, err)
+
+	return jwk
+}
+
+func newSigningKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(signingKeyFlag, "", "")
+	cmd.Flags().String(signingKeyFileFlag, "", "")
+	cmd.Flags().String(SigningKeyJWKFlagName, "", "")
+	cmd.Flags().String(SigningKeyJWKFileFlagName, "", "")
+	cmd.Flags().String(SigningKeyEnvFlagName, "", "")
+	cmd.Flags().String(signingKeyPasswordFlag, "", "")
+	cmd.Flags().String(SigningKeyPasswordEnvFlagName, "", "")
+	AddPKCS11Flags(cmd)
+	AddAWSKMSFlags(cmd)
+	AddAzureKeyVaultFlags(cmd)
+	AddGCPKMSFlags(cmd)
+	AddVaultFlags(cmd)
+	AddSSHAgentFlags(cmd)
+	AddPIVFlags(cmd)
+
+	return cmd
+}
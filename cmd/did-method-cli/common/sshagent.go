@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/sshagent"
+)
+
+const (
+	// SSHAgentKeyFlagName is the flag name of the fingerprint of the ssh-agent signing key
+	SSHAgentKeyFlagName = "ssh-agent-key"
+	// SSHAgentKeyEnvKey is the environment variable of the fingerprint of the ssh-agent signing key
+	SSHAgentKeyEnvKey = "DID_METHOD_CLI_SSH_AGENT_KEY"
+	// SSHAgentKeyFlagUsage is the usage of the ssh-agent signing key flag
+	SSHAgentKeyFlagUsage = "Fingerprint of the Ed25519 key of ssh-agent used for signing the request, as listed by" +
+		" ssh-add -l (SHA256:...) or in the MD5 format (MD5:aa:bb:...). When set, the request is signed by the agent" +
+		" and the private key never leaves it." +
+		" Alternatively, this can be set with the following environment variable: " + SSHAgentKeyEnvKey
+
+	// SSHAgentSocketFlagName is the flag name of the ssh-agent socket
+	SSHAgentSocketFlagName = "ssh-agent-socket"
+	// SSHAgentSocketEnvKey is the environment variable of the ssh-agent socket
+	SSHAgentSocketEnvKey = "DID_METHOD_CLI_SSH_AGENT_SOCKET"
+	// SSHAgentSocketFlagUsage is the usage of the ssh-agent socket flag
+	SSHAgentSocketFlagUsage = "Path of the unix socket of ssh-agent. Defaults to " + sshagent.SocketEnvKey + "." +
+		" Alternatively, this can be set with the following environment variable: " + SSHAgentSocketEnvKey
+)
+
+// AddSSHAgentFlags adds the ssh-agent signing flags to the command
+func AddSSHAgentFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(SSHAgentKeyFlagName, "", "", SSHAgentKeyFlagUsage)
+	cmd.Flags().StringP(SSHAgentSocketFlagName, "", "", SSHAgentSocketFlagUsage)
+}
+
+// getSSHAgentSigningKey returns the signer of the ssh-agent key set with --ssh-agent-key
+func getSSHAgentSigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	return sshagent.New(cmdutils.GetUserSetOptionalVarFromString(cmd, SSHAgentSocketFlagName, SSHAgentSocketEnvKey),
+		cmdutils.GetUserSetOptionalVarFromString(cmd, SSHAgentKeyFlagName, SSHAgentKeyEnvKey))
+}
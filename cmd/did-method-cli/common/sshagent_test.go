@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/sshagent"
+)
+
+func TestGetSSHAgentSigningKey(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		os.Clearenv()
+
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keyring := agent.NewKeyring()
+		require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: privateKey}))
+
+		sshKey, err := ssh.NewPublicKey(publicKey)
+		require.NoError(t, err)
+
+		socket := serveAgent(t, keyring)
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(SSHAgentKeyFlagName, ssh.FingerprintSHA256(sshKey)))
+		require.NoError(t, os.Setenv(SSHAgentSocketEnvKey, socket))
+
+		signingKey, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.NoError(t, err)
+		require.Equal(t, publicKey, signingKey.(*sshagent.Signer).Public())
+		require.NoError(t, signingKey.(*sshagent.Signer).Close())
+	})
+
+	t.Run("test missing socket", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSSHAgentCmd()
+		require.NoError(t, cmd.Flags().Set(SSHAgentKeyFlagName, "SHA256:key"))
+
+		_, err := getSSHAgentSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ssh-agent socket is not set")
+	})
+
+	t.Run("test agent not listening", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSSHAgentCmd()
+		require.NoError(t, cmd.Flags().Set(SSHAgentKeyFlagName, "SHA256:key"))
+		require.NoError(t, cmd.Flags().Set(SSHAgentSocketFlagName, filepath.Join(os.TempDir(), "missing-agent.sock")))
+
+		_, err := getSSHAgentSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to connect to ssh-agent")
+	})
+}
+
+func serveAgent(t *testing.T, keyring agent.Agent) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "agent")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, os.RemoveAll(dir)) })
+
+	socket := filepath.Join(dir, "agent.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, listener.Close()) })
+
+	go func() {
+		for {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+
+			go func() {
+				_ = agent.ServeAgent(keyring, conn) //nolint: errcheck
+			}()
+		}
+	}()
+
+	return socket
+}
+
+func newSSHAgentCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddSSHAgentFlags(cmd)
+
+	return cmd
+}
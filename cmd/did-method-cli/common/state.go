@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+)
+
+const (
+	// StateOutFlagName is the flag name of the state output file
+	StateOutFlagName = "state-out"
+	// StateOutEnvKey is the environment variable of the state output file
+	StateOutEnvKey = "DID_METHOD_CLI_STATE_OUT"
+	// StateOutFlagUsage is the usage of the state output flag
+	StateOutFlagUsage = "File to write a machine-readable state (DID, key references, commitments, endpoints)" +
+		" to after the operation succeeds." +
+		" Alternatively, this can be set with the following environment variable: " + StateOutEnvKey
+
+	// StateInFlagName is the flag name of the state input file
+	StateInFlagName = "state-in"
+	// StateInEnvKey is the environment variable of the state input file
+	StateInEnvKey = "DID_METHOD_CLI_STATE_IN"
+	// StateInFlagUsage is the usage of the state input flag
+	StateInFlagUsage = "State file written by a previous --state-out, used to fill in the DID URI, domain" +
+		" and sidetree URLs when not set explicitly." +
+		" Alternatively, this can be set with the following environment variable: " + StateInEnvKey
+
+	didURIFlagName      = "did-uri"
+	domainFlagName      = "domain"
+	sidetreeURLFlagName = "sidetree-url"
+
+	// sha2_256 is the multihash code used to compute the commitments recorded in the state file
+	sha2_256 = 18
+
+	stateFilePermissions = 0600
+)
+
+// State is the machine-readable result of a DID operation, written with --state-out and read with --state-in
+type State struct {
+	Operation    string            `json:"operation"`
+	DID          string            `json:"did"`
+	Domain       string            `json:"domain,omitempty"`
+	SidetreeURLs []string          `json:"sidetreeURLs,omitempty"`
+	Keys         map[string]string `json:"keys,omitempty"`
+	Commitments  *Commitments      `json:"commitments,omitempty"`
+}
+
+// Commitments holds the commitments to the next update and recovery keys
+type Commitments struct {
+	MultihashAlgorithm uint   `json:"multihashAlgorithm"`
+	Update             string `json:"update,omitempty"`
+	Recovery           string `json:"recovery,omitempty"`
+}
+
+// NewState returns a new State for the given operation
+func NewState(operation, did, domain string, sidetreeURLs []string) *State {
+	return &State{
+		Operation:    operation,
+		DID:          did,
+		Domain:       domain,
+		SidetreeURLs: sidetreeURLs,
+		Keys:         map[string]string{},
+	}
+}
+
+// AddKeyReference records the path of the key file set with the given flag. Inline keys are never recorded.
+func (s *State) AddKeyReference(cmd *cobra.Command, keyFileFlagName, keyFileEnvKey string) {
+	if keyFile := cmdutils.GetUserSetOptionalVarFromString(cmd, keyFileFlagName, keyFileEnvKey); keyFile != "" {
+		s.Keys[keyFileFlagName] = keyFile
+	}
+}
+
+// SetCommitments records the commitments to the given update and recovery public keys. Either key may be nil.
+func (s *State) SetCommitments(updateKey, recoveryKey crypto.PublicKey) error {
+	c := &Commitments{MultihashAlgorithm: sha2_256}
+
+	var err error
+
+	if updateKey != nil {
+		c.Update, err = calculateCommitment(updateKey)
+		if err != nil {
+			return fmt.Errorf("update commitment: %w", err)
+		}
+	}
+
+	if recoveryKey != nil {
+		c.Recovery, err = calculateCommitment(recoveryKey)
+		if err != nil {
+			return fmt.Errorf("recovery commitment: %w", err)
+		}
+	}
+
+	s.Commitments = c
+
+	return nil
+}
+
+func calculateCommitment(key crypto.PublicKey) (string, error) {
+	jwk, err := pubkey.GetPublicKeyJWK(key)
+	if err != nil {
+		return "", err
+	}
+
+	return commitment.Calculate(jwk, sha2_256)
+}
+
+// WriteStateOut writes the state to the file set with --state-out, if any
+func WriteStateOut(cmd *cobra.Command, state *State) error {
+	stateFile := cmdutils.GetUserSetOptionalVarFromString(cmd, StateOutFlagName, StateOutEnvKey)
+	if stateFile == "" {
+		return nil
+	}
+
+	bytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(stateFile), bytes, stateFilePermissions); err != nil {
+		return fmt.Errorf("failed to write state file '%s': %w", stateFile, err)
+	}
+
+	return nil
+}
+
+// ReadState reads a state file
+func ReadState(stateFile string) (*State, error) {
+	bytes, err := ioutil.ReadFile(filepath.Clean(stateFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file '%s': %w", stateFile, err)
+	}
+
+	state := &State{}
+
+	if err := json.Unmarshal(bytes, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file '%s': %w", stateFile, err)
+	}
+
+	return state, nil
+}
+
+// ApplyStateIn reads the state file set with --state-in, if any, and uses it to fill in the did-uri, domain
+// and sidetree-url flags of the command that were not set on the command line or in the environment.
+// envKeys maps these flag names to their environment variables.
+func ApplyStateIn(cmd *cobra.Command, envKeys map[string]string) error {
+	stateFile := cmdutils.GetUserSetOptionalVarFromString(cmd, StateInFlagName, StateInEnvKey)
+	if stateFile == "" {
+		return nil
+	}
+
+	state, err := ReadState(stateFile)
+	if err != nil {
+		return err
+	}
+
+	if err := fillFlag(cmd, didURIFlagName, envKeys[didURIFlagName], state.DID); err != nil {
+		return err
+	}
+
+	if err := fillFlag(cmd, domainFlagName, envKeys[domainFlagName], state.Domain); err != nil {
+		return err
+	}
+
+	return fillFlag(cmd, sidetreeURLFlagName, envKeys[sidetreeURLFlagName], state.SidetreeURLs...)
+}
+
+func fillFlag(cmd *cobra.Command, flagName, envKey string, values ...string) error {
+	if cmd.Flags().Lookup(flagName) == nil || cmd.Flags().Changed(flagName) {
+		return nil
+	}
+
+	if _, ok := os.LookupEnv(envKey); ok && envKey != "" {
+		return nil
+	}
+
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		if err := cmd.Flags().Set(flagName, v); err != nil {
+			return fmt.Errorf("failed to set %s from state file: %w", flagName, err)
+		}
+	}
+
+	return nil
+}
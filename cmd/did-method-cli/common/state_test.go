@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	t.Run("test write and read state", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "state")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := newStateCmd()
+		require.NoError(t, cmd.Flags().Set(StateOutFlagName, filepath.Join(dir, "state.json")))
+		require.NoError(t, cmd.Flags().Set("updatekey-file", "update.pem"))
+
+		pk, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		state := NewState("create", "did:trustbloc:foo.bar:123", "foo.bar", []string{"https://foo.bar/sidetree"})
+		state.AddKeyReference(cmd, "updatekey-file", "")
+		state.AddKeyReference(cmd, "recoverykey-file", "")
+		require.NoError(t, state.SetCommitments(pk, pk))
+
+		require.NoError(t, WriteStateOut(cmd, state))
+
+		read, err := ReadState(filepath.Join(dir, "state.json"))
+		require.NoError(t, err)
+		require.Equal(t, state, read)
+		require.Equal(t, map[string]string{"updatekey-file": "update.pem"}, read.Keys)
+		require.Equal(t, read.Commitments.Update, read.Commitments.Recovery)
+	})
+
+	t.Run("test state out not set", func(t *testing.T) {
+		require.NoError(t, WriteStateOut(newStateCmd(), &State{}))
+	})
+
+	t.Run("test write state error", func(t *testing.T) {
+		cmd := newStateCmd()
+		require.NoError(t, cmd.Flags().Set(StateOutFlagName, filepath.Join("invalid", "dir", "state.json")))
+
+		err := WriteStateOut(cmd, &State{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to write state file")
+	})
+
+	t.Run("test invalid commitment key", func(t *testing.T) {
+		err := NewState("create", "", "", nil).SetCommitments("invalid", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update commitment")
+
+		err = NewState("create", "", "", nil).SetCommitments(nil, "invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recovery commitment")
+	})
+
+	t.Run("test read invalid state", func(t *testing.T) {
+		_, err := ReadState("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read state file")
+
+		file, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(file.Name())) }()
+
+		_, err = file.WriteString("{")
+		require.NoError(t, err)
+
+		_, err = ReadState(file.Name())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse state file")
+	})
+}
+
+func TestApplyStateIn(t *testing.T) {
+	file, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.Remove(file.Name())) }()
+
+	_, err = file.WriteString(`{"did":"did:ex:123","domain":"foo.bar","sidetreeURLs":["https://a","https://b"]}`)
+	require.NoError(t, err)
+
+	envKeys := map[string]string{didURIFlagName: "DID_URI", domainFlagName: "DOMAIN", sidetreeURLFlagName: "URL"}
+
+	t.Run("test flags are filled from state", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newStateCmd()
+		require.NoError(t, cmd.Flags().Set(StateInFlagName, file.Name()))
+
+		require.NoError(t, ApplyStateIn(cmd, envKeys))
+
+		require.Equal(t, "did:ex:123", cmd.Flags().Lookup(didURIFlagName).Value.String())
+		require.Equal(t, "foo.bar", cmd.Flags().Lookup(domainFlagName).Value.String())
+
+		urls, err := cmd.Flags().GetStringArray(sidetreeURLFlagName)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://a", "https://b"}, urls)
+	})
+
+	t.Run("test user set values take precedence", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv("DOMAIN", "env.domain"))
+
+		cmd := newStateCmd()
+		require.NoError(t, cmd.Flags().Set(StateInFlagName, file.Name()))
+		require.NoError(t, cmd.Flags().Set(didURIFlagName, "did:ex:456"))
+
+		require.NoError(t, ApplyStateIn(cmd, envKeys))
+
+		require.Equal(t, "did:ex:456", cmd.Flags().Lookup(didURIFlagName).Value.String())
+		require.False(t, cmd.Flags().Changed(domainFlagName))
+	})
+
+	t.Run("test state in not set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newStateCmd()
+
+		require.NoError(t, ApplyStateIn(cmd, envKeys))
+		require.False(t, cmd.Flags().Changed(didURIFlagName))
+	})
+
+	t.Run("test invalid state file", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newStateCmd()
+		require.NoError(t, cmd.Flags().Set(StateInFlagName, "invalid"))
+
+		err := ApplyStateIn(cmd, envKeys)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read state file")
+	})
+}
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(StateOutFlagName, "", "")
+	cmd.Flags().String(StateInFlagName, "", "")
+	cmd.Flags().String(didURIFlagName, "", "")
+	cmd.Flags().String(domainFlagName, "", "")
+	cmd.Flags().StringArray(sidetreeURLFlagName, []string{}, "")
+	cmd.Flags().String("updatekey-file", "", "")
+	cmd.Flags().String("recoverykey-file", "", "")
+
+	return cmd
+}
@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
+)
+
+const (
+	// TLSClientCertFlagName is the flag name of the client certificate file
+	TLSClientCertFlagName = "tls-client-cert"
+	// TLSClientCertEnvKey is the environment variable of the client certificate file
+	TLSClientCertEnvKey = "DID_METHOD_CLI_TLS_CLIENT_CERT"
+	// TLSClientCertFlagUsage is the usage of the client certificate file flag
+	TLSClientCertFlagUsage = "PEM file of the client certificate presented to the sidetree endpoints and the" +
+		" consortium domain which require mutual TLS. Requires " + TLSClientKeyFlagName + "." +
+		" Alternatively, this can be set with the following environment variable: " + TLSClientCertEnvKey
+
+	// TLSClientKeyFlagName is the flag name of the client certificate private key file
+	TLSClientKeyFlagName = "tls-client-key"
+	// TLSClientKeyEnvKey is the environment variable of the client certificate private key file
+	TLSClientKeyEnvKey = "DID_METHOD_CLI_TLS_CLIENT_KEY"
+	// TLSClientKeyFlagUsage is the usage of the client certificate private key file flag
+	TLSClientKeyFlagUsage = "PEM file of the private key of the client certificate." +
+		" Alternatively, this can be set with the following environment variable: " + TLSClientKeyEnvKey
+
+	// TLSMinVersionFlagName is the flag name of the minimum TLS version
+	TLSMinVersionFlagName = "tls-min-version"
+	// TLSMinVersionEnvKey is the environment variable of the minimum TLS version
+	TLSMinVersionEnvKey = "DID_METHOD_CLI_TLS_MIN_VERSION"
+	// TLSMinVersionFlagUsage is the usage of the minimum TLS version flag
+	TLSMinVersionFlagUsage = "Minimum TLS version of the connections. Possible values [1.2] [1.3]." +
+		" Defaults to 1.2 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + TLSMinVersionEnvKey
+
+	// TLSCipherSuitesFlagName is the flag name of the TLS cipher suites
+	TLSCipherSuitesFlagName = "tls-cipher-suites"
+	// TLSCipherSuitesEnvKey is the environment variable of the TLS cipher suites
+	TLSCipherSuitesEnvKey = "DID_METHOD_CLI_TLS_CIPHER_SUITES"
+	// TLSCipherSuitesFlagUsage is the usage of the TLS cipher suites flag
+	TLSCipherSuitesFlagUsage = "Comma-Separated list of the cipher suites of the TLS 1.2 connections," +
+		" for example TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. Defaults to the secure cipher suites if not set." +
+		" Alternatively, this can be set with the following environment variable: " + TLSCipherSuitesEnvKey
+
+	// TLSInsecureSkipVerifyFlagName is the flag name of the disabling of the server certificate verification
+	TLSInsecureSkipVerifyFlagName = "tls-insecure-skip-verify"
+	// TLSInsecureSkipVerifyEnvKey is the environment variable of the disabling of the server certificate verification
+	TLSInsecureSkipVerifyEnvKey = "DID_METHOD_CLI_TLS_INSECURE_SKIP_VERIFY"
+	// TLSInsecureSkipVerifyFlagUsage is the usage of the disabling of the server certificate verification flag
+	TLSInsecureSkipVerifyFlagUsage = "Skip the verification of the server certificates, for development only." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + TLSInsecureSkipVerifyEnvKey
+
+	// TLSPinsFlagName is the flag name of the pinned keys
+	TLSPinsFlagName = "tls-pins"
+	// TLSPinsEnvKey is the environment variable of the pinned keys
+	TLSPinsEnvKey = "DID_METHOD_CLI_TLS_PINS"
+	// TLSPinsFlagUsage is the usage of the pinned keys flag
+	TLSPinsFlagUsage = "Comma-Separated list of the keys pinned for the consortium domain and the other hosts," +
+		" as <host>=sha256/<base64 SHA-256 hash of the SubjectPublicKeyInfo of a certificate of the host>." +
+		" The connections to a pinned host are refused unless it presents a certificate with a pinned key." +
+		" Alternatively, this can be set with the following environment variable: " + TLSPinsEnvKey
+)
+
+// AddTLSFlags adds the client certificate, TLS settings and pinned keys flags to the command
+func AddTLSFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(TLSClientCertFlagName, "", "", TLSClientCertFlagUsage)
+	cmd.Flags().StringP(TLSClientKeyFlagName, "", "", TLSClientKeyFlagUsage)
+	cmd.Flags().StringP(TLSMinVersionFlagName, "", "", TLSMinVersionFlagUsage)
+	cmd.Flags().StringArrayP(TLSCipherSuitesFlagName, "", []string{}, TLSCipherSuitesFlagUsage)
+	cmd.Flags().StringP(TLSInsecureSkipVerifyFlagName, "", "", TLSInsecureSkipVerifyFlagUsage)
+	cmd.Flags().StringArrayP(TLSPinsFlagName, "", []string{}, TLSPinsFlagUsage)
+}
+
+// NewTLSConfig returns the TLS config of the requests of the command, trusting the root CAs, presenting the client
+// certificate of --tls-client-cert and --tls-client-key if set, and with the TLS settings of the flags
+func NewTLSConfig(cmd *cobra.Command, rootCAs *x509.CertPool) (*tls.Config, error) {
+	opts, err := getTLSOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := getClientCertificate(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if cert != nil {
+		opts = append(opts, tlsconfig.WithClientCertificates(*cert))
+	}
+
+	return tlsconfig.New(append(opts, tlsconfig.WithRootCAs(rootCAs))...), nil
+}
+
+func getTLSOptions(cmd *cobra.Command) ([]tlsconfig.Option, error) {
+	var opts []tlsconfig.Option
+
+	if minVersion := cmdutils.GetUserSetOptionalVarFromString(cmd, TLSMinVersionFlagName,
+		TLSMinVersionEnvKey); minVersion != "" {
+		version, err := tlsconfig.ParseVersion(minVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, tlsconfig.WithMinVersion(version))
+	}
+
+	if names := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, TLSCipherSuitesFlagName,
+		TLSCipherSuitesEnvKey); len(names) > 0 {
+		suites, err := tlsconfig.ParseCipherSuites(names)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, tlsconfig.WithCipherSuites(suites...))
+	}
+
+	if values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, TLSPinsFlagName, TLSPinsEnvKey); len(values) > 0 {
+		pins, err := tlsconfig.ParsePins(values)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, tlsconfig.WithPins(pins))
+	}
+
+	if insecure := cmdutils.GetUserSetOptionalVarFromString(cmd, TLSInsecureSkipVerifyFlagName,
+		TLSInsecureSkipVerifyEnvKey); insecure != "" {
+		insecureSkipVerify, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", TLSInsecureSkipVerifyFlagName, err)
+		}
+
+		opts = append(opts, tlsconfig.WithInsecureSkipVerify(insecureSkipVerify))
+	}
+
+	return opts, nil
+}
+
+func getClientCertificate(cmd *cobra.Command) (*tls.Certificate, error) {
+	certFile := cmdutils.GetUserSetOptionalVarFromString(cmd, TLSClientCertFlagName, TLSClientCertEnvKey)
+	keyFile := cmdutils.GetUserSetOptionalVarFromString(cmd, TLSClientKeyFlagName, TLSClientKeyEnvKey)
+
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("%s and %s must be set together", TLSClientCertFlagName, TLSClientKeyFlagName)
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+	}
+
+	return &cert, nil
+}
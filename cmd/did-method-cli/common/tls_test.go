@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	certFile, keyFile := writeClientCert(t, dir)
+
+	t.Run("test without client certificate", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTLSCmd()
+		require.NoError(t, cmd.ParseFlags(nil))
+
+		tlsConfig, err := NewTLSConfig(cmd, x509.NewCertPool())
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig.RootCAs)
+		require.Empty(t, tlsConfig.Certificates)
+	})
+
+	t.Run("test client certificate", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTLSCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--" + TLSClientCertFlagName, certFile,
+			"--" + TLSClientKeyFlagName, keyFile}))
+
+		tlsConfig, err := NewTLSConfig(cmd, nil)
+		require.NoError(t, err)
+		require.Len(t, tlsConfig.Certificates, 1)
+	})
+
+	t.Run("test client certificate without key", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTLSCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--" + TLSClientCertFlagName, certFile}))
+
+		_, err := NewTLSConfig(cmd, nil)
+		require.EqualError(t, err, "tls-client-cert and tls-client-key must be set together")
+	})
+
+	t.Run("test invalid client certificate", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTLSCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--" + TLSClientCertFlagName, keyFile,
+			"--" + TLSClientKeyFlagName, keyFile}))
+
+		_, err := NewTLSConfig(cmd, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load the client certificate")
+	})
+
+	t.Run("test tls settings", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTLSCmd()
+		require.NoError(t, cmd.ParseFlags([]string{"--" + TLSMinVersionFlagName, "1.3",
+			"--" + TLSCipherSuitesFlagName, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			"--" + TLSInsecureSkipVerifyFlagName, "true",
+			"--" + TLSPinsFlagName, "example.com=sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}))
+
+		tlsConfig, err := NewTLSConfig(cmd, nil)
+		require.NoError(t, err)
+		require.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+		require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+		require.True(t, tlsConfig.InsecureSkipVerify)
+		require.NotNil(t, tlsConfig.VerifyConnection)
+	})
+
+	t.Run("test default tls settings", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newTLSCmd()
+		require.NoError(t, cmd.ParseFlags(nil))
+
+		tlsConfig, err := NewTLSConfig(cmd, nil)
+		require.NoError(t, err)
+		require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		require.Empty(t, tlsConfig.CipherSuites)
+		require.False(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("test invalid tls settings", func(t *testing.T) {
+		for flag, value := range map[string]string{
+			TLSMinVersionFlagName:         "1.0",
+			TLSCipherSuitesFlagName:       "TLS_RSA_WITH_RC4_128_SHA",
+			TLSInsecureSkipVerifyFlagName: "maybe",
+			TLSPinsFlagName:               "example.com=sha256/invalid",
+		} {
+			os.Clearenv()
+
+			cmd := newTLSCmd()
+			require.NoError(t, cmd.ParseFlags([]string{"--" + flag, value}))
+
+			_, err := NewTLSConfig(cmd, nil)
+			require.Error(t, err, flag)
+		}
+	})
+}
+
+func newTLSCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddTLSFlags(cmd)
+
+	return cmd
+}
+
+func writeClientCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "client"},
+		NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE",
+		Bytes: certDER}), 0600))
+
+	keyFile := filepath.Join(dir, "client-key.pem")
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY",
+		Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
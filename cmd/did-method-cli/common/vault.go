@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/signer/vault"
+)
+
+const (
+	// VaultKeyFlagName is the flag name of the Vault transit signing key
+	VaultKeyFlagName = "vault-key"
+	// VaultKeyEnvKey is the environment variable of the Vault transit signing key
+	VaultKeyEnvKey = "DID_METHOD_CLI_VAULT_KEY"
+	// VaultKeyFlagUsage is the usage of the Vault transit signing key flag
+	VaultKeyFlagUsage = "Name of the ecdsa-p256 or ed25519 key of the HashiCorp Vault transit secrets engine used for" +
+		" signing the request. When set, the request is signed by Vault and the private key never leaves it." +
+		" Requires --vault-address and either --vault-token or --vault-role-id and --vault-secret-id." +
+		" Alternatively, this can be set with the following environment variable: " + VaultKeyEnvKey
+
+	// VaultAddressFlagName is the flag name of the Vault address
+	VaultAddressFlagName = "vault-address"
+	// VaultAddressEnvKey is the environment variable of the Vault address
+	VaultAddressEnvKey = "DID_METHOD_CLI_VAULT_ADDRESS"
+	// VaultAddressFlagUsage is the usage of the Vault address flag
+	VaultAddressFlagUsage = "Address of the Vault server, such as https://vault.example.com:8200." +
+		" Alternatively, this can be set with the following environment variable: " + VaultAddressEnvKey
+
+	// VaultTokenFlagName is the flag name of the Vault token
+	VaultTokenFlagName = "vault-token"
+	// VaultTokenEnvKey is the environment variable of the Vault token
+	VaultTokenEnvKey = "DID_METHOD_CLI_VAULT_TOKEN"
+	// VaultTokenFlagUsage is the usage of the Vault token flag
+	VaultTokenFlagUsage = "Token to authenticate to Vault with." +
+		" Alternatively, this can be set with the following environment variable: " + VaultTokenEnvKey
+
+	// VaultRoleIDFlagName is the flag name of the Vault AppRole role ID
+	VaultRoleIDFlagName = "vault-role-id"
+	// VaultRoleIDEnvKey is the environment variable of the Vault AppRole role ID
+	VaultRoleIDEnvKey = "DID_METHOD_CLI_VAULT_ROLE_ID"
+	// VaultRoleIDFlagUsage is the usage of the Vault AppRole role ID flag
+	VaultRoleIDFlagUsage = "Role ID to log in to Vault with the AppRole auth method, instead of a token." +
+		" Alternatively, this can be set with the following environment variable: " + VaultRoleIDEnvKey
+
+	// VaultSecretIDFlagName is the flag name of the Vault AppRole secret ID
+	VaultSecretIDFlagName = "vault-secret-id"
+	// VaultSecretIDEnvKey is the environment variable of the Vault AppRole secret ID
+	VaultSecretIDEnvKey = "DID_METHOD_CLI_VAULT_SECRET_ID"
+	// VaultSecretIDFlagUsage is the usage of the Vault AppRole secret ID flag
+	VaultSecretIDFlagUsage = "Secret ID of the AppRole role set with --vault-role-id." +
+		" Alternatively, this can be set with the following environment variable: " + VaultSecretIDEnvKey
+
+	// VaultTransitMountFlagName is the flag name of the Vault transit secrets engine mount path
+	VaultTransitMountFlagName = "vault-transit-mount"
+	// VaultTransitMountEnvKey is the environment variable of the Vault transit secrets engine mount path
+	VaultTransitMountEnvKey = "DID_METHOD_CLI_VAULT_TRANSIT_MOUNT"
+	// VaultTransitMountFlagUsage is the usage of the Vault transit secrets engine mount path flag
+	VaultTransitMountFlagUsage = "Path the transit secrets engine is mounted at. Defaults to transit." +
+		" Alternatively, this can be set with the following environment variable: " + VaultTransitMountEnvKey
+
+	// VaultNamespaceFlagName is the flag name of the Vault Enterprise namespace
+	VaultNamespaceFlagName = "vault-namespace"
+	// VaultNamespaceEnvKey is the environment variable of the Vault Enterprise namespace
+	VaultNamespaceEnvKey = "DID_METHOD_CLI_VAULT_NAMESPACE"
+	// VaultNamespaceFlagUsage is the usage of the Vault Enterprise namespace flag
+	VaultNamespaceFlagUsage = "Vault Enterprise namespace of the key." +
+		" Alternatively, this can be set with the following environment variable: " + VaultNamespaceEnvKey
+)
+
+// AddVaultFlags adds the Vault transit signing flags to the command
+func AddVaultFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(VaultKeyFlagName, "", "", VaultKeyFlagUsage)
+	cmd.Flags().StringP(VaultAddressFlagName, "", "", VaultAddressFlagUsage)
+	cmd.Flags().StringP(VaultTokenFlagName, "", "", VaultTokenFlagUsage)
+	cmd.Flags().StringP(VaultRoleIDFlagName, "", "", VaultRoleIDFlagUsage)
+	cmd.Flags().StringP(VaultSecretIDFlagName, "", "", VaultSecretIDFlagUsage)
+	cmd.Flags().StringP(VaultTransitMountFlagName, "", "", VaultTransitMountFlagUsage)
+	cmd.Flags().StringP(VaultNamespaceFlagName, "", "", VaultNamespaceFlagUsage)
+}
+
+// getVaultSigningKey returns the signer of the Vault transit key set with --vault-key
+func getVaultSigningKey(cmd *cobra.Command) (crypto.Signer, error) {
+	address, err := cmdutils.GetUserSetVarFromString(cmd, VaultAddressFlagName, VaultAddressEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := getVaultOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return vault.New(address, cmdutils.GetUserSetOptionalVarFromString(cmd, VaultKeyFlagName, VaultKeyEnvKey),
+		opts...)
+}
+
+func getVaultOptions(cmd *cobra.Command) ([]vault.Option, error) {
+	var opts []vault.Option
+
+	token := cmdutils.GetUserSetOptionalVarFromString(cmd, VaultTokenFlagName, VaultTokenEnvKey)
+	roleID := cmdutils.GetUserSetOptionalVarFromString(cmd, VaultRoleIDFlagName, VaultRoleIDEnvKey)
+	secretID := cmdutils.GetUserSetOptionalVarFromString(cmd, VaultSecretIDFlagName, VaultSecretIDEnvKey)
+
+	switch {
+	case token != "" && roleID != "":
+		return nil, fmt.Errorf("only one of --%s or --%s may be specified", VaultTokenFlagName, VaultRoleIDFlagName)
+	case token != "":
+		opts = append(opts, vault.WithToken(token))
+	case roleID != "" && secretID != "":
+		opts = append(opts, vault.WithAppRole(roleID, secretID))
+	case roleID != "":
+		return nil, fmt.Errorf("--%s is required with --%s", VaultSecretIDFlagName, VaultRoleIDFlagName)
+	}
+
+	if mount := cmdutils.GetUserSetOptionalVarFromString(cmd, VaultTransitMountFlagName,
+		VaultTransitMountEnvKey); mount != "" {
+		opts = append(opts, vault.WithTransitMount(mount))
+	}
+
+	if namespace := cmdutils.GetUserSetOptionalVarFromString(cmd, VaultNamespaceFlagName,
+		VaultNamespaceEnvKey); namespace != "" {
+		opts = append(opts, vault.WithNamespace(namespace))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVaultSigningKey(t *testing.T) {
+	t.Run("test vault key and cloud kms key are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newSigningKeyCmd()
+		require.NoError(t, cmd.Flags().Set(VaultKeyFlagName, "update-key"))
+		require.NoError(t, cmd.Flags().Set(GCPKMSKeyFlagName, "projects/p/locations/l/keyRings/r/cryptoKeys/k"+
+			"/cryptoKeyVersions/1"))
+
+		_, err := GetSigningKey(cmd, signingKeyFlag, "", signingKeyFileFlag, "", signingKeyPasswordFlag, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "--vault-key, --ssh-agent-key")
+	})
+
+	t.Run("test missing address", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newVaultCmd()
+		require.NoError(t, cmd.Flags().Set(VaultKeyFlagName, "update-key"))
+
+		_, err := getVaultSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "vault-address")
+	})
+
+	t.Run("test missing credentials", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newVaultCmd()
+		require.NoError(t, cmd.Flags().Set(VaultKeyFlagName, "update-key"))
+		require.NoError(t, cmd.Flags().Set(VaultAddressFlagName, "http://localhost:8200"))
+
+		_, err := getVaultSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "vault token or approle is required")
+	})
+
+	t.Run("test token and role id are both set", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newVaultCmd()
+		require.NoError(t, cmd.Flags().Set(VaultAddressFlagName, "http://localhost:8200"))
+		require.NoError(t, cmd.Flags().Set(VaultTokenFlagName, "token"))
+		require.NoError(t, cmd.Flags().Set(VaultRoleIDFlagName, "role1"))
+
+		_, err := getVaultSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of --vault-token or --vault-role-id may be specified")
+	})
+
+	t.Run("test missing secret id", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newVaultCmd()
+		require.NoError(t, cmd.Flags().Set(VaultAddressFlagName, "http://localhost:8200"))
+		require.NoError(t, cmd.Flags().Set(VaultRoleIDFlagName, "role1"))
+
+		_, err := getVaultSigningKey(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "--vault-secret-id is required with --vault-role-id")
+	})
+
+	t.Run("test options", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newVaultCmd()
+		require.NoError(t, cmd.Flags().Set(VaultRoleIDFlagName, "role1"))
+		require.NoError(t, cmd.Flags().Set(VaultSecretIDFlagName, "secret1"))
+		require.NoError(t, cmd.Flags().Set(VaultTransitMountFlagName, "did-transit"))
+		require.NoError(t, cmd.Flags().Set(VaultNamespaceFlagName, "ns1"))
+
+		opts, err := getVaultOptions(cmd)
+		require.NoError(t, err)
+		require.Len(t, opts, 3)
+	})
+}
+
+func newVaultCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddVaultFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc"
+)
+
+const (
+	// WaitFlagName is the flag name of the wait
+	WaitFlagName = "wait"
+	// WaitEnvKey is the environment variable of the wait
+	WaitEnvKey = "DID_METHOD_CLI_WAIT"
+	// WaitFlagUsage is the usage of the wait flag
+	WaitFlagUsage = "Poll the resolver after the operation and only exit once the change can be resolved." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + WaitEnvKey
+
+	// WaitTimeoutFlagName is the flag name of the wait timeout
+	WaitTimeoutFlagName = "wait-timeout"
+	// WaitTimeoutEnvKey is the environment variable of the wait timeout
+	WaitTimeoutEnvKey = "DID_METHOD_CLI_WAIT_TIMEOUT"
+	// WaitTimeoutFlagUsage is the usage of the wait timeout flag
+	WaitTimeoutFlagUsage = "How long to wait for the change to be resolvable, for example 90s or 5m. Defaults to 60s." +
+		" Alternatively, this can be set with the following environment variable: " + WaitTimeoutEnvKey
+
+	// WaitIntervalFlagName is the flag name of the wait interval
+	WaitIntervalFlagName = "wait-interval"
+	// WaitIntervalEnvKey is the environment variable of the wait interval
+	WaitIntervalEnvKey = "DID_METHOD_CLI_WAIT_INTERVAL"
+	// WaitIntervalFlagUsage is the usage of the wait interval flag
+	WaitIntervalFlagUsage = "How long to sleep between two resolutions while waiting, for example 500ms. Defaults to 1s." +
+		" Alternatively, this can be set with the following environment variable: " + WaitIntervalEnvKey
+
+	defaultWaitTimeout  = 60 * time.Second
+	defaultWaitInterval = time.Second
+)
+
+// WaitConfig holds the settings of --wait
+type WaitConfig struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// AddWaitFlags adds the --wait, --wait-timeout and --wait-interval flags to the command
+func AddWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(WaitFlagName, "", "", WaitFlagUsage)
+	cmd.Flags().StringP(WaitTimeoutFlagName, "", "", WaitTimeoutFlagUsage)
+	cmd.Flags().StringP(WaitIntervalFlagName, "", "", WaitIntervalFlagUsage)
+}
+
+// GetWaitConfig returns the wait settings of the command, or nil if --wait is not set
+func GetWaitConfig(cmd *cobra.Command) (*WaitConfig, error) {
+	waitString := cmdutils.GetUserSetOptionalVarFromString(cmd, WaitFlagName, WaitEnvKey)
+	if waitString == "" {
+		return nil, nil
+	}
+
+	wait, err := strconv.ParseBool(waitString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", WaitFlagName, err)
+	}
+
+	if !wait {
+		return nil, nil
+	}
+
+	timeout, err := getDuration(cmd, WaitTimeoutFlagName, WaitTimeoutEnvKey, defaultWaitTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := getDuration(cmd, WaitIntervalFlagName, WaitIntervalEnvKey, defaultWaitInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WaitConfig{Timeout: timeout, Interval: interval}, nil
+}
+
+func getDuration(cmd *cobra.Command, flagName, envKey string, defaultValue time.Duration) (time.Duration, error) {
+	durationString := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if durationString == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(durationString)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", flagName, err)
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid value for %s: must be positive", flagName)
+	}
+
+	return duration, nil
+}
+
+// NewResolver returns a VDRI that resolves DIDs through the first sidetree URL if there is one, or else through
+// the consortium of the domain, like resolve-did does
+func NewResolver(domain string, sidetreeURLs []string, tlsConfig *tls.Config, authToken string) *trustbloc.VDRI {
+	opts := []trustbloc.Option{trustbloc.WithTLSConfig(tlsConfig), trustbloc.WithAuthToken(authToken)}
+
+	if domain != "" {
+		opts = append(opts, trustbloc.WithDomain(domain))
+	}
+
+	if len(sidetreeURLs) > 0 {
+		opts = append(opts, trustbloc.WithResolverURL(strings.TrimSuffix(sidetreeURLs[0], "/")+"/identifiers"))
+	}
+
+	return trustbloc.New(opts...)
+}
+
+// WaitForDID resolves the DID until observed returns true for the resolved document, or the timeout expires
+func WaitForDID(config *WaitConfig, didURI, domain string, sidetreeURLs []string, tlsConfig *tls.Config,
+	authToken string, observed func(doc *docdid.Doc) bool) error {
+	vdri := NewResolver(domain, sidetreeURLs, tlsConfig, authToken)
+	deadline := time.Now().Add(config.Timeout)
+
+	for {
+		result, err := vdri.Read(didURI)
+		if err == nil && observed(result.DIDDocument) {
+			return nil
+		}
+
+		if !time.Now().Add(config.Interval).Before(deadline) {
+			if err != nil {
+				return fmt.Errorf("timed out after %s waiting for %s to be resolvable: %w", config.Timeout, didURI, err)
+			}
+
+			return fmt.Errorf("timed out after %s waiting for the changes to %s to be resolvable", config.Timeout, didURI)
+		}
+
+		time.Sleep(config.Interval)
+	}
+}
+
+// HasVerificationMethod returns whether the document has a verification method with this ID or fragment
+func HasVerificationMethod(doc *docdid.Doc, id string) bool {
+	for i := range doc.VerificationMethod {
+		if matchesID(doc.VerificationMethod[i].ID, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasService returns whether the document has a service with this ID or fragment
+func HasService(doc *docdid.Doc, id string) bool {
+	for i := range doc.Service {
+		if matchesID(doc.Service[i].ID, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesID(docID, id string) bool {
+	return docID == id || strings.HasSuffix(docID, "#"+strings.TrimPrefix(id, "#"))
+}
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWaitConfig(t *testing.T) {
+	t.Run("test wait not set", func(t *testing.T) {
+		os.Clearenv()
+
+		config, err := GetWaitConfig(newWaitCmd())
+		require.NoError(t, err)
+		require.Nil(t, config)
+	})
+
+	t.Run("test defaults", func(t *testing.T) {
+		os.Clearenv()
+
+		require.NoError(t, os.Setenv(WaitEnvKey, "true"))
+
+		config, err := GetWaitConfig(newWaitCmd())
+		require.NoError(t, err)
+		require.Equal(t, &WaitConfig{Timeout: 60 * time.Second, Interval: time.Second}, config)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newWaitCmd()
+		require.NoError(t, cmd.Flags().Set(WaitFlagName, "true"))
+		require.NoError(t, cmd.Flags().Set(WaitTimeoutFlagName, "5m"))
+		require.NoError(t, cmd.Flags().Set(WaitIntervalFlagName, "500ms"))
+
+		config, err := GetWaitConfig(cmd)
+		require.NoError(t, err)
+		require.Equal(t, &WaitConfig{Timeout: 5 * time.Minute, Interval: 500 * time.Millisecond}, config)
+	})
+
+	t.Run("test invalid wait", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newWaitCmd()
+		require.NoError(t, cmd.Flags().Set(WaitFlagName, "wrongvalue"))
+
+		_, err := GetWaitConfig(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for wait")
+	})
+
+	t.Run("test invalid wait timeout", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newWaitCmd()
+		require.NoError(t, cmd.Flags().Set(WaitFlagName, "true"))
+		require.NoError(t, cmd.Flags().Set(WaitTimeoutFlagName, "-1s"))
+
+		_, err := GetWaitConfig(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for wait-timeout: must be positive")
+	})
+
+	t.Run("test invalid wait interval", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := newWaitCmd()
+		require.NoError(t, cmd.Flags().Set(WaitFlagName, "true"))
+		require.NoError(t, cmd.Flags().Set(WaitIntervalFlagName, "1"))
+
+		_, err := GetWaitConfig(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for wait-interval")
+	})
+}
+
+func TestWaitForDID(t *testing.T) {
+	const didID = "did:ex:123"
+
+	config := &WaitConfig{Timeout: time.Second, Interval: 10 * time.Millisecond}
+
+	t.Run("success", func(t *testing.T) {
+		var requests int32
+
+		serv := newResolverServer(t, func() *did.Doc {
+			if atomic.AddInt32(&requests, 1) < 3 {
+				return nil
+			}
+
+			return &did.Doc{ID: didID, Context: []string{did.ContextV1},
+				Service: []did.Service{{ID: didID + "#svc1", Type: "type", ServiceEndpoint: "https://example.com"}}}
+		})
+		defer serv.Close()
+
+		err := WaitForDID(config, didID, "", []string{serv.URL}, &tls.Config{MinVersion: tls.VersionTLS12}, "",
+			func(doc *did.Doc) bool { return HasService(doc, "svc1") })
+		require.NoError(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("test timeout while not resolvable", func(t *testing.T) {
+		serv := newResolverServer(t, func() *did.Doc { return nil })
+		defer serv.Close()
+
+		err := WaitForDID(config, didID, "", []string{serv.URL}, &tls.Config{MinVersion: tls.VersionTLS12}, "",
+			func(*did.Doc) bool { return true })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out after 1s waiting for did:ex:123 to be resolvable")
+	})
+
+	t.Run("test timeout while change not observed", func(t *testing.T) {
+		serv := newResolverServer(t, func() *did.Doc {
+			return &did.Doc{ID: didID, Context: []string{did.ContextV1}}
+		})
+		defer serv.Close()
+
+		err := WaitForDID(config, didID, "", []string{serv.URL}, &tls.Config{MinVersion: tls.VersionTLS12}, "",
+			func(doc *did.Doc) bool { return HasVerificationMethod(doc, "key1") })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out after 1s waiting for the changes to did:ex:123")
+	})
+}
+
+func newResolverServer(t *testing.T, resolve func() *did.Doc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc := resolve()
+		if doc == nil {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		docBytes, err := doc.JSONBytes()
+		require.NoError(t, err)
+
+		b, err := json.Marshal(map[string]interface{}{
+			"@context":    "https://www.w3.org/ns/did-resolution/v1",
+			"didDocument": json.RawMessage(docBytes),
+		})
+		require.NoError(t, err)
+
+		w.Header().Set("Content-type", "application/did+ld+json")
+		_, err = fmt.Fprint(w, string(b))
+		require.NoError(t, err)
+	}))
+}
+
+func newWaitCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	AddWaitFlags(cmd)
+
+	return cmd
+}
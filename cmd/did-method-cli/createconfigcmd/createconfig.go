@@ -7,7 +7,6 @@ package createconfigcmd
 
 import (
 	"crypto"
-	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
@@ -25,6 +24,7 @@ import (
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
 	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
 
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
@@ -75,6 +75,13 @@ const (
 	updateKeyFlagUsage = "The public key PEM used for validating the signature of the next update of the document. " +
 		" Alternatively, this can be set with the following environment variable: " + updateKeyEnvKey
 
+	wellKnownFlagName  = "well-known"
+	wellKnownEnvKey    = "DID_METHOD_CLI_WELL_KNOWN"
+	wellKnownFlagUsage = "Write a .well-known file tree for each domain to the output directory, ready to be hosted" +
+		" at the root of the domain's web server." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + wellKnownEnvKey
+
 	updateKeyFileFlagName  = "updatekey-file"
 	updateKeyFileEnvKey    = "DID_METHOD_CLI_UPDATEKEY_FILE"
 	updateKeyFileFlagUsage = "The file that contains the public key PEM used for" +
@@ -93,6 +100,7 @@ type parameters struct {
 	recoveryKey     crypto.PublicKey
 	updateKey       crypto.PublicKey
 	outputDirectory string
+	wellKnown       bool
 }
 
 // GetCreateConfigCmd returns the Cobra create conifg command.
@@ -120,7 +128,7 @@ func createCreateConfigCmd() *cobra.Command {
 				return err
 			}
 
-			return writeFiles(parameters.outputDirectory, filesData, didConfData)
+			return writeFiles(parameters.outputDirectory, parameters.wellKnown, filesData, didConfData)
 		},
 	}
 }
@@ -137,12 +145,22 @@ func getParameters(cmd *cobra.Command) (*parameters, error) {
 		return nil, err
 	}
 
+	tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+	if err != nil {
+		return nil, err
+	}
+
 	sidetreeWriteToken := cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
 		sidetreeWriteTokenEnvKey)
 
 	outputDirectory := cmdutils.GetUserSetOptionalVarFromString(cmd, outputDirectoryFlagName,
 		outputDirectoryEnvKey)
 
+	wellKnown, err := getWellKnown(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	config, err := configcommon.GetConfig(cmd)
 	if err != nil {
 		return nil, err
@@ -163,22 +181,41 @@ func getParameters(cmd *cobra.Command) (*parameters, error) {
 	parameters := &parameters{
 		sidetreeURL: strings.TrimSpace(sidetreeURL),
 		didClient: did.New(did.WithAuthToken(sidetreeWriteToken),
-			did.WithTLSConfig(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12})),
+			did.WithTLSConfig(tlsConfig)),
 		config:          config,
 		recoveryKey:     recoveryKey,
 		updateKey:       updateKey,
 		outputDirectory: outputDirectory,
+		wellKnown:       wellKnown,
 	}
 
 	return parameters, nil
 }
 
-func writeFiles(outputDirectory string, filesData, didConfData map[string][]byte) error {
+func getWellKnown(cmd *cobra.Command) (bool, error) {
+	wellKnownString := cmdutils.GetUserSetOptionalVarFromString(cmd, wellKnownFlagName, wellKnownEnvKey)
+	if wellKnownString == "" {
+		return false, nil
+	}
+
+	wellKnown, err := strconv.ParseBool(wellKnownString)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", wellKnownFlagName, err)
+	}
+
+	return wellKnown, nil
+}
+
+func writeFiles(outputDirectory string, wellKnown bool, filesData, didConfData map[string][]byte) error {
 	err := os.RemoveAll(outputDirectory)
 	if err != nil {
 		return fmt.Errorf("remove outputDirectory: %w", err)
 	}
 
+	if wellKnown {
+		return configcommon.WriteWellKnown(outputDirectory, filesData, didConfData)
+	}
+
 	err = configcommon.WriteConfig(outputDirectory, filesData)
 	if err != nil {
 		return err
@@ -295,9 +332,11 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
 		tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
 	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
 	startCmd.Flags().StringP(configcommon.ConfigFileFlagName, "", "", configcommon.ConfigFileFlagUsage)
 	startCmd.Flags().StringP(outputDirectoryFlagName, "", "", outputDirectoryFlagUsage)
+	startCmd.Flags().StringP(wellKnownFlagName, "", "", wellKnownFlagUsage)
 	startCmd.Flags().StringP(recoveryKeyFlagName, "", "", recoveryKeyFlagUsage)
 	startCmd.Flags().StringP(recoveryKeyFileFlagName, "", "", recoveryKeyFileFlagUsage)
 	startCmd.Flags().StringP(updateKeyFlagName, "", "", updateKeyFlagUsage)
@@ -317,7 +317,7 @@ func TestCreateConfigCmd(t *testing.T) {
 
 		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
 
-		require.NoError(t, writeFiles(dir, filesData, didConfData))
+		require.NoError(t, writeFiles(dir, false, filesData, didConfData))
 
 		_, err = os.Stat(dir + "/did-trustbloc/consortium.net.json")
 		require.False(t, os.IsNotExist(err))
@@ -328,6 +328,67 @@ func TestCreateConfigCmd(t *testing.T) {
 		_, err = os.Stat(dir + "/stakeholder.one/did-configuration.json")
 		require.False(t, os.IsNotExist(err))
 	})
+	t.Run("test create config and write the well-known file tree", func(t *testing.T) {
+		os.Clearenv()
+
+		jwkFile, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(jwkFile.Name())) }()
+
+		_, err = jwkFile.WriteString(jwkData)
+		require.NoError(t, err)
+
+		file, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		_, err = file.WriteString(fmt.Sprintf(configData, jwkFile.Name()))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(file.Name())) }()
+
+		require.NoError(t, os.Setenv(configcommon.ConfigFileEnvKey, file.Name()))
+
+		c, err := configcommon.GetConfig(&cobra.Command{})
+		require.NoError(t, err)
+
+		filesData, didConfData, err := createConfig(&parameters{config: c,
+			didClient: &mockDIDClient{&docdid.Doc{ID: "did:test:123"}}})
+		require.NoError(t, err)
+
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		require.NoError(t, writeFiles(dir, true, filesData, didConfData))
+
+		for _, domain := range []string{"consortium.net", "stakeholder.one"} {
+			for _, configFile := range []string{"consortium.net.json", "stakeholder.one.json"} {
+				_, err = os.Stat(dir + "/" + domain + "/.well-known/did-trustbloc/" + configFile)
+				require.NoError(t, err)
+			}
+		}
+
+		_, err = os.Stat(dir + "/stakeholder.one/.well-known/did-configuration.json")
+		require.NoError(t, err)
+
+		_, err = os.Stat(dir + "/consortium.net/.well-known/did-configuration.json")
+		require.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestWellKnownInvalidArg(t *testing.T) {
+	os.Clearenv()
+
+	startCmd := GetCreateConfigCmd()
+
+	require.NoError(t, os.Setenv(sidetreeURLEnvKey, "localhost:8080"))
+	require.NoError(t, os.Setenv(wellKnownEnvKey, "wrongvalue"))
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid value for well-known")
 }
 
 func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
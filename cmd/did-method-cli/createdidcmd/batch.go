@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package createdidcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+)
+
+const (
+	batchFileFlagName  = "batch-file"
+	batchFileEnvKey    = "DID_METHOD_CLI_BATCH_FILE"
+	batchFileFlagUsage = "JSON manifest of the DIDs to create, each with its own public key, service," +
+		" recovery key and update key files. The domain, sidetree, TLS and token flags apply to all of them." +
+		" Alternatively, this can be set with the following environment variable: " + batchFileEnvKey
+
+	batchConcurrencyFlagName  = "batch-concurrency"
+	batchConcurrencyEnvKey    = "DID_METHOD_CLI_BATCH_CONCURRENCY"
+	batchConcurrencyFlagUsage = "Maximum number of DIDs of the batch file created concurrently." +
+		" Defaults to 4 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + batchConcurrencyEnvKey
+
+	batchRateLimitFlagName  = "batch-rate-limit"
+	batchRateLimitEnvKey    = "DID_METHOD_CLI_BATCH_RATE_LIMIT"
+	batchRateLimitFlagUsage = "Maximum number of DIDs of the batch file created per second, in bursts of up to the" +
+		" batch concurrency, to stay within the rate limit of the sidetree endpoints. Not limited if not set." +
+		" Alternatively, this can be set with the following environment variable: " + batchRateLimitEnvKey
+
+	batchResultsFileFlagName  = "batch-results-file"
+	batchResultsFileEnvKey    = "DID_METHOD_CLI_BATCH_RESULTS_FILE"
+	batchResultsFileFlagUsage = "File to write the results of the batch file to. Printed if not set." +
+		" Alternatively, this can be set with the following environment variable: " + batchResultsFileEnvKey
+
+	defaultBatchConcurrency = 4
+	resultsFilePermissions  = 0600
+
+	batchStatusCreated = "created"
+	batchStatusFailed  = "failed"
+)
+
+// batchEntry is a DID of the batch file
+type batchEntry struct {
+	PublicKeyFile   string `json:"publicKeyFile,omitempty"`
+	ServiceFile     string `json:"serviceFile,omitempty"`
+	RecoveryKeyFile string `json:"recoveryKeyFile"`
+	UpdateKeyFile   string `json:"updateKeyFile"`
+}
+
+// batchResult is the result of creating a DID of the batch file
+type batchResult struct {
+	Index  int    `json:"index" yaml:"index"`
+	DID    string `json:"did,omitempty" yaml:"did,omitempty"`
+	Status string `json:"status" yaml:"status"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func createBatch(cmd *cobra.Command, batchFile string) error {
+	entries, err := readBatchFile(batchFile)
+	if err != nil {
+		return err
+	}
+
+	concurrency, err := getBatchConcurrency(cmd)
+	if err != nil {
+		return err
+	}
+
+	output, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newBatchClient(cmd, concurrency)
+	if err != nil {
+		return err
+	}
+
+	domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainFileEnvKey)
+	sidetreeURLOpts := getSidetreeURL(cmd)
+
+	results := runBatch(entries, concurrency, func(entry *batchEntry) (string, error) {
+		opts, err := batchEntryOptions(entry)
+		if err != nil {
+			return "", err
+		}
+
+		didDoc, err := client.CreateDID(domain, append(opts, sidetreeURLOpts...)...)
+		if err != nil {
+			return "", fmt.Errorf("failed to create did: %w", err)
+		}
+
+		return didDoc.ID, nil
+	})
+
+	if err := writeBatchResults(cmd, output, results); err != nil {
+		return err
+	}
+
+	failed := 0
+
+	for _, result := range results {
+		if result.Status == batchStatusFailed {
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to create %d of %d DIDs", failed, len(results))
+	}
+
+	return nil
+}
+
+// newBatchClient returns the client creating the DIDs of the batch file, rate limited in bursts of up to the
+// concurrency if the batch rate limit is set
+func newBatchClient(cmd *cobra.Command, concurrency int) (*did.Client, error) {
+	rootCAs, err := getRootCAs(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+	if err != nil {
+		return nil, err
+	}
+
+	fipsOption, err := common.GetFIPSOption(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	debugOption, err := common.GetDebugOption(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := common.GetLogger(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit, err := getBatchRateLimit(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return did.New(did.WithAuthToken(cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
+		sidetreeWriteTokenEnvKey)),
+		did.WithTLSConfig(tlsConfig),
+		fipsOption,
+		debugOption,
+		did.WithLogger(logger),
+		did.WithRateLimit(rateLimit, concurrency)), nil
+}
+
+func readBatchFile(batchFile string) ([]batchEntry, error) {
+	bytes, err := ioutil.ReadFile(filepath.Clean(batchFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file '%s': %w", batchFile, err)
+	}
+
+	var entries []batchEntry
+
+	if err := json.Unmarshal(bytes, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch file '%s': %w", batchFile, err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("batch file '%s' is empty", batchFile)
+	}
+
+	return entries, nil
+}
+
+func getBatchConcurrency(cmd *cobra.Command) (int, error) {
+	concurrencyString := cmdutils.GetUserSetOptionalVarFromString(cmd, batchConcurrencyFlagName,
+		batchConcurrencyEnvKey)
+	if concurrencyString == "" {
+		return defaultBatchConcurrency, nil
+	}
+
+	concurrency, err := strconv.Atoi(concurrencyString)
+	if err != nil || concurrency < 1 {
+		return 0, fmt.Errorf("invalid value for %s: %s", batchConcurrencyFlagName, concurrencyString)
+	}
+
+	return concurrency, nil
+}
+
+func getBatchRateLimit(cmd *cobra.Command) (float64, error) {
+	rateLimitString := cmdutils.GetUserSetOptionalVarFromString(cmd, batchRateLimitFlagName, batchRateLimitEnvKey)
+	if rateLimitString == "" {
+		return 0, nil
+	}
+
+	rateLimit, err := strconv.ParseFloat(rateLimitString, 64)
+	if err != nil || rateLimit <= 0 {
+		return 0, fmt.Errorf("invalid value for %s: %s", batchRateLimitFlagName, rateLimitString)
+	}
+
+	return rateLimit, nil
+}
+
+// runBatch calls createFn for every entry, at most concurrency at a time, and returns the results in the order
+// of the entries
+func runBatch(entries []batchEntry, concurrency int, createFn func(*batchEntry) (string, error)) []batchResult {
+	results := make([]batchResult, len(entries))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := range entries {
+		wg.Add(1)
+
+		semaphore <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			results[i] = batchResult{Index: i, Status: batchStatusCreated}
+
+			didID, err := createFn(&entries[i])
+			if err != nil {
+				results[i].Status = batchStatusFailed
+				results[i].Error = err.Error()
+
+				return
+			}
+
+			results[i].DID = didID
+		}(i)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func batchEntryOptions(entry *batchEntry) ([]create.Option, error) {
+	var opts []create.Option
+
+	if entry.PublicKeyFile != "" {
+		publicKeys, err := common.GetPublicKeysFromFile(entry.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get public keys from file %w", err)
+		}
+
+		for i := range publicKeys {
+			opts = append(opts, create.WithPublicKey(&publicKeys[i]))
+		}
+	}
+
+	if entry.ServiceFile != "" {
+		services, err := common.GetServices(entry.ServiceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services from file %w", err)
+		}
+
+		for i := range services {
+			opts = append(opts, create.WithService(&services[i]))
+		}
+	}
+
+	recoveryKey, err := common.PublicKeyFromFile(entry.RecoveryKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recovery key: %w", err)
+	}
+
+	updateKey, err := common.PublicKeyFromFile(entry.UpdateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read update key: %w", err)
+	}
+
+	return append(opts, create.WithRecoveryPublicKey(recoveryKey), create.WithUpdatePublicKey(updateKey)), nil
+}
+
+func writeBatchResults(cmd *cobra.Command, output string, results []batchResult) error {
+	resultsFile := cmdutils.GetUserSetOptionalVarFromString(cmd, batchResultsFileFlagName, batchResultsFileEnvKey)
+	if resultsFile == "" {
+		var text strings.Builder
+
+		for _, result := range results {
+			if result.Status == batchStatusFailed {
+				fmt.Fprintf(&text, "%d %s: %s\n", result.Index, result.Status, result.Error)
+
+				continue
+			}
+
+			fmt.Fprintf(&text, "%d %s %s\n", result.Index, result.Status, result.DID)
+		}
+
+		return common.PrintResult(cmd, output, results, text.String())
+	}
+
+	bytes, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch results: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Clean(resultsFile), bytes, resultsFilePermissions); err != nil {
+		return fmt.Errorf("failed to write batch results file '%s': %w", resultsFile, err)
+	}
+
+	return nil
+}
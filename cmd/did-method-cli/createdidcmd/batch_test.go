@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package createdidcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBatch(t *testing.T) {
+	var count int32
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := fmt.Sprintf("did%d", atomic.AddInt32(&count, 1))
+
+		bytes, err := (&did.Doc{ID: id, Context: []string{did.ContextV1}}).JSONBytes()
+		require.NoError(t, err)
+
+		_, err = fmt.Fprintf(w, `{"didDocument": %s}`, bytes)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	dir, err := ioutil.TempDir("", "batch")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	recoveryKeyFile := filepath.Join(dir, "recovery.pem")
+	require.NoError(t, ioutil.WriteFile(recoveryKeyFile, []byte(recoveryKeyPEM), 0600))
+
+	updateKeyFile := filepath.Join(dir, "update.pem")
+	require.NoError(t, ioutil.WriteFile(updateKeyFile, []byte(updateKeyPEM), 0600))
+
+	batchFile := func(t *testing.T, entries []batchEntry) string {
+		bytes, err := json.Marshal(entries)
+		require.NoError(t, err)
+
+		file := filepath.Join(dir, "batch.json")
+		require.NoError(t, ioutil.WriteFile(file, bytes, 0600))
+
+		return file
+	}
+
+	entry := batchEntry{RecoveryKeyFile: recoveryKeyFile, UpdateKeyFile: updateKeyFile}
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		resultsFile := filepath.Join(dir, "results.json")
+
+		cmd := GetCreateDIDCmd()
+		cmd.SetArgs([]string{
+			flag + batchFileFlagName, batchFile(t, []batchEntry{entry, entry, entry}),
+			flag + batchConcurrencyFlagName, "2",
+			flag + batchRateLimitFlagName, "100",
+			flag + batchResultsFileFlagName, resultsFile,
+			flag + sidetreeURLFlagName, serv.URL,
+		})
+
+		require.NoError(t, cmd.Execute())
+
+		resultsBytes, err := ioutil.ReadFile(filepath.Clean(resultsFile))
+		require.NoError(t, err)
+
+		var results []batchResult
+
+		require.NoError(t, json.Unmarshal(resultsBytes, &results))
+		require.Len(t, results, 3)
+
+		for i, result := range results {
+			require.Equal(t, i, result.Index)
+			require.Equal(t, batchStatusCreated, result.Status)
+			require.NotEmpty(t, result.DID)
+		}
+	})
+
+	t.Run("test failed entries are reported", func(t *testing.T) {
+		os.Clearenv()
+
+		out := &bytes.Buffer{}
+
+		cmd := GetCreateDIDCmd()
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{
+			flag + batchFileFlagName, batchFile(t, []batchEntry{entry, {RecoveryKeyFile: "wrongfile",
+				UpdateKeyFile: updateKeyFile}}),
+			flag + sidetreeURLFlagName, serv.URL,
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create 1 of 2 DIDs")
+		require.Contains(t, out.String(), "0 created did")
+		require.Contains(t, out.String(), "1 failed: failed to read recovery key")
+	})
+
+	t.Run("test invalid batch file", func(t *testing.T) {
+		os.Clearenv()
+
+		file := filepath.Join(dir, "invalid.json")
+		require.NoError(t, ioutil.WriteFile(file, []byte("{"), 0600))
+
+		cmd := GetCreateDIDCmd()
+		cmd.SetArgs([]string{flag + batchFileFlagName, file})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal batch file")
+	})
+
+	t.Run("test empty batch file", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetCreateDIDCmd()
+		cmd.SetArgs([]string{flag + batchFileFlagName, batchFile(t, []batchEntry{})})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is empty")
+	})
+
+	t.Run("test invalid concurrency", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetCreateDIDCmd()
+		cmd.SetArgs([]string{
+			flag + batchFileFlagName, batchFile(t, []batchEntry{entry}),
+			flag + batchConcurrencyFlagName, "0",
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for batch-concurrency")
+	})
+
+	t.Run("test invalid rate limit", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetCreateDIDCmd()
+		cmd.SetArgs([]string{
+			flag + batchFileFlagName, batchFile(t, []batchEntry{entry}),
+			flag + batchRateLimitFlagName, "0",
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for batch-rate-limit")
+	})
+}
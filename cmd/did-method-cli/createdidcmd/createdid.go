@@ -8,9 +8,11 @@ package createdidcmd
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"strconv"
 
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/spf13/cobra"
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
 	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
@@ -18,6 +20,7 @@ import (
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
 )
 
 const (
@@ -77,6 +80,12 @@ const (
 	updateKeyFileFlagUsage = "The file that contains the public key PEM used for" +
 		" validating the signature of the next update of the document." +
 		" Alternatively, this can be set with the following environment variable: " + updateKeyFileEnvKey
+
+	longFormFlagName  = "long-form"
+	longFormEnvKey    = "DID_METHOD_CLI_LONG_FORM"
+	longFormFlagUsage = "Also print the long-form DID, which carries the initial state of the document and can be" +
+		" resolved before the DID is anchored. Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + longFormEnvKey
 )
 
 // GetCreateDIDCmd returns the Cobra create did command.
@@ -94,26 +103,100 @@ func createDIDCmd() *cobra.Command {
 		Short: "Create TrustBloc DID",
 		Long:  "Create TrustBloc DID",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			err := common.ApplyDefaults(cmd, map[string]string{
+				domainFlagName: domainFileEnvKey, sidetreeURLFlagName: sidetreeURLEnvKey})
+			if err != nil {
+				return err
+			}
+
+			if batchFile := cmdutils.GetUserSetOptionalVarFromString(cmd, batchFileFlagName,
+				batchFileEnvKey); batchFile != "" {
+				return createBatch(cmd, batchFile)
+			}
+
 			rootCAs, err := getRootCAs(cmd)
 			if err != nil {
 				return err
 			}
 
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
 			sidetreeWriteToken := cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
 				sidetreeWriteTokenEnvKey)
 
 			domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName,
 				domainFileEnvKey)
 
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			result := common.NewResult("create", "")
+
+			dryRun, err := common.GetDryRun(cmd)
+			if err != nil {
+				return err
+			}
+
+			waitConfig, err := common.GetWaitConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			longForm, err := getLongForm(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger, err := common.GetLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			fipsOption, err := common.GetFIPSOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			debugOption, err := common.GetDebugOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			didWebPath, didWebOption := common.GetDIDWeb(cmd)
+
 			client := did.New(did.WithAuthToken(sidetreeWriteToken),
-				did.WithTLSConfig(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}))
+				did.WithTLSConfig(tlsConfig),
+				did.WithOperationCallback(result.SetOperationInfo),
+				did.WithDryRun(common.DryRunCallback(cmd, dryRun)),
+				fipsOption,
+				debugOption,
+				didWebOption,
+				did.WithLogger(logger))
+
+			ks, keySet, err := common.GetKeySet(cmd)
+			if err != nil {
+				return err
+			}
 
-			opts, err := createDIDOption(cmd)
+			opts, err := createDIDOption(cmd, keySet)
 			if err != nil {
 				return err
 			}
 
+			if didWebPath != "" {
+				opts = append(opts, create.WithDIDWeb(didWebPath))
+			}
+
 			didDoc, err := client.CreateDID(domain, opts...)
+			if errors.Is(err, did.ErrDryRun) {
+				return nil
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to create did: %w", err)
 			}
@@ -123,13 +206,92 @@ func createDIDCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println(string(bytes))
+			result.DID = didDoc.ID
+
+			if err := result.SetDIDDocument(bytes); err != nil {
+				return err
+			}
+
+			text := string(bytes)
+
+			if longForm {
+				if err := result.SetLongFormDID(); err != nil {
+					return err
+				}
+
+				text += "\n" + result.LongFormDID
+			}
+
+			if err := common.PrintResult(cmd, output, result, text); err != nil {
+				return err
+			}
+
+			if err := writeState(cmd, didDoc.ID, domain, keySet); err != nil {
+				return err
+			}
 
-			return nil
+			if err := ks.Commit(keySet, didDoc.ID); err != nil {
+				return err
+			}
+
+			return waitForDID(cmd, waitConfig, didDoc.ID, domain, tlsConfig, sidetreeWriteToken)
 		},
 	}
 }
 
+// getLongForm returns whether --long-form is set
+func getLongForm(cmd *cobra.Command) (bool, error) {
+	longFormString := cmdutils.GetUserSetOptionalVarFromString(cmd, longFormFlagName, longFormEnvKey)
+	if longFormString == "" {
+		return false, nil
+	}
+
+	longForm, err := strconv.ParseBool(longFormString)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", longFormFlagName, err)
+	}
+
+	return longForm, nil
+}
+
+// waitForDID blocks until the created DID can be resolved, if --wait is set
+func waitForDID(cmd *cobra.Command, waitConfig *common.WaitConfig, didID, domain string, tlsConfig *tls.Config,
+	authToken string) error {
+	if waitConfig == nil {
+		return nil
+	}
+
+	return common.WaitForDID(waitConfig, didID, domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey), tlsConfig,
+		authToken, func(*docdid.Doc) bool { return true })
+}
+
+func writeState(cmd *cobra.Command, didID, domain string, keySet *keystore.KeySet) error {
+	state := common.NewState("create", didID, domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey))
+
+	state.AddKeyReference(cmd, updateKeyFileFlagName, updateKeyFileEnvKey)
+	state.AddKeyReference(cmd, recoveryKeyFileFlagName, recoveryKeyFileEnvKey)
+
+	updateKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeUpdate, updateKeyFlagName, updateKeyEnvKey,
+		updateKeyFileFlagName, updateKeyFileEnvKey)
+	if err != nil {
+		return err
+	}
+
+	recoveryKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeRecovery, recoveryKeyFlagName,
+		recoveryKeyEnvKey, recoveryKeyFileFlagName, recoveryKeyFileEnvKey)
+	if err != nil {
+		return err
+	}
+
+	if err := state.SetCommitments(updateKey, recoveryKey); err != nil {
+		return err
+	}
+
+	return common.WriteStateOut(cmd, state)
+}
+
 func getSidetreeURL(cmd *cobra.Command) []create.Option {
 	var opts []create.Option
 
@@ -143,22 +305,22 @@ func getSidetreeURL(cmd *cobra.Command) []create.Option {
 	return opts
 }
 
-func createDIDOption(cmd *cobra.Command) ([]create.Option, error) {
+func createDIDOption(cmd *cobra.Command, keySet *keystore.KeySet) ([]create.Option, error) {
 	opts, err := getPublicKeys(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	recoveryKey, err := common.GetKey(cmd, recoveryKeyFlagName, recoveryKeyEnvKey, recoveryKeyFileFlagName,
-		recoveryKeyFileEnvKey, nil, false)
+	recoveryKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeRecovery, recoveryKeyFlagName,
+		recoveryKeyEnvKey, recoveryKeyFileFlagName, recoveryKeyFileEnvKey)
 	if err != nil {
 		return nil, err
 	}
 
 	opts = append(opts, create.WithRecoveryPublicKey(recoveryKey))
 
-	updateKey, err := common.GetKey(cmd, updateKeyFlagName, updateKeyEnvKey, updateKeyFileFlagName,
-		updateKeyFileEnvKey, nil, false)
+	updateKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeUpdate, updateKeyFlagName, updateKeyEnvKey,
+		updateKeyFileFlagName, updateKeyFileEnvKey)
 	if err != nil {
 		return nil, err
 	}
@@ -243,6 +405,7 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
 		tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
 	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
 	startCmd.Flags().StringP(publicKeyFileFlagName, "", "", publicKeyFileFlagUsage)
 	startCmd.Flags().StringP(serviceFileFlagName, "", "", serviceFlagUsage)
@@ -250,5 +413,20 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(recoveryKeyFileFlagName, "", "", recoveryKeyFileFlagUsage)
 	startCmd.Flags().StringP(updateKeyFlagName, "", "", updateKeyFlagUsage)
 	startCmd.Flags().StringP(updateKeyFileFlagName, "", "", updateKeyFileFlagUsage)
+	common.AddKeyAliasFlags(startCmd)
 	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
+	startCmd.Flags().StringP(common.StateOutFlagName, "", "", common.StateOutFlagUsage)
+	startCmd.Flags().StringP(common.StateInFlagName, "", "", common.StateInFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.DryRunFlagName, "", "", common.DryRunFlagUsage)
+	startCmd.Flags().StringP(common.FIPSFlagName, "", "", common.FIPSFlagUsage)
+	common.AddWaitFlags(startCmd)
+	common.AddDIDWebFlags(startCmd)
+	startCmd.Flags().StringP(longFormFlagName, "", "", longFormFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
+	startCmd.Flags().StringP(batchFileFlagName, "", "", batchFileFlagUsage)
+	startCmd.Flags().StringP(batchConcurrencyFlagName, "", "", batchConcurrencyFlagUsage)
+	startCmd.Flags().StringP(batchRateLimitFlagName, "", "", batchRateLimitFlagUsage)
+	startCmd.Flags().StringP(batchResultsFileFlagName, "", "", batchResultsFileFlagUsage)
 }
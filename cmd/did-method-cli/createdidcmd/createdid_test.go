@@ -6,16 +6,20 @@ SPDX-License-Identifier: Apache-2.0
 package createdidcmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 )
 
 const (
@@ -142,7 +146,7 @@ func TestCreateDID(t *testing.T) {
 	}
 
 	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+		bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 		require.NoError(t, err)
 		b, err := json.Marshal(didResolution{Context: "https://www.w3.org/ns/did-resolution/v1",
 			DIDDocument: bytes})
@@ -234,6 +238,69 @@ func TestCreateDID(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("success with long form", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		var args []string
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, recoveryKeyFileFlagNameArg(recoveryKeyFile.Name())...)
+		args = append(args, updateKeyFileFlagNameArg(updateKeyFile.Name())...)
+		args = append(args, flag+longFormFlagName, "true", flag+common.OutputFlagName, common.OutputJSON)
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		result := &common.Result{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), result))
+		require.Equal(t, "did1", result.DID)
+		require.True(t, strings.HasPrefix(result.LongFormDID, "did1:"))
+	})
+
+	t.Run("test invalid long form", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		cmd.SetArgs([]string{flag + longFormFlagName, "maybe"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for long-form")
+	})
+
+	t.Run("success with state out", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCreateDIDCmd()
+
+		stateFile, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(stateFile.Name())) }()
+
+		var args []string
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, recoveryKeyFileFlagNameArg(recoveryKeyFile.Name())...)
+		args = append(args, updateKeyFileFlagNameArg(updateKeyFile.Name())...)
+		args = append(args, flag+common.StateOutFlagName, stateFile.Name())
+
+		cmd.SetArgs(args)
+		err = cmd.Execute()
+		require.NoError(t, err)
+
+		state, err := common.ReadState(stateFile.Name())
+		require.NoError(t, err)
+		require.Equal(t, "create", state.Operation)
+		require.Equal(t, "did1", state.DID)
+		require.Equal(t, []string{serv.URL}, state.SidetreeURLs)
+		require.Equal(t, updateKeyFile.Name(), state.Keys[updateKeyFileFlagName])
+		require.Equal(t, recoveryKeyFile.Name(), state.Keys[recoveryKeyFileFlagName])
+		require.NotEmpty(t, state.Commitments.Update)
+		require.NotEmpty(t, state.Commitments.Recovery)
+	})
 }
 
 func TestGetPublicKeys(t *testing.T) {
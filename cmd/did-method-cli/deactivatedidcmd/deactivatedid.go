@@ -6,10 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package deactivatedidcmd
 
 import (
-	"crypto/tls"
+	"bufio"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
@@ -18,6 +20,7 @@ import (
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
 )
 
 const (
@@ -67,6 +70,12 @@ const (
 	signingKeyPasswordEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_PASSWORD" //nolint: gosec
 	signingKeyPasswordFlagUsage = "signing key pem password. " +
 		" Alternatively, this can be set with the following environment variable: " + signingKeyPasswordEnvKey
+
+	confirmFlagName  = "yes"
+	confirmEnvKey    = "DID_METHOD_CLI_YES"
+	confirmFlagUsage = "Deactivate the DID without asking for confirmation. Deactivation cannot be undone." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + confirmEnvKey
 )
 
 // GetDeactivateDIDCmd returns the Cobra deactivate did command.
@@ -84,11 +93,22 @@ func deactivateDIDCmd() *cobra.Command {
 		Short: "Deactivate TrustBloc DID",
 		Long:  "Deactivate TrustBloc DID",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			err := common.ApplyDefaults(cmd, map[string]string{
+				didURIFlagName: didURIEnvKey, domainFlagName: domainFileEnvKey, sidetreeURLFlagName: sidetreeURLEnvKey})
+			if err != nil {
+				return err
+			}
+
 			rootCAs, err := getRootCAs(cmd)
 			if err != nil {
 				return err
 			}
 
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
 			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName,
 				didURIEnvKey, false)
 			if err != nil {
@@ -101,22 +121,62 @@ func deactivateDIDCmd() *cobra.Command {
 			domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName,
 				domainFileEnvKey)
 
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			result := common.NewResult("deactivate", didURI)
+
+			dryRun, err := common.GetDryRun(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger, err := common.GetLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			fipsOption, err := common.GetFIPSOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			debugOption, err := common.GetDebugOption(cmd)
+			if err != nil {
+				return err
+			}
+
 			client := did.New(did.WithAuthToken(sidetreeWriteToken),
-				did.WithTLSConfig(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}))
+				did.WithTLSConfig(tlsConfig),
+				did.WithOperationCallback(result.SetOperationInfo),
+				did.WithDryRun(common.DryRunCallback(cmd, dryRun)),
+				fipsOption,
+				debugOption,
+				did.WithLogger(logger))
 
 			opts, err := deactivateDIDOption(cmd)
 			if err != nil {
 				return err
 			}
 
+			if !dryRun {
+				if err := confirmDeactivate(cmd, didURI); err != nil {
+					return err
+				}
+			}
+
 			err = client.DeactivateDID(didURI, domain, opts...)
+			if errors.Is(err, did.ErrDryRun) {
+				return nil
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to deactivate did: %w", err)
 			}
 
-			fmt.Printf("successfully deactivated DID %s", didURI)
-
-			return nil
+			return common.PrintResult(cmd, output, result, fmt.Sprintf("successfully deactivated DID %s", didURI))
 		},
 	}
 }
@@ -137,9 +197,14 @@ func getSidetreeURL(cmd *cobra.Command) []deactivate.Option {
 func deactivateDIDOption(cmd *cobra.Command) ([]deactivate.Option, error) {
 	var opts []deactivate.Option
 
-	signingKey, err := common.GetKey(cmd, signingKeyFlagName, signingKeyEnvKey, signingKeyFileFlagName,
-		signingKeyFileEnvKey, []byte(cmdutils.GetUserSetOptionalVarFromString(cmd, signingKeyPasswordFlagName,
-			signingKeyPasswordEnvKey)), true)
+	_, keySet, err := common.GetKeySet(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey, err := common.GetSigningKeyFromKeySet(cmd, keySet, keystore.PurposeRecovery, signingKeyFlagName,
+		signingKeyEnvKey, signingKeyFileFlagName, signingKeyFileEnvKey, signingKeyPasswordFlagName,
+		signingKeyPasswordEnvKey)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +216,36 @@ func deactivateDIDOption(cmd *cobra.Command) ([]deactivate.Option, error) {
 	return opts, nil
 }
 
+// confirmDeactivate asks the user to confirm the deactivation on stdin, unless --yes is set
+func confirmDeactivate(cmd *cobra.Command, didURI string) error {
+	confirmString := cmdutils.GetUserSetOptionalVarFromString(cmd, confirmFlagName, confirmEnvKey)
+
+	if confirmString != "" {
+		confirmed, err := strconv.ParseBool(confirmString)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", confirmFlagName, err)
+		}
+
+		if confirmed {
+			return nil
+		}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Deactivating DID %s cannot be undone. Continue? [y/N]: ", didURI)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return fmt.Errorf("deactivation of DID %s was not confirmed", didURI)
+	}
+}
+
 func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
 	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
 		tlsSystemCertPoolEnvKey)
@@ -178,9 +273,29 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
 		tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
 	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
 	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
 	startCmd.Flags().StringP(signingKeyFlagName, "", "", signingKeyFlagUsage)
 	startCmd.Flags().StringP(signingKeyFileFlagName, "", "", signingKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+	common.AddKeyAliasFlags(startCmd)
 	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
+	startCmd.Flags().StringP(common.StateInFlagName, "", "", common.StateInFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.DryRunFlagName, "", "", common.DryRunFlagUsage)
+	startCmd.Flags().StringP(common.FIPSFlagName, "", "", common.FIPSFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
+	startCmd.Flags().StringP(confirmFlagName, "", "", confirmFlagUsage)
 }
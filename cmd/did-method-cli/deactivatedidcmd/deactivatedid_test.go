@@ -6,10 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package deactivatedidcmd
 
 import (
+	"bytes"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -69,6 +71,7 @@ func TestDeactivateDID(t *testing.T) {
 		args = append(args, sidetreeURLArg("wrongurl")...)
 		args = append(args, signingKeyPasswordArg()...)
 		args = append(args, signingKeyFileFlagNameArg(privateKeyfile.Name())...)
+		args = append(args, confirmArg("true")...)
 
 		cmd.SetArgs(args)
 		err := cmd.Execute()
@@ -86,6 +89,7 @@ func TestDeactivateDID(t *testing.T) {
 		args = append(args, sidetreeURLArg(serv.URL)...)
 		args = append(args, signingKeyPasswordArg()...)
 		args = append(args, signingKeyFileFlagNameArg(privateKeyfile.Name())...)
+		args = append(args, confirmArg("true")...)
 
 		cmd.SetArgs(args)
 		err := cmd.Execute()
@@ -94,6 +98,91 @@ func TestDeactivateDID(t *testing.T) {
 	})
 }
 
+func TestConfirmDeactivate(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer serv.Close()
+
+	privateKeyfile, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	_, err = privateKeyfile.WriteString(privateKeyPEM)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.Remove(privateKeyfile.Name())) }()
+
+	args := func() []string {
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyfile.Name())...)
+
+		return args
+	}
+
+	t.Run("test confirmed on prompt", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDeactivateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetErr(out)
+		cmd.SetIn(strings.NewReader("y\n"))
+		cmd.SetArgs(args())
+
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "Deactivating DID did:ex:123 cannot be undone")
+	})
+
+	t.Run("test declined on prompt", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDeactivateDIDCmd()
+
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader("n\n"))
+		cmd.SetArgs(args())
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "deactivation of DID did:ex:123 was not confirmed")
+	})
+
+	t.Run("test no answer", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDeactivateDIDCmd()
+
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader(""))
+		cmd.SetArgs(args())
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read confirmation")
+	})
+
+	t.Run("test confirmed with env", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDeactivateDIDCmd()
+
+		require.NoError(t, os.Setenv(confirmEnvKey, "true"))
+
+		cmd.SetArgs(args())
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("test invalid confirm value", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDeactivateDIDCmd()
+
+		cmd.SetArgs(append(args(), confirmArg("wrongvalue")...))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for yes")
+	})
+}
+
 func TestKeys(t *testing.T) {
 	t.Run("test error getting signing key", func(t *testing.T) {
 		os.Clearenv()
@@ -142,3 +231,7 @@ func signingKeyFileFlagNameArg(value string) []string {
 func signingKeyPasswordArg() []string {
 	return []string{flag + signingKeyPasswordFlagName, "123"}
 }
+
+func confirmArg(value string) []string {
+	return []string{flag + confirmFlagName, value}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didconfigurationcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+const (
+	didURIFlagName  = "did-uri"
+	didURIEnvKey    = "DID_METHOD_CLI_DID_URI"
+	didURIFlagUsage = "DID URI. " +
+		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
+
+	originFlagName  = "origin"
+	originEnvKey    = "DID_METHOD_CLI_ORIGIN"
+	originFlagUsage = "Web origin the DID is linked to, such as https://example.com." +
+		" Alternatively, this can be set with the following environment variable: " + originEnvKey
+
+	signingKeyIDFlagName  = "signingkey-id"
+	signingKeyIDEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_ID"
+	signingKeyIDFlagUsage = "ID of the public key of the DID document whose private key signs the credential," +
+		" such as key1." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyIDEnvKey
+
+	signingKeyFlagName  = "signingkey"
+	signingKeyEnvKey    = "DID_METHOD_CLI_SIGNINGKEY"
+	signingKeyFlagUsage = "The private key PEM used for signing the domain linkage credential." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyEnvKey
+
+	signingKeyFileFlagName  = "signingkey-file"
+	signingKeyFileEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_FILE"
+	signingKeyFileFlagUsage = "The file that contains the private key" +
+		" PEM used for signing the domain linkage credential." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyFileEnvKey
+
+	signingKeyPasswordFlagName  = "signingkey-password"
+	signingKeyPasswordEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_PASSWORD" //nolint: gosec
+	signingKeyPasswordFlagUsage = "signing key pem password. " +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyPasswordEnvKey
+
+	validityFlagName  = "validity"
+	validityEnvKey    = "DID_METHOD_CLI_VALIDITY"
+	validityFlagUsage = "How long the credential is valid for, for example 720h. Defaults to one year." +
+		" Alternatively, this can be set with the following environment variable: " + validityEnvKey
+
+	outputFileFlagName  = "output-file"
+	outputFileEnvKey    = "DID_METHOD_CLI_OUTPUT_FILE"
+	outputFileFlagUsage = "The did-configuration.json file to write, to which the credential is added if it exists." +
+		" Defaults to did-configuration.json." +
+		" Alternatively, this can be set with the following environment variable: " + outputFileEnvKey
+
+	defaultValidity   = 365 * 24 * time.Hour
+	defaultOutputFile = "did-configuration.json"
+	filePermissions   = 0644
+)
+
+// GetDIDConfigurationCmd returns the Cobra create did configuration command.
+func GetDIDConfigurationCmd() *cobra.Command {
+	didConfigurationCmd := didConfigurationCmd()
+
+	createFlags(didConfigurationCmd)
+
+	return didConfigurationCmd
+}
+
+func didConfigurationCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create-did-configuration",
+		Short: "Create the DID configuration linking a DID to a web origin",
+		Long: "Create the did-configuration.json served at /.well-known/did-configuration.json of a web origin," +
+			" with a domain linkage credential linking the DID to the origin",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName, didURIEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			origin, err := getOrigin(cmd)
+			if err != nil {
+				return err
+			}
+
+			keyID, err := cmdutils.GetUserSetVarFromString(cmd, signingKeyIDFlagName, signingKeyIDEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			validity, err := getValidity(cmd)
+			if err != nil {
+				return err
+			}
+
+			signingKey, err := common.GetSigningKey(cmd, signingKeyFlagName, signingKeyEnvKey, signingKeyFileFlagName,
+				signingKeyFileEnvKey, signingKeyPasswordFlagName, signingKeyPasswordEnvKey)
+			if err != nil {
+				return err
+			}
+
+			issued := time.Now()
+
+			credential, err := did.CreateDomainLinkageCredential(didURI, origin, keyID, signingKey, issued,
+				issued.Add(validity))
+			if err != nil {
+				return fmt.Errorf("failed to create domain linkage credential: %w", err)
+			}
+
+			outputFile := cmdutils.GetUserSetOptionalVarFromString(cmd, outputFileFlagName, outputFileEnvKey)
+			if outputFile == "" {
+				outputFile = defaultOutputFile
+			}
+
+			err = writeDIDConfiguration(outputFile, credential)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "DID configuration written to %s\n", outputFile)
+
+			return nil
+		},
+	}
+}
+
+// getOrigin returns the origin, which is a scheme and a host without path
+func getOrigin(cmd *cobra.Command) (string, error) {
+	origin, err := cmdutils.GetUserSetVarFromString(cmd, originFlagName, originEnvKey, false)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+		return "", fmt.Errorf("invalid origin '%s': must be a scheme and a host, such as https://example.com",
+			origin)
+	}
+
+	return u.Scheme + "://" + u.Host, nil
+}
+
+func getValidity(cmd *cobra.Command) (time.Duration, error) {
+	validityString := cmdutils.GetUserSetOptionalVarFromString(cmd, validityFlagName, validityEnvKey)
+	if validityString == "" {
+		return defaultValidity, nil
+	}
+
+	validity, err := time.ParseDuration(validityString)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", validityFlagName, err)
+	}
+
+	if validity <= 0 {
+		return 0, fmt.Errorf("invalid value for %s: must be positive", validityFlagName)
+	}
+
+	return validity, nil
+}
+
+// writeDIDConfiguration writes the DID configuration with the credential, adding it to the linked DIDs of the
+// existing file
+func writeDIDConfiguration(outputFile, credential string) error {
+	conf := did.NewDIDConfiguration()
+
+	data, err := ioutil.ReadFile(filepath.Clean(outputFile))
+
+	switch {
+	case err == nil:
+		if errUnmarshal := json.Unmarshal(data, conf); errUnmarshal != nil {
+			return fmt.Errorf("failed to parse DID configuration '%s': %w", outputFile, errUnmarshal)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read DID configuration '%s': %w", outputFile, err)
+	}
+
+	conf.LinkedDIDs = append(conf.LinkedDIDs, credential)
+
+	data, err = json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal DID configuration: %w", err)
+	}
+
+	err = ioutil.WriteFile(filepath.Clean(outputFile), data, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to write DID configuration '%s': %w", outputFile, err)
+	}
+
+	return nil
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
+	startCmd.Flags().StringP(originFlagName, "", "", originFlagUsage)
+	startCmd.Flags().StringP(signingKeyIDFlagName, "", "", signingKeyIDFlagUsage)
+	startCmd.Flags().StringP(signingKeyFlagName, "", "", signingKeyFlagUsage)
+	startCmd.Flags().StringP(signingKeyFileFlagName, "", "", signingKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
+	startCmd.Flags().StringP(validityFlagName, "", "", validityFlagUsage)
+	startCmd.Flags().StringP(outputFileFlagName, "", "", outputFileFlagUsage)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffconfigcmd
+
+import (
+	"crypto"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gojose "github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+// diff is what changed between two versions of a consortium config
+type diff struct {
+	Domain  string               `json:"domain" yaml:"domain"`
+	Chained bool                 `json:"chained" yaml:"chained"`
+	Policy  []*change            `json:"policy,omitempty" yaml:"policy,omitempty"`
+	Added   []*member            `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed []*member            `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Changed []*stakeholderChange `json:"changed,omitempty" yaml:"changed,omitempty"`
+}
+
+// change is the old and new values of a field
+type change struct {
+	Field string `json:"field" yaml:"field"`
+	Old   string `json:"old" yaml:"old"`
+	New   string `json:"new" yaml:"new"`
+}
+
+// member is a stakeholder added to or removed from the consortium
+type member struct {
+	Domain string `json:"domain" yaml:"domain"`
+	DID    string `json:"did" yaml:"did"`
+}
+
+// stakeholderChange is what changed for a stakeholder that is a member of both versions
+type stakeholderChange struct {
+	Domain           string    `json:"domain" yaml:"domain"`
+	Changes          []*change `json:"changes,omitempty" yaml:"changes,omitempty"`
+	EndpointsAdded   []string  `json:"endpointsAdded,omitempty" yaml:"endpointsAdded,omitempty"`
+	EndpointsRemoved []string  `json:"endpointsRemoved,omitempty" yaml:"endpointsRemoved,omitempty"`
+}
+
+func compare(oldVersion, newVersion *version) *diff {
+	d := &diff{Domain: newVersion.consortium.Domain,
+		Chained: newVersion.consortium.Previous == configcommon.ConfigHash(oldVersion.data)}
+
+	d.Policy = comparePolicy(&oldVersion.consortium.Policy, &newVersion.consortium.Policy)
+
+	oldMembers := members(oldVersion.consortium)
+	newMembers := members(newVersion.consortium)
+
+	for _, m := range oldVersion.consortium.Members {
+		if m != nil && newMembers[m.Domain] == nil {
+			d.Removed = append(d.Removed, &member{Domain: m.Domain, DID: m.DID})
+		}
+	}
+
+	for _, m := range newVersion.consortium.Members {
+		if m == nil {
+			continue
+		}
+
+		old := oldMembers[m.Domain]
+		if old == nil {
+			d.Added = append(d.Added, &member{Domain: m.Domain, DID: m.DID})
+
+			continue
+		}
+
+		c := compareStakeholder(old, m, oldVersion.stakeholders[m.Domain], newVersion.stakeholders[m.Domain])
+		if len(c.Changes) > 0 || len(c.EndpointsAdded) > 0 || len(c.EndpointsRemoved) > 0 {
+			d.Changed = append(d.Changed, c)
+		}
+	}
+
+	return d
+}
+
+func comparePolicy(oldPolicy, newPolicy *models.ConsortiumPolicy) []*change {
+	var changes []*change
+
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, &change{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("cache.maxAge", strconv.FormatUint(uint64(oldPolicy.Cache.MaxAge), 10),
+		strconv.FormatUint(uint64(newPolicy.Cache.MaxAge), 10))
+	add("numQueries", strconv.Itoa(oldPolicy.NumQueries), strconv.Itoa(newPolicy.NumQueries))
+	add("sidetree.multihashAlgorithm", multihashAlgorithm(oldPolicy), multihashAlgorithm(newPolicy))
+
+	return changes
+}
+
+func multihashAlgorithm(policy *models.ConsortiumPolicy) string {
+	if policy.Sidetree == nil || policy.Sidetree.MultiHashAlgorithm == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(policy.Sidetree.MultiHashAlgorithm), 10)
+}
+
+func members(consortium *models.Consortium) map[string]*models.StakeholderListElement {
+	m := map[string]*models.StakeholderListElement{}
+
+	for _, element := range consortium.Members {
+		if element != nil {
+			m[element.Domain] = element
+		}
+	}
+
+	return m
+}
+
+func compareStakeholder(oldMember, newMember *models.StakeholderListElement,
+	oldStakeholder, newStakeholder *models.Stakeholder) *stakeholderChange {
+	c := &stakeholderChange{Domain: newMember.Domain}
+
+	if oldMember.DID != newMember.DID {
+		c.Changes = append(c.Changes, &change{Field: "did", Old: oldMember.DID, New: newMember.DID})
+	}
+
+	oldKey, newKey := describeKey(&oldMember.PublicKey), describeKey(&newMember.PublicKey)
+	if oldKey != newKey {
+		c.Changes = append(c.Changes, &change{Field: "publicKey", Old: oldKey, New: newKey})
+	}
+
+	if oldStakeholder == nil || newStakeholder == nil {
+		return c
+	}
+
+	if oldStakeholder.Policy.Cache.MaxAge != newStakeholder.Policy.Cache.MaxAge {
+		c.Changes = append(c.Changes, &change{Field: "policy.cache.maxAge",
+			Old: strconv.FormatUint(uint64(oldStakeholder.Policy.Cache.MaxAge), 10),
+			New: strconv.FormatUint(uint64(newStakeholder.Policy.Cache.MaxAge), 10)})
+	}
+
+	c.EndpointsAdded = missing(newStakeholder.Endpoints, oldStakeholder.Endpoints)
+	c.EndpointsRemoved = missing(oldStakeholder.Endpoints, newStakeholder.Endpoints)
+
+	return c
+}
+
+// describeKey describes a member key by its ID and JWK thumbprint, so that a key rotated under the same ID shows
+func describeKey(publicKey *models.PublicKey) string {
+	key := gojose.JSONWebKey{}
+	if err := key.UnmarshalJSON(publicKey.JWK); err != nil {
+		return publicKey.ID + " " + string(publicKey.JWK)
+	}
+
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return publicKey.ID + " " + string(publicKey.JWK)
+	}
+
+	return strings.TrimSpace(publicKey.ID + " " + base64.RawURLEncoding.EncodeToString(thumbprint))
+}
+
+// missing returns the values that are in a but not in b
+func missing(a, b []string) []string {
+	var result []string
+
+	for _, value := range a {
+		found := false
+
+		for _, other := range b {
+			if value == other {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+func (d *diff) text() string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "consortium config %s\n", d.Domain)
+
+	if d.Chained {
+		fmt.Fprintln(b, "new version chains to the old version")
+	} else {
+		fmt.Fprintln(b, "new version doesn't chain to the old version")
+	}
+
+	for _, c := range d.Policy {
+		fmt.Fprintf(b, "~ policy %s: %s -> %s\n", c.Field, c.Old, c.New)
+	}
+
+	for _, m := range d.Added {
+		fmt.Fprintf(b, "+ stakeholder %s %s\n", m.Domain, m.DID)
+	}
+
+	for _, m := range d.Removed {
+		fmt.Fprintf(b, "- stakeholder %s %s\n", m.Domain, m.DID)
+	}
+
+	for _, s := range d.Changed {
+		fmt.Fprintf(b, "~ stakeholder %s\n", s.Domain)
+
+		for _, c := range s.Changes {
+			fmt.Fprintf(b, "    %s: %s -> %s\n", c.Field, c.Old, c.New)
+		}
+
+		for _, endpoint := range s.EndpointsAdded {
+			fmt.Fprintf(b, "    + endpoint %s\n", endpoint)
+		}
+
+		for _, endpoint := range s.EndpointsRemoved {
+			fmt.Fprintf(b, "    - endpoint %s\n", endpoint)
+		}
+	}
+
+	if len(d.Policy) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 {
+		fmt.Fprintln(b, "no changes")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,225 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffconfigcmd
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	oldFlagName  = "old"
+	oldEnvKey    = "DID_METHOD_CLI_OLD"
+	oldFlagUsage = "File path or URL of the old version of the consortium config." +
+		" The stakeholder configs are read from the same directory or URL path, named <domain>.json." +
+		" Alternatively, this can be set with the following environment variable: " + oldEnvKey
+
+	newFlagName  = "new"
+	newEnvKey    = "DID_METHOD_CLI_NEW"
+	newFlagUsage = "File path or URL of the new version of the consortium config." +
+		" The stakeholder configs are read from the same directory or URL path, named <domain>.json." +
+		" Alternatively, this can be set with the following environment variable: " + newEnvKey
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "DID_METHOD_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "DID_METHOD_CLI_TLS_CACERTS"
+)
+
+// GetDiffConfigCmd returns the Cobra diff config command.
+func GetDiffConfigCmd() *cobra.Command {
+	diffConfigCmd := createDiffConfigCmd()
+
+	createFlags(diffConfigCmd)
+
+	return diffConfigCmd
+}
+
+func createDiffConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff-config",
+		Short: "Compare two versions of a consortium config",
+		Long: "Compare two versions of a consortium config and their stakeholder configs, and print what changed:" +
+			" stakeholders added or removed, endpoints changed, keys rotated and policy changes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldLocation, err := cmdutils.GetUserSetVarFromString(cmd, oldFlagName, oldEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			newLocation, err := cmdutils.GetUserSetVarFromString(cmd, newFlagName, newEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
+			r := &reader{httpClient: &http.Client{Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+				Proxy:           http.ProxyFromEnvironment}}}
+
+			oldVersion, err := r.readVersion(oldLocation)
+			if err != nil {
+				return err
+			}
+
+			newVersion, err := r.readVersion(newLocation)
+			if err != nil {
+				return err
+			}
+
+			d := compare(oldVersion, newVersion)
+
+			return common.PrintResult(cmd, output, d, d.text())
+		},
+	}
+}
+
+// version is a version of a consortium config, together with the stakeholder configs found next to it
+type version struct {
+	data         []byte
+	consortium   *models.Consortium
+	stakeholders map[string]*models.Stakeholder
+}
+
+type reader struct {
+	httpClient *http.Client
+}
+
+func (r *reader) readVersion(location string) (*version, error) {
+	data, err := r.read(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consortium config '%s' : %w", location, err)
+	}
+
+	consortium, err := models.ParseConsortium(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse consortium config '%s' : %w", location, err)
+	}
+
+	v := &version{data: data, consortium: consortium.Config, stakeholders: map[string]*models.Stakeholder{}}
+
+	for _, member := range consortium.Config.Members {
+		if member == nil {
+			continue
+		}
+
+		// a stakeholder config that can't be read is left out, and its endpoints aren't compared
+		if stakeholder := r.readStakeholder(sibling(location, member.Domain+".json")); stakeholder != nil {
+			v.stakeholders[member.Domain] = stakeholder
+		}
+	}
+
+	return v, nil
+}
+
+func (r *reader) readStakeholder(location string) *models.Stakeholder {
+	data, err := r.read(location)
+	if err != nil {
+		return nil
+	}
+
+	stakeholder, err := models.ParseStakeholder(data)
+	if err != nil {
+		return nil
+	}
+
+	return stakeholder.Config
+}
+
+func (r *reader) read(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return ioutil.ReadFile(filepath.Clean(location))
+	}
+
+	resp, err := r.httpClient.Get(location)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed: error %d", resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// sibling returns the location of the file with the given name in the same directory or URL path as the location
+func sibling(location, name string) string {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return filepath.Join(filepath.Dir(location), name)
+	}
+
+	return location[:strings.LastIndex(location, "/")+1] + name
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(oldFlagName, "", "", oldFlagUsage)
+	startCmd.Flags().StringP(newFlagName, "", "", newFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffconfigcmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const flag = "--"
+
+type stakeholder struct {
+	domain    string
+	key       ed25519.PrivateKey
+	endpoints []string
+}
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test old is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetDiffConfigCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither old (command line flag) nor "+
+			"DID_METHOD_CLI_OLD (environment variable) have been set.")
+	})
+}
+
+func TestDiffConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	one := &stakeholder{domain: "stakeholder.one", key: generateKey(t),
+		endpoints: []string{"https://stakeholder.one/peer1", "https://stakeholder.one/peer2"}}
+	two := &stakeholder{domain: "stakeholder.two", key: generateKey(t), endpoints: []string{"https://stakeholder.two"}}
+	three := &stakeholder{domain: "stakeholder.three", key: generateKey(t),
+		endpoints: []string{"https://stakeholder.three"}}
+
+	oldFile := writeVersion(t, filepath.Join(dir, "old"), "", 2, one, two)
+
+	oldBytes, err := ioutil.ReadFile(filepath.Clean(oldFile))
+	require.NoError(t, err)
+
+	rotated := &stakeholder{domain: "stakeholder.one", key: generateKey(t),
+		endpoints: []string{"https://stakeholder.one/peer1", "https://stakeholder.one/peer3"}}
+
+	newFile := writeVersion(t, filepath.Join(dir, "new"), configcommon.ConfigHash(oldBytes), 1, rotated, three)
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetDiffConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + oldFlagName, oldFile, flag + newFlagName, newFile})
+		require.NoError(t, cmd.Execute())
+
+		require.Contains(t, out.String(), "new version chains to the old version")
+		require.Contains(t, out.String(), "~ policy numQueries: 2 -> 1")
+		require.Contains(t, out.String(), "+ stakeholder stakeholder.three")
+		require.Contains(t, out.String(), "- stakeholder stakeholder.two")
+		require.Contains(t, out.String(), "~ stakeholder stakeholder.one\n    publicKey: ")
+		require.Contains(t, out.String(), "    + endpoint https://stakeholder.one/peer3")
+		require.Contains(t, out.String(), "    - endpoint https://stakeholder.one/peer2")
+	})
+
+	t.Run("success with json output", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetDiffConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + oldFlagName, newFile, flag + newFlagName, oldFile,
+			flag + common.OutputFlagName, common.OutputJSON})
+		require.NoError(t, cmd.Execute())
+
+		d := diff{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &d))
+		require.False(t, d.Chained)
+		require.Equal(t, []*member{{Domain: "stakeholder.two", DID: "did:trustbloc:stakeholder.two"}}, d.Added)
+		require.Equal(t, []*member{{Domain: "stakeholder.three", DID: "did:trustbloc:stakeholder.three"}}, d.Removed)
+		require.Len(t, d.Changed, 1)
+		require.Equal(t, []string{"https://stakeholder.one/peer2"}, d.Changed[0].EndpointsAdded)
+	})
+
+	t.Run("test no changes", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetDiffConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + oldFlagName, oldFile, flag + newFlagName, oldFile})
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "no changes")
+	})
+
+	t.Run("test config not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetDiffConfigCmd()
+		cmd.SetArgs([]string{flag + oldFlagName, "wrong", flag + newFlagName, newFile})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read consortium config 'wrong'")
+	})
+}
+
+func writeVersion(t *testing.T, dir, previous string, numQueries int, stakeholders ...*stakeholder) string {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0700))
+
+	consortium := models.Consortium{Domain: "consortium.net", Previous: previous,
+		Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 2419200}, NumQueries: numQueries}}
+
+	for _, s := range stakeholders {
+		jwk, err := (&jose.JSONWebKey{Key: s.key.Public()}).MarshalJSON()
+		require.NoError(t, err)
+
+		consortium.Members = append(consortium.Members, &models.StakeholderListElement{Domain: s.domain,
+			DID: "did:trustbloc:" + s.domain, PublicKey: models.PublicKey{ID: "did:trustbloc:" + s.domain + "#key1",
+				JWK: jwk}})
+
+		writeConfig(t, filepath.Join(dir, s.domain+".json"), &models.Stakeholder{Domain: s.domain,
+			DID: "did:trustbloc:" + s.domain, Endpoints: s.endpoints}, s.key)
+	}
+
+	consortiumFile := filepath.Join(dir, "consortium.net.json")
+	writeConfig(t, consortiumFile, &consortium, stakeholders[0].key)
+
+	return consortiumFile
+}
+
+func writeConfig(t *testing.T, file string, config interface{}, key ed25519.PrivateKey) {
+	t.Helper()
+
+	configBytes, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	jws, err := configcommon.SignConfig(configBytes, []jose.SigningKey{{Key: key, Algorithm: jose.EdDSA}})
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(jws), 0600))
+}
+
+func generateKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
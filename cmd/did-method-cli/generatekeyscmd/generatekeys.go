@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generatekeyscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+const (
+	outDirFlagName  = "out-dir"
+	outDirEnvKey    = "DID_METHOD_CLI_OUT_DIR"
+	outDirFlagUsage = "Directory to write the generated keys and publickeys.json to." +
+		" Alternatively, this can be set with the following environment variable: " + outDirEnvKey
+
+	keyTypeFlagName  = "key-type"
+	keyTypeEnvKey    = "DID_METHOD_CLI_KEY_TYPE"
+	keyTypeFlagUsage = "Type of the generated keys." +
+		" Possible values [Ed25519] [P256]. Defaults to Ed25519 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyTypeEnvKey
+
+	verificationKeyIDFlagName  = "verification-key-id"
+	verificationKeyIDEnvKey    = "DID_METHOD_CLI_VERIFICATION_KEY_ID"
+	verificationKeyIDFlagUsage = "Comma-Separated list of IDs of the verification keys to generate." +
+		" Defaults to key1 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + verificationKeyIDEnvKey
+
+	purposeFlagName  = "purpose"
+	purposeEnvKey    = "DID_METHOD_CLI_PURPOSE"
+	purposeFlagUsage = "Comma-Separated list of purposes of the verification keys." +
+		" Defaults to authentication if not set." +
+		" Alternatively, this can be set with the following environment variable: " + purposeEnvKey
+
+	keyPasswordFlagName  = "key-password"
+	keyPasswordEnvKey    = "DID_METHOD_CLI_KEY_PASSWORD" //nolint: gosec
+	keyPasswordFlagUsage = "Password used to encrypt the generated private key PEMs. Not encrypted if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyPasswordEnvKey
+
+	defaultVerificationKeyID = "key1"
+
+	publicKeysFileName = "publickeys.json"
+
+	recoveryKeyDir = "recover"
+	updateKeyDir   = "update"
+
+	dirPermissions       = 0700
+	publicKeyPermissions = 0644
+)
+
+type parameters struct {
+	outDir             string
+	keyType            string
+	verificationKeyIDs []string
+	purposes           []string
+	password           []byte
+}
+
+// GetGenerateKeysCmd returns the Cobra generate keys command.
+func GetGenerateKeysCmd() *cobra.Command {
+	generateKeysCmd := generateKeysCmd()
+
+	createFlags(generateKeysCmd)
+
+	return generateKeysCmd
+}
+
+func generateKeysCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "generate-keys",
+		Short: "Generate keys for a TrustBloc DID",
+		Long: "Generate the recovery, update and verification keys for a TrustBloc DID, and a publickeys.json" +
+			" that can be used with create-did",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.ApplyConfig(cmd); err != nil {
+				return err
+			}
+
+			params, err := getParameters(cmd)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(params.outDir, dirPermissions); err != nil {
+				return fmt.Errorf("failed to create out dir: %w", err)
+			}
+
+			for _, dir := range []string{recoveryKeyDir, updateKeyDir} {
+				_, err := common.GeneratePEMKeyPair(cmd.OutOrStdout(), filepath.Join(params.outDir, dir), params.keyType,
+					params.password)
+				if err != nil {
+					return err
+				}
+			}
+
+			return generateVerificationKeys(cmd.OutOrStdout(), params)
+		},
+	}
+}
+
+func getParameters(cmd *cobra.Command) (*parameters, error) {
+	outDir, err := cmdutils.GetUserSetVarFromString(cmd, outDirFlagName, outDirEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &parameters{
+		outDir:  outDir,
+		keyType: cmdutils.GetUserSetOptionalVarFromString(cmd, keyTypeFlagName, keyTypeEnvKey),
+		verificationKeyIDs: cmdutils.GetUserSetOptionalVarFromArrayString(cmd, verificationKeyIDFlagName,
+			verificationKeyIDEnvKey),
+		purposes: cmdutils.GetUserSetOptionalVarFromArrayString(cmd, purposeFlagName, purposeEnvKey),
+		password: []byte(cmdutils.GetUserSetOptionalVarFromString(cmd, keyPasswordFlagName, keyPasswordEnvKey)),
+	}
+
+	if params.keyType == "" {
+		params.keyType = doc.Ed25519KeyType
+	}
+
+	if len(params.verificationKeyIDs) == 0 {
+		params.verificationKeyIDs = []string{defaultVerificationKeyID}
+	}
+
+	if len(params.purposes) == 0 {
+		params.purposes = []string{doc.KeyPurposeAuthentication}
+	}
+
+	return params, nil
+}
+
+func generateVerificationKeys(out io.Writer, params *parameters) error {
+	publicKeys := make([]common.PublicKey, 0, len(params.verificationKeyIDs))
+
+	for _, id := range params.verificationKeyIDs {
+		publicKey, err := common.GenerateVerificationKey(out, params.outDir, id, params.keyType, params.password)
+		if err != nil {
+			return err
+		}
+
+		publicKey.Purposes = params.purposes
+		publicKeys = append(publicKeys, *publicKey)
+	}
+
+	publicKeysBytes, err := json.MarshalIndent(publicKeys, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal public keys: %w", err)
+	}
+
+	return common.WriteKeyFile(out, filepath.Join(params.outDir, publicKeysFileName), publicKeysBytes,
+		publicKeyPermissions)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(outDirFlagName, "", "", outDirFlagUsage)
+	startCmd.Flags().StringP(keyTypeFlagName, "", "", keyTypeFlagUsage)
+	startCmd.Flags().StringArrayP(verificationKeyIDFlagName, "", []string{}, verificationKeyIDFlagUsage)
+	startCmd.Flags().StringArrayP(purposeFlagName, "", []string{}, purposeFlagUsage)
+	startCmd.Flags().StringP(keyPasswordFlagName, "", "", keyPasswordFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
+}
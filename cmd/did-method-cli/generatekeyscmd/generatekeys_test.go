@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package generatekeyscmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test out dir is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetGenerateKeysCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither out-dir (command line flag) nor "+
+			"DID_METHOD_CLI_OUT_DIR (environment variable) have been set.")
+	})
+}
+
+func TestGenerateKeys(t *testing.T) {
+	t.Run("success with defaults", func(t *testing.T) {
+		os.Clearenv()
+
+		dir := tempDir(t)
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetGenerateKeysCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(outDirArg(dir))
+
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), filepath.Join(dir, publicKeysFileName))
+
+		for _, keyDir := range []string{recoveryKeyDir, updateKeyDir} {
+			publicKey, err := common.PublicKeyFromFile(filepath.Join(dir, keyDir, common.PublicKeyFileName))
+			require.NoError(t, err)
+			require.IsType(t, ed25519.PublicKey{}, publicKey)
+
+			_, err = common.PrivateKeyFromFile(filepath.Join(dir, keyDir, common.PrivateKeyFileName), nil)
+			require.NoError(t, err)
+		}
+
+		publicKeys, err := common.GetPublicKeysFromFile(filepath.Join(dir, publicKeysFileName))
+		require.NoError(t, err)
+		require.Len(t, publicKeys, 1)
+		require.Equal(t, defaultVerificationKeyID, publicKeys[0].ID)
+		require.Equal(t, common.Ed25519VerificationKey2018, publicKeys[0].Type)
+		require.Equal(t, []string{doc.KeyPurposeAuthentication}, publicKeys[0].Purposes)
+	})
+
+	t.Run("success with P256 encrypted keys", func(t *testing.T) {
+		os.Clearenv()
+
+		dir := tempDir(t)
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetGenerateKeysCmd()
+		cmd.SetOut(&bytes.Buffer{})
+
+		var args []string
+		args = append(args, outDirArg(dir)...)
+		args = append(args, flag+keyTypeFlagName, "p256")
+		args = append(args, flag+verificationKeyIDFlagName, "key1")
+		args = append(args, flag+verificationKeyIDFlagName, "key2")
+		args = append(args, flag+purposeFlagName, doc.KeyPurposeAssertionMethod)
+		args = append(args, flag+keyPasswordFlagName, "123")
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		privateKey, err := common.PrivateKeyFromFile(filepath.Join(dir, updateKeyDir, common.PrivateKeyFileName),
+			[]byte("123"))
+		require.NoError(t, err)
+		require.IsType(t, &ecdsa.PrivateKey{}, privateKey)
+
+		_, err = common.PrivateKeyFromFile(filepath.Join(dir, "key2_private.pem"), []byte("123"))
+		require.NoError(t, err)
+
+		publicKeys, err := common.GetPublicKeysFromFile(filepath.Join(dir, publicKeysFileName))
+		require.NoError(t, err)
+		require.Len(t, publicKeys, 2)
+		require.Equal(t, doc.JWSVerificationKey2020, publicKeys[1].Type)
+		require.Equal(t, []string{doc.KeyPurposeAssertionMethod}, publicKeys[1].Purposes)
+	})
+
+	t.Run("test unsupported key type", func(t *testing.T) {
+		os.Clearenv()
+
+		dir := tempDir(t)
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetGenerateKeysCmd()
+		cmd.SetArgs(append(outDirArg(dir), flag+keyTypeFlagName, "rsa"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported: rsa")
+	})
+
+	t.Run("test existing keys are not overwritten", func(t *testing.T) {
+		os.Clearenv()
+
+		dir := tempDir(t)
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetGenerateKeysCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs(outDirArg(dir))
+		require.NoError(t, cmd.Execute())
+
+		cmd = GetGenerateKeysCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs(outDirArg(dir))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists")
+	})
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "keys")
+	require.NoError(t, err)
+
+	return dir
+}
+
+func outDirArg(value string) []string {
+	return []string{flag + outDirFlagName, value}
+}
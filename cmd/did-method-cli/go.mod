@@ -9,14 +9,20 @@ replace github.com/trustbloc/trustbloc-did-method => ../..
 replace github.com/kilic/bls12-381 => github.com/trustbloc/bls12-381 v0.0.0-20201104214312-31de2a204df8
 
 require (
+	github.com/ThalesIgnite/crypto11 v1.2.3
 	github.com/btcsuite/btcutil v1.0.1
-	github.com/hyperledger/aries-framework-go v0.1.5-0.20201110161050-249e1c428734
+	github.com/hyperledger/aries-framework-go v0.1.7-0.20210603210127-e57b8c94e3cf
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/cobra v1.0.0
+	github.com/spf13/pflag v1.0.3
 	github.com/square/go-jose/v3 v3.0.0-20200630053402-0a67ce9b0693
-	github.com/stretchr/testify v1.6.1
+	github.com/stretchr/testify v1.7.0
 	github.com/trustbloc/edge-core v0.1.5-0.20201106164919-76ecfeca954f
 	github.com/trustbloc/sidetree-core-go v0.1.5-0.20201203160228-bad37e299892
 	github.com/trustbloc/trustbloc-did-method v0.0.0
+	golang.org/x/crypto v0.0.0-20201002170205-7f63de1d35b0
+	gopkg.in/yaml.v2 v2.3.0
 )
 
 go 1.15
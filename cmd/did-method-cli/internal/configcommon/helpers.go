@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	gojose "github.com/square/go-jose/v3"
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	"gopkg.in/yaml.v2"
 
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 )
@@ -24,7 +25,7 @@ import (
 const (
 	ConfigFileFlagName  = "config-file"
 	ConfigFileEnvKey    = "DID_METHOD_CLI_CONFIG_FILE"
-	ConfigFileFlagUsage = "Config file include data required for creating well known config files " +
+	ConfigFileFlagUsage = "YAML or JSON config file include data required for creating well known config files " +
 		" Alternatively, this can be set with the following environment variable: " + ConfigFileEnvKey
 )
 
@@ -81,6 +82,31 @@ func WriteConfig(outputDirectory string, filesData map[string][]byte) error {
 	return nil
 }
 
+// WriteWellKnown writes a .well-known file tree for each domain to the given directory, ready to be hosted at the
+// root of the domain's web server: every config file goes to <domain>/.well-known/did-trustbloc/, and the DID
+// configuration of the domain, if any, to <domain>/.well-known/did-configuration.json
+func WriteWellKnown(outputDirectory string, filesData, didConfData map[string][]byte) error {
+	for domain := range filesData {
+		wellKnownDirectory := path.Join(outputDirectory, domain, ".well-known")
+
+		if err := WriteConfig(wellKnownDirectory, filesData); err != nil {
+			return err
+		}
+
+		didConf, ok := didConfData[domain]
+		if !ok {
+			continue
+		}
+
+		err := ioutil.WriteFile(path.Join(wellKnownDirectory, "did-configuration.json"), didConf, 0644) //nolint: gosec
+		if err != nil {
+			return fmt.Errorf("failed to write file %w", err)
+		}
+	}
+
+	return nil
+}
+
 // GetConfig gets a config file for generating/updating a consortium's configs
 func GetConfig(cmd *cobra.Command) (*Config, error) {
 	configFile, err := cmdutils.GetUserSetVarFromString(cmd, ConfigFileFlagName,
@@ -94,6 +120,13 @@ func GetConfig(cmd *cobra.Command) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file '%s' : %w", configFile, err)
 	}
 
+	if !json.Valid(data) {
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed unmarshal to config %w", err)
+		}
+	}
+
 	var conf Config
 
 	if err := json.Unmarshal(data, &conf); err != nil {
@@ -116,6 +149,38 @@ func GetConfig(cmd *cobra.Command) (*Config, error) {
 	return &conf, nil
 }
 
+// yamlToJSON converts a YAML config file to JSON, so that the JSON field names of the config apply to both formats
+func yamlToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonValue(value))
+}
+
+// jsonValue converts the maps of a YAML value, which may have keys of any type, to maps with string keys
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+
+		for key, val := range v {
+			m[fmt.Sprint(key)] = jsonValue(val)
+		}
+
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = jsonValue(val)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
+
 // SignConfig sign a config file
 func SignConfig(configBytes []byte, keys []gojose.SigningKey) (string, error) {
 	signer, err := gojose.NewMultiSigner(keys, nil)
@@ -42,6 +42,22 @@ const (
   ]
 }`
 
+	configYAMLData = `consortiumData:
+  domain: consortium.net
+  policy:
+    cache:
+      maxAge: 2419200
+    numQueries: 2
+membersData:
+  - domain: stakeholder.one
+    policy:
+      cache:
+        maxAge: 604800
+    endpoints:
+      - http://endpoints.stakeholder.one/peer1/
+    privateKeyJwkPath: %s
+`
+
 	jwkData = `{
 	"kty": "OKP",
 	"kid": "key1",
@@ -196,6 +212,41 @@ func TestGetConfig(t *testing.T) {
 		require.NotNil(t, member)
 		require.Equal(t, "stakeholder.one", member.Domain)
 	})
+	t.Run("test get config from yaml file", func(t *testing.T) {
+		os.Clearenv()
+
+		jwkFile, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(jwkFile.Name())) }()
+
+		_, err = jwkFile.WriteString(jwkData)
+		require.NoError(t, err)
+
+		file, err := ioutil.TempFile("", "*.yaml")
+		require.NoError(t, err)
+
+		_, err = file.WriteString(fmt.Sprintf(configYAMLData, jwkFile.Name()))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(file.Name())) }()
+
+		require.NoError(t, os.Setenv(ConfigFileEnvKey, file.Name()))
+
+		c, err := GetConfig(&cobra.Command{})
+		require.NoError(t, err)
+
+		require.Equal(t, "consortium.net", c.ConsortiumData.Domain)
+		require.Equal(t, 2, c.ConsortiumData.Policy.NumQueries)
+		require.Equal(t, uint32(2419200), c.ConsortiumData.Policy.Cache.MaxAge)
+		require.Len(t, c.MembersData, 1)
+
+		member := c.MembersData[0]
+		require.Equal(t, "stakeholder.one", member.Domain)
+		require.Equal(t, uint32(604800), member.Policy.Cache.MaxAge)
+		require.Equal(t, []string{"http://endpoints.stakeholder.one/peer1/"}, member.Endpoints)
+		require.Equal(t, "key1", member.JSONWebKey.KeyID)
+	})
 }
 
 func getKey(t *testing.T, data string) jose.JSONWebKey {
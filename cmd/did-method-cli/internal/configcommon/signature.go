@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configcommon
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	gojose "github.com/square/go-jose/v3"
+)
+
+const p256CoordinateSize = 32
+
+// rawJWS is the JSON serialization of a JWS, either general (signatures) or flattened (a single signature)
+type rawJWS struct {
+	Payload    string          `json:"payload"`
+	Protected  string          `json:"protected,omitempty"`
+	Header     json.RawMessage `json:"header,omitempty"`
+	Signature  string          `json:"signature,omitempty"`
+	Signatures []rawSignature  `json:"signatures,omitempty"`
+}
+
+type rawSignature struct {
+	Protected string          `json:"protected,omitempty"`
+	Header    json.RawMessage `json:"header,omitempty"`
+	Signature string          `json:"signature"`
+}
+
+// SigningKey returns the go-jose signing key of a private key (Ed25519 or P-256), or of a crypto.Signer holding
+// one, such as a PKCS#11 key
+func SigningKey(privateKey interface{}) (gojose.SigningKey, error) {
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		return gojose.SigningKey{Key: key, Algorithm: gojose.EdDSA}, nil
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return gojose.SigningKey{}, fmt.Errorf("curve %s not supported", key.Curve.Params().Name)
+		}
+
+		return gojose.SigningKey{Key: key, Algorithm: gojose.ES256}, nil
+	case crypto.Signer:
+		signer, err := newOpaqueSigner(key)
+		if err != nil {
+			return gojose.SigningKey{}, err
+		}
+
+		return gojose.SigningKey{Key: signer, Algorithm: signer.alg}, nil
+	default:
+		return gojose.SigningKey{}, errors.New("key not supported")
+	}
+}
+
+// AddSignature signs the payload of a config JWS with the key, and returns the config JWS with the new signature
+// added to its existing signatures. A config that isn't a JWS yet is signed as the payload.
+func AddSignature(config []byte, key gojose.SigningKey) (string, error) {
+	payload, signatures, err := parseConfig(config, key)
+	if err != nil {
+		return "", err
+	}
+
+	signed, err := SignConfig(payload, []gojose.SigningKey{key})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign config: %w", err)
+	}
+
+	added := rawJWS{}
+	if err := json.Unmarshal([]byte(signed), &added); err != nil {
+		return "", fmt.Errorf("failed to parse signed config: %w", err)
+	}
+
+	result := rawJWS{Payload: added.Payload, Signatures: append(signatures, added.signatures()...)}
+	if len(result.Signatures) == 1 {
+		result = rawJWS{Payload: added.Payload, Protected: added.Protected, Header: added.Header,
+			Signature: added.Signature}
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config jws: %w", err)
+	}
+
+	return string(resultBytes), nil
+}
+
+// parseConfig returns the payload and the signatures of a config JWS, or the config itself as the payload if it
+// isn't a JWS yet
+func parseConfig(config []byte, key gojose.SigningKey) ([]byte, []rawSignature, error) {
+	jws, err := gojose.ParseSigned(string(config))
+	if err != nil {
+		if !json.Valid(config) {
+			return nil, nil, errors.New("config is neither a jws nor json")
+		}
+
+		return config, nil, nil
+	}
+
+	if _, _, _, err := jws.VerifyMulti(signingPublicKey(key)); err == nil {
+		return nil, nil, errors.New("config is already signed with this key")
+	}
+
+	existing := rawJWS{}
+	if err := json.Unmarshal([]byte(jws.FullSerialize()), &existing); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config jws: %w", err)
+	}
+
+	return jws.UnsafePayloadWithoutVerification(), existing.signatures(), nil
+}
+
+func (j *rawJWS) signatures() []rawSignature {
+	if j.Signature != "" {
+		return []rawSignature{{Protected: j.Protected, Header: j.Header, Signature: j.Signature}}
+	}
+
+	return j.Signatures
+}
+
+func signingPublicKey(key gojose.SigningKey) interface{} {
+	switch k := key.Key.(type) {
+	case gojose.OpaqueSigner:
+		return k.Public()
+	case crypto.Signer:
+		return k.Public()
+	default:
+		return nil
+	}
+}
+
+// opaqueSigner signs JWS payloads with a crypto.Signer whose private key isn't accessible, such as a PKCS#11 key
+type opaqueSigner struct {
+	signer crypto.Signer
+	alg    gojose.SignatureAlgorithm
+}
+
+func newOpaqueSigner(signer crypto.Signer) (*opaqueSigner, error) {
+	switch key := signer.Public().(type) {
+	case ed25519.PublicKey:
+		return &opaqueSigner{signer: signer, alg: gojose.EdDSA}, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("curve %s not supported", key.Curve.Params().Name)
+		}
+
+		return &opaqueSigner{signer: signer, alg: gojose.ES256}, nil
+	default:
+		return nil, errors.New("key not supported")
+	}
+}
+
+func (s *opaqueSigner) Public() *gojose.JSONWebKey {
+	return &gojose.JSONWebKey{Key: s.signer.Public(), Algorithm: string(s.alg)}
+}
+
+func (s *opaqueSigner) Algs() []gojose.SignatureAlgorithm {
+	return []gojose.SignatureAlgorithm{s.alg}
+}
+
+func (s *opaqueSigner) SignPayload(payload []byte, alg gojose.SignatureAlgorithm) ([]byte, error) {
+	if alg != s.alg {
+		return nil, fmt.Errorf("algorithm %s not supported", alg)
+	}
+
+	if alg == gojose.EdDSA {
+		return s.signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+
+	digest := sha256.Sum256(payload)
+
+	der, err := s.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	// crypto.Signer returns an ASN.1 ECDSA signature, while JWS uses the concatenation of r and s
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("failed to parse ecdsa signature: %w", err)
+	}
+
+	signature := make([]byte, 2*p256CoordinateSize)
+	sig.R.FillBytes(signature[:p256CoordinateSize])
+	sig.S.FillBytes(signature[p256CoordinateSize:])
+
+	return signature, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configcommon
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSignature(t *testing.T) {
+	payload := []byte(`{"domain":"consortium.net"}`)
+
+	pub1, priv1, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	priv2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	key1, err := SigningKey(priv1)
+	require.NoError(t, err)
+
+	key2, err := SigningKey(priv2)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		signed, err := AddSignature(payload, key1)
+		require.NoError(t, err)
+
+		signed, err = AddSignature([]byte(signed), key2)
+		require.NoError(t, err)
+
+		jws, err := jose.ParseSigned(signed)
+		require.NoError(t, err)
+		require.Len(t, jws.Signatures, 2)
+
+		for _, key := range []interface{}{pub1, &priv2.PublicKey} {
+			_, _, out, err := jws.VerifyMulti(key)
+			require.NoError(t, err)
+			require.Equal(t, payload, out)
+		}
+	})
+
+	t.Run("success with a crypto.Signer", func(t *testing.T) {
+		for _, signer := range []crypto.Signer{priv1, priv2} {
+			key, err := SigningKey(&testSigner{signer})
+			require.NoError(t, err)
+
+			signed, err := AddSignature(payload, key)
+			require.NoError(t, err)
+
+			jws, err := jose.ParseSigned(signed)
+			require.NoError(t, err)
+
+			_, err = jws.Verify(signer.Public())
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("test already signed with the key", func(t *testing.T) {
+		signed, err := AddSignature(payload, key1)
+		require.NoError(t, err)
+
+		_, err = AddSignature([]byte(signed), key1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "config is already signed with this key")
+	})
+
+	t.Run("test invalid config", func(t *testing.T) {
+		_, err := AddSignature([]byte("not a config"), key1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "config is neither a jws nor json")
+	})
+
+	t.Run("test key not supported", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = SigningKey(priv)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "curve P-384 not supported")
+
+		_, err = SigningKey("key")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key not supported")
+	})
+}
+
+// testSigner hides the private key type of a crypto.Signer, as a PKCS#11 signer does
+type testSigner struct {
+	signer crypto.Signer
+}
+
+func (s *testSigner) Public() crypto.PublicKey {
+	return s.signer.Public()
+}
+
+func (s *testSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.signer.Sign(r, digest, opts)
+}
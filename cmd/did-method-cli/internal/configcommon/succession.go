@@ -0,0 +1,222 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configcommon
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	gojose "github.com/square/go-jose/v3"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+// Flags of the CLI commands signing configs, and of the commands deriving a new version of a consortium config
+const (
+	ConsortiumFileFlagName  = "consortium-file"
+	ConsortiumFileEnvKey    = "DID_METHOD_CLI_CONSORTIUM_FILE"
+	ConsortiumFileFlagUsage = "The current consortium config JWS, that the new version of the config is chained to." +
+		" Alternatively, this can be set with the following environment variable: " + ConsortiumFileEnvKey
+
+	OutputDirectoryFlagName  = "output-directory"
+	OutputDirectoryEnvKey    = "DID_METHOD_CLI_OUTPUT_DIRECTORY"
+	OutputDirectoryFlagUsage = "Directory to write the config files to publish, and the history of the current" +
+		" consortium config to." +
+		" Alternatively, this can be set with the following environment variable: " + OutputDirectoryEnvKey
+
+	SigningKeyFlagName  = "signingkey"
+	SigningKeyEnvKey    = "DID_METHOD_CLI_SIGNINGKEY"
+	SigningKeyFlagUsage = "The private key PEM of the stakeholder used for signing the config." +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyEnvKey
+
+	SigningKeyFileFlagName  = "signingkey-file"
+	SigningKeyFileEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_FILE"
+	SigningKeyFileFlagUsage = "The file that contains the private key PEM of the stakeholder" +
+		" used for signing the config." +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyFileEnvKey
+
+	SigningKeyPasswordFlagName  = "signingkey-password"
+	SigningKeyPasswordEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_PASSWORD" //nolint: gosec
+	SigningKeyPasswordFlagUsage = "signing key pem password. " +
+		" Alternatively, this can be set with the following environment variable: " + SigningKeyPasswordEnvKey
+)
+
+// AddSigningKeyFlags adds the flags setting the key a stakeholder signs a config with
+func AddSigningKeyFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(SigningKeyFlagName, "", "", SigningKeyFlagUsage)
+	startCmd.Flags().StringP(SigningKeyFileFlagName, "", "", SigningKeyFileFlagUsage)
+	startCmd.Flags().StringP(SigningKeyPasswordFlagName, "", "", SigningKeyPasswordFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+}
+
+// GetSigningKey returns the key set with the signing key flags, that a stakeholder signs a config with
+func GetSigningKey(cmd *cobra.Command) (gojose.SigningKey, error) {
+	privateKey, err := common.GetSigningKey(cmd, SigningKeyFlagName, SigningKeyEnvKey, SigningKeyFileFlagName,
+		SigningKeyFileEnvKey, SigningKeyPasswordFlagName, SigningKeyPasswordEnvKey)
+	if err != nil {
+		return gojose.SigningKey{}, err
+	}
+
+	signingKey, err := SigningKey(privateKey)
+	if err != nil {
+		return gojose.SigningKey{}, fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	return signingKey, nil
+}
+
+// AddSuccessionFlags adds the flags of the commands deriving a new version of a consortium config
+func AddSuccessionFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(ConsortiumFileFlagName, "", "", ConsortiumFileFlagUsage)
+	startCmd.Flags().StringP(OutputDirectoryFlagName, "", "", OutputDirectoryFlagUsage)
+	AddSigningKeyFlags(startCmd)
+}
+
+// ReadConsortium reads the current consortium config JWS set with the consortium file flag, and returns it
+// together with the raw file
+func ReadConsortium(cmd *cobra.Command) (*models.ConsortiumFileData, []byte, error) {
+	consortiumFile, err := cmdutils.GetUserSetVarFromString(cmd, ConsortiumFileFlagName, ConsortiumFileEnvKey, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fileBytes, err := ioutil.ReadFile(filepath.Clean(consortiumFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read consortium file '%s' : %w", consortiumFile, err)
+	}
+
+	consortium, err := models.ParseConsortium(fileBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse consortium file '%s' : %w", consortiumFile, err)
+	}
+
+	return consortium, fileBytes, nil
+}
+
+// ConfigHash returns the hash a new version of a config refers to its previous version with
+func ConfigHash(fileBytes []byte) string {
+	sum := sha256.Sum256(fileBytes)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// WriteSuccessor chains the next version of the consortium config to the current one, signs it with the key set with
+// the signing key flags, then writes it, the other files and a copy of the current config in the history directory
+// to the output directory. It then reports whether the next version is endorsed by enough members of the current
+// one to succeed it.
+func WriteSuccessor(cmd *cobra.Command, current *models.ConsortiumFileData, currentBytes []byte,
+	next *models.Consortium, filesData map[string][]byte) error {
+	signingKey, err := GetSigningKey(cmd)
+	if err != nil {
+		return err
+	}
+
+	hash := ConfigHash(currentBytes)
+	next.Previous = hash
+
+	nextBytes, err := json.Marshal(next)
+	if err != nil {
+		return fmt.Errorf("failed to marshal consortium config: %w", err)
+	}
+
+	jws, err := AddSignature(nextBytes, signingKey)
+	if err != nil {
+		return err
+	}
+
+	filesData[next.Domain] = []byte(jws)
+
+	outputDirectory := cmdutils.GetUserSetOptionalVarFromString(cmd, OutputDirectoryFlagName, OutputDirectoryEnvKey)
+
+	if err := WriteConfig(outputDirectory, filesData); err != nil {
+		return err
+	}
+
+	if err := writeHistory(path.Join(outputDirectory, "history"), hash, currentBytes); err != nil {
+		return err
+	}
+
+	nextData, err := models.ParseConsortium([]byte(jws))
+	if err != nil {
+		return err
+	}
+
+	ReportSuccession(cmd, nextData, current.Config)
+
+	return nil
+}
+
+func writeHistory(historyDirectory, hash string, fileBytes []byte) error {
+	if err := os.MkdirAll(historyDirectory, 0755); err != nil { //nolint: gosec
+		return err
+	}
+
+	err := ioutil.WriteFile(path.Join(historyDirectory, hash+".json"), fileBytes, 0644) //nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to write file %w", err)
+	}
+
+	return nil
+}
+
+// Endorsements returns the domains of the members of the consortium whose key verifies a signature of the config
+func Endorsements(jws *gojose.JSONWebSignature, consortium *models.Consortium) []string {
+	var domains []string
+
+	for _, member := range consortium.Members {
+		key := gojose.JSONWebKey{}
+
+		if err := key.UnmarshalJSON(member.PublicKey.JWK); err != nil {
+			continue
+		}
+
+		if _, _, _, err := jws.VerifyMulti(key); err == nil {
+			domains = append(domains, member.Domain)
+		}
+	}
+
+	return domains
+}
+
+// ReportSuccession reports whether the next version of a consortium config is endorsed by enough members of the
+// current consortium config, as required by its policy, for clients to accept the next version
+func ReportSuccession(cmd *cobra.Command, next *models.ConsortiumFileData, current *models.Consortium) {
+	endorsements := Endorsements(next.JWS, current)
+	required := current.NumQueries()
+
+	endorsedBy := "none of the current members"
+	if len(endorsements) > 0 {
+		endorsedBy = strings.Join(endorsements, ", ")
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "consortium config %s endorsed by %s (%d of the %d endorsements required)\n",
+		next.Config.Domain, endorsedBy, len(endorsements), required)
+
+	if len(endorsements) < required {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d more members of the current consortium have to sign the config with "+
+			"sign-config before it can be published\n", required-len(endorsements))
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configcommon
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func TestConfigHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("config"))
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), ConfigHash([]byte("config")))
+}
+
+func TestEndorsements(t *testing.T) {
+	consortium := &models.Consortium{Domain: "consortium.net"}
+
+	var keys []jose.SigningKey
+
+	for _, domain := range []string{"stakeholder.one", "stakeholder.two"} {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		jwk, err := (&jose.JSONWebKey{Key: pub}).MarshalJSON()
+		require.NoError(t, err)
+
+		consortium.Members = append(consortium.Members, &models.StakeholderListElement{Domain: domain,
+			PublicKey: models.PublicKey{JWK: jwk}})
+
+		keys = append(keys, jose.SigningKey{Key: priv, Algorithm: jose.EdDSA})
+	}
+
+	consortium.Members = append(consortium.Members, &models.StakeholderListElement{Domain: "stakeholder.bad",
+		PublicKey: models.PublicKey{JWK: []byte("not a jwk")}})
+
+	t.Run("test endorsed by a member", func(t *testing.T) {
+		signed, err := SignConfig([]byte(`{"domain":"consortium.net"}`), keys[1:])
+		require.NoError(t, err)
+
+		jws, err := jose.ParseSigned(signed)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"stakeholder.two"}, Endorsements(jws, consortium))
+	})
+
+	t.Run("test endorsed by all members", func(t *testing.T) {
+		signed, err := SignConfig([]byte(`{"domain":"consortium.net"}`), keys)
+		require.NoError(t, err)
+
+		jws, err := jose.ParseSigned(signed)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"stakeholder.one", "stakeholder.two"}, Endorsements(jws, consortium))
+	})
+}
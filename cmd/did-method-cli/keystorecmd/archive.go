@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystorecmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+func backupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the keys of a DID to an encrypted archive",
+		Long: "Write the keys of an alias, with the commitment history of its DID, to an archive encrypted with the" +
+			" archive password, to migrate the DID to another machine or to restore it from the backup",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archiveFile, err := cmdutils.GetUserSetVarFromString(cmd, archiveFileFlagName, archiveFileEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			password, err := cmdutils.GetUserSetVarFromString(cmd, archivePasswordFlagName, archivePasswordEnvKey,
+				false)
+			if err != nil {
+				return err
+			}
+
+			_, keySet, err := common.GetKeySet(cmd)
+			if err != nil {
+				return err
+			}
+
+			if keySet == nil {
+				return errors.New("key alias is required")
+			}
+
+			commitments, err := getCommitments(cmd, keySet.DID)
+			if err != nil {
+				return err
+			}
+
+			data, err := keystore.ExportArchive(keystore.NewArchive(keySet, commitments), []byte(password))
+			if err != nil {
+				return err
+			}
+
+			if err := ioutil.WriteFile(archiveFile, data, archivePermissions); err != nil {
+				return fmt.Errorf("failed to write archive '%s': %w", archiveFile, err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "backed up alias %s to %s\n", keySet.Alias, archiveFile)
+
+			return nil
+		},
+	}
+
+	common.AddKeyAliasFlags(cmd)
+	addArchiveFlags(cmd)
+
+	return cmd
+}
+
+func restoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the keys of a DID from an encrypted archive",
+		Long: "Add the keys of an archive written by backup to the keystore, under the archived alias unless" +
+			" --key-alias is set, and the commitment history of the DID to the commitment store if set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archive, err := readArchive(cmd)
+			if err != nil {
+				return err
+			}
+
+			ks, err := common.GetKeystore(cmd)
+			if err != nil {
+				return err
+			}
+
+			alias := cmdutils.GetUserSetOptionalVarFromString(cmd, common.KeyAliasFlagName, common.KeyAliasEnvKey)
+
+			keySet, err := ks.Restore(archive, alias)
+			if err != nil {
+				return err
+			}
+
+			if err := putCommitments(cmd, archive.Commitments); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "restored alias %s\n", keySet.Alias)
+
+			return nil
+		},
+	}
+
+	common.AddKeyAliasFlags(cmd)
+	addArchiveFlags(cmd)
+
+	return cmd
+}
+
+func addArchiveFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(archiveFileFlagName, "", "", archiveFileFlagUsage)
+	cmd.Flags().StringP(archivePasswordFlagName, "", "", archivePasswordFlagUsage)
+	cmd.Flags().StringP(commitmentStoreFlagName, "", "", commitmentStoreFlagUsage)
+}
+
+func readArchive(cmd *cobra.Command) (*keystore.Archive, error) {
+	archiveFile, err := cmdutils.GetUserSetVarFromString(cmd, archiveFileFlagName, archiveFileEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := cmdutils.GetUserSetVarFromString(cmd, archivePasswordFlagName, archivePasswordEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(archiveFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive '%s': %w", archiveFile, err)
+	}
+
+	return keystore.ImportArchive(data, []byte(password))
+}
+
+// getCommitments returns the commitments of the DID from the commitment store, or nil if the commitment store
+// isn't set or has none
+func getCommitments(cmd *cobra.Command, did string) (*commitmentstore.Commitments, error) {
+	path := cmdutils.GetUserSetOptionalVarFromString(cmd, commitmentStoreFlagName, commitmentStoreEnvKey)
+	if path == "" || did == "" {
+		return nil, nil
+	}
+
+	commitments, err := commitmentstore.NewFileStore(path).Get(did)
+	if errors.Is(err, commitmentstore.ErrNotFound) {
+		return nil, nil
+	}
+
+	return commitments, err
+}
+
+func putCommitments(cmd *cobra.Command, commitments *commitmentstore.Commitments) error {
+	path := cmdutils.GetUserSetOptionalVarFromString(cmd, commitmentStoreFlagName, commitmentStoreEnvKey)
+	if path == "" || commitments == nil {
+		return nil
+	}
+
+	return commitmentstore.NewFileStore(path).Put(commitments)
+}
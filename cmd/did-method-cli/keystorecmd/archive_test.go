@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystorecmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+func TestBackupRestoreCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "keystore.json")
+	archivePath := filepath.Join(dir, "archive.json")
+	storePath := filepath.Join(dir, "commitments.json")
+
+	ks, err := keystore.Create(path, []byte("password"))
+	require.NoError(t, err)
+
+	_, privateKey, err := common.GenerateKeyPair("Ed25519")
+	require.NoError(t, err)
+
+	require.NoError(t, ks.AddKey("alias1", "did:ex:123", keystore.PurposeUpdate, privateKey))
+	require.NoError(t, ks.Save())
+
+	commitments := &commitmentstore.Commitments{DID: "did:ex:123", MultihashAlgorithm: 18,
+		UpdateCommitment: "c2", PreviousCommitments: []string{"c1"}}
+	require.NoError(t, commitmentstore.NewFileStore(storePath).Put(commitments))
+
+	t.Run("test backup and restore", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(archivePasswordEnvKey, "archive"))
+
+		out, err := execute(t, "backup", flag+common.KeystoreFlagName, path,
+			flag+common.KeystorePasswordFlagName, "password", flag+common.KeyAliasFlagName, "did:ex:123",
+			flag+archiveFileFlagName, archivePath, flag+commitmentStoreFlagName, storePath)
+		require.NoError(t, err)
+		require.Equal(t, "backed up alias alias1 to "+archivePath+"\n", out)
+
+		otherPath := filepath.Join(dir, "other.json")
+		otherStorePath := filepath.Join(dir, "other-commitments.json")
+
+		_, err = keystore.Create(otherPath, []byte("other"))
+		require.NoError(t, err)
+
+		out, err = execute(t, "restore", flag+common.KeystoreFlagName, otherPath,
+			flag+common.KeystorePasswordFlagName, "other", flag+common.KeyAliasFlagName, "restored",
+			flag+archiveFileFlagName, archivePath, flag+commitmentStoreFlagName, otherStorePath)
+		require.NoError(t, err)
+		require.Equal(t, "restored alias restored\n", out)
+
+		other, err := keystore.Open(otherPath, []byte("other"))
+		require.NoError(t, err)
+
+		key, err := other.KeySet("restored").PrivateKey(keystore.PurposeUpdate)
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+
+		restored, err := commitmentstore.NewFileStore(otherStorePath).Get("did:ex:123")
+		require.NoError(t, err)
+		require.Equal(t, commitments, restored)
+	})
+
+	t.Run("test backup missing alias", func(t *testing.T) {
+		os.Clearenv()
+
+		_, err := execute(t, "backup", flag+common.KeystoreFlagName, path,
+			flag+common.KeystorePasswordFlagName, "password", flag+archiveFileFlagName, archivePath,
+			flag+archivePasswordFlagName, "archive")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key alias is required")
+	})
+
+	t.Run("test restore wrong archive password", func(t *testing.T) {
+		os.Clearenv()
+
+		_, err := execute(t, "restore", flag+common.KeystoreFlagName, path,
+			flag+common.KeystorePasswordFlagName, "password", flag+archiveFileFlagName, archivePath,
+			flag+archivePasswordFlagName, "wrong")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the password may be wrong")
+	})
+
+	t.Run("test restore existing alias", func(t *testing.T) {
+		os.Clearenv()
+
+		_, err := execute(t, "restore", flag+common.KeystoreFlagName, path,
+			flag+common.KeystorePasswordFlagName, "password", flag+archiveFileFlagName, archivePath,
+			flag+archivePasswordFlagName, "archive")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key alias 'alias1' already exists in keystore")
+	})
+}
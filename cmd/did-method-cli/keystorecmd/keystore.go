@@ -0,0 +1,337 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystorecmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+const (
+	didURIFlagName  = "did-uri"
+	didURIEnvKey    = "DID_METHOD_CLI_DID_URI"
+	didURIFlagUsage = "DID URI the keys of the alias belong to. Set by create-did if not set." +
+		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
+
+	keyPurposeFlagName  = "key-purpose"
+	keyPurposeEnvKey    = "DID_METHOD_CLI_KEY_PURPOSE"
+	keyPurposeFlagUsage = "Purpose of the key." +
+		" Possible values [update] [recovery] [next-update] [next-recovery]." +
+		" Alternatively, this can be set with the following environment variable: " + keyPurposeEnvKey
+
+	keyFileFlagName  = "key-file"
+	keyFileEnvKey    = "DID_METHOD_CLI_KEY_FILE"
+	keyFileFlagUsage = "The file that contains the private key PEM to add. A new key is generated if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyFileEnvKey
+
+	keyPasswordFlagName  = "key-password"
+	keyPasswordEnvKey    = "DID_METHOD_CLI_KEY_PASSWORD" //nolint: gosec
+	keyPasswordFlagUsage = "Password of the private key PEM to add, or to encrypt the exported private key PEM with." +
+		" Alternatively, this can be set with the following environment variable: " + keyPasswordEnvKey
+
+	keyTypeFlagName  = "key-type"
+	keyTypeEnvKey    = "DID_METHOD_CLI_KEY_TYPE"
+	keyTypeFlagUsage = "Type of the generated key." +
+		" Possible values [Ed25519] [P256]. Defaults to Ed25519 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyTypeEnvKey
+
+	publicFlagName  = "public"
+	publicEnvKey    = "DID_METHOD_CLI_PUBLIC"
+	publicFlagUsage = "Export the public key instead of the private key." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + publicEnvKey
+
+	archiveFileFlagName  = "archive-file"
+	archiveFileEnvKey    = "DID_METHOD_CLI_ARCHIVE_FILE"
+	archiveFileFlagUsage = "The encrypted archive file of the keys of a DID, written by backup and read by restore." +
+		" Alternatively, this can be set with the following environment variable: " + archiveFileEnvKey
+
+	archivePasswordFlagName  = "archive-password"
+	archivePasswordEnvKey    = "DID_METHOD_CLI_ARCHIVE_PASSWORD" //nolint: gosec
+	archivePasswordFlagUsage = "The password the archive is encrypted with." +
+		" Alternatively, this can be set with the following environment variable: " + archivePasswordEnvKey
+
+	commitmentStoreFlagName  = "commitment-store"
+	commitmentStoreEnvKey    = "DID_METHOD_CLI_COMMITMENT_STORE"
+	commitmentStoreFlagUsage = "The commitment store file the commitment history of the DID is read from by backup," +
+		" and written to by restore. The commitment history isn't archived if not set." +
+		" Alternatively, this can be set with the following environment variable: " + commitmentStoreEnvKey
+
+	defaultKeyType     = "Ed25519"
+	archivePermissions = 0600
+)
+
+type keySetInfo struct {
+	Alias    string   `json:"alias" yaml:"alias"`
+	DID      string   `json:"did,omitempty" yaml:"did,omitempty"`
+	Purposes []string `json:"purposes" yaml:"purposes"`
+}
+
+// GetKeystoreCmd returns the Cobra keystore command.
+func GetKeystoreCmd() *cobra.Command {
+	keystoreCmd := &cobra.Command{
+		Use:   "keystore",
+		Short: "Manage the encrypted keystore",
+		Long: "Manage the encrypted local keystore of the update, recovery and next commitment keys of DIDs," +
+			" which other commands can reference with --key-alias",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	keystoreCmd.AddCommand(initCmd(), addCmd(), listCmd(), exportCmd(), backupCmd(), restoreCmd())
+
+	return keystoreCmd
+}
+
+func initCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create an empty keystore",
+		Long:  "Create an empty keystore file, encrypted with the keystore password",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := cmdutils.GetUserSetVarFromString(cmd, common.KeystoreFlagName, common.KeystoreEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			password, err := cmdutils.GetUserSetVarFromString(cmd, common.KeystorePasswordFlagName,
+				common.KeystorePasswordEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			if _, err := keystore.Create(path, []byte(password)); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "created keystore %s\n", path)
+
+			return nil
+		},
+	}
+
+	common.AddKeystoreFlags(cmd)
+
+	return cmd
+}
+
+func addCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a key to the keystore",
+		Long: "Add a private key, read from a PEM file or generated, to the key set of an alias with a purpose." +
+			" An existing key of the alias with the same purpose is replaced.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias, err := cmdutils.GetUserSetVarFromString(cmd, common.KeyAliasFlagName, common.KeyAliasEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			purpose, err := cmdutils.GetUserSetVarFromString(cmd, keyPurposeFlagName, keyPurposeEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			ks, err := common.GetKeystore(cmd)
+			if err != nil {
+				return err
+			}
+
+			privateKey, err := getPrivateKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			didURI := cmdutils.GetUserSetOptionalVarFromString(cmd, didURIFlagName, didURIEnvKey)
+
+			if err := ks.AddKey(alias, didURI, purpose, privateKey); err != nil {
+				return err
+			}
+
+			if err := ks.Save(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "added %s key to alias %s\n", purpose, alias)
+
+			return nil
+		},
+	}
+
+	common.AddKeyAliasFlags(cmd)
+	cmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
+	cmd.Flags().StringP(keyPurposeFlagName, "", "", keyPurposeFlagUsage)
+	cmd.Flags().StringP(keyFileFlagName, "", "", keyFileFlagUsage)
+	cmd.Flags().StringP(keyPasswordFlagName, "", "", keyPasswordFlagUsage)
+	cmd.Flags().StringP(keyTypeFlagName, "", "", keyTypeFlagUsage)
+
+	return cmd
+}
+
+func getPrivateKey(cmd *cobra.Command) (interface{}, error) {
+	keyFile := cmdutils.GetUserSetOptionalVarFromString(cmd, keyFileFlagName, keyFileEnvKey)
+	if keyFile != "" {
+		keyBytes, err := common.ReadKeyFile(cmd, keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return common.PrivateKeyFromPEM(keyBytes,
+			[]byte(cmdutils.GetUserSetOptionalVarFromString(cmd, keyPasswordFlagName, keyPasswordEnvKey)))
+	}
+
+	keyType := cmdutils.GetUserSetOptionalVarFromString(cmd, keyTypeFlagName, keyTypeEnvKey)
+	if keyType == "" {
+		keyType = defaultKeyType
+	}
+
+	_, privateKey, err := common.GenerateKeyPair(keyType)
+
+	return privateKey, err
+}
+
+func listCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the aliases of the keystore",
+		Long:  "List the aliases of the keystore, with their DID and the purposes of their keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			ks, err := common.GetKeystore(cmd)
+			if err != nil {
+				return err
+			}
+
+			infos := make([]keySetInfo, 0, len(ks.KeySets))
+			lines := make([]string, 0, len(ks.KeySets))
+
+			for _, keySet := range ks.KeySets {
+				info := keySetInfo{Alias: keySet.Alias, DID: keySet.DID}
+
+				for purpose := range keySet.Keys {
+					info.Purposes = append(info.Purposes, purpose)
+				}
+
+				sort.Strings(info.Purposes)
+
+				infos = append(infos, info)
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s", info.Alias, info.DID,
+					strings.Join(info.Purposes, ",")))
+			}
+
+			return common.PrintResult(cmd, output, infos, strings.Join(lines, "\n"))
+		},
+	}
+
+	common.AddKeystoreFlags(cmd)
+	cmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+
+	return cmd
+}
+
+func exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a key of the keystore",
+		Long:  "Print the private key PEM, or the public key PEM, of the key of an alias with a purpose",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			alias, err := cmdutils.GetUserSetVarFromString(cmd, common.KeyAliasFlagName, common.KeyAliasEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			purpose, err := cmdutils.GetUserSetVarFromString(cmd, keyPurposeFlagName, keyPurposeEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			public, err := getPublic(cmd)
+			if err != nil {
+				return err
+			}
+
+			keyPEM, err := exportKey(cmd, alias, purpose, public)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), string(keyPEM))
+
+			return nil
+		},
+	}
+
+	common.AddKeyAliasFlags(cmd)
+	cmd.Flags().StringP(keyPurposeFlagName, "", "", keyPurposeFlagUsage)
+	cmd.Flags().StringP(keyPasswordFlagName, "", "", keyPasswordFlagUsage)
+	cmd.Flags().StringP(publicFlagName, "", "", publicFlagUsage)
+
+	return cmd
+}
+
+func getPublic(cmd *cobra.Command) (bool, error) {
+	publicString := cmdutils.GetUserSetOptionalVarFromString(cmd, publicFlagName, publicEnvKey)
+	if publicString == "" {
+		return false, nil
+	}
+
+	public, err := strconv.ParseBool(publicString)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", publicFlagName, err)
+	}
+
+	return public, nil
+}
+
+func exportKey(cmd *cobra.Command, alias, purpose string, public bool) ([]byte, error) {
+	ks, err := common.GetKeystore(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := ks.KeySet(alias)
+	if keySet == nil {
+		return nil, fmt.Errorf("key alias '%s' not found in keystore", alias)
+	}
+
+	if public {
+		publicKey, e := keySet.PublicKey(purpose)
+		if e != nil {
+			return nil, e
+		}
+
+		if publicKey == nil {
+			return nil, fmt.Errorf("alias '%s' has no %s key", alias, purpose)
+		}
+
+		return common.PublicKeyToPEM(publicKey)
+	}
+
+	privateKey, err := keySet.PrivateKey(purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	if privateKey == nil {
+		return nil, fmt.Errorf("alias '%s' has no %s key", alias, purpose)
+	}
+
+	return common.PrivateKeyToPEM(privateKey,
+		[]byte(cmdutils.GetUserSetOptionalVarFromString(cmd, keyPasswordFlagName, keyPasswordEnvKey)))
+}
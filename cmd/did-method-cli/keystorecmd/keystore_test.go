@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystorecmd
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+const flag = "--"
+
+func TestKeystoreCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "keystore.json")
+
+	t.Run("test init", func(t *testing.T) {
+		os.Clearenv()
+
+		out, err := execute(t, "init", flag+common.KeystoreFlagName, path,
+			flag+common.KeystorePasswordFlagName, "password")
+		require.NoError(t, err)
+		require.Equal(t, "created keystore "+path+"\n", out)
+
+		_, err = execute(t, "init", flag+common.KeystoreFlagName, path,
+			flag+common.KeystorePasswordFlagName, "password")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("test add generated and from file", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(common.KeystoreEnvKey, path))
+		require.NoError(t, os.Setenv(common.KeystorePasswordEnvKey, "password"))
+
+		out, err := execute(t, "add", flag+common.KeyAliasFlagName, "alias1",
+			flag+keyPurposeFlagName, keystore.PurposeUpdate, flag+didURIFlagName, "did:ex:123")
+		require.NoError(t, err)
+		require.Equal(t, "added update key to alias alias1\n", out)
+
+		_, privateKey, err := common.GenerateKeyPair("P256")
+		require.NoError(t, err)
+
+		keyPEM, err := common.PrivateKeyToPEM(privateKey, []byte("keypassword"))
+		require.NoError(t, err)
+
+		keyFile := filepath.Join(dir, "key.pem")
+		require.NoError(t, ioutil.WriteFile(keyFile, keyPEM, 0600))
+
+		_, err = execute(t, "add", flag+common.KeyAliasFlagName, "alias1",
+			flag+keyPurposeFlagName, keystore.PurposeRecovery, flag+keyFileFlagName, keyFile,
+			flag+keyPasswordFlagName, "keypassword")
+		require.NoError(t, err)
+	})
+
+	t.Run("test add invalid purpose", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(common.KeystoreEnvKey, path))
+		require.NoError(t, os.Setenv(common.KeystorePasswordEnvKey, "password"))
+
+		_, err := execute(t, "add", flag+common.KeyAliasFlagName, "alias1", flag+keyPurposeFlagName, "wrong")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid key purpose 'wrong'")
+	})
+
+	t.Run("test list", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(common.KeystoreEnvKey, path))
+		require.NoError(t, os.Setenv(common.KeystorePasswordEnvKey, "password"))
+
+		out, err := execute(t, "list")
+		require.NoError(t, err)
+		require.Equal(t, "alias1\tdid:ex:123\trecovery,update\n", out)
+
+		out, err = execute(t, "list", flag+common.OutputFlagName, "json")
+		require.NoError(t, err)
+
+		var infos []keySetInfo
+		require.NoError(t, json.Unmarshal([]byte(out), &infos))
+		require.Equal(t, []keySetInfo{{Alias: "alias1", DID: "did:ex:123",
+			Purposes: []string{keystore.PurposeRecovery, keystore.PurposeUpdate}}}, infos)
+	})
+
+	t.Run("test export", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(common.KeystoreEnvKey, path))
+		require.NoError(t, os.Setenv(common.KeystorePasswordEnvKey, "password"))
+
+		out, err := execute(t, "export", flag+common.KeyAliasFlagName, "alias1",
+			flag+keyPurposeFlagName, keystore.PurposeRecovery)
+		require.NoError(t, err)
+
+		privateKey, err := common.PrivateKeyFromPEM([]byte(out), nil)
+		require.NoError(t, err)
+
+		out, err = execute(t, "export", flag+common.KeyAliasFlagName, "alias1",
+			flag+keyPurposeFlagName, keystore.PurposeRecovery, flag+publicFlagName, "true")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(out, "-----BEGIN PUBLIC KEY-----"))
+
+		publicKeyPEM, err := common.PublicKeyToPEM(privateKey.(crypto.Signer).Public())
+		require.NoError(t, err)
+		require.Equal(t, string(publicKeyPEM), out)
+	})
+
+	t.Run("test export missing key", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(common.KeystoreEnvKey, path))
+		require.NoError(t, os.Setenv(common.KeystorePasswordEnvKey, "password"))
+
+		_, err := execute(t, "export", flag+common.KeyAliasFlagName, "alias1",
+			flag+keyPurposeFlagName, keystore.PurposeNextUpdate)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "alias 'alias1' has no next-update key")
+
+		_, err = execute(t, "export", flag+common.KeyAliasFlagName, "alias2",
+			flag+keyPurposeFlagName, keystore.PurposeUpdate)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key alias 'alias2' not found in keystore")
+	})
+
+	t.Run("test wrong keystore password", func(t *testing.T) {
+		os.Clearenv()
+		require.NoError(t, os.Setenv(common.KeystoreEnvKey, path))
+		require.NoError(t, os.Setenv(common.KeystorePasswordEnvKey, "wrong"))
+
+		_, err := execute(t, "list")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the password may be wrong")
+	})
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := GetKeystoreCmd()
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
@@ -10,13 +10,30 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/addstakeholdercmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/bootstraptestnetcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/confighashcmd"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/createconfigcmd"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/createdidcmd"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/deactivatedidcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/didconfigurationcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/diffconfigcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/generatekeyscmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/keystorecmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/migratedidcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/mnemoniccmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/publishconfigcmd"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/recoverdidcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/removestakeholdercmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/resolvedidcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/rotatekeyscmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/signconfigcmd"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/updateconfigcmd"
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/updatedidcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/updatepolicycmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/verifyconfigcmd"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/verifydidcmd"
 )
 
 func main() {
@@ -26,13 +43,32 @@ func main() {
 		},
 	}
 
+	common.AddLogLevelFlag(rootCmd)
+	common.AddDebugFlag(rootCmd)
+
 	rootCmd.AddCommand(createconfigcmd.GetCreateConfigCmd())
 	rootCmd.AddCommand(updateconfigcmd.GetUpdateConfigCmd())
+	rootCmd.AddCommand(signconfigcmd.GetSignConfigCmd())
+	rootCmd.AddCommand(addstakeholdercmd.GetAddStakeholderCmd())
+	rootCmd.AddCommand(removestakeholdercmd.GetRemoveStakeholderCmd())
+	rootCmd.AddCommand(updatepolicycmd.GetUpdatePolicyCmd())
+	rootCmd.AddCommand(verifyconfigcmd.GetVerifyConfigCmd())
+	rootCmd.AddCommand(diffconfigcmd.GetDiffConfigCmd())
+	rootCmd.AddCommand(publishconfigcmd.GetPublishConfigCmd())
+	rootCmd.AddCommand(bootstraptestnetcmd.GetBootstrapTestnetCmd())
 	rootCmd.AddCommand(confighashcmd.GetConfigHashCmd())
 	rootCmd.AddCommand(createdidcmd.GetCreateDIDCmd())
 	rootCmd.AddCommand(updatedidcmd.GetUpdateDIDCmd())
 	rootCmd.AddCommand(recoverdidcmd.GetRecoverDIDCmd())
 	rootCmd.AddCommand(deactivatedidcmd.GetDeactivateDIDCmd())
+	rootCmd.AddCommand(resolvedidcmd.GetResolveDIDCmd())
+	rootCmd.AddCommand(didconfigurationcmd.GetDIDConfigurationCmd())
+	rootCmd.AddCommand(migratedidcmd.GetMigrateDIDCmd())
+	rootCmd.AddCommand(verifydidcmd.GetVerifyDIDCmd())
+	rootCmd.AddCommand(generatekeyscmd.GetGenerateKeysCmd())
+	rootCmd.AddCommand(rotatekeyscmd.GetRotateKeysCmd())
+	rootCmd.AddCommand(keystorecmd.GetKeystoreCmd())
+	rootCmd.AddCommand(mnemoniccmd.GetMnemonicCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Failed to run did method cli: %s", err.Error())
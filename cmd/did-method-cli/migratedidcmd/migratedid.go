@@ -0,0 +1,384 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migratedidcmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/migrate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+const (
+	didURIFlagName  = "did-uri"
+	didURIEnvKey    = "DID_METHOD_CLI_DID_URI"
+	didURIFlagUsage = "did:trustbloc DID URI to migrate. " +
+		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
+
+	domainFlagName      = "domain"
+	domainFileEnvKey    = "DID_METHOD_CLI_DOMAIN"
+	domainFileFlagUsage = "URL to the did:trustbloc consortium's domain. " +
+		" Alternatively, this can be set with the following environment variable: " + domainFileEnvKey
+
+	sidetreeURLFlagName  = "sidetree-url"
+	sidetreeURLFlagUsage = "Comma-Separated list of sidetree url the did:trustbloc DID is resolved with." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeURLEnvKey
+	sidetreeURLEnvKey = "DID_METHOD_CLI_SIDETREE_URL"
+
+	orbURLFlagName  = "orb-url"
+	orbURLEnvKey    = "DID_METHOD_CLI_ORB_URL"
+	orbURLFlagUsage = "Sidetree URL of the did:orb node, such as https://orb.example.com/sidetree/0.1.0." +
+		" Alternatively, this can be set with the following environment variable: " + orbURLEnvKey
+
+	orbDIDFlagName  = "orb-did"
+	orbDIDEnvKey    = "DID_METHOD_CLI_ORB_DID"
+	orbDIDFlagUsage = "did:orb DID the DID was already migrated to, which is updated to match the DID instead of" +
+		" creating a new did:orb DID." +
+		" Alternatively, this can be set with the following environment variable: " + orbDIDEnvKey
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "DID_METHOD_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "DID_METHOD_CLI_TLS_CACERTS"
+
+	sidetreeWriteTokenFlagName  = "sidetree-write-token"
+	sidetreeWriteTokenEnvKey    = "DID_METHOD_CLI_SIDETREE_WRITE_TOKEN" //nolint: gosec
+	sidetreeWriteTokenFlagUsage = "The sidetree write token " +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeWriteTokenEnvKey
+
+	recoveryKeyFlagName  = "recoverykey"
+	recoveryKeyEnvKey    = "DID_METHOD_CLI_RECOVERYKEY"
+	recoveryKeyFlagUsage = "The public key PEM used for recovery of the did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + recoveryKeyEnvKey
+
+	recoveryKeyFileFlagName  = "recoverykey-file"
+	recoveryKeyFileEnvKey    = "DID_METHOD_CLI_RECOVERYKEY_FILE"
+	recoveryKeyFileFlagUsage = "The file that contains the public key PEM used for recovery of the did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + recoveryKeyFileEnvKey
+
+	updateKeyFlagName  = "updatekey"
+	updateKeyEnvKey    = "DID_METHOD_CLI_UPDATEKEY"
+	updateKeyFlagUsage = "The public key PEM used for validating the signature of the next update of the" +
+		" did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + updateKeyEnvKey
+
+	updateKeyFileFlagName  = "updatekey-file"
+	updateKeyFileEnvKey    = "DID_METHOD_CLI_UPDATEKEY_FILE"
+	updateKeyFileFlagUsage = "The file that contains the public key PEM used for" +
+		" validating the signature of the next update of the did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + updateKeyFileEnvKey
+
+	signingKeyFlagName  = "signingkey"
+	signingKeyEnvKey    = "DID_METHOD_CLI_SIGNINGKEY"
+	signingKeyFlagUsage = "The private key PEM used for signing the update of the did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyEnvKey
+
+	signingKeyFileFlagName  = "signingkey-file"
+	signingKeyFileEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_FILE"
+	signingKeyFileFlagUsage = "The file that contains the private key" +
+		" PEM used for signing the update of the did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyFileEnvKey
+
+	signingKeyPasswordFlagName  = "signingkey-password"
+	signingKeyPasswordEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_PASSWORD" //nolint: gosec
+	signingKeyPasswordFlagUsage = "signing key pem password. " +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyPasswordEnvKey
+
+	nextUpdateKeyFlagName  = "nextupdatekey"
+	nextUpdateKeyEnvKey    = "DID_METHOD_CLI_NEXTUPDATEKEY"
+	nextUpdateKeyFlagUsage = "The public key PEM used for validating the signature of the next update of the" +
+		" did:orb document." +
+		" Alternatively, this can be set with the following environment variable: " + nextUpdateKeyEnvKey
+
+	nextUpdateKeyFileFlagName  = "nextupdatekey-file"
+	nextUpdateKeyFileEnvKey    = "DID_METHOD_CLI_NEXTUPDATEKEY_FILE"
+	nextUpdateKeyFileFlagUsage = "The file that contains the public key" +
+		" PEM used for validating the signature of the next update of the did:orb document. " +
+		" Alternatively, this can be set with the following environment variable: " + nextUpdateKeyFileEnvKey
+
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationNone   = "none"
+)
+
+// result is the migration result, with the equivalence report of the DID and its did:orb DID. For an update, the
+// report is the one of the did:orb document before the update.
+type result struct {
+	Operation   string   `json:"operation" yaml:"operation"`
+	DID         string   `json:"did" yaml:"did"`
+	OrbDID      string   `json:"orbDID" yaml:"orbDID"`
+	Equivalent  bool     `json:"equivalent" yaml:"equivalent"`
+	Divergences []string `json:"divergences,omitempty" yaml:"divergences,omitempty"`
+	Skipped     []string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// GetMigrateDIDCmd returns the Cobra migrate did command.
+func GetMigrateDIDCmd() *cobra.Command {
+	migrateDIDCmd := migrateDIDCmd()
+
+	createFlags(migrateDIDCmd)
+
+	return migrateDIDCmd
+}
+
+func migrateDIDCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate-did",
+		Short: "Migrate TrustBloc DID to did:orb",
+		Long: "Create the did:orb DID equivalent to a TrustBloc DID, or update the did:orb DID it was migrated to," +
+			" and report whether the two documents are equivalent",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
+			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName, didURIEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			orbURL, err := cmdutils.GetUserSetVarFromString(cmd, orbURLFlagName, orbURLEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			orbDID := cmdutils.GetUserSetOptionalVarFromString(cmd, orbDIDFlagName, orbDIDEnvKey)
+
+			sidetreeWriteToken := cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
+				sidetreeWriteTokenEnvKey)
+
+			domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainFileEnvKey)
+
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			dryRun, err := common.GetDryRun(cmd)
+			if err != nil {
+				return err
+			}
+
+			resolved, err := common.NewResolver(domain,
+				cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey), tlsConfig,
+				sidetreeWriteToken).Read(didURI)
+			if err != nil {
+				return fmt.Errorf("failed to resolve did: %w", err)
+			}
+
+			didDoc := resolved.DIDDocument
+
+			client := did.New(did.WithAuthToken(sidetreeWriteToken),
+				did.WithTLSConfig(tlsConfig),
+				did.WithDryRun(common.DryRunCallback(cmd, dryRun)))
+
+			m := &migration{cmd: cmd, client: client, plan: migrate.NewPlan(didDoc), orbURL: orbURL}
+
+			var (
+				orbDoc    *docdid.Doc
+				operation string
+			)
+
+			if orbDID == "" {
+				orbDoc, err = m.create()
+				operation = operationCreate
+			} else {
+				orbDoc, operation, err = m.update(orbDID, tlsConfig, sidetreeWriteToken)
+			}
+
+			if errors.Is(err, did.ErrDryRun) {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return printResult(cmd, output, operation, migrate.Compare(didDoc, orbDoc))
+		},
+	}
+}
+
+// migration creates or updates the did:orb DID with the plan of the did:trustbloc document
+type migration struct {
+	cmd    *cobra.Command
+	client *did.Client
+	plan   *migrate.Plan
+	orbURL string
+}
+
+// create creates the did:orb DID and returns its document
+func (m *migration) create() (*docdid.Doc, error) {
+	recoveryKey, err := common.GetKey(m.cmd, recoveryKeyFlagName, recoveryKeyEnvKey, recoveryKeyFileFlagName,
+		recoveryKeyFileEnvKey, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	updateKey, err := common.GetKey(m.cmd, updateKeyFlagName, updateKeyEnvKey, updateKeyFileFlagName,
+		updateKeyFileEnvKey, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := m.plan.CreateOptions()
+	opts = append(opts, create.WithRecoveryPublicKey(recoveryKey), create.WithUpdatePublicKey(updateKey),
+		create.WithSidetreeEndpoint(m.orbURL))
+
+	orbDoc, err := m.client.CreateDID("", opts...)
+	if err != nil && !errors.Is(err, did.ErrDryRun) {
+		return nil, fmt.Errorf("failed to create did:orb did: %w", err)
+	}
+
+	return orbDoc, err
+}
+
+// update updates the did:orb DID, unless its document already matches the plan, and returns its document as it
+// was before the update
+func (m *migration) update(orbDID string, tlsConfig *tls.Config, authToken string) (*docdid.Doc, string, error) {
+	resolved, err := common.NewResolver("", []string{m.orbURL}, tlsConfig, authToken).Read(orbDID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve did:orb did: %w", err)
+	}
+
+	orbDoc := resolved.DIDDocument
+
+	opts := m.plan.UpdateOptions(orbDoc)
+	if len(opts) == 0 {
+		return orbDoc, operationNone, nil
+	}
+
+	signingKey, err := common.GetSigningKey(m.cmd, signingKeyFlagName, signingKeyEnvKey, signingKeyFileFlagName,
+		signingKeyFileEnvKey, signingKeyPasswordFlagName, signingKeyPasswordEnvKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextUpdateKey, err := common.GetKey(m.cmd, nextUpdateKeyFlagName, nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName,
+		nextUpdateKeyFileEnvKey, nil, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts = append(opts, update.WithSigningKey(signingKey), update.WithNextUpdatePublicKey(nextUpdateKey),
+		update.WithSidetreeEndpoint(m.orbURL))
+
+	err = m.client.UpdateDID(orbDID, "", opts...)
+	if err != nil && !errors.Is(err, did.ErrDryRun) {
+		return nil, "", fmt.Errorf("failed to update did:orb did: %w", err)
+	}
+
+	return orbDoc, operationUpdate, err
+}
+
+func printResult(cmd *cobra.Command, output, operation string, report *migrate.Report) error {
+	r := &result{Operation: operation, DID: report.DID, OrbDID: report.OrbDID, Equivalent: report.Equivalent,
+		Divergences: report.Divergences, Skipped: report.Skipped}
+
+	var text strings.Builder
+
+	switch operation {
+	case operationCreate:
+		fmt.Fprintf(&text, "Created did:orb DID %s for %s\n", r.OrbDID, r.DID)
+	case operationUpdate:
+		fmt.Fprintf(&text, "Updated did:orb DID %s to match %s\n", r.OrbDID, r.DID)
+	default:
+		fmt.Fprintf(&text, "did:orb DID %s already matches %s\n", r.OrbDID, r.DID)
+	}
+
+	fmt.Fprintf(&text, "Equivalent: %t", r.Equivalent)
+
+	for _, divergence := range r.Divergences {
+		fmt.Fprintf(&text, "\nDivergence: %s", divergence)
+	}
+
+	if len(r.Skipped) != 0 {
+		fmt.Fprintf(&text, "\nNot migrated, as sidetree only takes Ed25519 and P-256 keys: %s",
+			strings.Join(r.Skipped, ", "))
+	}
+
+	return common.PrintResult(cmd, output, r, text.String())
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
+	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
+	startCmd.Flags().StringP(orbURLFlagName, "", "", orbURLFlagUsage)
+	startCmd.Flags().StringP(orbDIDFlagName, "", "", orbDIDFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
+	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
+	startCmd.Flags().StringP(recoveryKeyFlagName, "", "", recoveryKeyFlagUsage)
+	startCmd.Flags().StringP(recoveryKeyFileFlagName, "", "", recoveryKeyFileFlagUsage)
+	startCmd.Flags().StringP(updateKeyFlagName, "", "", updateKeyFlagUsage)
+	startCmd.Flags().StringP(updateKeyFileFlagName, "", "", updateKeyFileFlagUsage)
+	startCmd.Flags().StringP(signingKeyFlagName, "", "", signingKeyFlagUsage)
+	startCmd.Flags().StringP(signingKeyFileFlagName, "", "", signingKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
+	startCmd.Flags().StringP(nextUpdateKeyFlagName, "", "", nextUpdateKeyFlagUsage)
+	startCmd.Flags().StringP(nextUpdateKeyFileFlagName, "", "", nextUpdateKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.DryRunFlagName, "", "", common.DryRunFlagUsage)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migratedidcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+
+	recoveryKeyPEM = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAErOnEHb7wH+YOYA6XQroWbeNrR18Y
+f4HEGojknkxuXjFKGyI821aUlIO7xT+I6dPlfsWyXRSLYeJoFA9rLLjOjA==
+-----END PUBLIC KEY-----`
+
+	updateKeyPEM = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEFoxLiiZZYCh8XOZE0MXUYIgCrwIq
+ho+LGIVUXDNaduiNfpLmk5MXS5Q7WQAMgaJBRyRldIvbrNWqph4DH2gdKQ==
+-----END PUBLIC KEY-----`
+)
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test did uri is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetMigrateDIDCmd()
+
+		cmd.SetArgs(orbURLArg("https://orb.example.com/sidetree/0.1.0"))
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither did-uri (command line flag) nor "+
+			"DID_METHOD_CLI_DID_URI (environment variable) have been set.")
+	})
+
+	t.Run("test orb url is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetMigrateDIDCmd()
+
+		cmd.SetArgs(didURIArg())
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither orb-url (command line flag) nor "+
+			"DID_METHOD_CLI_ORB_URL (environment variable) have been set.")
+	})
+}
+
+func TestMigrateDID(t *testing.T) {
+	type didResolution struct {
+		Context     interface{}     `json:"@context"`
+		DIDDocument json.RawMessage `json:"didDocument"`
+	}
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bytes, err := (&did.Doc{ID: "did:ex:123", Context: []string{did.ContextV1}}).JSONBytes()
+		require.NoError(t, err)
+		b, err := json.Marshal(didResolution{Context: "https://w3id.org/did-resolution/v1", DIDDocument: bytes})
+		require.NoError(t, err)
+		w.Header().Set("Content-type", "application/did+ld+json")
+		_, err = fmt.Fprint(w, string(b))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	dir, err := ioutil.TempDir("", "migratedid")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	recoveryKeyFile := filepath.Join(dir, "recovery.pem")
+	require.NoError(t, ioutil.WriteFile(recoveryKeyFile, []byte(recoveryKeyPEM), 0600))
+
+	updateKeyFile := filepath.Join(dir, "update.pem")
+	require.NoError(t, ioutil.WriteFile(updateKeyFile, []byte(updateKeyPEM), 0600))
+
+	t.Run("test failed to resolve did", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetMigrateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLFlagName, "wrongurl")
+		args = append(args, orbURLArg(serv.URL)...)
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve did")
+	})
+
+	t.Run("test create", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetMigrateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLFlagName, serv.URL)
+		args = append(args, orbURLArg(serv.URL)...)
+		args = append(args, flag+recoveryKeyFileFlagName, recoveryKeyFile)
+		args = append(args, flag+updateKeyFileFlagName, updateKeyFile)
+		args = append(args, flag+"output", "json")
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		var r result
+		require.NoError(t, json.Unmarshal(out.Bytes(), &r))
+		require.Equal(t, operationCreate, r.Operation)
+		require.True(t, r.Equivalent)
+	})
+
+	t.Run("test update of equivalent did:orb did", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetMigrateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLFlagName, serv.URL)
+		args = append(args, orbURLArg(serv.URL)...)
+		args = append(args, flag+orbDIDFlagName, "did:ex:123")
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "did:orb DID did:ex:123 already matches did:ex:123")
+		require.Contains(t, out.String(), "Equivalent: true")
+	})
+}
+
+func didURIArg() []string {
+	return []string{flag + didURIFlagName, "did:ex:123"}
+}
+
+func orbURLArg(value string) []string {
+	return []string{flag + orbURLFlagName, value}
+}
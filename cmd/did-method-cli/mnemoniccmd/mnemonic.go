@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mnemoniccmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/mnemonic"
+)
+
+const (
+	mnemonicFlagName  = "mnemonic"
+	mnemonicEnvKey    = "DID_METHOD_CLI_MNEMONIC"
+	mnemonicFlagUsage = "BIP39 mnemonic the keys are derived from. Prefer the environment variable." +
+		" Alternatively, this can be set with the following environment variable: " + mnemonicEnvKey
+
+	passphraseFlagName  = "mnemonic-passphrase"
+	passphraseEnvKey    = "DID_METHOD_CLI_MNEMONIC_PASSPHRASE" //nolint: gosec
+	passphraseFlagUsage = "Optional BIP39 passphrase of the mnemonic." +
+		" Alternatively, this can be set with the following environment variable: " + passphraseEnvKey
+
+	updateKeyIndexFlagName  = "update-key-index"
+	updateKeyIndexEnvKey    = "DID_METHOD_CLI_UPDATE_KEY_INDEX"
+	updateKeyIndexFlagUsage = "Index of the current update key, incremented after each update." +
+		" Defaults to 0 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + updateKeyIndexEnvKey
+
+	recoveryKeyIndexFlagName  = "recovery-key-index"
+	recoveryKeyIndexEnvKey    = "DID_METHOD_CLI_RECOVERY_KEY_INDEX"
+	recoveryKeyIndexFlagUsage = "Index of the current recovery key, incremented after each recovery." +
+		" Defaults to 0 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + recoveryKeyIndexEnvKey
+
+	outDirFlagName  = "out-dir"
+	outDirEnvKey    = "DID_METHOD_CLI_OUT_DIR"
+	outDirFlagUsage = "Directory to write the derived keys to." +
+		" Alternatively, this can be set with the following environment variable: " + outDirEnvKey
+
+	keyTypeFlagName  = "key-type"
+	keyTypeEnvKey    = "DID_METHOD_CLI_KEY_TYPE"
+	keyTypeFlagUsage = "Type of the derived keys." +
+		" Possible values [Ed25519] [P256]. Defaults to Ed25519 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyTypeEnvKey
+
+	keyPasswordFlagName  = "key-password"
+	keyPasswordEnvKey    = "DID_METHOD_CLI_KEY_PASSWORD" //nolint: gosec
+	keyPasswordFlagUsage = "Password used to encrypt the derived private key PEMs. Not encrypted if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyPasswordEnvKey
+
+	updateKeyDir       = "update"
+	recoveryKeyDir     = "recover"
+	nextUpdateKeyDir   = "next-update"
+	nextRecoveryKeyDir = "next-recover"
+
+	dirPermissions = 0700
+)
+
+type derivedKey struct {
+	dir  string
+	path string
+}
+
+// GetMnemonicCmd returns the Cobra mnemonic command.
+func GetMnemonicCmd() *cobra.Command {
+	mnemonicCmd := &cobra.Command{
+		Use:   "mnemonic",
+		Short: "Derive the keys of a DID from a mnemonic",
+		Long: "Generate a BIP39 mnemonic, and derive the update and recovery keys of a DID from it, so that backing" +
+			" up the mnemonic is enough to regenerate the keys needed to recover the DID",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	mnemonicCmd.AddCommand(newCmd(), deriveCmd())
+
+	return mnemonicCmd
+}
+
+func newCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "new",
+		Short: "Generate a mnemonic",
+		Long:  "Generate and print a new BIP39 mnemonic of 24 words. Write it down and keep it offline.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := mnemonic.New()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), m)
+
+			return nil
+		},
+	}
+}
+
+func deriveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "derive",
+		Short: "Derive the keys of a DID from a mnemonic",
+		Long: "Derive the update and recovery key pairs of the indexes, and the next update and recovery key pairs," +
+			" from a mnemonic, and write them to the update, recover, next-update and next-recover dirs of" +
+			" the out dir. The same mnemonic and indexes always derive the same keys.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := cmdutils.GetUserSetVarFromString(cmd, mnemonicFlagName, mnemonicEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			outDir, err := cmdutils.GetUserSetVarFromString(cmd, outDirFlagName, outDirEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			keys, err := getDerivedKeys(cmd)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(outDir, dirPermissions); err != nil {
+				return fmt.Errorf("failed to create out dir: %w", err)
+			}
+
+			return deriveKeys(cmd, m, outDir, keys)
+		},
+	}
+
+	cmd.Flags().StringP(mnemonicFlagName, "", "", mnemonicFlagUsage)
+	cmd.Flags().StringP(passphraseFlagName, "", "", passphraseFlagUsage)
+	cmd.Flags().StringP(updateKeyIndexFlagName, "", "", updateKeyIndexFlagUsage)
+	cmd.Flags().StringP(recoveryKeyIndexFlagName, "", "", recoveryKeyIndexFlagUsage)
+	cmd.Flags().StringP(outDirFlagName, "", "", outDirFlagUsage)
+	cmd.Flags().StringP(keyTypeFlagName, "", "", keyTypeFlagUsage)
+	cmd.Flags().StringP(keyPasswordFlagName, "", "", keyPasswordFlagUsage)
+
+	return cmd
+}
+
+func getDerivedKeys(cmd *cobra.Command) ([]derivedKey, error) {
+	updateIndex, err := getIndex(cmd, updateKeyIndexFlagName, updateKeyIndexEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryIndex, err := getIndex(cmd, recoveryKeyIndexFlagName, recoveryKeyIndexEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []derivedKey{
+		{dir: updateKeyDir, path: mnemonic.UpdateKeyPath(updateIndex)},
+		{dir: recoveryKeyDir, path: mnemonic.RecoveryKeyPath(recoveryIndex)},
+		{dir: nextUpdateKeyDir, path: mnemonic.UpdateKeyPath(updateIndex + 1)},
+		{dir: nextRecoveryKeyDir, path: mnemonic.RecoveryKeyPath(recoveryIndex + 1)},
+	}, nil
+}
+
+func deriveKeys(cmd *cobra.Command, m, outDir string, keys []derivedKey) error {
+	passphrase := cmdutils.GetUserSetOptionalVarFromString(cmd, passphraseFlagName, passphraseEnvKey)
+	password := []byte(cmdutils.GetUserSetOptionalVarFromString(cmd, keyPasswordFlagName, keyPasswordEnvKey))
+
+	keyType := cmdutils.GetUserSetOptionalVarFromString(cmd, keyTypeFlagName, keyTypeEnvKey)
+	if keyType == "" {
+		keyType = doc.Ed25519KeyType
+	}
+
+	for _, key := range keys {
+		publicKey, privateKey, err := mnemonic.DeriveKey(m, passphrase, key.path, keyType)
+		if err != nil {
+			return err
+		}
+
+		err = common.WritePEMKeyPair(cmd.OutOrStdout(), filepath.Join(outDir, key.dir), publicKey, privateKey,
+			password)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getIndex(cmd *cobra.Command, flagName, envKey string) (uint32, error) {
+	indexString := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if indexString == "" {
+		return 0, nil
+	}
+
+	index, err := strconv.ParseUint(indexString, 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", flagName, err)
+	}
+
+	return uint32(index), nil
+}
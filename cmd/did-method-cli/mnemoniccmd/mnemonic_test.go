@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mnemoniccmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+)
+
+const (
+	flag         = "--"
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+)
+
+func TestNewCmd(t *testing.T) {
+	out, err := execute(t, "new")
+	require.NoError(t, err)
+	require.Len(t, strings.Fields(out), 24)
+}
+
+func TestDeriveCmd(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		dir, err := ioutil.TempDir("", "mnemonic")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		require.NoError(t, os.Setenv(mnemonicEnvKey, testMnemonic))
+
+		_, err = execute(t, "derive", flag+outDirFlagName, filepath.Join(dir, "keys"), flag+updateKeyIndexFlagName,
+			"1", flag+keyTypeFlagName, "P256")
+		require.NoError(t, err)
+
+		_, err = execute(t, "derive", flag+outDirFlagName, filepath.Join(dir, "next"), flag+updateKeyIndexFlagName,
+			"2", flag+keyTypeFlagName, "P256")
+		require.NoError(t, err)
+
+		for _, keyDir := range []string{updateKeyDir, recoveryKeyDir, nextUpdateKeyDir, nextRecoveryKeyDir} {
+			publicKey, err := common.PublicKeyFromFile(filepath.Join(dir, "keys", keyDir, common.PublicKeyFileName))
+			require.NoError(t, err)
+			require.IsType(t, &ecdsa.PublicKey{}, publicKey)
+		}
+
+		// the next update key of an update is the update key of the next update
+		nextUpdateKey, err := ioutil.ReadFile(filepath.Join(dir, "keys", nextUpdateKeyDir, common.PrivateKeyFileName))
+		require.NoError(t, err)
+
+		updateKey, err := ioutil.ReadFile(filepath.Join(dir, "next", updateKeyDir, common.PrivateKeyFileName))
+		require.NoError(t, err)
+		require.Equal(t, nextUpdateKey, updateKey)
+
+		recoveryKey, err := ioutil.ReadFile(filepath.Join(dir, "next", recoveryKeyDir, common.PrivateKeyFileName))
+		require.NoError(t, err)
+
+		keysRecoveryKey, err := ioutil.ReadFile(filepath.Join(dir, "keys", recoveryKeyDir, common.PrivateKeyFileName))
+		require.NoError(t, err)
+		require.Equal(t, keysRecoveryKey, recoveryKey)
+	})
+
+	t.Run("test missing mnemonic", func(t *testing.T) {
+		os.Clearenv()
+
+		_, err := execute(t, "derive", flag+outDirFlagName, "keys")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither mnemonic (command line flag) nor DID_METHOD_CLI_MNEMONIC"+
+			" (environment variable) have been set.")
+	})
+
+	t.Run("test invalid mnemonic", func(t *testing.T) {
+		os.Clearenv()
+
+		dir, err := ioutil.TempDir("", "mnemonic")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		_, err = execute(t, "derive", flag+mnemonicFlagName, "abandon about", flag+outDirFlagName, dir)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid mnemonic")
+	})
+
+	t.Run("test invalid index", func(t *testing.T) {
+		os.Clearenv()
+
+		_, err := execute(t, "derive", flag+mnemonicFlagName, testMnemonic, flag+outDirFlagName, "keys",
+			flag+recoveryKeyIndexFlagName, "-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for recovery-key-index")
+	})
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := GetMnemonicCmd()
+
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
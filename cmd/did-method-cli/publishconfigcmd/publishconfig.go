@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package publishconfigcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+)
+
+const (
+	configFileFlagName  = "config-file"
+	configFileEnvKey    = "DID_METHOD_CLI_CONFIG_FILE"
+	configFileFlagUsage = "Comma-Separated list of the config files to publish. A directory publishes all the" +
+		" .json files in it, such as the did-trustbloc directory written by the config commands." +
+		" Alternatively, this can be set with the following environment variable: " + configFileEnvKey
+
+	ipfsURLFlagName  = "ipfs-url"
+	ipfsURLEnvKey    = "DID_METHOD_CLI_IPFS_URL"
+	ipfsURLFlagUsage = "URL of the HTTP API of the IPFS node to add the config files to." +
+		" Defaults to " + defaultIPFSURL + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + ipfsURLEnvKey
+
+	ipfsGatewayFlagName  = "ipfs-gateway"
+	ipfsGatewayEnvKey    = "DID_METHOD_CLI_IPFS_GATEWAY"
+	ipfsGatewayFlagUsage = "URL of the IPFS HTTP gateway the printed URLs of the config files point to." +
+		" Defaults to " + defaultIPFSGateway + " if not set." +
+		" Alternatively, this can be set with the following environment variable: " + ipfsGatewayEnvKey
+
+	ipfsAuthTokenFlagName  = "ipfs-auth-token"
+	ipfsAuthTokenEnvKey    = "DID_METHOD_CLI_IPFS_AUTH_TOKEN" //nolint: gosec
+	ipfsAuthTokenFlagUsage = "Bearer token of the IPFS node or pinning service." +
+		" Alternatively, this can be set with the following environment variable: " + ipfsAuthTokenEnvKey
+
+	defaultIPFSURL     = "http://localhost:5001"
+	defaultIPFSGateway = "https://ipfs.io"
+)
+
+// published is a config file added to IPFS
+type published struct {
+	File string `json:"file" yaml:"file"`
+	CID  string `json:"cid" yaml:"cid"`
+	URI  string `json:"uri" yaml:"uri"`
+	URL  string `json:"url" yaml:"url"`
+}
+
+type ipfsClient struct {
+	httpClient *http.Client
+	url        string
+	authToken  string
+}
+
+// GetPublishConfigCmd returns the Cobra publish config command.
+func GetPublishConfigCmd() *cobra.Command {
+	publishConfigCmd := createPublishConfigCmd()
+
+	createFlags(publishConfigCmd)
+
+	return publishConfigCmd
+}
+
+func createPublishConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "publish-config",
+		Short: "Publish consortium and stakeholder configs to IPFS",
+		Long: "Add consortium and stakeholder config files to an IPFS node, and print their CIDs and URLs." +
+			" A config published to IPFS is referenced by its ipfs://<cid> URI instead of its domain.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFiles, err := cmdutils.GetUserSetVarFromArrayString(cmd, configFileFlagName, configFileEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			files, err := listFiles(configFiles)
+			if err != nil {
+				return err
+			}
+
+			client := &ipfsClient{httpClient: &http.Client{},
+				url:       getOptional(cmd, ipfsURLFlagName, ipfsURLEnvKey, defaultIPFSURL),
+				authToken: cmdutils.GetUserSetOptionalVarFromString(cmd, ipfsAuthTokenFlagName, ipfsAuthTokenEnvKey)}
+
+			gateway := getOptional(cmd, ipfsGatewayFlagName, ipfsGatewayEnvKey, defaultIPFSGateway)
+
+			results, text, err := client.publish(files, gateway)
+			if err != nil {
+				return err
+			}
+
+			return common.PrintResult(cmd, output, results, text)
+		},
+	}
+}
+
+func getOptional(cmd *cobra.Command, flagName, envKey, defaultValue string) string {
+	value := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// listFiles returns the config files to publish, with the .json files of the directories in place of them
+func listFiles(configFiles []string) ([]string, error) {
+	var files []string
+
+	for _, configFile := range configFiles {
+		info, err := os.Stat(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file '%s' : %w", configFile, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, configFile)
+
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(configFile, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(matches)
+
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// publish adds the files to IPFS, and returns their CIDs, URIs and gateway URLs, as results and as text
+func (c *ipfsClient) publish(files []string, gateway string) ([]*published, string, error) {
+	var results []*published
+
+	text := &strings.Builder{}
+
+	for _, file := range files {
+		cid, err := c.add(file)
+		if err != nil {
+			return nil, "", err
+		}
+
+		result := &published{File: file, CID: cid, URI: "ipfs://" + cid,
+			URL: strings.TrimSuffix(gateway, "/") + "/ipfs/" + cid}
+		results = append(results, result)
+
+		fmt.Fprintf(text, "%s %s %s\n", result.File, result.URI, result.URL)
+	}
+
+	return results, text.String(), nil
+}
+
+// add adds the file to IPFS with the add call of the HTTP API of the node, and returns its CID
+func (c *ipfsClient) add(file string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file '%s' : %w", file, err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = part.Write(data); err != nil {
+		return "", err
+	}
+
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost,
+		strings.TrimSuffix(c.url, "/")+"/api/v0/add?cid-version=1&pin=true", body)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	return c.send(req, file)
+}
+
+func (c *ipfsClient) send(req *http.Request, file string) (string, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to add config file '%s' to ipfs: %w", file, err)
+	}
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read ipfs response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to add config file '%s' to ipfs: error %d, `%s`", file, resp.StatusCode,
+			string(respBytes))
+	}
+
+	added := struct {
+		Hash string `json:"Hash"`
+	}{}
+
+	if err := json.Unmarshal(respBytes, &added); err != nil || added.Hash == "" {
+		return "", fmt.Errorf("invalid ipfs response: %s", string(respBytes))
+	}
+
+	return added.Hash, nil
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringArrayP(configFileFlagName, "", []string{}, configFileFlagUsage)
+	startCmd.Flags().StringP(ipfsURLFlagName, "", "", ipfsURLFlagUsage)
+	startCmd.Flags().StringP(ipfsGatewayFlagName, "", "", ipfsGatewayFlagUsage)
+	startCmd.Flags().StringP(ipfsAuthTokenFlagName, "", "", ipfsAuthTokenFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package publishconfigcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test config file is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetPublishConfigCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither config-file (command line flag) nor "+
+			"DID_METHOD_CLI_CONFIG_FILE (environment variable) have been set.")
+	})
+}
+
+func TestPublishConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "consortium.net.json"), []byte("consortium"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "stakeholder.one.json"), []byte("stakeholder"), 0600))
+
+	var authorization string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"Name":"%s","Hash":"bafy%s","Size":"%d"}`, header.Filename, data, len(data))
+	}))
+
+	defer serv.Close()
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetPublishConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + configFileFlagName, filepath.Join(dir, "consortium.net.json"),
+			flag + ipfsURLFlagName, serv.URL, flag + ipfsAuthTokenFlagName, "token"})
+		require.NoError(t, cmd.Execute())
+
+		require.Contains(t, out.String(), "ipfs://bafyconsortium https://ipfs.io/ipfs/bafyconsortium")
+		require.Equal(t, "Bearer token", authorization)
+	})
+
+	t.Run("success with directory and json output", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetPublishConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + configFileFlagName, dir, flag + ipfsURLFlagName, serv.URL,
+			flag + ipfsGatewayFlagName, "http://localhost:8080/", flag + common.OutputFlagName, common.OutputJSON})
+		require.NoError(t, cmd.Execute())
+
+		var results []*published
+		require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+		require.Len(t, results, 2)
+		require.Equal(t, "bafyconsortium", results[0].CID)
+		require.Equal(t, "ipfs://bafystakeholder", results[1].URI)
+		require.Equal(t, "http://localhost:8080/ipfs/bafystakeholder", results[1].URL)
+		require.Empty(t, authorization)
+	})
+
+	t.Run("test config file not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetPublishConfigCmd()
+		cmd.SetArgs([]string{flag + configFileFlagName, "wrong", flag + ipfsURLFlagName, serv.URL})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read config file 'wrong'")
+	})
+
+	t.Run("test ipfs error", func(t *testing.T) {
+		os.Clearenv()
+
+		errServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer errServ.Close()
+
+		cmd := GetPublishConfigCmd()
+		cmd.SetArgs([]string{flag + configFileFlagName, dir, flag + ipfsURLFlagName, errServ.URL})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "to ipfs: error 500")
+	})
+
+	t.Run("test invalid ipfs response", func(t *testing.T) {
+		os.Clearenv()
+
+		errServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "{}")
+		}))
+		defer errServ.Close()
+
+		cmd := GetPublishConfigCmd()
+		cmd.SetArgs([]string{flag + configFileFlagName, dir, flag + ipfsURLFlagName, errServ.URL})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid ipfs response")
+	})
+}
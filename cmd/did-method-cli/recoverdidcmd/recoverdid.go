@@ -6,8 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package recoverdidcmd
 
 import (
-	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -18,6 +18,7 @@ import (
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
 )
 
 const (
@@ -117,11 +118,22 @@ func recoverDIDCmd() *cobra.Command {
 		Short: "Recover TrustBloc DID",
 		Long:  "Recover TrustBloc DID",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			err := common.ApplyDefaults(cmd, map[string]string{
+				didURIFlagName: didURIEnvKey, domainFlagName: domainFileEnvKey, sidetreeURLFlagName: sidetreeURLEnvKey})
+			if err != nil {
+				return err
+			}
+
 			rootCAs, err := getRootCAs(cmd)
 			if err != nil {
 				return err
 			}
 
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
 			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName,
 				didURIEnvKey, false)
 			if err != nil {
@@ -134,22 +146,70 @@ func recoverDIDCmd() *cobra.Command {
 			domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName,
 				domainFileEnvKey)
 
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			result := common.NewResult("recover", didURI)
+
+			dryRun, err := common.GetDryRun(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger, err := common.GetLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			fipsOption, err := common.GetFIPSOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			debugOption, err := common.GetDebugOption(cmd)
+			if err != nil {
+				return err
+			}
+
 			client := did.New(did.WithAuthToken(sidetreeWriteToken),
-				did.WithTLSConfig(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}))
+				did.WithTLSConfig(tlsConfig),
+				did.WithOperationCallback(result.SetOperationInfo),
+				did.WithDryRun(common.DryRunCallback(cmd, dryRun)),
+				fipsOption,
+				debugOption,
+				did.WithLogger(logger))
+
+			ks, keySet, err := common.GetKeySet(cmd)
+			if err != nil {
+				return err
+			}
 
-			opts, err := recoverDIDOption(cmd)
+			opts, err := recoverDIDOption(cmd, keySet)
 			if err != nil {
 				return err
 			}
 
 			err = client.RecoverDID(didURI, domain, opts...)
+			if errors.Is(err, did.ErrDryRun) {
+				return nil
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to recover did: %w", err)
 			}
 
-			fmt.Printf("successfully recoverd DID %s", didURI)
+			err = common.PrintResult(cmd, output, result, fmt.Sprintf("successfully recovered DID %s", didURI))
+			if err != nil {
+				return err
+			}
+
+			if err := writeState(cmd, didURI, domain, keySet); err != nil {
+				return err
+			}
 
-			return nil
+			return ks.Commit(keySet, didURI, keystore.PurposeUpdate, keystore.PurposeRecovery)
 		},
 	}
 }
@@ -167,31 +227,58 @@ func getSidetreeURL(cmd *cobra.Command) []recovery.Option {
 	return opts
 }
 
-func recoverDIDOption(cmd *cobra.Command) ([]recovery.Option, error) {
+func writeState(cmd *cobra.Command, didURI, domain string, keySet *keystore.KeySet) error {
+	state := common.NewState("recover", didURI, domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey))
+
+	state.AddKeyReference(cmd, signingKeyFileFlagName, signingKeyFileEnvKey)
+	state.AddKeyReference(cmd, nextUpdateKeyFileFlagName, nextUpdateKeyFileEnvKey)
+	state.AddKeyReference(cmd, nextRecoveryKeyFileFlagName, nextRecoveryKeyFileEnvKey)
+
+	nextUpdateKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeNextUpdate, nextUpdateKeyFlagName,
+		nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName, nextUpdateKeyFileEnvKey)
+	if err != nil {
+		return err
+	}
+
+	nextRecoveryKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeNextRecovery, nextRecoveryKeyFlagName,
+		nextRecoveryKeyEnvKey, nextRecoveryKeyFileFlagName, nextRecoveryKeyFileEnvKey)
+	if err != nil {
+		return err
+	}
+
+	if err := state.SetCommitments(nextUpdateKey, nextRecoveryKey); err != nil {
+		return err
+	}
+
+	return common.WriteStateOut(cmd, state)
+}
+
+func recoverDIDOption(cmd *cobra.Command, keySet *keystore.KeySet) ([]recovery.Option, error) {
 	opts, err := getPublicKeys(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	signingKey, err := common.GetKey(cmd, signingKeyFlagName, signingKeyEnvKey, signingKeyFileFlagName,
-		signingKeyFileEnvKey, []byte(cmdutils.GetUserSetOptionalVarFromString(cmd, signingKeyPasswordFlagName,
-			signingKeyPasswordEnvKey)), true)
+	signingKey, err := common.GetSigningKeyFromKeySet(cmd, keySet, keystore.PurposeRecovery, signingKeyFlagName,
+		signingKeyEnvKey, signingKeyFileFlagName, signingKeyFileEnvKey, signingKeyPasswordFlagName,
+		signingKeyPasswordEnvKey)
 	if err != nil {
 		return nil, err
 	}
 
 	opts = append(opts, recovery.WithSigningKey(signingKey))
 
-	nextUpdateKey, err := common.GetKey(cmd, nextUpdateKeyFlagName, nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName,
-		nextUpdateKeyFileEnvKey, nil, false)
+	nextUpdateKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeNextUpdate, nextUpdateKeyFlagName,
+		nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName, nextUpdateKeyFileEnvKey)
 	if err != nil {
 		return nil, err
 	}
 
 	opts = append(opts, recovery.WithNextUpdatePublicKey(nextUpdateKey))
 
-	nextRecoveryKey, err := common.GetKey(cmd, nextRecoveryKeyFlagName, nextRecoveryKeyEnvKey,
-		nextRecoveryKeyFileFlagName, nextUpdateKeyFileEnvKey, nil, false)
+	nextRecoveryKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeNextRecovery, nextRecoveryKeyFlagName,
+		nextRecoveryKeyEnvKey, nextRecoveryKeyFileFlagName, nextUpdateKeyFileEnvKey)
 	if err != nil {
 		return nil, err
 	}
@@ -277,15 +364,35 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
 		tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
 	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
 	startCmd.Flags().StringP(publicKeyFileFlagName, "", "", publicKeyFileFlagUsage)
 	startCmd.Flags().StringP(serviceFileFlagName, "", "", serviceFlagUsage)
 	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
 	startCmd.Flags().StringP(signingKeyFlagName, "", "", signingKeyFlagUsage)
 	startCmd.Flags().StringP(signingKeyFileFlagName, "", "", signingKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+	common.AddKeyAliasFlags(startCmd)
 	startCmd.Flags().StringP(nextUpdateKeyFlagName, "", "", nextUpdateKeyFlagUsage)
 	startCmd.Flags().StringP(nextUpdateKeyFileFlagName, "", "", nextUpdateKeyFileFlagUsage)
 	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
 	startCmd.Flags().StringP(nextRecoveryKeyFlagName, "", "", nextRecoveryKeyFlagUsage)
 	startCmd.Flags().StringP(nextRecoveryKeyFileFlagName, "", "", nextRecoveryKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.StateOutFlagName, "", "", common.StateOutFlagUsage)
+	startCmd.Flags().StringP(common.StateInFlagName, "", "", common.StateInFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.DryRunFlagName, "", "", common.DryRunFlagUsage)
+	startCmd.Flags().StringP(common.FIPSFlagName, "", "", common.FIPSFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
 }
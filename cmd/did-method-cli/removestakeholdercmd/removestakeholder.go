@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package removestakeholdercmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	domainFlagName  = "stakeholder-domain"
+	domainEnvKey    = "DID_METHOD_CLI_STAKEHOLDER_DOMAIN"
+	domainFlagUsage = "Domain of the stakeholder to remove." +
+		" Alternatively, this can be set with the following environment variable: " + domainEnvKey
+)
+
+// GetRemoveStakeholderCmd returns the Cobra remove stakeholder command.
+func GetRemoveStakeholderCmd() *cobra.Command {
+	removeStakeholderCmd := createRemoveStakeholderCmd()
+
+	createFlags(removeStakeholderCmd)
+
+	return removeStakeholderCmd
+}
+
+func createRemoveStakeholderCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-stakeholder",
+		Short: "Remove a stakeholder from a consortium",
+		Long: "Create the next version of a consortium config, with a stakeholder removed from its members and signed" +
+			" by a current member. The next version has to be endorsed by as many current members as the current" +
+			" consortium policy requires before it can be published.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			domain, err := cmdutils.GetUserSetVarFromString(cmd, domainFlagName, domainEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			current, currentBytes, err := configcommon.ReadConsortium(cmd)
+			if err != nil {
+				return err
+			}
+
+			next, err := removeMember(current.Config, domain)
+			if err != nil {
+				return err
+			}
+
+			return configcommon.WriteSuccessor(cmd, current, currentBytes, next, map[string][]byte{})
+		},
+	}
+}
+
+func removeMember(current *models.Consortium, domain string) (*models.Consortium, error) {
+	next := *current
+	next.Members = nil
+
+	for _, member := range current.Members {
+		if member.Domain != domain {
+			next.Members = append(next.Members, member)
+		}
+	}
+
+	if len(next.Members) == len(current.Members) {
+		return nil, fmt.Errorf("stakeholder %s is not a member of the consortium", domain)
+	}
+
+	if len(next.Members) == 0 {
+		return nil, fmt.Errorf("stakeholder %s is the last member of the consortium", domain)
+	}
+
+	// the remaining members have to be able to endorse the versions of the config that follow this one
+	if next.Policy.NumQueries > len(next.Members) {
+		return nil, fmt.Errorf("removing stakeholder %s leaves %d members, fewer than the %d endorsements"+
+			" required by the consortium policy: lower numQueries with update-policy first", domain,
+			len(next.Members), next.Policy.NumQueries)
+	}
+
+	return &next, nil
+}
+
+func createFlags(startCmd *cobra.Command) {
+	configcommon.AddSuccessionFlags(startCmd)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFlagUsage)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package removestakeholdercmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test stakeholder domain is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetRemoveStakeholderCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither stakeholder-domain (command line flag) nor "+
+			"DID_METHOD_CLI_STAKEHOLDER_DOMAIN (environment variable) have been set.")
+	})
+}
+
+func TestRemoveStakeholder(t *testing.T) {
+	var keys []ed25519.PrivateKey
+
+	for i := 0; i < 3; i++ {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keys = append(keys, key)
+	}
+
+	consortiumBytes := consortiumConfig(t, 2, keys...)
+
+	consortiumFile := writeFile(t, string(consortiumBytes))
+	defer func() { require.NoError(t, os.Remove(consortiumFile)) }()
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetRemoveStakeholderCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, consortiumFile,
+			flag + configcommon.OutputDirectoryFlagName, dir, flag + domainFlagName, "stakeholder.three",
+			flag + common.SigningKeyJWKFlagName, jwk(t, keys[0], false)})
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "endorsed by stakeholder.one (1 of the 2 endorsements required)")
+
+		nextBytes, err := ioutil.ReadFile(filepath.Join(dir, "did-trustbloc", "consortium.net.json"))
+		require.NoError(t, err)
+
+		next, err := models.ParseConsortium(nextBytes)
+		require.NoError(t, err)
+		require.Len(t, next.Config.Members, 2)
+		require.Equal(t, "stakeholder.one", next.Config.Members[0].Domain)
+		require.Equal(t, "stakeholder.two", next.Config.Members[1].Domain)
+		require.Equal(t, configcommon.ConfigHash(consortiumBytes), next.Config.Previous)
+
+		_, err = os.Stat(filepath.Join(dir, "history", next.Config.Previous+".json"))
+		require.NoError(t, err)
+	})
+
+	t.Run("test stakeholder is not a member", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetRemoveStakeholderCmd()
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, consortiumFile,
+			flag + domainFlagName, "stakeholder.four"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stakeholder stakeholder.four is not a member of the consortium")
+	})
+
+	t.Run("test remaining members can't satisfy the policy", func(t *testing.T) {
+		os.Clearenv()
+
+		strictFile := writeFile(t, string(consortiumConfig(t, 3, keys...)))
+		defer func() { require.NoError(t, os.Remove(strictFile)) }()
+
+		cmd := GetRemoveStakeholderCmd()
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, strictFile,
+			flag + domainFlagName, "stakeholder.three"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "removing stakeholder stakeholder.three leaves 2 members, fewer than the 3"+
+			" endorsements required by the consortium policy")
+	})
+
+	t.Run("test last member", func(t *testing.T) {
+		os.Clearenv()
+
+		lastFile := writeFile(t, string(consortiumConfig(t, 0, keys[0])))
+		defer func() { require.NoError(t, os.Remove(lastFile)) }()
+
+		cmd := GetRemoveStakeholderCmd()
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, lastFile,
+			flag + domainFlagName, "stakeholder.one"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stakeholder stakeholder.one is the last member of the consortium")
+	})
+}
+
+func consortiumConfig(t *testing.T, numQueries int, keys ...ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	consortium := models.Consortium{Domain: "consortium.net",
+		Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 2419200}, NumQueries: numQueries}}
+
+	var signingKeys []jose.SigningKey
+
+	for i, key := range keys {
+		domain := []string{"stakeholder.one", "stakeholder.two", "stakeholder.three"}[i]
+
+		consortium.Members = append(consortium.Members, &models.StakeholderListElement{Domain: domain,
+			DID: "did:trustbloc:" + domain, PublicKey: models.PublicKey{JWK: json.RawMessage(jwk(t, key, true))}})
+
+		signingKeys = append(signingKeys, jose.SigningKey{Key: key, Algorithm: jose.EdDSA})
+	}
+
+	consortiumBytes, err := json.Marshal(consortium)
+	require.NoError(t, err)
+
+	jws, err := configcommon.SignConfig(consortiumBytes, signingKeys)
+	require.NoError(t, err)
+
+	return []byte(jws)
+}
+
+func jwk(t *testing.T, privateKey ed25519.PrivateKey, public bool) string {
+	t.Helper()
+
+	key := &jose.JSONWebKey{Key: privateKey, KeyID: "key1"}
+	if public {
+		key = &jose.JSONWebKey{Key: privateKey.Public(), KeyID: "key1"}
+	}
+
+	jwkBytes, err := key.MarshalJSON()
+	require.NoError(t, err)
+
+	return string(jwkBytes)
+}
+
+func writeFile(t *testing.T, data string) string {
+	t.Helper()
+
+	file, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+
+	return file.Name()
+}
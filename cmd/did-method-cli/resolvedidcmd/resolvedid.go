@@ -0,0 +1,308 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc"
+)
+
+const (
+	didURIFlagName  = "did-uri"
+	didURIEnvKey    = "DID_METHOD_CLI_DID_URI"
+	didURIFlagUsage = "DID URI. " +
+		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
+
+	domainFlagName      = "domain"
+	domainFileEnvKey    = "DID_METHOD_CLI_DOMAIN"
+	domainFileFlagUsage = "URL to the did:trustbloc consortium's domain. Defaults to the domain of the DID." +
+		" Alternatively, this can be set with the following environment variable: " + domainFileEnvKey
+
+	resolverURLFlagName  = "resolver-url"
+	resolverURLEnvKey    = "DID_METHOD_CLI_RESOLVER_URL"
+	resolverURLFlagUsage = "URL of a resolver to resolve the DID with directly, instead of discovering" +
+		" the sidetree endpoints of the consortium." +
+		" Alternatively, this can be set with the following environment variable: " + resolverURLEnvKey
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "DID_METHOD_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "DID_METHOD_CLI_TLS_CACERTS"
+
+	sidetreeReadTokenFlagName  = "sidetree-read-token"
+	sidetreeReadTokenEnvKey    = "DID_METHOD_CLI_SIDETREE_READ_TOKEN" //nolint: gosec
+	sidetreeReadTokenFlagUsage = "The sidetree read token " +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeReadTokenEnvKey
+
+	versionIDFlagName  = "version-id"
+	versionIDEnvKey    = "DID_METHOD_CLI_VERSION_ID"
+	versionIDFlagUsage = "Resolve the version of the DID document with this version ID." +
+		" Alternatively, this can be set with the following environment variable: " + versionIDEnvKey
+
+	versionTimeFlagName  = "version-time"
+	versionTimeEnvKey    = "DID_METHOD_CLI_VERSION_TIME"
+	versionTimeFlagUsage = "Resolve the version of the DID document that was valid at this time (RFC3339)." +
+		" Alternatively, this can be set with the following environment variable: " + versionTimeEnvKey
+
+	metadataFlagName  = "metadata"
+	metadataEnvKey    = "DID_METHOD_CLI_METADATA"
+	metadataFlagUsage = "Print the full DID resolution result, including resolution and document metadata," +
+		" instead of only the DID document." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + metadataEnvKey
+
+	didResolutionContext = "https://www.w3.org/ns/did-resolution/v1"
+	didLDJSON            = "application/did+ld+json"
+)
+
+type resolutionResult struct {
+	Context               string                `json:"@context"`
+	DIDDocument           json.RawMessage       `json:"didDocument"`
+	DIDResolutionMetadata didResolutionMetadata `json:"didResolutionMetadata"`
+	DIDDocumentMetadata   didDocumentMetadata   `json:"didDocumentMetadata"`
+}
+
+type didResolutionMetadata struct {
+	ContentType string `json:"contentType"`
+	Retrieved   string `json:"retrieved"`
+	Domain      string `json:"domain,omitempty"`
+	ResolverURL string `json:"resolverURL,omitempty"`
+	VersionID   string `json:"versionId,omitempty"`
+	VersionTime string `json:"versionTime,omitempty"`
+}
+
+type didDocumentMetadata struct {
+	Created *time.Time `json:"created,omitempty"`
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+type resolveParameters struct {
+	didURI      string
+	domain      string
+	resolverURL string
+	versionID   string
+	versionTime string
+	metadata    bool
+}
+
+// GetResolveDIDCmd returns the Cobra resolve did command.
+func GetResolveDIDCmd() *cobra.Command {
+	resolveDIDCmd := resolveDIDCmd()
+
+	createFlags(resolveDIDCmd)
+
+	return resolveDIDCmd
+}
+
+func resolveDIDCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resolve-did",
+		Short: "Resolve TrustBloc DID",
+		Long:  "Resolve TrustBloc DID and print the DID document",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.ApplyConfig(cmd); err != nil {
+				return err
+			}
+
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
+			params, err := getResolveParameters(cmd)
+			if err != nil {
+				return err
+			}
+
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger, err := common.GetLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			opts := []trustbloc.Option{
+				trustbloc.WithTLSConfig(tlsConfig),
+				trustbloc.WithAuthToken(cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeReadTokenFlagName,
+					sidetreeReadTokenEnvKey)),
+				trustbloc.WithLogger(logger),
+			}
+
+			if params.domain != "" {
+				opts = append(opts, trustbloc.WithDomain(params.domain))
+			}
+
+			if params.resolverURL != "" {
+				opts = append(opts, trustbloc.WithResolverURL(params.resolverURL))
+			}
+
+			resolved, err := trustbloc.New(opts...).Read(params.didURI, resolveOptions(params)...)
+			if err != nil {
+				return fmt.Errorf("failed to resolve did: %w", err)
+			}
+
+			out, err := formatResult(resolved.DIDDocument, params)
+			if err != nil {
+				return err
+			}
+
+			var result interface{}
+
+			if err := json.Unmarshal(out, &result); err != nil {
+				return fmt.Errorf("failed to unmarshal result: %w", err)
+			}
+
+			return common.PrintResult(cmd, output, result, string(out))
+		},
+	}
+}
+
+func getResolveParameters(cmd *cobra.Command) (*resolveParameters, error) {
+	didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName, didURIEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &resolveParameters{
+		didURI:      didURI,
+		domain:      cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainFileEnvKey),
+		resolverURL: cmdutils.GetUserSetOptionalVarFromString(cmd, resolverURLFlagName, resolverURLEnvKey),
+		versionID:   cmdutils.GetUserSetOptionalVarFromString(cmd, versionIDFlagName, versionIDEnvKey),
+		versionTime: cmdutils.GetUserSetOptionalVarFromString(cmd, versionTimeFlagName, versionTimeEnvKey),
+	}
+
+	if params.versionID != "" && params.versionTime != "" {
+		return nil, fmt.Errorf("only one of %s and %s can be set", versionIDFlagName, versionTimeFlagName)
+	}
+
+	if params.versionTime != "" {
+		if _, err := time.Parse(time.RFC3339, params.versionTime); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", versionTimeFlagName, err)
+		}
+	}
+
+	metadataString := cmdutils.GetUserSetOptionalVarFromString(cmd, metadataFlagName, metadataEnvKey)
+	if metadataString != "" {
+		params.metadata, err = strconv.ParseBool(metadataString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", metadataFlagName, err)
+		}
+	}
+
+	return params, nil
+}
+
+func resolveOptions(params *resolveParameters) []vdrapi.DIDMethodOption {
+	var opts []vdrapi.DIDMethodOption
+
+	if params.versionID != "" {
+		opts = append(opts, vdrapi.WithOption(trustbloc.VersionIDOpt, params.versionID))
+	}
+
+	if params.versionTime != "" {
+		opts = append(opts, vdrapi.WithOption(trustbloc.VersionTimeOpt, params.versionTime))
+	}
+
+	return opts
+}
+
+func formatResult(doc *docdid.Doc, params *resolveParameters) ([]byte, error) {
+	docBytes, err := doc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal did document: %w", err)
+	}
+
+	if !params.metadata {
+		var out bytes.Buffer
+
+		if err := json.Indent(&out, docBytes, "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to format did document: %w", err)
+		}
+
+		return out.Bytes(), nil
+	}
+
+	result := &resolutionResult{
+		Context:     didResolutionContext,
+		DIDDocument: docBytes,
+		DIDResolutionMetadata: didResolutionMetadata{
+			ContentType: didLDJSON,
+			Retrieved:   time.Now().UTC().Format(time.RFC3339),
+			Domain:      params.domain,
+			ResolverURL: params.resolverURL,
+			VersionID:   params.versionID,
+			VersionTime: params.versionTime,
+		},
+		DIDDocumentMetadata: didDocumentMetadata{Created: doc.Created, Updated: doc.Updated},
+	}
+
+	return json.MarshalIndent(result, "", "  ")
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
+	startCmd.Flags().StringP(resolverURLFlagName, "", "", resolverURLFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
+		tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
+	startCmd.Flags().StringP(sidetreeReadTokenFlagName, "", "", sidetreeReadTokenFlagUsage)
+	startCmd.Flags().StringP(versionIDFlagName, "", "", versionIDFlagUsage)
+	startCmd.Flags().StringP(versionTimeFlagName, "", "", versionTimeFlagUsage)
+	startCmd.Flags().StringP(metadataFlagName, "", "", metadataFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
+}
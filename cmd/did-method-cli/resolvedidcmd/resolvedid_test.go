@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvedidcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test did uri is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		cmd.SetArgs(domainArg())
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither did-uri (command line flag) nor "+
+			"DID_METHOD_CLI_DID_URI (environment variable) have been set.")
+	})
+}
+
+func TestInvalidArgs(t *testing.T) {
+	t.Run("test version id and version time are both set", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+versionIDFlagName, "1")
+		args = append(args, flag+versionTimeFlagName, "2020-12-01T10:00:00Z")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of version-id and version-time can be set")
+	})
+
+	t.Run("test invalid version time", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		cmd.SetArgs(append(didURIArg(), flag+versionTimeFlagName, "yesterday"))
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for version-time")
+	})
+
+	t.Run("test invalid metadata", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		cmd.SetArgs(append(didURIArg(), flag+metadataFlagName, "wrongvalue"))
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for metadata")
+	})
+}
+
+func TestResolveDID(t *testing.T) {
+	type didResolution struct {
+		Context     interface{}     `json:"@context"`
+		DIDDocument json.RawMessage `json:"didDocument"`
+	}
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bytes, err := (&did.Doc{ID: "did:ex:123", Context: []string{did.ContextV1}}).JSONBytes()
+		require.NoError(t, err)
+		b, err := json.Marshal(didResolution{Context: didResolutionContext, DIDDocument: bytes})
+		require.NoError(t, err)
+		w.Header().Set("Content-type", didLDJSON)
+		_, err = fmt.Fprint(w, string(b))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("test failed to resolve did", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		cmd.SetArgs(append(didURIArg(), resolverURLArg("wrongurl")...))
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve did")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(append(didURIArg(), resolverURLArg(serv.URL)...))
+
+		require.NoError(t, cmd.Execute())
+
+		doc, err := did.ParseDocument(out.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, "did:ex:123", doc.ID)
+	})
+
+	t.Run("success with metadata", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetResolveDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, resolverURLArg(serv.URL)...)
+		args = append(args, flag+versionIDFlagName, "1")
+		args = append(args, flag+metadataFlagName, "true")
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		result := &resolutionResult{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), result))
+		require.Equal(t, didResolutionContext, result.Context)
+		require.Equal(t, serv.URL, result.DIDResolutionMetadata.ResolverURL)
+		require.Equal(t, "1", result.DIDResolutionMetadata.VersionID)
+
+		doc, err := did.ParseDocument(result.DIDDocument)
+		require.NoError(t, err)
+		require.Equal(t, "did:ex:123", doc.ID)
+	})
+}
+
+func TestFormatResult(t *testing.T) {
+	created := time.Now().UTC().Truncate(time.Second)
+
+	out, err := formatResult(&did.Doc{ID: "did:ex:123", Context: []string{did.ContextV1}, Created: &created},
+		&resolveParameters{metadata: true, domain: "testnet", versionTime: "2020-12-01T10:00:00Z"})
+	require.NoError(t, err)
+
+	result := &resolutionResult{}
+	require.NoError(t, json.Unmarshal(out, result))
+	require.Equal(t, "testnet", result.DIDResolutionMetadata.Domain)
+	require.Equal(t, "2020-12-01T10:00:00Z", result.DIDResolutionMetadata.VersionTime)
+	require.Equal(t, didLDJSON, result.DIDResolutionMetadata.ContentType)
+	require.True(t, created.Equal(*result.DIDDocumentMetadata.Created))
+	require.Nil(t, result.DIDDocumentMetadata.Updated)
+}
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	os.Clearenv()
+
+	startCmd := GetResolveDIDCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func domainArg() []string {
+	return []string{flag + domainFlagName, "domain"}
+}
+
+func didURIArg() []string {
+	return []string{flag + didURIFlagName, "did:ex:123"}
+}
+
+func resolverURLArg(value string) []string {
+	return []string{flag + resolverURLFlagName, value}
+}
@@ -0,0 +1,381 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rotatekeyscmd
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+const (
+	didURIFlagName  = "did-uri"
+	didURIEnvKey    = "DID_METHOD_CLI_DID_URI"
+	didURIFlagUsage = "DID URI. " +
+		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
+
+	domainFlagName      = "domain"
+	domainFileEnvKey    = "DID_METHOD_CLI_DOMAIN"
+	domainFileFlagUsage = "URL to the did:trustbloc consortium's domain. " +
+		" Alternatively, this can be set with the following environment variable: " + domainFileEnvKey
+
+	sidetreeURLFlagName  = "sidetree-url"
+	sidetreeURLFlagUsage = "Comma-Separated list of sidetree url." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeURLEnvKey
+	sidetreeURLEnvKey = "DID_METHOD_CLI_SIDETREE_URL"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "DID_METHOD_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "DID_METHOD_CLI_TLS_CACERTS"
+
+	sidetreeWriteTokenFlagName  = "sidetree-write-token"
+	sidetreeWriteTokenEnvKey    = "DID_METHOD_CLI_SIDETREE_WRITE_TOKEN" //nolint: gosec
+	sidetreeWriteTokenFlagUsage = "The sidetree write token " +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeWriteTokenEnvKey
+
+	keyIDFlagName  = "key-id"
+	keyIDEnvKey    = "DID_METHOD_CLI_KEY_ID"
+	keyIDFlagUsage = "Comma-Separated list of IDs of the verification keys to rotate." +
+		" Each key is removed and replaced by a newly generated key with the same ID." +
+		" Alternatively, this can be set with the following environment variable: " + keyIDEnvKey
+
+	keyTypeFlagName  = "key-type"
+	keyTypeEnvKey    = "DID_METHOD_CLI_KEY_TYPE"
+	keyTypeFlagUsage = "Type of the generated keys." +
+		" Possible values [Ed25519] [P256]. Defaults to Ed25519 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyTypeEnvKey
+
+	purposeFlagName  = "purpose"
+	purposeEnvKey    = "DID_METHOD_CLI_PURPOSE"
+	purposeFlagUsage = "Comma-Separated list of purposes of the replacement keys." +
+		" Defaults to authentication if not set." +
+		" Alternatively, this can be set with the following environment variable: " + purposeEnvKey
+
+	outDirFlagName  = "out-dir"
+	outDirEnvKey    = "DID_METHOD_CLI_OUT_DIR"
+	outDirFlagUsage = "Directory to write the generated keys to. Existing keys are never overwritten." +
+		" Alternatively, this can be set with the following environment variable: " + outDirEnvKey
+
+	keyPasswordFlagName  = "key-password"
+	keyPasswordEnvKey    = "DID_METHOD_CLI_KEY_PASSWORD" //nolint: gosec
+	keyPasswordFlagUsage = "Password used to encrypt the generated private key PEMs. Not encrypted if not set." +
+		" Alternatively, this can be set with the following environment variable: " + keyPasswordEnvKey
+
+	signingKeyFlagName  = "signingkey"
+	signingKeyEnvKey    = "DID_METHOD_CLI_SIGNINGKEY"
+	signingKeyFlagUsage = "The private key PEM used for signing the update of the document." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyEnvKey
+
+	signingKeyFileFlagName  = "signingkey-file"
+	signingKeyFileEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_FILE"
+	signingKeyFileFlagUsage = "The file that contains the private key" +
+		" PEM used for signing the update of the document." +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyFileEnvKey
+
+	signingKeyPasswordFlagName  = "signingkey-password"
+	signingKeyPasswordEnvKey    = "DID_METHOD_CLI_SIGNINGKEY_PASSWORD" //nolint: gosec
+	signingKeyPasswordFlagUsage = "signing key pem password. " +
+		" Alternatively, this can be set with the following environment variable: " + signingKeyPasswordEnvKey
+
+	nextUpdateKeyDir = "update"
+
+	dirPermissions = 0700
+)
+
+type rotatedKeys struct {
+	opts              []update.Option
+	nextUpdateKey     crypto.PublicKey
+	nextUpdateKeyFile string
+	privateKeyFiles   map[string]string
+}
+
+// GetRotateKeysCmd returns the Cobra rotate keys command.
+func GetRotateKeysCmd() *cobra.Command {
+	rotateKeysCmd := rotateKeysCmd()
+
+	createFlags(rotateKeysCmd)
+
+	return rotateKeysCmd
+}
+
+func rotateKeysCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Rotate TrustBloc DID keys",
+		Long: "Rotate verification keys of a TrustBloc DID. The listed keys are replaced by newly generated keys" +
+			" and a new next update key is generated.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := common.ApplyDefaults(cmd, map[string]string{
+				didURIFlagName: didURIEnvKey, domainFlagName: domainFileEnvKey, sidetreeURLFlagName: sidetreeURLEnvKey})
+			if err != nil {
+				return err
+			}
+
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
+			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName,
+				didURIEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			sidetreeWriteToken := cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
+				sidetreeWriteTokenEnvKey)
+
+			domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName,
+				domainFileEnvKey)
+
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			result := common.NewResult("rotate-keys", didURI)
+
+			logger, err := common.GetLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			fipsOption, err := common.GetFIPSOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			debugOption, err := common.GetDebugOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			client := did.New(did.WithAuthToken(sidetreeWriteToken),
+				did.WithTLSConfig(tlsConfig),
+				did.WithOperationCallback(result.SetOperationInfo),
+				fipsOption,
+				debugOption,
+				did.WithLogger(logger))
+
+			keys, err := rotateKeysOption(cmd, common.InfoWriter(cmd, output))
+			if err != nil {
+				return err
+			}
+
+			err = client.UpdateDID(didURI, domain, keys.opts...)
+			if err != nil {
+				return fmt.Errorf("failed to rotate keys: %w", err)
+			}
+
+			result.Keys = keys.privateKeyFiles
+
+			err = common.PrintResult(cmd, output, result, fmt.Sprintf(
+				"successfully rotated keys of DID %s\nsign the next update with %s", didURI, keys.nextUpdateKeyFile))
+			if err != nil {
+				return err
+			}
+
+			return writeState(cmd, didURI, domain, keys)
+		},
+	}
+}
+
+func writeState(cmd *cobra.Command, didURI, domain string, keys *rotatedKeys) error {
+	state := common.NewState("rotate-keys", didURI, domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey))
+
+	state.Keys[signingKeyFileFlagName] = keys.nextUpdateKeyFile
+
+	if err := state.SetCommitments(keys.nextUpdateKey, nil); err != nil {
+		return err
+	}
+
+	return common.WriteStateOut(cmd, state)
+}
+
+func getSidetreeURL(cmd *cobra.Command) []update.Option {
+	var opts []update.Option
+
+	sidetreeURL := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName,
+		sidetreeURLEnvKey)
+
+	for _, v := range sidetreeURL {
+		opts = append(opts, update.WithSidetreeEndpoint(v))
+	}
+
+	return opts
+}
+
+// rotateKeysOption generates the replacement and next update keys, writes them to the out dir and returns
+// the update options. The keys are written before the update is sent, so that they are never lost.
+func rotateKeysOption(cmd *cobra.Command, out io.Writer) (*rotatedKeys, error) {
+	keyIDs := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, keyIDFlagName, keyIDEnvKey)
+	if len(keyIDs) == 0 {
+		return nil, fmt.Errorf("at least one key to rotate (--%s) is required", keyIDFlagName)
+	}
+
+	outDir, err := cmdutils.GetUserSetVarFromString(cmd, outDirFlagName, outDirEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey, err := common.GetSigningKey(cmd, signingKeyFlagName, signingKeyEnvKey, signingKeyFileFlagName,
+		signingKeyFileEnvKey, signingKeyPasswordFlagName, signingKeyPasswordEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyType := cmdutils.GetUserSetOptionalVarFromString(cmd, keyTypeFlagName, keyTypeEnvKey)
+	if keyType == "" {
+		keyType = doc.Ed25519KeyType
+	}
+
+	password := []byte(cmdutils.GetUserSetOptionalVarFromString(cmd, keyPasswordFlagName, keyPasswordEnvKey))
+
+	if err := os.MkdirAll(outDir, dirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create out dir: %w", err)
+	}
+
+	opts, err := replacementKeys(out, outDir, keyIDs, keyType, password,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, purposeFlagName, purposeEnvKey))
+	if err != nil {
+		return nil, err
+	}
+
+	updateKeyDir := filepath.Join(outDir, nextUpdateKeyDir)
+
+	nextUpdateKey, err := common.GeneratePEMKeyPair(out, updateKeyDir, keyType, password)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, update.WithSigningKey(signingKey), update.WithNextUpdatePublicKey(nextUpdateKey))
+	opts = append(opts, getSidetreeURL(cmd)...)
+
+	keys := &rotatedKeys{
+		opts:              opts,
+		nextUpdateKey:     nextUpdateKey,
+		nextUpdateKeyFile: filepath.Join(updateKeyDir, common.PrivateKeyFileName),
+		privateKeyFiles:   map[string]string{},
+	}
+
+	for _, id := range keyIDs {
+		keys.privateKeyFiles[id] = common.VerificationPrivateKeyFile(outDir, id)
+	}
+
+	keys.privateKeyFiles[nextUpdateKeyDir] = keys.nextUpdateKeyFile
+
+	return keys, nil
+}
+
+func replacementKeys(out io.Writer, outDir string, keyIDs []string, keyType string, password []byte,
+	purposes []string) ([]update.Option, error) {
+	if len(purposes) == 0 {
+		purposes = []string{doc.KeyPurposeAuthentication}
+	}
+
+	var opts []update.Option
+
+	for _, id := range keyIDs {
+		publicKey, err := common.GenerateVerificationKey(out, outDir, id, keyType, password)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey.Purposes = purposes
+
+		publicKeys, err := common.GetPublicKeys([]common.PublicKey{*publicKey})
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, update.WithReplacePublicKey(&publicKeys[0]))
+	}
+
+	return opts, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
+		tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
+	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
+	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
+	startCmd.Flags().StringArrayP(keyIDFlagName, "", []string{}, keyIDFlagUsage)
+	startCmd.Flags().StringP(keyTypeFlagName, "", "", keyTypeFlagUsage)
+	startCmd.Flags().StringArrayP(purposeFlagName, "", []string{}, purposeFlagUsage)
+	startCmd.Flags().StringP(outDirFlagName, "", "", outDirFlagUsage)
+	startCmd.Flags().StringP(keyPasswordFlagName, "", "", keyPasswordFlagUsage)
+	startCmd.Flags().StringP(signingKeyFlagName, "", "", signingKeyFlagUsage)
+	startCmd.Flags().StringP(signingKeyFileFlagName, "", "", signingKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
+	startCmd.Flags().StringP(common.StateOutFlagName, "", "", common.StateOutFlagUsage)
+	startCmd.Flags().StringP(common.StateInFlagName, "", "", common.StateInFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.FIPSFlagName, "", "", common.FIPSFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signconfigcmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+)
+
+const (
+	configFileFlagName  = "config-file"
+	configFileEnvKey    = "DID_METHOD_CLI_CONFIG_FILE"
+	configFileFlagUsage = "The consortium or stakeholder config file to sign: the unsigned config JSON, or the" +
+		" config JWS already signed by other stakeholders." +
+		" Alternatively, this can be set with the following environment variable: " + configFileEnvKey
+
+	outputFileFlagName  = "output-file"
+	outputFileEnvKey    = "DID_METHOD_CLI_OUTPUT_FILE"
+	outputFileFlagUsage = "File to write the signed config JWS to. The signed config JWS is printed if not set." +
+		" Alternatively, this can be set with the following environment variable: " + outputFileEnvKey
+
+	configFilePermissions = 0644
+)
+
+// GetSignConfigCmd returns the Cobra sign config command.
+func GetSignConfigCmd() *cobra.Command {
+	signConfigCmd := createSignConfigCmd()
+
+	createFlags(signConfigCmd)
+
+	return signConfigCmd
+}
+
+func createSignConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sign-config",
+		Short: "Sign a consortium or stakeholder config",
+		Long: "Add the signature of a stakeholder to a consortium or stakeholder config, so that the stakeholders" +
+			" can endorse a config one after the other",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFile, err := cmdutils.GetUserSetVarFromString(cmd, configFileFlagName, configFileEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			config, err := ioutil.ReadFile(filepath.Clean(configFile))
+			if err != nil {
+				return fmt.Errorf("failed to read config file '%s' : %w", configFile, err)
+			}
+
+			signingKey, err := configcommon.GetSigningKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			jws, err := configcommon.AddSignature(config, signingKey)
+			if err != nil {
+				return err
+			}
+
+			outputFile := cmdutils.GetUserSetOptionalVarFromString(cmd, outputFileFlagName, outputFileEnvKey)
+			if outputFile == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), jws)
+
+				return nil
+			}
+
+			if err := ioutil.WriteFile(outputFile, []byte(jws), configFilePermissions); err != nil {
+				return fmt.Errorf("failed to write file %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(configFileFlagName, "", "", configFileFlagUsage)
+	startCmd.Flags().StringP(outputFileFlagName, "", "", outputFileFlagUsage)
+	configcommon.AddSigningKeyFlags(startCmd)
+}
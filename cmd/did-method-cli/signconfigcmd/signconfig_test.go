@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package signconfigcmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+)
+
+const (
+	flag = "--"
+
+	configData = `{"domain":"consortium.net","policy":{"cache":{"maxAge":2419200},"numQueries":2},"members":[]}`
+)
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test config file is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSignConfigCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither config-file (command line flag) nor "+
+			"DID_METHOD_CLI_CONFIG_FILE (environment variable) have been set.")
+	})
+
+	t.Run("test signing key is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetSignConfigCmd()
+
+		configFile := writeFile(t, configData)
+		defer func() { require.NoError(t, os.Remove(configFile)) }()
+
+		cmd.SetArgs([]string{flag + configFileFlagName, configFile})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "either key (--signingkey) or key file (--signingkey-file) is required")
+	})
+}
+
+func TestSignConfig(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pub2, priv2, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	configFile := writeFile(t, configData)
+	defer func() { require.NoError(t, os.Remove(configFile)) }()
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	signedFile := filepath.Join(dir, "signed.json")
+
+	t.Run("test sign and add a second signature", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetSignConfigCmd()
+		cmd.SetArgs([]string{flag + configFileFlagName, configFile, flag + outputFileFlagName, signedFile,
+			flag + common.SigningKeyJWKFlagName, jwk(t, priv1)})
+		require.NoError(t, cmd.Execute())
+
+		cmd = GetSignConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + configFileFlagName, signedFile, flag + common.SigningKeyJWKFlagName, jwk(t, priv2)})
+		require.NoError(t, cmd.Execute())
+
+		jws, err := jose.ParseSigned(strings.TrimSpace(out.String()))
+		require.NoError(t, err)
+		require.Len(t, jws.Signatures, 2)
+
+		for _, key := range []ed25519.PublicKey{pub1, pub2} {
+			_, _, payload, err := jws.VerifyMulti(key)
+			require.NoError(t, err)
+			require.Equal(t, configData, string(payload))
+		}
+	})
+
+	t.Run("test config already signed with the key", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetSignConfigCmd()
+		cmd.SetArgs([]string{flag + configFileFlagName, signedFile, flag + common.SigningKeyJWKFlagName, jwk(t, priv1)})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "config is already signed with this key")
+	})
+
+	t.Run("test config file not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetSignConfigCmd()
+		cmd.SetArgs([]string{flag + configFileFlagName, "wrong", flag + common.SigningKeyJWKFlagName, jwk(t, priv1)})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read config file 'wrong'")
+	})
+}
+
+func jwk(t *testing.T, privateKey ed25519.PrivateKey) string {
+	t.Helper()
+
+	jwkBytes, err := (&jose.JSONWebKey{Key: privateKey}).MarshalJSON()
+	require.NoError(t, err)
+
+	return string(jwkBytes)
+}
+
+func writeFile(t *testing.T, data string) string {
+	t.Helper()
+
+	file, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+
+	return file.Name()
+}
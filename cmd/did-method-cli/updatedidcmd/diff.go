@@ -0,0 +1,334 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package updatedidcmd
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+const (
+	diffFlagName  = "diff"
+	diffEnvKey    = "DID_METHOD_CLI_DIFF"
+	diffFlagUsage = "Resolve the current document, apply the update to it locally and show the difference," +
+		" then ask for confirmation before submitting the update." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + diffEnvKey
+
+	confirmFlagName  = "yes"
+	confirmEnvKey    = "DID_METHOD_CLI_YES"
+	confirmFlagUsage = "Submit the update without asking for confirmation after showing the --diff." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + confirmEnvKey
+
+	verificationMethodProperty = "verificationMethod"
+	publicKeyProperty          = "publicKey"
+	serviceProperty            = "service"
+	idProperty                 = "id"
+	purposesProperty           = "purposes"
+	diffContextLines           = 3
+)
+
+// purposeProperties are the document properties that reference verification methods by purpose
+var purposeProperties = []string{ //nolint: gochecknoglobals
+	doc.KeyPurposeAuthentication, doc.KeyPurposeAssertionMethod, doc.KeyPurposeKeyAgreement,
+	doc.KeyPurposeCapabilityDelegation, doc.KeyPurposeCapabilityInvocation,
+}
+
+// getDiff returns whether --diff is set
+func getDiff(cmd *cobra.Command) (bool, error) {
+	diffString := cmdutils.GetUserSetOptionalVarFromString(cmd, diffFlagName, diffEnvKey)
+	if diffString == "" {
+		return false, nil
+	}
+
+	diff, err := strconv.ParseBool(diffString)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", diffFlagName, err)
+	}
+
+	return diff, nil
+}
+
+// previewUpdate prints the difference between the current and the updated document on stderr and asks the user
+// to confirm it, unless confirm is false (as for --dry-run) or --yes is set
+func previewUpdate(cmd *cobra.Command, didURI, domain string, tlsConfig *tls.Config, authToken string,
+	confirm bool) error {
+	current, err := common.NewResolver(domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey), tlsConfig,
+		authToken).Read(didURI)
+	if err != nil {
+		return fmt.Errorf("failed to resolve did: %w", err)
+	}
+
+	currentBytes, err := current.DIDDocument.JSONBytes()
+	if err != nil {
+		return err
+	}
+
+	currentDoc := make(map[string]interface{})
+	if err := json.Unmarshal(currentBytes, &currentDoc); err != nil {
+		return fmt.Errorf("failed to unmarshal did document: %w", err)
+	}
+
+	updatedDoc := make(map[string]interface{})
+	if err := json.Unmarshal(currentBytes, &updatedDoc); err != nil {
+		return fmt.Errorf("failed to unmarshal did document: %w", err)
+	}
+
+	if err := applyPatches(cmd, updatedDoc, didURI); err != nil {
+		return err
+	}
+
+	diff, err := documentDiff(currentDoc, updatedDoc)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "The update does not change the document of DID %s\n", didURI)
+	} else {
+		fmt.Fprint(cmd.ErrOrStderr(), diff)
+	}
+
+	if !confirm {
+		return nil
+	}
+
+	return confirmUpdate(cmd, didURI)
+}
+
+// applyPatches applies the public keys and services to add and to remove to the document, the way the
+// sidetree patches of the update do
+func applyPatches(cmd *cobra.Command, document map[string]interface{}, didURI string) error {
+	methodProperty := verificationMethodProperty
+	if _, ok := document[methodProperty]; !ok {
+		if _, ok := document[publicKeyProperty]; ok {
+			methodProperty = publicKeyProperty
+		}
+	}
+
+	for _, id := range cmdutils.GetUserSetOptionalVarFromArrayString(cmd, removePublicKeyIDFlagName,
+		removePublicKeyIDEnvKey) {
+		removePublicKey(document, methodProperty, id)
+	}
+
+	for _, id := range cmdutils.GetUserSetOptionalVarFromArrayString(cmd, removeServiceIDFlagName,
+		removeServiceIDEnvKey) {
+		document[serviceProperty] = removeByID(document[serviceProperty], id)
+	}
+
+	if err := addPublicKeys(cmd, document, methodProperty, didURI); err != nil {
+		return err
+	}
+
+	return addServices(cmd, document)
+}
+
+func addPublicKeys(cmd *cobra.Command, document map[string]interface{}, methodProperty, didURI string) error {
+	publicKeyFile := cmdutils.GetUserSetOptionalVarFromString(cmd, addPublicKeyFileFlagName, addPublicKeyFileEnvKey)
+	if publicKeyFile == "" {
+		return nil
+	}
+
+	publicKeys, err := common.GetPublicKeysFromFile(publicKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to get public keys from file %w", err)
+	}
+
+	rawPublicKeys, err := doc.PopulateRawPublicKeys(publicKeys)
+	if err != nil {
+		return err
+	}
+
+	for _, rawPublicKey := range rawPublicKeys {
+		addPublicKey(document, methodProperty, didURI, rawPublicKey)
+	}
+
+	return nil
+}
+
+func addServices(cmd *cobra.Command, document map[string]interface{}) error {
+	serviceFile := cmdutils.GetUserSetOptionalVarFromString(cmd, addServiceFileFlagName, addServiceFileEnvKey)
+	if serviceFile == "" {
+		return nil
+	}
+
+	services, err := common.GetServices(serviceFile)
+	if err != nil {
+		return fmt.Errorf("failed to get services from file %w", err)
+	}
+
+	for i := range services {
+		service := map[string]interface{}{}
+
+		for k, v := range services[i].Properties {
+			service[k] = v
+		}
+
+		service[idProperty] = "#" + services[i].ID
+		service["type"] = services[i].Type
+		service["serviceEndpoint"] = services[i].ServiceEndpoint
+
+		// adding a service with an existing ID replaces it
+		document[serviceProperty] = append(asList(removeByID(document[serviceProperty], services[i].ID)), service)
+	}
+
+	return nil
+}
+
+func addPublicKey(document map[string]interface{}, methodProperty, didURI string,
+	rawPublicKey map[string]interface{}) {
+	id := fmt.Sprint(rawPublicKey[idProperty])
+
+	// adding a public key with an existing ID replaces it
+	removePublicKey(document, methodProperty, id)
+
+	method := map[string]interface{}{}
+
+	for k, v := range rawPublicKey {
+		if k != purposesProperty {
+			method[k] = v
+		}
+	}
+
+	method[idProperty] = "#" + id
+	method["controller"] = didURI
+
+	document[methodProperty] = append(asList(document[methodProperty]), method)
+
+	purposes, ok := rawPublicKey[purposesProperty].([]string)
+	if !ok {
+		return
+	}
+
+	for _, purpose := range purposes {
+		document[purpose] = append(asList(document[purpose]), "#"+id)
+	}
+}
+
+func removePublicKey(document map[string]interface{}, methodProperty, id string) {
+	document[methodProperty] = removeByID(document[methodProperty], id)
+
+	for _, purpose := range purposeProperties {
+		document[purpose] = removeByID(document[purpose], id)
+	}
+}
+
+// removeByID removes the entries with this ID, or fragment, from a list of objects or references
+func removeByID(value interface{}, id string) interface{} {
+	list := asList(value)
+	if list == nil {
+		return value
+	}
+
+	var kept []interface{}
+
+	for _, entry := range list {
+		entryID, ok := entry.(string)
+		if !ok {
+			if object, ok := entry.(map[string]interface{}); ok {
+				entryID = fmt.Sprint(object[idProperty])
+			}
+		}
+
+		if entryID == id || strings.HasSuffix(entryID, "#"+strings.TrimPrefix(id, "#")) {
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	return kept
+}
+
+func asList(value interface{}) []interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	return list
+}
+
+// documentDiff returns the unified diff of the indented JSON of the two documents, or "" if they are equal
+func documentDiff(current, updated map[string]interface{}) (string, error) {
+	removeEmpty(current)
+	removeEmpty(updated)
+
+	currentJSON, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal did document: %w", err)
+	}
+
+	updatedJSON, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal did document: %w", err)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(currentJSON) + "\n"),
+		B:        difflib.SplitLines(string(updatedJSON) + "\n"),
+		FromFile: "current",
+		ToFile:   "updated",
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to diff did documents: %w", err)
+	}
+
+	return diff, nil
+}
+
+// removeEmpty removes the properties left empty by the patches, which are omitted from resolved documents
+func removeEmpty(document map[string]interface{}) {
+	for k, v := range document {
+		if list, ok := v.([]interface{}); v == nil || (ok && len(list) == 0) {
+			delete(document, k)
+		}
+	}
+}
+
+// confirmUpdate asks the user to confirm the update on stdin, unless --yes is set
+func confirmUpdate(cmd *cobra.Command, didURI string) error {
+	confirmString := cmdutils.GetUserSetOptionalVarFromString(cmd, confirmFlagName, confirmEnvKey)
+
+	if confirmString != "" {
+		confirmed, err := strconv.ParseBool(confirmString)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", confirmFlagName, err)
+		}
+
+		if confirmed {
+			return nil
+		}
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Submit the update of DID %s? [y/N]: ", didURI)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return fmt.Errorf("update of DID %s was not confirmed", didURI)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package updatedidcmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatches(t *testing.T) {
+	servicesFile, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	_, err = servicesFile.WriteString(servicesData)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.Remove(servicesFile.Name())) }()
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		require.NoError(t, cmd.Flags().Set(removePublicKeyIDFlagName, "key1"))
+		require.NoError(t, cmd.Flags().Set(removeServiceIDFlagName, "svc3"))
+		require.NoError(t, cmd.Flags().Set(addServiceFileFlagName, servicesFile.Name()))
+
+		document := map[string]interface{}{
+			"verificationMethod": []interface{}{
+				map[string]interface{}{"id": "did:ex:123#key1"},
+				map[string]interface{}{"id": "did:ex:123#key2"},
+			},
+			"authentication": []interface{}{"did:ex:123#key1", "did:ex:123#key2"},
+			"service": []interface{}{
+				map[string]interface{}{"id": "did:ex:123#svc1", "type": "old"},
+				map[string]interface{}{"id": "did:ex:123#svc3"},
+			},
+		}
+
+		require.NoError(t, applyPatches(cmd, document, "did:ex:123"))
+
+		require.Equal(t, []interface{}{map[string]interface{}{"id": "did:ex:123#key2"}}, document["verificationMethod"])
+		require.Equal(t, []interface{}{"did:ex:123#key2"}, document["authentication"])
+
+		services := document["service"].([]interface{})
+		require.Len(t, services, 2)
+		require.Equal(t, "#svc1", services[0].(map[string]interface{})["id"])
+		require.Equal(t, "type1", services[0].(map[string]interface{})["type"])
+		require.Equal(t, "#svc2", services[1].(map[string]interface{})["id"])
+	})
+
+	t.Run("test public key file not found", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		require.NoError(t, cmd.Flags().Set(addPublicKeyFileFlagName, "./wrong"))
+
+		err := applyPatches(cmd, map[string]interface{}{}, "did:ex:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get public keys from file")
+	})
+}
+
+func TestPreviewUpdate(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		docBytes, err := (&did.Doc{ID: "did:ex:123", Context: []string{did.ContextV1},
+			Service: []did.Service{{ID: "did:ex:123#svc1", Type: "type1", ServiceEndpoint: "https://example.com"}}}).JSONBytes()
+		require.NoError(t, err)
+
+		b, err := json.Marshal(map[string]interface{}{
+			"@context":    "https://www.w3.org/ns/did-resolution/v1",
+			"didDocument": json.RawMessage(docBytes),
+		})
+		require.NoError(t, err)
+
+		w.Header().Set("Content-type", "application/did+ld+json")
+		_, err = fmt.Fprint(w, string(b))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	t.Run("test update confirmed", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+		require.NoError(t, cmd.Flags().Set(sidetreeURLFlagName, serv.URL))
+		require.NoError(t, cmd.Flags().Set(removeServiceIDFlagName, "svc1"))
+
+		errOut := &bytes.Buffer{}
+		cmd.SetErr(errOut)
+		cmd.SetIn(strings.NewReader("y\n"))
+
+		require.NoError(t, previewUpdate(cmd, "did:ex:123", "", tlsConfig, "", true))
+		require.Contains(t, errOut.String(), "--- current\n+++ updated\n")
+		require.Contains(t, errOut.String(), `-      "id": "did:ex:123#svc1",`)
+		require.Contains(t, errOut.String(), "Submit the update of DID did:ex:123? [y/N]: ")
+	})
+
+	t.Run("test update not confirmed", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+		require.NoError(t, cmd.Flags().Set(sidetreeURLFlagName, serv.URL))
+
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader("\n"))
+
+		err := previewUpdate(cmd, "did:ex:123", "", tlsConfig, "", true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update of DID did:ex:123 was not confirmed")
+	})
+
+	t.Run("test no change without confirmation", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+		require.NoError(t, cmd.Flags().Set(sidetreeURLFlagName, serv.URL))
+
+		errOut := &bytes.Buffer{}
+		cmd.SetErr(errOut)
+
+		require.NoError(t, previewUpdate(cmd, "did:ex:123", "", tlsConfig, "", false))
+		require.Equal(t, "The update does not change the document of DID did:ex:123\n", errOut.String())
+	})
+
+	t.Run("test update confirmed with yes", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+		require.NoError(t, cmd.Flags().Set(sidetreeURLFlagName, serv.URL))
+		require.NoError(t, cmd.Flags().Set(confirmFlagName, "true"))
+
+		cmd.SetErr(&bytes.Buffer{})
+
+		require.NoError(t, previewUpdate(cmd, "did:ex:123", "", tlsConfig, "", true))
+	})
+
+	t.Run("test failed to resolve did", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+		require.NoError(t, cmd.Flags().Set(sidetreeURLFlagName, "wrongurl"))
+
+		err := previewUpdate(cmd, "did:ex:123", "", tlsConfig, "", true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve did")
+	})
+}
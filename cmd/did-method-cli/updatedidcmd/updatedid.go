@@ -8,9 +8,11 @@ package updatedidcmd
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"strconv"
 
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/spf13/cobra"
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
 	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
@@ -18,6 +20,7 @@ import (
 	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
 )
 
 const (
@@ -115,11 +118,22 @@ func updateDIDCmd() *cobra.Command {
 		Short: "Update TrustBloc DID",
 		Long:  "Update TrustBloc DID",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			err := common.ApplyDefaults(cmd, map[string]string{
+				didURIFlagName: didURIEnvKey, domainFlagName: domainFileEnvKey, sidetreeURLFlagName: sidetreeURLEnvKey})
+			if err != nil {
+				return err
+			}
+
 			rootCAs, err := getRootCAs(cmd)
 			if err != nil {
 				return err
 			}
 
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
 			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName,
 				didURIEnvKey, false)
 			if err != nil {
@@ -132,26 +146,183 @@ func updateDIDCmd() *cobra.Command {
 			domain := cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName,
 				domainFileEnvKey)
 
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			result := common.NewResult("update", didURI)
+
+			dryRun, err := common.GetDryRun(cmd)
+			if err != nil {
+				return err
+			}
+
+			waitConfig, err := common.GetWaitConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			diff, err := getDiff(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger, err := common.GetLogger(cmd)
+			if err != nil {
+				return err
+			}
+
+			fipsOption, err := common.GetFIPSOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			debugOption, err := common.GetDebugOption(cmd)
+			if err != nil {
+				return err
+			}
+
+			didWebPath, didWebOption := common.GetDIDWeb(cmd)
+
 			client := did.New(did.WithAuthToken(sidetreeWriteToken),
-				did.WithTLSConfig(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}))
+				did.WithTLSConfig(tlsConfig),
+				did.WithOperationCallback(result.SetOperationInfo),
+				did.WithDryRun(common.DryRunCallback(cmd, dryRun)),
+				fipsOption,
+				debugOption,
+				didWebOption,
+				did.WithLogger(logger))
+
+			ks, keySet, err := common.GetKeySet(cmd)
+			if err != nil {
+				return err
+			}
 
-			opts, err := updateDIDOption(cmd)
+			opts, err := updateDIDOption(cmd, keySet)
 			if err != nil {
 				return err
 			}
 
+			didWebOpts, err := getDIDWeb(cmd, didWebPath)
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, didWebOpts...)
+
+			if diff {
+				if err := previewUpdate(cmd, didURI, domain, tlsConfig, sidetreeWriteToken, !dryRun); err != nil {
+					return err
+				}
+			}
+
 			err = client.UpdateDID(didURI, domain, opts...)
+			if errors.Is(err, did.ErrDryRun) {
+				return nil
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to update did: %w", err)
 			}
 
-			fmt.Printf("successfully updated DID %s", didURI)
+			err = common.PrintResult(cmd, output, result, fmt.Sprintf("successfully updated DID %s", didURI))
+			if err != nil {
+				return err
+			}
 
-			return nil
+			if err := writeState(cmd, didURI, domain, keySet); err != nil {
+				return err
+			}
+
+			if err := ks.Commit(keySet, didURI, keystore.PurposeUpdate); err != nil {
+				return err
+			}
+
+			return waitForUpdate(cmd, waitConfig, didURI, domain, tlsConfig, sidetreeWriteToken)
 		},
 	}
 }
 
+// waitForUpdate blocks until the resolved document has the added public keys and services, and not the
+// removed ones, if --wait is set
+func waitForUpdate(cmd *cobra.Command, waitConfig *common.WaitConfig, didURI, domain string, tlsConfig *tls.Config,
+	authToken string) error {
+	if waitConfig == nil {
+		return nil
+	}
+
+	var addedPublicKeys, addedServices []string
+
+	if publicKeyFile := cmdutils.GetUserSetOptionalVarFromString(cmd, addPublicKeyFileFlagName,
+		addPublicKeyFileEnvKey); publicKeyFile != "" {
+		publicKeys, err := common.GetPublicKeysFromFile(publicKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to get public keys from file %w", err)
+		}
+
+		for i := range publicKeys {
+			addedPublicKeys = append(addedPublicKeys, publicKeys[i].ID)
+		}
+	}
+
+	if serviceFile := cmdutils.GetUserSetOptionalVarFromString(cmd, addServiceFileFlagName,
+		addServiceFileEnvKey); serviceFile != "" {
+		services, err := common.GetServices(serviceFile)
+		if err != nil {
+			return fmt.Errorf("failed to get services from file %w", err)
+		}
+
+		for i := range services {
+			addedServices = append(addedServices, services[i].ID)
+		}
+	}
+
+	removedPublicKeys := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, removePublicKeyIDFlagName,
+		removePublicKeyIDEnvKey)
+	removedServices := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, removeServiceIDFlagName,
+		removeServiceIDEnvKey)
+
+	return common.WaitForDID(waitConfig, didURI, domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey), tlsConfig,
+		authToken, func(doc *docdid.Doc) bool {
+			return hasAll(doc, addedPublicKeys, common.HasVerificationMethod, true) &&
+				hasAll(doc, removedPublicKeys, common.HasVerificationMethod, false) &&
+				hasAll(doc, addedServices, common.HasService, true) &&
+				hasAll(doc, removedServices, common.HasService, false)
+		})
+}
+
+func hasAll(doc *docdid.Doc, ids []string, has func(doc *docdid.Doc, id string) bool, expected bool) bool {
+	for _, id := range ids {
+		if has(doc, id) != expected {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeState(cmd *cobra.Command, didURI, domain string, keySet *keystore.KeySet) error {
+	state := common.NewState("update", didURI, domain,
+		cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey))
+
+	state.AddKeyReference(cmd, signingKeyFileFlagName, signingKeyFileEnvKey)
+	state.AddKeyReference(cmd, nextUpdateKeyFileFlagName, nextUpdateKeyFileEnvKey)
+
+	nextUpdateKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeNextUpdate, nextUpdateKeyFlagName,
+		nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName, nextUpdateKeyFileEnvKey)
+	if err != nil {
+		return err
+	}
+
+	if err := state.SetCommitments(nextUpdateKey, nil); err != nil {
+		return err
+	}
+
+	return common.WriteStateOut(cmd, state)
+}
+
 func getSidetreeURL(cmd *cobra.Command) []update.Option {
 	var opts []update.Option
 
@@ -165,23 +336,23 @@ func getSidetreeURL(cmd *cobra.Command) []update.Option {
 	return opts
 }
 
-func updateDIDOption(cmd *cobra.Command) ([]update.Option, error) {
+func updateDIDOption(cmd *cobra.Command, keySet *keystore.KeySet) ([]update.Option, error) {
 	opts, err := getPublicKeys(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	signingKey, err := common.GetKey(cmd, signingKeyFlagName, signingKeyEnvKey, signingKeyFileFlagName,
-		signingKeyFileEnvKey, []byte(cmdutils.GetUserSetOptionalVarFromString(cmd, signingKeyPasswordFlagName,
-			signingKeyPasswordEnvKey)), true)
+	signingKey, err := common.GetSigningKeyFromKeySet(cmd, keySet, keystore.PurposeUpdate, signingKeyFlagName,
+		signingKeyEnvKey, signingKeyFileFlagName, signingKeyFileEnvKey, signingKeyPasswordFlagName,
+		signingKeyPasswordEnvKey)
 	if err != nil {
 		return nil, err
 	}
 
 	opts = append(opts, update.WithSigningKey(signingKey))
 
-	nextUpdateKey, err := common.GetKey(cmd, nextUpdateKeyFlagName, nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName,
-		nextUpdateKeyFileEnvKey, nil, false)
+	nextUpdateKey, err := common.GetPublicKey(cmd, keySet, keystore.PurposeNextUpdate, nextUpdateKeyFlagName,
+		nextUpdateKeyEnvKey, nextUpdateKeyFileFlagName, nextUpdateKeyFileEnvKey)
 	if err != nil {
 		return nil, err
 	}
@@ -204,6 +375,20 @@ func updateDIDOption(cmd *cobra.Command) ([]update.Option, error) {
 	return opts, nil
 }
 
+// getDIDWeb returns the option publishing the did:web document of the updated DID for the domain path, if set
+func getDIDWeb(cmd *cobra.Command, didWebPath string) ([]update.Option, error) {
+	if didWebPath == "" {
+		return nil, nil
+	}
+
+	attempts, interval, err := common.GetDIDWebWait(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return []update.Option{update.WithDIDWeb(didWebPath, attempts, interval)}, nil
+}
+
 func getServices(cmd *cobra.Command) ([]update.Option, error) {
 	serviceFile := cmdutils.GetUserSetOptionalVarFromString(cmd, addServiceFileFlagName,
 		addServiceFileEnvKey)
@@ -297,14 +482,38 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "",
 		tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
 	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
 	startCmd.Flags().StringP(addPublicKeyFileFlagName, "", "", addPublicKeyFileFlagUsage)
 	startCmd.Flags().StringP(addServiceFileFlagName, "", "", addServiceFlagUsage)
 	startCmd.Flags().StringP(signingKeyFlagName, "", "", signingKeyFlagUsage)
 	startCmd.Flags().StringP(signingKeyFileFlagName, "", "", signingKeyFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFlagName, "", "", common.SigningKeyJWKFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyJWKFileFlagName, "", "", common.SigningKeyJWKFileFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyEnvFlagName, "", "", common.SigningKeyEnvFlagUsage)
+	startCmd.Flags().StringP(common.SigningKeyPasswordEnvFlagName, "", "", common.SigningKeyPasswordEnvFlagUsage)
+	common.AddPKCS11Flags(startCmd)
+	common.AddAWSKMSFlags(startCmd)
+	common.AddAzureKeyVaultFlags(startCmd)
+	common.AddGCPKMSFlags(startCmd)
+	common.AddVaultFlags(startCmd)
+	common.AddSSHAgentFlags(startCmd)
+	common.AddPIVFlags(startCmd)
+	common.AddKeyAliasFlags(startCmd)
 	startCmd.Flags().StringP(nextUpdateKeyFlagName, "", "", nextUpdateKeyFlagUsage)
 	startCmd.Flags().StringP(nextUpdateKeyFileFlagName, "", "", nextUpdateKeyFileFlagUsage)
 	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
+	startCmd.Flags().StringP(common.StateOutFlagName, "", "", common.StateOutFlagUsage)
+	startCmd.Flags().StringP(common.StateInFlagName, "", "", common.StateInFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.DryRunFlagName, "", "", common.DryRunFlagUsage)
+	startCmd.Flags().StringP(common.FIPSFlagName, "", "", common.FIPSFlagUsage)
+	common.AddWaitFlags(startCmd)
+	common.AddDIDWebFlags(startCmd)
+	startCmd.Flags().StringP(diffFlagName, "", "", diffFlagUsage)
+	startCmd.Flags().StringP(confirmFlagName, "", "", confirmFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
 	startCmd.Flags().StringArrayP(removePublicKeyIDFlagName, "", []string{}, removePublicKeyIDFlagUsage)
 	startCmd.Flags().StringArrayP(removeServiceIDFlagName, "", []string{}, removeServiceIDFlagUsage)
 	startCmd.Flags().StringP(signingKeyPasswordFlagName, "", "", signingKeyPasswordFlagUsage)
@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package updatedidcmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,6 +16,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
 )
 
 const (
@@ -267,8 +271,8 @@ func TestUpdateDID(t *testing.T) {
 		args = append(args, signingKeyFileFlagNameArg(privateKeyFile.Name())...)
 		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile.Name())...)
 		args = append(args, addServicesFileArg(servicesFile.Name())...)
-		args = append(args, removeServiceIDArg("svc1")...)
-		args = append(args, removePublicKeyIDArg("key1")...)
+		args = append(args, removeServiceIDArg("svc3")...)
+		args = append(args, removePublicKeyIDArg("key3")...)
 		args = append(args, signingKeyPasswordArg()...)
 		args = append(args, addPublicKeyFileArg(file.Name())...)
 
@@ -277,6 +281,175 @@ func TestUpdateDID(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("test success with json output", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyFile.Name())...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile.Name())...)
+		args = append(args, removeServiceIDArg("svc1")...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, flag+common.OutputFlagName, common.OutputJSON)
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		result := &common.Result{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), result))
+		require.Equal(t, "update", result.Operation)
+		require.Equal(t, "did:ex:123", result.DID)
+		require.Equal(t, serv.URL, result.Endpoint)
+		require.NotEmpty(t, result.RequestHash)
+	})
+
+	t.Run("test dry run", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+
+		errOut := &bytes.Buffer{}
+		cmd.SetErr(errOut)
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyFile.Name())...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile.Name())...)
+		args = append(args, removeServiceIDArg("svc1")...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, flag+common.DryRunFlagName, "true")
+
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		request := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(out.Bytes(), &request))
+		require.Equal(t, "update", request["type"])
+		require.Contains(t, errOut.String(), "dry run: request to "+serv.URL+"/operations was not sent")
+	})
+
+	t.Run("test invalid dry run", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		cmd.SetArgs(append(didURIArg(), flag+common.DryRunFlagName, "maybe"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for dry-run")
+	})
+
+	t.Run("test wait timed out", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, sidetreeURLArg(serv.URL)...)
+		args = append(args, signingKeyFileFlagNameArg(privateKeyFile.Name())...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile.Name())...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, removeServiceIDArg("svc1")...)
+		args = append(args, flag+common.WaitFlagName, "true", flag+common.WaitTimeoutFlagName, "50ms",
+			flag+common.WaitIntervalFlagName, "10ms")
+
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timed out after 50ms waiting for")
+	})
+
+	t.Run("test invalid wait", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		cmd.SetArgs(append(didURIArg(), flag+common.WaitFlagName, "maybe"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for wait")
+	})
+
+	t.Run("test invalid output", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		cmd.SetArgs(append(didURIArg(), flag+common.OutputFlagName, "xml"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for output")
+	})
+
+	t.Run("test success with state in and state out", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		stateInFile, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(stateInFile.Name())) }()
+
+		_, err = stateInFile.WriteString(fmt.Sprintf(`{"operation":"create","did":"did:ex:123","sidetreeURLs":["%s"]}`,
+			serv.URL))
+		require.NoError(t, err)
+
+		stateOutFile, err := ioutil.TempFile("", "*.json")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.Remove(stateOutFile.Name())) }()
+
+		var args []string
+		args = append(args, signingKeyFileFlagNameArg(privateKeyFile.Name())...)
+		args = append(args, nextUpdateKeyFileFlagNameArg(publicKeyFile.Name())...)
+		args = append(args, removeServiceIDArg("svc1")...)
+		args = append(args, signingKeyPasswordArg()...)
+		args = append(args, flag+common.StateInFlagName, stateInFile.Name())
+		args = append(args, flag+common.StateOutFlagName, stateOutFile.Name())
+
+		cmd.SetArgs(args)
+		err = cmd.Execute()
+		require.NoError(t, err)
+
+		state, err := common.ReadState(stateOutFile.Name())
+		require.NoError(t, err)
+		require.Equal(t, "update", state.Operation)
+		require.Equal(t, "did:ex:123", state.DID)
+		require.Equal(t, []string{serv.URL}, state.SidetreeURLs)
+		require.Equal(t, privateKeyFile.Name(), state.Keys[signingKeyFileFlagName])
+		require.NotEmpty(t, state.Commitments.Update)
+		require.Empty(t, state.Commitments.Recovery)
+	})
+
+	t.Run("test invalid state in", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		cmd.SetArgs([]string{flag + common.StateInFlagName, "invalid"})
+		err = cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read state file")
+	})
+
+	t.Run("test invalid config", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdateDIDCmd()
+
+		cmd.SetArgs([]string{flag + common.ConfigFlagName, "invalid"})
+		err = cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read config file")
+	})
 }
 
 func TestGetPublicKeys(t *testing.T) {
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package updatepolicycmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	maxAgeFlagName  = "max-age"
+	maxAgeEnvKey    = "DID_METHOD_CLI_MAX_AGE"
+	maxAgeFlagUsage = "Cache lifetime in seconds of the consortium config." +
+		" Alternatively, this can be set with the following environment variable: " + maxAgeEnvKey
+
+	numQueriesFlagName  = "num-queries"
+	numQueriesEnvKey    = "DID_METHOD_CLI_NUM_QUERIES"
+	numQueriesFlagUsage = "Number of stakeholders to query, and of endorsements required for the next version of" +
+		" the consortium config. 0 means all the stakeholders." +
+		" Alternatively, this can be set with the following environment variable: " + numQueriesEnvKey
+
+	multihashAlgorithmFlagName  = "sidetree-multihash-algorithm"
+	multihashAlgorithmEnvKey    = "DID_METHOD_CLI_SIDETREE_MULTIHASH_ALGORITHM"
+	multihashAlgorithmFlagUsage = "Multihash code of the hash algorithm of the sidetree operations of the" +
+		" consortium, for example 18 for SHA2-256." +
+		" Alternatively, this can be set with the following environment variable: " + multihashAlgorithmEnvKey
+)
+
+// GetUpdatePolicyCmd returns the Cobra update policy command.
+func GetUpdatePolicyCmd() *cobra.Command {
+	updatePolicyCmd := createUpdatePolicyCmd()
+
+	createFlags(updatePolicyCmd)
+
+	return updatePolicyCmd
+}
+
+func createUpdatePolicyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "update-policy",
+		Short: "Update the policy of a consortium",
+		Long: "Create the next version of a consortium config, with its policy updated and signed by a current" +
+			" member. The next version has to be endorsed by as many current members as the current consortium" +
+			" policy requires before it can be published.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			current, currentBytes, err := configcommon.ReadConsortium(cmd)
+			if err != nil {
+				return err
+			}
+
+			next := *current.Config
+
+			if err := updatePolicy(cmd, &next.Policy); err != nil {
+				return err
+			}
+
+			return configcommon.WriteSuccessor(cmd, current, currentBytes, &next, map[string][]byte{})
+		},
+	}
+}
+
+func updatePolicy(cmd *cobra.Command, policy *models.ConsortiumPolicy) error {
+	maxAge := cmdutils.GetUserSetOptionalVarFromString(cmd, maxAgeFlagName, maxAgeEnvKey)
+	numQueries := cmdutils.GetUserSetOptionalVarFromString(cmd, numQueriesFlagName, numQueriesEnvKey)
+	multihashAlgorithm := cmdutils.GetUserSetOptionalVarFromString(cmd, multihashAlgorithmFlagName,
+		multihashAlgorithmEnvKey)
+
+	if maxAge == "" && numQueries == "" && multihashAlgorithm == "" {
+		return fmt.Errorf("at least one of --%s, --%s or --%s is required", maxAgeFlagName, numQueriesFlagName,
+			multihashAlgorithmFlagName)
+	}
+
+	if maxAge != "" {
+		value, err := strconv.ParseUint(maxAge, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", maxAgeFlagName, err)
+		}
+
+		policy.Cache.MaxAge = uint32(value)
+	}
+
+	if numQueries != "" {
+		value, err := strconv.Atoi(numQueries)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", numQueriesFlagName, err)
+		}
+
+		if value < 0 {
+			return errors.New("num-queries must not be negative")
+		}
+
+		policy.NumQueries = value
+	}
+
+	if multihashAlgorithm != "" {
+		value, err := strconv.ParseUint(multihashAlgorithm, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", multihashAlgorithmFlagName, err)
+		}
+
+		policy.Sidetree = &models.SidetreePolicy{MultiHashAlgorithm: uint(value)}
+	}
+
+	return nil
+}
+
+func createFlags(startCmd *cobra.Command) {
+	configcommon.AddSuccessionFlags(startCmd)
+	startCmd.Flags().StringP(maxAgeFlagName, "", "", maxAgeFlagUsage)
+	startCmd.Flags().StringP(numQueriesFlagName, "", "", numQueriesFlagUsage)
+	startCmd.Flags().StringP(multihashAlgorithmFlagName, "", "", multihashAlgorithmFlagUsage)
+}
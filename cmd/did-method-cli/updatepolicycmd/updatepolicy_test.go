@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package updatepolicycmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test consortium file is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetUpdatePolicyCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither consortium-file (command line flag) nor "+
+			"DID_METHOD_CLI_CONSORTIUM_FILE (environment variable) have been set.")
+	})
+}
+
+func TestUpdatePolicy(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	consortiumBytes := consortiumConfig(t, key)
+
+	consortiumFile := writeFile(t, string(consortiumBytes))
+	defer func() { require.NoError(t, os.Remove(consortiumFile)) }()
+
+	jwk, err := (&jose.JSONWebKey{Key: key}).MarshalJSON()
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		cmd := GetUpdatePolicyCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, consortiumFile,
+			flag + configcommon.OutputDirectoryFlagName, dir, flag + common.SigningKeyJWKFlagName, string(jwk),
+			flag + maxAgeFlagName, "3600", flag + numQueriesFlagName, "1", flag + multihashAlgorithmFlagName, "18"})
+		require.NoError(t, cmd.Execute())
+		require.Contains(t, out.String(), "endorsed by stakeholder.one (1 of the 1 endorsements required)")
+
+		nextBytes, err := ioutil.ReadFile(filepath.Join(dir, "did-trustbloc", "consortium.net.json"))
+		require.NoError(t, err)
+
+		next, err := models.ParseConsortium(nextBytes)
+		require.NoError(t, err)
+		require.Equal(t, uint32(3600), next.Config.Policy.Cache.MaxAge)
+		require.Equal(t, 1, next.Config.Policy.NumQueries)
+		require.Equal(t, uint(18), next.Config.Policy.Sidetree.MultiHashAlgorithm)
+		require.Len(t, next.Config.Members, 1)
+		require.Equal(t, configcommon.ConfigHash(consortiumBytes), next.Config.Previous)
+	})
+
+	t.Run("test no policy change", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdatePolicyCmd()
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, consortiumFile})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "at least one of --max-age, --num-queries or"+
+			" --sidetree-multihash-algorithm is required")
+	})
+
+	t.Run("test invalid values", func(t *testing.T) {
+		for flagName, value := range map[string]string{maxAgeFlagName: "-1", numQueriesFlagName: "all",
+			multihashAlgorithmFlagName: "sha256"} {
+			os.Clearenv()
+
+			cmd := GetUpdatePolicyCmd()
+			cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, consortiumFile, flag + flagName, value})
+
+			err := cmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid value for "+flagName)
+		}
+	})
+
+	t.Run("test negative num queries", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetUpdatePolicyCmd()
+		cmd.SetArgs([]string{flag + configcommon.ConsortiumFileFlagName, consortiumFile,
+			flag + numQueriesFlagName, "-2"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "num-queries must not be negative")
+	})
+}
+
+func consortiumConfig(t *testing.T, key ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	jwk, err := (&jose.JSONWebKey{Key: key.Public()}).MarshalJSON()
+	require.NoError(t, err)
+
+	consortium := models.Consortium{Domain: "consortium.net",
+		Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 2419200}},
+		Members: []*models.StakeholderListElement{{Domain: "stakeholder.one", DID: "did:trustbloc:stakeholder.one",
+			PublicKey: models.PublicKey{JWK: jwk}}}}
+
+	consortiumBytes, err := json.Marshal(consortium)
+	require.NoError(t, err)
+
+	jws, err := configcommon.SignConfig(consortiumBytes, []jose.SigningKey{{Key: key, Algorithm: jose.EdDSA}})
+	require.NoError(t, err)
+
+	return []byte(jws)
+}
+
+func writeFile(t *testing.T, data string) string {
+	t.Helper()
+
+	file, err := ioutil.TempFile("", "*.json")
+	require.NoError(t, err)
+
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+
+	return file.Name()
+}
@@ -0,0 +1,293 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifyconfigcmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	gojose "github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/validation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+// report is the result of the verification of the configs of a consortium
+type report struct {
+	Domain       string          `json:"domain" yaml:"domain"`
+	Valid        bool            `json:"valid" yaml:"valid"`
+	Consortium   *configReport   `json:"consortium" yaml:"consortium"`
+	Stakeholders []*configReport `json:"stakeholders" yaml:"stakeholders"`
+	History      []*historyEntry `json:"history,omitempty" yaml:"history,omitempty"`
+	Warnings     []string        `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// configReport lists the problems found in a consortium or stakeholder config
+type configReport struct {
+	Domain   string              `json:"domain" yaml:"domain"`
+	Valid    bool                `json:"valid" yaml:"valid"`
+	Problems validation.Problems `json:"problems,omitempty" yaml:"problems,omitempty"`
+}
+
+// historyEntry is a previous version of the consortium config, and the endorsements by its members of the version
+// that follows it
+type historyEntry struct {
+	Hash         string   `json:"hash" yaml:"hash"`
+	Valid        bool     `json:"valid" yaml:"valid"`
+	Endorsements []string `json:"endorsements,omitempty" yaml:"endorsements,omitempty"`
+	Required     int      `json:"required,omitempty" yaml:"required,omitempty"`
+	Error        string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type configService interface {
+	GetConsortium(url, domain string) (*models.ConsortiumFileData, error)
+	GetStakeholder(url, domain string) (*models.StakeholderFileData, error)
+}
+
+type verifier struct {
+	configService configService
+	httpClient    *http.Client
+	opts          []validation.Option
+}
+
+func newHTTPClient(tlsConfig *tls.Config) *http.Client {
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}
+}
+
+func (v *verifier) verify(domain, url, history string) (*report, error) {
+	consortium, err := v.configService.GetConsortium(url, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch consortium config of %s: %w", domain, err)
+	}
+
+	problems := validation.ValidateConsortiumConfig([]byte(consortium.JWS.FullSerialize()), v.opts...)
+
+	r := &report{Domain: domain, Consortium: &configReport{Domain: domain, Valid: !problems.HasErrors(),
+		Problems: problems}}
+
+	for _, member := range consortium.Config.Members {
+		if member == nil || member.Domain == "" {
+			continue
+		}
+
+		// stakeholder configs are fetched from the same place as the consortium config, when it isn't the domain
+		stakeholderURL := member.Domain
+		if url != domain {
+			stakeholderURL = url
+		}
+
+		r.Stakeholders = append(r.Stakeholders, v.verifyStakeholder(stakeholderURL, member))
+	}
+
+	switch {
+	case consortium.Config.Previous == "":
+	case history == "":
+		r.Warnings = append(r.Warnings, fmt.Sprintf("history chain isn't verified: previous version %s",
+			consortium.Config.Previous))
+	default:
+		r.History = v.verifyHistory(consortium, history)
+	}
+
+	r.Valid = r.valid()
+
+	return r, nil
+}
+
+func (v *verifier) verifyStakeholder(url string, member *models.StakeholderListElement) *configReport {
+	r := &configReport{Domain: member.Domain}
+
+	stakeholder, err := v.configService.GetStakeholder(url, member.Domain)
+	if err != nil {
+		r.Problems = append(r.Problems, validation.Problem{Severity: validation.SeverityError,
+			Code: validation.CodeUnreachable, Message: fmt.Sprintf("failed to fetch stakeholder config: %s", err)})
+
+		return r
+	}
+
+	r.Problems = validation.ValidateStakeholderConfig([]byte(stakeholder.JWS.FullSerialize()), v.opts...)
+
+	key := gojose.JSONWebKey{}
+	if err := key.UnmarshalJSON(member.PublicKey.JWK); err == nil {
+		if _, _, _, err := stakeholder.JWS.VerifyMulti(key); err != nil {
+			r.Problems = append(r.Problems, validation.Problem{Severity: validation.SeverityError,
+				Code:    validation.CodeInvalidJWS,
+				Message: "stakeholder config isn't signed with the member key of the consortium config"})
+		}
+	}
+
+	if stakeholder.Config.DID != member.DID {
+		r.Problems = append(r.Problems, validation.Problem{Severity: validation.SeverityError,
+			Code: validation.CodeInvalidField, Field: "did", Message: fmt.Sprintf(
+				"stakeholder DID %s differs from the member DID %s of the consortium config",
+				stakeholder.Config.DID, member.DID)})
+	}
+
+	r.Valid = !r.Problems.HasErrors()
+
+	return r
+}
+
+// verifyHistory follows the previous versions of the consortium config, and checks that each version is endorsed
+// by as many members of the version before it as its policy requires
+func (v *verifier) verifyHistory(consortium *models.ConsortiumFileData, history string) []*historyEntry {
+	var entries []*historyEntry
+
+	seen := map[string]bool{}
+
+	for current := consortium; current.Config.Previous != ""; {
+		entry := &historyEntry{Hash: current.Config.Previous}
+		entries = append(entries, entry)
+
+		if seen[entry.Hash] {
+			entry.Error = "history chain loops back to this version"
+
+			break
+		}
+
+		seen[entry.Hash] = true
+
+		previous, err := v.readHistory(history, entry.Hash)
+		if err != nil {
+			entry.Error = err.Error()
+
+			break
+		}
+
+		entry.Endorsements = configcommon.Endorsements(current.JWS, previous.Config)
+		entry.Required = previous.Config.NumQueries()
+		entry.Valid = len(entry.Endorsements) >= entry.Required
+
+		current = previous
+	}
+
+	return entries
+}
+
+func (v *verifier) readHistory(history, hash string) (*models.ConsortiumFileData, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if strings.HasPrefix(history, "http://") || strings.HasPrefix(history, "https://") {
+		data, err = v.fetch(strings.TrimSuffix(history, "/") + "/" + hash + ".json")
+	} else {
+		dir := strings.TrimPrefix(history, "file://")
+		data, err = ioutil.ReadFile(filepath.Clean(filepath.Join(dir, hash+".json")))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous version: %w", err)
+	}
+
+	if configcommon.ConfigHash(data) != hash {
+		return nil, errors.New("previous version doesn't match its hash")
+	}
+
+	previous, err := models.ParseConsortium(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse previous version: %w", err)
+	}
+
+	return previous, nil
+}
+
+func (v *verifier) fetch(url string) ([]byte, error) {
+	resp, err := v.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed: error %d", url, resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+func (r *report) valid() bool {
+	if !r.Consortium.Valid {
+		return false
+	}
+
+	for _, stakeholder := range r.Stakeholders {
+		if !stakeholder.Valid {
+			return false
+		}
+	}
+
+	for _, entry := range r.History {
+		if !entry.Valid {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *report) text() string {
+	b := &strings.Builder{}
+
+	r.Consortium.write(b, "consortium")
+
+	for _, stakeholder := range r.Stakeholders {
+		stakeholder.write(b, "stakeholder")
+	}
+
+	for _, entry := range r.History {
+		if entry.Error != "" {
+			fmt.Fprintf(b, "previous version %s: %s\n", entry.Hash, entry.Error)
+
+			continue
+		}
+
+		fmt.Fprintf(b, "previous version %s: %s, next version endorsed by %d of the %d members required [%s]\n",
+			entry.Hash, validity(entry.Valid), len(entry.Endorsements), entry.Required,
+			strings.Join(entry.Endorsements, ", "))
+	}
+
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(b, "warning: %s\n", warning)
+	}
+
+	fmt.Fprintf(b, "config of consortium %s: %s", r.Domain, validity(r.Valid))
+
+	return b.String()
+}
+
+func (c *configReport) write(b *strings.Builder, kind string) {
+	fmt.Fprintf(b, "%s config %s: %s\n", kind, c.Domain, validity(c.Valid))
+
+	for _, problem := range c.Problems {
+		field := ""
+		if problem.Field != "" {
+			field = " " + problem.Field
+		}
+
+		fmt.Fprintf(b, "  %s%s: %s\n", problem.Severity, field, problem.Message)
+	}
+}
+
+func validity(valid bool) string {
+	if valid {
+		return "valid"
+	}
+
+	return "invalid"
+}
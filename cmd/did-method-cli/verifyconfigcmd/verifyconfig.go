@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifyconfigcmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/validation"
+)
+
+const (
+	domainFlagName  = "domain"
+	domainEnvKey    = "DID_METHOD_CLI_DOMAIN"
+	domainFlagUsage = "The did:trustbloc consortium's domain. " +
+		" Alternatively, this can be set with the following environment variable: " + domainEnvKey
+
+	urlFlagName  = "url"
+	urlEnvKey    = "DID_METHOD_CLI_URL"
+	urlFlagUsage = "Where to fetch the consortium and stakeholder configs from instead of their domains:" +
+		" a URL, an ipfs:// URI or a file:// URI of a local directory laid out like the root of a web server." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+
+	historyFlagName  = "history"
+	historyEnvKey    = "DID_METHOD_CLI_HISTORY"
+	historyFlagUsage = "URL or local directory holding the previous versions of the consortium config," +
+		" named by their hash as <hash>.json. The history chain isn't verified if not set." +
+		" Alternatively, this can be set with the following environment variable: " + historyEnvKey
+
+	checkReachabilityFlagName  = "check-reachability"
+	checkReachabilityEnvKey    = "DID_METHOD_CLI_CHECK_REACHABILITY"
+	checkReachabilityFlagUsage = "Check that the stakeholder sidetree endpoints respond." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + checkReachabilityEnvKey
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "DID_METHOD_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "DID_METHOD_CLI_TLS_CACERTS"
+)
+
+type parameters struct {
+	domain            string
+	url               string
+	history           string
+	checkReachability bool
+	tlsConfig         *tls.Config
+	output            string
+}
+
+// GetVerifyConfigCmd returns the Cobra verify config command.
+func GetVerifyConfigCmd() *cobra.Command {
+	verifyConfigCmd := createVerifyConfigCmd()
+
+	createFlags(verifyConfigCmd)
+
+	return verifyConfigCmd
+}
+
+func createVerifyConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-config",
+		Short: "Verify the configs of a consortium",
+		Long: "Fetch the consortium config of a domain and the configs of its stakeholders, and verify their" +
+			" signatures, the endorsement policy of the consortium and the history chain of the consortium config",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parameters, err := getParameters(cmd)
+			if err != nil {
+				return err
+			}
+
+			var opts []validation.Option
+			if parameters.checkReachability {
+				opts = append(opts, validation.CheckReachability(parameters.tlsConfig))
+			}
+
+			verifier := &verifier{
+				configService: httpconfig.NewService(httpconfig.WithTLSConfig(parameters.tlsConfig)),
+				httpClient:    newHTTPClient(parameters.tlsConfig),
+				opts:          opts,
+			}
+
+			report, err := verifier.verify(parameters.domain, parameters.url, parameters.history)
+			if err != nil {
+				return err
+			}
+
+			if err := common.PrintResult(cmd, parameters.output, report, report.text()); err != nil {
+				return err
+			}
+
+			if !report.Valid {
+				return fmt.Errorf("config of consortium %s is invalid", parameters.domain)
+			}
+
+			return nil
+		},
+	}
+}
+
+func getParameters(cmd *cobra.Command) (*parameters, error) {
+	domain, err := cmdutils.GetUserSetVarFromString(cmd, domainFlagName, domainEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	url := cmdutils.GetUserSetOptionalVarFromString(cmd, urlFlagName, urlEnvKey)
+	if url == "" {
+		url = domain
+	}
+
+	checkReachability := false
+
+	if checkReachabilityString := cmdutils.GetUserSetOptionalVarFromString(cmd, checkReachabilityFlagName,
+		checkReachabilityEnvKey); checkReachabilityString != "" {
+		checkReachability, err = strconv.ParseBool(checkReachabilityString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", checkReachabilityFlagName, err)
+		}
+	}
+
+	rootCAs, err := getRootCAs(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := common.GetOutputFormat(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parameters{
+		domain:            domain,
+		url:               url,
+		history:           cmdutils.GetUserSetOptionalVarFromString(cmd, historyFlagName, historyEnvKey),
+		checkReachability: checkReachability,
+		tlsConfig:         tlsConfig,
+		output:            output,
+	}, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(historyFlagName, "", "", historyFlagUsage)
+	startCmd.Flags().StringP(checkReachabilityFlagName, "", "", checkReachabilityFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifyconfigcmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/internal/configcommon"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const flag = "--"
+
+var domains = []string{"stakeholder.one", "stakeholder.two"} //nolint: gochecknoglobals
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test domain is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyConfigCmd()
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither domain (command line flag) nor "+
+			"DID_METHOD_CLI_DOMAIN (environment variable) have been set.")
+	})
+}
+
+func TestVerifyConfig(t *testing.T) {
+	keys := generateKeys(t)
+
+	first := consortiumConfig(t, "", keys)
+	second := consortiumConfig(t, configcommon.ConfigHash(first), keys)
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	history := filepath.Join(dir, "history")
+	require.NoError(t, os.MkdirAll(history, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(history, configcommon.ConfigHash(first)+".json"), first, 0600))
+
+	wellKnown := filepath.Join(dir, ".well-known", "did-trustbloc")
+	require.NoError(t, os.MkdirAll(wellKnown, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(wellKnown, "consortium.net.json"), second, 0600))
+
+	for i, domain := range domains {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(wellKnown, domain+".json"),
+			stakeholderConfig(t, domain, keys[i]), 0600))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetVerifyConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + domainFlagName, "consortium.net", flag + urlFlagName, "file://" + dir,
+			flag + historyFlagName, history})
+		require.NoError(t, cmd.Execute())
+
+		require.Contains(t, out.String(), "stakeholder config stakeholder.two: valid")
+		require.Contains(t, out.String(), "next version endorsed by 2 of the 2 members required")
+		require.Contains(t, out.String(), "config of consortium consortium.net: valid")
+	})
+
+	t.Run("success with json output", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetVerifyConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + domainFlagName, "consortium.net", flag + urlFlagName, "file://" + dir,
+			flag + common.OutputFlagName, common.OutputJSON})
+		require.NoError(t, cmd.Execute())
+
+		r := report{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &r))
+		require.True(t, r.Valid)
+		require.Len(t, r.Stakeholders, 2)
+		require.Empty(t, r.History)
+		require.Len(t, r.Warnings, 1)
+	})
+
+	t.Run("test stakeholder config signed with another key", func(t *testing.T) {
+		os.Clearenv()
+
+		stakeholderFile := filepath.Join(wellKnown, "stakeholder.two.json")
+		require.NoError(t, ioutil.WriteFile(stakeholderFile, stakeholderConfig(t, "stakeholder.two", keys[0]), 0600))
+
+		defer func() {
+			require.NoError(t, ioutil.WriteFile(stakeholderFile, stakeholderConfig(t, "stakeholder.two", keys[1]),
+				0600))
+		}()
+
+		cmd := GetVerifyConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + domainFlagName, "consortium.net", flag + urlFlagName, "file://" + dir})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "config of consortium consortium.net is invalid")
+		require.Contains(t, out.String(), "stakeholder config isn't signed with the member key")
+	})
+
+	t.Run("test previous version is missing", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetVerifyConfigCmd()
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{flag + domainFlagName, "consortium.net", flag + urlFlagName, "file://" + dir,
+			flag + historyFlagName, dir})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, out.String(), "failed to read previous version")
+	})
+
+	t.Run("test consortium config not found", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetVerifyConfigCmd()
+		cmd.SetArgs([]string{flag + domainFlagName, "other.net", flag + urlFlagName, "file://" + dir})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to fetch consortium config of other.net")
+	})
+}
+
+func generateKeys(t *testing.T) []ed25519.PrivateKey {
+	t.Helper()
+
+	var keys []ed25519.PrivateKey
+
+	for range domains {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func consortiumConfig(t *testing.T, previous string, keys []ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	consortium := models.Consortium{Domain: "consortium.net", Previous: previous,
+		Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 2419200}, NumQueries: 2}}
+
+	var signingKeys []jose.SigningKey
+
+	for i, key := range keys {
+		jwk, err := (&jose.JSONWebKey{Key: key.Public()}).MarshalJSON()
+		require.NoError(t, err)
+
+		consortium.Members = append(consortium.Members, &models.StakeholderListElement{Domain: domains[i],
+			DID: "did:trustbloc:" + domains[i], PublicKey: models.PublicKey{JWK: jwk}})
+
+		signingKeys = append(signingKeys, jose.SigningKey{Key: key, Algorithm: jose.EdDSA})
+	}
+
+	consortiumBytes, err := json.Marshal(consortium)
+	require.NoError(t, err)
+
+	jws, err := configcommon.SignConfig(consortiumBytes, signingKeys)
+	require.NoError(t, err)
+
+	return []byte(jws)
+}
+
+func stakeholderConfig(t *testing.T, domain string, key ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	stakeholder := models.Stakeholder{Domain: domain, DID: "did:trustbloc:" + domain,
+		Policy:    models.StakeholderSettings{Cache: models.CacheControl{MaxAge: 604800}},
+		Endpoints: []string{"https://" + domain + "/sidetree"}}
+
+	stakeholderBytes, err := json.Marshal(stakeholder)
+	require.NoError(t, err)
+
+	jws, err := configcommon.SignConfig(stakeholderBytes, []jose.SigningKey{{Key: key, Algorithm: jose.EdDSA}})
+	require.NoError(t, err)
+
+	return []byte(jws)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifydidcmd
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/cmd/did-method-cli/common"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+const (
+	didURIFlagName  = "did-uri"
+	didURIEnvKey    = "DID_METHOD_CLI_DID_URI"
+	didURIFlagUsage = "DID URI to verify. " +
+		" Alternatively, this can be set with the following environment variable: " + didURIEnvKey
+
+	domainFlagName      = "domain"
+	domainFileEnvKey    = "DID_METHOD_CLI_DOMAIN"
+	domainFileFlagUsage = "URL to the did:trustbloc consortium's domain. " +
+		" Alternatively, this can be set with the following environment variable: " + domainFileEnvKey
+
+	sidetreeURLFlagName  = "sidetree-url"
+	sidetreeURLFlagUsage = "Comma-Separated list of sidetree url the DID is resolved with." +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeURLEnvKey
+	sidetreeURLEnvKey = "DID_METHOD_CLI_SIDETREE_URL"
+
+	casURLFlagName  = "cas-url"
+	casURLEnvKey    = "DID_METHOD_CLI_CAS_URL"
+	casURLFlagUsage = "URL of the IPFS gateway the batch files of the anchors are fetched from." +
+		" Defaults to https://ipfs.io if not set." +
+		" Alternatively, this can be set with the following environment variable: " + casURLEnvKey
+
+	anchorFlagName  = "anchor"
+	anchorEnvKey    = "DID_METHOD_CLI_ANCHOR"
+	anchorFlagUsage = "Comma-Separated list of the anchor strings of the transactions of the DID on the ledger," +
+		" in their order, such as 1.QmWd5PH6vyRH5kMdzZRPBnf952dbR4av3Bd7B2wBqMaAcf." +
+		" Alternatively, this can be set with the following environment variable: " + anchorEnvKey
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "DID_METHOD_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "DID_METHOD_CLI_TLS_CACERTS"
+
+	sidetreeReadTokenFlagName  = "sidetree-read-token"
+	sidetreeReadTokenEnvKey    = "DID_METHOD_CLI_SIDETREE_READ_TOKEN" //nolint: gosec
+	sidetreeReadTokenFlagUsage = "The sidetree read token " +
+		" Alternatively, this can be set with the following environment variable: " + sidetreeReadTokenEnvKey
+)
+
+// GetVerifyDIDCmd returns the Cobra verify did command.
+func GetVerifyDIDCmd() *cobra.Command {
+	verifyDIDCmd := verifyDIDCmd()
+
+	createFlags(verifyDIDCmd)
+
+	return verifyDIDCmd
+}
+
+func verifyDIDCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-did",
+		Short: "Verify TrustBloc DID from the CAS",
+		Long: "Replay the operations of a TrustBloc DID from the batch files of its anchors in the CAS" +
+			" and report whether the resolution of the DID matches them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.ApplyConfig(cmd); err != nil {
+				return err
+			}
+
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			tlsConfig, err := common.NewTLSConfig(cmd, rootCAs)
+			if err != nil {
+				return err
+			}
+
+			didURI, err := cmdutils.GetUserSetVarFromString(cmd, didURIFlagName, didURIEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			anchors, err := cmdutils.GetUserSetVarFromArrayString(cmd, anchorFlagName, anchorEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			output, err := common.GetOutputFormat(cmd)
+			if err != nil {
+				return err
+			}
+
+			client := did.New(did.WithAuthToken(cmdutils.GetUserSetOptionalVarFromString(cmd,
+				sidetreeReadTokenFlagName, sidetreeReadTokenEnvKey)),
+				did.WithTLSConfig(tlsConfig))
+
+			verification, err := client.VerifyFromCAS(didURI,
+				cmdutils.GetUserSetOptionalVarFromString(cmd, domainFlagName, domainFileEnvKey),
+				cmdutils.GetUserSetOptionalVarFromString(cmd, casURLFlagName, casURLEnvKey), anchors,
+				cmdutils.GetUserSetOptionalVarFromArrayString(cmd, sidetreeURLFlagName, sidetreeURLEnvKey)...)
+			if err != nil {
+				return fmt.Errorf("failed to verify did: %w", err)
+			}
+
+			return printResult(cmd, output, verification)
+		},
+	}
+}
+
+func printResult(cmd *cobra.Command, output string, verification *did.CASVerification) error {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "Verified: %t", verification.Verified)
+
+	for _, op := range verification.Operations {
+		fmt.Fprintf(&text, "\nOperation: %s in %s", op.Type, op.Anchor)
+
+		if !op.Applied {
+			text.WriteString(" ignored")
+		}
+
+		if op.Reason != "" {
+			fmt.Fprintf(&text, " (%s)", op.Reason)
+		}
+	}
+
+	for _, divergence := range verification.Divergences {
+		fmt.Fprintf(&text, "\nDivergence: %s", divergence)
+	}
+
+	return common.PrintResult(cmd, output, verification, text.String())
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(didURIFlagName, "", "", didURIFlagUsage)
+	startCmd.Flags().StringP(domainFlagName, "", "", domainFileFlagUsage)
+	startCmd.Flags().StringArrayP(sidetreeURLFlagName, "", []string{}, sidetreeURLFlagUsage)
+	startCmd.Flags().StringP(casURLFlagName, "", "", casURLFlagUsage)
+	startCmd.Flags().StringArrayP(anchorFlagName, "", []string{}, anchorFlagUsage)
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	common.AddTLSFlags(startCmd)
+	startCmd.Flags().StringP(sidetreeReadTokenFlagName, "", "", sidetreeReadTokenFlagUsage)
+	startCmd.Flags().StringP(common.OutputFlagName, "", "", common.OutputFlagUsage)
+	startCmd.Flags().StringP(common.ConfigFlagName, "", "", common.ConfigFlagUsage)
+	startCmd.Flags().StringP(common.ProfileFlagName, "", "", common.ProfileFlagUsage)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifydidcmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+)
+
+const flag = "--"
+
+func TestMissingArg(t *testing.T) {
+	t.Run("test did uri is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyDIDCmd()
+
+		cmd.SetArgs([]string{flag + anchorFlagName, "1.anchor"})
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither did-uri (command line flag) nor "+
+			"DID_METHOD_CLI_DID_URI (environment variable) have been set.")
+	})
+
+	t.Run("test anchor is missing", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyDIDCmd()
+
+		cmd.SetArgs(didURIArg())
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither anchor (command line flag) nor "+
+			"DID_METHOD_CLI_ANCHOR (environment variable) have been set.")
+	})
+}
+
+func TestVerifyDID(t *testing.T) {
+	var anchorFile bytes.Buffer
+
+	w := gzip.NewWriter(&anchorFile)
+	_, err := w.Write([]byte(`{"operations":{}}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/anchor" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err = w.Write(anchorFile.Bytes())
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("test failed to fetch batch", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLFlagName, serv.URL)
+		args = append(args, flag+casURLFlagName, serv.URL)
+		args = append(args, flag+anchorFlagName, "1.missing")
+
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to verify did")
+	})
+
+	t.Run("test DID not created in the anchors", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLFlagName, serv.URL)
+		args = append(args, flag+casURLFlagName, serv.URL)
+		args = append(args, flag+anchorFlagName, "1.anchor")
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "Verified: false\nDivergence: the anchors have no create operation of the DID\n",
+			out.String())
+	})
+
+	t.Run("test json output", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyDIDCmd()
+
+		var args []string
+		args = append(args, didURIArg()...)
+		args = append(args, flag+sidetreeURLFlagName, serv.URL)
+		args = append(args, flag+casURLFlagName, serv.URL)
+		args = append(args, flag+anchorFlagName, "1.anchor")
+		args = append(args, flag+"output", "json")
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		var v did.CASVerification
+		require.NoError(t, json.Unmarshal(out.Bytes(), &v))
+		require.Equal(t, "did:ex:123", v.DID)
+		require.False(t, v.Verified)
+	})
+}
+
+func didURIArg() []string {
+	return []string{flag + didURIFlagName, "did:ex:123"}
+}
@@ -8,18 +8,39 @@ package startcmd
 import (
 	"crypto/tls"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
 	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi"
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi/didmethodpb"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/correlation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/cors"
 	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/didmethod"
 	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/didmethod/operation"
 	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/healthcheck"
+	healthcheckop "github.com/trustbloc/trustbloc-did-method/pkg/restapi/healthcheck/operation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/idempotency"
+	metricsrest "github.com/trustbloc/trustbloc-did-method/pkg/restapi/metrics"
+	metricsop "github.com/trustbloc/trustbloc-did-method/pkg/restapi/metrics/operation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/openapi"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/ratelimit"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/wellknown"
+	wellknownop "github.com/trustbloc/trustbloc-did-method/pkg/restapi/wellknown/operation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/doh"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 )
 
 const (
@@ -41,6 +62,54 @@ const (
 		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
 	tlsCACertsEnvKey = "DID_METHOD_TLS_CACERTS"
 
+	tlsClientCertFlagName  = "tls-client-cert"
+	tlsClientCertFlagUsage = "Path of the PEM client certificate presented to the sidetree endpoints and the" +
+		" consortium domain which require mutual TLS. Must be set with tls-client-key." +
+		" Alternatively, this can be set with the following environment variable: " + tlsClientCertEnvKey
+	tlsClientCertEnvKey = "DID_METHOD_TLS_CLIENT_CERT"
+
+	tlsClientKeyFlagName  = "tls-client-key"
+	tlsClientKeyFlagUsage = "Path of the PEM private key of the client certificate. Must be set with tls-client-cert." +
+		" Alternatively, this can be set with the following environment variable: " + tlsClientKeyEnvKey
+	tlsClientKeyEnvKey = "DID_METHOD_TLS_CLIENT_KEY"
+
+	tlsMinVersionFlagName  = "tls-min-version"
+	tlsMinVersionFlagUsage = "Minimum TLS version of the connections to the sidetree endpoints, the consortium" +
+		" domain and the other servers. Possible values [1.2] [1.3]. Defaults to 1.2 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsMinVersionEnvKey
+	tlsMinVersionEnvKey = "DID_METHOD_TLS_MIN_VERSION"
+
+	tlsCipherSuitesFlagName  = "tls-cipher-suites"
+	tlsCipherSuitesFlagUsage = "Comma-Separated list of the cipher suites of the TLS 1.2 connections," +
+		" for example TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. Defaults to the secure cipher suites if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCipherSuitesEnvKey
+	tlsCipherSuitesEnvKey = "DID_METHOD_TLS_CIPHER_SUITES"
+
+	tlsInsecureSkipVerifyFlagName  = "tls-insecure-skip-verify"
+	tlsInsecureSkipVerifyFlagUsage = "Skip the verification of the server certificates, for development only." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsInsecureSkipVerifyEnvKey
+	tlsInsecureSkipVerifyEnvKey = "DID_METHOD_TLS_INSECURE_SKIP_VERIFY"
+
+	tlsPinsFlagName  = "tls-pins"
+	tlsPinsFlagUsage = "Comma-Separated list of the keys pinned for the consortium domain and the other hosts," +
+		" as <host>=sha256/<base64 SHA-256 hash of the SubjectPublicKeyInfo of a certificate of the host>." +
+		" The connections to a pinned host are refused unless it presents a certificate with a pinned key." +
+		" Alternatively, this can be set with the following environment variable: " + tlsPinsEnvKey
+	tlsPinsEnvKey = "DID_METHOD_TLS_PINS"
+
+	dohURLFlagName  = "dns-over-https-url"
+	dohURLFlagUsage = "URL of a trusted DNS-over-HTTPS server, such as https://1.1.1.1/dns-query, resolving the" +
+		" consortium domain and the sidetree endpoints instead of the system resolver." +
+		" Alternatively, this can be set with the following environment variable: " + dohURLEnvKey
+	dohURLEnvKey = "DID_METHOD_DNS_OVER_HTTPS_URL"
+
+	dnssecDomainsFlagName  = "dnssec-domains"
+	dnssecDomainsFlagUsage = "Comma-Separated list of the domains, such as the consortium domain, whose names must be" +
+		" answered as authenticated with DNSSEC by the DNS-over-HTTPS server. Requires dns-over-https-url." +
+		" Alternatively, this can be set with the following environment variable: " + dnssecDomainsEnvKey
+	dnssecDomainsEnvKey = "DID_METHOD_DNSSEC_DOMAINS"
+
 	domainFlagName      = "domain"
 	domainFlagShorthand = "b"
 	domainFlagUsage     = "domain"
@@ -49,7 +118,8 @@ const (
 	modeFlagName      = "mode"
 	modeFlagShorthand = "m"
 	modeFlagUsage     = "Mode in which the did-method service will run. Possible values: " +
-		"['registrar', 'resolver', 'combined'] (default: combined)."
+		"['registrar', 'resolver', 'combined', 'public-registrar', 'config-host'] (default: combined)." +
+		" The config-host mode only hosts the config files of well-known-dir."
 	modeEnvKey = "DID_METHOD_MODE"
 
 	sidetreeReadTokenFlagName  = "sidetree-read-token"
@@ -66,15 +136,184 @@ const (
 	enableSignaturesEnvKey    = "ENABLE_SIGNATURES"
 	enableSignaturesFlagUsage = "Enable signatures. Possible values [true] [false]. Defaults to true if not set." +
 		" Alternatively, this can be set with the following environment variable: " + enableSignaturesEnvKey
+
+	publicRegistrationSecretFlagName  = "public-registration-secret"
+	publicRegistrationSecretEnvKey    = "DID_METHOD_PUBLIC_REGISTRATION_SECRET" //nolint: gosec
+	publicRegistrationSecretFlagUsage = "Secret used to sign proof-of-work challenges." +
+		" Required in 'public-registrar' mode." +
+		" Alternatively, this can be set with the following environment variable: " + publicRegistrationSecretEnvKey
+
+	publicRegistrationDifficultyFlagName  = "public-registration-difficulty"
+	publicRegistrationDifficultyEnvKey    = "DID_METHOD_PUBLIC_REGISTRATION_DIFFICULTY"
+	publicRegistrationDifficultyFlagUsage = "Number of leading zero bits required in proof-of-work hashes," +
+		" between 1 and 256. Defaults to 20 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + publicRegistrationDifficultyEnvKey
+
+	publicRegistrationRateLimitFlagName  = "public-registration-rate-limit"
+	publicRegistrationRateLimitEnvKey    = "DID_METHOD_PUBLIC_REGISTRATION_RATE_LIMIT"
+	publicRegistrationRateLimitFlagUsage = "Maximum number of registrations per client per hour." +
+		" Defaults to 10 if not set." +
+		" Alternatively, this can be set with the following environment variable: " + publicRegistrationRateLimitEnvKey
+
+	captchaVerifyURLFlagName  = "captcha-verify-url"
+	captchaVerifyURLEnvKey    = "DID_METHOD_CAPTCHA_VERIFY_URL"
+	captchaVerifyURLFlagUsage = "reCAPTCHA/hCaptcha compatible siteverify URL." +
+		" If set, CAPTCHA tokens are accepted in place of proof-of-work in 'public-registrar' mode." +
+		" Alternatively, this can be set with the following environment variable: " + captchaVerifyURLEnvKey
+
+	captchaSecretFlagName  = "captcha-secret"
+	captchaSecretEnvKey    = "DID_METHOD_CAPTCHA_SECRET" //nolint: gosec
+	captchaSecretFlagUsage = "Secret used with the siteverify URL." +
+		" Alternatively, this can be set with the following environment variable: " + captchaSecretEnvKey
+
+	keyStoreFlagName  = "key-store"
+	keyStoreEnvKey    = "DID_METHOD_KEY_STORE"
+	keyStoreFlagUsage = "Encrypted keystore file of the private recovery and update keys of the DIDs managed by the" +
+		" service, created if it doesn't exist." +
+		" If set, update, recover and deactivate requests which don't hold the signing key in their secret" +
+		" are signed with the key of this keystore." +
+		" Alternatively, this can be set with the following environment variable: " + keyStoreEnvKey
+
+	keyStorePasswordFlagName  = "key-store-password"
+	keyStorePasswordEnvKey    = "DID_METHOD_KEY_STORE_PASSWORD" //nolint: gosec
+	keyStorePasswordFlagUsage = "The password the keystore is encrypted with. Required if the key store is set." +
+		" Alternatively, this can be set with the following environment variable: " + keyStorePasswordEnvKey
+
+	deactivationTokenFlagName  = "deactivation-token"
+	deactivationTokenEnvKey    = "DID_METHOD_DEACTIVATION_TOKEN" //nolint: gosec
+	deactivationTokenFlagUsage = "Bearer token required by deactivate requests, and by update and recover requests" +
+		" signed with the keys of the key store." +
+		" If not set, deactivate, update and recover requests can't be signed with the keys of the key store." +
+		" Alternatively, this can be set with the following environment variable: " + deactivationTokenEnvKey
+
+	apiKeysFlagName  = "api-keys"
+	apiKeysEnvKey    = "DID_METHOD_API_KEYS"
+	apiKeysFlagUsage = "Comma-Separated list of client=key API keys authenticating the requests to the did method" +
+		" endpoints, sent in the X-API-Key header or as bearer token." +
+		" Alternatively, this can be set with the following environment variable: " + apiKeysEnvKey
+
+	oauth2IntrospectionURLFlagName  = "oauth2-introspection-url"
+	oauth2IntrospectionURLEnvKey    = "DID_METHOD_OAUTH2_INTROSPECTION_URL"
+	oauth2IntrospectionURLFlagUsage = "OAuth2 token introspection endpoint (RFC 7662) validating the bearer tokens" +
+		" authenticating the requests to the did method endpoints." +
+		" Alternatively, this can be set with the following environment variable: " + oauth2IntrospectionURLEnvKey
+
+	oauth2ClientIDFlagName  = "oauth2-client-id"
+	oauth2ClientIDEnvKey    = "DID_METHOD_OAUTH2_CLIENT_ID"
+	oauth2ClientIDFlagUsage = "Client ID of the service at the OAuth2 token introspection endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + oauth2ClientIDEnvKey
+
+	oauth2ClientSecretFlagName  = "oauth2-client-secret"
+	oauth2ClientSecretEnvKey    = "DID_METHOD_OAUTH2_CLIENT_SECRET" //nolint: gosec
+	oauth2ClientSecretFlagUsage = "Client secret of the service at the OAuth2 token introspection endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + oauth2ClientSecretEnvKey
+
+	asyncOperationsFlagName  = "async-operations"
+	asyncOperationsEnvKey    = "DID_METHOD_ASYNC_OPERATIONS"
+	asyncOperationsFlagUsage = "Set to true to return an operation ID from the create, update and recover requests" +
+		" immediately, and report whether the operation is pending, anchored or failed at /1.0/operations/{id}." +
+		" The generated keys of the operation are returned once, and the operations are kept in memory for an hour." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + asyncOperationsEnvKey
+
+	webhookURLsFlagName  = "webhook-urls"
+	webhookURLsEnvKey    = "DID_METHOD_WEBHOOK_URLS"
+	webhookURLsFlagUsage = "Comma-Separated list of URLs notified of the outcome of all the create, update and" +
+		" recover operations, once the change of the did document is observable. Requires webhook-secret." +
+		" Alternatively, this can be set with the following environment variable: " + webhookURLsEnvKey
+
+	webhookSecretFlagName  = "webhook-secret"
+	webhookSecretEnvKey    = "DID_METHOD_WEBHOOK_SECRET" //nolint: gosec
+	webhookSecretFlagUsage = "HMAC-SHA256 key signing the webhook notifications in the X-Signature header." +
+		" Enables the webhooks, and the callbackUrl option of the operation requests." +
+		" Alternatively, this can be set with the following environment variable: " + webhookSecretEnvKey
+
+	webhookCallbackHostsFlagName  = "webhook-callback-hosts"
+	webhookCallbackHostsEnvKey    = "DID_METHOD_WEBHOOK_CALLBACK_HOSTS"
+	webhookCallbackHostsFlagUsage = "Comma-Separated list of the hosts the callbackUrl option of the operation" +
+		" requests may target. If not set, the callbackUrl option is refused." +
+		" Alternatively, this can be set with the following environment variable: " + webhookCallbackHostsEnvKey
+
+	corsAllowedOriginsFlagName  = "cors-allowed-origins"
+	corsAllowedOriginsEnvKey    = "DID_METHOD_CORS_ALLOWED_ORIGINS"
+	corsAllowedOriginsFlagUsage = "Comma-Separated list of origins allowed to call the service from a browser," +
+		" * allows any origin. Cross-origin requests are not allowed if not set." +
+		" Alternatively, this can be set with the following environment variable: " + corsAllowedOriginsEnvKey
+
+	corsAllowedMethodsFlagName  = "cors-allowed-methods"
+	corsAllowedMethodsEnvKey    = "DID_METHOD_CORS_ALLOWED_METHODS"
+	corsAllowedMethodsFlagUsage = "Comma-Separated list of methods allowed in cross-origin requests." +
+		" Defaults to GET,POST if not set." +
+		" Alternatively, this can be set with the following environment variable: " + corsAllowedMethodsEnvKey
+
+	corsAllowedHeadersFlagName  = "cors-allowed-headers"
+	corsAllowedHeadersEnvKey    = "DID_METHOD_CORS_ALLOWED_HEADERS"
+	corsAllowedHeadersFlagUsage = "Comma-Separated list of headers allowed in cross-origin requests." +
+		" Defaults to Accept,Content-Type,Authorization,X-API-Key,Idempotency-Key if not set." +
+		" Alternatively, this can be set with the following environment variable: " + corsAllowedHeadersEnvKey
+
+	grpcHostURLFlagName  = "grpc-host-url"
+	grpcHostURLEnvKey    = "DID_METHOD_GRPC_HOST_URL"
+	grpcHostURLFlagUsage = "URL to run the gRPC API of the did method operations on. Format: HostName:Port." +
+		" The gRPC API is disabled if not set, and isn't available in public-registrar mode." +
+		" Alternatively, this can be set with the following environment variable: " + grpcHostURLEnvKey
+
+	grpcTLSCertFlagName  = "grpc-tls-cert"
+	grpcTLSCertEnvKey    = "DID_METHOD_GRPC_TLS_CERT"
+	grpcTLSCertFlagUsage = "Path of the PEM server certificate of the gRPC API. Must be set with grpc-tls-key." +
+		" The gRPC API is served without TLS if not set, which is refused when the requests are authenticated." +
+		" Alternatively, this can be set with the following environment variable: " + grpcTLSCertEnvKey
+
+	grpcTLSKeyFlagName  = "grpc-tls-key"
+	grpcTLSKeyEnvKey    = "DID_METHOD_GRPC_TLS_KEY"
+	grpcTLSKeyFlagUsage = "Path of the PEM private key of the gRPC server certificate. Must be set with grpc-tls-cert." +
+		" Alternatively, this can be set with the following environment variable: " + grpcTLSKeyEnvKey
+
+	rateLimitFlagName  = "rate-limit"
+	rateLimitEnvKey    = "DID_METHOD_RATE_LIMIT"
+	rateLimitFlagUsage = "Maximum number of requests to the did method endpoints per client per rate limit window," +
+		" the clients being identified by their authenticated identity or else by their IP address." +
+		" Rate limiting is disabled if not set." +
+		" Alternatively, this can be set with the following environment variable: " + rateLimitEnvKey
+
+	rateLimitWindowFlagName  = "rate-limit-window"
+	rateLimitWindowEnvKey    = "DID_METHOD_RATE_LIMIT_WINDOW"
+	rateLimitWindowFlagUsage = "Duration of the rate limit window, for example 1m or 1h. Defaults to 1m if not set." +
+		" Alternatively, this can be set with the following environment variable: " + rateLimitWindowEnvKey
+
+	idempotencyKeyTTLFlagName  = "idempotency-key-ttl"
+	idempotencyKeyTTLEnvKey    = "DID_METHOD_IDEMPOTENCY_KEY_TTL"
+	idempotencyKeyTTLFlagUsage = "Duration the responses to the did method requests with an Idempotency-Key header" +
+		" are kept for, for example 10m or 24h, the requests retried with the same key getting the stored response." +
+		" Idempotency keys are ignored if not set." +
+		" Alternatively, this can be set with the following environment variable: " + idempotencyKeyTTLEnvKey
+
+	wellKnownDirFlagName  = "well-known-dir"
+	wellKnownDirEnvKey    = "DID_METHOD_WELL_KNOWN_DIR"
+	wellKnownDirFlagUsage = "Directory of the consortium and stakeholder config files hosted at" +
+		" /.well-known/did-trustbloc/, laid out as the .well-known directory written by the create-config command," +
+		" ie. <dir>/did-trustbloc/<domain>.json. Required in 'config-host' mode." +
+		" Alternatively, this can be set with the following environment variable: " + wellKnownDirEnvKey
+
+	defaultPublicRegistrationDifficulty = 20
+	minPublicRegistrationDifficulty     = 1
+	maxPublicRegistrationDifficulty     = 256
+	defaultPublicRegistrationRateLimit  = 10
+	defaultRateLimitWindow              = time.Minute
+	webhookTimeout                      = 10 * time.Second
+	dohTimeout                          = 10 * time.Second
+	maxIdempotencyKeys                  = 10000
 )
 
 // mode in which to run the did-method service
 type mode string
 
 const (
-	registrar mode = "registrar"
-	resolver  mode = "resolver"
-	combined  mode = "combined"
+	registrar       mode = "registrar"
+	resolver        mode = "resolver"
+	combined        mode = "combined"
+	publicRegistrar mode = "public-registrar"
+	configHost      mode = "config-host"
 )
 
 type server interface {
@@ -94,11 +333,56 @@ type parameters struct {
 	hostURL            string
 	tlsSystemCertPool  bool
 	tlsCACerts         []string
+	tlsClientCert      string
+	tlsClientKey       string
+	tlsOpts            []tlsconfig.Option
+	dohURL             string
+	dnssecDomains      []string
 	blocDomain         string
 	mode               string
 	sidetreeReadToken  string
 	sidetreeWriteToken string
 	enableSignatures   bool
+	publicRegistration *publicRegistrationParameters
+	keyStore           string
+	keyStorePassword   string
+	deactivationToken  string
+	auth               *authParameters
+	rateLimit          *rateLimitParameters
+	idempotencyKeyTTL  time.Duration
+	asyncOperations    bool
+	webhookURLs        []string
+	webhookSecret      string
+	callbackHosts      []string
+	cors               *cors.Config
+	grpcHostURL        string
+	grpcTLSCert        string
+	grpcTLSKey         string
+	wellKnownDir       string
+}
+
+// authParameters configure the authentication of the requests to the did method endpoints, which is disabled if
+// neither API keys nor an introspection URL are set
+type authParameters struct {
+	apiKeys          []string
+	introspectionURL string
+	clientID         string
+	clientSecret     string
+}
+
+// rateLimitParameters configure the rate limiting of the requests to the did method endpoints, which is disabled if
+// the limit is not set
+type rateLimitParameters struct {
+	limit  int
+	window time.Duration
+}
+
+type publicRegistrationParameters struct {
+	secret           string
+	difficulty       int
+	rateLimit        int
+	captchaVerifyURL string
+	captchaSecret    string
 }
 
 // GetStartCmd returns the Cobra start command.
@@ -143,15 +427,9 @@ func createStartCmd(srv server) *cobra.Command {
 			sidetreeWriteToken := cmdutils.GetUserSetOptionalVarFromString(cmd, sidetreeWriteTokenFlagName,
 				sidetreeWriteTokenEnvKey)
 
-			enableSignaturesString := cmdutils.GetUserSetOptionalVarFromString(cmd, enableSignaturesFlagName,
-				enableSignaturesEnvKey)
-
-			enableSignatures := true
-			if enableSignaturesString != "" {
-				enableSignatures, err = strconv.ParseBool(enableSignaturesString)
-				if err != nil {
-					return err
-				}
+			enableSignatures, err := getBool(cmd, enableSignaturesFlagName, enableSignaturesEnvKey, true)
+			if err != nil {
+				return err
 			}
 
 			parameters := &parameters{
@@ -166,11 +444,173 @@ func createStartCmd(srv server) *cobra.Command {
 				enableSignatures:   enableSignatures,
 			}
 
+			err = setServiceParameters(cmd, parameters)
+			if err != nil {
+				return err
+			}
+
 			return startDidMethod(parameters)
 		},
 	}
 }
 
+// setServiceParameters sets the parameters of the optional features of the service
+func setServiceParameters(cmd *cobra.Command, p *parameters) error {
+	publicRegistration, err := getPublicRegistration(cmd, p.mode)
+	if err != nil {
+		return err
+	}
+
+	rateLimit, err := getRateLimit(cmd)
+	if err != nil {
+		return err
+	}
+
+	idempotencyKeyTTL, err := getIdempotencyKeyTTL(cmd)
+	if err != nil {
+		return err
+	}
+
+	asyncOperations, err := getBool(cmd, asyncOperationsFlagName, asyncOperationsEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	p.publicRegistration = publicRegistration
+	p.keyStore = cmdutils.GetUserSetOptionalVarFromString(cmd, keyStoreFlagName, keyStoreEnvKey)
+	p.keyStorePassword = cmdutils.GetUserSetOptionalVarFromString(cmd, keyStorePasswordFlagName,
+		keyStorePasswordEnvKey)
+	p.deactivationToken = cmdutils.GetUserSetOptionalVarFromString(cmd, deactivationTokenFlagName,
+		deactivationTokenEnvKey)
+	p.auth = getAuth(cmd)
+	p.rateLimit = rateLimit
+	p.idempotencyKeyTTL = idempotencyKeyTTL
+	p.asyncOperations = asyncOperations
+
+	p.cors = getCORS(cmd)
+	p.grpcHostURL = cmdutils.GetUserSetOptionalVarFromString(cmd, grpcHostURLFlagName, grpcHostURLEnvKey)
+
+	err = setWebhookParameters(cmd, p)
+	if err != nil {
+		return err
+	}
+
+	err = setTLSParameters(cmd, p)
+	if err != nil {
+		return err
+	}
+
+	err = setDNSParameters(cmd, p)
+	if err != nil {
+		return err
+	}
+
+	p.wellKnownDir = cmdutils.GetUserSetOptionalVarFromString(cmd, wellKnownDirFlagName, wellKnownDirEnvKey)
+
+	if p.mode == string(configHost) && p.wellKnownDir == "" {
+		return fmt.Errorf("%s is required in %s mode", wellKnownDirFlagName, configHost)
+	}
+
+	return nil
+}
+
+// setWebhookParameters sets the webhooks notified of the operations, and the hosts of the callback URLs
+func setWebhookParameters(cmd *cobra.Command, p *parameters) error {
+	p.webhookURLs = cmdutils.GetUserSetOptionalVarFromArrayString(cmd, webhookURLsFlagName, webhookURLsEnvKey)
+	p.webhookSecret = cmdutils.GetUserSetOptionalVarFromString(cmd, webhookSecretFlagName, webhookSecretEnvKey)
+	p.callbackHosts = cmdutils.GetUserSetOptionalVarFromArrayString(cmd, webhookCallbackHostsFlagName,
+		webhookCallbackHostsEnvKey)
+
+	if len(p.webhookURLs) > 0 && p.webhookSecret == "" {
+		return fmt.Errorf("%s is required with %s", webhookSecretFlagName, webhookURLsFlagName)
+	}
+
+	return nil
+}
+
+// setTLSParameters sets the client certificate and the TLS settings of the connections of the server
+func setTLSParameters(cmd *cobra.Command, p *parameters) error {
+	p.tlsClientCert = cmdutils.GetUserSetOptionalVarFromString(cmd, tlsClientCertFlagName, tlsClientCertEnvKey)
+	p.tlsClientKey = cmdutils.GetUserSetOptionalVarFromString(cmd, tlsClientKeyFlagName, tlsClientKeyEnvKey)
+
+	if (p.tlsClientCert == "") != (p.tlsClientKey == "") {
+		return fmt.Errorf("%s and %s must be set together", tlsClientCertFlagName, tlsClientKeyFlagName)
+	}
+
+	p.grpcTLSCert = cmdutils.GetUserSetOptionalVarFromString(cmd, grpcTLSCertFlagName, grpcTLSCertEnvKey)
+	p.grpcTLSKey = cmdutils.GetUserSetOptionalVarFromString(cmd, grpcTLSKeyFlagName, grpcTLSKeyEnvKey)
+
+	if (p.grpcTLSCert == "") != (p.grpcTLSKey == "") {
+		return fmt.Errorf("%s and %s must be set together", grpcTLSCertFlagName, grpcTLSKeyFlagName)
+	}
+
+	tlsOpts, err := getTLSOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	p.tlsOpts = tlsOpts
+
+	return nil
+}
+
+// setDNSParameters sets the DNS-over-HTTPS server resolving the hosts the server connects to, and the domains whose
+// names must be authenticated with DNSSEC
+func setDNSParameters(cmd *cobra.Command, p *parameters) error {
+	p.dohURL = cmdutils.GetUserSetOptionalVarFromString(cmd, dohURLFlagName, dohURLEnvKey)
+	p.dnssecDomains = cmdutils.GetUserSetOptionalVarFromArrayString(cmd, dnssecDomainsFlagName, dnssecDomainsEnvKey)
+
+	if len(p.dnssecDomains) > 0 && p.dohURL == "" {
+		return fmt.Errorf("%s is required with %s", dohURLFlagName, dnssecDomainsFlagName)
+	}
+
+	return nil
+}
+
+func getTLSOptions(cmd *cobra.Command) ([]tlsconfig.Option, error) {
+	var opts []tlsconfig.Option
+
+	if minVersion := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsMinVersionFlagName,
+		tlsMinVersionEnvKey); minVersion != "" {
+		version, err := tlsconfig.ParseVersion(minVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, tlsconfig.WithMinVersion(version))
+	}
+
+	if names := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCipherSuitesFlagName,
+		tlsCipherSuitesEnvKey); len(names) > 0 {
+		suites, err := tlsconfig.ParseCipherSuites(names)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, tlsconfig.WithCipherSuites(suites...))
+	}
+
+	if values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsPinsFlagName, tlsPinsEnvKey); len(values) > 0 {
+		pins, err := tlsconfig.ParsePins(values)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, tlsconfig.WithPins(pins))
+	}
+
+	insecureSkipVerify, err := getBool(cmd, tlsInsecureSkipVerifyFlagName, tlsInsecureSkipVerifyEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if insecureSkipVerify {
+		opts = append(opts, tlsconfig.WithInsecureSkipVerify(true))
+	}
+
+	return opts, nil
+}
+
 func getTLS(cmd *cobra.Command) (bool, []string, error) {
 	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
 		tlsSystemCertPoolEnvKey)
@@ -192,6 +632,140 @@ func getTLS(cmd *cobra.Command) (bool, []string, error) {
 	return tlsSystemCertPool, tlsCACerts, nil
 }
 
+func getAuth(cmd *cobra.Command) *authParameters {
+	return &authParameters{
+		apiKeys: cmdutils.GetUserSetOptionalVarFromArrayString(cmd, apiKeysFlagName, apiKeysEnvKey),
+		introspectionURL: cmdutils.GetUserSetOptionalVarFromString(cmd, oauth2IntrospectionURLFlagName,
+			oauth2IntrospectionURLEnvKey),
+		clientID: cmdutils.GetUserSetOptionalVarFromString(cmd, oauth2ClientIDFlagName, oauth2ClientIDEnvKey),
+		clientSecret: cmdutils.GetUserSetOptionalVarFromString(cmd, oauth2ClientSecretFlagName,
+			oauth2ClientSecretEnvKey),
+	}
+}
+
+func getCORS(cmd *cobra.Command) *cors.Config {
+	allowedOrigins := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, corsAllowedOriginsFlagName,
+		corsAllowedOriginsEnvKey)
+	if len(allowedOrigins) == 0 {
+		return nil
+	}
+
+	return &cors.Config{
+		AllowedOrigins: allowedOrigins,
+		AllowedMethods: cmdutils.GetUserSetOptionalVarFromArrayString(cmd, corsAllowedMethodsFlagName,
+			corsAllowedMethodsEnvKey),
+		AllowedHeaders: cmdutils.GetUserSetOptionalVarFromArrayString(cmd, corsAllowedHeadersFlagName,
+			corsAllowedHeadersEnvKey),
+	}
+}
+
+func getRateLimit(cmd *cobra.Command) (*rateLimitParameters, error) {
+	limit, err := getInt(cmd, rateLimitFlagName, rateLimitEnvKey, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	window := defaultRateLimitWindow
+
+	if windowString := cmdutils.GetUserSetOptionalVarFromString(cmd, rateLimitWindowFlagName,
+		rateLimitWindowEnvKey); windowString != "" {
+		window, err = time.ParseDuration(windowString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", rateLimitWindowFlagName, err)
+		}
+	}
+
+	if limit < 0 || window <= 0 {
+		return nil, fmt.Errorf("%s and %s must be positive", rateLimitFlagName, rateLimitWindowFlagName)
+	}
+
+	return &rateLimitParameters{limit: limit, window: window}, nil
+}
+
+func getIdempotencyKeyTTL(cmd *cobra.Command) (time.Duration, error) {
+	ttlString := cmdutils.GetUserSetOptionalVarFromString(cmd, idempotencyKeyTTLFlagName, idempotencyKeyTTLEnvKey)
+	if ttlString == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(ttlString)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", idempotencyKeyTTLFlagName, err)
+	}
+
+	if ttl <= 0 {
+		return 0, fmt.Errorf("%s must be positive", idempotencyKeyTTLFlagName)
+	}
+
+	return ttl, nil
+}
+
+func getPublicRegistration(cmd *cobra.Command, mode string) (*publicRegistrationParameters, error) {
+	if mode != string(publicRegistrar) {
+		return nil, nil
+	}
+
+	secret, err := cmdutils.GetUserSetVarFromString(cmd, publicRegistrationSecretFlagName,
+		publicRegistrationSecretEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	difficulty, err := getInt(cmd, publicRegistrationDifficultyFlagName, publicRegistrationDifficultyEnvKey,
+		defaultPublicRegistrationDifficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	if difficulty < minPublicRegistrationDifficulty || difficulty > maxPublicRegistrationDifficulty {
+		return nil, fmt.Errorf("%s must be between %d and %d", publicRegistrationDifficultyFlagName,
+			minPublicRegistrationDifficulty, maxPublicRegistrationDifficulty)
+	}
+
+	rateLimit, err := getInt(cmd, publicRegistrationRateLimitFlagName, publicRegistrationRateLimitEnvKey,
+		defaultPublicRegistrationRateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publicRegistrationParameters{
+		secret:     secret,
+		difficulty: difficulty,
+		rateLimit:  rateLimit,
+		captchaVerifyURL: cmdutils.GetUserSetOptionalVarFromString(cmd, captchaVerifyURLFlagName,
+			captchaVerifyURLEnvKey),
+		captchaSecret: cmdutils.GetUserSetOptionalVarFromString(cmd, captchaSecretFlagName, captchaSecretEnvKey),
+	}, nil
+}
+
+func getInt(cmd *cobra.Command, flagName, envKey string, defaultValue int) (int, error) {
+	value := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", flagName, err)
+	}
+
+	return n, nil
+}
+
+func getBool(cmd *cobra.Command, flagName, envKey string, defaultValue bool) (bool, error) {
+	value := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", flagName, err)
+	}
+
+	return b, nil
+}
+
 func getMode(cmd *cobra.Command) (string, error) {
 	mode := cmdutils.GetUserSetOptionalVarFromString(cmd, modeFlagName, modeEnvKey)
 
@@ -211,54 +785,391 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, tlsSystemCertPoolFlagShorthand, "",
 		tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, tlsCACertsFlagShorthand, []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(tlsClientCertFlagName, "", "", tlsClientCertFlagUsage)
+	startCmd.Flags().StringP(tlsClientKeyFlagName, "", "", tlsClientKeyFlagUsage)
+	startCmd.Flags().StringP(tlsMinVersionFlagName, "", "", tlsMinVersionFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCipherSuitesFlagName, "", []string{}, tlsCipherSuitesFlagUsage)
+	startCmd.Flags().StringP(tlsInsecureSkipVerifyFlagName, "", "", tlsInsecureSkipVerifyFlagUsage)
+	startCmd.Flags().StringArrayP(tlsPinsFlagName, "", []string{}, tlsPinsFlagUsage)
+	startCmd.Flags().StringP(dohURLFlagName, "", "", dohURLFlagUsage)
+	startCmd.Flags().StringArrayP(dnssecDomainsFlagName, "", []string{}, dnssecDomainsFlagUsage)
 	startCmd.Flags().StringP(domainFlagName, domainFlagShorthand, "", domainFlagUsage)
 	startCmd.Flags().StringP(modeFlagName, modeFlagShorthand, "", modeFlagUsage)
 	startCmd.Flags().StringP(sidetreeReadTokenFlagName, "", "", sidetreeReadTokenFlagUsage)
 	startCmd.Flags().StringP(sidetreeWriteTokenFlagName, "", "", sidetreeWriteTokenFlagUsage)
 	startCmd.Flags().StringP(enableSignaturesFlagName, "", "", enableSignaturesFlagUsage)
+	startCmd.Flags().StringP(publicRegistrationSecretFlagName, "", "", publicRegistrationSecretFlagUsage)
+	startCmd.Flags().StringP(publicRegistrationDifficultyFlagName, "", "", publicRegistrationDifficultyFlagUsage)
+	startCmd.Flags().StringP(publicRegistrationRateLimitFlagName, "", "", publicRegistrationRateLimitFlagUsage)
+	startCmd.Flags().StringP(captchaVerifyURLFlagName, "", "", captchaVerifyURLFlagUsage)
+	startCmd.Flags().StringP(captchaSecretFlagName, "", "", captchaSecretFlagUsage)
+	startCmd.Flags().StringP(keyStoreFlagName, "", "", keyStoreFlagUsage)
+	startCmd.Flags().StringP(keyStorePasswordFlagName, "", "", keyStorePasswordFlagUsage)
+	startCmd.Flags().StringP(deactivationTokenFlagName, "", "", deactivationTokenFlagUsage)
+	startCmd.Flags().StringArrayP(apiKeysFlagName, "", []string{}, apiKeysFlagUsage)
+	startCmd.Flags().StringP(oauth2IntrospectionURLFlagName, "", "", oauth2IntrospectionURLFlagUsage)
+	startCmd.Flags().StringP(oauth2ClientIDFlagName, "", "", oauth2ClientIDFlagUsage)
+	startCmd.Flags().StringP(oauth2ClientSecretFlagName, "", "", oauth2ClientSecretFlagUsage)
+	startCmd.Flags().StringP(asyncOperationsFlagName, "", "", asyncOperationsFlagUsage)
+	startCmd.Flags().StringArrayP(webhookURLsFlagName, "", []string{}, webhookURLsFlagUsage)
+	startCmd.Flags().StringP(webhookSecretFlagName, "", "", webhookSecretFlagUsage)
+	startCmd.Flags().StringArrayP(webhookCallbackHostsFlagName, "", []string{}, webhookCallbackHostsFlagUsage)
+	startCmd.Flags().StringArrayP(corsAllowedOriginsFlagName, "", []string{}, corsAllowedOriginsFlagUsage)
+	startCmd.Flags().StringArrayP(corsAllowedMethodsFlagName, "", []string{}, corsAllowedMethodsFlagUsage)
+	startCmd.Flags().StringArrayP(corsAllowedHeadersFlagName, "", []string{}, corsAllowedHeadersFlagUsage)
+	startCmd.Flags().StringP(rateLimitFlagName, "", "", rateLimitFlagUsage)
+	startCmd.Flags().StringP(rateLimitWindowFlagName, "", "", rateLimitWindowFlagUsage)
+	startCmd.Flags().StringP(idempotencyKeyTTLFlagName, "", "", idempotencyKeyTTLFlagUsage)
+	startCmd.Flags().StringP(grpcHostURLFlagName, "", "", grpcHostURLFlagUsage)
+	startCmd.Flags().StringP(grpcTLSCertFlagName, "", "", grpcTLSCertFlagUsage)
+	startCmd.Flags().StringP(grpcTLSKeyFlagName, "", "", grpcTLSKeyFlagUsage)
+	startCmd.Flags().StringP(wellKnownDirFlagName, "", "", wellKnownDirFlagUsage)
 }
 
 func startDidMethod(parameters *parameters) error {
+	tlsConfig, err := newTLSConfig(parameters)
+	if err != nil {
+		return err
+	}
+
+	router := mux.NewRouter()
+
+	addHealthCheckHandlers(router, parameters.blocDomain, tlsConfig)
+
+	if parameters.wellKnownDir != "" {
+		addWellKnownHandlers(router, parameters.wellKnownDir)
+	}
+
+	if parameters.mode != string(configHost) {
+		err = addOperationHandlers(router, parameters, tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	var handler http.Handler = router
+
+	if parameters.cors != nil {
+		// preflight requests are answered before routing, the router only has the routes of the allowed methods
+		handler = cors.Handler(parameters.cors, router)
+	}
+
+	return parameters.srv.ListenAndServe(parameters.hostURL, correlation.Handler(handler))
+}
+
+// newTLSConfig returns the TLS config of the connections of the server, trusting the root CAs and presenting the
+// client certificate if set, with the TLS settings
+func newTLSConfig(parameters *parameters) (*tls.Config, error) {
 	rootCAs, err := tlsutils.GetCertPool(parameters.tlsSystemCertPool, parameters.tlsCACerts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []tlsconfig.Option{tlsconfig.WithRootCAs(rootCAs)}
+	opts = append(opts, parameters.tlsOpts...)
+
+	if parameters.tlsClientCert != "" {
+		cert, errLoad := tls.LoadX509KeyPair(parameters.tlsClientCert, parameters.tlsClientKey)
+		if errLoad != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", errLoad)
+		}
+
+		opts = append(opts, tlsconfig.WithClientCertificates(cert))
+	}
+
+	tlsConfig := tlsconfig.New(opts...)
+
+	if tlsConfig.InsecureSkipVerify {
+		log.Printf("WARNING: the server certificates are not verified, %s is for development only",
+			tlsInsecureSkipVerifyFlagName)
+	}
+
+	return tlsConfig, nil
+}
+
+// addOperationHandlers adds the metrics and did method endpoints, and starts the gRPC API if enabled
+func addOperationHandlers(router *mux.Router, parameters *parameters, tlsConfig *tls.Config) error {
+	metricsRegistry := metrics.NewRegistry()
+
+	config, err := didMethodConfig(parameters, tlsConfig, metricsRegistry)
 	if err != nil {
 		return err
 	}
 
-	didMethodService, err := didmethod.New(&operation.Config{TLSConfig: &tls.Config{RootCAs: rootCAs,
-		MinVersion: tls.VersionTLS12}, BlocDomain: parameters.blocDomain, Mode: parameters.mode,
-		SidetreeReadToken: parameters.sidetreeReadToken, SidetreeWriteToken: parameters.sidetreeWriteToken,
-		EnableSignatures: parameters.enableSignatures})
+	didMethodService, err := didmethod.New(config)
 	if err != nil {
 		return err
 	}
 
-	router := mux.NewRouter()
+	prot, err := getProtection(parameters, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	// add metrics endpoint, exposing the request metrics of the did method endpoints and of sidetree
+	for _, handler := range metricsrest.New(metricsRegistry).GetOperations() {
+		router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+	}
+
+	err = addDIDMethodHandlers(router, didMethodService, metricsRegistry, prot)
+	if err != nil {
+		return err
+	}
 
-	// add health check endpoint
-	healthCheckService := healthcheck.New()
+	if parameters.grpcHostURL != "" {
+		return startGRPC(parameters, didMethodService, prot, tlsConfig)
+	}
+
+	return nil
+}
 
-	healthCheckHandlers := healthCheckService.GetOperations()
-	for _, handler := range healthCheckHandlers {
+// addWellKnownHandlers adds the hosting of the consortium and stakeholder config files of the directory, so that a
+// stakeholder can host its config files with the server of its did method operations
+func addWellKnownHandlers(router *mux.Router, wellKnownDir string) {
+	for _, handler := range wellknown.New(wellknownop.NewDirSource(wellKnownDir)).GetOperations() {
 		router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
 	}
+}
 
-	handlers := didMethodService.GetOperations()
+// addDIDMethodHandlers adds the did method endpoints, with their requests validated against the OpenAPI document
+// served at /openapi.json
+func addDIDMethodHandlers(router *mux.Router, didMethodService *didmethod.Controller, metricsRegistry *metrics.Registry,
+	prot *protection) error {
+	openAPIService, err := openapi.New()
+	if err != nil {
+		return err
+	}
 
-	for _, handler := range handlers {
+	for _, handler := range openAPIService.GetOperations() {
 		router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
 	}
 
-	return parameters.srv.ListenAndServe(parameters.hostURL, router)
+	for _, handler := range didMethodService.GetOperations() {
+		handle := openAPIService.Validate(handler.Path(), handler.Method(), handler.Handle())
+
+		router.HandleFunc(handler.Path(), metricsop.InstrumentHandler(metricsRegistry, handler.Path(),
+			handler.Method(), protect(handle, prot))).Methods(handler.Method())
+	}
+
+	return nil
+}
+
+func didMethodConfig(parameters *parameters, tlsConfig *tls.Config,
+	metricsProvider metrics.Provider) (*operation.Config, error) {
+	config := &operation.Config{TLSConfig: tlsConfig,
+		BlocDomain: parameters.blocDomain, Mode: parameters.mode,
+		SidetreeReadToken: parameters.sidetreeReadToken, SidetreeWriteToken: parameters.sidetreeWriteToken,
+		EnableSignatures:   parameters.enableSignatures,
+		PublicRegistration: publicRegistrationConfig(parameters.publicRegistration, tlsConfig),
+		DeactivationToken:  parameters.deactivationToken, MetricsProvider: metricsProvider}
+
+	if parameters.keyStore != "" {
+		keyStore, err := operation.NewEncryptedKeyStore(parameters.keyStore, []byte(parameters.keyStorePassword))
+		if err != nil {
+			return nil, err
+		}
+
+		config.KeyStore = keyStore
+	}
+
+	if parameters.asyncOperations {
+		config.AsyncOperations = &operation.AsyncOperationsConfig{}
+	}
+
+	if parameters.webhookSecret != "" {
+		config.Webhooks = &operation.WebhooksConfig{URLs: parameters.webhookURLs, Secret: []byte(parameters.webhookSecret),
+			HTTPClient:    &http.Client{Timeout: webhookTimeout, Transport: newTransport(tlsConfig)},
+			CallbackHosts: parameters.callbackHosts}
+	}
+
+	if parameters.dohURL != "" {
+		config.Resolver = doh.NewResolver(parameters.dohURL, doh.WithDNSSEC(parameters.dnssecDomains...),
+			doh.WithHTTPClient(&http.Client{Timeout: dohTimeout, Transport: newTransport(tlsConfig)}))
+	}
+
+	return config, nil
+}
+
+// startGRPC serves the gRPC API of the did method operations in the background, the calls being protected as the
+// REST requests
+func startGRPC(parameters *parameters, didMethodService *didmethod.Controller, prot *protection,
+	tlsConfig *tls.Config) error {
+	serverOpts, err := grpcServerOptions(parameters, prot, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	didMethodServer, err := didMethodService.GetGRPCServer()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", parameters.grpcHostURL)
+	if err != nil {
+		return fmt.Errorf("failed to listen on grpc host url %s: %w", parameters.grpcHostURL, err)
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+
+	didmethodpb.RegisterDIDMethodServer(grpcServer, didMethodServer)
+
+	go func() {
+		if serveErr := grpcServer.Serve(listener); serveErr != nil {
+			log.Printf("gRPC server stopped: %s", serveErr)
+		}
+	}()
+
+	return nil
+}
+
+// grpcServerOptions returns the interceptors applying the protection of the REST requests to the gRPC calls, and the
+// TLS credentials of the server with the TLS settings, without which the calls can't be authenticated
+func grpcServerOptions(parameters *parameters, prot *protection, tlsConfig *tls.Config) ([]grpc.ServerOption, error) {
+	interceptors := []grpc.UnaryServerInterceptor{grpcapi.CorrelationInterceptor(),
+		grpcapi.AuthInterceptor(prot.authenticators)}
+
+	if prot.limiter != nil {
+		interceptors = append(interceptors, ratelimit.UnaryInterceptor(prot.limiter))
+	}
+
+	if prot.idempotency != nil {
+		interceptors = append(interceptors, idempotency.UnaryInterceptor(prot.idempotency))
+	}
+
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(interceptors...)}
+
+	if parameters.grpcTLSCert == "" {
+		if len(prot.authenticators) > 0 {
+			return nil, fmt.Errorf("%s and %s are required to authenticate the gRPC calls", grpcTLSCertFlagName,
+				grpcTLSKeyFlagName)
+		}
+
+		return opts, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(parameters.grpcTLSCert, parameters.grpcTLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the gRPC server certificate: %w", err)
+	}
+
+	return append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert},
+		MinVersion: tlsConfig.MinVersion, CipherSuites: tlsConfig.CipherSuites}))), nil
+}
+
+// addHealthCheckHandlers adds the health check and readiness endpoints, the service is ready when the consortium
+// domain and one of its sidetree endpoints are reachable
+func addHealthCheckHandlers(router *mux.Router, blocDomain string, tlsConfig *tls.Config) {
+	var readinessChecks []healthcheckop.ReadinessCheck
+
+	if blocDomain != "" {
+		readinessChecks = healthcheckop.NewDomainChecks(blocDomain, tlsConfig)
+	}
+
+	for _, handler := range healthcheck.New(readinessChecks...).GetOperations() {
+		router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+	}
+}
+
+// protection is the authentication, rate limiting and idempotency of the did method endpoints, each disabled if nil
+type protection struct {
+	authenticators []auth.Authenticator
+	limiter        *ratelimit.Limiter
+	idempotency    *idempotency.Store
+}
+
+func getProtection(p *parameters, tlsConfig *tls.Config) (*protection, error) {
+	authenticators, err := getAuthenticators(p.auth, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	prot := &protection{authenticators: authenticators}
+
+	if p.rateLimit != nil && p.rateLimit.limit > 0 {
+		prot.limiter = ratelimit.New(p.rateLimit.limit, p.rateLimit.window)
+	}
+
+	if p.idempotencyKeyTTL > 0 {
+		prot.idempotency = idempotency.New(p.idempotencyKeyTTL, maxIdempotencyKeys)
+	}
+
+	return prot, nil
+}
+
+// protect wraps the did method handler with authentication, rate limiting and idempotency, when enabled, the requests
+// being authenticated first so that the clients are rate limited and their idempotency keys scoped by their identity,
+// and rate limited before a stored response is replayed
+func protect(handle http.HandlerFunc, p *protection) http.HandlerFunc {
+	if p.idempotency != nil {
+		handle = idempotency.Handler(p.idempotency, handle)
+	}
+
+	if p.limiter != nil {
+		handle = ratelimit.Handler(p.limiter, handle)
+	}
+
+	if len(p.authenticators) > 0 {
+		handle = auth.Handler(p.authenticators, handle)
+	}
+
+	return handle
+}
+
+func getAuthenticators(p *authParameters, tlsConfig *tls.Config) ([]auth.Authenticator, error) {
+	var authenticators []auth.Authenticator
+
+	if len(p.apiKeys) > 0 {
+		apiKeys, err := auth.NewAPIKeyAuthenticator(p.apiKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		authenticators = append(authenticators, apiKeys)
+	}
+
+	if p.introspectionURL != "" {
+		authenticators = append(authenticators, auth.NewIntrospectionAuthenticator(p.introspectionURL,
+			p.clientID, p.clientSecret, &http.Client{Transport: newTransport(tlsConfig)}))
+	}
+
+	return authenticators, nil
+}
+
+func publicRegistrationConfig(p *publicRegistrationParameters,
+	tlsConfig *tls.Config) *operation.PublicRegistrationConfig {
+	if p == nil {
+		return nil
+	}
+
+	config := &operation.PublicRegistrationConfig{
+		ChallengeSecret: []byte(p.secret),
+		Difficulty:      p.difficulty,
+		RateLimit:       p.rateLimit,
+	}
+
+	if p.captchaVerifyURL != "" {
+		config.TokenVerifier = operation.NewSiteVerifyTokenVerifier(p.captchaVerifyURL, p.captchaSecret,
+			&http.Client{Transport: newTransport(tlsConfig)})
+	}
+
+	return config
+}
+
+// newTransport returns the transport of the outbound requests of the server, through the proxy set by the HTTPS_PROXY
+// and HTTP_PROXY environment variables if any
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}
 }
 
 func supportedMode(mode string) bool {
-	if len(mode) > 0 && mode != string(registrar) && mode != string(resolver) {
+	switch mode {
+	case "", string(registrar), string(resolver), string(combined), string(publicRegistrar), string(configHost):
+		return true
+	default:
 		return false
 	}
-
-	return true
 }
 
 func isRegistrar(mode string) bool {
-	return mode == string(registrar) || mode == string(combined)
+	return mode == string(registrar) || mode == string(combined) || mode == string(publicRegistrar)
 }
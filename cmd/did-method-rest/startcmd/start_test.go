@@ -6,9 +6,19 @@ SPDX-License-Identifier: Apache-2.0
 package startcmd
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
@@ -59,6 +69,325 @@ func TestStartCmdValidArgs(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestStartCmdWithKeyStoreArgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	t.Run("test key store", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+keyStoreFlagName, filepath.Join(dir, "keystore.json"), flag+keyStorePasswordFlagName,
+			"password", flag+deactivationTokenFlagName, "token")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+		require.FileExists(t, filepath.Join(dir, "keystore.json"))
+	})
+
+	t.Run("test missing key store password", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+keyStoreFlagName, filepath.Join(dir, "other.json"))
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "keystore password is required")
+	})
+}
+
+func TestStartCmdWithAuthArgs(t *testing.T) {
+	t.Run("test api keys and introspection", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+apiKeysFlagName, "wallet=key1", flag+oauth2IntrospectionURLFlagName,
+			"https://localhost/introspect", flag+oauth2ClientIDFlagName, "did-method")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test invalid api key", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+apiKeysFlagName, "key1")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expected client=key")
+	})
+}
+
+func TestStartCmdWithRateLimitArgs(t *testing.T) {
+	t.Run("test rate limit", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+rateLimitFlagName, "100", flag+rateLimitWindowFlagName, "1h")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test invalid rate limit", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+rateLimitFlagName, "-1")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be positive")
+	})
+
+	t.Run("test invalid rate limit window", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+rateLimitFlagName, "100", flag+rateLimitWindowFlagName, "hour")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for rate-limit-window")
+	})
+}
+
+func TestStartCmdWithIdempotencyKeyTTLArg(t *testing.T) {
+	t.Run("test idempotency key ttl", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+idempotencyKeyTTLFlagName, "24h")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test invalid idempotency key ttl", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+idempotencyKeyTTLFlagName, "day")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for idempotency-key-ttl")
+	})
+
+	t.Run("test negative idempotency key ttl", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+idempotencyKeyTTLFlagName, "-1h")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "idempotency-key-ttl must be positive")
+	})
+}
+
+func TestStartCmdWithAsyncOperationsArg(t *testing.T) {
+	t.Run("test async operations", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+asyncOperationsFlagName, "true")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test invalid async operations", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+asyncOperationsFlagName, "aaa")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for async-operations")
+	})
+}
+
+func TestStartCmdWithWebhookArgs(t *testing.T) {
+	t.Run("test webhooks", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+webhookURLsFlagName, "https://localhost/webhook", flag+webhookSecretFlagName,
+			"secret", flag+webhookCallbackHostsFlagName, "localhost")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test webhook urls without secret", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+webhookURLsFlagName, "https://localhost/webhook")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "webhook-secret is required with webhook-urls")
+	})
+}
+
+func TestStartCmdWithCORSArgs(t *testing.T) {
+	startCmd := GetStartCmd(&mockServer{})
+
+	args := getValidArgs()
+	args = append(args, flag+corsAllowedOriginsFlagName, "https://wallet.example.com",
+		flag+corsAllowedMethodsFlagName, "GET", flag+corsAllowedHeadersFlagName, "Content-Type")
+
+	startCmd.SetArgs(args)
+
+	err := startCmd.Execute()
+	require.NoError(t, err)
+}
+
+func TestStartCmdWithGRPCHostURLArg(t *testing.T) {
+	t.Run("test grpc api", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+grpcHostURLFlagName, "localhost:0")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test invalid grpc host url", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+grpcHostURLFlagName, "invalid")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to listen on grpc host url invalid")
+	})
+
+	t.Run("test grpc api with protections", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		certFile, keyFile := writeServerCertificate(t)
+
+		args := getValidArgs()
+		args = append(args, flag+grpcHostURLFlagName, "localhost:0", flag+apiKeysFlagName, "wallet=key1",
+			flag+rateLimitFlagName, "10", flag+grpcTLSCertFlagName, certFile, flag+grpcTLSKeyFlagName, keyFile)
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("test grpc api with authentication requires tls", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+grpcHostURLFlagName, "localhost:0", flag+apiKeysFlagName, "wallet=key1")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "grpc-tls-cert and grpc-tls-key are required to authenticate the gRPC calls")
+	})
+
+	t.Run("test grpc server cert and key must be set together", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+grpcHostURLFlagName, "localhost:0", flag+grpcTLSCertFlagName, "cert.pem")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "grpc-tls-cert and grpc-tls-key must be set together")
+	})
+
+	t.Run("test grpc server cert not found", func(t *testing.T) {
+		startCmd := GetStartCmd(&mockServer{})
+
+		args := getValidArgs()
+		args = append(args, flag+grpcHostURLFlagName, "localhost:0", flag+grpcTLSCertFlagName, "cert.pem",
+			flag+grpcTLSKeyFlagName, "key.pem")
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load the gRPC server certificate")
+	})
+}
+
+// writeServerCertificate writes a self-signed server certificate and its key, and returns their paths
+func writeServerCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "localhost"},
+		NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), DNSNames: []string{"localhost"}}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		0600))
+
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		0600))
+
+	return certFile, keyFile
+}
+
 func TestStartCmdWithInvalidEnableSignaturesArg(t *testing.T) {
 	startCmd := GetStartCmd(&mockServer{})
 
@@ -130,6 +459,224 @@ func TestDomainFlagVar(t *testing.T) {
 	})
 }
 
+func TestPublicRegistrarMode(t *testing.T) {
+	t.Run("test public registrar mode", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+modeFlagName, string(publicRegistrar),
+			flag+publicRegistrationSecretFlagName, "secret", flag+publicRegistrationDifficultyFlagName, "16",
+			flag+captchaVerifyURLFlagName, "https://hcaptcha.com/siteverify", flag+captchaSecretFlagName, "captcha"))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("test combined mode set explicitly", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+modeFlagName, string(combined)))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("test secret is required", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+modeFlagName, string(publicRegistrar)))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither public-registration-secret (command line flag) nor "+
+			"DID_METHOD_PUBLIC_REGISTRATION_SECRET (environment variable) have been set.")
+	})
+
+	t.Run("test invalid rate limit", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+modeFlagName, string(publicRegistrar),
+			flag+publicRegistrationSecretFlagName, "secret", flag+publicRegistrationRateLimitFlagName, "abc"))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for public-registration-rate-limit")
+	})
+
+	t.Run("test invalid difficulty", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+modeFlagName, string(publicRegistrar),
+			flag+publicRegistrationSecretFlagName, "secret", flag+publicRegistrationDifficultyFlagName, "abc"))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for public-registration-difficulty")
+
+		for _, difficulty := range []string{"0", "257"} {
+			startCmd = GetStartCmd(&mockServer{})
+
+			startCmd.SetArgs(append(getValidArgs(), flag+modeFlagName, string(publicRegistrar),
+				flag+publicRegistrationSecretFlagName, "secret", flag+publicRegistrationDifficultyFlagName, difficulty))
+
+			err = startCmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "public-registration-difficulty must be between 1 and 256")
+		}
+	})
+}
+
+func TestConfigHostMode(t *testing.T) {
+	t.Run("test config host mode", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(hostURLArg(), flag+modeFlagName, string(configHost),
+			flag+wellKnownDirFlagName, "wellknown"))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("test config hosted with the did method operations", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+wellKnownDirFlagName, "wellknown"))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("test well-known dir is required", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(hostURLArg(), flag+modeFlagName, string(configHost)))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "well-known-dir is required in config-host mode")
+	})
+}
+
+func TestTLSClientCertArgs(t *testing.T) {
+	t.Run("test client cert and key must be set together", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsClientCertFlagName, "cert.pem"))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "tls-client-cert and tls-client-key must be set together")
+	})
+
+	t.Run("test client cert not found", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsClientCertFlagName, "cert.pem",
+			flag+tlsClientKeyFlagName, "key.pem"))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load the client certificate")
+	})
+}
+
+func TestTLSSettingsArgs(t *testing.T) {
+	t.Run("test tls settings", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsMinVersionFlagName, "1.3",
+			flag+tlsCipherSuitesFlagName, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			flag+tlsInsecureSkipVerifyFlagName, "true",
+			flag+tlsPinsFlagName, "domain=sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("test invalid pin", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsPinsFlagName, "domain"))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "invalid pin domain, expected <host>=sha256/<base64 hash>")
+	})
+
+	t.Run("test invalid min version", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsMinVersionFlagName, "1.0"))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "unsupported TLS version 1.0, expected 1.2 or 1.3")
+	})
+
+	t.Run("test invalid cipher suite", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsCipherSuitesFlagName, "TLS_RSA_WITH_RC4_128_SHA"))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "unsupported cipher suite TLS_RSA_WITH_RC4_128_SHA")
+	})
+
+	t.Run("test invalid insecure skip verify", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+tlsInsecureSkipVerifyFlagName, "maybe"))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for tls-insecure-skip-verify")
+	})
+}
+
+func TestDNSArgs(t *testing.T) {
+	t.Run("test dns over https", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+dohURLFlagName, "https://1.1.1.1/dns-query",
+			flag+dnssecDomainsFlagName, "domain"))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("test dnssec domains without dns over https", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetStartCmd(&mockServer{})
+
+		startCmd.SetArgs(append(getValidArgs(), flag+dnssecDomainsFlagName, "domain"))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "dns-over-https-url is required with dnssec-domains")
+	})
+}
+
 func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
 	startCmd := GetStartCmd(&mockServer{})
 
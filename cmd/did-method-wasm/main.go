@@ -0,0 +1,130 @@
+//go:build js && wasm
+// +build js,wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package main is the WebAssembly build of the DID client and resolver for browser wallets. It sets the global
+// trustblocDID object, whose newClient function takes the {authToken, resolverURL} options of the bindings and
+// returns a client with the createDID, updateDID, recoverDID, deactivateDID and resolveDID functions of the
+// bindings. The requests and the documents are JSON strings, and the functions return promises.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/bindings"
+)
+
+func main() {
+	js.Global().Set("trustblocDID", js.ValueOf(map[string]interface{}{
+		"newClient": js.FuncOf(newClient),
+	}))
+
+	// keep the functions available to the page
+	select {}
+}
+
+func newClient(_ js.Value, args []js.Value) interface{} {
+	opts := &bindings.Options{}
+
+	if len(args) > 0 && args[0].Type() == js.TypeObject {
+		opts.AuthToken = stringProperty(args[0], "authToken")
+		opts.ResolverURL = stringProperty(args[0], "resolverURL")
+	}
+
+	c := bindings.NewClient(opts)
+
+	return js.ValueOf(map[string]interface{}{
+		"createDID": js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+			return promise(args, 2, func(a []string) (interface{}, error) {
+				didDoc, err := c.CreateDID(a[0], []byte(a[1]))
+
+				return string(didDoc), err
+			})
+		}),
+		"updateDID": js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+			return promise(args, 3, func(a []string) (interface{}, error) {
+				return js.Undefined(), c.UpdateDID(a[0], a[1], []byte(a[2]))
+			})
+		}),
+		"recoverDID": js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+			return promise(args, 3, func(a []string) (interface{}, error) {
+				return js.Undefined(), c.RecoverDID(a[0], a[1], []byte(a[2]))
+			})
+		}),
+		"deactivateDID": js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+			return promise(args, 3, func(a []string) (interface{}, error) {
+				return js.Undefined(), c.DeactivateDID(a[0], a[1], []byte(a[2]))
+			})
+		}),
+		"resolveDID": js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+			return promise(args, 1, func(a []string) (interface{}, error) {
+				didDoc, err := c.ResolveDID(a[0])
+
+				return string(didDoc), err
+			})
+		}),
+	})
+}
+
+// promise returns a promise of the result of the operation with the n string arguments. The operation runs in a
+// goroutine, as its requests would otherwise block the event loop which the Fetch API needs.
+func promise(args []js.Value, n int, operation func(args []string) (interface{}, error)) js.Value {
+	executor := js.FuncOf(func(_ js.Value, promiseArgs []js.Value) interface{} {
+		resolve, reject := promiseArgs[0], promiseArgs[1]
+
+		strArgs, err := stringArgs(args, n)
+		if err != nil {
+			reject.Invoke(js.Global().Get("Error").New(err.Error()))
+
+			return nil
+		}
+
+		go func() {
+			result, err := operation(strArgs)
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+
+				return
+			}
+
+			resolve.Invoke(result)
+		}()
+
+		return nil
+	})
+	defer executor.Release()
+
+	return js.Global().Get("Promise").New(executor)
+}
+
+func stringArgs(args []js.Value, n int) ([]string, error) {
+	if len(args) < n {
+		return nil, fmt.Errorf("expected %d arguments, got %d", n, len(args))
+	}
+
+	strArgs := make([]string, n)
+
+	for i := range strArgs {
+		if args[i].Type() != js.TypeString {
+			return nil, errors.New("the arguments must be strings")
+		}
+
+		strArgs[i] = args[i].String()
+	}
+
+	return strArgs, nil
+}
+
+func stringProperty(v js.Value, name string) string {
+	if p := v.Get(name); p.Type() == js.TypeString {
+		return p.String()
+	}
+
+	return ""
+}
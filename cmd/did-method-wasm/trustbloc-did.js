@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// loadClient loads the WebAssembly build of the DID client, the wasm_exec.js script of the Go distribution being
+// loaded first, and returns a client whose requests and DID documents are objects rather than JSON strings.
+//
+// The options are {authToken, resolverURL}, and the requests are those of the gomobile bindings, for example
+//
+//   const client = await loadClient('did-method.wasm', {authToken: 'token'})
+//   const didDoc = await client.createDID('testnet.trustbloc.local', {recoveryKey: jwk1, updateKey: jwk2})
+export async function loadClient(wasmURL, options = {}) {
+  if (!globalThis.trustblocDID) {
+    const go = new Go()
+    const result = await WebAssembly.instantiateStreaming(fetch(wasmURL), go.importObject)
+
+    // the program keeps running to serve the calls, so its completion isn't awaited
+    go.run(result.instance)
+  }
+
+  const client = globalThis.trustblocDID.newClient(options)
+
+  return {
+    createDID: async (domain, request) => JSON.parse(await client.createDID(domain, JSON.stringify(request))),
+    updateDID: (did, domain, request) => client.updateDID(did, domain, JSON.stringify(request)),
+    recoverDID: (did, domain, request) => client.recoverDID(did, domain, JSON.stringify(request)),
+    deactivateDID: (did, domain, request) => client.deactivateDID(did, domain, JSON.stringify(request)),
+    resolveDID: async (did) => JSON.parse(await client.resolveDID(did)),
+  }
+}
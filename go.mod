@@ -11,12 +11,33 @@ replace github.com/kilic/bls12-381 => github.com/trustbloc/bls12-381 v0.0.0-2020
 go 1.15
 
 require (
+	cloud.google.com/go v0.72.0
+	github.com/Azure/azure-sdk-for-go v48.2.0+incompatible
+	github.com/Azure/go-autorest/autorest v0.11.12
+	github.com/Azure/go-autorest/autorest/azure/auth v0.5.3
+	github.com/Azure/go-autorest/autorest/to v0.4.0 // indirect
+	github.com/Azure/go-autorest/autorest/validation v0.3.1 // indirect
+	github.com/aws/aws-sdk-go v1.36.29
 	github.com/bluele/gcache v0.0.0-20190518031135-bc40bd653833
 	github.com/btcsuite/btcutil v1.0.1
+	github.com/fxamacker/cbor/v2 v2.2.0
+	github.com/go-piv/piv-go v1.7.0
+	github.com/googleapis/gax-go/v2 v2.0.5
 	github.com/gorilla/mux v1.7.4
-	github.com/hyperledger/aries-framework-go v0.1.5-0.20201110161050-249e1c428734
-	github.com/sirupsen/logrus v1.4.2
+	github.com/hyperledger/aries-framework-go v0.1.7-0.20210603210127-e57b8c94e3cf
+	github.com/hyperledger/aries-framework-go/component/storageutil v0.0.0-20210520055214-ae429bb89bf7
+	github.com/prometheus/client_golang v1.8.0
+	github.com/sirupsen/logrus v1.6.0
 	github.com/square/go-jose/v3 v3.0.0-20200630053402-0a67ce9b0693
-	github.com/stretchr/testify v1.6.1
+	github.com/stretchr/testify v1.7.0
 	github.com/trustbloc/sidetree-core-go v0.1.5-0.20201203160228-bad37e299892
+	github.com/tyler-smith/go-bip39 v1.0.2
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	golang.org/x/crypto v0.0.0-20201002170205-7f63de1d35b0
+	google.golang.org/api v0.35.0
+	google.golang.org/genproto v0.0.0-20201110150050-8816d57aaa9a
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 )
@@ -0,0 +1,390 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bindings wraps the DID client and the VDRI for gomobile, so that iOS and Android wallets can create,
+// update, recover, deactivate and resolve did:trustbloc DIDs. The parameters and results are strings and byte slices:
+// the requests and the documents are JSON, and the keys are JWKs.
+package bindings
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc"
+)
+
+// Options configure a client, the zero value being a client without auth token which resolves the DIDs with the
+// sidetree endpoints of their domain
+type Options struct {
+	// AuthToken is the bearer token of the requests to the sidetree endpoints
+	AuthToken string
+	// ResolverURL is the URL of a resolver to resolve the DIDs with, instead of the sidetree endpoints of their
+	// domain
+	ResolverURL string
+}
+
+// Client creates, updates, recovers, deactivates and resolves DIDs
+type Client struct {
+	client *did.Client
+	vdri   *trustbloc.VDRI
+}
+
+// publicKey is a public key of a DID document in a request
+type publicKey struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Purposes []string        `json:"purposes,omitempty"`
+	JWK      json.RawMessage `json:"jwk"`
+}
+
+// service is a service of a DID document in a request
+type service struct {
+	ID              string   `json:"id"`
+	Type            string   `json:"type"`
+	Priority        uint     `json:"priority,omitempty"`
+	RecipientKeys   []string `json:"recipientKeys,omitempty"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+	ServiceEndpoint string   `json:"serviceEndpoint"`
+}
+
+type createRequest struct {
+	SidetreeEndpoints []string        `json:"sidetreeEndpoints,omitempty"`
+	RecoveryKey       json.RawMessage `json:"recoveryKey"`
+	UpdateKey         json.RawMessage `json:"updateKey"`
+	PublicKeys        []*publicKey    `json:"publicKeys,omitempty"`
+	Services          []*service      `json:"services,omitempty"`
+}
+
+type updateRequest struct {
+	SidetreeEndpoints []string        `json:"sidetreeEndpoints,omitempty"`
+	SigningKey        json.RawMessage `json:"signingKey"`
+	SigningKeyID      string          `json:"signingKeyId,omitempty"`
+	NextUpdateKey     json.RawMessage `json:"nextUpdateKey"`
+	AddPublicKeys     []*publicKey    `json:"addPublicKeys,omitempty"`
+	RemovePublicKeys  []string        `json:"removePublicKeys,omitempty"`
+	AddServices       []*service      `json:"addServices,omitempty"`
+	RemoveServices    []string        `json:"removeServices,omitempty"`
+}
+
+type recoverRequest struct {
+	SidetreeEndpoints []string        `json:"sidetreeEndpoints,omitempty"`
+	SigningKey        json.RawMessage `json:"signingKey"`
+	SigningKeyID      string          `json:"signingKeyId,omitempty"`
+	NextRecoveryKey   json.RawMessage `json:"nextRecoveryKey"`
+	NextUpdateKey     json.RawMessage `json:"nextUpdateKey"`
+	PublicKeys        []*publicKey    `json:"publicKeys,omitempty"`
+	Services          []*service      `json:"services,omitempty"`
+}
+
+type deactivateRequest struct {
+	SidetreeEndpoints []string        `json:"sidetreeEndpoints,omitempty"`
+	SigningKey        json.RawMessage `json:"signingKey"`
+	SigningKeyID      string          `json:"signingKeyId,omitempty"`
+}
+
+// NewClient returns a client configured with the options, which may be nil
+func NewClient(opts *Options) *Client {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	var (
+		clientOpts []did.Option
+		vdriOpts   []trustbloc.Option
+	)
+
+	if opts.AuthToken != "" {
+		clientOpts = append(clientOpts, did.WithAuthToken(opts.AuthToken))
+		vdriOpts = append(vdriOpts, trustbloc.WithAuthToken(opts.AuthToken))
+	}
+
+	if opts.ResolverURL != "" {
+		vdriOpts = append(vdriOpts, trustbloc.WithResolverURL(opts.ResolverURL))
+	}
+
+	return &Client{client: did.New(clientOpts...), vdri: trustbloc.New(vdriOpts...)}
+}
+
+// CreateDID creates a DID with the sidetree endpoints of the domain, or else with the sidetree endpoints of the
+// request, and returns its DID document. The request is the JSON object
+//
+//	{"sidetreeEndpoints": [...], "recoveryKey": <JWK>, "updateKey": <JWK>,
+//	 "publicKeys": [{"id": ..., "type": ..., "purposes": [...], "jwk": <JWK>}],
+//	 "services": [{"id": ..., "type": ..., "serviceEndpoint": ...}]}
+//
+// with the public recovery and update keys.
+func (c *Client) CreateDID(domain string, request []byte) ([]byte, error) {
+	req := &createRequest{}
+	if err := json.Unmarshal(request, req); err != nil {
+		return nil, fmt.Errorf("failed to parse create request: %w", err)
+	}
+
+	recoveryKey, err := parsePublicKey(req.RecoveryKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery key: %w", err)
+	}
+
+	updateKey, err := parsePublicKey(req.UpdateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update key: %w", err)
+	}
+
+	opts := []create.Option{create.WithRecoveryPublicKey(recoveryKey), create.WithUpdatePublicKey(updateKey)}
+
+	for _, endpoint := range req.SidetreeEndpoints {
+		opts = append(opts, create.WithSidetreeEndpoint(endpoint))
+	}
+
+	publicKeys, err := docPublicKeys(req.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range publicKeys {
+		opts = append(opts, create.WithPublicKey(&publicKeys[i]))
+	}
+
+	for _, s := range docServices(req.Services) {
+		opts = append(opts, create.WithService(s))
+	}
+
+	didDoc, err := c.client.CreateDID(domain, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return didDoc.JSONBytes()
+}
+
+// UpdateDID updates the DID with the sidetree endpoints of the domain, or else with the sidetree endpoints of the
+// request. The request is the JSON object
+//
+//	{"sidetreeEndpoints": [...], "signingKey": <JWK>, "signingKeyId": ..., "nextUpdateKey": <JWK>,
+//	 "addPublicKeys": [...], "removePublicKeys": [<ID>, ...], "addServices": [...], "removeServices": [<ID>, ...]}
+//
+// with the private update key, the public next update key, and the public keys and services as in CreateDID.
+func (c *Client) UpdateDID(didID, domain string, request []byte) error {
+	req := &updateRequest{}
+	if err := json.Unmarshal(request, req); err != nil {
+		return fmt.Errorf("failed to parse update request: %w", err)
+	}
+
+	signingKey, err := parsePrivateKey(req.SigningKey)
+	if err != nil {
+		return fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	nextUpdateKey, err := parsePublicKey(req.NextUpdateKey)
+	if err != nil {
+		return fmt.Errorf("invalid next update key: %w", err)
+	}
+
+	opts := []update.Option{update.WithSigningKey(signingKey), update.WithSigningKeyID(req.SigningKeyID),
+		update.WithNextUpdatePublicKey(nextUpdateKey)}
+
+	for _, endpoint := range req.SidetreeEndpoints {
+		opts = append(opts, update.WithSidetreeEndpoint(endpoint))
+	}
+
+	publicKeys, err := docPublicKeys(req.AddPublicKeys)
+	if err != nil {
+		return err
+	}
+
+	for i := range publicKeys {
+		opts = append(opts, update.WithAddPublicKey(&publicKeys[i]))
+	}
+
+	for _, id := range req.RemovePublicKeys {
+		opts = append(opts, update.WithRemovePublicKey(id))
+	}
+
+	for _, s := range docServices(req.AddServices) {
+		opts = append(opts, update.WithAddService(s))
+	}
+
+	for _, id := range req.RemoveServices {
+		opts = append(opts, update.WithRemoveService(id))
+	}
+
+	return c.client.UpdateDID(didID, domain, opts...)
+}
+
+// RecoverDID recovers the DID with the sidetree endpoints of the domain, or else with the sidetree endpoints of the
+// request. The request is the JSON object
+//
+//	{"sidetreeEndpoints": [...], "signingKey": <JWK>, "signingKeyId": ..., "nextRecoveryKey": <JWK>,
+//	 "nextUpdateKey": <JWK>, "publicKeys": [...], "services": [...]}
+//
+// with the private recovery key, the public next recovery and update keys, and the public keys and services of the
+// recovered document as in CreateDID.
+func (c *Client) RecoverDID(didID, domain string, request []byte) error {
+	req := &recoverRequest{}
+	if err := json.Unmarshal(request, req); err != nil {
+		return fmt.Errorf("failed to parse recover request: %w", err)
+	}
+
+	signingKey, err := parsePrivateKey(req.SigningKey)
+	if err != nil {
+		return fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	nextRecoveryKey, err := parsePublicKey(req.NextRecoveryKey)
+	if err != nil {
+		return fmt.Errorf("invalid next recovery key: %w", err)
+	}
+
+	nextUpdateKey, err := parsePublicKey(req.NextUpdateKey)
+	if err != nil {
+		return fmt.Errorf("invalid next update key: %w", err)
+	}
+
+	opts := []recovery.Option{recovery.WithSigningKey(signingKey), recovery.WithSigningKeyID(req.SigningKeyID),
+		recovery.WithNextRecoveryPublicKey(nextRecoveryKey), recovery.WithNextUpdatePublicKey(nextUpdateKey)}
+
+	for _, endpoint := range req.SidetreeEndpoints {
+		opts = append(opts, recovery.WithSidetreeEndpoint(endpoint))
+	}
+
+	publicKeys, err := docPublicKeys(req.PublicKeys)
+	if err != nil {
+		return err
+	}
+
+	for i := range publicKeys {
+		opts = append(opts, recovery.WithPublicKey(&publicKeys[i]))
+	}
+
+	for _, s := range docServices(req.Services) {
+		opts = append(opts, recovery.WithService(s))
+	}
+
+	return c.client.RecoverDID(didID, domain, opts...)
+}
+
+// DeactivateDID deactivates the DID with the sidetree endpoints of the domain, or else with the sidetree endpoints
+// of the request. The request is the JSON object
+//
+//	{"sidetreeEndpoints": [...], "signingKey": <JWK>, "signingKeyId": ...}
+//
+// with the private recovery key.
+func (c *Client) DeactivateDID(didID, domain string, request []byte) error {
+	req := &deactivateRequest{}
+	if err := json.Unmarshal(request, req); err != nil {
+		return fmt.Errorf("failed to parse deactivate request: %w", err)
+	}
+
+	signingKey, err := parsePrivateKey(req.SigningKey)
+	if err != nil {
+		return fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	opts := []deactivate.Option{deactivate.WithSigningKey(signingKey), deactivate.WithSigningKeyID(req.SigningKeyID)}
+
+	for _, endpoint := range req.SidetreeEndpoints {
+		opts = append(opts, deactivate.WithSidetreeEndpoint(endpoint))
+	}
+
+	return c.client.DeactivateDID(didID, domain, opts...)
+}
+
+// ResolveDID resolves the DID and returns its DID document
+func (c *Client) ResolveDID(didID string) ([]byte, error) {
+	result, err := c.vdri.Read(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.DIDDocument.JSONBytes()
+}
+
+func parsePublicKey(jwk json.RawMessage) (crypto.PublicKey, error) {
+	key, err := parseJWK(jwk)
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.IsPublic() {
+		return nil, errors.New("not a public key")
+	}
+
+	return key.Key, nil
+}
+
+func parsePrivateKey(jwk json.RawMessage) (crypto.PrivateKey, error) {
+	key, err := parseJWK(jwk)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.IsPublic() {
+		return nil, errors.New("not a private key")
+	}
+
+	return key.Key, nil
+}
+
+func parseJWK(jwk json.RawMessage) (*jose.JSONWebKey, error) {
+	if len(jwk) == 0 {
+		return nil, errors.New("missing JWK")
+	}
+
+	key := &jose.JSONWebKey{}
+	if err := key.UnmarshalJSON(jwk); err != nil {
+		return nil, fmt.Errorf("failed to parse JWK: %w", err)
+	}
+
+	return key, nil
+}
+
+// docPublicKeys converts the public keys of a request as the CLI converts the public keys of its JWK files
+func docPublicKeys(publicKeys []*publicKey) ([]doc.PublicKey, error) {
+	keys := make([]doc.PublicKey, len(publicKeys))
+
+	for i, pk := range publicKeys {
+		key, err := parsePublicKey(pk.JWK)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", pk.ID, err)
+		}
+
+		keys[i] = doc.PublicKey{ID: pk.ID, Type: pk.Type, Purposes: pk.Purposes, Encoding: doc.PublicKeyEncodingJwk}
+
+		switch k := key.(type) {
+		case ed25519.PublicKey:
+			keys[i].KeyType, keys[i].Value = doc.Ed25519KeyType, k
+		case *ecdsa.PublicKey:
+			keys[i].KeyType, keys[i].Value = doc.P256KeyType, elliptic.Marshal(k.Curve, k.X, k.Y)
+		default:
+			return nil, fmt.Errorf("invalid public key %s: %w", pk.ID, did.ErrKeyNotSupported)
+		}
+	}
+
+	return keys, nil
+}
+
+func docServices(services []*service) []*docdid.Service {
+	svcs := make([]*docdid.Service, len(services))
+
+	for i, s := range services {
+		svcs[i] = &docdid.Service{ID: s.ID, Type: s.Type, Priority: s.Priority, RecipientKeys: s.RecipientKeys,
+			RoutingKeys: s.RoutingKeys, ServiceEndpoint: s.ServiceEndpoint}
+	}
+
+	return svcs
+}
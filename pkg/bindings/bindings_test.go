@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bindings
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+func TestClient(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	nextPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	publicJWK := marshalJWK(t, pubKey)
+	privateJWK := marshalJWK(t, privKey)
+	nextJWK := marshalJWK(t, nextPubKey)
+	ecPublicJWK := marshalJWK(t, ecPrivKey.Public())
+
+	didDoc := &docdid.Doc{Context: []string{docdid.ContextV1}, ID: "did:ex:123",
+		VerificationMethod: []docdid.VerificationMethod{{ID: "did:ex:123#key1", Type: doc.Ed25519VerificationKey2018,
+			Controller: "did:ex:123", Value: pubKey}}}
+
+	docBytes, err := didDoc.JSONBytes()
+	require.NoError(t, err)
+
+	var operations []string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			operations = append(operations, r.URL.Path)
+
+			fmt.Fprint(w, string(docBytes))
+		case r.URL.Path == "/resolver/did:ex:123":
+			w.Header().Set("Content-Type", "application/did+ld+json")
+			fmt.Fprintf(w, `{"didDocument": %s}`, docBytes)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer serv.Close()
+
+	c := NewClient(&Options{AuthToken: "token", ResolverURL: serv.URL + "/resolver"})
+
+	var created, resolved []byte
+
+	t.Run("test create", func(t *testing.T) {
+		created, err = c.CreateDID("", []byte(fmt.Sprintf(`{"sidetreeEndpoints": [%q], "recoveryKey": %s,
+			"updateKey": %s, "publicKeys": [{"id": "key1", "type": "JwsVerificationKey2020",
+			"purposes": ["authentication"], "jwk": %s}, {"id": "key2", "type": "JwsVerificationKey2020",
+			"jwk": %s}], "services": [{"id": "svc1", "type": "type", "serviceEndpoint": "https://example.com"}]}`,
+			serv.URL, ecPublicJWK, publicJWK, publicJWK, ecPublicJWK)))
+		require.NoError(t, err)
+		require.Contains(t, string(created), "did:ex:123")
+	})
+
+	t.Run("test update, recover and deactivate", func(t *testing.T) {
+		err = c.UpdateDID("did:ex:123", "", []byte(fmt.Sprintf(`{"sidetreeEndpoints": [%q], "signingKey": %s,
+			"nextUpdateKey": %s, "removePublicKeys": ["key2"], "addServices": [{"id": "svc2", "type": "type",
+			"serviceEndpoint": "https://example.com"}], "removeServices": ["svc1"]}`,
+			serv.URL, privateJWK, nextJWK)))
+		require.NoError(t, err)
+
+		err = c.RecoverDID("did:ex:123", "", []byte(fmt.Sprintf(`{"sidetreeEndpoints": [%q], "signingKey": %s,
+			"nextRecoveryKey": %s, "nextUpdateKey": %s, "publicKeys": [{"id": "key1",
+			"type": "JwsVerificationKey2020", "jwk": %s}]}`, serv.URL, privateJWK, ecPublicJWK, nextJWK, publicJWK)))
+		require.NoError(t, err)
+
+		err = c.DeactivateDID("did:ex:123", "", []byte(fmt.Sprintf(`{"sidetreeEndpoints": [%q], "signingKey": %s}`,
+			serv.URL, marshalJWK(t, ecPrivKey))))
+		require.NoError(t, err)
+
+		require.Len(t, operations, 4)
+	})
+
+	t.Run("test resolve", func(t *testing.T) {
+		resolved, err = c.ResolveDID("did:ex:123")
+		require.NoError(t, err)
+		require.Contains(t, string(resolved), "did:ex:123#key1")
+
+		_, err = c.ResolveDID("did:ex:456")
+		require.Error(t, err)
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		_, err = c.CreateDID("", []byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse create request")
+
+		_, err = c.CreateDID("", []byte(`{}`))
+		require.EqualError(t, err, "invalid recovery key: missing JWK")
+
+		_, err = c.CreateDID("", []byte(fmt.Sprintf(`{"recoveryKey": %s, "updateKey": %s}`, publicJWK, privateJWK)))
+		require.EqualError(t, err, "invalid update key: not a public key")
+
+		_, err = c.CreateDID("", []byte(fmt.Sprintf(`{"recoveryKey": %s, "updateKey": %s,
+			"publicKeys": [{"id": "key1", "jwk": {}}]}`, publicJWK, publicJWK)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid public key key1: failed to parse JWK")
+
+		err = c.UpdateDID("did:ex:123", "", []byte(fmt.Sprintf(`{"signingKey": %s}`, publicJWK)))
+		require.EqualError(t, err, "invalid signing key: not a private key")
+
+		err = c.RecoverDID("did:ex:123", "", []byte(fmt.Sprintf(`{"signingKey": %s, "nextRecoveryKey": %s}`,
+			privateJWK, publicJWK)))
+		require.EqualError(t, err, "invalid next update key: missing JWK")
+
+		err = c.DeactivateDID("did:ex:123", "", []byte(`{"signingKey": "key"}`))
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "invalid signing key: failed to parse JWK"))
+
+		err = c.DeactivateDID("did:ex:123", "", []byte(fmt.Sprintf(`{"signingKey": %s}`, privateJWK)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "domain is empty")
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	require.NotNil(t, NewClient(nil))
+}
+
+func marshalJWK(t *testing.T, key interface{}) string {
+	t.Helper()
+
+	jwk, err := (&jose.JSONWebKey{Key: key}).MarshalJSON()
+	require.NoError(t, err)
+
+	return string(jwk)
+}
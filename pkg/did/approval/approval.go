@@ -0,0 +1,256 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package approval collects the approvals of a recovery request by designated approvers, before the request is
+// submitted. Each approver signs the request with a detached JWS, and the request is approved once the number of
+// valid approvals reaches the threshold. The request travels between the approvers, so the submitter verifies the
+// approvals against its own policy rather than the approvers and the threshold carried by the request.
+package approval
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/square/go-jose/v3"
+)
+
+// ErrNotApproved is returned when a request is submitted before it reaches its approval threshold
+var ErrNotApproved = errors.New("approval threshold not reached")
+
+// Approver is an approver designated for a request, with the public key which verifies its approvals
+type Approver struct {
+	ID  string           `json:"id"`
+	Key *jose.JSONWebKey `json:"key"`
+}
+
+// Approval is the approval of a request by an approver
+type Approval struct {
+	ApproverID string `json:"approverId"`
+	// JWS is the detached compact JWS of the approver over the request
+	JWS string `json:"jws"`
+}
+
+// Policy is the approval policy of the submitter: the approvers it designated and the number of their approvals
+// required
+type Policy struct {
+	Threshold int
+	Approvers []*Approver
+}
+
+// Request is a sidetree request pending approval. It is exported as JSON to the approvers, and the approvals they
+// return are added to it.
+type Request struct {
+	DID       string      `json:"did"`
+	Request   []byte      `json:"request"`
+	Threshold int         `json:"threshold"`
+	Approvers []*Approver `json:"approvers"`
+	Approvals []*Approval `json:"approvals,omitempty"`
+}
+
+// NewApprover returns an approver with the public key
+func NewApprover(id string, publicKey crypto.PublicKey) *Approver {
+	return &Approver{ID: id, Key: &jose.JSONWebKey{Key: publicKey, KeyID: id}}
+}
+
+// NewPolicy returns the policy requiring the approvals of threshold of the approvers
+func NewPolicy(threshold int, approvers ...*Approver) (*Policy, error) {
+	if err := validateApprovers(threshold, approvers); err != nil {
+		return nil, err
+	}
+
+	return &Policy{Threshold: threshold, Approvers: approvers}, nil
+}
+
+// NewRequest returns the request pending approval by threshold of the approvers
+func NewRequest(did string, request []byte, threshold int, approvers ...*Approver) (*Request, error) {
+	r := &Request{DID: did, Request: request, Threshold: threshold, Approvers: approvers}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Parse parses a request exported with Export
+func Parse(data []byte) (*Request, error) {
+	r := &Request{}
+
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal approval request: %w", err)
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Export exports the request as JSON, to be sent to the approvers
+func (r *Request) Export() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Sign returns the approval of the request by the approver, signed with its private key
+func Sign(r *Request, approverID string, privateKey crypto.PrivateKey) (*Approval, error) {
+	alg, err := signatureAlgorithm(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: privateKey},
+		(&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), approverID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+
+	sig, err := signer.Sign(r.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	compact, err := sig.DetachedCompactSerialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize signature: %w", err)
+	}
+
+	return &Approval{ApproverID: approverID, JWS: compact}, nil
+}
+
+// AddApproval verifies the approval against the key of its approver and adds it to the request. An approver
+// approves a request once.
+func (r *Request) AddApproval(approval *Approval) error {
+	if err := r.verify(approval); err != nil {
+		return err
+	}
+
+	for _, a := range r.Approvals {
+		if a.ApproverID == approval.ApproverID {
+			return fmt.Errorf("request already approved by %s", approval.ApproverID)
+		}
+	}
+
+	r.Approvals = append(r.Approvals, approval)
+
+	return nil
+}
+
+// Approved returns whether the valid approvals of distinct approvers reach the threshold of the request. The
+// approvers and the threshold come with the request, so this only tells the approvers whether more approvals are
+// needed: the submitter verifies the request with Policy.Verify.
+func (r *Request) Approved() bool {
+	return (&Policy{Threshold: r.Threshold, Approvers: r.Approvers}).approved(r)
+}
+
+// Verify returns ErrNotApproved unless the valid approvals of the request by distinct approvers of the policy reach
+// the threshold of the policy. The approvers and the threshold of the request are ignored, since an exported request
+// may have been altered.
+func (p *Policy) Verify(r *Request) error {
+	if err := validateApprovers(p.Threshold, p.Approvers); err != nil {
+		return fmt.Errorf("invalid approval policy: %w", err)
+	}
+
+	if !p.approved(r) {
+		return ErrNotApproved
+	}
+
+	return nil
+}
+
+func (p *Policy) approved(r *Request) bool {
+	approved := make(map[string]bool)
+
+	for _, a := range r.Approvals {
+		if verify(r.Request, p.Approvers, a) == nil {
+			approved[a.ApproverID] = true
+		}
+	}
+
+	return len(approved) >= p.Threshold
+}
+
+func (r *Request) verify(approval *Approval) error {
+	return verify(r.Request, r.Approvers, approval)
+}
+
+func verify(request []byte, approvers []*Approver, approval *Approval) error {
+	approver := findApprover(approvers, approval.ApproverID)
+	if approver == nil {
+		return fmt.Errorf("%s is not an approver of the request", approval.ApproverID)
+	}
+
+	sig, err := jose.ParseDetached(approval.JWS, request)
+	if err != nil {
+		return fmt.Errorf("failed to parse approval of %s: %w", approval.ApproverID, err)
+	}
+
+	if _, err = sig.Verify(approver.Key); err != nil {
+		return fmt.Errorf("invalid approval of %s: %w", approval.ApproverID, err)
+	}
+
+	return nil
+}
+
+func findApprover(approvers []*Approver, id string) *Approver {
+	for _, a := range approvers {
+		if a.ID == id {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (r *Request) validate() error {
+	if len(r.Request) == 0 {
+		return errors.New("request is empty")
+	}
+
+	return validateApprovers(r.Threshold, r.Approvers)
+}
+
+func validateApprovers(threshold int, approvers []*Approver) error {
+	if threshold < 1 || threshold > len(approvers) {
+		return fmt.Errorf("threshold must be between 1 and the number of approvers (%d)", len(approvers))
+	}
+
+	ids := make(map[string]bool)
+
+	for _, a := range approvers {
+		if a.ID == "" || a.Key == nil {
+			return errors.New("approver id and key are required")
+		}
+
+		if ids[a.ID] {
+			return fmt.Errorf("duplicate approver %s", a.ID)
+		}
+
+		ids[a.ID] = true
+	}
+
+	return nil
+}
+
+func signatureAlgorithm(privateKey crypto.PrivateKey) (jose.SignatureAlgorithm, error) {
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		return jose.EdDSA, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return jose.ES256, nil
+		case elliptic.P384():
+			return jose.ES384, nil
+		}
+	}
+
+	return "", errors.New("key not supported")
+}
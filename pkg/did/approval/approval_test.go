@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package approval
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	priv2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	priv3, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	approvers := []*Approver{NewApprover("a1", pub1), NewApprover("a2", priv2.Public()),
+		NewApprover("a3", priv3.Public())}
+
+	t.Run("test approval threshold", func(t *testing.T) {
+		r, err := NewRequest("did:ex:123", []byte(`{"type":"recover"}`), 2, approvers...)
+		require.NoError(t, err)
+		require.False(t, r.Approved())
+
+		approval, err := Sign(r, "a1", priv1)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+		require.False(t, r.Approved())
+
+		err = r.AddApproval(approval)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "request already approved by a1")
+
+		// the request is exported to the next approver
+		data, err := r.Export()
+		require.NoError(t, err)
+
+		r, err = Parse(data)
+		require.NoError(t, err)
+
+		approval, err = Sign(r, "a3", priv3)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+		require.True(t, r.Approved())
+	})
+
+	t.Run("test invalid approvals", func(t *testing.T) {
+		r, err := NewRequest("did:ex:123", []byte(`{"type":"recover"}`), 1, approvers...)
+		require.NoError(t, err)
+
+		approval, err := Sign(r, "a2", priv1)
+		require.NoError(t, err)
+
+		err = r.AddApproval(approval)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid approval of a2")
+
+		approval, err = Sign(r, "a4", priv1)
+		require.NoError(t, err)
+
+		err = r.AddApproval(approval)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "a4 is not an approver of the request")
+
+		err = r.AddApproval(&Approval{ApproverID: "a1", JWS: "invalid"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse approval of a1")
+
+		approval, err = Sign(r, "a2", priv2)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+		require.True(t, r.Approved())
+
+		// the request approved is altered
+		r.Request = []byte(`{"type":"deactivate"}`)
+		require.False(t, r.Approved())
+	})
+
+	t.Run("test unsupported key", func(t *testing.T) {
+		r, err := NewRequest("did:ex:123", []byte(`{}`), 1, approvers...)
+		require.NoError(t, err)
+
+		_, err = Sign(r, "a1", "key")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key not supported")
+	})
+
+	t.Run("test invalid request", func(t *testing.T) {
+		_, err := NewRequest("did:ex:123", nil, 1, approvers...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "request is empty")
+
+		_, err = NewRequest("did:ex:123", []byte(`{}`), 4, approvers...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "threshold must be between 1 and the number of approvers (3)")
+
+		_, err = NewRequest("did:ex:123", []byte(`{}`), 1, NewApprover("a1", pub1), NewApprover("a1", pub1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "duplicate approver a1")
+
+		_, err = NewRequest("did:ex:123", []byte(`{}`), 1, &Approver{ID: "a1"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "approver id and key are required")
+
+		_, err = Parse([]byte(`{`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal approval request")
+
+		_, err = Parse([]byte(`{"request":"e30=","threshold":0}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "threshold must be between")
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	priv2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	policy, err := NewPolicy(2, NewApprover("a1", pub1), NewApprover("a2", priv2.Public()))
+	require.NoError(t, err)
+
+	t.Run("test approved", func(t *testing.T) {
+		r, err := NewRequest("did:ex:123", []byte(`{"type":"recover"}`), 2, policy.Approvers...)
+		require.NoError(t, err)
+
+		approval, err := Sign(r, "a1", priv1)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+		require.True(t, errors.Is(policy.Verify(r), ErrNotApproved))
+
+		approval, err = Sign(r, "a2", priv2)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+		require.NoError(t, policy.Verify(r))
+	})
+
+	t.Run("test tampered approvers", func(t *testing.T) {
+		r, err := NewRequest("did:ex:123", []byte(`{"type":"recover"}`), 2, policy.Approvers...)
+		require.NoError(t, err)
+
+		approval, err := Sign(r, "a1", priv1)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+
+		// an approver replaces the other approver with its own key, and lowers the threshold
+		_, forgedKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		r.Approvers = []*Approver{policy.Approvers[0], NewApprover("a2", forgedKey.Public())}
+		r.Threshold = 1
+
+		data, err := r.Export()
+		require.NoError(t, err)
+
+		r, err = Parse(data)
+		require.NoError(t, err)
+
+		approval, err = Sign(r, "a2", forgedKey)
+		require.NoError(t, err)
+		require.NoError(t, r.AddApproval(approval))
+		require.True(t, r.Approved())
+
+		require.True(t, errors.Is(policy.Verify(r), ErrNotApproved))
+	})
+
+	t.Run("test invalid policy", func(t *testing.T) {
+		_, err := NewPolicy(0, policy.Approvers...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "threshold must be between 1 and the number of approvers (2)")
+
+		r, err := NewRequest("did:ex:123", []byte(`{"type":"recover"}`), 1, policy.Approvers...)
+		require.NoError(t, err)
+
+		err = (&Policy{Threshold: 1}).Verify(r)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid approval policy")
+	})
+}
@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+)
+
+// CanonicalizeRequest returns the canonical JSON (JCS, RFC 8785) of a sidetree operation request. The client sends
+// its requests in this form, so that the requests built from the same inputs are byte for byte identical and can be
+// hashed, cached and compared. Only the signatures of the update, recover and deactivate requests signed with ECDSA
+// keys differ, ECDSA signatures being randomized; the signatures of Ed25519 keys are deterministic.
+func CanonicalizeRequest(request []byte) ([]byte, error) {
+	var value interface{}
+
+	if err := json.Unmarshal(request, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse request: %w", err)
+	}
+
+	canonical, err := docutil.MarshalCanonical(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize request: %w", err)
+	}
+
+	return canonical, nil
+}
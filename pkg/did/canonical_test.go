@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+)
+
+func TestCanonicalizeRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		canonical, err := CanonicalizeRequest([]byte(`{"type": "deactivate", "didSuffix": "EiA", "signedData": "s"}`))
+		require.NoError(t, err)
+		require.Equal(t, `{"didSuffix":"EiA","signedData":"s","type":"deactivate"}`, string(canonical))
+	})
+
+	t.Run("test invalid request", func(t *testing.T) {
+		_, err := CanonicalizeRequest([]byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse request")
+	})
+}
+
+func TestClient_CanonicalRequests(t *testing.T) {
+	var requests [][]byte
+
+	v := New(WithDryRun(func(_ string, r []byte) {
+		requests = append(requests, r)
+	}))
+
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSigningKey(privKey),
+			deactivate.WithSidetreeEndpoint("https://localhost/sidetree"))
+		require.True(t, errors.Is(err, ErrDryRun))
+	}
+
+	require.Len(t, requests, 2)
+	require.Equal(t, requests[0], requests[1])
+
+	canonical, err := CanonicalizeRequest(requests[0])
+	require.NoError(t, err)
+	require.Equal(t, canonical, requests[0])
+}
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/replay"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const defaultCASURL = "https://ipfs.io"
+
+// CASVerification is the result of the verification of a DID from the CAS, which compares the resolution of the
+// DID with its state replayed from the batch files of its anchors
+type CASVerification struct {
+	DID string `json:"did" yaml:"did"`
+	// Operations are the operations of the DID found in the batches, including the ignored invalid ones
+	Operations []replay.Operation `json:"operations" yaml:"operations"`
+	// Verified is whether the resolution matches the replayed state
+	Verified    bool     `json:"verified" yaml:"verified"`
+	Divergences []string `json:"divergences,omitempty" yaml:"divergences,omitempty"`
+}
+
+// VerifyFromCAS verifies the resolution of the DID independently of the resolver, trusting only the anchors, which
+// are the anchor strings of the transactions of the DID on the ledger in their order. The anchor, map and chunk files
+// of their batches are fetched from the IPFS gateway at the CAS URL, which defaults to the IPFS gateway of the client
+// or else to https://ipfs.io, and the operations of the DID are replayed, checking their commitments, signatures and
+// delta hashes. The public keys, services and commitments of the replayed state are compared with the resolution.
+func (c *Client) VerifyFromCAS(did, domain, casURL string, anchors []string,
+	sidetreeEndpoints ...string) (*CASVerification, error) {
+	if len(anchors) == 0 {
+		return nil, errors.New("anchors are required")
+	}
+
+	endpoints := make([]*models.Endpoint, len(sidetreeEndpoints))
+	for i, endpoint := range sidetreeEndpoints {
+		endpoints[i] = &models.Endpoint{URL: endpoint}
+	}
+
+	endpointURL, err := c.getEndpoint(context.Background(), domain, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	didSuffix, err := getUniqueSuffix(ShortFormDID(did))
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := replay.Replay(replay.NewGatewayFetcher(c.casURL(casURL), c.client), didSuffix, anchors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay the operations of %s: %w", did, err)
+	}
+
+	divergences, err := c.compareReplayedState(did, endpointURL, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CASVerification{DID: did, Operations: state.Operations, Verified: len(divergences) == 0,
+		Divergences: divergences}, nil
+}
+
+func (c *Client) casURL(casURL string) string {
+	if casURL != "" {
+		return casURL
+	}
+
+	if c.ipfsGateway != "" {
+		return c.ipfsGateway
+	}
+
+	return defaultCASURL
+}
+
+// compareReplayedState resolves the DID and returns the divergences of its resolution from the replayed state. A
+// deactivated DID is expected not to resolve.
+func (c *Client) compareReplayedState(did, endpointURL string, state *replay.State) ([]string, error) {
+	if !state.Created {
+		return []string{"the anchors have no create operation of the DID"}, nil
+	}
+
+	responseBytes, err := c.resolve(did, endpointURL)
+
+	if state.Deactivated {
+		if err == nil {
+			return []string{"the DID is deactivated but resolves"}, nil
+		}
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", did, err)
+	}
+
+	didDoc, err := parseDocument(responseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseMethodMetadata(responseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedKeys, resolvedServices := documentIDs(didDoc)
+
+	var divergences []string
+
+	divergences = appendDivergences(divergences, "public keys", state.PublicKeys, resolvedKeys)
+	divergences = appendDivergences(divergences, "services", state.Services, resolvedServices)
+	divergences = appendCommitmentDivergence(divergences, "update", state.UpdateCommitment,
+		metadata.UpdateCommitment)
+	divergences = appendCommitmentDivergence(divergences, "recovery", state.RecoveryCommitment,
+		metadata.RecoveryCommitment)
+
+	return divergences, nil
+}
+
+// appendCommitmentDivergence appends the divergence of the resolved commitment from the replayed one, if the
+// resolver reports it
+func appendCommitmentDivergence(divergences []string, commitment, replayed, resolved string) []string {
+	if resolved == "" || resolved == replayed {
+		return divergences
+	}
+
+	return append(divergences, fmt.Sprintf("%s commitment %s differs from the replayed %s commitment %s",
+		commitment, resolved, commitment, replayed))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/replay"
+)
+
+func TestClient_VerifyFromCAS(t *testing.T) {
+	delta := map[string]interface{}{"updateCommitment": "update-commitment",
+		"patches": []interface{}{map[string]interface{}{"action": "replace",
+			"document": map[string]interface{}{"publicKey": []interface{}{map[string]interface{}{"id": "key1"}}}}}}
+
+	deltaHash, err := canonicalMultihash(delta, multihashSHA2256)
+	require.NoError(t, err)
+
+	suffixData := map[string]interface{}{"deltaHash": deltaHash, "recoveryCommitment": "recovery-commitment"}
+
+	didSuffix, err := canonicalMultihash(suffixData, multihashSHA2256)
+	require.NoError(t, err)
+
+	did := "did:trustbloc:testnet:" + didSuffix
+
+	cas := map[string][]byte{
+		"anchor": compress(t, map[string]interface{}{"mapFileUri": "map", "operations": map[string]interface{}{
+			"create": []interface{}{map[string]interface{}{"suffixData": suffixData}}}}),
+		"map": compress(t, map[string]interface{}{"chunks": []interface{}{
+			map[string]interface{}{"chunkFileUri": "chunk"}}}),
+		"chunk": compress(t, map[string]interface{}{"deltas": []interface{}{delta}}),
+	}
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	resolved := &docdid.Doc{Context: []string{docdid.ContextV1}, ID: did,
+		VerificationMethod: []docdid.VerificationMethod{{ID: did + "#key1", Type: doc.Ed25519VerificationKey2018,
+			Controller: did, Value: pubKey}}}
+
+	resolvedBytes, err := resolved.JSONBytes()
+	require.NoError(t, err)
+
+	newServer := func(recoveryCommitment string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/ipfs/") {
+				content, ok := cas[strings.TrimPrefix(r.URL.Path, "/ipfs/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				_, e := w.Write(content)
+				require.NoError(t, e)
+
+				return
+			}
+
+			require.Equal(t, "/identifiers/"+did, r.URL.Path)
+
+			fmt.Fprintf(w, `{"didDocument":%s,"methodMetadata":{"updateCommitment":"update-commitment",`+
+				`"recoveryCommitment":"%s"}}`, resolvedBytes, recoveryCommitment)
+		}))
+	}
+
+	t.Run("test verified DID", func(t *testing.T) {
+		serv := newServer("recovery-commitment")
+		defer serv.Close()
+
+		v, err := New().VerifyFromCAS(did, "", serv.URL, []string{"1.anchor"}, serv.URL)
+		require.NoError(t, err)
+		require.True(t, v.Verified)
+		require.Empty(t, v.Divergences)
+		require.Equal(t, []replay.Operation{{Anchor: "1.anchor", Type: replay.TypeCreate, Applied: true}},
+			v.Operations)
+	})
+
+	t.Run("test diverging resolution", func(t *testing.T) {
+		serv := newServer("other")
+		defer serv.Close()
+
+		v, err := New().VerifyFromCAS(did, "", serv.URL, []string{"1.anchor"}, serv.URL)
+		require.NoError(t, err)
+		require.False(t, v.Verified)
+		require.Equal(t, []string{"recovery commitment other differs from the replayed recovery commitment " +
+			"recovery-commitment"}, v.Divergences)
+	})
+
+	t.Run("test DID not created in the anchors", func(t *testing.T) {
+		serv := newServer("recovery-commitment")
+		defer serv.Close()
+
+		v, err := New().VerifyFromCAS("did:trustbloc:testnet:other", "", serv.URL, []string{"1.anchor"}, serv.URL)
+		require.NoError(t, err)
+		require.False(t, v.Verified)
+		require.Equal(t, []string{"the anchors have no create operation of the DID"}, v.Divergences)
+	})
+
+	t.Run("test error from missing anchors", func(t *testing.T) {
+		_, err := New().VerifyFromCAS(did, "", "", nil, "http://localhost")
+		require.EqualError(t, err, "anchors are required")
+	})
+
+	t.Run("test error from missing batch file", func(t *testing.T) {
+		serv := newServer("recovery-commitment")
+		defer serv.Close()
+
+		_, err := New().VerifyFromCAS(did, "", serv.URL, []string{"1.missing"}, serv.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to replay the operations of "+did)
+	})
+}
+
+func compress(t *testing.T, file interface{}) []byte {
+	t.Helper()
+
+	content, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	var compressed bytes.Buffer
+
+	w := gzip.NewWriter(&compressed)
+	_, err = w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return compressed.Bytes()
+}
@@ -7,20 +7,24 @@ package did
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
-	log "github.com/sirupsen/logrus"
 	"github.com/square/go-jose/v3"
 	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
 	"github.com/trustbloc/sidetree-core-go/pkg/jws"
@@ -30,17 +34,35 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/0_1/client"
 
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/approval"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/didweb"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/pkcs8"
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/memorycacheconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/metricsconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/contentencoding"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/discovery/staticdiscovery"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/endpoint"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/selection/staticselection"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+// types of the DID operations, as set in the operation attribute of their spans and label of their metrics
+const (
+	operationCreate     = "create"
+	operationUpdate     = "update"
+	operationRecover    = "recover"
+	operationDeactivate = "deactivate"
 )
 
 type endpointService interface {
@@ -53,11 +75,48 @@ type configService interface {
 
 // Client for did bloc
 type Client struct {
-	endpointService endpointService
-	client          *http.Client
-	tlsConfig       *tls.Config
-	authToken       string
-	configService   configService
+	endpointService   endpointService
+	client            *http.Client
+	tlsConfig         *tls.Config
+	tlsOpts           []tlsconfig.Option
+	proxyURL          *url.URL
+	authToken         string
+	configService     configService
+	metrics           metrics.Provider
+	ipfsGateway       string
+	operationCallback func(info *OperationInfo)
+	dryRunCallback    func(endpoint string, request []byte)
+	commitmentStore   commitmentstore.Store
+	didWebPublisher   didweb.Publisher
+	fipsMode          bool
+	logger            logger.Logger
+	tracer            tracing.Tracer
+	debugHTTP         bool
+	maxIdleConns      int
+	idleConnTimeout   time.Duration
+	keepAlive         time.Duration
+	dialTimeout       time.Duration
+	resolver          *net.Resolver
+	allowedNetworks   []*net.IPNet
+	rateLimiter       *rateLimiter
+	idempotencyKeyTTL time.Duration
+	idempotentOps     *idempotentOperations
+	protocolCheck     bool
+	protocolVersion   string
+}
+
+// ErrDryRun is returned by the operations of a client created with WithDryRun, after the request was passed to
+// the dry run callback instead of being sent
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// OperationInfo describes a sidetree operation request accepted by an endpoint
+type OperationInfo struct {
+	// Endpoint is the sidetree endpoint the request was sent to
+	Endpoint string
+	// RequestHash is the base64url encoded SHA-256 hash of the request body
+	RequestHash string
+	// Request is the request body
+	Request []byte
 }
 
 type didResolution struct {
@@ -69,25 +128,52 @@ type didResolution struct {
 
 // New return did bloc client
 func New(opts ...Option) *Client {
-	c := &Client{client: &http.Client{}}
+	c := &Client{client: &http.Client{}, metrics: metrics.NoopProvider{}, logger: logger.Default(),
+		tracer: tracing.NoopTracer{}, maxIdleConns: defaultMaxIdleConns, idleConnTimeout: defaultIdleConnTimeout,
+		keepAlive: defaultKeepAlive, dialTimeout: defaultDialTimeout, idempotencyKeyTTL: defaultIdempotencyKeyTTL,
+		protocolVersion: ProtocolVersion01}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	c.client.Transport = &http.Transport{TLSClientConfig: c.tlsConfig}
-	configService := memorycacheconfig.NewService(httpconfig.NewService(httpconfig.WithTLSConfig(c.tlsConfig)))
+	c.idempotentOps = newIdempotentOperations(c.idempotencyKeyTTL)
+
+	c.client.Transport = c.newTransport()
+	httpConfigOpts := []httpconfig.Option{httpconfig.WithHTTPClient(c.client), httpconfig.WithLogger(c.logger)}
+	if c.ipfsGateway != "" {
+		httpConfigOpts = append(httpConfigOpts, httpconfig.WithIPFSGateway(c.ipfsGateway))
+	}
+
+	configService := memorycacheconfig.NewService(metricsconfig.NewService(
+		httpconfig.NewService(httpConfigOpts...), c.metrics))
 	c.configService = configService
 	c.endpointService = endpoint.NewService(
-		staticdiscovery.NewService(configService),
-		staticselection.NewService(configService))
+		staticdiscovery.NewService(configService, staticdiscovery.WithLogger(c.logger)),
+		staticselection.NewService(configService),
+		endpoint.WithMetricsProvider(c.metrics))
 
 	return c
 }
 
 // CreateDID create did doc
-func (c *Client) CreateDID(domain string, opts ...create.Option) (*docdid.Doc, error) { //
+func (c *Client) CreateDID(domain string, opts ...create.Option) (*docdid.Doc, error) {
+	ctx, span := c.startOperation(operationCreate, "", domain)
+
+	didDoc, err := c.idempotentOps.run(createIdempotencyKey(opts), operationCreate, "", func() (*docdid.Doc, error) {
+		return c.createDID(ctx, domain, opts)
+	})
+	if err == nil {
+		span.SetAttribute(tracing.AttributeDID, didDoc.ID)
+	}
+
+	span.End(err)
+
+	return didDoc, err
+}
+
+func (c *Client) createDID(ctx context.Context, domain string, opts []create.Option) (*docdid.Doc, error) {
 	createDIDOpts := &create.Opts{}
 	// Apply options
 	for _, opt := range opts {
@@ -99,29 +185,86 @@ func (c *Client) CreateDID(domain string, opts ...create.Option) (*docdid.Doc, e
 		return nil, err
 	}
 
-	sidetreeEndpoint, err := c.getEndpoint(domain, createDIDOpts.SidetreeEndpoints)
+	sidetreeEndpoint, err := c.getEndpoint(ctx, domain, createDIDOpts.SidetreeEndpoints)
 	if err != nil {
 		return nil, err
 	}
 
-	sidetreeConfig, err := c.configService.GetSidetreeConfig(sidetreeEndpoint)
+	sidetreeConfig, err := c.getSidetreeConfig(ctx, sidetreeEndpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := buildCreateRequest(sidetreeConfig, createDIDOpts)
+	err = c.checkCreate(sidetreeConfig, createDIDOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.buildRequest(ctx, sidetreeConfig, func() ([]byte, error) {
+		if c.protocolVersion == ProtocolVersion1 {
+			return buildCreateRequestV1(sidetreeConfig, createDIDOpts)
+		}
+
+		return buildCreateRequest(sidetreeConfig, createDIDOpts)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to build sidetree request: %w", err)
 	}
 
-	responseBytes, err := c.sendRequest(req, sidetreeEndpoint)
+	responseBytes, err := c.sendRequest(ctx, operationCreate, req, sidetreeEndpoint, createDIDOpts.RequestHeaders)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send create sidetree request: %w", err)
 	}
 
+	didDoc, err := parseDocument(responseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.storeCommitments(didDoc.ID, sidetreeConfig.MultiHashAlgorithm, createDIDOpts.UpdatePublicKey,
+		createDIDOpts.RecoveryPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return didDoc, c.completeCreate(ctx, didDoc, sidetreeEndpoint, createDIDOpts)
+}
+
+// checkCreate checks the create options against the FIPS mode, the limits of the sidetree config and the did:web
+// publisher of the client
+func (c *Client) checkCreate(config *models.SidetreeConfig, opts *create.Opts) error {
+	if err := c.checkFIPSCreate(config, opts); err != nil {
+		return err
+	}
+
+	if err := checkLimitsCreate(config, opts); err != nil {
+		return err
+	}
+
+	return c.checkDIDWeb(opts.DIDWebPath)
+}
+
+// completeCreate verifies the anchored document of the created DID and publishes its did:web document, if requested
+func (c *Client) completeCreate(ctx context.Context, didDoc *docdid.Doc, endpointURL string,
+	opts *create.Opts) error {
+	if opts.VerifyAttempts > 0 {
+		if err := c.verifyCreated(ctx, didDoc.ID, endpointURL, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.DIDWebPath != "" {
+		return c.publishDIDWeb(didDoc, opts.DIDWebPath)
+	}
+
+	return nil
+}
+
+// parseDocument parses the document of a sidetree response, which is either the document or its resolution result
+func parseDocument(responseBytes []byte) (*docdid.Doc, error) {
 	var r didResolution
-	if errUnmarshal := json.Unmarshal(responseBytes, &r); errUnmarshal != nil {
-		return nil, fmt.Errorf("unmarshal data return from sidtree %w", errUnmarshal)
+	if err := json.Unmarshal(responseBytes, &r); err != nil {
+		return nil, fmt.Errorf("unmarshal data return from sidtree %w", err)
 	}
 
 	didDocBytes := responseBytes
@@ -132,7 +275,7 @@ func (c *Client) CreateDID(domain string, opts ...create.Option) (*docdid.Doc, e
 
 	didDoc, err := docdid.ParseDocument(didDocBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse public DID document: %s", err)
+		return nil, fmt.Errorf("failed to parse public DID document: %w", err)
 	}
 
 	return didDoc, nil
@@ -147,112 +290,549 @@ func validateCreateReq(createDIDOpts *create.Opts) error {
 		return fmt.Errorf("update public key is required")
 	}
 
-	return nil
+	err := doc.ValidatePurposes(createDIDOpts.PublicKeys)
+	if err != nil {
+		return err
+	}
+
+	return doc.ValidateIDs(createDIDOpts.PublicKeys, createDIDOpts.Services)
 }
 
 // UpdateDID update did doc
 func (c *Client) UpdateDID(did, domain string, opts ...update.Option) error {
-	updateDIDOpts := &update.Opts{}
-	// Apply options
-	for _, opt := range opts {
-		opt(updateDIDOpts)
+	ctx, span := c.startOperation(operationUpdate, did, domain)
+
+	_, err := c.idempotentOps.run(updateIdempotencyKey(opts), operationUpdate, did, func() (*docdid.Doc, error) {
+		return nil, c.updateDID(ctx, did, domain, opts)
+	})
+
+	span.End(err)
+
+	return err
+}
+
+func (c *Client) updateDID(ctx context.Context, did, domain string, opts []update.Option) error {
+	updateDIDOpts, err := getUpdateOpts(opts)
+	if err != nil {
+		return err
 	}
 
-	if updateDIDOpts.SigningKey == nil {
-		return fmt.Errorf("signing public key is required")
+	sidetreeEndpoint, err := c.getEndpoint(ctx, domain, updateDIDOpts.SidetreeEndpoints)
+	if err != nil {
+		return err
 	}
 
-	if updateDIDOpts.NextUpdatePublicKey == nil {
-		return fmt.Errorf("next update public key is required")
+	sidetreeConfig, err := c.getSidetreeConfig(ctx, sidetreeEndpoint)
+	if err != nil {
+		return err
+	}
+
+	if updateDIDOpts.SigningKey == nil {
+		updateDIDOpts.SigningKey, err = c.selectSigningKey(did, sidetreeEndpoint, updateDIDOpts.SigningKeySet,
+			sidetreeConfig.MultiHashAlgorithm, activeUpdateCommitment)
+	} else {
+		err = c.checkSigningKey(did, sidetreeEndpoint, updateDIDOpts.SigningKey, sidetreeConfig.MultiHashAlgorithm,
+			activeUpdateCommitment, ErrWrongUpdateKey)
 	}
 
-	sidetreeEndpoint, err := c.getEndpoint(domain, updateDIDOpts.SidetreeEndpoints)
 	if err != nil {
 		return err
 	}
 
-	sidetreeConfig, err := c.configService.GetSidetreeConfig(sidetreeEndpoint)
+	err = c.checkUpdate(sidetreeConfig, updateDIDOpts)
 	if err != nil {
 		return err
 	}
 
-	req, err := c.buildUpdateRequest(did, sidetreeConfig, updateDIDOpts)
+	req, err := c.buildRequest(ctx, sidetreeConfig, func() ([]byte, error) {
+		if c.protocolVersion == ProtocolVersion1 {
+			return buildUpdateRequestV1(did, sidetreeConfig, updateDIDOpts)
+		}
+
+		return c.buildUpdateRequest(did, sidetreeConfig, updateDIDOpts)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to build update request: %w", err)
 	}
 
-	_, err = c.sendRequest(req, sidetreeEndpoint)
+	_, err = c.sendRequest(ctx, operationUpdate, req, sidetreeEndpoint, updateDIDOpts.RequestHeaders)
 	if err != nil {
-		return fmt.Errorf("failed to send create sidetree request: %w", err)
+		return fmt.Errorf("failed to send update sidetree request: %w", err)
 	}
 
-	return nil
+	err = c.storeCommitments(did, sidetreeConfig.MultiHashAlgorithm, updateDIDOpts.NextUpdatePublicKey, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.publishUpdatedDIDWeb(did, sidetreeEndpoint, updateDIDOpts)
+}
+
+// checkUpdate checks the update options against the FIPS mode, the limits of the sidetree config and the did:web
+// publisher of the client
+func (c *Client) checkUpdate(config *models.SidetreeConfig, opts *update.Opts) error {
+	if err := c.checkFIPSUpdate(config, opts); err != nil {
+		return err
+	}
+
+	if err := checkLimitsUpdate(config, opts); err != nil {
+		return err
+	}
+
+	return c.checkDIDWeb(opts.DIDWebPath)
 }
 
 // RecoverDID recover did doc
 func (c *Client) RecoverDID(did, domain string, opts ...recovery.Option) error {
+	ctx, span := c.startOperation(operationRecover, did, domain)
+
+	_, err := c.idempotentOps.run(recoverIdempotencyKey(opts), operationRecover, did, func() (*docdid.Doc, error) {
+		return nil, c.recoverDID(ctx, did, domain, opts)
+	})
+
+	span.End(err)
+
+	return err
+}
+
+func (c *Client) recoverDID(ctx context.Context, did, domain string, opts []recovery.Option) error {
+	req, err := c.prepareRecoverRequest(ctx, did, domain, opts)
+	if err != nil {
+		return err
+	}
+
+	return c.sendRecoverRequest(ctx, did, req)
+}
+
+// BuildRecoverRequest builds the recovery request of the DID without sending it, so that it is approved before being
+// submitted with SubmitRecoverRequest, see package approval
+func (c *Client) BuildRecoverRequest(did, domain string, opts ...recovery.Option) ([]byte, error) {
+	ctx, span := c.startOperation(operationRecover, did, domain)
+
+	req, err := c.prepareRecoverRequest(ctx, did, domain, opts)
+
+	span.End(err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return req.body, nil
+}
+
+// SubmitRecoverRequest sends a recovery request of the DID built with BuildRecoverRequest, once its approvals reach
+// the threshold of the policy. The approvers and the threshold carried by the request are not trusted, the approvals
+// are verified against the policy. The options are the ones the request was built with, for the sidetree endpoints,
+// the request headers and the next keys recorded in the commitment store.
+func (c *Client) SubmitRecoverRequest(did string, request *approval.Request, policy *approval.Policy, domain string,
+	opts ...recovery.Option) error {
+	if err := checkApprovedRecoverRequest(did, request, policy); err != nil {
+		return err
+	}
+
+	ctx, span := c.startOperation(operationRecover, did, domain)
+
+	err := c.submitRecoverRequest(ctx, request, domain, opts)
+
+	span.End(err)
+
+	return err
+}
+
+// checkApprovedRecoverRequest checks that the approved request, which is signed by the approvers but not its DID, is
+// the recovery of the DID
+func checkApprovedRecoverRequest(did string, request *approval.Request, policy *approval.Policy) error {
+	if request.DID != did {
+		return fmt.Errorf("approval request is for %s, not %s", request.DID, did)
+	}
+
+	var body struct {
+		Type      string `json:"type"`
+		DIDSuffix string `json:"didSuffix"`
+	}
+
+	if err := json.Unmarshal(request.Request, &body); err != nil {
+		return fmt.Errorf("failed to unmarshal approved request: %w", err)
+	}
+
+	didSuffix, err := getUniqueSuffix(did)
+	if err != nil {
+		return err
+	}
+
+	if body.Type != operationRecover || body.DIDSuffix != didSuffix {
+		return fmt.Errorf("approved request is not the recovery of %s", did)
+	}
+
+	return policy.Verify(request)
+}
+
+func (c *Client) submitRecoverRequest(ctx context.Context, request *approval.Request, domain string,
+	opts []recovery.Option) error {
 	recoverDIDOpts := &recovery.Opts{}
 	// Apply options
 	for _, opt := range opts {
 		opt(recoverDIDOpts)
 	}
 
-	err := validateRecoverReq(recoverDIDOpts)
+	sidetreeEndpoint, err := c.getEndpoint(ctx, domain, recoverDIDOpts.SidetreeEndpoints)
 	if err != nil {
 		return err
 	}
 
-	sidetreeEndpoint, err := c.getEndpoint(domain, recoverDIDOpts.SidetreeEndpoints)
+	sidetreeConfig, err := c.getSidetreeConfig(ctx, sidetreeEndpoint)
 	if err != nil {
 		return err
 	}
 
-	sidetreeConfig, err := c.configService.GetSidetreeConfig(sidetreeEndpoint)
+	return c.sendRecoverRequest(ctx, request.DID, &recoverRequest{body: request.Request, endpoint: sidetreeEndpoint,
+		config: sidetreeConfig, opts: recoverDIDOpts})
+}
+
+// recoverRequest is a recovery request built for a sidetree endpoint
+type recoverRequest struct {
+	body     []byte
+	endpoint string
+	config   *models.SidetreeConfig
+	opts     *recovery.Opts
+}
+
+func (c *Client) prepareRecoverRequest(ctx context.Context, did, domain string,
+	opts []recovery.Option) (*recoverRequest, error) {
+	recoverDIDOpts := &recovery.Opts{}
+	// Apply options
+	for _, opt := range opts {
+		opt(recoverDIDOpts)
+	}
+
+	var err error
+
+	recoverDIDOpts.SigningKey, err = decryptSigningKey(recoverDIDOpts.SigningKey, recoverDIDOpts.EncryptedSigningKey,
+		recoverDIDOpts.SigningKeyPassphrase)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	req, err := buildRecoverRequest(did, sidetreeConfig, recoverDIDOpts)
+	err = validateRecoverReq(recoverDIDOpts)
 	if err != nil {
-		return fmt.Errorf("failed to build sidetree request: %w", err)
+		return nil, err
+	}
+
+	sidetreeEndpoint, err := c.getEndpoint(ctx, domain, recoverDIDOpts.SidetreeEndpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	sidetreeConfig, err := c.getSidetreeConfig(ctx, sidetreeEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if recoverDIDOpts.SigningKey == nil {
+		recoverDIDOpts.SigningKey, err = c.selectSigningKey(did, sidetreeEndpoint, recoverDIDOpts.SigningKeySet,
+			sidetreeConfig.MultiHashAlgorithm, activeRecoveryCommitment)
+	} else {
+		err = c.checkSigningKey(did, sidetreeEndpoint, recoverDIDOpts.SigningKey, sidetreeConfig.MultiHashAlgorithm,
+			activeRecoveryCommitment, ErrWrongRecoveryKey)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.checkFIPSRecover(sidetreeConfig, recoverDIDOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkLimitsRecover(sidetreeConfig, recoverDIDOpts)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = c.sendRequest(req, sidetreeEndpoint)
+	req, err := c.buildRequest(ctx, sidetreeConfig, func() ([]byte, error) {
+		if c.protocolVersion == ProtocolVersion1 {
+			return buildRecoverRequestV1(did, sidetreeConfig, recoverDIDOpts)
+		}
+
+		return buildRecoverRequest(did, sidetreeConfig, recoverDIDOpts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sidetree request: %w", err)
+	}
+
+	return &recoverRequest{body: req, endpoint: sidetreeEndpoint, config: sidetreeConfig, opts: recoverDIDOpts}, nil
+}
+
+func (c *Client) sendRecoverRequest(ctx context.Context, did string, req *recoverRequest) error {
+	_, err := c.sendRequest(ctx, operationRecover, req.body, req.endpoint, req.opts.RequestHeaders)
 	if err != nil {
 		return fmt.Errorf("failed to send recover sidetree request: %w", err)
 	}
 
-	return err
+	return c.storeCommitments(did, req.config.MultiHashAlgorithm, req.opts.NextUpdatePublicKey,
+		req.opts.NextRecoveryPublicKey)
 }
 
 // DeactivateDID deactivate did doc
 func (c *Client) DeactivateDID(did, domain string, opts ...deactivate.Option) error {
-	deactivateDIDOpts := &deactivate.Opts{}
-	// Apply options
-	for _, opt := range opts {
-		opt(deactivateDIDOpts)
+	ctx, span := c.startOperation(operationDeactivate, did, domain)
+
+	_, err := c.idempotentOps.run(deactivateIdempotencyKey(opts), operationDeactivate, did,
+		func() (*docdid.Doc, error) {
+			return nil, c.deactivateDID(ctx, did, domain, opts)
+		})
+
+	span.End(err)
+
+	return err
+}
+
+func (c *Client) deactivateDID(ctx context.Context, did, domain string, opts []deactivate.Option) error {
+	deactivateDIDOpts, err := getDeactivateOpts(opts)
+	if err != nil {
+		return err
+	}
+
+	sidetreeEndpoint, err := c.getEndpoint(ctx, domain, deactivateDIDOpts.SidetreeEndpoints)
+	if err != nil {
+		return err
+	}
+
+	sidetreeConfig, err := c.getDeactivateSidetreeConfig(ctx, sidetreeEndpoint)
+	if err != nil {
+		return err
 	}
 
 	if deactivateDIDOpts.SigningKey == nil {
-		return fmt.Errorf("signing key is required")
+		deactivateDIDOpts.SigningKey, err = c.selectRecoveryKey(did, sidetreeEndpoint, deactivateDIDOpts.SigningKeySet)
+		if err != nil {
+			return err
+		}
 	}
 
-	sidetreeEndpoint, err := c.getEndpoint(domain, deactivateDIDOpts.SidetreeEndpoints)
+	err = c.checkFIPSDeactivate(deactivateDIDOpts)
 	if err != nil {
 		return err
 	}
 
-	req, err := buildDeactivateRequest(did, deactivateDIDOpts)
+	req, err := c.buildRequest(ctx, sidetreeConfig, func() ([]byte, error) {
+		if c.protocolVersion == ProtocolVersion1 {
+			return buildDeactivateRequestV1(did, sidetreeConfig, deactivateDIDOpts)
+		}
+
+		return buildDeactivateRequest(did, deactivateDIDOpts)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to build sidetree request: %w", err)
 	}
 
-	_, err = c.sendRequest(req, sidetreeEndpoint)
+	_, err = c.sendRequest(ctx, operationDeactivate, req, sidetreeEndpoint, deactivateDIDOpts.RequestHeaders)
 	if err != nil {
 		return fmt.Errorf("failed to send deactivate sidetree request: %w", err)
 	}
 
-	return err
+	if c.commitmentStore != nil {
+		if err = c.commitmentStore.Delete(did); err != nil {
+			return fmt.Errorf("did deactivated but failed to delete its commitments: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// selectRecoveryKey selects the key of the key set the active recovery commitment of the DID commits to
+func (c *Client) selectRecoveryKey(did, sidetreeEndpoint string,
+	keySet []crypto.PrivateKey) (crypto.PrivateKey, error) {
+	sidetreeConfig, err := c.configService.GetSidetreeConfig(sidetreeEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.selectSigningKey(did, sidetreeEndpoint, keySet, sidetreeConfig.MultiHashAlgorithm,
+		activeRecoveryCommitment)
+}
+
+// storeCommitments records the keys the DID committed to in the commitment store, after a successful operation.
+// The commitments of the keys which are nil are kept.
+func (c *Client) storeCommitments(did string, multihashCode uint, updateKey, recoveryKey crypto.PublicKey) error {
+	if c.commitmentStore == nil {
+		return nil
+	}
+
+	commitments, err := c.commitmentStore.Get(did)
+	if errors.Is(err, commitmentstore.ErrNotFound) {
+		commitments = &commitmentstore.Commitments{DID: did}
+	} else if err != nil {
+		return fmt.Errorf("operation succeeded but failed to get the commitments of %s: %w", did, err)
+	}
+
+	commitments.MultihashAlgorithm = multihashCode
+
+	if updateKey != nil {
+		commitments.PreviousCommitments = appendCommitment(commitments.PreviousCommitments,
+			commitments.UpdateCommitment)
+
+		commitments.UpdateKey, commitments.UpdateCommitment, err = calculateCommitment(updateKey, multihashCode)
+		if err != nil {
+			return err
+		}
+	}
+
+	if recoveryKey != nil {
+		commitments.PreviousCommitments = appendCommitment(commitments.PreviousCommitments,
+			commitments.RecoveryCommitment)
+
+		commitments.RecoveryKey, commitments.RecoveryCommitment, err = calculateCommitment(recoveryKey, multihashCode)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = c.commitmentStore.Put(commitments); err != nil {
+		return fmt.Errorf("operation succeeded but failed to store the commitments of %s: %w", did, err)
+	}
+
+	return nil
+}
+
+func appendCommitment(commitments []string, value string) []string {
+	if value == "" {
+		return commitments
+	}
+
+	return append(commitments, value)
+}
+
+func calculateCommitment(key crypto.PublicKey, multihashCode uint) (*jws.JWK, string, error) {
+	jwk, err := pubkey.GetPublicKeyJWK(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get public key jwk: %w", err)
+	}
+
+	value, err := commitment.Calculate(jwk, multihashCode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return jwk, value, nil
+}
+
+func getUpdateOpts(opts []update.Option) (*update.Opts, error) {
+	updateDIDOpts := &update.Opts{}
+	// Apply options
+	for _, opt := range opts {
+		opt(updateDIDOpts)
+	}
+
+	var err error
+
+	updateDIDOpts.SigningKey, err = decryptSigningKey(updateDIDOpts.SigningKey, updateDIDOpts.EncryptedSigningKey,
+		updateDIDOpts.SigningKeyPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateDIDOpts.SigningKey == nil && len(updateDIDOpts.SigningKeySet) == 0 {
+		return nil, fmt.Errorf("signing public key is required")
+	}
+
+	if updateDIDOpts.NextUpdatePublicKey == nil {
+		return nil, fmt.Errorf("next update public key is required")
+	}
+
+	if err = validateUpdatePatches(updateDIDOpts); err != nil {
+		return nil, err
+	}
+
+	return updateDIDOpts, nil
+}
+
+// validateUpdatePatches checks the public keys and services added and removed by the update, as an ID both added
+// and removed would leave the result to the order the sidetree node applies the patches in, unless the public key is
+// replaced
+func validateUpdatePatches(updateDIDOpts *update.Opts) error {
+	err := doc.ValidatePurposes(updateDIDOpts.AddPublicKeys)
+	if err != nil {
+		return err
+	}
+
+	err = doc.ValidateIDs(updateDIDOpts.AddPublicKeys, updateDIDOpts.AddServices)
+	if err != nil {
+		return err
+	}
+
+	for i := range updateDIDOpts.AddPublicKeys {
+		id := updateDIDOpts.AddPublicKeys[i].ID
+
+		if containsID(updateDIDOpts.RemovePublicKeys, id) && !containsID(updateDIDOpts.ReplacedPublicKeys, id) {
+			return fmt.Errorf("public key %s is both added and removed", id)
+		}
+	}
+
+	for i := range updateDIDOpts.AddServices {
+		if containsID(updateDIDOpts.RemoveServices, updateDIDOpts.AddServices[i].ID) {
+			return fmt.Errorf("service %s is both added and removed", updateDIDOpts.AddServices[i].ID)
+		}
+	}
+
+	if id, ok := doc.DuplicateID(updateDIDOpts.RemovePublicKeys); ok {
+		return fmt.Errorf("duplicate removed public key ID %s", id)
+	}
+
+	if id, ok := doc.DuplicateID(updateDIDOpts.RemoveServices); ok {
+		return fmt.Errorf("duplicate removed service ID %s", id)
+	}
+
+	return nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getDeactivateOpts(opts []deactivate.Option) (*deactivate.Opts, error) {
+	deactivateDIDOpts := &deactivate.Opts{}
+	// Apply options
+	for _, opt := range opts {
+		opt(deactivateDIDOpts)
+	}
+
+	var err error
+
+	deactivateDIDOpts.SigningKey, err = decryptSigningKey(deactivateDIDOpts.SigningKey,
+		deactivateDIDOpts.EncryptedSigningKey, deactivateDIDOpts.SigningKeyPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if deactivateDIDOpts.SigningKey == nil && len(deactivateDIDOpts.SigningKeySet) == 0 {
+		return nil, fmt.Errorf("signing key is required")
+	}
+
+	return deactivateDIDOpts, nil
+}
+
+// decryptSigningKey returns the signing key decrypted from the encrypted signing key PEM if set, or else the
+// signing key
+func decryptSigningKey(signingKey crypto.PrivateKey, encryptedSigningKey,
+	passphrase []byte) (crypto.PrivateKey, error) {
+	if encryptedSigningKey == nil {
+		return signingKey, nil
+	}
+
+	key, err := pkcs8.ParsePrivateKeyPEM(encryptedSigningKey, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+	}
+
+	return key, nil
 }
 
 func validateRecoverReq(recoverDIDOpts *recovery.Opts) error {
@@ -264,30 +844,40 @@ func validateRecoverReq(recoverDIDOpts *recovery.Opts) error {
 		return fmt.Errorf("next update public key is required")
 	}
 
-	if recoverDIDOpts.SigningKey == nil {
+	if recoverDIDOpts.SigningKey == nil && len(recoverDIDOpts.SigningKeySet) == 0 {
 		return fmt.Errorf("signing key is required")
 	}
 
-	return nil
+	err := doc.ValidatePurposes(recoverDIDOpts.PublicKeys)
+	if err != nil {
+		return err
+	}
+
+	return doc.ValidateIDs(recoverDIDOpts.PublicKeys, recoverDIDOpts.Services)
 }
 
-func (c *Client) getEndpoint(domain string, sidetreeEndpoints []*models.Endpoint) (string, error) {
+func (c *Client) getEndpoint(ctx context.Context, domain string,
+	sidetreeEndpoints []*models.Endpoint) (string, error) {
 	if domain == "" && len(sidetreeEndpoints) == 0 {
-		return "", errors.New("domain is empty and sidetree endpoints is empty")
+		return "", ErrDomainEmpty
 	}
 
 	endpoints := sidetreeEndpoints
 
 	if domain != "" {
+		_, span := c.tracer.Start(ctx, tracing.SpanEndpointDiscovery, map[string]string{tracing.AttributeDomain: domain})
+
 		var err error
 		endpoints, err = c.endpointService.GetEndpoints(domain)
 
+		span.End(err)
+
 		if err != nil {
 			return "", fmt.Errorf("failed to get endpoints: %w", err)
 		}
 
 		if len(endpoints) == 0 {
-			return "", errors.New("list of endpoints is empty")
+			return "", ErrEndpointsEmpty
 		}
 	}
 
@@ -295,6 +885,68 @@ func (c *Client) getEndpoint(domain string, sidetreeEndpoints []*models.Endpoint
 	return endpoints[0].URL, nil
 }
 
+// getSidetreeConfig fetches the sidetree config of the endpoint in a span
+func (c *Client) getSidetreeConfig(ctx context.Context, sidetreeEndpoint string) (*models.SidetreeConfig, error) {
+	_, span := c.tracer.Start(ctx, tracing.SpanConfigFetch, map[string]string{tracing.AttributeURL: sidetreeEndpoint})
+
+	sidetreeConfig, err := c.configService.GetSidetreeConfig(sidetreeEndpoint)
+	if err == nil && c.protocolCheck {
+		err = checkProtocolVersion(sidetreeEndpoint, sidetreeConfig.Version, c.protocolVersion)
+	}
+
+	span.End(err)
+
+	return sidetreeConfig, err
+}
+
+// getDeactivateSidetreeConfig fetches the sidetree config of a deactivation. Deactivate requests of protocol version
+// 0.1 are built without it, so it's only fetched, for them, to check the protocol version, and is otherwise nil.
+func (c *Client) getDeactivateSidetreeConfig(ctx context.Context,
+	sidetreeEndpoint string) (*models.SidetreeConfig, error) {
+	if c.protocolVersion != ProtocolVersion1 && !c.protocolCheck {
+		return nil, nil
+	}
+
+	return c.getSidetreeConfig(ctx, sidetreeEndpoint)
+}
+
+// buildRequest builds a sidetree operation request of the protocol version of the client with the build function in a
+// span, canonicalizes it and checks its size against the sidetree config. The config may be nil for deactivate
+// requests, which are small.
+func (c *Client) buildRequest(ctx context.Context, config *models.SidetreeConfig,
+	build func() ([]byte, error)) ([]byte, error) {
+	_, span := c.tracer.Start(ctx, tracing.SpanBuildOperation, nil)
+
+	var req []byte
+
+	err := checkSupportedProtocolVersion(c.protocolVersion)
+	if err == nil {
+		req, err = build()
+	}
+
+	if err == nil {
+		req, err = CanonicalizeRequest(req)
+	}
+
+	if err == nil && config != nil {
+		err = checkOperationSize(config, req)
+	}
+
+	span.End(err)
+
+	return req, err
+}
+
+// startOperation starts the span of a DID operation, the DID being empty for a creation
+func (c *Client) startOperation(operation, did, domain string) (context.Context, tracing.Span) {
+	attributes := map[string]string{tracing.AttributeOperation: operation, tracing.AttributeDomain: domain}
+	if did != "" {
+		attributes[tracing.AttributeDID] = did
+	}
+
+	return c.tracer.Start(context.Background(), tracing.SpanDIDOperation, attributes)
+}
+
 // unwrapPubKeyJWK takes a key which may contain a JSON JWK as a public key value
 // and returns a PublicKey which contains the JWK's key value as the public key value
 func unwrapPubKeyJWK(key doc.PublicKey) (*doc.PublicKey, error) { // nolint: gocritic
@@ -321,7 +973,7 @@ func (c *Client) buildUpdateRequest(did string, sidetreeConfig *models.SidetreeC
 	updateDIDOpts *update.Opts) ([]byte, error) {
 	nextUpdateKey, err := pubkey.GetPublicKeyJWK(updateDIDOpts.NextUpdatePublicKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get next update key : %s", err)
+		return nil, fmt.Errorf("failed to get next update key : %w", err)
 	}
 
 	nextUpdateCommitment, err := commitment.Calculate(nextUpdateKey, sidetreeConfig.MultiHashAlgorithm)
@@ -374,23 +1026,36 @@ func buildDeactivateRequest(did string, deactivateDIDOpts *deactivate.Opts) ([]b
 }
 
 func getSigner(signingkey crypto.PrivateKey, keyID string) (client.Signer, *jws.JWK, error) {
+	signer, publicKey, err := newSigner(signingkey, keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signingPublicKey, err := pubkey.GetPublicKeyJWK(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return signer, signingPublicKey, nil
+}
+
+func newSigner(signingkey crypto.PrivateKey, keyID string) (client.Signer, crypto.PublicKey, error) {
 	switch key := signingkey.(type) {
 	case *ecdsa.PrivateKey:
-		updateKey, err := pubkey.GetPublicKeyJWK(key.Public())
-		if err != nil {
-			return nil, nil, err
-		}
-
-		return ecsigner.New(key, "ES256", keyID), updateKey, nil
+		return ecsigner.New(key, "ES256", keyID), key.Public(), nil
 	case ed25519.PrivateKey:
-		updateKey, err := pubkey.GetPublicKeyJWK(key.Public())
+		return edsigner.New(key, "EdDSA", keyID), key.Public(), nil
+	case *KMSSigner:
+		return &kmsOperationSigner{KMSSigner: key, kid: keyID}, key.Public(), nil
+	case crypto.Signer:
+		signer, err := newOpaqueSigner(key, keyID)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		return edsigner.New(key, "EdDSA", keyID), updateKey, nil
+		return signer, key.Public(), nil
 	default:
-		return nil, nil, fmt.Errorf("key not supported")
+		return nil, nil, ErrKeyNotSupported
 	}
 }
 
@@ -508,17 +1173,17 @@ func buildCreateRequest(sidetreeConfig *models.SidetreeConfig, createDIDOpts *cr
 
 	docBytes, err := didDoc.JSONBytes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get document bytes : %s", err)
+		return nil, fmt.Errorf("failed to get document bytes : %w", err)
 	}
 
 	recoveryKey, err := pubkey.GetPublicKeyJWK(createDIDOpts.RecoveryPublicKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recovery key : %s", err)
+		return nil, fmt.Errorf("failed to get recovery key : %w", err)
 	}
 
 	updateKey, err := pubkey.GetPublicKeyJWK(createDIDOpts.UpdatePublicKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get update key : %s", err)
+		return nil, fmt.Errorf("failed to get update key : %w", err)
 	}
 
 	recoveryCommitment, err := commitment.Calculate(recoveryKey, sidetreeConfig.MultiHashAlgorithm)
@@ -565,7 +1230,7 @@ func buildRecoverRequest(did string, sidetreeConfig *models.SidetreeConfig,
 
 	docBytes, err := didDoc.JSONBytes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get document bytes : %s", err)
+		return nil, fmt.Errorf("failed to get document bytes : %w", err)
 	}
 
 	nextRecoveryCommitment, nextUpdateCommitment, err := getCommitment(sidetreeConfig, recoverDIDOpts)
@@ -598,12 +1263,12 @@ func buildRecoverRequest(did string, sidetreeConfig *models.SidetreeConfig,
 func getCommitment(sidetreeConfig *models.SidetreeConfig, recoverDIDOpts *recovery.Opts) (string, string, error) {
 	nextRecoveryKey, err := pubkey.GetPublicKeyJWK(recoverDIDOpts.NextRecoveryPublicKey)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get next recovery key : %s", err)
+		return "", "", fmt.Errorf("failed to get next recovery key : %w", err)
 	}
 
 	nextUpdateKey, err := pubkey.GetPublicKeyJWK(recoverDIDOpts.NextUpdatePublicKey)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get next update key : %s", err)
+		return "", "", fmt.Errorf("failed to get next update key : %w", err)
 	}
 
 	nextRecoveryCommitment, err := commitment.Calculate(nextRecoveryKey, sidetreeConfig.MultiHashAlgorithm)
@@ -619,41 +1284,95 @@ func getCommitment(sidetreeConfig *models.SidetreeConfig, recoverDIDOpts *recove
 	return nextRecoveryCommitment, nextUpdateCommitment, nil
 }
 
-func (c *Client) sendRequest(req []byte, endpointURL string) ([]byte, error) {
+func (c *Client) sendRequest(ctx context.Context, operation string, req []byte, endpointURL string,
+	headers map[string]string) ([]byte, error) {
+	if c.dryRunCallback != nil {
+		c.logger.Debug("dry run, sidetree request not sent", "endpoint", endpointURL)
+
+		c.dryRunCallback(endpointURL, req)
+
+		return nil, ErrDryRun
+	}
+
+	c.throttle(endpointURL)
+
+	start := time.Now()
+
+	ctx, span := c.tracer.Start(ctx, tracing.SpanSidetreeOperation, map[string]string{tracing.AttributeURL: endpointURL})
+
+	responseBytes, err := c.postOperation(ctx, req, endpointURL, headers)
+
+	span.End(err)
+
+	metrics.RecordOperation(c.metrics, operation, endpointURL, start, err)
+
+	if err != nil {
+		c.logger.Debug("sidetree request failed", "endpoint", endpointURL, "error", err)
+
+		return nil, err
+	}
+
+	c.logger.Debug("sidetree request accepted", "endpoint", endpointURL, "duration", time.Since(start))
+
+	if c.operationCallback != nil {
+		hash := sha256.Sum256(req)
+
+		c.operationCallback(&OperationInfo{
+			Endpoint:    endpointURL,
+			RequestHash: base64.RawURLEncoding.EncodeToString(hash[:]),
+			Request:     req,
+		})
+	}
+
+	return responseBytes, nil
+}
+
+func (c *Client) postOperation(ctx context.Context, req []byte, endpointURL string,
+	headers map[string]string) ([]byte, error) {
 	httpReq, err := http.NewRequest(http.MethodPost, endpointURL+"/operations", bytes.NewReader(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	contentencoding.SetAcceptEncoding(httpReq)
+
+	for name, value := range headers {
+		httpReq.Header.Set(name, value)
+	}
 
 	if c.authToken != "" {
 		httpReq.Header.Add("Authorization", c.authToken)
 	}
 
+	c.tracer.Inject(ctx, httpReq.Header)
+
+	c.dumpRequest(httpReq, req)
+
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	defer closeResponseBody(resp.Body)
+	defer c.closeResponseBody(resp.Body)
 
-	responseBytes, err := ioutil.ReadAll(resp.Body)
+	responseBytes, err := contentencoding.ReadBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response : %s", err)
+		return nil, fmt.Errorf("failed to read response : %w", err)
 	}
 
+	c.dumpResponse(httpReq, resp, responseBytes)
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("got unexpected response from %s status '%d' body %s",
-			endpointURL, resp.StatusCode, responseBytes)
+		return nil, newSidetreeRequestError(endpointURL, resp.StatusCode, responseBytes)
 	}
 
 	return responseBytes, nil
 }
 
-func closeResponseBody(respBody io.Closer) {
+func (c *Client) closeResponseBody(respBody io.Closer) {
 	e := respBody.Close()
 	if e != nil {
-		log.Errorf("Failed to close response body: %v", e)
+		c.logger.Error("failed to close response body", "error", e)
 	}
 }
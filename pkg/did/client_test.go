@@ -6,30 +6,53 @@ SPDX-License-Identifier: Apache-2.0
 package did
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/approval"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/pkcs8"
 	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
 	mockdiscovery "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/discovery"
 	mockendpoint "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/endpoint"
+	mocklogger "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/logger"
+	mockmetrics "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/metrics"
 	mockselection "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/selection"
+	mocktracing "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/tracing"
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/endpoint"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
 )
 
 func TestClient_DeactivateDID(t *testing.T) {
@@ -42,6 +65,7 @@ func TestClient_DeactivateDID(t *testing.T) {
 		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSigningKey(privKey))
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "domain is empty")
+		require.True(t, errors.Is(err, ErrDomainEmpty))
 	})
 
 	t.Run("test signing key empty", func(t *testing.T) {
@@ -78,6 +102,7 @@ func TestClient_DeactivateDID(t *testing.T) {
 		err := v.DeactivateDID("did:ex:123", "testnet", deactivate.WithSigningKey("www"))
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "key not supported")
+		require.True(t, errors.Is(err, ErrKeyNotSupported))
 	})
 
 	t.Run("test error from unique suffix", func(t *testing.T) {
@@ -137,6 +162,78 @@ func TestClient_DeactivateDID(t *testing.T) {
 			deactivate.WithSidetreeEndpoint(serv.URL), deactivate.WithSigningKeyID("k1"))
 		require.NoError(t, err)
 	})
+
+	t.Run("test success with request header", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "abc-123", r.Header.Get("X-Correlation-ID"))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer serv.Close()
+
+		v := New()
+
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSigningKey(privKey),
+			deactivate.WithSidetreeEndpoint(serv.URL), deactivate.WithRequestHeader("X-Correlation-ID", "abc-123"))
+		require.NoError(t, err)
+	})
+
+	t.Run("test success with operation callback", func(t *testing.T) {
+		var body []byte
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			body, err = ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer serv.Close()
+
+		var info *OperationInfo
+
+		v := New(WithOperationCallback(func(i *OperationInfo) { info = i }))
+
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSigningKey(privKey),
+			deactivate.WithSidetreeEndpoint(serv.URL))
+		require.NoError(t, err)
+
+		hash := sha256.Sum256(body)
+
+		require.NotNil(t, info)
+		require.Equal(t, serv.URL, info.Endpoint)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(hash[:]), info.RequestHash)
+		require.Equal(t, body, info.Request)
+	})
+	t.Run("test dry run", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.FailNow(t, "request must not be sent")
+		}))
+		defer serv.Close()
+
+		var (
+			endpoint string
+			request  []byte
+		)
+
+		v := New(WithDryRun(func(e string, r []byte) {
+			endpoint = e
+			request = r
+		}))
+
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSigningKey(privKey),
+			deactivate.WithSidetreeEndpoint(serv.URL))
+		require.True(t, errors.Is(err, ErrDryRun))
+		require.Equal(t, serv.URL, endpoint)
+		require.Contains(t, string(request), `"type":"deactivate"`)
+	})
 }
 
 func TestClient_RecoverDID(t *testing.T) {
@@ -205,6 +302,31 @@ func TestClient_RecoverDID(t *testing.T) {
 		require.Contains(t, err.Error(), "signing key is required")
 	})
 
+	t.Run("test invalid purpose", func(t *testing.T) {
+		v := New()
+
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		err = v.RecoverDID("did:ex:123", "testnet", recovery.WithNextRecoveryPublicKey(pubKey),
+			recovery.WithNextUpdatePublicKey(pubKey), recovery.WithSigningKey(privKey),
+			recovery.WithPublicKey(&doc.PublicKey{ID: "key1", Purposes: []string{"authenication"}}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid purpose 'authenication' of public key key1")
+	})
+
+	t.Run("test duplicate service ID", func(t *testing.T) {
+		v := New()
+
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		err = v.RecoverDID("did:ex:123", "testnet", recovery.WithNextRecoveryPublicKey(pubKey),
+			recovery.WithNextUpdatePublicKey(pubKey), recovery.WithSigningKey(privKey),
+			recovery.WithService(&did.Service{ID: "svc1"}), recovery.WithService(&did.Service{ID: "svc1"}))
+		require.EqualError(t, err, "duplicate service ID svc1")
+	})
+
 	t.Run("test error from get endpoints", func(t *testing.T) {
 		v := New()
 
@@ -373,7 +495,7 @@ func TestClient_RecoverDID(t *testing.T) {
 
 	t.Run("test success", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			b, err := json.Marshal(didResolution{Context: "https://www.w3.org/ns/did-resolution/v1",
 				DIDDocument: bytes})
@@ -460,6 +582,55 @@ func TestClient_UpdateDID(t *testing.T) {
 		require.Contains(t, err.Error(), "next update public key is required")
 	})
 
+	t.Run("test invalid purpose", func(t *testing.T) {
+		v := New()
+
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		err = v.UpdateDID("did:ex:123", "testnet", update.WithSigningKey(privKey),
+			update.WithNextUpdatePublicKey(pubKey),
+			update.WithAddPublicKey(&doc.PublicKey{ID: "key1", Purposes: []string{"authenication"}}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid purpose 'authenication' of public key key1")
+	})
+
+	t.Run("test duplicate and conflicting IDs", func(t *testing.T) {
+		v := New()
+
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		for _, test := range []struct {
+			opts []update.Option
+			err  string
+		}{
+			{[]update.Option{update.WithAddPublicKey(&doc.PublicKey{ID: "key1"}),
+				update.WithAddPublicKey(&doc.PublicKey{ID: "key1"})}, "duplicate public key ID key1"},
+			{[]update.Option{update.WithAddService(&did.Service{ID: "svc1"}),
+				update.WithAddService(&did.Service{ID: "svc1"})}, "duplicate service ID svc1"},
+			{[]update.Option{update.WithAddPublicKey(&doc.PublicKey{ID: "key1"}),
+				update.WithRemovePublicKey("key1")}, "public key key1 is both added and removed"},
+			{[]update.Option{update.WithAddService(&did.Service{ID: "svc1"}),
+				update.WithRemoveService("svc1")}, "service svc1 is both added and removed"},
+			{[]update.Option{update.WithRemovePublicKey("key1"), update.WithRemovePublicKey("key1")},
+				"duplicate removed public key ID key1"},
+			{[]update.Option{update.WithReplacePublicKey(&doc.PublicKey{ID: "key1"}),
+				update.WithRemovePublicKey("key1")}, "duplicate removed public key ID key1"},
+			{[]update.Option{update.WithRemoveService("svc1"), update.WithRemoveService("svc1")},
+				"duplicate removed service ID svc1"},
+		} {
+			err = v.UpdateDID("did:ex:123", "testnet", append(test.opts, update.WithSigningKey(privKey),
+				update.WithNextUpdatePublicKey(pubKey))...)
+			require.EqualError(t, err, test.err)
+		}
+
+		err = v.UpdateDID("did:ex:123", "testnet", update.WithReplacePublicKey(&doc.PublicKey{ID: "key1"}),
+			update.WithSigningKey(privKey), update.WithNextUpdatePublicKey(pubKey))
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "both added and removed")
+	})
+
 	t.Run("test error from get endpoints", func(t *testing.T) {
 		v := New()
 
@@ -492,6 +663,7 @@ func TestClient_UpdateDID(t *testing.T) {
 			update.WithSigningKey(privKey))
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "list of endpoints is empty")
+		require.True(t, errors.Is(err, ErrEndpointsEmpty))
 	})
 
 	t.Run("test failed to get next update key", func(t *testing.T) {
@@ -587,7 +759,7 @@ func TestClient_UpdateDID(t *testing.T) {
 		err = v.UpdateDID("did:ex:123", "",
 			update.WithSidetreeEndpoint(serv.URL), update.WithSigningKey(ecPrivKey),
 			update.WithNextUpdatePublicKey(pubKey), update.WithRemoveService("svc1"),
-			update.WithRemoveService("svc1"), update.WithRemovePublicKey("k1"),
+			update.WithRemoveService("svc2"), update.WithRemovePublicKey("k1"),
 			update.WithRemovePublicKey("k2"), update.WithAddPublicKey(&doc.PublicKey{ID: "key3",
 				Encoding: doc.PublicKeyEncodingJwk, KeyType: doc.Ed25519KeyType, Value: pubKey}),
 			update.WithAddService(&did.Service{ID: "svc3"}))
@@ -708,6 +880,7 @@ func TestClient_CreateDID(t *testing.T) {
 		// test http status not equal 200
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "internal error")
 		}))
 		defer serv.Close()
 
@@ -719,9 +892,17 @@ func TestClient_CreateDID(t *testing.T) {
 		createDID, err = v.CreateDID("testnet", create.WithRecoveryPublicKey(ed25519RecoveryPubKey),
 			create.WithUpdatePublicKey(ed25519UpdatePubKey))
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "got unexpected response")
+		require.Contains(t, err.Error(), "failed to send create sidetree request: got unexpected response from "+
+			serv.URL+" status '500' body internal error")
 		require.Nil(t, createDID)
 
+		var requestFailed *ErrSidetreeRequestFailed
+
+		require.True(t, errors.As(err, &requestFailed))
+		require.Equal(t, serv.URL, requestFailed.URL)
+		require.Equal(t, http.StatusInternalServerError, requestFailed.Status)
+		require.Equal(t, "internal error", string(requestFailed.Body))
+
 		// test failed to parse did
 		serv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			bytes, err1 := (&did.Doc{ID: "did1"}).JSONBytes()
@@ -745,7 +926,7 @@ func TestClient_CreateDID(t *testing.T) {
 
 	t.Run("test success", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			b, err := json.Marshal(didResolution{Context: "https://www.w3.org/ns/did-resolution/v1",
 				DIDDocument: bytes})
@@ -794,7 +975,7 @@ func TestClient_CreateDID(t *testing.T) {
 
 	t.Run("test create DID - invalid key type", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			_, err = fmt.Fprint(w, string(bytes))
 			require.NoError(t, err)
@@ -828,7 +1009,7 @@ func TestClient_CreateDID(t *testing.T) {
 
 	t.Run("test create DID - EC key error", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			_, err = fmt.Fprint(w, string(bytes))
 			require.NoError(t, err)
@@ -867,7 +1048,7 @@ func TestClient_CreateDID(t *testing.T) {
 
 	t.Run("test unsupported recovery public key type", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			_, err = fmt.Fprint(w, string(bytes))
 			require.NoError(t, err)
@@ -890,7 +1071,7 @@ func TestClient_CreateDID(t *testing.T) {
 
 	t.Run("test recovery public key empty", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			_, err = fmt.Fprint(w, string(bytes))
 			require.NoError(t, err)
@@ -912,7 +1093,7 @@ func TestClient_CreateDID(t *testing.T) {
 
 	t.Run("test update public key empty", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			_, err = fmt.Fprint(w, string(bytes))
 			require.NoError(t, err)
@@ -935,9 +1116,32 @@ func TestClient_CreateDID(t *testing.T) {
 		require.Nil(t, createDID)
 	})
 
+	t.Run("test invalid purpose", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		createDID, err := New().CreateDID("testnet", create.WithRecoveryPublicKey(pubKey),
+			create.WithUpdatePublicKey(pubKey),
+			create.WithPublicKey(&doc.PublicKey{ID: "key1", Purposes: []string{"authenication"}}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid purpose 'authenication' of public key key1")
+		require.Nil(t, createDID)
+	})
+
+	t.Run("test duplicate public key ID", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		createDID, err := New().CreateDID("testnet", create.WithRecoveryPublicKey(pubKey),
+			create.WithUpdatePublicKey(pubKey), create.WithPublicKey(&doc.PublicKey{ID: "key1"}),
+			create.WithPublicKey(&doc.PublicKey{ID: "key1"}))
+		require.EqualError(t, err, "duplicate public key ID key1")
+		require.Nil(t, createDID)
+	})
+
 	t.Run("test unsupported public key encoding", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.Context}}).JSONBytes()
+			bytes, err := (&did.Doc{ID: "did1", Context: []string{did.ContextV1}}).JSONBytes()
 			require.NoError(t, err)
 			_, err = fmt.Fprint(w, string(bytes))
 			require.NoError(t, err)
@@ -963,6 +1167,467 @@ func TestClient_CreateDID(t *testing.T) {
 	})
 }
 
+func TestClient_CommitmentStore(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bytes, err := (&did.Doc{ID: "did:ex:123", Context: []string{did.ContextV1}}).JSONBytes()
+		require.NoError(t, err)
+		_, err = w.Write(bytes)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	newClient := func(store commitmentstore.Store) *Client {
+		v := New(WithCommitmentStore(store))
+
+		v.endpointService = &mockendpoint.MockEndpointService{
+			GetEndpointsFunc: func(domain string) (endpoints []*models.Endpoint, err error) {
+				return []*models.Endpoint{{URL: serv.URL}}, nil
+			}}
+
+		v.configService = &mockconfig.MockConfigService{
+			GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+				return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+			}}
+
+		return v
+	}
+
+	recoveryPubKey, recoveryPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	updatePubKey, updatePrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	nextUpdateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	nextRecoveryPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test commitments of the operations", func(t *testing.T) {
+		store := commitmentstore.NewMemStore()
+		v := newClient(store)
+
+		_, err := v.CreateDID("testnet", create.WithRecoveryPublicKey(recoveryPubKey),
+			create.WithUpdatePublicKey(updatePubKey))
+		require.NoError(t, err)
+
+		commitments, err := store.Get("did:ex:123")
+		require.NoError(t, err)
+		require.Equal(t, uint(18), commitments.MultihashAlgorithm)
+		require.NotEmpty(t, commitments.UpdateCommitment)
+		require.NotEmpty(t, commitments.RecoveryCommitment)
+		require.Equal(t, "Ed25519", commitments.UpdateKey.Crv)
+
+		recoveryCommitment := commitments.RecoveryCommitment
+
+		err = v.UpdateDID("did:ex:123", "testnet", update.WithSigningKey(updatePrivKey),
+			update.WithNextUpdatePublicKey(nextUpdateKey.Public()), update.WithRemoveService("svc1"))
+		require.NoError(t, err)
+
+		commitments, err = store.Get("did:ex:123")
+		require.NoError(t, err)
+		require.Equal(t, "P-256", commitments.UpdateKey.Crv)
+		require.Equal(t, recoveryCommitment, commitments.RecoveryCommitment)
+
+		err = v.RecoverDID("did:ex:123", "testnet", recovery.WithSigningKey(recoveryPrivKey),
+			recovery.WithNextRecoveryPublicKey(nextRecoveryPubKey), recovery.WithNextUpdatePublicKey(updatePubKey))
+		require.NoError(t, err)
+
+		commitments, err = store.Get("did:ex:123")
+		require.NoError(t, err)
+		require.NotEqual(t, recoveryCommitment, commitments.RecoveryCommitment)
+		require.Equal(t, "Ed25519", commitments.UpdateKey.Crv)
+		require.Len(t, commitments.PreviousCommitments, 3)
+		require.Equal(t, recoveryCommitment, commitments.PreviousCommitments[2])
+
+		err = v.DeactivateDID("did:ex:123", "testnet", deactivate.WithSigningKey(recoveryPrivKey))
+		require.NoError(t, err)
+
+		_, err = store.Get("did:ex:123")
+		require.True(t, errors.Is(err, commitmentstore.ErrNotFound))
+	})
+
+	t.Run("test store error", func(t *testing.T) {
+		v := newClient(&failingStore{err: errors.New("store error")})
+
+		_, err := v.CreateDID("testnet", create.WithRecoveryPublicKey(recoveryPubKey),
+			create.WithUpdatePublicKey(updatePubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "operation succeeded but failed to get the commitments of did:ex:123")
+
+		err = v.DeactivateDID("did:ex:123", "testnet", deactivate.WithSigningKey(recoveryPrivKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "did deactivated but failed to delete its commitments: store error")
+	})
+}
+
+func TestClient_SigningKeySet(t *testing.T) {
+	_, updatePrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	recoveryPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, otherPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, updateValue, err := calculateCommitment(updatePrivKey.Public(), 18)
+	require.NoError(t, err)
+
+	_, recoveryValue, err := calculateCommitment(recoveryPrivKey.Public(), 18)
+	require.NoError(t, err)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/identifiers/did:ex:123":
+			_, err := fmt.Fprintf(w, `{"methodMetadata":{"updateCommitment":%q,"recoveryCommitment":%q}}`,
+				updateValue, recoveryValue)
+			require.NoError(t, err)
+		case "/operations":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer serv.Close()
+
+	// an update needs a patch
+	removeService := update.WithRemoveService("svc1")
+
+	v := New()
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	t.Run("test key selection", func(t *testing.T) {
+		key, err := v.selectSigningKey("did:ex:123", serv.URL, []crypto.PrivateKey{otherPrivKey, updatePrivKey}, 18,
+			activeUpdateCommitment)
+		require.NoError(t, err)
+		require.Equal(t, updatePrivKey, key)
+
+		key, err = v.selectSigningKey("did:ex:123", serv.URL, []crypto.PrivateKey{otherPrivKey, recoveryPrivKey}, 18,
+			activeRecoveryCommitment)
+		require.NoError(t, err)
+		require.Equal(t, recoveryPrivKey, key)
+	})
+
+	t.Run("test operations with key set", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKeySet(otherPrivKey, updatePrivKey, recoveryPrivKey),
+			update.WithNextUpdatePublicKey(otherPrivKey.Public()), removeService)
+		require.NoError(t, err)
+
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithSigningKeySet(updatePrivKey, recoveryPrivKey),
+			recovery.WithNextRecoveryPublicKey(otherPrivKey.Public()),
+			recovery.WithNextUpdatePublicKey(otherPrivKey.Public()))
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKeySet(recoveryPrivKey))
+		require.NoError(t, err)
+	})
+
+	t.Run("test update with candidate signing keys", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKeys(otherPrivKey, updatePrivKey),
+			update.WithNextUpdatePublicKey(otherPrivKey.Public()), removeService)
+		require.NoError(t, err)
+
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKeys(otherPrivKey), update.WithNextUpdatePublicKey(otherPrivKey.Public()), removeService)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "none of the keys of the key set matches the active commitment of did:ex:123")
+	})
+
+	t.Run("test no matching key", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKeySet(otherPrivKey, recoveryPrivKey),
+			update.WithNextUpdatePublicKey(otherPrivKey.Public()), removeService)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "none of the keys of the key set matches the active commitment of did:ex:123")
+	})
+
+	t.Run("test signing key checked", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(updatePrivKey), update.WithNextUpdatePublicKey(otherPrivKey.Public()), removeService)
+		require.NoError(t, err)
+
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(otherPrivKey), update.WithNextUpdatePublicKey(otherPrivKey.Public()), removeService)
+		require.True(t, errors.Is(err, ErrWrongUpdateKey))
+		require.Contains(t, err.Error(), "the signing key doesn't match the active commitment of did:ex:123")
+
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithSigningKey(updatePrivKey),
+			recovery.WithNextRecoveryPublicKey(otherPrivKey.Public()),
+			recovery.WithNextUpdatePublicKey(otherPrivKey.Public()))
+		require.True(t, errors.Is(err, ErrWrongRecoveryKey))
+
+		// the check is skipped if the DID can't be resolved
+		err = v.UpdateDID("did:ex:456", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(otherPrivKey), update.WithNextUpdatePublicKey(updatePrivKey.Public()), removeService)
+		require.NoError(t, err)
+	})
+
+	t.Run("test unsupported key", func(t *testing.T) {
+		err := v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKeySet("www"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key not supported")
+	})
+
+	t.Run("test resolution error", func(t *testing.T) {
+		err := v.DeactivateDID("did:ex:456", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKeySet(recoveryPrivKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve the active commitment")
+		require.Contains(t, err.Error(), "status '404'")
+	})
+
+	t.Run("test no active commitment", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(`{"methodMetadata":{}}`))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		err := v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithSigningKeySet(recoveryPrivKey),
+			recovery.WithNextRecoveryPublicKey(otherPrivKey.Public()),
+			recovery.WithNextUpdatePublicKey(otherPrivKey.Public()))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolution of did:ex:123 has no active commitment")
+	})
+
+	t.Run("test invalid resolution", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(`{`))
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		_, err := v.selectSigningKey("did:ex:123", serv.URL, nil, 18, activeUpdateCommitment)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal resolution")
+	})
+}
+
+func TestClient_RecoverApproval(t *testing.T) {
+	var submitted []byte
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the DID isn't resolved, so the signing key isn't checked against its active commitment
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		var err error
+
+		submitted, err = ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	v := New()
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	_, recoveryPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	nextPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	approverPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	policy, err := approval.NewPolicy(1, approval.NewApprover("approver1", approverPrivKey.Public()))
+	require.NoError(t, err)
+
+	req, err := v.BuildRecoverRequest("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+		recovery.WithSigningKey(recoveryPrivKey), recovery.WithNextRecoveryPublicKey(nextPubKey),
+		recovery.WithNextUpdatePublicKey(nextPubKey))
+	require.NoError(t, err)
+	require.Nil(t, submitted)
+
+	t.Run("test submit approved request", func(t *testing.T) {
+		request, err := approval.NewRequest("did:ex:123", req, 1, policy.Approvers...)
+		require.NoError(t, err)
+
+		err = v.SubmitRecoverRequest("did:ex:123", request, policy, "", recovery.WithSidetreeEndpoint(serv.URL))
+		require.True(t, errors.Is(err, approval.ErrNotApproved))
+		require.Nil(t, submitted)
+
+		a, err := approval.Sign(request, "approver1", approverPrivKey)
+		require.NoError(t, err)
+		require.NoError(t, request.AddApproval(a))
+
+		err = v.SubmitRecoverRequest("did:ex:123", request, policy, "", recovery.WithSidetreeEndpoint(serv.URL))
+		require.NoError(t, err)
+		require.Equal(t, req, submitted)
+	})
+
+	t.Run("test submit request with tampered approvers", func(t *testing.T) {
+		submitted = nil
+
+		// the approver list of the exported request is replaced with a key of the attacker
+		_, forgedKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		request, err := approval.NewRequest("did:ex:123", req, 1,
+			approval.NewApprover("approver1", forgedKey.Public()))
+		require.NoError(t, err)
+
+		a, err := approval.Sign(request, "approver1", forgedKey)
+		require.NoError(t, err)
+		require.NoError(t, request.AddApproval(a))
+		require.True(t, request.Approved())
+
+		err = v.SubmitRecoverRequest("did:ex:123", request, policy, "", recovery.WithSidetreeEndpoint(serv.URL))
+		require.True(t, errors.Is(err, approval.ErrNotApproved))
+		require.Nil(t, submitted)
+	})
+
+	t.Run("test submit request of another DID", func(t *testing.T) {
+		submitted = nil
+
+		request, err := approval.NewRequest("did:ex:456", req, 1, policy.Approvers...)
+		require.NoError(t, err)
+
+		a, err := approval.Sign(request, "approver1", approverPrivKey)
+		require.NoError(t, err)
+		require.NoError(t, request.AddApproval(a))
+
+		err = v.SubmitRecoverRequest("did:ex:456", request, policy, "", recovery.WithSidetreeEndpoint(serv.URL))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "approved request is not the recovery of did:ex:456")
+
+		err = v.SubmitRecoverRequest("did:ex:123", request, policy, "", recovery.WithSidetreeEndpoint(serv.URL))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "approval request is for did:ex:456, not did:ex:123")
+
+		for _, body := range []string{`{`, `{"type":"deactivate","didSuffix":"456"}`} {
+			request, err = approval.NewRequest("did:ex:456", []byte(body), 1, policy.Approvers...)
+			require.NoError(t, err)
+
+			err = v.SubmitRecoverRequest("did:ex:456", request, policy, "")
+			require.Error(t, err)
+		}
+
+		require.Nil(t, submitted)
+	})
+
+	t.Run("test build error", func(t *testing.T) {
+		_, err := v.BuildRecoverRequest("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "next recovery public key is required")
+	})
+
+	t.Run("test submit without endpoint", func(t *testing.T) {
+		request, err := approval.NewRequest("did:ex:123", req, 1, policy.Approvers...)
+		require.NoError(t, err)
+
+		a, err := approval.Sign(request, "approver1", approverPrivKey)
+		require.NoError(t, err)
+		require.NoError(t, request.AddApproval(a))
+
+		err = v.SubmitRecoverRequest("did:ex:123", request, policy, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "domain is empty and sidetree endpoints is empty")
+	})
+}
+
+func TestClient_EncryptedSigningKey(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	v := New()
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	encrypted, err := pkcs8.Encrypt(der, []byte("passphrase"))
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: pkcs8.EncryptedPEMType, Bytes: encrypted})
+
+	nextUpdatePubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	nextRecoveryPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test operations with encrypted signing key", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithEncryptedSigningKey(keyPEM, []byte("passphrase")),
+			update.WithNextUpdatePublicKey(nextUpdatePubKey), update.WithRemoveService("svc1"))
+		require.NoError(t, err)
+
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithEncryptedSigningKey(keyPEM, []byte("passphrase")),
+			recovery.WithNextRecoveryPublicKey(nextRecoveryPubKey),
+			recovery.WithNextUpdatePublicKey(nextUpdatePubKey))
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithEncryptedSigningKey(keyPEM, []byte("passphrase")))
+		require.NoError(t, err)
+	})
+
+	t.Run("test wrong passphrase", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithEncryptedSigningKey(keyPEM, []byte("wrong")),
+			update.WithNextUpdatePublicKey(privateKey.Public()))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt signing key")
+
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithEncryptedSigningKey(keyPEM, []byte("wrong")),
+			recovery.WithNextRecoveryPublicKey(privateKey.Public()),
+			recovery.WithNextUpdatePublicKey(privateKey.Public()))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt signing key")
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithEncryptedSigningKey(keyPEM, []byte("wrong")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt signing key")
+	})
+}
+
+type failingStore struct {
+	err error
+}
+
+func (s *failingStore) Put(*commitmentstore.Commitments) error {
+	return s.err
+}
+
+func (s *failingStore) Get(string) (*commitmentstore.Commitments, error) {
+	return nil, s.err
+}
+
+func (s *failingStore) Delete(string) error {
+	return s.err
+}
+
 func Test_unwrapPubKeyJWK(t *testing.T) {
 	t.Run("no wrapping", func(t *testing.T) {
 		key := doc.PublicKey{Value: []byte("abcd")}
@@ -1012,3 +1677,479 @@ func selectionMock(endpoints []*models.Endpoint, err error) *mockselection.MockS
 		},
 	}
 }
+
+func TestClient_FIPSMode(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	multihashCode := uint(18)
+
+	v := New(WithFIPSMode())
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: multihashCode}, nil
+		}}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test operations with P-256 keys", func(t *testing.T) {
+		nextUpdateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		nextRecoveryKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(ecKey), update.WithNextUpdatePublicKey(nextUpdateKey.Public()),
+			update.WithRemoveService("svc1"))
+		require.NoError(t, err)
+
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithSigningKey(ecKey), recovery.WithNextRecoveryPublicKey(nextRecoveryKey.Public()),
+			recovery.WithNextUpdatePublicKey(nextUpdateKey.Public()))
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(ecKey))
+		require.NoError(t, err)
+	})
+
+	t.Run("test ed25519 keys", func(t *testing.T) {
+		_, err := v.CreateDID("", create.WithSidetreeEndpoint(serv.URL),
+			create.WithRecoveryPublicKey(edPublicKey), create.WithUpdatePublicKey(ecKey.Public()))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(edKey), update.WithNextUpdatePublicKey(ecKey.Public()))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+		require.Contains(t, err.Error(), "ed25519")
+
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithSigningKey(ecKey), recovery.WithNextRecoveryPublicKey(ecKey.Public()),
+			recovery.WithNextUpdatePublicKey(edPublicKey))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(edKey))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+	})
+
+	t.Run("test ed25519 document key", func(t *testing.T) {
+		_, err := v.CreateDID("", create.WithSidetreeEndpoint(serv.URL),
+			create.WithRecoveryPublicKey(ecKey.Public()), create.WithUpdatePublicKey(ecKey.Public()),
+			create.WithPublicKey(&doc.PublicKey{ID: "key1", Type: doc.JWSVerificationKey2020,
+				KeyType: doc.Ed25519KeyType, Value: edPublicKey}))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+		require.Contains(t, err.Error(), "public key key1")
+	})
+
+	t.Run("test P-384 key", func(t *testing.T) {
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(p384Key), update.WithNextUpdatePublicKey(ecKey.Public()))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+		require.Contains(t, err.Error(), "P-384")
+	})
+
+	t.Run("test sha3 multihash", func(t *testing.T) {
+		multihashCode = 22
+		defer func() { multihashCode = 18 }()
+
+		err := v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL),
+			update.WithSigningKey(ecKey), update.WithNextUpdatePublicKey(ecKey.Public()))
+		require.True(t, errors.Is(err, ErrNotFIPSApproved))
+		require.Contains(t, err.Error(), "multihash algorithm 22")
+	})
+
+	t.Run("test ed25519 keys without fips mode", func(t *testing.T) {
+		err := New().checkFIPS(22, edKey)
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	failingServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServ.Close()
+
+	l := &mocklogger.Logger{}
+
+	v := New(WithLogger(l))
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+		deactivate.WithSigningKey(privateKey))
+	require.NoError(t, err)
+
+	entry := l.Entry("sidetree request accepted")
+	require.NotNil(t, entry)
+	require.Equal(t, "debug", entry.Level)
+	require.Equal(t, serv.URL, entry.Fields["endpoint"])
+
+	_, err = v.sendRequest(context.Background(), operationCreate, []byte("{}"), failingServ.URL, nil)
+	require.Error(t, err)
+	require.NotNil(t, l.Entry("sidetree request failed"))
+}
+
+func TestClient_WithMetricsProvider(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	failingServ := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServ.Close()
+
+	p := mockmetrics.NewProvider()
+
+	v := New(WithMetricsProvider(p))
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+		deactivate.WithSigningKey(privateKey))
+	require.NoError(t, err)
+
+	nextUpdatePubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(failingServ.URL),
+		update.WithSigningKey(privateKey), update.WithNextUpdatePublicKey(nextUpdatePubKey),
+		update.WithRemoveService("svc1"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to send update sidetree request")
+
+	domain := metrics.DomainFromURL(serv.URL)
+	require.Equal(t, 1, p.OperationCount(metrics.SidetreeOperationRequests, domain, "deactivate",
+		metrics.StatusSuccess))
+	require.Equal(t, 1, p.OperationObservations(metrics.SidetreeOperationDuration, domain, "deactivate",
+		metrics.StatusSuccess))
+	require.Equal(t, 1, p.OperationCount(metrics.SidetreeOperationRequests, metrics.DomainFromURL(failingServ.URL),
+		"update", metrics.StatusFailure))
+}
+
+func TestClient_WithTracer(t *testing.T) {
+	var spanHeader string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spanHeader = r.Header.Get(mocktracing.SpanHeader)
+	}))
+	defer serv.Close()
+
+	ecPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	nextUpdatePubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test spans of an operation", func(t *testing.T) {
+		tracer := &mocktracing.Tracer{}
+
+		v := New(WithTracer(tracer))
+
+		v.endpointService = &mockendpoint.MockEndpointService{
+			GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+				return []*models.Endpoint{{URL: serv.URL}}, nil
+			}}
+
+		v.configService = &mockconfig.MockConfigService{
+			GetSidetreeConfigFunc: func(s string) (*models.SidetreeConfig, error) {
+				return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+			}}
+
+		err = v.UpdateDID("did:ex:123", "testnet", update.WithSigningKey(ecPrivKey),
+			update.WithNextUpdatePublicKey(nextUpdatePubKey), update.WithRemoveService("svc1"))
+		require.NoError(t, err)
+
+		spans := tracer.Spans(tracing.SpanDIDOperation)
+		require.Len(t, spans, 1)
+		require.True(t, spans[0].Ended)
+		require.NoError(t, spans[0].Err)
+		require.Equal(t, map[string]string{tracing.AttributeOperation: "update", tracing.AttributeDID: "did:ex:123",
+			tracing.AttributeDomain: "testnet"}, spans[0].Attributes)
+
+		for _, name := range []string{tracing.SpanEndpointDiscovery, tracing.SpanConfigFetch,
+			tracing.SpanBuildOperation, tracing.SpanSidetreeOperation} {
+			spans = tracer.Spans(name)
+			require.Len(t, spans, 1, name)
+			require.Equal(t, tracing.SpanDIDOperation, spans[0].Parent, name)
+			require.True(t, spans[0].Ended, name)
+		}
+
+		require.Equal(t, "testnet", tracer.Spans(tracing.SpanEndpointDiscovery)[0].Attributes[tracing.AttributeDomain])
+		require.Equal(t, serv.URL, tracer.Spans(tracing.SpanSidetreeOperation)[0].Attributes[tracing.AttributeURL])
+		require.Equal(t, tracing.SpanSidetreeOperation, spanHeader)
+	})
+
+	t.Run("test failed operation", func(t *testing.T) {
+		tracer := &mocktracing.Tracer{}
+
+		v := New(WithTracer(tracer))
+
+		v.endpointService = &mockendpoint.MockEndpointService{
+			GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+				return nil, errors.New("discovery error")
+			}}
+
+		err = v.DeactivateDID("did:ex:123", "testnet", deactivate.WithSigningKey(ecPrivKey))
+		require.Error(t, err)
+
+		spans := tracer.Spans(tracing.SpanDIDOperation)
+		require.Len(t, spans, 1)
+		require.Equal(t, err, spans[0].Err)
+		require.Equal(t, "deactivate", spans[0].Attributes[tracing.AttributeOperation])
+
+		spans = tracer.Spans(tracing.SpanEndpointDiscovery)
+		require.Len(t, spans, 1)
+		require.EqualError(t, spans[0].Err, "discovery error")
+		require.Empty(t, tracer.Spans(tracing.SpanSidetreeOperation))
+	})
+}
+
+func TestClient_WithDebugHTTP(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := w.Write([]byte(`{"code":"invalid-delta"}`))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test requests and responses logged", func(t *testing.T) {
+		l := &mocklogger.Logger{}
+
+		v := New(WithLogger(l), WithAuthToken("tk1"), WithDebugHTTP())
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privateKey), deactivate.WithRequestHeader("X-Tenant", "tenant1"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "got unexpected response")
+
+		entry := l.Entry("sidetree http request")
+		require.NotNil(t, entry)
+		require.Equal(t, "info", entry.Level)
+		require.Equal(t, http.MethodPost, entry.Fields["method"])
+		require.Equal(t, serv.URL+"/operations", entry.Fields["url"])
+		headers, ok := entry.Fields["headers"].(map[string]string)
+		require.True(t, ok)
+		require.Equal(t, "[REDACTED]", headers["Authorization"])
+		require.Equal(t, "tenant1", headers["X-Tenant"])
+		require.Contains(t, entry.Fields["body"], "\n  \"type\": \"deactivate\"")
+
+		entry = l.Entry("sidetree http response")
+		require.NotNil(t, entry)
+		require.Equal(t, http.StatusBadRequest, entry.Fields["status"])
+		headers, ok = entry.Fields["headers"].(map[string]string)
+		require.True(t, ok)
+		require.Equal(t, "[REDACTED]", headers["Set-Cookie"])
+		require.Equal(t, "{\n  \"code\": \"invalid-delta\"\n}", entry.Fields["body"])
+	})
+
+	t.Run("test nothing logged by default", func(t *testing.T) {
+		l := &mocklogger.Logger{}
+
+		v := New(WithLogger(l))
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privateKey))
+		require.Error(t, err)
+		require.Nil(t, l.Entry("sidetree http request"))
+		require.Nil(t, l.Entry("sidetree http response"))
+	})
+
+	t.Run("test body which is not json", func(t *testing.T) {
+		require.Equal(t, "not json", prettyBody([]byte("not json")))
+		require.Empty(t, prettyBody(nil))
+	})
+}
+
+func TestClient_WithClientCertificates(t *testing.T) {
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+
+	t.Run("test no client certificates", func(t *testing.T) {
+		tlsConfig := &tls.Config{ServerName: "test", MinVersion: tls.VersionTLS12}
+
+		v := New(WithTLSConfig(tlsConfig))
+		require.Equal(t, tlsConfig, v.transportTLSConfig())
+	})
+
+	t.Run("test client certificates added to the tls config", func(t *testing.T) {
+		tlsConfig := &tls.Config{ServerName: "test", MinVersion: tls.VersionTLS12}
+
+		v := New(WithTLSConfig(tlsConfig), WithClientCertificates(cert))
+
+		transport, ok := v.client.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.Equal(t, "test", transport.TLSClientConfig.ServerName)
+		require.Equal(t, []tls.Certificate{cert}, transport.TLSClientConfig.Certificates)
+		require.Empty(t, tlsConfig.Certificates)
+	})
+
+	t.Run("test client certificates without tls config", func(t *testing.T) {
+		v := New(WithClientCertificates(cert))
+
+		transport, ok := v.client.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+		require.Equal(t, []tls.Certificate{cert}, transport.TLSClientConfig.Certificates)
+	})
+
+	t.Run("test tls options", func(t *testing.T) {
+		v := New(WithClientCertificates(cert), WithTLSOptions(tlsconfig.WithMinVersion(tls.VersionTLS13),
+			tlsconfig.WithCipherSuites(tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256)))
+
+		tlsConfig := v.transportTLSConfig()
+		require.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+		require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+		require.Equal(t, []tls.Certificate{cert}, tlsConfig.Certificates)
+	})
+}
+
+func TestClient_ConnectionPool(t *testing.T) {
+	t.Run("test options", func(t *testing.T) {
+		v := New(WithMaxIdleConns(5), WithIdleConnTimeout(time.Minute), WithKeepAlive(-1))
+
+		transport, ok := v.client.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.Equal(t, 5, transport.MaxIdleConns)
+		require.Equal(t, 5, transport.MaxIdleConnsPerHost)
+		require.Equal(t, time.Minute, transport.IdleConnTimeout)
+	})
+
+	t.Run("test connections reused until closed", func(t *testing.T) {
+		var connections int32
+
+		serv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		serv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&connections, 1)
+			}
+		}
+		serv.Start()
+
+		defer serv.Close()
+
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		v := New()
+
+		for i := 0; i < 3; i++ {
+			err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+				deactivate.WithSigningKey(privateKey))
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&connections))
+
+		require.NoError(t, v.Close())
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privateKey))
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&connections))
+	})
+}
+
+func TestClient_WithProxyURL(t *testing.T) {
+	var requestURI string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v := New(WithProxyURL(proxyURL))
+
+	err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint("http://sidetree.example.com"),
+		deactivate.WithSigningKey(privateKey))
+	require.NoError(t, err)
+	require.Equal(t, "http://sidetree.example.com/operations", requestURI)
+}
+
+func TestClient_CompressedResponse(t *testing.T) {
+	var acceptEncoding string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Encoding", "gzip")
+
+		gw := gzip.NewWriter(w)
+		_, err := gw.Write([]byte(`{"id": "did:trustbloc:testnet:123"}`))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+	}))
+	defer serv.Close()
+
+	v := New()
+
+	resp, err := v.postOperation(context.Background(), []byte("{}"), serv.URL, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"id": "did:trustbloc:testnet:123"}`, string(resp))
+	require.Equal(t, "gzip, deflate", acceptEncoding)
+}
+
+func TestClient_WithAllowedNetworks(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	_, private, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test allowed network", func(t *testing.T) {
+		v := New(WithAllowedNetworks(private, loopback), WithDialTimeout(time.Second))
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privateKey))
+		require.NoError(t, err)
+	})
+
+	t.Run("test network not allowed", func(t *testing.T) {
+		v := New(WithAllowedNetworks(private), WithResolver(&net.Resolver{}))
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privateKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not in the allowed networks")
+	})
+}
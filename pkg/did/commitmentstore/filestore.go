@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitmentstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const filePermissions = 0600
+
+// FileStore is a Store persisting the commitments of the DIDs to a JSON file. The file holds public keys and
+// commitments only, no private keys.
+type FileStore struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// NewFileStore returns a Store persisting the commitments to the JSON file with the path. The file is created by
+// the first Put if it doesn't exist.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Put stores the commitments of their DID, replacing the previous ones
+func (s *FileStore) Put(commitments *Commitments) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	all[commitments.DID] = commitments
+
+	return s.write(all)
+}
+
+// Get returns the commitments of the DID, or ErrNotFound
+func (s *FileStore) Get(did string) (*Commitments, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	commitments, ok := all[did]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return commitments, nil
+}
+
+// Delete deletes the commitments of the DID
+func (s *FileStore) Delete(did string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := all[did]; !ok {
+		return nil
+	}
+
+	delete(all, did)
+
+	return s.write(all)
+}
+
+func (s *FileStore) read() (map[string]*Commitments, error) {
+	all := make(map[string]*Commitments)
+
+	data, err := ioutil.ReadFile(filepath.Clean(s.path))
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commitment store '%s': %w", s.path, err)
+	}
+
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse commitment store '%s': %w", s.path, err)
+	}
+
+	return all, nil
+}
+
+func (s *FileStore) write(all map[string]*Commitments) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal commitment store: %w", err)
+	}
+
+	// write to a temporary file first, so that the store isn't lost if writing fails
+	tmp := s.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, data, filePermissions); err != nil {
+		return fmt.Errorf("failed to write commitment store '%s': %w", s.path, err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write commitment store '%s': %w", s.path, err)
+	}
+
+	return nil
+}
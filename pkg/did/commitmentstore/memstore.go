@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitmentstore
+
+import (
+	"sync"
+)
+
+// MemStore is an in-memory Store
+type MemStore struct {
+	mutex       sync.RWMutex
+	commitments map[string]*Commitments
+}
+
+// NewMemStore returns an empty in-memory Store
+func NewMemStore() *MemStore {
+	return &MemStore{commitments: make(map[string]*Commitments)}
+}
+
+// Put stores the commitments of their DID, replacing the previous ones
+func (s *MemStore) Put(commitments *Commitments) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := *commitments
+	s.commitments[commitments.DID] = &c
+
+	return nil
+}
+
+// Get returns the commitments of the DID, or ErrNotFound
+func (s *MemStore) Get(did string) (*Commitments, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	commitments, ok := s.commitments[did]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	c := *commitments
+
+	return &c, nil
+}
+
+// Delete deletes the commitments of the DID
+func (s *MemStore) Delete(did string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.commitments, did)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package commitmentstore stores the update and recovery keys the DIDs committed to in their last operations, so
+// that the keys to reveal in the next update, recovery or deactivation can be located.
+package commitmentstore
+
+import (
+	"errors"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+// ErrNotFound is returned when the store has no commitments of the DID
+var ErrNotFound = errors.New("commitments not found")
+
+// Commitments are the commitments of a DID to its next update and recovery keys, with the public keys committed to.
+// The update key is the key whose private key signs the next update, and the recovery key the key whose private key
+// signs the next recovery or deactivation.
+type Commitments struct {
+	DID                string   `json:"did"`
+	MultihashAlgorithm uint     `json:"multihashAlgorithm"`
+	UpdateCommitment   string   `json:"updateCommitment,omitempty"`
+	UpdateKey          *jws.JWK `json:"updateKey,omitempty"`
+	RecoveryCommitment string   `json:"recoveryCommitment,omitempty"`
+	RecoveryKey        *jws.JWK `json:"recoveryKey,omitempty"`
+	// PreviousCommitments are the commitments replaced by the operations, whose keys were revealed, oldest first
+	PreviousCommitments []string `json:"previousCommitments,omitempty"`
+}
+
+// Store stores the commitments of DIDs
+type Store interface {
+	// Put stores the commitments of their DID, replacing the previous ones
+	Put(commitments *Commitments) error
+	// Get returns the commitments of the DID, or ErrNotFound
+	Get(did string) (*Commitments, error)
+	// Delete deletes the commitments of the DID. It doesn't fail if there are none.
+	Delete(did string) error
+}
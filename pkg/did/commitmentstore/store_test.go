@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commitmentstore
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+func TestStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "commitmentstore")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "commitments.json")
+
+	for name, store := range map[string]Store{"memory": NewMemStore(), "file": NewFileStore(path)} {
+		store := store
+
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Get("did:ex:123")
+			require.True(t, errors.Is(err, ErrNotFound))
+
+			commitments := &Commitments{DID: "did:ex:123", MultihashAlgorithm: 18, UpdateCommitment: "c1",
+				UpdateKey: &jws.JWK{Kty: "OKP", Crv: "Ed25519", X: "x1"}, RecoveryCommitment: "c2",
+				RecoveryKey: &jws.JWK{Kty: "OKP", Crv: "Ed25519", X: "x2"}}
+
+			require.NoError(t, store.Put(commitments))
+			require.NoError(t, store.Put(&Commitments{DID: "did:ex:456", UpdateCommitment: "c3"}))
+
+			stored, err := store.Get("did:ex:123")
+			require.NoError(t, err)
+			require.Equal(t, commitments, stored)
+
+			stored.UpdateCommitment = "c4"
+			require.NoError(t, store.Put(stored))
+
+			stored, err = store.Get("did:ex:123")
+			require.NoError(t, err)
+			require.Equal(t, "c4", stored.UpdateCommitment)
+
+			require.NoError(t, store.Delete("did:ex:123"))
+			require.NoError(t, store.Delete("did:ex:123"))
+
+			_, err = store.Get("did:ex:123")
+			require.True(t, errors.Is(err, ErrNotFound))
+
+			stored, err = store.Get("did:ex:456")
+			require.NoError(t, err)
+			require.Equal(t, "c3", stored.UpdateCommitment)
+		})
+	}
+
+	t.Run("test file store persisted", func(t *testing.T) {
+		stored, err := NewFileStore(path).Get("did:ex:456")
+		require.NoError(t, err)
+		require.Equal(t, "c3", stored.UpdateCommitment)
+	})
+
+	t.Run("test invalid file", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.json")
+		require.NoError(t, ioutil.WriteFile(invalid, []byte("{"), 0600))
+
+		store := NewFileStore(invalid)
+
+		_, err := store.Get("did:ex:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse commitment store")
+
+		err = store.Put(&Commitments{DID: "did:ex:123"})
+		require.Error(t, err)
+
+		err = store.Delete("did:ex:123")
+		require.Error(t, err)
+	})
+
+	t.Run("test write error", func(t *testing.T) {
+		err := NewFileStore(filepath.Join(dir, "missing", "commitments.json")).Put(&Commitments{DID: "did:ex:123"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to write commitment store")
+	})
+}
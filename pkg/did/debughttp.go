@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// redactedHeaders are the headers whose values are replaced in the debug HTTP dumps, as they hold credentials
+var redactedHeaders = map[string]bool{ //nolint: gochecknoglobals
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// dumpRequest logs the sidetree request if the client was created with WithDebugHTTP
+func (c *Client) dumpRequest(req *http.Request, body []byte) {
+	if !c.debugHTTP {
+		return
+	}
+
+	c.logger.Info("sidetree http request", "method", req.Method, "url", req.URL.String(),
+		"headers", sanitizeHeaders(req.Header), "body", prettyBody(body))
+}
+
+// dumpResponse logs the response to the sidetree request if the client was created with WithDebugHTTP
+func (c *Client) dumpResponse(req *http.Request, resp *http.Response, body []byte) {
+	if !c.debugHTTP {
+		return
+	}
+
+	c.logger.Info("sidetree http response", "method", req.Method, "url", req.URL.String(),
+		"status", resp.StatusCode, "headers", sanitizeHeaders(resp.Header), "body", prettyBody(body))
+}
+
+// sanitizeHeaders returns the values of the headers, with the values of the headers holding credentials redacted
+func sanitizeHeaders(header http.Header) map[string]string {
+	sanitized := make(map[string]string, len(header))
+
+	for name, values := range header {
+		sanitized[name] = strings.Join(values, ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			sanitized[name] = redacted
+		}
+	}
+
+	return sanitized
+}
+
+// prettyBody returns the body indented if it is JSON, or else as is
+func prettyBody(body []byte) string {
+	var indented bytes.Buffer
+
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return string(body)
+	}
+
+	return indented.String()
+}
@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package didweb converts the documents of did:trustbloc DIDs to the equivalent did:web documents, so that the DIDs
+// are also published as did.json files for the consumers which only support did:web.
+package didweb
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+)
+
+const didJSON = "did.json"
+
+// Publisher publishes the did:web documents
+type Publisher interface {
+	// Publish publishes the did.json of the domain path, replacing the previous one
+	Publish(domainPath string, didJSON []byte) error
+}
+
+// ID returns the did:web DID of the domain path, such as did:web:example.com:users:alice for example.com/users/alice.
+// The port of the domain is percent-encoded.
+func ID(domainPath string) string {
+	segments := splitDomainPath(domainPath)
+	segments[0] = strings.ReplaceAll(segments[0], ":", "%3A")
+
+	return "did:web:" + strings.Join(segments, ":")
+}
+
+// FilePath returns the path of the did.json of the domain path relative to the web root of the domain, such as
+// users/alice/did.json for example.com/users/alice, or .well-known/did.json for a domain without path.
+func FilePath(domainPath string) string {
+	segments := splitDomainPath(domainPath)
+	if len(segments) == 1 {
+		return path.Join(".well-known", didJSON)
+	}
+
+	return path.Join(append(segments[1:], didJSON)...)
+}
+
+// Document returns the did.json of the domain path equivalent to the DID document: the DID and the IDs and
+// controllers relative to it are replaced with the did:web DID, and the DID is kept as alsoKnownAs.
+func Document(didDoc *docdid.Doc, domainPath string) ([]byte, error) {
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DID document: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(docBytes, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DID document: %w", err)
+	}
+
+	// the document is replaced in place
+	replaceDID(raw, didDoc.ID, ID(domainPath))
+
+	raw["alsoKnownAs"] = []string{didDoc.ID}
+
+	webDocBytes, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal did:web document: %w", err)
+	}
+
+	return webDocBytes, nil
+}
+
+// replaceDID replaces the DID with the did:web DID in the strings of the JSON value which are the DID or a DID URL
+// with a fragment, leaving the longer DIDs which start with the DID, such as its long form, unchanged. The arrays and
+// objects are replaced in place.
+func replaceDID(value interface{}, did, webDID string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if v == did || strings.HasPrefix(v, did+"#") {
+			return webDID + strings.TrimPrefix(v, did)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = replaceDID(v[i], did, webDID)
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = replaceDID(v[k], did, webDID)
+		}
+	}
+
+	return value
+}
+
+func splitDomainPath(domainPath string) []string {
+	return strings.Split(strings.Trim(domainPath, "/"), "/")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didweb
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+)
+
+func TestID(t *testing.T) {
+	require.Equal(t, "did:web:example.com", ID("example.com"))
+	require.Equal(t, "did:web:example.com:users:alice", ID("example.com/users/alice/"))
+	require.Equal(t, "did:web:example.com%3A8443:users", ID("example.com:8443/users"))
+}
+
+func TestFilePath(t *testing.T) {
+	require.Equal(t, ".well-known/did.json", FilePath("example.com"))
+	require.Equal(t, "users/alice/did.json", FilePath("example.com/users/alice"))
+}
+
+func TestDocument(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	didDoc := &docdid.Doc{Context: []string{docdid.ContextV1}, ID: "did:trustbloc:testnet:123",
+		VerificationMethod: []docdid.VerificationMethod{{ID: "did:trustbloc:testnet:123#key1",
+			Type: "Ed25519VerificationKey2018", Controller: "did:trustbloc:testnet:123", Value: pubKey}},
+		Service: []docdid.Service{{ID: "did:trustbloc:testnet:123#svc1", Type: "type",
+			ServiceEndpoint: "did:trustbloc:testnet:1234"}}}
+
+	webDoc, err := Document(didDoc, "example.com/users/alice")
+	require.NoError(t, err)
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(webDoc, &raw))
+	require.Equal(t, []interface{}{"did:trustbloc:testnet:123"}, raw["alsoKnownAs"])
+
+	parsed, err := docdid.ParseDocument(webDoc)
+	require.NoError(t, err)
+	require.Equal(t, "did:web:example.com:users:alice", parsed.ID)
+	require.Equal(t, "did:web:example.com:users:alice#key1", parsed.VerificationMethod[0].ID)
+	require.Equal(t, "did:web:example.com:users:alice", parsed.VerificationMethod[0].Controller)
+	require.Equal(t, "did:web:example.com:users:alice#svc1", parsed.Service[0].ID)
+	require.Equal(t, "did:trustbloc:testnet:1234", parsed.Service[0].ServiceEndpoint)
+}
+
+func TestFilePublisher(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "didweb")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(rootDir)) }()
+
+	p := NewFilePublisher(rootDir)
+
+	var data []byte
+
+	t.Run("test publish", func(t *testing.T) {
+		require.NoError(t, p.Publish("example.com/users/alice", []byte(`{"id": "did:web:example.com:users:alice"}`)))
+		require.NoError(t, p.Publish("example.com/users/alice", []byte(`{"service": []}`)))
+		require.NoError(t, p.Publish("example.com", []byte(`{"id": "did:web:example.com"}`)))
+
+		data, err = ioutil.ReadFile(filepath.Join(rootDir, "users", "alice", "did.json"))
+		require.NoError(t, err)
+		require.Equal(t, `{"service": []}`, string(data))
+		require.FileExists(t, filepath.Join(rootDir, ".well-known", "did.json"))
+	})
+
+	t.Run("test write error", func(t *testing.T) {
+		file := filepath.Join(rootDir, "file")
+		require.NoError(t, ioutil.WriteFile(file, nil, filePermissions))
+
+		err = NewFilePublisher(file).Publish("example.com/users", []byte("{}"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create directory")
+	})
+}
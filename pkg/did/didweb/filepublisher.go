@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didweb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	dirPermissions  = 0755
+	filePermissions = 0644
+)
+
+// FilePublisher is a Publisher writing the did.json files under the web root directory of the domain, from which a
+// web server serves them
+type FilePublisher struct {
+	rootDir string
+}
+
+// NewFilePublisher returns a Publisher writing the did.json files under the web root directory
+func NewFilePublisher(rootDir string) *FilePublisher {
+	return &FilePublisher{rootDir: rootDir}
+}
+
+// Publish writes the did.json of the domain path, such as <root>/users/alice/did.json for example.com/users/alice
+func (p *FilePublisher) Publish(domainPath string, didJSON []byte) error {
+	file := filepath.Join(p.rootDir, filepath.FromSlash(FilePath(domainPath)))
+
+	if err := os.MkdirAll(filepath.Dir(file), dirPermissions); err != nil {
+		return fmt.Errorf("failed to create directory of '%s': %w", file, err)
+	}
+
+	// write to a temporary file first, so that the web server never serves a partial document
+	tmp := file + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, didJSON, filePermissions); err != nil { //nolint: gosec
+		return fmt.Errorf("failed to write '%s': %w", file, err)
+	}
+
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", file, err)
+	}
+
+	return nil
+}
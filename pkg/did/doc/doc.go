@@ -11,6 +11,7 @@ import (
 	"crypto/elliptic"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/square/go-jose/v3"
@@ -56,6 +57,10 @@ const (
 	P256KeyType = "P256"
 )
 
+// keyPurposes are the purposes of the public keys the resolver supports
+var keyPurposes = []string{KeyPurposeAuthentication, KeyPurposeAssertionMethod, //nolint: gochecknoglobals
+	KeyPurposeKeyAgreement, KeyPurposeCapabilityDelegation, KeyPurposeCapabilityInvocation}
+
 type rawDoc struct {
 	PublicKey []map[string]interface{} `json:"publicKey,omitempty"`
 	Service   []map[string]interface{} `json:"service,omitempty"`
@@ -98,6 +103,70 @@ func (doc *Doc) JSONBytes() ([]byte, error) {
 	return byteDoc, nil
 }
 
+// ValidatePurposes checks that the purposes of the public keys are supported, as the resolver ignores the purposes
+// it doesn't support, such as misspelled ones
+func ValidatePurposes(pks []PublicKey) error {
+	for i := range pks {
+		for _, purpose := range pks[i].Purposes {
+			if !isKeyPurpose(purpose) {
+				return fmt.Errorf("invalid purpose '%s' of public key %s, valid purposes are %s", purpose, pks[i].ID,
+					strings.Join(keyPurposes, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateIDs checks that no two public keys and no two services have the same ID, rather than leaving it to the
+// sidetree node to reject the operation or to keep one of them
+func ValidateIDs(pks []PublicKey, services []docdid.Service) error {
+	keyIDs := make([]string, len(pks))
+	for i := range pks {
+		keyIDs[i] = pks[i].ID
+	}
+
+	if id, ok := DuplicateID(keyIDs); ok {
+		return fmt.Errorf("duplicate public key ID %s", id)
+	}
+
+	serviceIDs := make([]string, len(services))
+	for i := range services {
+		serviceIDs[i] = services[i].ID
+	}
+
+	if id, ok := DuplicateID(serviceIDs); ok {
+		return fmt.Errorf("duplicate service ID %s", id)
+	}
+
+	return nil
+}
+
+// DuplicateID returns the first ID which appears twice in the IDs
+func DuplicateID(ids []string) (string, bool) {
+	seen := make(map[string]bool, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			return id, true
+		}
+
+		seen[id] = true
+	}
+
+	return "", false
+}
+
+func isKeyPurpose(purpose string) bool {
+	for _, p := range keyPurposes {
+		if p == purpose {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetValueFromJWK Populate the PublicKey contents from a JSON Web Key
 func (pk *PublicKey) GetValueFromJWK(jwk *jose.JSONWebKey) error {
 	if edKey, ok := jwk.Key.(ed25519.PublicKey); ok {
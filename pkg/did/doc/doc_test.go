@@ -60,6 +60,38 @@ func TestDoc_JSONBytes(t *testing.T) {
 	})
 }
 
+func TestValidatePurposes(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ValidatePurposes([]PublicKey{{ID: "key1"}, {ID: "key2", Purposes: []string{
+			KeyPurposeAuthentication, KeyPurposeAssertionMethod, KeyPurposeKeyAgreement,
+			KeyPurposeCapabilityDelegation, KeyPurposeCapabilityInvocation}}}))
+	})
+
+	t.Run("error invalid purpose", func(t *testing.T) {
+		err := ValidatePurposes([]PublicKey{{ID: "key1", Purposes: []string{KeyPurposeAuthentication}},
+			{ID: "key2", Purposes: []string{"authenication"}}})
+		require.EqualError(t, err, "invalid purpose 'authenication' of public key key2, valid purposes are "+
+			"authentication, assertionMethod, keyAgreement, capabilityDelegation, capabilityInvocation")
+	})
+}
+
+func TestValidateIDs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ValidateIDs([]PublicKey{{ID: "key1"}, {ID: "key2"}},
+			[]ariesdid.Service{{ID: "key1"}, {ID: "svc1"}}))
+	})
+
+	t.Run("error duplicate public key ID", func(t *testing.T) {
+		err := ValidateIDs([]PublicKey{{ID: "key1"}, {ID: "key2"}, {ID: "key1"}}, nil)
+		require.EqualError(t, err, "duplicate public key ID key1")
+	})
+
+	t.Run("error duplicate service ID", func(t *testing.T) {
+		err := ValidateIDs(nil, []ariesdid.Service{{ID: "svc1"}, {ID: "svc1"}})
+		require.EqualError(t, err, "duplicate service ID svc1")
+	})
+}
+
 func TestPublicKey_GetValueFromJWK(t *testing.T) {
 	t.Run("success - ed25519 value", func(t *testing.T) {
 		keyJSON := `{
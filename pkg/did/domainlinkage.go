@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	didConfigurationContext = "https://identity.foundation/.well-known/did-configuration/v1"
+	credentialsContext      = "https://www.w3.org/2018/credentials/v1"
+)
+
+// DIDConfiguration is the did-configuration.json served by a web origin at /.well-known/did-configuration.json,
+// linking DIDs to the origin with domain linkage credentials in the JWT format.
+// Implements https://identity.foundation/.well-known/resources/did-configuration/
+type DIDConfiguration struct {
+	Context    string   `json:"@context"`
+	LinkedDIDs []string `json:"linked_dids"`
+}
+
+type domainLinkageClaims struct {
+	Issuer    string                   `json:"iss"`
+	Subject   string                   `json:"sub"`
+	NotBefore int64                    `json:"nbf"`
+	Expiry    int64                    `json:"exp"`
+	VC        *domainLinkageCredential `json:"vc"`
+}
+
+type domainLinkageCredential struct {
+	Context           []string          `json:"@context"`
+	Type              []string          `json:"type"`
+	Issuer            string            `json:"issuer"`
+	IssuanceDate      string            `json:"issuanceDate"`
+	ExpirationDate    string            `json:"expirationDate"`
+	CredentialSubject map[string]string `json:"credentialSubject"`
+}
+
+// NewDIDConfiguration returns the DID configuration of the linked DIDs, which are domain linkage credentials such as
+// those of CreateDomainLinkageCredential
+func NewDIDConfiguration(linkedDIDs ...string) *DIDConfiguration {
+	return &DIDConfiguration{Context: didConfigurationContext, LinkedDIDs: linkedDIDs}
+}
+
+// CreateDomainLinkageCredential returns the JWT of a DomainLinkageCredential binding the DID to the web origin, such
+// as https://example.com, valid from issued until expires. It's signed with the private key of the verification
+// method of the DID with the key ID, which may be any of the signing keys of the operations, such as a KMSSigner.
+func CreateDomainLinkageCredential(did, origin, keyID string, signingKey crypto.PrivateKey, issued,
+	expires time.Time) (string, error) {
+	if !strings.Contains(keyID, "#") {
+		keyID = did + "#" + keyID
+	}
+
+	signer, _, err := newSigner(signingKey, keyID)
+	if err != nil {
+		return "", err
+	}
+
+	headers := signer.Headers()
+	headers["typ"] = "JWT"
+
+	claims := &domainLinkageClaims{Issuer: did, Subject: did, NotBefore: issued.Unix(), Expiry: expires.Unix(),
+		VC: &domainLinkageCredential{
+			Context:           []string{credentialsContext, didConfigurationContext},
+			Type:              []string{"VerifiableCredential", "DomainLinkageCredential"},
+			Issuer:            did,
+			IssuanceDate:      issued.UTC().Format(time.RFC3339),
+			ExpirationDate:    expires.UTC().Format(time.RFC3339),
+			CredentialSubject: map[string]string{"id": did, "origin": origin},
+		}}
+
+	headersBytes, err := json.Marshal(headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT headers: %w", err)
+	}
+
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headersBytes) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsBytes)
+
+	signature, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign domain linkage credential: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
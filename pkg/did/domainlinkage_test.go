@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDomainLinkageCredential(t *testing.T) {
+	edPubKey, edPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecPrivKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issued := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	expires := issued.AddDate(1, 0, 0)
+
+	var (
+		jwt     string
+		signed  *jose.JSONWebSignature
+		payload []byte
+	)
+
+	for _, key := range []struct {
+		name       string
+		privateKey interface{}
+		publicKey  interface{}
+	}{
+		{"Ed25519", edPrivKey, edPubKey},
+		{"P-256", ecPrivKey, &ecPrivKey.PublicKey},
+	} {
+		key := key
+
+		t.Run("test "+key.name+" key", func(t *testing.T) {
+			jwt, err = CreateDomainLinkageCredential("did:ex:123", "https://example.com", "key1",
+				key.privateKey, issued, expires)
+			require.NoError(t, err)
+
+			signed, err = jose.ParseSigned(jwt)
+			require.NoError(t, err)
+			require.Equal(t, "did:ex:123#key1", signed.Signatures[0].Header.KeyID)
+
+			payload, err = signed.Verify(key.publicKey)
+			require.NoError(t, err)
+
+			var claims domainLinkageClaims
+			require.NoError(t, json.Unmarshal(payload, &claims))
+			require.Equal(t, "did:ex:123", claims.Issuer)
+			require.Equal(t, expires.Unix(), claims.Expiry)
+			require.Equal(t, []string{"VerifiableCredential", "DomainLinkageCredential"}, claims.VC.Type)
+			require.Equal(t, "2021-11-01T00:00:00Z", claims.VC.ExpirationDate)
+			require.Equal(t, map[string]string{"id": "did:ex:123", "origin": "https://example.com"},
+				claims.VC.CredentialSubject)
+		})
+	}
+
+	t.Run("test unsupported key", func(t *testing.T) {
+		_, err = CreateDomainLinkageCredential("did:ex:123", "https://example.com", "did:ex:123#key1", rsaPrivKey,
+			issued, expires)
+		require.True(t, errors.Is(err, ErrKeyNotSupported))
+	})
+}
+
+func TestNewDIDConfiguration(t *testing.T) {
+	conf := NewDIDConfiguration("jwt1", "jwt2")
+
+	confBytes, err := json.Marshal(conf)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"@context": "https://identity.foundation/.well-known/did-configuration/v1",
+		"linked_dids": ["jwt1", "jwt2"]}`, string(confBytes))
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDomainEmpty is returned by the operations given neither a domain nor sidetree endpoints to send their
+	// request to
+	ErrDomainEmpty = errors.New("domain is empty and sidetree endpoints is empty")
+
+	// ErrEndpointsEmpty is returned by the operations when the discovery of the sidetree endpoints of the domain
+	// returns none
+	ErrEndpointsEmpty = errors.New("list of endpoints is empty")
+
+	// ErrKeyNotSupported is returned for the keys the client can't sign the requests or compute the commitments with,
+	// such as RSA keys or ECDSA keys on another curve than P-256
+	ErrKeyNotSupported = errors.New("key not supported")
+
+	// ErrLimitExceeded is returned by the operations whose request exceeds a limit of the sidetree config of the
+	// endpoint, such as the maximum operation size or the maximum length of the IDs
+	ErrLimitExceeded = errors.New("sidetree limit exceeded")
+
+	// ErrDocumentMismatch is returned by CreateDID, along with the created document, when the verification of the
+	// anchored document finds other public keys or services than the requested ones
+	ErrDocumentMismatch = errors.New("anchored document doesn't match the requested document")
+
+	// ErrWrongUpdateKey is returned by UpdateDID when the signing key isn't the key which the active update
+	// commitment of the DID commits to
+	ErrWrongUpdateKey = errors.New("wrong update key")
+
+	// ErrWrongRecoveryKey is returned by RecoverDID when the signing key isn't the key which the active recovery
+	// commitment of the DID commits to
+	ErrWrongRecoveryKey = errors.New("wrong recovery key")
+
+	// ErrIncompatibleEndpoint is returned by the operations of a client created with WithProtocolCheck when the
+	// sidetree endpoint doesn't implement the sidetree protocol version of the client
+	ErrIncompatibleEndpoint = errors.New("incompatible sidetree endpoint")
+
+	// ErrNoDIDWebPublisher is returned by the operations given a did:web domain path by a client created without
+	// WithDIDWebPublisher
+	ErrNoDIDWebPublisher = errors.New("no did:web publisher")
+
+	// ErrOperationInProgress is returned by the operations given the idempotency key of an operation which is still
+	// in progress
+	ErrOperationInProgress = errors.New("operation with the same idempotency key in progress")
+
+	// ErrIdempotencyKeyReused is returned by the operations given the idempotency key of another operation or of an
+	// operation on another DID
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused for another operation")
+)
+
+// maxErrorBodyLength is the maximum length of the response body kept in ErrSidetreeRequestFailed, as the body of
+// an error response can be as large as the HTML page of a proxy
+const maxErrorBodyLength = 1024
+
+// ErrSidetreeRequestFailed is returned when a sidetree endpoint responds to a request with an unexpected status,
+// such as a request rejected by the endpoint or a rate limited request. Callers get it with errors.As.
+type ErrSidetreeRequestFailed struct {
+	// URL is the URL of the sidetree endpoint
+	URL string
+	// Status is the HTTP status of the response
+	Status int
+	// Body is the body of the response, truncated to its first 1024 bytes
+	Body []byte
+	// Truncated is whether the body of the response was truncated
+	Truncated bool
+}
+
+func newSidetreeRequestError(endpointURL string, status int, body []byte) *ErrSidetreeRequestFailed {
+	if len(body) <= maxErrorBodyLength {
+		return &ErrSidetreeRequestFailed{URL: endpointURL, Status: status, Body: body}
+	}
+
+	// copied so that the error doesn't hold the whole body
+	return &ErrSidetreeRequestFailed{URL: endpointURL, Status: status,
+		Body: append([]byte(nil), body[:maxErrorBodyLength]...), Truncated: true}
+}
+
+func (e *ErrSidetreeRequestFailed) Error() string {
+	msg := fmt.Sprintf("got unexpected response from %s status '%d' body %s", e.URL, e.Status, e.Body)
+	if e.Truncated {
+		msg += "... (truncated)"
+	}
+
+	return msg
+}
@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrSidetreeRequestFailed(t *testing.T) {
+	t.Run("test body", func(t *testing.T) {
+		err := newSidetreeRequestError("https://example.com/sidetree", http.StatusBadRequest,
+			[]byte(`{"code":"invalid-delta"}`))
+		require.False(t, err.Truncated)
+		require.EqualError(t, err, `got unexpected response from https://example.com/sidetree status '400' `+
+			`body {"code":"invalid-delta"}`)
+	})
+
+	t.Run("test truncated body", func(t *testing.T) {
+		body := []byte(strings.Repeat("a", maxErrorBodyLength) + "b")
+
+		err := newSidetreeRequestError("https://example.com/sidetree", http.StatusBadGateway, body)
+		require.True(t, err.Truncated)
+		require.Len(t, err.Body, maxErrorBodyLength)
+		require.Equal(t, "got unexpected response from https://example.com/sidetree status '502' body "+
+			strings.Repeat("a", maxErrorBodyLength)+"... (truncated)", err.Error())
+
+		body[0] = 'b'
+		require.Equal(t, byte('a'), err.Body[0])
+	})
+}
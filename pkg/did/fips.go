@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	multihashSHA2256 = 18
+	multihashSHA2512 = 19
+)
+
+// ErrNotFIPSApproved is returned by the operations of a client created with WithFIPSMode, when a key or the multihash
+// algorithm of the sidetree endpoint is not FIPS-approved
+var ErrNotFIPSApproved = errors.New("not a FIPS-approved algorithm")
+
+// checkFIPS checks, in FIPS mode, that the multihash algorithm is SHA-2 and that the keys, which are public keys,
+// private keys or signers, are P-256 keys
+func (c *Client) checkFIPS(multihashCode uint, keys ...interface{}) error {
+	if !c.fipsMode {
+		return nil
+	}
+
+	if multihashCode != multihashSHA2256 && multihashCode != multihashSHA2512 {
+		return fmt.Errorf("multihash algorithm %d: %w", multihashCode, ErrNotFIPSApproved)
+	}
+
+	for _, key := range keys {
+		if err := checkFIPSKey(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkFIPSDocKeys checks, in FIPS mode, that the public keys of the DID document are P-256 keys
+func (c *Client) checkFIPSDocKeys(keys []doc.PublicKey) error {
+	if !c.fipsMode {
+		return nil
+	}
+
+	for i := range keys {
+		if keys[i].KeyType != doc.P256KeyType || keys[i].Type == doc.Ed25519VerificationKey2018 {
+			return fmt.Errorf("key type %s of public key %s: %w", keys[i].KeyType, keys[i].ID, ErrNotFIPSApproved)
+		}
+	}
+
+	return nil
+}
+
+func checkFIPSKey(key interface{}) error {
+	switch k := key.(type) {
+	case nil:
+		return nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return fmt.Errorf("curve %s: %w", k.Curve.Params().Name, ErrNotFIPSApproved)
+		}
+
+		return nil
+	case *ecdsa.PrivateKey:
+		return checkFIPSKey(&k.PublicKey)
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return fmt.Errorf("ed25519: %w", ErrNotFIPSApproved)
+	case *KMSSigner:
+		return checkFIPSKey(k.Public())
+	case crypto.Signer:
+		return checkFIPSKey(k.Public())
+	default:
+		return fmt.Errorf("key type %T: %w", key, ErrNotFIPSApproved)
+	}
+}
+
+func (c *Client) checkFIPSCreate(config *models.SidetreeConfig, opts *create.Opts) error {
+	if err := c.checkFIPS(config.MultiHashAlgorithm, opts.RecoveryPublicKey, opts.UpdatePublicKey); err != nil {
+		return err
+	}
+
+	return c.checkFIPSDocKeys(opts.PublicKeys)
+}
+
+func (c *Client) checkFIPSUpdate(config *models.SidetreeConfig, opts *update.Opts) error {
+	if err := c.checkFIPS(config.MultiHashAlgorithm, opts.SigningKey, opts.NextUpdatePublicKey); err != nil {
+		return err
+	}
+
+	return c.checkFIPSDocKeys(opts.AddPublicKeys)
+}
+
+func (c *Client) checkFIPSRecover(config *models.SidetreeConfig, opts *recovery.Opts) error {
+	if err := c.checkFIPS(config.MultiHashAlgorithm, opts.SigningKey, opts.NextRecoveryPublicKey,
+		opts.NextUpdatePublicKey); err != nil {
+		return err
+	}
+
+	return c.checkFIPSDocKeys(opts.PublicKeys)
+}
+
+func (c *Client) checkFIPSDeactivate(opts *deactivate.Opts) error {
+	if !c.fipsMode {
+		return nil
+	}
+
+	return checkFIPSKey(opts.SigningKey)
+}
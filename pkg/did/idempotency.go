@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+const defaultIdempotencyKeyTTL = 24 * time.Hour
+
+// idempotentOperations are the operations run with an idempotency key, the successful ones being kept for the TTL so
+// that a retried operation returns the result of the first one instead of submitting the operation again
+type idempotentOperations struct {
+	ttl        time.Duration
+	mutex      sync.Mutex
+	operations map[string]*idempotentOperation
+	now        func() time.Time
+}
+
+type idempotentOperation struct {
+	operation string
+	did       string
+	done      bool
+	finished  time.Time
+	didDoc    *docdid.Doc
+}
+
+func newIdempotentOperations(ttl time.Duration) *idempotentOperations {
+	return &idempotentOperations{ttl: ttl, operations: make(map[string]*idempotentOperation), now: time.Now}
+}
+
+// run runs the operation on the DID, the DID being empty for a creation, unless an operation with the idempotency
+// key succeeded within the TTL, in which case it returns the document that operation returned. An operation which
+// failed is forgotten, so that it can be retried with the same key.
+func (o *idempotentOperations) run(key, operation, did string,
+	run func() (*docdid.Doc, error)) (*docdid.Doc, error) {
+	if key == "" {
+		return run()
+	}
+
+	op, err := o.start(key, operation, did)
+	if err != nil || op.done {
+		return op.didDoc, err
+	}
+
+	didDoc, err := run()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if err != nil {
+		delete(o.operations, key)
+
+		return didDoc, err
+	}
+
+	op.done = true
+	op.finished = o.now()
+	op.didDoc = didDoc
+
+	return didDoc, nil
+}
+
+// start returns the successful operation with the key, or else records the operation as in progress
+func (o *idempotentOperations) start(key, operation, did string) (*idempotentOperation, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	now := o.now()
+
+	for k, op := range o.operations {
+		if op.done && now.Sub(op.finished) >= o.ttl {
+			delete(o.operations, k)
+		}
+	}
+
+	op, ok := o.operations[key]
+	if !ok {
+		op = &idempotentOperation{operation: operation, did: did}
+		o.operations[key] = op
+
+		return op, nil
+	}
+
+	if op.operation != operation || op.did != did {
+		return &idempotentOperation{}, fmt.Errorf("%w: %s", ErrIdempotencyKeyReused, key)
+	}
+
+	if !op.done {
+		return &idempotentOperation{}, fmt.Errorf("%w: %s", ErrOperationInProgress, key)
+	}
+
+	return op, nil
+}
+
+func createIdempotencyKey(opts []create.Option) string {
+	createDIDOpts := &create.Opts{}
+	for _, opt := range opts {
+		opt(createDIDOpts)
+	}
+
+	return createDIDOpts.IdempotencyKey
+}
+
+func updateIdempotencyKey(opts []update.Option) string {
+	updateDIDOpts := &update.Opts{}
+	for _, opt := range opts {
+		opt(updateDIDOpts)
+	}
+
+	return updateDIDOpts.IdempotencyKey
+}
+
+func recoverIdempotencyKey(opts []recovery.Option) string {
+	recoverDIDOpts := &recovery.Opts{}
+	for _, opt := range opts {
+		opt(recoverDIDOpts)
+	}
+
+	return recoverDIDOpts.IdempotencyKey
+}
+
+func deactivateIdempotencyKey(opts []deactivate.Option) string {
+	deactivateDIDOpts := &deactivate.Opts{}
+	for _, opt := range opts {
+		opt(deactivateDIDOpts)
+	}
+
+	return deactivateDIDOpts.IdempotencyKey
+}
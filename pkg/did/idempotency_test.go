@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+)
+
+func TestIdempotentOperations(t *testing.T) {
+	var runs int
+
+	run := func() (*docdid.Doc, error) {
+		runs++
+
+		return &docdid.Doc{ID: "did:ex:123"}, nil
+	}
+
+	t.Run("test operation run once", func(t *testing.T) {
+		runs = 0
+		o := newIdempotentOperations(time.Hour)
+
+		didDoc, err := o.run("key1", operationCreate, "", run)
+		require.NoError(t, err)
+		require.Equal(t, "did:ex:123", didDoc.ID)
+
+		didDoc, err = o.run("key1", operationCreate, "", run)
+		require.NoError(t, err)
+		require.Equal(t, "did:ex:123", didDoc.ID)
+		require.Equal(t, 1, runs)
+
+		_, err = o.run("", operationCreate, "", run)
+		require.NoError(t, err)
+		require.Equal(t, 2, runs)
+	})
+
+	t.Run("test key expired", func(t *testing.T) {
+		runs = 0
+		now := time.Now()
+
+		o := newIdempotentOperations(time.Hour)
+		o.now = func() time.Time { return now }
+
+		_, err := o.run("key1", operationUpdate, "did:ex:123", run)
+		require.NoError(t, err)
+
+		now = now.Add(time.Hour)
+
+		_, err = o.run("key1", operationUpdate, "did:ex:123", run)
+		require.NoError(t, err)
+		require.Equal(t, 2, runs)
+	})
+
+	t.Run("test failed operation retried", func(t *testing.T) {
+		o := newIdempotentOperations(time.Hour)
+
+		_, err := o.run("key1", operationUpdate, "did:ex:123", func() (*docdid.Doc, error) {
+			return nil, errors.New("timeout")
+		})
+		require.EqualError(t, err, "timeout")
+
+		runs = 0
+
+		_, err = o.run("key1", operationUpdate, "did:ex:123", run)
+		require.NoError(t, err)
+		require.Equal(t, 1, runs)
+	})
+
+	t.Run("test key reused", func(t *testing.T) {
+		o := newIdempotentOperations(time.Hour)
+
+		_, err := o.run("key1", operationUpdate, "did:ex:123", run)
+		require.NoError(t, err)
+
+		_, err = o.run("key1", operationRecover, "did:ex:123", run)
+		require.True(t, errors.Is(err, ErrIdempotencyKeyReused))
+
+		_, err = o.run("key1", operationUpdate, "did:ex:456", run)
+		require.True(t, errors.Is(err, ErrIdempotencyKeyReused))
+	})
+
+	t.Run("test operation in progress", func(t *testing.T) {
+		o := newIdempotentOperations(time.Hour)
+
+		_, err := o.run("key1", operationUpdate, "did:ex:123", func() (*docdid.Doc, error) {
+			return o.run("key1", operationUpdate, "did:ex:123", run)
+		})
+		require.True(t, errors.Is(err, ErrOperationInProgress))
+		require.EqualError(t, err, "operation with the same idempotency key in progress: key1")
+	})
+}
+
+func TestClient_WithIdempotencyKey(t *testing.T) {
+	var requests int32
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer serv.Close()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v := New(WithIdempotencyKeyTTL(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privateKey), deactivate.WithIdempotencyKey("key1"))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+		deactivate.WithSigningKey(privateKey))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/contentencoding"
+)
+
+// methodMetadata is the method metadata of a sidetree resolution, with the commitments active for the next
+// operations on the DID and the identifiers of the DID
+type methodMetadata struct {
+	UpdateCommitment   string   `json:"updateCommitment"`
+	RecoveryCommitment string   `json:"recoveryCommitment"`
+	CanonicalID        string   `json:"canonicalId"`
+	EquivalentID       []string `json:"equivalentId"`
+}
+
+func activeUpdateCommitment(metadata *methodMetadata) string {
+	return metadata.UpdateCommitment
+}
+
+func activeRecoveryCommitment(metadata *methodMetadata) string {
+	return metadata.RecoveryCommitment
+}
+
+// selectSigningKey resolves the DID at the sidetree endpoint and returns the key of the key set which the active
+// commitment, as returned by activeCommitment, commits to
+func (c *Client) selectSigningKey(did, endpointURL string, keySet []crypto.PrivateKey, multihashCode uint,
+	activeCommitment func(*methodMetadata) string) (crypto.PrivateKey, error) {
+	metadata, err := c.resolveMethodMetadata(did, endpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the active commitment: %w", err)
+	}
+
+	active := activeCommitment(metadata)
+	if active == "" {
+		return nil, fmt.Errorf("resolution of %s has no active commitment", did)
+	}
+
+	for _, key := range keySet {
+		value, e := keyCommitment(key, multihashCode)
+		if e != nil {
+			return nil, e
+		}
+
+		if value == active {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("none of the keys of the key set matches the active commitment of %s", did)
+}
+
+// checkSigningKey checks, before the request is sent, that the signing key is the key which the active commitment of
+// the DID commits to, so that a wrong key fails with errWrongKey rather than with the request being rejected by the
+// sidetree node. The check is skipped if the DID can't be resolved or its resolution has no active commitment, the
+// node then validating the request on its own.
+func (c *Client) checkSigningKey(did, endpointURL string, key crypto.PrivateKey, multihashCode uint,
+	activeCommitment func(*methodMetadata) string, errWrongKey error) error {
+	metadata, err := c.resolveMethodMetadata(did, endpointURL)
+	if err != nil {
+		c.logger.Debug("skipping the signing key check, failed to resolve the active commitment", "did", did,
+			"error", err)
+
+		return nil
+	}
+
+	active := activeCommitment(metadata)
+	if active == "" {
+		return nil
+	}
+
+	value, err := keyCommitment(key, multihashCode)
+	if err != nil {
+		return err
+	}
+
+	if value != active {
+		return fmt.Errorf("%w: the signing key doesn't match the active commitment of %s", errWrongKey, did)
+	}
+
+	return nil
+}
+
+func keyCommitment(key crypto.PrivateKey, multihashCode uint) (string, error) {
+	_, publicKey, err := newSigner(key, "")
+	if err != nil {
+		return "", err
+	}
+
+	_, value, err := calculateCommitment(publicKey, multihashCode)
+
+	return value, err
+}
+
+func (c *Client) resolveMethodMetadata(did, endpointURL string) (*methodMetadata, error) {
+	responseBytes, err := c.resolve(did, endpointURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMethodMetadata(responseBytes)
+}
+
+// parseMethodMetadata parses the method metadata of a resolution
+func parseMethodMetadata(responseBytes []byte) (*methodMetadata, error) {
+	var r struct {
+		MethodMetadata      *methodMetadata `json:"methodMetadata"`
+		DIDDocumentMetadata *struct {
+			Method       methodMetadata `json:"method"`
+			CanonicalID  string         `json:"canonicalId"`
+			EquivalentID []string       `json:"equivalentId"`
+		} `json:"didDocumentMetadata"`
+	}
+
+	if err := json.Unmarshal(responseBytes, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resolution: %w", err)
+	}
+
+	if r.MethodMetadata != nil {
+		return r.MethodMetadata, nil
+	}
+
+	if r.DIDDocumentMetadata == nil {
+		return &methodMetadata{}, nil
+	}
+
+	// DIF Sidetree v1 resolutions have the commitments in the method metadata of the DID document metadata
+	metadata := r.DIDDocumentMetadata.Method
+	metadata.CanonicalID = r.DIDDocumentMetadata.CanonicalID
+	metadata.EquivalentID = r.DIDDocumentMetadata.EquivalentID
+
+	return &metadata, nil
+}
+
+// resolve resolves the DID at the sidetree endpoint and returns its resolution result
+func (c *Client) resolve(did, endpointURL string) ([]byte, error) {
+	httpReq, err := http.NewRequest(http.MethodGet, endpointURL+"/identifiers/"+did, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request: %w", err)
+	}
+
+	if c.authToken != "" {
+		httpReq.Header.Add("Authorization", c.authToken)
+	}
+
+	contentencoding.SetAcceptEncoding(httpReq)
+
+	c.dumpRequest(httpReq, nil)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer c.closeResponseBody(resp.Body)
+
+	responseBytes, err := contentencoding.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response : %w", err)
+	}
+
+	c.dumpResponse(httpReq, resp, responseBytes)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newSidetreeRequestError(endpointURL, resp.StatusCode, responseBytes)
+	}
+
+	return responseBytes, nil
+}
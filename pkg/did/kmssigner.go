@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"fmt"
+
+	ariescrypto "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+// KMSSigner signs the operations with a key of an aries KMS and Crypto pair, such as localkms or a remote KMS,
+// referenced by its key ID. It's passed as the signing key of the update, recover and deactivate options, and the
+// JWS key ID of the operations is still set with the signing key ID option.
+type KMSSigner struct {
+	crypto    ariescrypto.Crypto
+	kh        interface{}
+	publicKey crypto.PublicKey
+	alg       string
+	der       bool
+}
+
+// NewKMSSigner returns the signer of the ECDSA P-256 or Ed25519 key of the KMS with the key ID.
+func NewKMSSigner(keyManager kms.KeyManager, c ariescrypto.Crypto, keyID string) (*KMSSigner, error) {
+	kh, err := keyManager.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kms key %s: %w", keyID, err)
+	}
+
+	pubKeyBytes, err := keyManager.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export public key of kms key %s: %w", keyID, err)
+	}
+
+	publicKey, alg, der, err := parseKMSPublicKey(pubKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("kms key %s not supported: %w", keyID, err)
+	}
+
+	return &KMSSigner{crypto: c, kh: kh, publicKey: publicKey, alg: alg, der: der}, nil
+}
+
+// parseKMSPublicKey parses the public key exported by the KMS, which is in the format of the signatures of its key
+// type: an uncompressed point for IEEE P1363 keys and PKIX for DER keys
+func parseKMSPublicKey(pubKeyBytes []byte) (crypto.PublicKey, string, bool, error) {
+	switch {
+	case len(pubKeyBytes) == ed25519.PublicKeySize:
+		return ed25519.PublicKey(pubKeyBytes), "EdDSA", false, nil
+	case len(pubKeyBytes) > 0 && pubKeyBytes[0] == 4: //nolint: gomnd
+		x, y := elliptic.Unmarshal(elliptic.P256(), pubKeyBytes)
+		if x == nil {
+			return nil, "", false, fmt.Errorf("failed to unmarshal public key")
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, "ES256", false, nil
+	default:
+		publicKey, err := x509.ParsePKIXPublicKey(pubKeyBytes)
+		if err != nil {
+			return nil, "", false, fmt.Errorf("failed to parse public key: %w", err)
+		}
+
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, "", false, ErrKeyNotSupported
+		}
+
+		return ecKey, "ES256", true, nil
+	}
+}
+
+// Public returns the public key of the KMS key.
+func (s *KMSSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the data with the KMS key. ECDSA signatures are returned in the JWS R||S format.
+func (s *KMSSigner) Sign(data []byte) ([]byte, error) {
+	signature, err := s.crypto.Sign(data, s.kh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with kms key: %w", err)
+	}
+
+	if !s.der {
+		return signature, nil
+	}
+
+	return jwsSignature(signature)
+}
+
+// kmsOperationSigner is the signer of the operations with the KMS key, with the JWS key ID of the operation
+type kmsOperationSigner struct {
+	*KMSSigner
+	kid string
+}
+
+// Headers returns the JWS headers of the signer
+func (s *kmsOperationSigner) Headers() jws.Headers {
+	return jwsHeaders(s.alg, s.kid)
+}
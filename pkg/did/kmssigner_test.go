@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+func TestKMSSigner(t *testing.T) {
+	keyManager, err := localkms.New("local-lock://test/key/uri",
+		mockkms.NewProviderForKMS(mem.NewProvider(), &noop.NoLock{}))
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	for _, keyType := range []kms.KeyType{kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP256TypeDER} {
+		keyType := keyType
+
+		t.Run("test ecdsa key "+string(keyType), func(t *testing.T) {
+			keyID, _, err := keyManager.Create(keyType)
+			require.NoError(t, err)
+
+			kmsSigner, err := NewKMSSigner(keyManager, c, keyID)
+			require.NoError(t, err)
+
+			signer, publicKey, err := getSigner(kmsSigner, "k1")
+			require.NoError(t, err)
+			require.Equal(t, "P-256", publicKey.Crv)
+			require.Equal(t, "ES256", signer.Headers()[jws.HeaderAlgorithm])
+			require.Equal(t, "k1", signer.Headers()[jws.HeaderKeyID])
+
+			signature, err := signer.Sign([]byte("data"))
+			require.NoError(t, err)
+			require.Len(t, signature, 64)
+
+			ecKey, ok := kmsSigner.Public().(*ecdsa.PublicKey)
+			require.True(t, ok)
+
+			digest := sha256.Sum256([]byte("data"))
+			require.True(t, ecdsa.Verify(ecKey, digest[:],
+				new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+		})
+	}
+
+	t.Run("test ed25519 key", func(t *testing.T) {
+		keyID, _, err := keyManager.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kmsSigner, err := NewKMSSigner(keyManager, c, keyID)
+		require.NoError(t, err)
+
+		signer, _, err := getSigner(kmsSigner, "")
+		require.NoError(t, err)
+		require.Equal(t, "EdDSA", signer.Headers()[jws.HeaderAlgorithm])
+
+		signature, err := signer.Sign([]byte("data"))
+		require.NoError(t, err)
+
+		edKey, ok := kmsSigner.Public().(ed25519.PublicKey)
+		require.True(t, ok)
+		require.True(t, ed25519.Verify(edKey, []byte("data"), signature))
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		_, err := NewKMSSigner(keyManager, c, "unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get kms key unknown")
+	})
+
+	t.Run("test export public key error", func(t *testing.T) {
+		_, err := NewKMSSigner(&mockkms.KeyManager{ExportPubKeyBytesErr: errors.New("export error")}, c, "k1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "export error")
+	})
+
+	t.Run("test curve not supported", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		pubKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+
+		_, err = NewKMSSigner(&mockkms.KeyManager{ExportPubKeyBytesValue: pubKeyBytes}, c, "k1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "kms key k1 not supported")
+	})
+
+	t.Run("test sign error", func(t *testing.T) {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		kmsSigner, err := NewKMSSigner(&mockkms.KeyManager{ExportPubKeyBytesValue: publicKey},
+			&mockcrypto.Crypto{SignErr: errors.New("sign error")}, "k1")
+		require.NoError(t, err)
+
+		_, err = kmsSigner.Sign([]byte("data"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sign error")
+	})
+}
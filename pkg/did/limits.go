@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"fmt"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func checkLimitsCreate(config *models.SidetreeConfig, opts *create.Opts) error {
+	return checkDocLimits(config, opts.PublicKeys, opts.Services)
+}
+
+// checkLimitsUpdate checks the public keys and services added by the update on their own, as the document they are
+// added to isn't resolved
+func checkLimitsUpdate(config *models.SidetreeConfig, opts *update.Opts) error {
+	return checkDocLimits(config, opts.AddPublicKeys, opts.AddServices)
+}
+
+func checkLimitsRecover(config *models.SidetreeConfig, opts *recovery.Opts) error {
+	return checkDocLimits(config, opts.PublicKeys, opts.Services)
+}
+
+func checkDocLimits(config *models.SidetreeConfig, publicKeys []doc.PublicKey, services []docdid.Service) error {
+	if config.MaxPublicKeys != 0 && uint(len(publicKeys)) > config.MaxPublicKeys {
+		return fmt.Errorf("%w: %d public keys, the maximum is %d", ErrLimitExceeded, len(publicKeys),
+			config.MaxPublicKeys)
+	}
+
+	if config.MaxServices != 0 && uint(len(services)) > config.MaxServices {
+		return fmt.Errorf("%w: %d services, the maximum is %d", ErrLimitExceeded, len(services), config.MaxServices)
+	}
+
+	for i := range publicKeys {
+		if err := checkIDLength(config, "public key", publicKeys[i].ID); err != nil {
+			return err
+		}
+	}
+
+	for i := range services {
+		if err := checkIDLength(config, "service", services[i].ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkIDLength(config *models.SidetreeConfig, element, id string) error {
+	if config.MaxIDLength != 0 && uint(len(id)) > config.MaxIDLength {
+		return fmt.Errorf("%w: ID of %s %s is %d characters long, the maximum is %d", ErrLimitExceeded, element, id,
+			len(id), config.MaxIDLength)
+	}
+
+	return nil
+}
+
+func checkOperationSize(config *models.SidetreeConfig, req []byte) error {
+	if config.MaxOperationSize != 0 && uint(len(req)) > config.MaxOperationSize {
+		return fmt.Errorf("%w: operation request is %d bytes, the maximum is %d", ErrLimitExceeded, len(req),
+			config.MaxOperationSize)
+	}
+
+	return nil
+}
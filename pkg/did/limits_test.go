@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func TestCheckDocLimits(t *testing.T) {
+	config := &models.SidetreeConfig{MaxPublicKeys: 1, MaxServices: 1, MaxIDLength: 4}
+
+	t.Run("test within limits", func(t *testing.T) {
+		require.NoError(t, checkDocLimits(config, []doc.PublicKey{{ID: "key1"}}, []docdid.Service{{ID: "svc1"}}))
+		require.NoError(t, checkDocLimits(&models.SidetreeConfig{},
+			[]doc.PublicKey{{ID: "key1"}, {ID: "key2"}}, []docdid.Service{{ID: "service1"}}))
+	})
+
+	t.Run("test limits exceeded", func(t *testing.T) {
+		for _, test := range []struct {
+			publicKeys []doc.PublicKey
+			services   []docdid.Service
+			err        string
+		}{
+			{[]doc.PublicKey{{ID: "key1"}, {ID: "key2"}}, nil, "2 public keys, the maximum is 1"},
+			{nil, []docdid.Service{{ID: "svc1"}, {ID: "svc2"}}, "2 services, the maximum is 1"},
+			{[]doc.PublicKey{{ID: "key10"}}, nil, "ID of public key key10 is 5 characters long, the maximum is 4"},
+			{nil, []docdid.Service{{ID: "svc10"}}, "ID of service svc10 is 5 characters long, the maximum is 4"},
+		} {
+			err := checkDocLimits(config, test.publicKeys, test.services)
+			require.True(t, errors.Is(err, ErrLimitExceeded))
+			require.EqualError(t, err, "sidetree limit exceeded: "+test.err)
+		}
+	})
+}
+
+func TestCheckOperationSize(t *testing.T) {
+	require.NoError(t, checkOperationSize(&models.SidetreeConfig{}, make([]byte, 2000)))
+	require.NoError(t, checkOperationSize(&models.SidetreeConfig{MaxOperationSize: 2000}, make([]byte, 2000)))
+
+	err := checkOperationSize(&models.SidetreeConfig{MaxOperationSize: 2000}, make([]byte, 2001))
+	require.True(t, errors.Is(err, ErrLimitExceeded))
+	require.EqualError(t, err, "sidetree limit exceeded: operation request is 2001 bytes, the maximum is 2000")
+}
+
+func TestClient_SidetreeLimits(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	updatePubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	v := New()
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18, MaxOperationSize: 100, MaxIDLength: 4}, nil
+		}}
+
+	t.Run("test create operation size", func(t *testing.T) {
+		_, err = v.CreateDID("", create.WithSidetreeEndpoint("https://example.com/sidetree"),
+			create.WithRecoveryPublicKey(pubKey), create.WithUpdatePublicKey(updatePubKey))
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		require.Contains(t, err.Error(), "operation request is")
+	})
+
+	t.Run("test update key ID length", func(t *testing.T) {
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint("https://example.com/sidetree"),
+			update.WithSigningKey(privKey), update.WithNextUpdatePublicKey(pubKey),
+			update.WithAddPublicKey(&doc.PublicKey{ID: "key10"}))
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		require.Contains(t, err.Error(), "ID of public key key10")
+	})
+
+	t.Run("test recover service ID length", func(t *testing.T) {
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint("https://example.com/sidetree"),
+			recovery.WithSigningKey(privKey), recovery.WithNextRecoveryPublicKey(pubKey),
+			recovery.WithNextUpdatePublicKey(pubKey), recovery.WithService(&docdid.Service{ID: "svc10"}))
+		require.True(t, errors.Is(err, ErrLimitExceeded))
+		require.Contains(t, err.Error(), "ID of service svc10")
+	})
+}
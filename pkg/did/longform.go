@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+const (
+	operationTypeProperty = "type"
+	createOperationType   = "create"
+)
+
+// LongFormDID returns the long-form DID of a DID created with the given sidetree create request. The long-form
+// DID carries the initial state of the document, which is the canonical JSON of the suffix data and the delta of
+// the request, so that it can be resolved before the create operation is anchored.
+func LongFormDID(shortFormDID string, createRequest []byte) (string, error) {
+	request := make(map[string]interface{})
+
+	if err := json.Unmarshal(createRequest, &request); err != nil {
+		return "", fmt.Errorf("failed to parse create request: %w", err)
+	}
+
+	if request[operationTypeProperty] != createOperationType {
+		return "", errors.New("not a create request")
+	}
+
+	delete(request, operationTypeProperty)
+
+	initialState, err := docutil.MarshalCanonical(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize initial state: %w", err)
+	}
+
+	return shortFormDID + ":" + base64.RawURLEncoding.EncodeToString(initialState), nil
+}
+
+// ShortFormDID returns the short-form DID of a long-form DID, which is the DID without its initial state, and returns
+// any other DID as is
+func ShortFormDID(did string) string {
+	i := strings.LastIndex(did, ":")
+	if i < 0 {
+		return did
+	}
+
+	initialState, err := base64.RawURLEncoding.DecodeString(did[i+1:])
+	if err != nil {
+		return did
+	}
+
+	var state struct {
+		SuffixData json.RawMessage `json:"suffixData"`
+		Delta      json.RawMessage `json:"delta"`
+	}
+
+	if err = json.Unmarshal(initialState, &state); err != nil || len(state.SuffixData) == 0 ||
+		len(state.Delta) == 0 {
+		return did
+	}
+
+	return did[:i]
+}
+
+// DIDsEquivalent returns whether the DIDs identify the same DID, either being the long-form DID of the other or both
+// having the same short-form DID
+func DIDsEquivalent(a, b string) bool {
+	return a == b || ShortFormDID(a) == ShortFormDID(b)
+}
+
+// Identifiers are the identifiers of a DID, as returned in the method metadata of its resolution
+type Identifiers struct {
+	// CanonicalID is the ID the DID should be referred to with, which is its short-form DID once it is anchored
+	CanonicalID string
+	// EquivalentIDs are the other IDs identifying the DID, such as its long-form DID
+	EquivalentIDs []string
+}
+
+// Identifies returns whether the DID is one of the identifiers, or equivalent to one of them
+func (ids *Identifiers) Identifies(did string) bool {
+	if DIDsEquivalent(did, ids.CanonicalID) {
+		return true
+	}
+
+	for _, id := range ids.EquivalentIDs {
+		if DIDsEquivalent(did, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveIdentifiers resolves the DID, at the first sidetree endpoint of the domain or else at the first of the
+// sidetree endpoints, and returns its canonical and equivalent IDs. The canonical ID defaults to the short-form DID
+// of the DID if the resolution has none.
+func (c *Client) ResolveIdentifiers(did, domain string, sidetreeEndpoints ...string) (*Identifiers, error) {
+	endpoints := make([]*models.Endpoint, len(sidetreeEndpoints))
+	for i, endpoint := range sidetreeEndpoints {
+		endpoints[i] = &models.Endpoint{URL: endpoint}
+	}
+
+	endpointURL, err := c.getEndpoint(context.Background(), domain, endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := c.resolveMethodMetadata(did, endpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the identifiers of %s: %w", did, err)
+	}
+
+	ids := &Identifiers{CanonicalID: metadata.CanonicalID, EquivalentIDs: metadata.EquivalentID}
+	if ids.CanonicalID == "" {
+		ids.CanonicalID = ShortFormDID(did)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLongFormDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		longFormDID, err := LongFormDID("did:trustbloc:domain:EiA",
+			[]byte(`{"type":"create","suffixData":{"recoveryCommitment":"c1","deltaHash":"h"},"delta":{"patches":[]}}`))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(longFormDID, "did:trustbloc:domain:EiA:"))
+
+		initialState, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(longFormDID,
+			"did:trustbloc:domain:EiA:"))
+		require.NoError(t, err)
+		require.Equal(t, `{"delta":{"patches":[]},"suffixData":{"deltaHash":"h","recoveryCommitment":"c1"}}`,
+			string(initialState))
+	})
+
+	t.Run("test invalid request", func(t *testing.T) {
+		_, err := LongFormDID("did:trustbloc:domain:EiA", []byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse create request")
+	})
+
+	t.Run("test not a create request", func(t *testing.T) {
+		_, err := LongFormDID("did:trustbloc:domain:EiA", []byte(`{"type":"update"}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a create request")
+	})
+}
+
+func TestShortFormDID(t *testing.T) {
+	longFormDID, err := LongFormDID("did:trustbloc:domain:EiA",
+		[]byte(`{"type":"create","suffixData":{"recoveryCommitment":"c1","deltaHash":"h"},"delta":{"patches":[]}}`))
+	require.NoError(t, err)
+
+	require.Equal(t, "did:trustbloc:domain:EiA", ShortFormDID(longFormDID))
+	require.Equal(t, "did:trustbloc:domain:EiA", ShortFormDID("did:trustbloc:domain:EiA"))
+	require.Equal(t, "EiA", ShortFormDID("EiA"))
+
+	// the last part is base64url encoded JSON, but not an initial state
+	notInitialState := "did:trustbloc:domain:EiA:" + base64.RawURLEncoding.EncodeToString([]byte(`{"delta":{}}`))
+	require.Equal(t, notInitialState, ShortFormDID(notInitialState))
+
+	require.True(t, DIDsEquivalent(longFormDID, "did:trustbloc:domain:EiA"))
+	require.True(t, DIDsEquivalent("did:trustbloc:domain:EiA", longFormDID))
+	require.True(t, DIDsEquivalent(longFormDID, longFormDID))
+	require.False(t, DIDsEquivalent(longFormDID, "did:trustbloc:domain:EiB"))
+	require.False(t, DIDsEquivalent("did:trustbloc:domain:EiA", "did:trustbloc:other:EiA"))
+}
+
+func TestClient_ResolveIdentifiers(t *testing.T) {
+	longFormDID, err := LongFormDID("did:trustbloc:domain:EiA",
+		[]byte(`{"type":"create","suffixData":{"recoveryCommitment":"c1","deltaHash":"h"},"delta":{"patches":[]}}`))
+	require.NoError(t, err)
+
+	var metadata string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "EiB") {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"didDocument": {}, "methodMetadata": %s}`, metadata)
+	}))
+	defer serv.Close()
+
+	v := New()
+
+	var ids *Identifiers
+
+	t.Run("test identifiers", func(t *testing.T) {
+		metadata = fmt.Sprintf(`{"canonicalId": "did:trustbloc:domain:EiA", "equivalentId": ["%s"]}`, longFormDID)
+
+		ids, err = v.ResolveIdentifiers(longFormDID, "", serv.URL)
+		require.NoError(t, err)
+		require.Equal(t, "did:trustbloc:domain:EiA", ids.CanonicalID)
+		require.Equal(t, []string{longFormDID}, ids.EquivalentIDs)
+		require.True(t, ids.Identifies("did:trustbloc:domain:EiA"))
+		require.True(t, ids.Identifies(longFormDID))
+		require.False(t, ids.Identifies("did:trustbloc:domain:EiB"))
+	})
+
+	t.Run("test identifiers of equivalent DID", func(t *testing.T) {
+		metadata = `{"canonicalId": "did:trustbloc:domain:EiC", "equivalentId": ["did:trustbloc:domain:EiA"]}`
+
+		ids, err = v.ResolveIdentifiers(longFormDID, "", serv.URL)
+		require.NoError(t, err)
+		require.True(t, ids.Identifies(longFormDID))
+	})
+
+	t.Run("test no canonical ID", func(t *testing.T) {
+		metadata = `{}`
+
+		ids, err = v.ResolveIdentifiers(longFormDID, "", serv.URL)
+		require.NoError(t, err)
+		require.Equal(t, "did:trustbloc:domain:EiA", ids.CanonicalID)
+		require.Empty(t, ids.EquivalentIDs)
+	})
+
+	t.Run("test resolution error", func(t *testing.T) {
+		_, err = v.ResolveIdentifiers("did:trustbloc:domain:EiB", "", serv.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve the identifiers of did:trustbloc:domain:EiB")
+	})
+
+	t.Run("test no domain nor endpoints", func(t *testing.T) {
+		_, err = v.ResolveIdentifiers(longFormDID, "")
+		require.True(t, errors.Is(err, ErrDomainEmpty))
+	})
+}
@@ -0,0 +1,331 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package migrate migrates did:trustbloc DIDs to did:orb. The resolved document of a DID is converted to the options
+// of the create operation of the equivalent did:orb DID, or of the update operation bringing an existing did:orb DID
+// in line with it, and the two documents are compared in an equivalence report.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+const (
+	jwkCurveEd25519 = "Ed25519"
+	jwkCurveP256    = "P-256"
+)
+
+// Plan holds the public keys and services of a DID document, in the form the operations of the client take them
+type Plan struct {
+	PublicKeys []doc.PublicKey
+	Services   []docdid.Service
+	// Skipped are the IDs of the public keys which can't be migrated, sidetree operations only taking Ed25519 and
+	// P-256 keys
+	Skipped []string
+}
+
+// Report is the equivalence report of a did:trustbloc DID and its did:orb DID
+type Report struct {
+	DID    string `json:"did"`
+	OrbDID string `json:"orbDID"`
+	// Equivalent is true if the did:orb document has the same public keys and services and no key was skipped
+	Equivalent  bool     `json:"equivalent"`
+	Divergences []string `json:"divergences,omitempty"`
+	Skipped     []string `json:"skipped,omitempty"`
+}
+
+// NewPlan returns the plan of the document. The IDs of the public keys and services, and the references of the
+// services to the keys of the DID, are made relative to the DID, so that they apply to the did:orb DID.
+func NewPlan(didDoc *docdid.Doc) *Plan {
+	p := &Plan{}
+
+	for i := range didDoc.VerificationMethod {
+		p.addPublicKey(&didDoc.VerificationMethod[i], "")
+	}
+
+	for _, r := range []struct {
+		purpose       string
+		verifications []docdid.Verification
+	}{
+		{doc.KeyPurposeAuthentication, didDoc.Authentication},
+		{doc.KeyPurposeAssertionMethod, didDoc.AssertionMethod},
+		{doc.KeyPurposeKeyAgreement, didDoc.KeyAgreement},
+		{doc.KeyPurposeCapabilityDelegation, didDoc.CapabilityDelegation},
+		{doc.KeyPurposeCapabilityInvocation, didDoc.CapabilityInvocation},
+	} {
+		for i := range r.verifications {
+			p.addPublicKey(&r.verifications[i].VerificationMethod, r.purpose)
+		}
+	}
+
+	for i := range didDoc.Service {
+		service := didDoc.Service[i]
+		service.ID = fragment(service.ID)
+		service.RecipientKeys = relativeReferences(didDoc.ID, service.RecipientKeys)
+		service.RoutingKeys = relativeReferences(didDoc.ID, service.RoutingKeys)
+
+		p.Services = append(p.Services, service)
+	}
+
+	return p
+}
+
+// addPublicKey adds the verification method, or the purpose to the public key already added for it
+func (p *Plan) addPublicKey(method *docdid.VerificationMethod, purpose string) {
+	id := fragment(method.ID)
+
+	if containsID(p.Skipped, id) {
+		return
+	}
+
+	for i := range p.PublicKeys {
+		if p.PublicKeys[i].ID == id {
+			if purpose != "" && !containsID(p.PublicKeys[i].Purposes, purpose) {
+				p.PublicKeys[i].Purposes = append(p.PublicKeys[i].Purposes, purpose)
+			}
+
+			return
+		}
+	}
+
+	keyType, ok := publicKeyType(method)
+	if !ok {
+		p.Skipped = append(p.Skipped, id)
+
+		return
+	}
+
+	publicKey := doc.PublicKey{ID: id, Type: method.Type, Encoding: doc.PublicKeyEncodingJwk, KeyType: keyType,
+		Value: method.Value}
+
+	if purpose != "" {
+		publicKey.Purposes = []string{purpose}
+	}
+
+	p.PublicKeys = append(p.PublicKeys, publicKey)
+}
+
+// CreateOptions returns the options of the create operation of a DID with the public keys and services of the plan
+func (p *Plan) CreateOptions() []create.Option {
+	var opts []create.Option
+
+	for i := range p.PublicKeys {
+		opts = append(opts, create.WithPublicKey(&p.PublicKeys[i]))
+	}
+
+	for i := range p.Services {
+		opts = append(opts, create.WithService(&p.Services[i]))
+	}
+
+	return opts
+}
+
+// UpdateOptions returns the options of the update operation bringing the DID of the document in line with the
+// plan, or no options if it already is. The public keys and services which differ are added again, replacing them.
+func (p *Plan) UpdateOptions(didDoc *docdid.Doc) []update.Option {
+	current := NewPlan(didDoc)
+
+	return append(p.publicKeyUpdates(current), p.serviceUpdates(current)...)
+}
+
+func (p *Plan) publicKeyUpdates(current *Plan) []update.Option {
+	var opts []update.Option
+
+	// the keys which can't be migrated are left as they are
+	for _, id := range append(current.publicKeyIDs(), current.Skipped...) {
+		if p.publicKey(id) == nil && !containsID(p.Skipped, id) {
+			opts = append(opts, update.WithRemovePublicKey(id))
+		}
+	}
+
+	for i := range p.PublicKeys {
+		existing := current.publicKey(p.PublicKeys[i].ID)
+		if existing == nil || !equalPublicKeys(existing, &p.PublicKeys[i]) {
+			opts = append(opts, update.WithAddPublicKey(&p.PublicKeys[i]))
+		}
+	}
+
+	return opts
+}
+
+func (p *Plan) serviceUpdates(current *Plan) []update.Option {
+	var opts []update.Option
+
+	for i := range current.Services {
+		if p.service(current.Services[i].ID) == nil {
+			opts = append(opts, update.WithRemoveService(current.Services[i].ID))
+		}
+	}
+
+	for i := range p.Services {
+		existing := current.service(p.Services[i].ID)
+		if existing == nil || !equalServices(existing, &p.Services[i]) {
+			opts = append(opts, update.WithAddService(&p.Services[i]))
+		}
+	}
+
+	return opts
+}
+
+// Compare returns the equivalence report of the document of the did:trustbloc DID and the document of its did:orb
+// DID
+func Compare(didDoc, orbDoc *docdid.Doc) *Report {
+	source := NewPlan(didDoc)
+	target := NewPlan(orbDoc)
+
+	r := &Report{DID: didDoc.ID, OrbDID: orbDoc.ID, Skipped: source.Skipped}
+	r.Divergences = append(comparePublicKeys(source, target), compareServices(source, target)...)
+
+	r.Equivalent = len(r.Divergences) == 0 && len(r.Skipped) == 0
+
+	return r
+}
+
+func comparePublicKeys(source, target *Plan) []string {
+	var divergences []string
+
+	for i := range source.PublicKeys {
+		key := &source.PublicKeys[i]
+
+		switch existing := target.publicKey(key.ID); {
+		case existing == nil:
+			divergences = append(divergences, fmt.Sprintf("missing public key %s", key.ID))
+		case !equalPublicKeys(existing, key):
+			divergences = append(divergences, fmt.Sprintf("public key %s differs", key.ID))
+		}
+	}
+
+	for i := range target.PublicKeys {
+		if source.publicKey(target.PublicKeys[i].ID) == nil && !containsID(source.Skipped, target.PublicKeys[i].ID) {
+			divergences = append(divergences, fmt.Sprintf("unexpected public key %s", target.PublicKeys[i].ID))
+		}
+	}
+
+	return divergences
+}
+
+func compareServices(source, target *Plan) []string {
+	var divergences []string
+
+	for i := range source.Services {
+		service := &source.Services[i]
+
+		switch existing := target.service(service.ID); {
+		case existing == nil:
+			divergences = append(divergences, fmt.Sprintf("missing service %s", service.ID))
+		case !equalServices(existing, service):
+			divergences = append(divergences, fmt.Sprintf("service %s differs", service.ID))
+		}
+	}
+
+	for i := range target.Services {
+		if source.service(target.Services[i].ID) == nil {
+			divergences = append(divergences, fmt.Sprintf("unexpected service %s", target.Services[i].ID))
+		}
+	}
+
+	return divergences
+}
+
+func (p *Plan) publicKeyIDs() []string {
+	ids := make([]string, len(p.PublicKeys))
+	for i := range p.PublicKeys {
+		ids[i] = p.PublicKeys[i].ID
+	}
+
+	return ids
+}
+
+func (p *Plan) publicKey(id string) *doc.PublicKey {
+	for i := range p.PublicKeys {
+		if p.PublicKeys[i].ID == id {
+			return &p.PublicKeys[i]
+		}
+	}
+
+	return nil
+}
+
+func (p *Plan) service(id string) *docdid.Service {
+	for i := range p.Services {
+		if p.Services[i].ID == id {
+			return &p.Services[i]
+		}
+	}
+
+	return nil
+}
+
+// publicKeyType returns the key type of the verification method, either from the curve of its JWK or from its
+// type for the Ed25519 keys which are not JWKs
+func publicKeyType(method *docdid.VerificationMethod) (string, bool) {
+	if jwk := method.JSONWebKey(); jwk != nil {
+		switch jwk.Crv {
+		case jwkCurveEd25519:
+			return doc.Ed25519KeyType, true
+		case jwkCurveP256:
+			return doc.P256KeyType, true
+		default:
+			return "", false
+		}
+	}
+
+	if method.Type == doc.Ed25519VerificationKey2018 {
+		return doc.Ed25519KeyType, true
+	}
+
+	return "", false
+}
+
+func equalPublicKeys(a, b *doc.PublicKey) bool {
+	return a.Type == b.Type && a.KeyType == b.KeyType && bytes.Equal(a.Value, b.Value) &&
+		reflect.DeepEqual(a.Purposes, b.Purposes)
+}
+
+func equalServices(a, b *docdid.Service) bool {
+	return a.Type == b.Type && a.ServiceEndpoint == b.ServiceEndpoint && a.Priority == b.Priority &&
+		reflect.DeepEqual(a.RecipientKeys, b.RecipientKeys) && reflect.DeepEqual(a.RoutingKeys, b.RoutingKeys) &&
+		reflect.DeepEqual(a.Properties, b.Properties)
+}
+
+// relativeReferences returns the references with the ones to the keys of the DID made relative to it, such as #key1
+// for did:trustbloc:testnet:123#key1
+func relativeReferences(did string, references []string) []string {
+	var result []string
+
+	for _, reference := range references {
+		if strings.HasPrefix(reference, did+"#") {
+			reference = "#" + fragment(reference)
+		}
+
+		result = append(result, reference)
+	}
+
+	return result
+}
+
+// fragment returns the fragment of an ID relative to the DID, such as key1 for did:trustbloc:testnet:123#key1
+func fragment(id string) string {
+	return id[strings.LastIndex(id, "#")+1:]
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrate
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	gojose "github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+const (
+	trustblocDID = "did:trustbloc:testnet:123"
+	orbDID       = "did:orb:bafkreiabc:456"
+)
+
+func TestNewPlan(t *testing.T) {
+	didDoc := newDoc(t, trustblocDID, newKeys(t))
+
+	p := NewPlan(didDoc)
+	require.Equal(t, []string{"key3"}, p.Skipped)
+	require.Len(t, p.PublicKeys, 2)
+
+	require.Equal(t, "key1", p.PublicKeys[0].ID)
+	require.Equal(t, doc.Ed25519KeyType, p.PublicKeys[0].KeyType)
+	require.Equal(t, doc.PublicKeyEncodingJwk, p.PublicKeys[0].Encoding)
+	require.Equal(t, []string{doc.KeyPurposeAuthentication, doc.KeyPurposeAssertionMethod},
+		p.PublicKeys[0].Purposes)
+
+	require.Equal(t, "key2", p.PublicKeys[1].ID)
+	require.Equal(t, doc.JWSVerificationKey2020, p.PublicKeys[1].Type)
+	require.Equal(t, doc.P256KeyType, p.PublicKeys[1].KeyType)
+	require.Equal(t, []string{doc.KeyPurposeAssertionMethod}, p.PublicKeys[1].Purposes)
+
+	require.Len(t, p.Services, 1)
+	require.Equal(t, "svc1", p.Services[0].ID)
+	require.Equal(t, []string{"#key1"}, p.Services[0].RecipientKeys)
+	require.Equal(t, []string{"did:ex:mediator#key1"}, p.Services[0].RoutingKeys)
+
+	opts := &create.Opts{}
+	for _, opt := range p.CreateOptions() {
+		opt(opts)
+	}
+
+	require.Equal(t, p.PublicKeys, opts.PublicKeys)
+	require.Equal(t, p.Services, opts.Services)
+}
+
+func TestCompare(t *testing.T) {
+	keys := newKeys(t)
+
+	t.Run("test equivalent", func(t *testing.T) {
+		r := Compare(newDoc(t, trustblocDID, keys), newDoc(t, orbDID, keys))
+		require.Empty(t, r.Divergences)
+		require.Equal(t, []string{"key3"}, r.Skipped)
+		require.False(t, r.Equivalent)
+		require.Equal(t, trustblocDID, r.DID)
+		require.Equal(t, orbDID, r.OrbDID)
+
+		keys.withoutKey3 = true
+
+		r = Compare(newDoc(t, trustblocDID, keys), newDoc(t, orbDID, keys))
+		require.True(t, r.Equivalent)
+
+		keys.withoutKey3 = false
+	})
+
+	t.Run("test divergences", func(t *testing.T) {
+		orbDoc := newDoc(t, orbDID, keys)
+		orbDoc.VerificationMethod[0].Value = keys.other
+		orbDoc.AssertionMethod = orbDoc.AssertionMethod[:1]
+		orbDoc.Service[0].ServiceEndpoint = "https://example.org"
+		orbDoc.Service = append(orbDoc.Service, docdid.Service{ID: orbDID + "#svc2", Type: "type"})
+
+		r := Compare(newDoc(t, trustblocDID, keys), orbDoc)
+		require.False(t, r.Equivalent)
+		require.Equal(t, []string{"public key key1 differs", "missing public key key2", "service svc1 differs",
+			"unexpected service svc2"}, r.Divergences)
+	})
+}
+
+func TestUpdateOptions(t *testing.T) {
+	keys := newKeys(t)
+
+	t.Run("test already equivalent", func(t *testing.T) {
+		require.Empty(t, NewPlan(newDoc(t, trustblocDID, keys)).UpdateOptions(newDoc(t, orbDID, keys)))
+	})
+
+	t.Run("test divergences", func(t *testing.T) {
+		orbDoc := newDoc(t, orbDID, keys)
+		orbDoc.VerificationMethod[0].Value = keys.other
+		orbDoc.AssertionMethod = orbDoc.AssertionMethod[:1]
+		orbDoc.VerificationMethod = append(orbDoc.VerificationMethod, docdid.VerificationMethod{ID: orbDID + "#key4",
+			Type: doc.Ed25519VerificationKey2018, Controller: orbDID, Value: keys.other})
+		orbDoc.Service = []docdid.Service{{ID: orbDID + "#svc2", Type: "type"}}
+
+		p := NewPlan(newDoc(t, trustblocDID, keys))
+
+		opts := &update.Opts{}
+		for _, opt := range p.UpdateOptions(orbDoc) {
+			opt(opts)
+		}
+
+		require.Equal(t, []string{"key4"}, opts.RemovePublicKeys)
+		require.Equal(t, []string{"svc2"}, opts.RemoveServices)
+		require.Equal(t, p.PublicKeys, opts.AddPublicKeys)
+		require.Equal(t, p.Services, opts.AddServices)
+	})
+}
+
+type testKeys struct {
+	ed25519     ed25519.PublicKey
+	p256        *jose.JWK
+	x25519      []byte
+	other       []byte
+	withoutKey3 bool
+}
+
+func newKeys(t *testing.T) *testKeys {
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &testKeys{ed25519: edKey, other: otherKey, x25519: make([]byte, 32),
+		p256: &jose.JWK{JSONWebKey: gojose.JSONWebKey{Key: &ecKey.PublicKey}, Kty: "EC", Crv: "P-256"}}
+}
+
+// newDoc returns the document of the DID with key1 referenced by two relationships, key2 embedded in one of them,
+// key3 which can't be migrated and a service referencing key1
+func newDoc(t *testing.T, did string, keys *testKeys) *docdid.Doc {
+	key1 := docdid.VerificationMethod{ID: did + "#key1", Type: doc.Ed25519VerificationKey2018, Controller: did,
+		Value: keys.ed25519}
+
+	key2, err := docdid.NewVerificationMethodFromJWK(did+"#key2", doc.JWSVerificationKey2020, did, keys.p256)
+	require.NoError(t, err)
+
+	didDoc := &docdid.Doc{ID: did, VerificationMethod: []docdid.VerificationMethod{key1},
+		Authentication: []docdid.Verification{{VerificationMethod: key1, Relationship: docdid.Authentication}},
+		AssertionMethod: []docdid.Verification{
+			{VerificationMethod: key1, Relationship: docdid.AssertionMethod},
+			*docdid.NewEmbeddedVerification(key2, docdid.AssertionMethod),
+		},
+		Service: []docdid.Service{{ID: did + "#svc1", Type: "DIDCommMessaging", ServiceEndpoint: "https://example.com",
+			RecipientKeys: []string{did + "#key1"}, RoutingKeys: []string{"did:ex:mediator#key1"}}},
+	}
+
+	if !keys.withoutKey3 {
+		didDoc.KeyAgreement = []docdid.Verification{*docdid.NewEmbeddedVerification(
+			docdid.NewVerificationMethodFromBytes(did+"#key3", "X25519KeyAgreementKey2019", did, keys.x25519),
+			docdid.KeyAgreement)}
+	}
+
+	return didDoc
+}
@@ -7,6 +7,16 @@ package did
 
 import (
 	"crypto/tls"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/didweb"
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
 )
 
 // Option is a DID client instance option
@@ -19,9 +29,207 @@ func WithTLSConfig(tlsConfig *tls.Config) Option {
 	}
 }
 
+// WithClientCertificates option presents the client certificates to the sidetree endpoints and the consortium domains
+// which require mutual TLS, in addition to the certificates of the tls.Config of WithTLSConfig
+func WithClientCertificates(certs ...tls.Certificate) Option {
+	return func(opts *Client) {
+		opts.tlsOpts = append(opts.tlsOpts, tlsconfig.WithClientCertificates(certs...))
+	}
+}
+
+// WithTLSOptions option applies the TLS settings, such as the minimum version or the cipher suites, to the
+// tls.Config of WithTLSConfig, or to a tls.Config with TLS 1.2 as minimum version if not set
+func WithTLSOptions(tlsOpts ...tlsconfig.Option) Option {
+	return func(opts *Client) {
+		opts.tlsOpts = append(opts.tlsOpts, tlsOpts...)
+	}
+}
+
+// WithProxyURL option sends the requests of the client, including its config fetches, through the HTTP proxy
+// instead of the proxy set by the HTTPS_PROXY and HTTP_PROXY environment variables if any
+func WithProxyURL(proxyURL *url.URL) Option {
+	return func(opts *Client) {
+		opts.proxyURL = proxyURL
+	}
+}
+
 // WithAuthToken add auth token
 func WithAuthToken(authToken string) Option {
 	return func(opts *Client) {
 		opts.authToken = "Bearer " + authToken
 	}
 }
+
+// WithMetricsProvider option records sidetree config, endpoint discovery and sidetree operation metrics with the
+// given provider, the sidetree operation metrics being labelled with the type of the operation. See package
+// promadapter for a provider registering the metrics with the Prometheus client.
+func WithMetricsProvider(p metrics.Provider) Option {
+	return func(opts *Client) {
+		opts.metrics = p
+	}
+}
+
+// WithIPFSGateway option sets the HTTP gateway used to fetch consortium and stakeholder configs
+// referenced by ipfs:// URIs
+func WithIPFSGateway(gatewayURL string) Option {
+	return func(opts *Client) {
+		opts.ipfsGateway = gatewayURL
+	}
+}
+
+// WithOperationCallback option calls the callback with the endpoint and request hash of every sidetree operation
+// request that was accepted
+func WithOperationCallback(callback func(info *OperationInfo)) Option {
+	return func(opts *Client) {
+		opts.operationCallback = callback
+	}
+}
+
+// WithDryRun option builds the sidetree operation requests but, instead of sending them, passes them to the
+// callback together with the endpoint they would have been sent to. Operations then fail with ErrDryRun.
+// A nil callback sends the requests as usual.
+func WithDryRun(callback func(endpoint string, request []byte)) Option {
+	return func(opts *Client) {
+		opts.dryRunCallback = callback
+	}
+}
+
+// WithCommitmentStore option records the update and recovery keys each DID committed to in the store, after every
+// successful operation, so that the keys to reveal in the next operation can be located. The commitments of a DID
+// are deleted once it is deactivated.
+func WithCommitmentStore(store commitmentstore.Store) Option {
+	return func(opts *Client) {
+		opts.commitmentStore = store
+	}
+}
+
+// WithDIDWebPublisher option sets the publisher of the did:web documents of the operations run with a did:web
+// domain path, which keep the did:web representations of the DIDs in sync with their did:trustbloc documents
+func WithDIDWebPublisher(publisher didweb.Publisher) Option {
+	return func(opts *Client) {
+		opts.didWebPublisher = publisher
+	}
+}
+
+// WithFIPSMode option restricts the client to FIPS-approved algorithms, for deployments with compliance
+// requirements: the keys of the operations must be P-256 keys, signing with ES256, and the multihash algorithm of
+// the sidetree endpoint SHA-256 or SHA-512. Operations fail with ErrNotFIPSApproved otherwise, such as with Ed25519
+// keys.
+func WithFIPSMode() Option {
+	return func(opts *Client) {
+		opts.fipsMode = true
+	}
+}
+
+// WithLogger option sets the logger of the client, its config service and its endpoint discovery, to route their
+// output into the logging of the host application. Defaults to logger.Default.
+func WithLogger(l logger.Logger) Option {
+	return func(opts *Client) {
+		opts.logger = l
+	}
+}
+
+// WithTracer option traces the operations of the client with the tracer: each operation is a span, with child spans
+// for the endpoint discovery, the sidetree config fetch, the build of the request and the sidetree request, which
+// carries the trace context. See package oteltracing for an OpenTelemetry tracer.
+func WithTracer(t tracing.Tracer) Option {
+	return func(opts *Client) {
+		opts.tracer = t
+	}
+}
+
+// WithDebugHTTP option logs the sidetree requests of the client and their responses with the logger of the client, at
+// info level, to troubleshoot the requests rejected by an endpoint. The values of the headers holding credentials,
+// such as Authorization, are redacted and the JSON bodies are indented.
+func WithDebugHTTP() Option {
+	return func(opts *Client) {
+		opts.debugHTTP = true
+	}
+}
+
+// WithMaxIdleConns option sets the maximum number of idle connections the client keeps open to reuse them, in total
+// and per host. Defaults to 100, to be raised for high-throughput issuers sending many concurrent requests.
+func WithMaxIdleConns(n int) Option {
+	return func(opts *Client) {
+		opts.maxIdleConns = n
+	}
+}
+
+// WithIdleConnTimeout option sets how long an idle connection is kept open before being closed. Defaults to 90
+// seconds, zero meaning no limit.
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(opts *Client) {
+		opts.idleConnTimeout = timeout
+	}
+}
+
+// WithDialTimeout option sets how long the client waits for a connection to be established. Defaults to 30 seconds.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(opts *Client) {
+		opts.dialTimeout = timeout
+	}
+}
+
+// WithResolver option sets the DNS resolver of the hosts the client connects to, such as a resolver querying the
+// name servers of a locked-down environment. Defaults to the resolver of the system.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(opts *Client) {
+		opts.resolver = resolver
+	}
+}
+
+// WithAllowedNetworks option restricts the addresses the client connects to, including for its config fetches, to
+// the networks, such as the networks of the consortium domains. The addresses are checked once the hosts are
+// resolved. With a proxy, the address of the proxy is checked instead.
+func WithAllowedNetworks(networks ...*net.IPNet) Option {
+	return func(opts *Client) {
+		opts.allowedNetworks = networks
+	}
+}
+
+// WithProtocolCheck option checks the sidetree protocol version of the sidetree endpoint, as reported with its sidetree
+// config, before an operation is sent to it, so that the operations fail with ErrIncompatibleEndpoint rather than
+// being rejected by an endpoint which doesn't implement the protocol version of the client or doesn't report its
+// version.
+func WithProtocolCheck() Option {
+	return func(opts *Client) {
+		opts.protocolCheck = true
+	}
+}
+
+// WithProtocolVersion option sets the sidetree protocol version of the requests of the operations, ProtocolVersion01,
+// the default, or ProtocolVersion1 for the endpoints implementing the DIF Sidetree v1 specification. It's also the
+// version expected by WithProtocolCheck.
+func WithProtocolVersion(version string) Option {
+	return func(opts *Client) {
+		opts.protocolVersion = version
+	}
+}
+
+// WithIdempotencyKeyTTL option sets how long the results of the operations run with an idempotency key are kept for,
+// the operations with the same key returning these results instead of being submitted again. Defaults to 24 hours.
+func WithIdempotencyKeyTTL(ttl time.Duration) Option {
+	return func(opts *Client) {
+		opts.idempotencyKeyTTL = ttl
+	}
+}
+
+// WithRateLimit option limits the sidetree operation requests of the client to the rate in operations per second,
+// with bursts of up to burst requests, so that batch jobs throttle themselves rather than exceed the rate limit of the
+// endpoints. The requests over the rate wait for their turn. A zero or negative rate means no limit.
+func WithRateLimit(opsPerSecond float64, burst int) Option {
+	return func(opts *Client) {
+		opts.rateLimiter = nil
+		if opsPerSecond > 0 {
+			opts.rateLimiter = newRateLimiter(opsPerSecond, burst)
+		}
+	}
+}
+
+// WithKeepAlive option sets the interval of the TCP keep-alive probes of the connections. Defaults to 30 seconds, a
+// negative interval disabling them.
+func WithKeepAlive(interval time.Duration) Option {
+	return func(opts *Client) {
+		opts.keepAlive = interval
+	}
+}
@@ -7,6 +7,7 @@ package create
 
 import (
 	"crypto"
+	"time"
 
 	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 
@@ -23,6 +24,16 @@ type Opts struct {
 	UpdatePublicKey   crypto.PublicKey
 	SigningKey        crypto.PrivateKey
 	SigningKeyID      string
+	// RequestHeaders are the headers added to the sidetree request
+	RequestHeaders map[string]string
+	// IdempotencyKey identifies the operation across retries
+	IdempotencyKey string
+	// VerifyAttempts is the number of times the created DID is resolved to verify its anchored document, which is
+	// not verified if zero
+	VerifyAttempts int
+	VerifyInterval time.Duration
+	// DIDWebPath is the domain path the equivalent did:web document is published for, if set
+	DIDWebPath string
 }
 
 // Option is a create DID option
@@ -63,3 +74,40 @@ func WithUpdatePublicKey(updatePublicKey crypto.PublicKey) Option {
 		opts.UpdatePublicKey = updatePublicKey
 	}
 }
+
+// WithRequestHeader add a header to the sidetree request, such as a correlation ID
+func WithRequestHeader(name, value string) Option {
+	return func(opts *Opts) {
+		if opts.RequestHeaders == nil {
+			opts.RequestHeaders = make(map[string]string)
+		}
+
+		opts.RequestHeaders[name] = value
+	}
+}
+
+// WithVerification verify the anchored document once the DID is created. The DID is resolved at the sidetree
+// endpoint up to attempts times, every interval, until it is anchored, and its document must then have exactly the
+// requested public keys and services.
+func WithVerification(attempts int, interval time.Duration) Option {
+	return func(opts *Opts) {
+		opts.VerifyAttempts = attempts
+		opts.VerifyInterval = interval
+	}
+}
+
+// WithIdempotencyKey set the key identifying the operation across retries. Once an operation with the key succeeded,
+// the client returns its result to the operations with the same key instead of submitting them again.
+func WithIdempotencyKey(key string) Option {
+	return func(opts *Opts) {
+		opts.IdempotencyKey = key
+	}
+}
+
+// WithDIDWeb publish the equivalent did:web document of the created DID for the domain path, such as
+// example.com/users/alice, with the did:web publisher of the client
+func WithDIDWeb(domainPath string) Option {
+	return func(opts *Opts) {
+		opts.DIDWebPath = domainPath
+	}
+}
@@ -16,6 +16,15 @@ type Opts struct {
 	SidetreeEndpoints []*models.Endpoint
 	SigningKey        crypto.PrivateKey
 	SigningKeyID      string
+	// SigningKeySet are the keys the signing key is selected from, by resolving the DID, if no signing key is set
+	SigningKeySet []crypto.PrivateKey
+	// EncryptedSigningKey is the PEM of the encrypted signing key, decrypted with SigningKeyPassphrase
+	EncryptedSigningKey  []byte
+	SigningKeyPassphrase []byte
+	// RequestHeaders are the headers added to the sidetree request
+	RequestHeaders map[string]string
+	// IdempotencyKey identifies the operation across retries
+	IdempotencyKey string
 }
 
 // Option is a deactivate DID option
@@ -36,9 +45,45 @@ func WithSigningKey(signingKey crypto.PrivateKey) Option {
 	}
 }
 
+// WithEncryptedSigningKey set the signing key from the PEM of an encrypted PKCS#8 private key, or of a legacy
+// encrypted PEM block, decrypted with the passphrase
+func WithEncryptedSigningKey(pemBytes, passphrase []byte) Option {
+	return func(opts *Opts) {
+		opts.EncryptedSigningKey = pemBytes
+		opts.SigningKeyPassphrase = passphrase
+	}
+}
+
+// WithSigningKeySet set the keys the signing key is selected from when no signing key is set. The DID is resolved
+// and the key which its active commitment commits to is used.
+func WithSigningKeySet(keys ...crypto.PrivateKey) Option {
+	return func(opts *Opts) {
+		opts.SigningKeySet = append(opts.SigningKeySet, keys...)
+	}
+}
+
 // WithSigningKeyID set signing key id
 func WithSigningKeyID(id string) Option {
 	return func(opts *Opts) {
 		opts.SigningKeyID = id
 	}
 }
+
+// WithRequestHeader add a header to the sidetree request, such as a correlation ID
+func WithRequestHeader(name, value string) Option {
+	return func(opts *Opts) {
+		if opts.RequestHeaders == nil {
+			opts.RequestHeaders = make(map[string]string)
+		}
+
+		opts.RequestHeaders[name] = value
+	}
+}
+
+// WithIdempotencyKey set the key identifying the operation across retries. Once an operation with the key succeeded,
+// the client returns its result to the operations with the same key instead of submitting them again.
+func WithIdempotencyKey(key string) Option {
+	return func(opts *Opts) {
+		opts.IdempotencyKey = key
+	}
+}
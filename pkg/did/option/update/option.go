@@ -7,6 +7,7 @@ package update
 
 import (
 	"crypto"
+	"time"
 
 	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 
@@ -19,14 +20,30 @@ type Option func(opts *Opts)
 
 // Opts update did opts
 type Opts struct {
-	AddPublicKeys       []doc.PublicKey
-	AddServices         []docdid.Service
-	RemovePublicKeys    []string
-	RemoveServices      []string
+	AddPublicKeys    []doc.PublicKey
+	AddServices      []docdid.Service
+	RemovePublicKeys []string
+	RemoveServices   []string
+	// ReplacedPublicKeys are the IDs of the public keys both removed and added, replacing their key material
+	ReplacedPublicKeys  []string
 	SidetreeEndpoints   []*models.Endpoint
 	NextUpdatePublicKey crypto.PublicKey
 	SigningKey          crypto.PrivateKey
 	SigningKeyID        string
+	// SigningKeySet are the keys the signing key is selected from, by resolving the DID, if no signing key is set
+	SigningKeySet []crypto.PrivateKey
+	// EncryptedSigningKey is the PEM of the encrypted signing key, decrypted with SigningKeyPassphrase
+	EncryptedSigningKey  []byte
+	SigningKeyPassphrase []byte
+	// RequestHeaders are the headers added to the sidetree request
+	RequestHeaders map[string]string
+	// IdempotencyKey identifies the operation across retries
+	IdempotencyKey string
+	// DIDWebPath is the domain path the equivalent did:web document is published for, if set, once the updated
+	// document is resolved up to DIDWebAttempts times every DIDWebInterval
+	DIDWebPath     string
+	DIDWebAttempts int
+	DIDWebInterval time.Duration
 }
 
 // WithAddPublicKey set public key to be added
@@ -50,6 +67,15 @@ func WithRemovePublicKey(publicKeyID string) Option {
 	}
 }
 
+// WithReplacePublicKey removes the public key of the same ID and adds the given one in its place
+func WithReplacePublicKey(publicKey *doc.PublicKey) Option {
+	return func(opts *Opts) {
+		opts.RemovePublicKeys = append(opts.RemovePublicKeys, publicKey.ID)
+		opts.AddPublicKeys = append(opts.AddPublicKeys, *publicKey)
+		opts.ReplacedPublicKeys = append(opts.ReplacedPublicKeys, publicKey.ID)
+	}
+}
+
 // WithRemoveService set remove service id
 func WithRemoveService(serviceID string) Option {
 	return func(opts *Opts) {
@@ -71,6 +97,29 @@ func WithSigningKey(signingKey crypto.PrivateKey) Option {
 	}
 }
 
+// WithEncryptedSigningKey set the signing key from the PEM of an encrypted PKCS#8 private key, or of a legacy
+// encrypted PEM block, decrypted with the passphrase
+func WithEncryptedSigningKey(pemBytes, passphrase []byte) Option {
+	return func(opts *Opts) {
+		opts.EncryptedSigningKey = pemBytes
+		opts.SigningKeyPassphrase = passphrase
+	}
+}
+
+// WithSigningKeySet set the keys the signing key is selected from when no signing key is set. The DID is resolved
+// and the key which its active commitment commits to is used.
+func WithSigningKeySet(keys ...crypto.PrivateKey) Option {
+	return func(opts *Opts) {
+		opts.SigningKeySet = append(opts.SigningKeySet, keys...)
+	}
+}
+
+// WithSigningKeys set the candidate signing keys, the one the active update commitment of the DID commits to signs
+// the update, which eases the races with a rotation of the update key. It is an alias of WithSigningKeySet.
+func WithSigningKeys(keys ...crypto.PrivateKey) Option {
+	return WithSigningKeySet(keys...)
+}
+
 // WithSigningKeyID set signing key id
 func WithSigningKeyID(id string) Option {
 	return func(opts *Opts) {
@@ -85,3 +134,34 @@ func WithSidetreeEndpoint(sidetreeEndpoint string) Option {
 			&models.Endpoint{URL: sidetreeEndpoint})
 	}
 }
+
+// WithRequestHeader add a header to the sidetree request, such as a correlation ID
+func WithRequestHeader(name, value string) Option {
+	return func(opts *Opts) {
+		if opts.RequestHeaders == nil {
+			opts.RequestHeaders = make(map[string]string)
+		}
+
+		opts.RequestHeaders[name] = value
+	}
+}
+
+// WithIdempotencyKey set the key identifying the operation across retries. Once an operation with the key succeeded,
+// the client returns its result to the operations with the same key instead of submitting them again.
+func WithIdempotencyKey(key string) Option {
+	return func(opts *Opts) {
+		opts.IdempotencyKey = key
+	}
+}
+
+// WithDIDWeb publish the equivalent did:web document of the updated DID for the domain path, such as
+// example.com/users/alice, with the did:web publisher of the client. The document is published once the update is
+// anchored, the DID being resolved at the sidetree endpoint up to attempts times, every interval, until its document
+// has the added public keys and services and no longer has the removed ones.
+func WithDIDWeb(domainPath string, attempts int, interval time.Duration) Option {
+	return func(opts *Opts) {
+		opts.DIDWebPath = domainPath
+		opts.DIDWebAttempts = attempts
+		opts.DIDWebInterval = interval
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pkcs8 parses password-protected private keys: encrypted PKCS#8 keys (PBES2 with PBKDF2 and AES-CBC, as
+// written by openssl pkcs8 -topk8 -v2) and legacy encrypted PEM blocks.
+package pkcs8
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha1" //nolint: gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// EncryptedPEMType is the PEM type of an encrypted PKCS#8 private key
+	EncryptedPEMType = "ENCRYPTED PRIVATE KEY"
+
+	saltSize   = 16
+	iterations = 100000
+	keySize    = 32
+	// maxIterations bounds the iteration count read from the keys, which would otherwise hang the key derivation
+	maxIterations = 10 * iterations
+)
+
+// nolint: gochecknoglobals
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// ParsePrivateKeyPEM parses the Ed25519 or ECDSA private key of the PEM. An encrypted PKCS#8 key, or a legacy
+// encrypted PEM block, is decrypted with the passphrase.
+func ParsePrivateKeyPEM(pemBytes, passphrase []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("private key not found in PEM")
+	}
+
+	der := block.Bytes
+
+	switch {
+	case block.Type == EncryptedPEMType:
+		var err error
+
+		der, err = Decrypt(block.Bytes, passphrase)
+		if err != nil {
+			return nil, err
+		}
+	case x509.IsEncryptedPEMBlock(block): //nolint: staticcheck
+		var err error
+
+		der, err = x509.DecryptPEMBlock(block, passphrase) //nolint: staticcheck
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+		}
+	}
+
+	return parsePrivateKey(der)
+}
+
+// Decrypt decrypts the DER of an encrypted PKCS#8 private key with the passphrase, and returns the DER of the
+// PKCS#8 private key
+func Decrypt(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted private key: %w", err)
+	}
+
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("encryption algorithm %s not supported, only PBES2 is",
+			info.EncryptionAlgorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse PBES2 parameters: %w", err)
+	}
+
+	block, iv, err := newBlockCipher(params, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid encrypted private key length")
+	}
+
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.EncryptedData)
+
+	plaintext, err = unpad(plaintext, block.BlockSize())
+	if err != nil {
+		return nil, errors.New("failed to decrypt private key, the passphrase may be wrong")
+	}
+
+	return plaintext, nil
+}
+
+// Encrypt encrypts the DER of a PKCS#8 private key with a key derived from the passphrase, with PBES2, PBKDF2
+// with HMAC-SHA256 and AES-256-CBC, and returns the DER of the encrypted PKCS#8 private key
+func Encrypt(der, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	iv := make([]byte, aes.BlockSize)
+
+	for _, b := range [][]byte{salt, iv} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+	}
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{Salt: salt, IterationCount: iterations, KeyLength: keySize,
+		PRF: pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue}})
+	if err != nil {
+		return nil, err
+	}
+
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key(passphrase, salt, iterations, keySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := pad(der, block.BlockSize())
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		EncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData:       ciphertext,
+	})
+}
+
+// newBlockCipher returns the AES cipher keyed with the key derived from the passphrase, and the IV of the scheme
+func newBlockCipher(params pbes2Params, passphrase []byte) (cipher.Block, []byte, error) {
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, fmt.Errorf("key derivation function %s not supported, only PBKDF2 is",
+			params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse PBKDF2 parameters: %w", err)
+	}
+
+	if err := checkIterationCount(kdfParams.IterationCount); err != nil {
+		return nil, nil, err
+	}
+
+	prf, err := prfHash(kdfParams.PRF.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size, err := aesKeySize(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var iv []byte
+	if _, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse encryption scheme IV: %w", err)
+	}
+
+	if len(iv) != aes.BlockSize {
+		return nil, nil, errors.New("invalid encryption scheme IV length")
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key(passphrase, kdfParams.Salt, kdfParams.IterationCount, size, prf))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, iv, nil
+}
+
+func checkIterationCount(count int) error {
+	if count < 1 || count > maxIterations {
+		return fmt.Errorf("PBKDF2 iteration count must be between 1 and %d", maxIterations)
+	}
+
+	return nil
+}
+
+func prfHash(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHMACWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	default:
+		return nil, fmt.Errorf("PBKDF2 pseudorandom function %s not supported", oid)
+	}
+}
+
+func aesKeySize(oid asn1.ObjectIdentifier) (int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, nil //nolint: gomnd
+	case oid.Equal(oidAES192CBC):
+		return 24, nil //nolint: gomnd
+	case oid.Equal(oidAES256CBC):
+		return 32, nil //nolint: gomnd
+	default:
+		return 0, fmt.Errorf("encryption scheme %s not supported", oid)
+	}
+}
+
+func pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+
+	padded := make([]byte, len(data), len(data)+n)
+	copy(padded, data)
+
+	for i := 0; i < n; i++ {
+		padded = append(padded, byte(n))
+	}
+
+	return padded
+}
+
+func unpad(data []byte, blockSize int) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize {
+		return nil, errors.New("invalid padding")
+	}
+
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, errors.New("invalid padding")
+		}
+	}
+
+	return data[:len(data)-n], nil
+}
+
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		switch key := key.(type) {
+		case ed25519.PrivateKey, *ecdsa.PrivateKey:
+			return key, nil
+		default:
+			return nil, errors.New("found unknown private key type in PKCS#8 wrapping")
+		}
+	}
+
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("failed to parse private key")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the sidetree requests of a client to a rate, with bursts of up to burst
+// requests. It tracks the time the bucket would be empty again at the rate, rather than a count of tokens.
+type rateLimiter struct {
+	interval  time.Duration
+	tolerance time.Duration
+	now       func() time.Time
+
+	mutex sync.Mutex
+	next  time.Time
+}
+
+func newRateLimiter(opsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	interval := time.Duration(float64(time.Second) / opsPerSecond)
+
+	return &rateLimiter{interval: interval, tolerance: time.Duration(burst-1) * interval, now: time.Now}
+}
+
+// reserve takes a token from the bucket, and returns how long to wait for it to be available
+func (l *rateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	delay := l.next.Sub(now) - l.tolerance
+	l.next = l.next.Add(l.interval)
+
+	if delay < 0 {
+		return 0
+	}
+
+	return delay
+}
+
+// throttle waits until the rate limit of the client, if any, allows a request to the endpoint
+func (c *Client) throttle(endpointURL string) {
+	if c.rateLimiter == nil {
+		return
+	}
+
+	if delay := c.rateLimiter.reserve(); delay > 0 {
+		c.logger.Debug("sidetree request rate limited", "endpoint", endpointURL, "delay", delay)
+
+		time.Sleep(delay)
+	}
+}
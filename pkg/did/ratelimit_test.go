@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("test burst and rate", func(t *testing.T) {
+		now := time.Now()
+
+		l := newRateLimiter(10, 3)
+		l.now = func() time.Time { return now }
+
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, 100*time.Millisecond, l.reserve())
+		require.Equal(t, 200*time.Millisecond, l.reserve())
+
+		now = now.Add(time.Second)
+
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, 100*time.Millisecond, l.reserve())
+	})
+
+	t.Run("test no burst", func(t *testing.T) {
+		now := time.Now()
+
+		l := newRateLimiter(2, 0)
+		l.now = func() time.Time { return now }
+
+		require.Equal(t, time.Duration(0), l.reserve())
+		require.Equal(t, 500*time.Millisecond, l.reserve())
+
+		now = now.Add(250 * time.Millisecond)
+
+		require.Equal(t, 750*time.Millisecond, l.reserve())
+	})
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test requests throttled", func(t *testing.T) {
+		v := New(WithRateLimit(20, 2))
+
+		start := time.Now()
+
+		for i := 0; i < 4; i++ {
+			err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+				deactivate.WithSigningKey(privateKey))
+			require.NoError(t, err)
+		}
+
+		require.GreaterOrEqual(t, int64(time.Since(start)), int64(90*time.Millisecond))
+	})
+
+	t.Run("test no limit", func(t *testing.T) {
+		v := New(WithRateLimit(20, 2), WithRateLimit(0, 0))
+		require.Nil(t, v.rateLimiter)
+	})
+}
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+)
+
+const (
+	multihashSHA2256 = 18
+	multihashSHA2512 = 19
+)
+
+// document holds the IDs of the public keys and services of a replayed document
+type document struct {
+	publicKeys []string
+	services   []string
+}
+
+// applyPatches applies the patches of a delta, either of the 0.1 protocol or of DIF Sidetree v1, which name the
+// service patches and properties differently
+func (d *document) applyPatches(patches []map[string]interface{}) error {
+	for _, patch := range patches {
+		if err := d.applyPatch(patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *document) applyPatch(patch map[string]interface{}) error {
+	var err error
+
+	switch patch["action"] {
+	case "replace":
+		replaced, _ := patch["document"].(map[string]interface{}) //nolint: errcheck
+
+		if d.publicKeys, err = elementIDs(property(replaced, "publicKeys", "publicKey")); err != nil {
+			return err
+		}
+
+		d.services, err = elementIDs(property(replaced, "services", "service"))
+	case "add-public-keys":
+		var ids []string
+
+		if ids, err = elementIDs(patch["publicKeys"]); err == nil {
+			d.publicKeys = addIDs(d.publicKeys, ids)
+		}
+	case "remove-public-keys":
+		d.publicKeys, err = removeIDs(d.publicKeys, property(patch, "ids", "publicKeys"))
+	case "add-service-endpoints", "add-services":
+		var ids []string
+
+		if ids, err = elementIDs(property(patch, "services", "serviceEndpoints")); err == nil {
+			d.services = addIDs(d.services, ids)
+		}
+	case "remove-service-endpoints", "remove-services":
+		d.services, err = removeIDs(d.services, patch["ids"])
+	default:
+		return fmt.Errorf("unsupported patch action %v", patch["action"])
+	}
+
+	return err
+}
+
+// property returns the first property of the object set with one of the names
+func property(object map[string]interface{}, names ...string) interface{} {
+	for _, name := range names {
+		if value, ok := object[name]; ok {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// elementIDs returns the IDs of the public keys or services of the value of a patch property
+func elementIDs(value interface{}) ([]string, error) {
+	elements, ok := value.([]interface{})
+	if !ok && value != nil {
+		return nil, errors.New("patch elements are not an array")
+	}
+
+	var ids []string
+
+	for _, element := range elements {
+		object, _ := element.(map[string]interface{}) //nolint: errcheck
+
+		id, ok := object["id"].(string)
+		if !ok || id == "" {
+			return nil, errors.New("patch element has no id")
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// addIDs adds the IDs which are not in the IDs yet, the elements with an ID already in them replacing the existing
+// ones
+func addIDs(ids, added []string) []string {
+	result := append([]string(nil), ids...)
+
+	for _, id := range added {
+		if !containsID(result, id) {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+// removeIDs removes the IDs of the value of a patch property from the IDs
+func removeIDs(ids []string, value interface{}) ([]string, error) {
+	removed, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("patch ids are not an array")
+	}
+
+	var result []string
+
+	for _, id := range ids {
+		if !containsID(removed, id) {
+			result = append(result, id)
+		}
+	}
+
+	return result, nil
+}
+
+func containsID(ids interface{}, id string) bool {
+	switch values := ids.(type) {
+	case []string:
+		for _, value := range values {
+			if value == id {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, value := range values {
+			if value == id {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// multihashCode returns the multihash algorithm of the base64url encoded multihash
+func multihashCode(encoded string) (uint, error) {
+	multihash, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode multihash: %w", err)
+	}
+
+	if len(multihash) < 2 || int(multihash[1]) != len(multihash)-2 {
+		return 0, errors.New("not a multihash")
+	}
+
+	return uint(multihash[0]), nil
+}
+
+// canonicalMultihash returns the base64url encoded multihash of the JCS canonical JSON of the value
+func canonicalMultihash(value interface{}, code uint) (string, error) {
+	canonical, err := docutil.MarshalCanonical(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize: %w", err)
+	}
+
+	var digest []byte
+
+	switch code {
+	case multihashSHA2256:
+		sum := sha256.Sum256(canonical)
+		digest = sum[:]
+	case multihashSHA2512:
+		sum := sha512.Sum512(canonical)
+		digest = sum[:]
+	default:
+		return "", fmt.Errorf("multihash algorithm %d not supported", code)
+	}
+
+	multihash := append([]byte{byte(code), byte(len(digest))}, digest...)
+
+	return base64.RawURLEncoding.EncodeToString(multihash), nil
+}
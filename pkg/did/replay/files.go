@@ -0,0 +1,181 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const ipfsScheme = "ipfs://"
+
+// Fetcher fetches the content of the batch files from the CAS
+type Fetcher interface {
+	Fetch(address string) ([]byte, error)
+}
+
+// GatewayFetcher fetches the batch files from an IPFS HTTP gateway
+type GatewayFetcher struct {
+	gatewayURL string
+	client     *http.Client
+}
+
+// NewGatewayFetcher returns a fetcher of the batch files from the IPFS HTTP gateway, such as https://ipfs.io, with
+// the HTTP client
+func NewGatewayFetcher(gatewayURL string, client *http.Client) *GatewayFetcher {
+	return &GatewayFetcher{gatewayURL: strings.TrimSuffix(gatewayURL, "/"), client: client}
+}
+
+// Fetch fetches the content of the CAS address, decompressing it if it's compressed with gzip as the batch files are
+func (f *GatewayFetcher) Fetch(address string) ([]byte, error) {
+	url := f.gatewayURL + "/ipfs/" + strings.TrimPrefix(address, ipfsScheme)
+
+	resp, err := f.client.Get(url) //nolint: noctx
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+
+	if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d: %s", url, resp.StatusCode, content)
+	}
+
+	return decompress(content)
+}
+
+// anchorFile is the anchor file of a batch, with the create, recover and deactivate operations
+type anchorFile struct {
+	MapFileURI string          `json:"mapFileUri"`
+	Operations batchOperations `json:"operations"`
+}
+
+// mapFile is the map file of a batch, with the update operations and the chunk file of the deltas
+type mapFile struct {
+	Chunks []struct {
+		ChunkFileURI string `json:"chunkFileUri"`
+	} `json:"chunks"`
+	Operations batchOperations `json:"operations"`
+}
+
+// chunkFile has the deltas of the create, recover and update operations of a batch, in that order
+type chunkFile struct {
+	Deltas []json.RawMessage `json:"deltas"`
+}
+
+type batchOperations struct {
+	Create []struct {
+		SuffixData json.RawMessage `json:"suffixData"`
+	} `json:"create"`
+	Recover    []signedOperation `json:"recover"`
+	Update     []signedOperation `json:"update"`
+	Deactivate []signedOperation `json:"deactivate"`
+}
+
+type signedOperation struct {
+	DIDSuffix  string `json:"didSuffix"`
+	SignedData string `json:"signedData"`
+}
+
+// batch holds the files of an anchored batch
+type batch struct {
+	anchor     string
+	anchorFile *anchorFile
+	mapFile    *mapFile
+	chunkFile  *chunkFile
+}
+
+// fetchBatch fetches the anchor file of the anchor string, which is the number of operations and the address of the
+// anchor file separated by a dot, and its map file
+func fetchBatch(fetcher Fetcher, anchor string) (*batch, error) {
+	address := anchor
+	if i := strings.Index(anchor, "."); i >= 0 {
+		address = anchor[i+1:]
+	}
+
+	b := &batch{anchor: anchor, anchorFile: &anchorFile{}, mapFile: &mapFile{}}
+
+	if err := fetchFile(fetcher, address, b.anchorFile); err != nil {
+		return nil, fmt.Errorf("anchor file of %s: %w", anchor, err)
+	}
+
+	// batches of deactivate operations only have no map file
+	if b.anchorFile.MapFileURI == "" {
+		return b, nil
+	}
+
+	if err := fetchFile(fetcher, b.anchorFile.MapFileURI, b.mapFile); err != nil {
+		return nil, fmt.Errorf("map file of %s: %w", anchor, err)
+	}
+
+	return b, nil
+}
+
+// delta returns the delta at the index of the chunk file of the batch, fetching the chunk file the first time
+func (b *batch) delta(fetcher Fetcher, index int) (json.RawMessage, error) {
+	if b.chunkFile == nil {
+		if len(b.mapFile.Chunks) == 0 {
+			return nil, fmt.Errorf("map file of %s has no chunk file", b.anchor)
+		}
+
+		b.chunkFile = &chunkFile{}
+
+		if err := fetchFile(fetcher, b.mapFile.Chunks[0].ChunkFileURI, b.chunkFile); err != nil {
+			return nil, fmt.Errorf("chunk file of %s: %w", b.anchor, err)
+		}
+	}
+
+	if index >= len(b.chunkFile.Deltas) {
+		return nil, nil
+	}
+
+	return b.chunkFile.Deltas[index], nil
+}
+
+func fetchFile(fetcher Fetcher, address string, file interface{}) error {
+	content, err := fetcher.Fetch(address)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(content, file); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// decompress decompresses the content if it's compressed with gzip
+func decompress(content []byte) ([]byte, error) {
+	if !bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		return content, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+
+	return decompressed, nil
+}
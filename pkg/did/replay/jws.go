@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+const (
+	p256SignatureSize = 64
+	compactJWSParts   = 3
+)
+
+// errInvalidSignature is the reason of the operations whose signed data isn't signed by the key it reveals
+var errInvalidSignature = errors.New("invalid signature")
+
+// parseSignedData parses the payload of the compact JWS of the signed data of an operation, without verifying it
+func parseSignedData(signedData string, payload interface{}) error {
+	parts := strings.Split(signedData, ".")
+	if len(parts) != compactJWSParts {
+		return errors.New("signed data is not a compact JWS")
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("failed to decode signed data payload: %w", err)
+	}
+
+	if err = json.Unmarshal(payloadBytes, payload); err != nil {
+		return fmt.Errorf("failed to unmarshal signed data payload: %w", err)
+	}
+
+	return nil
+}
+
+// verifySignedData verifies the signature of the compact JWS of the signed data with the JWK, an Ed25519 or P-256 key
+func verifySignedData(signedData string, key *jws.JWK) error {
+	i := strings.LastIndex(signedData, ".")
+	if i < 0 {
+		return errors.New("signed data is not a compact JWS")
+	}
+
+	signingInput := []byte(signedData[:i])
+
+	signature, err := base64.RawURLEncoding.DecodeString(signedData[i+1:])
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return fmt.Errorf("failed to decode key: %w", err)
+	}
+
+	switch {
+	case key.Kty == "OKP" && key.Crv == "Ed25519" && len(x) == ed25519.PublicKeySize:
+		if !ed25519.Verify(x, signingInput, signature) {
+			return errInvalidSignature
+		}
+	case key.Kty == "EC" && key.Crv == "P-256":
+		return verifyP256(signingInput, signature, x, key.Y)
+	default:
+		return fmt.Errorf("unsupported key %s %s", key.Kty, key.Crv)
+	}
+
+	return nil
+}
+
+// verifyP256 verifies the ES256 signature, in the JWS R||S format, with the P-256 key of the coordinates
+func verifyP256(signingInput, signature, x []byte, encodedY string) error {
+	y, err := base64.RawURLEncoding.DecodeString(encodedY)
+	if err != nil {
+		return fmt.Errorf("failed to decode key: %w", err)
+	}
+
+	if len(signature) != p256SignatureSize {
+		return errInvalidSignature
+	}
+
+	publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	digest := sha256.Sum256(signingInput)
+	r := new(big.Int).SetBytes(signature[:p256SignatureSize/2])
+	s := new(big.Int).SetBytes(signature[p256SignatureSize/2:])
+
+	if !ecdsa.Verify(publicKey, digest[:], r, s) {
+		return errInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package replay verifies DIDs independently of the resolvers, for auditors trusting only the anchors of the ledger.
+// The anchor, map and chunk files of the batches of the anchors are fetched from the CAS and the operations of the
+// DID are replayed, checking their commitments, signatures and delta hashes, to the state the resolution of the DID
+// is compared with.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+// types of the operations
+const (
+	TypeCreate     = "create"
+	TypeUpdate     = "update"
+	TypeRecover    = "recover"
+	TypeDeactivate = "deactivate"
+)
+
+// emptyDocumentError is the reason of the create and recover operations whose delta is invalid, which are applied
+// with an empty document and no update commitment
+type emptyDocumentError struct {
+	err error
+}
+
+func (e *emptyDocumentError) Error() string {
+	return "applied with an empty document: " + e.err.Error()
+}
+
+func (e *emptyDocumentError) Unwrap() error {
+	return e.err
+}
+
+// Operation is an operation of the DID found in the batch of an anchor
+type Operation struct {
+	Anchor string `json:"anchor" yaml:"anchor"`
+	Type   string `json:"type" yaml:"type"`
+	// Applied is whether the operation was applied to the state of the DID. Invalid operations, such as the ones
+	// whose signing key doesn't match the active commitment, are ignored as the resolvers ignore them.
+	Applied bool `json:"applied" yaml:"applied"`
+	// Reason is why the operation was ignored, or applied with an empty document
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// State is the state of a DID replayed from its operations
+type State struct {
+	DIDSuffix   string
+	Operations  []Operation
+	Created     bool
+	Deactivated bool
+	// PublicKeys and Services are the IDs of the public keys and services of the document
+	PublicKeys         []string
+	Services           []string
+	UpdateCommitment   string
+	RecoveryCommitment string
+}
+
+// Replay fetches the batch files of the anchors with the fetcher and replays the operations of the DID with the
+// suffix found in them. The anchors are anchor strings, the number of operations and the address of the anchor file
+// separated by a dot, in the order of their transactions on the ledger.
+func Replay(fetcher Fetcher, didSuffix string, anchors []string) (*State, error) {
+	s := &State{DIDSuffix: didSuffix}
+
+	for _, anchor := range anchors {
+		b, err := fetchBatch(fetcher, anchor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch batch: %w", err)
+		}
+
+		if err = s.replayBatch(fetcher, b); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// replayBatch replays the operations of the DID in the batch, the deltas of the chunk file being those of the
+// create, recover and update operations in that order
+func (s *State) replayBatch(fetcher Fetcher, b *batch) error {
+	ops := &b.anchorFile.Operations
+
+	for i := range ops.Create {
+		if computeSuffix(ops.Create[i].SuffixData) != s.DIDSuffix {
+			continue
+		}
+
+		delta, err := b.delta(fetcher, i)
+		if err != nil {
+			return err
+		}
+
+		s.apply(b.anchor, TypeCreate, s.create(ops.Create[i].SuffixData, delta))
+	}
+
+	for i := range ops.Recover {
+		if ops.Recover[i].DIDSuffix != s.DIDSuffix {
+			continue
+		}
+
+		delta, err := b.delta(fetcher, len(ops.Create)+i)
+		if err != nil {
+			return err
+		}
+
+		s.apply(b.anchor, TypeRecover, s.recover(ops.Recover[i].SignedData, delta))
+	}
+
+	for i := range ops.Deactivate {
+		if ops.Deactivate[i].DIDSuffix == s.DIDSuffix {
+			s.apply(b.anchor, TypeDeactivate, s.deactivate(ops.Deactivate[i].SignedData))
+		}
+	}
+
+	updates := b.mapFile.Operations.Update
+
+	for i := range updates {
+		if updates[i].DIDSuffix != s.DIDSuffix {
+			continue
+		}
+
+		delta, err := b.delta(fetcher, len(ops.Create)+len(ops.Recover)+i)
+		if err != nil {
+			return err
+		}
+
+		s.apply(b.anchor, TypeUpdate, s.update(updates[i].SignedData, delta))
+	}
+
+	return nil
+}
+
+func (s *State) apply(anchor, operationType string, err error) {
+	var emptyDocument *emptyDocumentError
+
+	op := Operation{Anchor: anchor, Type: operationType, Applied: err == nil || errors.As(err, &emptyDocument)}
+	if err != nil {
+		op.Reason = err.Error()
+	}
+
+	s.Operations = append(s.Operations, op)
+}
+
+func (s *State) create(suffixData, delta json.RawMessage) error {
+	if s.Created {
+		return errors.New("DID already created")
+	}
+
+	var suffix struct {
+		DeltaHash          string `json:"deltaHash"`
+		RecoveryCommitment string `json:"recoveryCommitment"`
+	}
+
+	if err := json.Unmarshal(suffixData, &suffix); err != nil {
+		return fmt.Errorf("failed to unmarshal suffix data: %w", err)
+	}
+
+	s.Created = true
+	s.RecoveryCommitment = suffix.RecoveryCommitment
+
+	return s.replaceDocument(delta, suffix.DeltaHash)
+}
+
+func (s *State) update(signedData string, delta json.RawMessage) error {
+	if err := s.checkActive(); err != nil {
+		return err
+	}
+
+	var payload struct {
+		UpdateKey *jws.JWK `json:"updateKey"`
+		DeltaHash string   `json:"deltaHash"`
+	}
+
+	if err := parseSignedData(signedData, &payload); err != nil {
+		return err
+	}
+
+	if err := verifyRevealedKey(signedData, payload.UpdateKey, s.UpdateCommitment); err != nil {
+		return err
+	}
+
+	d, err := parseDelta(delta, payload.DeltaHash)
+	if err != nil {
+		return err
+	}
+
+	doc := &document{publicKeys: s.PublicKeys, services: s.Services}
+
+	if err = doc.applyPatches(d.Patches); err != nil {
+		return err
+	}
+
+	s.PublicKeys, s.Services, s.UpdateCommitment = doc.publicKeys, doc.services, d.UpdateCommitment
+
+	return nil
+}
+
+func (s *State) recover(signedData string, delta json.RawMessage) error {
+	if err := s.checkActive(); err != nil {
+		return err
+	}
+
+	var payload struct {
+		RecoveryCommitment string   `json:"recoveryCommitment"`
+		RecoveryKey        *jws.JWK `json:"recoveryKey"`
+		DeltaHash          string   `json:"deltaHash"`
+	}
+
+	if err := parseSignedData(signedData, &payload); err != nil {
+		return err
+	}
+
+	if err := verifyRevealedKey(signedData, payload.RecoveryKey, s.RecoveryCommitment); err != nil {
+		return err
+	}
+
+	s.RecoveryCommitment = payload.RecoveryCommitment
+
+	return s.replaceDocument(delta, payload.DeltaHash)
+}
+
+func (s *State) deactivate(signedData string) error {
+	if err := s.checkActive(); err != nil {
+		return err
+	}
+
+	var payload struct {
+		DIDSuffix   string   `json:"didSuffix"`
+		RecoveryKey *jws.JWK `json:"recoveryKey"`
+	}
+
+	if err := parseSignedData(signedData, &payload); err != nil {
+		return err
+	}
+
+	if err := verifyRevealedKey(signedData, payload.RecoveryKey, s.RecoveryCommitment); err != nil {
+		return err
+	}
+
+	if payload.DIDSuffix != s.DIDSuffix {
+		return fmt.Errorf("signed data is for DID suffix %s", payload.DIDSuffix)
+	}
+
+	s.Deactivated = true
+
+	return nil
+}
+
+// replaceDocument replaces the document and the update commitment with the ones of the delta of a create or recover
+// operation, the document being empty and the update commitment unset if the delta is invalid
+func (s *State) replaceDocument(delta json.RawMessage, deltaHash string) error {
+	s.PublicKeys, s.Services, s.UpdateCommitment = nil, nil, ""
+
+	d, err := parseDelta(delta, deltaHash)
+	if err != nil {
+		return &emptyDocumentError{err: err}
+	}
+
+	doc := &document{}
+
+	if err = doc.applyPatches(d.Patches); err != nil {
+		return &emptyDocumentError{err: err}
+	}
+
+	s.PublicKeys, s.Services, s.UpdateCommitment = doc.publicKeys, doc.services, d.UpdateCommitment
+
+	return nil
+}
+
+func (s *State) checkActive() error {
+	if !s.Created {
+		return errors.New("DID not created")
+	}
+
+	if s.Deactivated {
+		return errors.New("DID deactivated")
+	}
+
+	return nil
+}
+
+// verifyRevealedKey checks that the key revealed by the signed data matches the active commitment and signed it
+func verifyRevealedKey(signedData string, revealed *jws.JWK, activeCommitment string) error {
+	if revealed == nil {
+		return errors.New("signed data has no key")
+	}
+
+	code, err := multihashCode(activeCommitment)
+	if err != nil {
+		return fmt.Errorf("invalid active commitment: %w", err)
+	}
+
+	value, err := commitment.Calculate(revealed, code)
+	if err != nil {
+		return fmt.Errorf("failed to calculate commitment: %w", err)
+	}
+
+	if value != activeCommitment {
+		return errors.New("key doesn't match the active commitment")
+	}
+
+	return verifySignedData(signedData, revealed)
+}
+
+type delta struct {
+	Patches          []map[string]interface{} `json:"patches"`
+	UpdateCommitment string                   `json:"updateCommitment"`
+}
+
+// parseDelta parses the delta and checks that its hash is the delta hash
+func parseDelta(deltaBytes json.RawMessage, deltaHash string) (*delta, error) {
+	if len(deltaBytes) == 0 {
+		return nil, errors.New("delta missing from the chunk file")
+	}
+
+	code, err := multihashCode(deltaHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delta hash: %w", err)
+	}
+
+	var value interface{}
+
+	if err = json.Unmarshal(deltaBytes, &value); err != nil {
+		return nil, fmt.Errorf("invalid delta: %w", err)
+	}
+
+	hash, err := canonicalMultihash(value, code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delta: %w", err)
+	}
+
+	if hash != deltaHash {
+		return nil, errors.New("hash of the delta doesn't match the delta hash")
+	}
+
+	d := &delta{}
+
+	if err = json.Unmarshal(deltaBytes, d); err != nil {
+		return nil, fmt.Errorf("invalid delta: %w", err)
+	}
+
+	return d, nil
+}
+
+// computeSuffix returns the unique suffix of the DID created with the suffix data, the multihash of its canonical
+// JSON with the algorithm of its delta hash, or an empty suffix if the suffix data is invalid
+func computeSuffix(suffixData json.RawMessage) string {
+	var value map[string]interface{}
+
+	if err := json.Unmarshal(suffixData, &value); err != nil {
+		return ""
+	}
+
+	deltaHash, _ := value["deltaHash"].(string) //nolint: errcheck
+
+	code, err := multihashCode(deltaHash)
+	if err != nil {
+		return ""
+	}
+
+	suffix, err := canonicalMultihash(value, code)
+	if err != nil {
+		return ""
+	}
+
+	return suffix
+}
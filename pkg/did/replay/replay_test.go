@@ -0,0 +1,243 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+)
+
+func TestReplay(t *testing.T) {
+	recoveryKey, updateKey, nextUpdateKey := newKey(t), newKey(t), newKey(t)
+
+	cas := casFetcher{}
+
+	createDelta := newDelta(t, updateKey, map[string]interface{}{"action": "replace",
+		"document": map[string]interface{}{"publicKeys": []interface{}{map[string]interface{}{"id": "key1"}},
+			"services": []interface{}{map[string]interface{}{"id": "svc1"}}}})
+	suffixData := map[string]interface{}{"deltaHash": hash(t, createDelta),
+		"recoveryCommitment": recoveryKey.commitment(t)}
+	otherSuffixData := map[string]interface{}{"deltaHash": hash(t, createDelta), "recoveryCommitment": "other"}
+
+	didSuffix := hash(t, suffixData)
+
+	cas.addBatch(t, "create", []interface{}{map[string]interface{}{"suffixData": otherSuffixData},
+		map[string]interface{}{"suffixData": suffixData}}, nil, nil, []interface{}{createDelta, createDelta})
+
+	updateDelta := newDelta(t, nextUpdateKey,
+		map[string]interface{}{"action": "add-public-keys", "publicKeys": []interface{}{
+			map[string]interface{}{"id": "key2"}}},
+		map[string]interface{}{"action": "remove-service-endpoints", "ids": []interface{}{"svc1"}})
+	update := signedOperation{DIDSuffix: didSuffix, SignedData: updateKey.sign(t,
+		map[string]interface{}{"updateKey": updateKey.jwk, "deltaHash": hash(t, updateDelta)})}
+
+	cas.addBatch(t, "update", nil, nil, []signedOperation{update}, []interface{}{updateDelta})
+	cas.addBatch(t, "replayed-update", nil, nil, []signedOperation{update}, []interface{}{updateDelta})
+
+	deactivate := signedOperation{DIDSuffix: didSuffix, SignedData: recoveryKey.sign(t,
+		map[string]interface{}{"didSuffix": didSuffix, "recoveryKey": recoveryKey.jwk})}
+
+	cas.addBatch(t, "deactivate", nil, []signedOperation{deactivate}, nil, nil)
+
+	t.Run("test replay", func(t *testing.T) {
+		s, err := Replay(cas, didSuffix, []string{"2.create", "1.update"})
+		require.NoError(t, err)
+		require.True(t, s.Created)
+		require.False(t, s.Deactivated)
+		require.Equal(t, []string{"key1", "key2"}, s.PublicKeys)
+		require.Empty(t, s.Services)
+		require.Equal(t, nextUpdateKey.commitment(t), s.UpdateCommitment)
+		require.Equal(t, recoveryKey.commitment(t), s.RecoveryCommitment)
+		require.Equal(t, []Operation{{Anchor: "2.create", Type: TypeCreate, Applied: true},
+			{Anchor: "1.update", Type: TypeUpdate, Applied: true}}, s.Operations)
+	})
+
+	t.Run("test invalid operations are ignored", func(t *testing.T) {
+		s, err := Replay(cas, didSuffix, []string{"1.update", "2.create", "1.update", "1.replayed-update",
+			"1.deactivate"})
+		require.NoError(t, err)
+		require.True(t, s.Deactivated)
+		require.Equal(t, []string{"key1", "key2"}, s.PublicKeys)
+
+		require.Len(t, s.Operations, 5)
+		require.False(t, s.Operations[0].Applied)
+		require.Equal(t, "DID not created", s.Operations[0].Reason)
+		require.False(t, s.Operations[3].Applied)
+		require.Equal(t, "key doesn't match the active commitment", s.Operations[3].Reason)
+		require.True(t, s.Operations[4].Applied)
+	})
+
+	t.Run("test invalid signature", func(t *testing.T) {
+		forged := signedOperation{DIDSuffix: didSuffix, SignedData: nextUpdateKey.sign(t,
+			map[string]interface{}{"updateKey": updateKey.jwk, "deltaHash": hash(t, updateDelta)})}
+
+		cas.addBatch(t, "forged-update", nil, nil, []signedOperation{forged}, []interface{}{updateDelta})
+
+		s, err := Replay(cas, didSuffix, []string{"2.create", "1.forged-update"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"key1"}, s.PublicKeys)
+		require.Equal(t, "invalid signature", s.Operations[1].Reason)
+	})
+
+	t.Run("test invalid create delta", func(t *testing.T) {
+		cas.addBatch(t, "invalid-delta", []interface{}{map[string]interface{}{"suffixData": suffixData}}, nil, nil,
+			[]interface{}{updateDelta})
+
+		s, err := Replay(cas, didSuffix, []string{"1.invalid-delta"})
+		require.NoError(t, err)
+		require.True(t, s.Created)
+		require.Empty(t, s.PublicKeys)
+		require.Empty(t, s.UpdateCommitment)
+		require.True(t, s.Operations[0].Applied)
+		require.Equal(t, "applied with an empty document: hash of the delta doesn't match the delta hash",
+			s.Operations[0].Reason)
+	})
+
+	t.Run("test missing batch file", func(t *testing.T) {
+		_, err := Replay(cas, didSuffix, []string{"1.missing"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to fetch batch: anchor file of 1.missing: missing not found")
+	})
+}
+
+func TestGatewayFetcher(t *testing.T) {
+	var compressed bytes.Buffer
+
+	w := gzip.NewWriter(&compressed)
+	_, err := w.Write([]byte(`{"mapFileUri":"map"}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/anchor" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err = w.Write(compressed.Bytes())
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	fetcher := NewGatewayFetcher(serv.URL+"/", http.DefaultClient)
+
+	content, err := fetcher.Fetch("ipfs://anchor")
+	require.NoError(t, err)
+	require.Equal(t, `{"mapFileUri":"map"}`, string(content))
+
+	_, err = fetcher.Fetch("other")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status 404")
+}
+
+type casFetcher map[string][]byte
+
+func (f casFetcher) Fetch(address string) ([]byte, error) {
+	content, ok := f[address]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", address)
+	}
+
+	return decompress(content)
+}
+
+// addBatch adds the anchor file, and the map and chunk files of the batch unless it only has deactivate operations
+func (f casFetcher) addBatch(t *testing.T, name string, creates []interface{}, deactivates,
+	updates []signedOperation, deltas []interface{}) {
+	t.Helper()
+
+	anchor := map[string]interface{}{"operations": map[string]interface{}{"create": creates,
+		"deactivate": deactivates}}
+
+	if deltas != nil {
+		anchor["mapFileUri"] = name + "-map"
+
+		f.add(t, name+"-map", map[string]interface{}{"chunks": []interface{}{
+			map[string]interface{}{"chunkFileUri": name + "-chunk"}},
+			"operations": map[string]interface{}{"update": updates}})
+		f.add(t, name+"-chunk", map[string]interface{}{"deltas": deltas})
+	}
+
+	f.add(t, name, anchor)
+}
+
+func (f casFetcher) add(t *testing.T, address string, file interface{}) {
+	t.Helper()
+
+	content, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	f[address] = content
+}
+
+type testKey struct {
+	privateKey ed25519.PrivateKey
+	jwk        *jws.JWK
+}
+
+func newKey(t *testing.T) *testKey {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := pubkey.GetPublicKeyJWK(publicKey)
+	require.NoError(t, err)
+
+	return &testKey{privateKey: privateKey, jwk: jwk}
+}
+
+func (k *testKey) commitment(t *testing.T) string {
+	t.Helper()
+
+	value, err := commitment.Calculate(k.jwk, multihashSHA2256)
+	require.NoError(t, err)
+
+	return value
+}
+
+// sign returns the compact JWS of the payload signed with the key
+func (k *testKey) sign(t *testing.T, payload interface{}) string {
+	t.Helper()
+
+	payloadBytes, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payloadBytes)
+
+	return signingInput + "." +
+		base64.RawURLEncoding.EncodeToString(ed25519.Sign(k.privateKey, []byte(signingInput)))
+}
+
+func newDelta(t *testing.T, nextUpdateKey *testKey, patches ...interface{}) map[string]interface{} {
+	t.Helper()
+
+	return map[string]interface{}{"patches": patches, "updateCommitment": nextUpdateKey.commitment(t)}
+}
+
+func hash(t *testing.T, value interface{}) string {
+	t.Helper()
+
+	h, err := canonicalMultihash(value, multihashSHA2256)
+	require.NoError(t, err)
+
+	return h
+}
@@ -0,0 +1,336 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/0_1/client"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+// operation types and patch actions of the DIF Sidetree v1 requests
+const (
+	v1TypeUpdate     = "update"
+	v1TypeRecover    = "recover"
+	v1TypeDeactivate = "deactivate"
+
+	v1ActionReplace          = "replace"
+	v1ActionAddPublicKeys    = "add-public-keys"
+	v1ActionRemovePublicKeys = "remove-public-keys"
+	v1ActionAddServices      = "add-services"
+	v1ActionRemoveServices   = "remove-services"
+)
+
+// v1CreateRequest is a DIF Sidetree v1 create request, whose initial state is its suffix data and delta
+type v1CreateRequest struct {
+	Type       string        `json:"type"`
+	SuffixData *v1SuffixData `json:"suffixData"`
+	Delta      *v1Delta      `json:"delta"`
+}
+
+type v1SuffixData struct {
+	DeltaHash          string `json:"deltaHash"`
+	RecoveryCommitment string `json:"recoveryCommitment"`
+}
+
+type v1Delta struct {
+	Patches          []map[string]interface{} `json:"patches"`
+	UpdateCommitment string                   `json:"updateCommitment"`
+}
+
+// v1OperationRequest is a DIF Sidetree v1 update, recover or deactivate request, a deactivate request having no delta.
+// The reveal value is the multihash of the signing key, which the active commitment of the DID commits to.
+type v1OperationRequest struct {
+	Type        string   `json:"type"`
+	DIDSuffix   string   `json:"didSuffix"`
+	RevealValue string   `json:"revealValue"`
+	Delta       *v1Delta `json:"delta,omitempty"`
+	SignedData  string   `json:"signedData"`
+}
+
+type v1UpdateSignedData struct {
+	UpdateKey *jws.JWK `json:"updateKey"`
+	DeltaHash string   `json:"deltaHash"`
+}
+
+type v1RecoverSignedData struct {
+	RecoveryCommitment string   `json:"recoveryCommitment"`
+	RecoveryKey        *jws.JWK `json:"recoveryKey"`
+	DeltaHash          string   `json:"deltaHash"`
+}
+
+type v1DeactivateSignedData struct {
+	DIDSuffix   string   `json:"didSuffix"`
+	RecoveryKey *jws.JWK `json:"recoveryKey"`
+}
+
+// buildCreateRequestV1 request builder for DIF Sidetree v1 public DID creation
+func buildCreateRequestV1(sidetreeConfig *models.SidetreeConfig, createDIDOpts *create.Opts) ([]byte, error) {
+	document, err := v1Document(createDIDOpts.PublicKeys, createDIDOpts.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryKey, err := pubkey.GetPublicKeyJWK(createDIDOpts.RecoveryPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recovery key : %w", err)
+	}
+
+	updateKey, err := pubkey.GetPublicKeyJWK(createDIDOpts.UpdatePublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get update key : %w", err)
+	}
+
+	recoveryCommitment, err := commitment.Calculate(recoveryKey, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	updateCommitment, err := commitment.Calculate(updateKey, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &v1Delta{Patches: []map[string]interface{}{{"action": v1ActionReplace, "document": document}},
+		UpdateCommitment: updateCommitment}
+
+	deltaHash, err := canonicalMultihash(delta, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&v1CreateRequest{Type: createOperationType, Delta: delta,
+		SuffixData: &v1SuffixData{DeltaHash: deltaHash, RecoveryCommitment: recoveryCommitment}})
+}
+
+// buildUpdateRequestV1 request builder for DIF Sidetree v1 public DID update
+func buildUpdateRequestV1(did string, sidetreeConfig *models.SidetreeConfig,
+	updateDIDOpts *update.Opts) ([]byte, error) {
+	nextUpdateKey, err := pubkey.GetPublicKeyJWK(updateDIDOpts.NextUpdatePublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next update key : %w", err)
+	}
+
+	nextUpdateCommitment, err := commitment.Calculate(nextUpdateKey, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	patches, err := v1UpdatePatches(updateDIDOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &v1Delta{Patches: patches, UpdateCommitment: nextUpdateCommitment}
+
+	deltaHash, err := canonicalMultihash(delta, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, updateKey, err := getSigner(updateDIDOpts.SigningKey, updateDIDOpts.SigningKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := signCompactJWS(signer, &v1UpdateSignedData{UpdateKey: updateKey, DeltaHash: deltaHash})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOperationRequestV1(v1TypeUpdate, did, updateKey, sidetreeConfig, delta, signedData)
+}
+
+// buildRecoverRequestV1 request builder for DIF Sidetree v1 public DID recovery
+func buildRecoverRequestV1(did string, sidetreeConfig *models.SidetreeConfig,
+	recoverDIDOpts *recovery.Opts) ([]byte, error) {
+	document, err := v1Document(recoverDIDOpts.PublicKeys, recoverDIDOpts.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRecoveryCommitment, nextUpdateCommitment, err := getCommitment(sidetreeConfig, recoverDIDOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &v1Delta{Patches: []map[string]interface{}{{"action": v1ActionReplace, "document": document}},
+		UpdateCommitment: nextUpdateCommitment}
+
+	deltaHash, err := canonicalMultihash(delta, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, recoveryKey, err := getSigner(recoverDIDOpts.SigningKey, recoverDIDOpts.SigningKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := signCompactJWS(signer, &v1RecoverSignedData{RecoveryCommitment: nextRecoveryCommitment,
+		RecoveryKey: recoveryKey, DeltaHash: deltaHash})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOperationRequestV1(v1TypeRecover, did, recoveryKey, sidetreeConfig, delta, signedData)
+}
+
+// buildDeactivateRequestV1 request builder for DIF Sidetree v1 public DID deactivate
+func buildDeactivateRequestV1(did string, sidetreeConfig *models.SidetreeConfig,
+	deactivateDIDOpts *deactivate.Opts) ([]byte, error) {
+	signer, recoveryKey, err := getSigner(deactivateDIDOpts.SigningKey, deactivateDIDOpts.SigningKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	didSuffix, err := getUniqueSuffix(did)
+	if err != nil {
+		return nil, err
+	}
+
+	signedData, err := signCompactJWS(signer, &v1DeactivateSignedData{DIDSuffix: didSuffix, RecoveryKey: recoveryKey})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOperationRequestV1(v1TypeDeactivate, did, recoveryKey, sidetreeConfig, nil, signedData)
+}
+
+// buildOperationRequestV1 builds the DIF Sidetree v1 request of an operation on the DID, revealing the signing key
+func buildOperationRequestV1(operationType, did string, signingKey *jws.JWK, sidetreeConfig *models.SidetreeConfig,
+	delta *v1Delta, signedData string) ([]byte, error) {
+	didSuffix, err := getUniqueSuffix(did)
+	if err != nil {
+		return nil, err
+	}
+
+	revealValue, err := canonicalMultihash(signingKey, sidetreeConfig.MultiHashAlgorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate reveal value: %w", err)
+	}
+
+	return json.Marshal(&v1OperationRequest{Type: operationType, DIDSuffix: didSuffix, RevealValue: revealValue,
+		Delta: delta, SignedData: signedData})
+}
+
+// v1Document returns the document of a DIF Sidetree v1 replace patch with the public keys and services
+func v1Document(publicKeys []doc.PublicKey, services []docdid.Service) (map[string]interface{}, error) {
+	var parsedKeys []doc.PublicKey
+
+	for _, key := range publicKeys {
+		parsedKey, err := unwrapPubKeyJWK(key)
+		if err != nil {
+			return nil, err
+		}
+
+		parsedKeys = append(parsedKeys, *parsedKey)
+	}
+
+	rawPublicKeys, err := doc.PopulateRawPublicKeys(parsedKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document public keys : %w", err)
+	}
+
+	return map[string]interface{}{"publicKeys": rawPublicKeys, "services": doc.PopulateRawServices(services)}, nil
+}
+
+// v1UpdatePatches returns the DIF Sidetree v1 patches of the update, in the order of createUpdatePatches
+func v1UpdatePatches(updateDIDOpts *update.Opts) ([]map[string]interface{}, error) {
+	var patches []map[string]interface{}
+
+	if len(updateDIDOpts.RemovePublicKeys) != 0 {
+		patches = append(patches, map[string]interface{}{"action": v1ActionRemovePublicKeys,
+			"ids": updateDIDOpts.RemovePublicKeys})
+	}
+
+	if len(updateDIDOpts.RemoveServices) != 0 {
+		patches = append(patches, map[string]interface{}{"action": v1ActionRemoveServices,
+			"ids": updateDIDOpts.RemoveServices})
+	}
+
+	if len(updateDIDOpts.AddServices) != 0 {
+		patches = append(patches, map[string]interface{}{"action": v1ActionAddServices,
+			"services": doc.PopulateRawServices(updateDIDOpts.AddServices)})
+	}
+
+	if len(updateDIDOpts.AddPublicKeys) != 0 {
+		rawPublicKeys, err := doc.PopulateRawPublicKeys(updateDIDOpts.AddPublicKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		patches = append(patches, map[string]interface{}{"action": v1ActionAddPublicKeys,
+			"publicKeys": rawPublicKeys})
+	}
+
+	return patches, nil
+}
+
+// signCompactJWS returns the compact JWS of the JSON payload signed by the signer
+func signCompactJWS(signer client.Signer, payload interface{}) (string, error) {
+	headersBytes, err := json.Marshal(signer.Headers())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWS headers: %w", err)
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWS payload: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headersBytes) + "." +
+		base64.RawURLEncoding.EncodeToString(payloadBytes)
+
+	signature, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign signed data: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// canonicalMultihash returns the base64url encoded multihash of the JCS canonical JSON of the value, as the delta
+// hashes and reveal values of the DIF Sidetree v1 requests
+func canonicalMultihash(value interface{}, multihashCode uint) (string, error) {
+	canonical, err := docutil.MarshalCanonical(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize: %w", err)
+	}
+
+	var digest []byte
+
+	switch multihashCode {
+	case multihashSHA2256:
+		sum := sha256.Sum256(canonical)
+		digest = sum[:]
+	case multihashSHA2512:
+		sum := sha512.Sum512(canonical)
+		digest = sum[:]
+	default:
+		return "", fmt.Errorf("multihash algorithm %d not supported", multihashCode)
+	}
+
+	multihash := append([]byte{byte(multihashCode), byte(len(digest))}, digest...)
+
+	return base64.RawURLEncoding.EncodeToString(multihash), nil
+}
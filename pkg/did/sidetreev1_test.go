@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func TestClient_ProtocolVersion1(t *testing.T) {
+	var request map[string]interface{}
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the DID isn't resolved, so the signing key isn't checked against its active commitment
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		request = make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(body, &request))
+
+		bytes, err := (&did.Doc{ID: "did:ex:123", Context: []string{did.ContextV1}}).JSONBytes()
+		require.NoError(t, err)
+		_, err = fmt.Fprintf(w, `{"didDocument": %s}`, bytes)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	nextPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	v := New(WithProtocolVersion(ProtocolVersion1))
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	t.Run("test create", func(t *testing.T) {
+		_, err = v.CreateDID("", create.WithSidetreeEndpoint(serv.URL), create.WithUpdatePublicKey(pubKey),
+			create.WithRecoveryPublicKey(nextPubKey), create.WithPublicKey(&doc.PublicKey{ID: "key1",
+				Type: doc.Ed25519VerificationKey2018, Value: pubKey, KeyType: doc.Ed25519KeyType,
+				Encoding: doc.PublicKeyEncodingJwk, Purposes: []string{doc.KeyPurposeAuthentication}}),
+			create.WithService(&did.Service{ID: "svc1", Type: "type", ServiceEndpoint: "https://example.com"}))
+		require.NoError(t, err)
+
+		require.Equal(t, "create", request["type"])
+
+		suffixData := request["suffixData"].(map[string]interface{})
+		require.NotEmpty(t, suffixData["recoveryCommitment"])
+
+		delta := request["delta"].(map[string]interface{})
+		require.NotEmpty(t, delta["updateCommitment"])
+
+		deltaHash, e := canonicalMultihash(delta, 18)
+		require.NoError(t, e)
+		require.Equal(t, deltaHash, suffixData["deltaHash"])
+
+		patch := delta["patches"].([]interface{})[0].(map[string]interface{})
+		require.Equal(t, "replace", patch["action"])
+
+		document := patch["document"].(map[string]interface{})
+		require.Len(t, document["publicKeys"], 1)
+		require.Len(t, document["services"], 1)
+	})
+
+	t.Run("test update", func(t *testing.T) {
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL), update.WithSigningKey(privKey),
+			update.WithNextUpdatePublicKey(nextPubKey), update.WithRemovePublicKey("key1"),
+			update.WithRemoveService("svc1"))
+		require.NoError(t, err)
+
+		require.Equal(t, "update", request["type"])
+		require.Equal(t, "123", request["didSuffix"])
+		requireRevealValue(t, request, pubKey)
+
+		delta := request["delta"].(map[string]interface{})
+		patches := delta["patches"].([]interface{})
+		require.Len(t, patches, 2)
+		require.Equal(t, "remove-public-keys", patches[0].(map[string]interface{})["action"])
+		require.Equal(t, []interface{}{"key1"}, patches[0].(map[string]interface{})["ids"])
+		require.Equal(t, "remove-services", patches[1].(map[string]interface{})["action"])
+
+		signedData := decodeSignedData(t, request)
+		require.NotEmpty(t, signedData["updateKey"])
+
+		deltaHash, e := canonicalMultihash(delta, 18)
+		require.NoError(t, e)
+		require.Equal(t, deltaHash, signedData["deltaHash"])
+	})
+
+	t.Run("test recover", func(t *testing.T) {
+		err = v.RecoverDID("did:ex:123", "", recovery.WithSidetreeEndpoint(serv.URL),
+			recovery.WithSigningKey(privKey), recovery.WithNextRecoveryPublicKey(nextPubKey),
+			recovery.WithNextUpdatePublicKey(nextPubKey))
+		require.NoError(t, err)
+
+		require.Equal(t, "recover", request["type"])
+		requireRevealValue(t, request, pubKey)
+
+		signedData := decodeSignedData(t, request)
+		require.NotEmpty(t, signedData["recoveryCommitment"])
+		require.NotEmpty(t, signedData["recoveryKey"])
+		require.NotEmpty(t, signedData["deltaHash"])
+	})
+
+	t.Run("test deactivate", func(t *testing.T) {
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privKey))
+		require.NoError(t, err)
+
+		require.Equal(t, "deactivate", request["type"])
+		require.Nil(t, request["delta"])
+		requireRevealValue(t, request, pubKey)
+
+		signedData := decodeSignedData(t, request)
+		require.Equal(t, "123", signedData["didSuffix"])
+	})
+
+	t.Run("test unsupported protocol version", func(t *testing.T) {
+		err = New(WithProtocolVersion("0.2")).DeactivateDID("did:ex:123", "",
+			deactivate.WithSidetreeEndpoint(serv.URL), deactivate.WithSigningKey(privKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported sidetree protocol version 0.2")
+	})
+}
+
+func TestClient_ResolveIdentifiersV1(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"didDocument": {}, "didDocumentMetadata": {"canonicalId": "did:trustbloc:domain:EiA",
+			"method": {"published": true, "updateCommitment": "c1"}}}`)
+	}))
+	defer serv.Close()
+
+	ids, err := New().ResolveIdentifiers("did:trustbloc:domain:EiA", "", serv.URL)
+	require.NoError(t, err)
+	require.Equal(t, "did:trustbloc:domain:EiA", ids.CanonicalID)
+
+	metadata, err := New().resolveMethodMetadata("did:trustbloc:domain:EiA", serv.URL)
+	require.NoError(t, err)
+	require.Equal(t, "c1", metadata.UpdateCommitment)
+}
+
+func TestCanonicalMultihash(t *testing.T) {
+	value, err := canonicalMultihash(map[string]string{"b": "2", "a": "1"}, 18)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(value, "Ei"))
+
+	value, err = canonicalMultihash(map[string]string{"a": "1"}, 19)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(value, "E0"))
+
+	_, err = canonicalMultihash(map[string]string{"a": "1"}, 17)
+	require.EqualError(t, err, "multihash algorithm 17 not supported")
+}
+
+// requireRevealValue requires the reveal value of the request to be the multihash of the public key
+func requireRevealValue(t *testing.T, request map[string]interface{}, pubKey ed25519.PublicKey) {
+	t.Helper()
+
+	jwk, err := pubkey.GetPublicKeyJWK(pubKey)
+	require.NoError(t, err)
+
+	revealValue, err := canonicalMultihash(jwk, 18)
+	require.NoError(t, err)
+	require.Equal(t, revealValue, request["revealValue"])
+}
+
+// decodeSignedData returns the payload of the signed data JWS of the request
+func decodeSignedData(t *testing.T, request map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	parts := strings.Split(request["signedData"].(string), ".")
+	require.Len(t, parts, 3)
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	signedData := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(payload, &signedData))
+
+	return signedData
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+// opaqueSigner signs with a crypto.Signer whose private key can't be read, such as a key held in an HSM
+// or smartcard
+type opaqueSigner struct {
+	signer crypto.Signer
+	alg    string
+	kid    string
+}
+
+func newOpaqueSigner(signer crypto.Signer, kid string) (*opaqueSigner, error) {
+	switch key := signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("curve not supported: %s: %w", key.Curve.Params().Name, ErrKeyNotSupported)
+		}
+
+		return &opaqueSigner{signer: signer, alg: "ES256", kid: kid}, nil
+	case ed25519.PublicKey:
+		return &opaqueSigner{signer: signer, alg: "EdDSA", kid: kid}, nil
+	default:
+		return nil, ErrKeyNotSupported
+	}
+}
+
+// Sign signs the data. ECDSA signatures are returned in the JWS R||S format.
+func (s *opaqueSigner) Sign(data []byte) ([]byte, error) {
+	if s.alg == "EdDSA" {
+		return s.signer.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+
+	digest := sha256.Sum256(data)
+
+	der, err := s.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return jwsSignature(der)
+}
+
+// Headers returns the JWS headers of the signer
+func (s *opaqueSigner) Headers() jws.Headers {
+	return jwsHeaders(s.alg, s.kid)
+}
+
+// jwsSignature converts the ASN.1 DER ecdsa P-256 signature to the JWS R||S format
+func jwsSignature(der []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ecdsa signature: %w", err)
+	}
+
+	const keySize = 32
+
+	signature := make([]byte, 2*keySize)
+	sig.R.FillBytes(signature[:keySize])
+	sig.S.FillBytes(signature[keySize:])
+
+	return signature, nil
+}
+
+func jwsHeaders(alg, kid string) jws.Headers {
+	headers := make(jws.Headers)
+	headers[jws.HeaderAlgorithm] = alg
+
+	if kid != "" {
+		headers[jws.HeaderKeyID] = kid
+	}
+
+	return headers
+}
@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package awskms provides a signer of DID operations backed by an asymmetric AWS KMS key, so that the private
+// recovery and update keys never leave AWS KMS. The signer is passed as the signing key of the update, recover and
+// deactivate options of the DID client.
+package awskms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+const arnRegionIndex = 3
+
+// Option is an AWS KMS signer option
+type Option func(opts *options)
+
+type options struct {
+	region   string
+	endpoint string
+	client   kmsiface.KMSAPI
+}
+
+// WithRegion sets the AWS region of the key. It defaults to the region of the key ARN, or else to the region of the
+// AWS shared config and environment.
+func WithRegion(region string) Option {
+	return func(opts *options) {
+		opts.region = region
+	}
+}
+
+// WithEndpoint sets the AWS KMS endpoint, such as a VPC endpoint or a local KMS for testing.
+func WithEndpoint(endpoint string) Option {
+	return func(opts *options) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithClient sets the AWS KMS client, instead of the client created with the default AWS credentials chain.
+func WithClient(client kmsiface.KMSAPI) Option {
+	return func(opts *options) {
+		opts.client = client
+	}
+}
+
+// Signer signs with an ECC_NIST_P256 AWS KMS key. It implements crypto.Signer.
+type Signer struct {
+	client    kmsiface.KMSAPI
+	keyID     string
+	publicKey *ecdsa.PublicKey
+}
+
+// New returns the signer of the AWS KMS key with the ARN (or ID or alias). The credentials are read with the
+// default AWS credentials chain: environment, shared credentials file and instance or task role.
+func New(keyID string, opts ...Option) (*Signer, error) {
+	o := &options{region: regionFromARN(keyID)}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	client := o.client
+
+	if client == nil {
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Config:            aws.Config{Region: nonEmpty(o.region), Endpoint: nonEmpty(o.endpoint)},
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create aws session: %w", err)
+		}
+
+		client = kms.New(sess)
+	}
+
+	publicKey, err := getPublicKey(client, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{client: client, keyID: keyID, publicKey: publicKey}, nil
+}
+
+// Public returns the public key of the AWS KMS key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the SHA-256 digest with the AWS KMS key, and returns the ASN.1 DER signature.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.SHA256 {
+		return nil, fmt.Errorf("hash function not supported: %s", opts.HashFunc())
+	}
+
+	out, err := s.client.Sign(&kms.SignInput{
+		KeyId:            aws.String(s.keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(kms.SigningAlgorithmSpecEcdsaSha256),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with aws kms key %s: %w", s.keyID, err)
+	}
+
+	return out.Signature, nil
+}
+
+func getPublicKey(client kmsiface.KMSAPI, keyID string) (*ecdsa.PublicKey, error) {
+	out, err := client.GetPublicKey(&kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key of aws kms key %s: %w", keyID, err)
+	}
+
+	if aws.StringValue(out.CustomerMasterKeySpec) != kms.CustomerMasterKeySpecEccNistP256 ||
+		aws.StringValue(out.KeyUsage) != kms.KeyUsageTypeSignVerify {
+		return nil, fmt.Errorf("aws kms key %s is not an %s signing key", keyID, kms.CustomerMasterKeySpecEccNistP256)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(out.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key of aws kms key %s: %w", keyID, err)
+	}
+
+	ecKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok || ecKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("public key of aws kms key %s is not a P-256 key", keyID)
+	}
+
+	return ecKey, nil
+}
+
+// regionFromARN returns the region of a key ARN (arn:aws:kms:region:account:key/id), or empty for a key ID or alias
+func regionFromARN(keyID string) string {
+	parts := strings.Split(keyID, ":")
+	if len(parts) <= arnRegionIndex || parts[0] != "arn" {
+		return ""
+	}
+
+	return parts[arnRegionIndex]
+}
+
+func nonEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return aws.String(value)
+}
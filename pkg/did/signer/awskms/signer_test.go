@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package awskms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/require"
+)
+
+const keyARN = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+func TestNew(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		signer, err := New(keyARN, WithClient(newMockKMS(t, privateKey)))
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.PublicKey, signer.Public())
+	})
+
+	t.Run("test get public key error", func(t *testing.T) {
+		client := newMockKMS(t, privateKey)
+		client.getPublicKeyErr = errors.New("access denied")
+
+		_, err := New(keyARN, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "access denied")
+	})
+
+	t.Run("test key spec not supported", func(t *testing.T) {
+		client := newMockKMS(t, privateKey)
+		client.keySpec = kms.CustomerMasterKeySpecEccNistP384
+
+		_, err := New(keyARN, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not an ECC_NIST_P256 signing key")
+	})
+
+	t.Run("test invalid public key", func(t *testing.T) {
+		client := newMockKMS(t, privateKey)
+		client.publicKey = []byte("invalid")
+
+		_, err := New(keyARN, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse public key of aws kms key")
+	})
+}
+
+func TestSigner_Sign(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	client := newMockKMS(t, privateKey)
+
+	signer, err := New(keyARN, WithClient(client))
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("data"))
+
+	t.Run("test success", func(t *testing.T) {
+		signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		require.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+	})
+
+	t.Run("test hash function not supported", func(t *testing.T) {
+		_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA384)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hash function not supported")
+	})
+
+	t.Run("test sign error", func(t *testing.T) {
+		client.signErr = errors.New("throttled")
+
+		_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "throttled")
+	})
+}
+
+func TestRegionFromARN(t *testing.T) {
+	require.Equal(t, "us-east-1", regionFromARN(keyARN))
+	require.Empty(t, regionFromARN("1234abcd-12ab-34cd-56ef-1234567890ab"))
+	require.Empty(t, regionFromARN("alias/update-key"))
+}
+
+type mockKMS struct {
+	kmsiface.KMSAPI
+	t               *testing.T
+	privateKey      *ecdsa.PrivateKey
+	publicKey       []byte
+	keySpec         string
+	getPublicKeyErr error
+	signErr         error
+}
+
+func newMockKMS(t *testing.T, privateKey *ecdsa.PrivateKey) *mockKMS {
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return &mockKMS{t: t, privateKey: privateKey, publicKey: publicKey,
+		keySpec: kms.CustomerMasterKeySpecEccNistP256}
+}
+
+func (m *mockKMS) GetPublicKey(input *kms.GetPublicKeyInput) (*kms.GetPublicKeyOutput, error) {
+	if m.getPublicKeyErr != nil {
+		return nil, m.getPublicKeyErr
+	}
+
+	return &kms.GetPublicKeyOutput{KeyId: input.KeyId, PublicKey: m.publicKey,
+		CustomerMasterKeySpec: aws.String(m.keySpec), KeyUsage: aws.String(kms.KeyUsageTypeSignVerify)}, nil
+}
+
+func (m *mockKMS) Sign(input *kms.SignInput) (*kms.SignOutput, error) {
+	if m.signErr != nil {
+		return nil, m.signErr
+	}
+
+	require.Equal(m.t, kms.MessageTypeDigest, aws.StringValue(input.MessageType))
+	require.Equal(m.t, kms.SigningAlgorithmSpecEcdsaSha256, aws.StringValue(input.SigningAlgorithm))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, m.privateKey, input.Message)
+	require.NoError(m.t, err)
+
+	return &kms.SignOutput{KeyId: input.KeyId, Signature: signature}, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package azurekv provides a signer of DID operations backed by an Azure Key Vault P-256 key, so that the private
+// recovery and update keys never leave the key vault. The signer is passed as the signing key of the update, recover
+// and deactivate options of the DID client.
+package azurekv
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.1/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+const (
+	keyVaultResource = "https://vault.azure.net"
+	keysPathSegment  = "keys"
+	keySize          = 32
+)
+
+// Client is the subset of the Azure Key Vault client used by the signer.
+type Client interface {
+	GetKey(ctx context.Context, vaultBaseURL, keyName, keyVersion string) (keyvault.KeyBundle, error)
+	Sign(ctx context.Context, vaultBaseURL, keyName, keyVersion string,
+		parameters keyvault.KeySignParameters) (keyvault.KeyOperationResult, error)
+}
+
+// Option is an Azure Key Vault signer option
+type Option func(opts *options)
+
+type options struct {
+	managedIdentity bool
+	clientID        string
+	client          Client
+}
+
+// WithManagedIdentity authenticates with the managed identity of the Azure resource (VM, App Service, AKS pod...)
+// instead of the service principal of the environment. The client ID selects a user-assigned identity; it's empty
+// for the system-assigned identity.
+func WithManagedIdentity(clientID string) Option {
+	return func(opts *options) {
+		opts.managedIdentity = true
+		opts.clientID = clientID
+	}
+}
+
+// WithClient sets the Azure Key Vault client, instead of the client created with the Azure credentials.
+func WithClient(client Client) Option {
+	return func(opts *options) {
+		opts.client = client
+	}
+}
+
+// Signer signs with an Azure Key Vault P-256 key. It implements crypto.Signer.
+type Signer struct {
+	client       Client
+	vaultBaseURL string
+	keyName      string
+	keyVersion   string
+	publicKey    *ecdsa.PublicKey
+}
+
+// New returns the signer of the Azure Key Vault key with the key identifier
+// (https://{vault}.vault.azure.net/keys/{name}[/{version}]). Without a version, the current version of the key is
+// used. By default, the credentials are read from the environment: the AZURE_TENANT_ID, AZURE_CLIENT_ID and
+// AZURE_CLIENT_SECRET (or AZURE_CERTIFICATE_PATH) of a service principal, or else the managed identity.
+func New(keyID string, opts ...Option) (*Signer, error) {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	vaultBaseURL, keyName, keyVersion, err := parseKeyID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := o.client
+
+	if client == nil {
+		authorizer, err := getAuthorizer(o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create azure authorizer: %w", err)
+		}
+
+		kvClient := keyvault.New()
+		kvClient.Authorizer = authorizer
+		client = &kvClient
+	}
+
+	s := &Signer{client: client, vaultBaseURL: vaultBaseURL, keyName: keyName, keyVersion: keyVersion}
+
+	s.publicKey, err = s.getPublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Public returns the public key of the Azure Key Vault key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the SHA-256 digest with the Azure Key Vault key, and returns the ASN.1 DER signature.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.SHA256 {
+		return nil, fmt.Errorf("hash function not supported: %s", opts.HashFunc())
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(digest)
+
+	result, err := s.client.Sign(context.Background(), s.vaultBaseURL, s.keyName, s.keyVersion,
+		keyvault.KeySignParameters{Algorithm: keyvault.ES256, Value: &value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with azure key vault key %s: %w", s.keyName, err)
+	}
+
+	if result.Result == nil {
+		return nil, fmt.Errorf("azure key vault key %s returned no signature", s.keyName)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(*result.Result)
+	if err != nil || len(signature) != 2*keySize {
+		return nil, fmt.Errorf("invalid signature from azure key vault key %s", s.keyName)
+	}
+
+	// key vault returns the JWS R||S format, crypto.Signer returns ASN.1 DER
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{new(big.Int).SetBytes(signature[:keySize]), new(big.Int).SetBytes(signature[keySize:])})
+}
+
+func (s *Signer) getPublicKey() (*ecdsa.PublicKey, error) {
+	bundle, err := s.client.GetKey(context.Background(), s.vaultBaseURL, s.keyName, s.keyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get azure key vault key %s: %w", s.keyName, err)
+	}
+
+	key := bundle.Key
+	if key == nil || (key.Kty != keyvault.EC && key.Kty != keyvault.ECHSM) || key.Crv != keyvault.P256 ||
+		key.X == nil || key.Y == nil {
+		return nil, fmt.Errorf("azure key vault key %s is not a P-256 key", s.keyName)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(*key.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key of azure key vault key %s: %w", s.keyName, err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(*key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key of azure key vault key %s: %w", s.keyName, err)
+	}
+
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+func getAuthorizer(o *options) (autorest.Authorizer, error) {
+	if !o.managedIdentity {
+		return auth.NewAuthorizerFromEnvironmentWithResource(keyVaultResource)
+	}
+
+	config := auth.NewMSIConfig()
+	config.Resource = keyVaultResource
+	config.ClientID = o.clientID
+
+	return config.Authorizer()
+}
+
+// parseKeyID parses the key identifier https://{vault}.vault.azure.net/keys/{name}[/{version}]
+func parseKeyID(keyID string) (string, string, string, error) {
+	u, err := url.Parse(keyID)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", "", fmt.Errorf("invalid azure key vault key id: %s", keyID)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	const minSegments, maxSegments = 2, 3
+
+	if len(segments) < minSegments || len(segments) > maxSegments || segments[0] != keysPathSegment ||
+		segments[1] == "" {
+		return "", "", "", fmt.Errorf("invalid azure key vault key id: %s", keyID)
+	}
+
+	keyVersion := ""
+	if len(segments) == maxSegments {
+		keyVersion = segments[2]
+	}
+
+	return u.Scheme + "://" + u.Host, segments[1], keyVersion, nil
+}
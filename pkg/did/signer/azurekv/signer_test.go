@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package azurekv
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.1/keyvault"
+	"github.com/stretchr/testify/require"
+)
+
+const keyID = "https://trustbloc.vault.azure.net/keys/update-key/0123456789abcdef"
+
+func TestNew(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		client := newMockClient(t, privateKey)
+
+		signer, err := New(keyID, WithClient(client))
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.PublicKey, signer.Public())
+		require.Equal(t, "https://trustbloc.vault.azure.net", client.vaultBaseURL)
+		require.Equal(t, "update-key", client.keyName)
+		require.Equal(t, "0123456789abcdef", client.keyVersion)
+	})
+
+	t.Run("test invalid key id", func(t *testing.T) {
+		for _, id := range []string{"update-key", "https://trustbloc.vault.azure.net/secrets/update-key",
+			"https://trustbloc.vault.azure.net/keys/update-key/version/other"} {
+			_, err := New(id, WithClient(newMockClient(t, privateKey)))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid azure key vault key id")
+		}
+	})
+
+	t.Run("test get key error", func(t *testing.T) {
+		client := newMockClient(t, privateKey)
+		client.getKeyErr = errors.New("forbidden")
+
+		_, err := New(keyID, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "forbidden")
+	})
+
+	t.Run("test key not supported", func(t *testing.T) {
+		client := newMockClient(t, privateKey)
+		client.key.Crv = keyvault.P384
+
+		_, err := New(keyID, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a P-256 key")
+	})
+}
+
+func TestSigner_Sign(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	client := newMockClient(t, privateKey)
+
+	signer, err := New("https://trustbloc.vault.azure.net/keys/update-key", WithClient(client))
+	require.NoError(t, err)
+	require.Empty(t, client.keyVersion)
+
+	digest := sha256.Sum256([]byte("data"))
+
+	t.Run("test success", func(t *testing.T) {
+		signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		require.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+	})
+
+	t.Run("test hash function not supported", func(t *testing.T) {
+		_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA512)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hash function not supported")
+	})
+
+	t.Run("test sign error", func(t *testing.T) {
+		client.signErr = errors.New("throttled")
+
+		_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "throttled")
+	})
+}
+
+type mockClient struct {
+	t            *testing.T
+	privateKey   *ecdsa.PrivateKey
+	key          *keyvault.JSONWebKey
+	vaultBaseURL string
+	keyName      string
+	keyVersion   string
+	getKeyErr    error
+	signErr      error
+}
+
+func newMockClient(t *testing.T, privateKey *ecdsa.PrivateKey) *mockClient {
+	x := base64.RawURLEncoding.EncodeToString(privateKey.X.Bytes())
+	y := base64.RawURLEncoding.EncodeToString(privateKey.Y.Bytes())
+
+	return &mockClient{t: t, privateKey: privateKey,
+		key: &keyvault.JSONWebKey{Kty: keyvault.ECHSM, Crv: keyvault.P256, X: &x, Y: &y}}
+}
+
+func (m *mockClient) GetKey(_ context.Context, vaultBaseURL, keyName,
+	keyVersion string) (keyvault.KeyBundle, error) {
+	m.vaultBaseURL, m.keyName, m.keyVersion = vaultBaseURL, keyName, keyVersion
+
+	if m.getKeyErr != nil {
+		return keyvault.KeyBundle{}, m.getKeyErr
+	}
+
+	return keyvault.KeyBundle{Key: m.key}, nil
+}
+
+func (m *mockClient) Sign(_ context.Context, _, _, _ string,
+	parameters keyvault.KeySignParameters) (keyvault.KeyOperationResult, error) {
+	if m.signErr != nil {
+		return keyvault.KeyOperationResult{}, m.signErr
+	}
+
+	require.Equal(m.t, keyvault.ES256, parameters.Algorithm)
+
+	digest, err := base64.RawURLEncoding.DecodeString(*parameters.Value)
+	require.NoError(m.t, err)
+
+	r, s, err := ecdsa.Sign(rand.Reader, m.privateKey, digest)
+	require.NoError(m.t, err)
+
+	signature := make([]byte, 2*keySize)
+	r.FillBytes(signature[:keySize])
+	s.FillBytes(signature[keySize:])
+
+	result := base64.RawURLEncoding.EncodeToString(signature)
+
+	return keyvault.KeyOperationResult{Result: &result}, nil
+}
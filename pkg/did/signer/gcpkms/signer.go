@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gcpkms provides a signer of DID operations backed by a Google Cloud KMS EC_SIGN_P256_SHA256 key version,
+// so that the private recovery and update keys never leave Cloud KMS. The signer is passed as the signing key of the
+// update, recover and deactivate options of the DID client.
+package gcpkms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+// Client is the subset of the Cloud KMS client used by the signer.
+type Client interface {
+	GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest,
+		opts ...gax.CallOption) (*kmspb.PublicKey, error)
+	AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest,
+		opts ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error)
+}
+
+// Option is a Cloud KMS signer option
+type Option func(opts *options)
+
+type options struct {
+	clientOptions []option.ClientOption
+	client        Client
+}
+
+// WithCredentialsFile sets the service account key file to authenticate with, instead of the application default
+// credentials.
+func WithCredentialsFile(file string) Option {
+	return func(opts *options) {
+		opts.clientOptions = append(opts.clientOptions, option.WithCredentialsFile(file))
+	}
+}
+
+// WithClient sets the Cloud KMS client, instead of the client created with the credentials.
+func WithClient(client Client) Option {
+	return func(opts *options) {
+		opts.client = client
+	}
+}
+
+// Signer signs with a Cloud KMS EC_SIGN_P256_SHA256 key version. It implements crypto.Signer.
+type Signer struct {
+	client    Client
+	closer    io.Closer
+	name      string
+	publicKey *ecdsa.PublicKey
+}
+
+// New returns the signer of the Cloud KMS key version with the resource name
+// (projects/{project}/locations/{location}/keyRings/{keyRing}/cryptoKeys/{key}/cryptoKeyVersions/{version}).
+// By default, the application default credentials are used: GOOGLE_APPLICATION_CREDENTIALS, the gcloud credentials
+// or the service account of the GCE instance or GKE workload.
+func New(name string, opts ...Option) (*Signer, error) {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Signer{client: o.client, name: name}
+
+	if s.client == nil {
+		client, err := kms.NewKeyManagementClient(context.Background(), o.clientOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cloud kms client: %w", err)
+		}
+
+		s.client, s.closer = client, client
+	}
+
+	publicKey, err := s.getPublicKey()
+	if err != nil {
+		if closeErr := s.Close(); closeErr != nil {
+			return nil, fmt.Errorf("%w (failed to close cloud kms client: %s)", err, closeErr)
+		}
+
+		return nil, err
+	}
+
+	s.publicKey = publicKey
+
+	return s, nil
+}
+
+// Public returns the public key of the Cloud KMS key version.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the SHA-256 digest with the Cloud KMS key version, and returns the ASN.1 DER signature.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.SHA256 {
+		return nil, fmt.Errorf("hash function not supported: %s", opts.HashFunc())
+	}
+
+	resp, err := s.client.AsymmetricSign(context.Background(), &kmspb.AsymmetricSignRequest{
+		Name:   s.name,
+		Digest: &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with cloud kms key %s: %w", s.name, err)
+	}
+
+	return resp.Signature, nil
+}
+
+// Close closes the connection of the Cloud KMS client created by the signer.
+func (s *Signer) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	return s.closer.Close()
+}
+
+func (s *Signer) getPublicKey() (*ecdsa.PublicKey, error) {
+	resp, err := s.client.GetPublicKey(context.Background(), &kmspb.GetPublicKeyRequest{Name: s.name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key of cloud kms key %s: %w", s.name, err)
+	}
+
+	if resp.Algorithm != kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256 {
+		return nil, fmt.Errorf("cloud kms key %s is not an EC_SIGN_P256_SHA256 key: %s", s.name, resp.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(resp.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode public key pem of cloud kms key %s", s.name)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key of cloud kms key %s: %w", s.name, err)
+	}
+
+	ecKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok || ecKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("public key of cloud kms key %s is not a P-256 key", s.name)
+	}
+
+	return ecKey, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gcpkms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/googleapis/gax-go/v2"
+	"github.com/stretchr/testify/require"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+const keyName = "projects/trustbloc/locations/global/keyRings/did/cryptoKeys/update-key/cryptoKeyVersions/1"
+
+func TestNew(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		signer, err := New(keyName, WithClient(newMockClient(t, privateKey)))
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.PublicKey, signer.Public())
+		require.NoError(t, signer.Close())
+	})
+
+	t.Run("test get public key error", func(t *testing.T) {
+		client := newMockClient(t, privateKey)
+		client.getPublicKeyErr = errors.New("permission denied")
+
+		_, err := New(keyName, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "permission denied")
+	})
+
+	t.Run("test algorithm not supported", func(t *testing.T) {
+		client := newMockClient(t, privateKey)
+		client.algorithm = kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384
+
+		_, err := New(keyName, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not an EC_SIGN_P256_SHA256 key")
+	})
+
+	t.Run("test invalid public key pem", func(t *testing.T) {
+		client := newMockClient(t, privateKey)
+		client.pem = "invalid"
+
+		_, err := New(keyName, WithClient(client))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode public key pem")
+	})
+}
+
+func TestSigner_Sign(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	client := newMockClient(t, privateKey)
+
+	signer, err := New(keyName, WithClient(client))
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("data"))
+
+	t.Run("test success", func(t *testing.T) {
+		signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		require.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+	})
+
+	t.Run("test hash function not supported", func(t *testing.T) {
+		_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA384)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hash function not supported")
+	})
+
+	t.Run("test sign error", func(t *testing.T) {
+		client.signErr = errors.New("unavailable")
+
+		_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unavailable")
+	})
+}
+
+type mockClient struct {
+	t               *testing.T
+	privateKey      *ecdsa.PrivateKey
+	pem             string
+	algorithm       kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+	getPublicKeyErr error
+	signErr         error
+}
+
+func newMockClient(t *testing.T, privateKey *ecdsa.PrivateKey) *mockClient {
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return &mockClient{t: t, privateKey: privateKey,
+		pem:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})),
+		algorithm: kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256}
+}
+
+func (m *mockClient) GetPublicKey(_ context.Context, req *kmspb.GetPublicKeyRequest,
+	_ ...gax.CallOption) (*kmspb.PublicKey, error) {
+	require.Equal(m.t, keyName, req.Name)
+
+	if m.getPublicKeyErr != nil {
+		return nil, m.getPublicKeyErr
+	}
+
+	return &kmspb.PublicKey{Pem: m.pem, Algorithm: m.algorithm}, nil
+}
+
+func (m *mockClient) AsymmetricSign(_ context.Context, req *kmspb.AsymmetricSignRequest,
+	_ ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error) {
+	if m.signErr != nil {
+		return nil, m.signErr
+	}
+
+	signature, err := ecdsa.SignASN1(rand.Reader, m.privateKey, req.Digest.GetSha256())
+	require.NoError(m.t, err)
+
+	return &kmspb.AsymmetricSignResponse{Signature: signature}, nil
+}
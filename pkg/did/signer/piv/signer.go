@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package piv provides a signer of DID operations backed by a P-256 key of a PIV token, such as a YubiKey, so that
+// the update, recover and deactivate operations of high-value DIDs are signed inside the token, and confirmed by
+// touching it when the touch policy of the key requires it. The signer is passed as the signing key of the update,
+// recover and deactivate options of the DID client.
+//
+// Access to the tokens needs cgo and PC/SC (pcsclite on Linux), and isn't part of the default build: build with
+// -tags piv.
+package piv
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	slotAuthentication     = 0x9a
+	slotSignature          = 0x9c
+	slotKeyManagement      = 0x9d
+	slotCardAuthentication = 0x9e
+	slotRetiredFirst       = 0x82
+	slotRetiredLast        = 0x95
+)
+
+// Option is a PIV signer option
+type Option func(opts *options)
+
+type options struct {
+	card        string
+	pin         string
+	touchPrompt func()
+}
+
+// WithCard selects the token whose PC/SC reader name contains the name, case-insensitively, such as "yubikey".
+// Defaults to the first token.
+func WithCard(name string) Option {
+	return func(opts *options) {
+		opts.card = name
+	}
+}
+
+// WithPIN sets the PIN of the token, verified before signing if the PIN policy of the key requires it.
+func WithPIN(pin string) Option {
+	return func(opts *options) {
+		opts.pin = pin
+	}
+}
+
+// WithTouchPrompt sets the function called before each signature, to ask the user to touch the token when the touch
+// policy of the key requires it. The token waits for the touch before signing.
+func WithTouchPrompt(prompt func()) Option {
+	return func(opts *options) {
+		opts.touchPrompt = prompt
+	}
+}
+
+// token is a PIV token, the slots being the key references of NIST SP 800-73-4 (0x9a, 0x9c, ...)
+type token interface {
+	publicKey(slot uint32) (crypto.PublicKey, error)
+	signer(slot uint32, publicKey crypto.PublicKey, pin string) (crypto.Signer, error)
+	Close() error
+}
+
+// Signer signs with a P-256 key of a PIV token. It implements crypto.Signer.
+type Signer struct {
+	token       token
+	signer      crypto.Signer
+	publicKey   *ecdsa.PublicKey
+	touchPrompt func()
+}
+
+// New opens the PIV token and returns the signer of the key of the slot, in hex (9a, 9c, 9d, 9e or a retired key
+// management slot 82 to 95). The public key is read from the certificate of the slot, or from the attestation of the
+// key if the slot has no certificate. The token stays open until Close.
+func New(slot string, opts ...Option) (*Signer, error) {
+	key, err := ParseSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	t, err := openToken(o.card)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newSigner(t, key, o)
+	if err != nil {
+		closeToken(t)
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func newSigner(t token, slot uint32, o *options) (*Signer, error) {
+	publicKey, err := t.publicKey(slot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the public key of piv slot %x: %w", slot, err)
+	}
+
+	ecKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok || ecKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("piv slot %x does not hold a P-256 key", slot)
+	}
+
+	signer, err := t.signer(slot, publicKey, o.pin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the private key of piv slot %x: %w", slot, err)
+	}
+
+	return &Signer{token: t, signer: signer, publicKey: ecKey, touchPrompt: o.touchPrompt}, nil
+}
+
+// ParseSlot parses the hex key reference of a PIV slot: 9a, 9c, 9d, 9e, or a retired key management slot 82 to 95.
+func ParseSlot(slot string) (uint32, error) {
+	key, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(slot), "0x"), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid piv slot '%s': %w", slot, err)
+	}
+
+	switch {
+	case key == slotAuthentication, key == slotSignature, key == slotKeyManagement, key == slotCardAuthentication:
+	case key >= slotRetiredFirst && key <= slotRetiredLast:
+	default:
+		return 0, fmt.Errorf("piv slot '%s' is not a key slot", slot)
+	}
+
+	return uint32(key), nil
+}
+
+// Public returns the P-256 public key of the slot.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest in the token, returning the ASN.1 DER signature. The token waits to be touched if the touch
+// policy of the key requires it.
+func (s *Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if s.touchPrompt != nil {
+		s.touchPrompt()
+	}
+
+	sig, err := s.signer.Sign(rand, digest, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with piv token: %w", err)
+	}
+
+	return sig, nil
+}
+
+// Close closes the token.
+func (s *Signer) Close() error {
+	return s.token.Close()
+}
+
+func closeToken(t token) {
+	if err := t.Close(); err != nil {
+		log.Warnf("failed to close piv token: %s", err)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package piv
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSlot(t *testing.T) {
+	for slot, key := range map[string]uint32{"9a": 0x9a, "9C": 0x9c, "0x9d": 0x9d, "9e": 0x9e, "82": 0x82, "95": 0x95} {
+		k, err := ParseSlot(slot)
+		require.NoError(t, err)
+		require.Equal(t, key, k)
+	}
+
+	_, err := ParseSlot("9b")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "piv slot '9b' is not a key slot")
+
+	_, err = ParseSlot("slot")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid piv slot 'slot'")
+}
+
+func TestSigner(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("message"))
+
+	t.Run("test sign", func(t *testing.T) {
+		tok := &mockToken{key: privateKey}
+		touched := 0
+
+		s, err := newSigner(tok, slotSignature, &options{pin: "123456", touchPrompt: func() { touched++ }})
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.PublicKey, s.Public())
+		require.Equal(t, "123456", tok.pin)
+
+		sig, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		require.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], sig))
+		require.Equal(t, 1, touched)
+
+		require.NoError(t, s.Close())
+		require.True(t, tok.closed)
+	})
+
+	t.Run("test sign error", func(t *testing.T) {
+		s, err := newSigner(&mockToken{key: privateKey, signErr: errors.New("touch timeout")}, slotSignature,
+			&options{})
+		require.NoError(t, err)
+
+		_, err = s.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to sign with piv token: touch timeout")
+	})
+
+	t.Run("test public key error", func(t *testing.T) {
+		_, err := newSigner(&mockToken{publicKeyErr: errors.New("no certificate")}, slotSignature, &options{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read the public key of piv slot 9c: no certificate")
+	})
+
+	t.Run("test key not P-256", func(t *testing.T) {
+		p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = newSigner(&mockToken{key: p384Key}, slotAuthentication, &options{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "piv slot 9a does not hold a P-256 key")
+
+		edKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = newSigner(&mockToken{publicKeyValue: edKey}, slotAuthentication, &options{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not hold a P-256 key")
+	})
+
+	t.Run("test private key error", func(t *testing.T) {
+		_, err := newSigner(&mockToken{key: privateKey, signerErr: errors.New("wrong pin")}, slotSignature,
+			&options{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get the private key of piv slot 9c: wrong pin")
+	})
+
+	t.Run("test invalid slot", func(t *testing.T) {
+		_, err := New("9b")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a key slot")
+	})
+}
+
+type mockToken struct {
+	key            *ecdsa.PrivateKey
+	publicKeyValue crypto.PublicKey
+	publicKeyErr   error
+	signerErr      error
+	signErr        error
+	pin            string
+	closed         bool
+}
+
+func (m *mockToken) publicKey(uint32) (crypto.PublicKey, error) {
+	if m.publicKeyErr != nil {
+		return nil, m.publicKeyErr
+	}
+
+	if m.publicKeyValue != nil {
+		return m.publicKeyValue, nil
+	}
+
+	return &m.key.PublicKey, nil
+}
+
+func (m *mockToken) signer(_ uint32, _ crypto.PublicKey, pin string) (crypto.Signer, error) {
+	if m.signerErr != nil {
+		return nil, m.signerErr
+	}
+
+	m.pin = pin
+
+	return &mockSigner{PrivateKey: m.key, err: m.signErr}, nil
+}
+
+func (m *mockToken) Close() error {
+	m.closed = true
+
+	return nil
+}
+
+type mockSigner struct {
+	*ecdsa.PrivateKey
+	err error
+}
+
+func (m *mockSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.PrivateKey.Sign(rand, digest, opts)
+}
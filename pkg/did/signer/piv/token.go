@@ -0,0 +1,111 @@
+//go:build piv
+// +build piv
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package piv
+
+import (
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/go-piv/piv-go/piv"
+)
+
+// yubiKey is a PIV token opened with piv-go, which supports the YubiKey and the tokens implementing the PIV standard
+type yubiKey struct {
+	yk *piv.YubiKey
+}
+
+func openToken(card string) (token, error) {
+	cards, err := piv.Cards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list piv tokens: %w", err)
+	}
+
+	for _, c := range cards {
+		if !strings.Contains(strings.ToLower(c), strings.ToLower(card)) {
+			continue
+		}
+
+		yk, e := piv.Open(c)
+		if e != nil {
+			return nil, fmt.Errorf("failed to open piv token '%s': %w", c, e)
+		}
+
+		return &yubiKey{yk: yk}, nil
+	}
+
+	if card == "" {
+		return nil, fmt.Errorf("no piv token found")
+	}
+
+	return nil, fmt.Errorf("no piv token matching '%s' found", card)
+}
+
+func (k *yubiKey) publicKey(slot uint32) (crypto.PublicKey, error) {
+	s, err := pivSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := k.yk.Certificate(s)
+	if err == nil {
+		return cert.PublicKey, nil
+	}
+
+	// keys generated on the token without a certificate have an attestation
+	attestation, e := k.yk.Attest(s)
+	if e != nil {
+		return nil, fmt.Errorf("slot has no certificate (%s) nor attestation: %w", err, e)
+	}
+
+	return attestation.PublicKey, nil
+}
+
+func (k *yubiKey) signer(slot uint32, publicKey crypto.PublicKey, pin string) (crypto.Signer, error) {
+	s, err := pivSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := k.yk.PrivateKey(s, publicKey, piv.KeyAuth{PIN: pin})
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key can't sign")
+	}
+
+	return signer, nil
+}
+
+func (k *yubiKey) Close() error {
+	return k.yk.Close()
+}
+
+func pivSlot(key uint32) (piv.Slot, error) {
+	switch key {
+	case slotAuthentication:
+		return piv.SlotAuthentication, nil
+	case slotSignature:
+		return piv.SlotSignature, nil
+	case slotKeyManagement:
+		return piv.SlotKeyManagement, nil
+	case slotCardAuthentication:
+		return piv.SlotCardAuthentication, nil
+	}
+
+	slot, ok := piv.RetiredKeyManagementSlot(key)
+	if !ok {
+		return piv.Slot{}, fmt.Errorf("piv slot %x is not a key slot", key)
+	}
+
+	return slot, nil
+}
@@ -0,0 +1,17 @@
+//go:build !piv
+// +build !piv
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package piv
+
+import (
+	"fmt"
+)
+
+func openToken(string) (token, error) {
+	return nil, fmt.Errorf("piv signing is not supported by this build, rebuild it with -tags piv")
+}
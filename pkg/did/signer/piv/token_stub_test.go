@@ -0,0 +1,21 @@
+//go:build !piv
+// +build !piv
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package piv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_NotSupported(t *testing.T) {
+	_, err := New("9c", WithCard("yubikey"), WithPIN("123456"), WithTouchPrompt(func() {}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rebuild it with -tags piv")
+}
@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sshagent provides a signer of DID operations backed by an Ed25519 key held in ssh-agent, so that the keys
+// developers already keep in their agent sign the update, recover and deactivate operations without being exported.
+// The signer is passed as the signing key of the update, recover and deactivate options of the DID client.
+package sshagent
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	// SocketEnvKey is the environment variable of the ssh-agent socket
+	SocketEnvKey = "SSH_AUTH_SOCK"
+
+	md5Prefix = "MD5:"
+)
+
+// Signer signs with an Ed25519 key of an ssh-agent. It implements crypto.Signer.
+type Signer struct {
+	agent     agent.Agent
+	key       ssh.PublicKey
+	publicKey ed25519.PublicKey
+	conn      io.Closer
+}
+
+// New connects to the ssh-agent listening on the unix socket, SSH_AUTH_SOCK if empty, and returns the signer of its
+// Ed25519 key with the fingerprint. The connection stays open until Close.
+func New(socket, fingerprint string) (*Signer, error) {
+	if socket == "" {
+		socket = os.Getenv(SocketEnvKey)
+	}
+
+	if socket == "" {
+		return nil, fmt.Errorf("ssh-agent socket is not set and %s is empty", SocketEnvKey)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+
+	s, err := NewWithAgent(agent.NewClient(conn), fingerprint)
+	if err != nil {
+		closeConn(conn)
+
+		return nil, err
+	}
+
+	s.conn = conn
+
+	return s, nil
+}
+
+// NewWithAgent returns the signer of the Ed25519 key of the agent with the fingerprint, either in the SHA256 format
+// listed by ssh-add -l (SHA256:...) or in the legacy MD5 format (MD5:aa:bb:...)
+func NewWithAgent(a agent.Agent, fingerprint string) (*Signer, error) {
+	keys, err := a.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %w", err)
+	}
+
+	for _, k := range keys {
+		key, e := ssh.ParsePublicKey(k.Blob)
+		if e != nil {
+			return nil, fmt.Errorf("failed to parse ssh-agent key: %w", e)
+		}
+
+		if !matches(key, fingerprint) {
+			continue
+		}
+
+		cryptoKey, ok := key.(ssh.CryptoPublicKey)
+		if !ok || key.Type() != ssh.KeyAlgoED25519 {
+			return nil, fmt.Errorf("ssh-agent key %s is a %s key, not an Ed25519 key", fingerprint, key.Type())
+		}
+
+		publicKey, ok := cryptoKey.CryptoPublicKey().(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("ssh-agent key %s is not an Ed25519 key", fingerprint)
+		}
+
+		return &Signer{agent: a, key: key, publicKey: publicKey}, nil
+	}
+
+	return nil, fmt.Errorf("ssh-agent has no key with fingerprint %s", fingerprint)
+}
+
+// Public returns the Ed25519 public key of the agent key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the message with the agent key. Ed25519 signs the message itself, so opts must not have a hash
+// function.
+func (s *Signer) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("ed25519 signs the message, not a digest")
+	}
+
+	sig, err := s.agent.Sign(s.key, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with ssh-agent: %w", err)
+	}
+
+	if sig.Format != ssh.KeyAlgoED25519 {
+		return nil, fmt.Errorf("unexpected ssh-agent signature format %s", sig.Format)
+	}
+
+	return sig.Blob, nil
+}
+
+// Close closes the connection to the agent opened by New.
+func (s *Signer) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.conn.Close()
+}
+
+func matches(key ssh.PublicKey, fingerprint string) bool {
+	if fingerprint == ssh.FingerprintSHA256(key) {
+		return true
+	}
+
+	return strings.TrimPrefix(fingerprint, md5Prefix) == ssh.FingerprintLegacyMD5(key)
+}
+
+func closeConn(conn io.Closer) {
+	if err := conn.Close(); err != nil {
+		log.Warnf("failed to close ssh-agent connection: %s", err)
+	}
+}
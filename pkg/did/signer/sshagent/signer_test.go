@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sshagent
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestSigner(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: ecKey}))
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: privateKey}))
+
+	sshKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+
+	ecSSHKey, err := ssh.NewPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	t.Run("test sign", func(t *testing.T) {
+		for _, fingerprint := range []string{ssh.FingerprintSHA256(sshKey), "MD5:" + ssh.FingerprintLegacyMD5(sshKey)} {
+			s, err := NewWithAgent(keyring, fingerprint)
+			require.NoError(t, err)
+			require.Equal(t, publicKey, s.Public())
+
+			sig, err := s.Sign(rand.Reader, []byte("message"), crypto.Hash(0))
+			require.NoError(t, err)
+			require.True(t, ed25519.Verify(publicKey, []byte("message"), sig))
+
+			_, err = s.Sign(rand.Reader, []byte("message"), crypto.SHA256)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "ed25519 signs the message, not a digest")
+
+			require.NoError(t, s.Close())
+		}
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		_, err := NewWithAgent(keyring, "SHA256:unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ssh-agent has no key with fingerprint SHA256:unknown")
+	})
+
+	t.Run("test key not ed25519", func(t *testing.T) {
+		_, err := NewWithAgent(keyring, ssh.FingerprintSHA256(ecSSHKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not an Ed25519 key")
+	})
+
+	t.Run("test key removed from the agent", func(t *testing.T) {
+		other := agent.NewKeyring()
+		require.NoError(t, other.Add(agent.AddedKey{PrivateKey: privateKey}))
+
+		s, err := NewWithAgent(other, ssh.FingerprintSHA256(sshKey))
+		require.NoError(t, err)
+
+		require.NoError(t, other.RemoveAll())
+
+		_, err = s.Sign(rand.Reader, []byte("message"), crypto.Hash(0))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to sign with ssh-agent")
+	})
+
+	t.Run("test agent socket", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "sshagent")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		socket := filepath.Join(dir, "agent.sock")
+
+		listener, err := net.Listen("unix", socket)
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, listener.Close()) }()
+
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				go func() {
+					_ = agent.ServeAgent(keyring, conn) //nolint: errcheck
+				}()
+			}
+		}()
+
+		os.Clearenv()
+		require.NoError(t, os.Setenv(SocketEnvKey, socket))
+
+		s, err := New("", ssh.FingerprintSHA256(sshKey))
+		require.NoError(t, err)
+
+		sig, err := s.Sign(rand.Reader, []byte("message"), crypto.Hash(0))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(publicKey, []byte("message"), sig))
+		require.NoError(t, s.Close())
+
+		_, err = New(socket, "SHA256:unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ssh-agent has no key")
+
+		_, err = New(filepath.Join(dir, "missing.sock"), ssh.FingerprintSHA256(sshKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to connect to ssh-agent")
+
+		os.Clearenv()
+
+		_, err = New("", ssh.FingerprintSHA256(sshKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ssh-agent socket is not set")
+	})
+}
@@ -0,0 +1,321 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package vault provides a signer of DID operations backed by a key of the HashiCorp Vault transit secrets engine,
+// so that Vault-managed keys sign the update, recover and deactivate operations without leaving Vault. The signer is
+// passed as the signing key of the update, recover and deactivate options of the DID client.
+package vault
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTransitMount = "transit"
+	defaultAppRoleMount = "approle"
+
+	keyTypeECDSAP256 = "ecdsa-p256"
+	keyTypeEd25519   = "ed25519"
+
+	tokenHeader     = "X-Vault-Token"
+	namespaceHeader = "X-Vault-Namespace"
+	signaturePrefix = "vault:v"
+)
+
+// Option is a Vault transit signer option
+type Option func(opts *options)
+
+type options struct {
+	token        string
+	roleID       string
+	secretID     string
+	transitMount string
+	appRoleMount string
+	namespace    string
+	httpClient   *http.Client
+}
+
+// WithToken authenticates to Vault with the token.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithAppRole authenticates to Vault with the AppRole role ID and secret ID, logging in when the signer is created.
+func WithAppRole(roleID, secretID string) Option {
+	return func(opts *options) {
+		opts.roleID = roleID
+		opts.secretID = secretID
+	}
+}
+
+// WithTransitMount sets the path the transit secrets engine is mounted at. Defaults to transit.
+func WithTransitMount(mount string) Option {
+	return func(opts *options) {
+		opts.transitMount = mount
+	}
+}
+
+// WithAppRoleMount sets the path the AppRole auth method is mounted at. Defaults to approle.
+func WithAppRoleMount(mount string) Option {
+	return func(opts *options) {
+		opts.appRoleMount = mount
+	}
+}
+
+// WithNamespace sets the Vault Enterprise namespace of the key.
+func WithNamespace(namespace string) Option {
+	return func(opts *options) {
+		opts.namespace = namespace
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the Vault requests, such as a client with the TLS config of the Vault CA.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// Signer signs with an ecdsa-p256 or ed25519 key of the Vault transit secrets engine. It implements crypto.Signer.
+// The latest version of the key when the signer is created is used.
+type Signer struct {
+	address    string
+	keyName    string
+	keyVersion int
+	opts       *options
+	publicKey  crypto.PublicKey
+}
+
+// New returns the signer of the transit key with the name, of the Vault server with the address
+// (https://vault.example.com:8200). The signer authenticates with either WithToken or WithAppRole.
+func New(address, keyName string, opts ...Option) (*Signer, error) {
+	o := &options{transitMount: defaultTransitMount, appRoleMount: defaultAppRoleMount, httpClient: http.DefaultClient}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Signer{address: strings.TrimSuffix(address, "/"), keyName: keyName, opts: o}
+
+	if o.roleID != "" {
+		token, err := s.login()
+		if err != nil {
+			return nil, err
+		}
+
+		o.token = token
+	}
+
+	if o.token == "" {
+		return nil, fmt.Errorf("vault token or approle is required")
+	}
+
+	if err := s.readKey(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Public returns the public key of the transit key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the SHA-256 digest with an ecdsa-p256 key, and returns the ASN.1 DER signature, or signs the message
+// with an ed25519 key.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	req := map[string]interface{}{
+		"input":       base64.StdEncoding.EncodeToString(digest),
+		"key_version": s.keyVersion,
+	}
+
+	switch s.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if opts.HashFunc() != crypto.SHA256 {
+			return nil, fmt.Errorf("hash function not supported: %s", opts.HashFunc())
+		}
+
+		req["prehashed"] = true
+		req["hash_algorithm"] = "sha2-256"
+		req["marshaling_algorithm"] = "asn1"
+	default:
+		if opts.HashFunc() != crypto.Hash(0) {
+			return nil, fmt.Errorf("ed25519 keys sign the message, not a digest")
+		}
+	}
+
+	resp := &struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}{}
+
+	if err := s.send(http.MethodPost, s.opts.transitMount+"/sign/"+s.keyName, req, resp); err != nil {
+		return nil, fmt.Errorf("failed to sign with vault key %s: %w", s.keyName, err)
+	}
+
+	// the signature is returned as vault:v{version}:{base64 signature}
+	parts := strings.SplitN(resp.Data.Signature, ":", 3) //nolint: gomnd
+	if len(parts) != 3 || !strings.HasPrefix(resp.Data.Signature, signaturePrefix) {
+		return nil, fmt.Errorf("invalid signature from vault key %s", s.keyName)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature from vault key %s: %w", s.keyName, err)
+	}
+
+	return signature, nil
+}
+
+func (s *Signer) login() (string, error) {
+	resp := &struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+
+	err := s.send(http.MethodPost, "auth/"+s.opts.appRoleMount+"/login",
+		map[string]string{"role_id": s.opts.roleID, "secret_id": s.opts.secretID}, resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to login to vault with approle: %w", err)
+	}
+
+	return resp.Auth.ClientToken, nil
+}
+
+func (s *Signer) readKey() error {
+	resp := &struct {
+		Data struct {
+			Type          string `json:"type"`
+			LatestVersion int    `json:"latest_version"`
+			Keys          map[string]struct {
+				PublicKey string `json:"public_key"`
+			} `json:"keys"`
+		} `json:"data"`
+	}{}
+
+	if err := s.send(http.MethodGet, s.opts.transitMount+"/keys/"+s.keyName, nil, resp); err != nil {
+		return fmt.Errorf("failed to read vault key %s: %w", s.keyName, err)
+	}
+
+	key, ok := resp.Data.Keys[strconv.Itoa(resp.Data.LatestVersion)]
+	if !ok {
+		return fmt.Errorf("vault key %s has no version %d", s.keyName, resp.Data.LatestVersion)
+	}
+
+	publicKey, err := parsePublicKey(resp.Data.Type, key.PublicKey)
+	if err != nil {
+		return fmt.Errorf("vault key %s not supported: %w", s.keyName, err)
+	}
+
+	s.keyVersion, s.publicKey = resp.Data.LatestVersion, publicKey
+
+	return nil
+}
+
+// parsePublicKey parses the public key of a transit key: a PEM for ecdsa-p256 keys, base64 for ed25519 keys
+func parsePublicKey(keyType, value string) (crypto.PublicKey, error) {
+	switch keyType {
+	case keyTypeECDSAP256:
+		block, _ := pem.Decode([]byte(value))
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode public key pem")
+		}
+
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("public key is not a P-256 key")
+		}
+
+		return ecKey, nil
+	case keyTypeEd25519:
+		publicKey, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key")
+		}
+
+		return ed25519.PublicKey(publicKey), nil
+	default:
+		return nil, fmt.Errorf("key type %s is not a signing key type (%s or %s)", keyType, keyTypeECDSAP256,
+			keyTypeEd25519)
+	}
+}
+
+func (s *Signer) send(method, path string, reqBody, respBody interface{}) error {
+	var body io.Reader
+
+	if reqBody != nil {
+		reqBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		body = bytes.NewReader(reqBytes)
+	}
+
+	req, err := http.NewRequest(method, s.address+"/v1/"+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if s.opts.token != "" {
+		req.Header.Set(tokenHeader, s.opts.token)
+	}
+
+	if s.opts.namespace != "" {
+		req.Header.Set(namespaceHeader, s.opts.namespace)
+	}
+
+	resp, err := s.opts.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Warnf("failed to close response body: %s", closeErr)
+		}
+	}()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBytes)))
+	}
+
+	if err := json.Unmarshal(respBytes, respBody); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token    = "s.token"
+	keyName  = "update-key"
+	roleID   = "role1"
+	secretID = "secret1"
+)
+
+func TestNew(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test token", func(t *testing.T) {
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		signer, err := New(server.URL, keyName, WithToken(token))
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.PublicKey, signer.Public())
+		require.Equal(t, 2, signer.keyVersion)
+	})
+
+	t.Run("test approle", func(t *testing.T) {
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		signer, err := New(server.URL+"/", keyName, WithAppRole(roleID, secretID), WithNamespace("ns1"))
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.PublicKey, signer.Public())
+	})
+
+	t.Run("test approle login error", func(t *testing.T) {
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		_, err := New(server.URL, keyName, WithAppRole(roleID, "other"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to login to vault with approle: vault returned status 400")
+	})
+
+	t.Run("test missing credentials", func(t *testing.T) {
+		_, err := New("http://localhost:8200", keyName)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "vault token or approle is required")
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		_, err := New(server.URL, "other", WithToken(token))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read vault key other: vault returned status 404")
+	})
+
+	t.Run("test permission denied", func(t *testing.T) {
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		_, err := New(server.URL, keyName, WithToken("other"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "vault returned status 403")
+	})
+
+	t.Run("test key type not supported", func(t *testing.T) {
+		transit := newMockTransit(t, privateKey)
+		transit.keyType = "aes256-gcm96"
+
+		server := httptest.NewServer(transit)
+		defer server.Close()
+
+		_, err := New(server.URL, keyName, WithToken(token), WithTransitMount("did-transit"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type aes256-gcm96 is not a signing key type")
+	})
+}
+
+func TestSigner_Sign(t *testing.T) {
+	t.Run("test ecdsa key", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		signer, err := New(server.URL, keyName, WithToken(token))
+		require.NoError(t, err)
+
+		digest := sha256.Sum256([]byte("data"))
+
+		signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		require.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+
+		_, err = signer.Sign(rand.Reader, digest[:], crypto.SHA512)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hash function not supported")
+	})
+
+	t.Run("test ed25519 key", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		server := httptest.NewServer(newMockTransit(t, privateKey))
+		defer server.Close()
+
+		signer, err := New(server.URL, keyName, WithToken(token))
+		require.NoError(t, err)
+		require.Equal(t, publicKey, signer.Public())
+
+		signature, err := signer.Sign(rand.Reader, []byte("data"), crypto.Hash(0))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(publicKey, []byte("data"), signature))
+
+		_, err = signer.Sign(rand.Reader, []byte("data"), crypto.SHA256)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ed25519 keys sign the message")
+	})
+
+	t.Run("test invalid signature", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		transit := newMockTransit(t, privateKey)
+		transit.signature = "invalid"
+
+		server := httptest.NewServer(transit)
+		defer server.Close()
+
+		signer, err := New(server.URL, keyName, WithToken(token))
+		require.NoError(t, err)
+
+		digest := sha256.Sum256([]byte("data"))
+
+		_, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid signature from vault key")
+	})
+}
+
+// mockTransit emulates the transit secrets engine and the approle auth method of Vault
+type mockTransit struct {
+	t          *testing.T
+	privateKey crypto.Signer
+	keyType    string
+	publicKey  string
+	signature  string
+}
+
+func newMockTransit(t *testing.T, privateKey crypto.Signer) *mockTransit {
+	m := &mockTransit{t: t, privateKey: privateKey}
+
+	switch publicKey := privateKey.Public().(type) {
+	case *ecdsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		require.NoError(t, err)
+
+		m.keyType = keyTypeECDSAP256
+		m.publicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	case ed25519.PublicKey:
+		m.keyType = keyTypeEd25519
+		m.publicKey = base64.StdEncoding.EncodeToString(publicKey)
+	}
+
+	return m
+}
+
+func (m *mockTransit) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/v1/auth/approle/login" {
+		m.login(rw, req)
+
+		return
+	}
+
+	if req.Header.Get(tokenHeader) != token {
+		rw.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	switch req.URL.Path {
+	case "/v1/transit/keys/" + keyName, "/v1/did-transit/keys/" + keyName:
+		m.write(rw, map[string]interface{}{"data": map[string]interface{}{
+			"type": m.keyType, "latest_version": 2,
+			"keys": map[string]interface{}{"1": map[string]string{}, "2": map[string]string{"public_key": m.publicKey}},
+		}})
+	case "/v1/transit/sign/" + keyName:
+		m.sign(rw, req)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockTransit) login(rw http.ResponseWriter, req *http.Request) {
+	creds := map[string]string{}
+	require.NoError(m.t, json.NewDecoder(req.Body).Decode(&creds))
+
+	if creds["role_id"] != roleID || creds["secret_id"] != secretID {
+		rw.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	m.write(rw, map[string]interface{}{"auth": map[string]string{"client_token": token}})
+}
+
+func (m *mockTransit) sign(rw http.ResponseWriter, req *http.Request) {
+	signReq := struct {
+		Input      string `json:"input"`
+		KeyVersion int    `json:"key_version"`
+		Prehashed  bool   `json:"prehashed"`
+	}{}
+	require.NoError(m.t, json.NewDecoder(req.Body).Decode(&signReq))
+	require.Equal(m.t, 2, signReq.KeyVersion)
+
+	input, err := base64.StdEncoding.DecodeString(signReq.Input)
+	require.NoError(m.t, err)
+
+	opts := crypto.Hash(0)
+	if signReq.Prehashed {
+		opts = crypto.SHA256
+	}
+
+	signature, err := m.privateKey.Sign(rand.Reader, input, opts)
+	require.NoError(m.t, err)
+
+	if m.signature == "" {
+		m.signature = "vault:v2:" + base64.StdEncoding.EncodeToString(signature)
+	}
+
+	m.write(rw, map[string]interface{}{"data": map[string]string{"signature": m.signature}})
+}
+
+func (m *mockTransit) write(rw http.ResponseWriter, resp interface{}) {
+	require.NoError(m.t, json.NewEncoder(rw).Encode(resp))
+}
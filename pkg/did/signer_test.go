@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+// hsmKey hides the concrete private key type, like a key held in an HSM
+type hsmKey struct {
+	crypto.Signer
+}
+
+func TestOpaqueSigner(t *testing.T) {
+	t.Run("test ecdsa key", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		signer, publicKey, err := getSigner(&hsmKey{privateKey}, "k1")
+		require.NoError(t, err)
+		require.NotNil(t, publicKey)
+		require.Equal(t, "ES256", signer.Headers()[jws.HeaderAlgorithm])
+		require.Equal(t, "k1", signer.Headers()[jws.HeaderKeyID])
+
+		signature, err := signer.Sign([]byte("data"))
+		require.NoError(t, err)
+		require.Len(t, signature, 64)
+
+		digest := sha256.Sum256([]byte("data"))
+		require.True(t, ecdsa.Verify(&privateKey.PublicKey, digest[:],
+			new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+	})
+
+	t.Run("test ed25519 key", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		signer, _, err := getSigner(&hsmKey{privateKey}, "")
+		require.NoError(t, err)
+		require.Equal(t, "EdDSA", signer.Headers()[jws.HeaderAlgorithm])
+
+		_, ok := signer.Headers()[jws.HeaderKeyID]
+		require.False(t, ok)
+
+		signature, err := signer.Sign([]byte("data"))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(publicKey, []byte("data"), signature))
+	})
+
+	t.Run("test curve not supported", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = newOpaqueSigner(&hsmKey{privateKey}, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "curve not supported: P-384")
+		require.True(t, errors.Is(err, ErrKeyNotSupported))
+	})
+}
@@ -0,0 +1,26 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"time"
+)
+
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+	defaultKeepAlive       = 30 * time.Second
+
+	defaultDialTimeout = 30 * time.Second
+)
+
+// Close closes the idle connections of the client, to release them once it is no longer used. The client can still
+// be used after, opening new connections.
+func (c *Client) Close() error {
+	c.client.CloseIdleConnections()
+
+	return nil
+}
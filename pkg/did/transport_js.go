@@ -0,0 +1,20 @@
+//go:build js
+// +build js
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"net/http"
+)
+
+// newTransport returns the transport of the client in a browser, which sends the requests with the Fetch API. The
+// transport only uses the Fetch API without a dialer, so the dialer, proxy, TLS and connection pool options don't
+// apply, the browser handling the connections itself.
+func (c *Client) newTransport() *http.Transport {
+	return &http.Transport{}
+}
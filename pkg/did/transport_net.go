@@ -0,0 +1,47 @@
+//go:build !js
+// +build !js
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/dialer"
+)
+
+const tlsHandshakeTimeout = 10 * time.Second
+
+// newTransport returns the transport of the client, shared by its sidetree requests and its config fetches so that
+// their connections are pooled. The requests of a client mostly going to the same endpoint, the idle connections are
+// limited per host as much as in total. The requests go through the proxy of WithProxyURL, or else through the proxy
+// set by the environment.
+func (c *Client) newTransport() *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if c.proxyURL != nil {
+		proxy = http.ProxyURL(c.proxyURL)
+	}
+
+	return &http.Transport{
+		Proxy:               proxy,
+		DialContext:         dialer.New(c.dialTimeout, c.keepAlive, c.resolver, c.allowedNetworks).DialContext,
+		TLSClientConfig:     c.transportTLSConfig(),
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+		MaxIdleConns:        c.maxIdleConns,
+		MaxIdleConnsPerHost: c.maxIdleConns,
+		IdleConnTimeout:     c.idleConnTimeout,
+	}
+}
+
+// transportTLSConfig returns the TLS config of WithTLSConfig, with the TLS options of WithClientCertificates and
+// WithTLSOptions
+func (c *Client) transportTLSConfig() *tls.Config {
+	return tlsconfig.Apply(c.tlsConfig, c.tlsOpts...)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+// verifyCreated resolves the created DID in a span and checks that its anchored document has exactly the public keys
+// and services of the create options
+func (c *Client) verifyCreated(ctx context.Context, did, endpointURL string, opts *create.Opts) error {
+	_, span := c.tracer.Start(ctx, tracing.SpanVerifyDocument, map[string]string{tracing.AttributeDID: did})
+
+	err := c.verifyDocument(did, endpointURL, opts)
+
+	span.End(err)
+
+	return err
+}
+
+func (c *Client) verifyDocument(did, endpointURL string, opts *create.Opts) error {
+	var (
+		responseBytes []byte
+		err           error
+	)
+
+	// the DID doesn't resolve until its create operation is anchored
+	for attempt := 1; attempt <= opts.VerifyAttempts; attempt++ {
+		responseBytes, err = c.resolve(did, endpointURL)
+		if err == nil {
+			break
+		}
+
+		c.logger.Debug("created DID not resolved", "did", did, "attempt", attempt, "error", err)
+
+		if attempt < opts.VerifyAttempts {
+			time.Sleep(opts.VerifyInterval)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to resolve created DID %s after %d attempts: %w", did, opts.VerifyAttempts, err)
+	}
+
+	didDoc, err := parseDocument(responseBytes)
+	if err != nil {
+		return err
+	}
+
+	return compareDocument(didDoc, opts)
+}
+
+// compareDocument compares the IDs of the public keys and services of the document with the requested ones
+func compareDocument(didDoc *docdid.Doc, opts *create.Opts) error {
+	var requestedKeys, requestedServices []string
+
+	for i := range opts.PublicKeys {
+		requestedKeys = append(requestedKeys, opts.PublicKeys[i].ID)
+	}
+
+	for i := range opts.Services {
+		requestedServices = append(requestedServices, opts.Services[i].ID)
+	}
+
+	resolvedKeys, resolvedServices := documentIDs(didDoc)
+
+	var divergences []string
+
+	divergences = appendDivergences(divergences, "public keys", requestedKeys, resolvedKeys)
+	divergences = appendDivergences(divergences, "services", requestedServices, resolvedServices)
+
+	if len(divergences) != 0 {
+		return fmt.Errorf("%w: %s", ErrDocumentMismatch, strings.Join(divergences, "; "))
+	}
+
+	return nil
+}
+
+// documentIDs returns the IDs of the public keys and services of the document, relative to the DID
+func documentIDs(didDoc *docdid.Doc) (keys, services []string) {
+	for i := range didDoc.VerificationMethod {
+		keys = append(keys, fragment(didDoc.VerificationMethod[i].ID))
+	}
+
+	// the keys with a single purpose may be embedded in their verification relationship
+	for _, verifications := range [][]docdid.Verification{didDoc.Authentication, didDoc.AssertionMethod,
+		didDoc.KeyAgreement, didDoc.CapabilityDelegation, didDoc.CapabilityInvocation} {
+		for i := range verifications {
+			if verifications[i].Embedded {
+				keys = append(keys, fragment(verifications[i].VerificationMethod.ID))
+			}
+		}
+	}
+
+	for i := range didDoc.Service {
+		services = append(services, fragment(didDoc.Service[i].ID))
+	}
+
+	return keys, services
+}
+
+// appendDivergences appends the requested IDs missing from the resolved IDs and the resolved IDs which were not
+// requested to the divergences
+func appendDivergences(divergences []string, elements string, requested, resolved []string) []string {
+	if missing := subtractIDs(requested, resolved); len(missing) != 0 {
+		divergences = append(divergences, fmt.Sprintf("missing %s %s", elements, strings.Join(missing, ", ")))
+	}
+
+	if unexpected := subtractIDs(resolved, requested); len(unexpected) != 0 {
+		divergences = append(divergences, fmt.Sprintf("unexpected %s %s", elements, strings.Join(unexpected, ", ")))
+	}
+
+	return divergences
+}
+
+func subtractIDs(ids, subtracted []string) []string {
+	var result []string
+
+	for _, id := range ids {
+		if !containsID(subtracted, id) && !containsID(result, id) {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+// fragment returns the fragment of an ID relative to the DID, such as key1 for did:trustbloc:testnet:123#key1
+func fragment(id string) string {
+	return id[strings.LastIndex(id, "#")+1:]
+}
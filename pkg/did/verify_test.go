@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
+	mocktracing "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/tracing"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+func TestCompareDocument(t *testing.T) {
+	opts := &create.Opts{PublicKeys: []doc.PublicKey{{ID: "key1"}, {ID: "key2"}},
+		Services: []docdid.Service{{ID: "svc1"}}}
+
+	t.Run("test matching document", func(t *testing.T) {
+		didDoc := &docdid.Doc{ID: "did:ex:123",
+			VerificationMethod: []docdid.VerificationMethod{{ID: "did:ex:123#key1"}},
+			Authentication: []docdid.Verification{{VerificationMethod: docdid.VerificationMethod{ID: "#key2"},
+				Embedded: true}, {VerificationMethod: docdid.VerificationMethod{ID: "did:ex:123#key1"}}},
+			Service: []docdid.Service{{ID: "#svc1"}}}
+
+		require.NoError(t, compareDocument(didDoc, opts))
+	})
+
+	t.Run("test diverging document", func(t *testing.T) {
+		didDoc := &docdid.Doc{ID: "did:ex:123",
+			VerificationMethod: []docdid.VerificationMethod{{ID: "did:ex:123#key1"}, {ID: "did:ex:123#key3"}},
+			Service:            []docdid.Service{{ID: "#svc2"}}}
+
+		err := compareDocument(didDoc, opts)
+		require.True(t, errors.Is(err, ErrDocumentMismatch))
+		require.EqualError(t, err, "anchored document doesn't match the requested document: missing public keys "+
+			"key2; unexpected public keys key3; missing services svc1; unexpected services svc2")
+	})
+}
+
+func TestClient_CreateDIDWithVerification(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	updatePubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	anchored := &docdid.Doc{Context: []string{docdid.ContextV1}, ID: "did:ex:123",
+		VerificationMethod: []docdid.VerificationMethod{{ID: "did:ex:123#key1", Type: doc.Ed25519VerificationKey2018,
+			Controller: "did:ex:123", Value: pubKey}}}
+
+	anchoredBytes, err := anchored.JSONBytes()
+	require.NoError(t, err)
+
+	var resolutions int32
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			fmt.Fprint(w, string(anchoredBytes))
+
+			return
+		}
+
+		require.Equal(t, "/identifiers/did:ex:123", r.URL.Path)
+
+		// the DID is anchored after the second resolution
+		if atomic.AddInt32(&resolutions, 1) <= 2 {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"didDocument": %s, "methodMetadata": {}}`, anchoredBytes)
+	}))
+	defer serv.Close()
+
+	tracer := &mocktracing.Tracer{}
+
+	v := New(WithTracer(tracer))
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	createOpts := []create.Option{create.WithSidetreeEndpoint(serv.URL), create.WithRecoveryPublicKey(pubKey),
+		create.WithUpdatePublicKey(updatePubKey), create.WithPublicKey(&doc.PublicKey{ID: "key1",
+			Type: doc.JWSVerificationKey2020, Encoding: doc.PublicKeyEncodingJwk, KeyType: doc.Ed25519KeyType,
+			Value: pubKey, Purposes: []string{doc.KeyPurposeAuthentication}})}
+
+	var didDoc *docdid.Doc
+
+	t.Run("test not anchored", func(t *testing.T) {
+		didDoc, err = v.CreateDID("", append(createOpts, create.WithVerification(2, time.Millisecond))...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve created DID did:ex:123 after 2 attempts")
+		require.Equal(t, "did:ex:123", didDoc.ID)
+	})
+
+	t.Run("test anchored", func(t *testing.T) {
+		didDoc, err = v.CreateDID("", append(createOpts, create.WithVerification(2, time.Millisecond))...)
+		require.NoError(t, err)
+		require.Equal(t, "did:ex:123", didDoc.ID)
+		require.Len(t, tracer.Spans(tracing.SpanVerifyDocument), 2)
+	})
+
+	t.Run("test diverging document", func(t *testing.T) {
+		didDoc, err = v.CreateDID("", append(createOpts, create.WithVerification(1, time.Millisecond),
+			create.WithService(&docdid.Service{ID: "svc1"}))...)
+		require.True(t, errors.Is(err, ErrDocumentMismatch))
+		require.Contains(t, err.Error(), "missing services svc1")
+		require.Equal(t, "did:ex:123", didDoc.ID)
+	})
+}
@@ -0,0 +1,48 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ProtocolVersion01 is the sidetree protocol version of the requests built with the sidetree client of
+	// sidetree-core-go versions/0_1, the default protocol version of the client
+	ProtocolVersion01 = "0.1"
+	// ProtocolVersion1 is the protocol version of the DIF Sidetree v1 specification, whose requests have the type,
+	// suffixData, delta, revealValue and signedData properties of https://identity.foundation/sidetree/spec/v1.0.0
+	ProtocolVersion1 = "1.0"
+)
+
+// checkProtocolVersion checks that the sidetree endpoint implements the expected protocol version, the patch
+// versions of a protocol version being compatible
+func checkProtocolVersion(endpointURL, version, expected string) error {
+	if version == "" {
+		return fmt.Errorf("%w: %s doesn't report its sidetree protocol version, expected version %s",
+			ErrIncompatibleEndpoint, endpointURL, expected)
+	}
+
+	v := strings.TrimPrefix(version, "v")
+
+	if v != expected && !strings.HasPrefix(v, expected+".") {
+		return fmt.Errorf("%w: %s implements sidetree protocol version %s, expected version %s",
+			ErrIncompatibleEndpoint, endpointURL, version, expected)
+	}
+
+	return nil
+}
+
+// checkSupportedProtocolVersion checks that the client can build the requests of the protocol version
+func checkSupportedProtocolVersion(version string) error {
+	if version != ProtocolVersion01 && version != ProtocolVersion1 {
+		return fmt.Errorf("unsupported sidetree protocol version %s, expected version %s or %s", version,
+			ProtocolVersion01, ProtocolVersion1)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func TestCheckProtocolVersion(t *testing.T) {
+	for _, version := range []string{"0.1", "0.1.0", "v0.1.5"} {
+		require.NoError(t, checkProtocolVersion("https://sidetree", version, ProtocolVersion01), version)
+	}
+
+	for _, version := range []string{"", "0.2", "1.0.0", "0.10.1"} {
+		err := checkProtocolVersion("https://sidetree", version, ProtocolVersion01)
+		require.True(t, errors.Is(err, ErrIncompatibleEndpoint), version)
+	}
+
+	err := checkProtocolVersion("https://sidetree", "1.0.0", ProtocolVersion01)
+	require.EqualError(t, err, "incompatible sidetree endpoint: https://sidetree implements sidetree protocol "+
+		"version 1.0.0, expected version 0.1")
+
+	require.NoError(t, checkProtocolVersion("https://sidetree", "1.0.0", ProtocolVersion1))
+}
+
+func TestCheckSupportedProtocolVersion(t *testing.T) {
+	require.NoError(t, checkSupportedProtocolVersion(ProtocolVersion01))
+	require.NoError(t, checkSupportedProtocolVersion(ProtocolVersion1))
+	require.EqualError(t, checkSupportedProtocolVersion("0.2"),
+		"unsupported sidetree protocol version 0.2, expected version 0.1 or 1.0")
+}
+
+func TestClient_WithProtocolCheck(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	nextPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	version := "0.1.0"
+
+	v := New(WithProtocolCheck())
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18, Version: version}, nil
+		}}
+
+	t.Run("test compatible endpoint", func(t *testing.T) {
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL), update.WithSigningKey(privKey),
+			update.WithNextUpdatePublicKey(nextPubKey), update.WithRemoveService("svc1"))
+		require.NoError(t, err)
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privKey))
+		require.NoError(t, err)
+	})
+
+	t.Run("test incompatible endpoint", func(t *testing.T) {
+		version = "1.0.0"
+
+		err = v.UpdateDID("did:ex:123", "", update.WithSidetreeEndpoint(serv.URL), update.WithSigningKey(privKey),
+			update.WithNextUpdatePublicKey(privKey.Public()))
+		require.True(t, errors.Is(err, ErrIncompatibleEndpoint))
+
+		err = v.DeactivateDID("did:ex:123", "", deactivate.WithSidetreeEndpoint(serv.URL),
+			deactivate.WithSigningKey(privKey))
+		require.True(t, errors.Is(err, ErrIncompatibleEndpoint))
+	})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/didweb"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+var errUpdateNotAnchored = errors.New("update not anchored yet")
+
+// checkDIDWeb checks that the did:web document of the domain path, if any, can be published, before the operation
+// is sent
+func (c *Client) checkDIDWeb(domainPath string) error {
+	if domainPath != "" && c.didWebPublisher == nil {
+		return fmt.Errorf("%w: can't publish the did:web document of %s", ErrNoDIDWebPublisher, domainPath)
+	}
+
+	return nil
+}
+
+// publishDIDWeb publishes the did:web document of the domain path equivalent to the DID document
+func (c *Client) publishDIDWeb(didDoc *docdid.Doc, domainPath string) error {
+	webDoc, err := didweb.Document(didDoc, domainPath)
+	if err != nil {
+		return err
+	}
+
+	if err := c.didWebPublisher.Publish(domainPath, webDoc); err != nil {
+		return fmt.Errorf("failed to publish the did:web document of %s: %w", domainPath, err)
+	}
+
+	c.logger.Debug("did:web document published", "did", didDoc.ID, "didWeb", didweb.ID(domainPath))
+
+	return nil
+}
+
+// publishUpdatedDIDWeb publishes the did:web document of the updated DID, if requested, once the resolved document
+// reflects the update
+func (c *Client) publishUpdatedDIDWeb(did, endpointURL string, opts *update.Opts) error {
+	if opts.DIDWebPath == "" {
+		return nil
+	}
+
+	attempts := opts.DIDWebAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var (
+		didDoc *docdid.Doc
+		err    error
+	)
+
+	// the resolved document doesn't reflect the update until it is anchored
+	for attempt := 1; attempt <= attempts; attempt++ {
+		didDoc, err = c.resolveUpdated(did, endpointURL, opts)
+		if err == nil {
+			break
+		}
+
+		c.logger.Debug("updated document not resolved", "did", did, "attempt", attempt, "error", err)
+
+		if attempt < attempts {
+			time.Sleep(opts.DIDWebInterval)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to resolve updated DID %s after %d attempts: %w", did, attempts, err)
+	}
+
+	return c.publishDIDWeb(didDoc, opts.DIDWebPath)
+}
+
+// resolveUpdated resolves the DID and returns its document if it has the public keys and services added by the
+// update and no longer has the removed ones
+func (c *Client) resolveUpdated(did, endpointURL string, opts *update.Opts) (*docdid.Doc, error) {
+	responseBytes, err := c.resolve(did, endpointURL)
+	if err != nil {
+		return nil, err
+	}
+
+	didDoc, err := parseDocument(responseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if !updateApplied(didDoc, opts) {
+		return nil, errUpdateNotAnchored
+	}
+
+	return didDoc, nil
+}
+
+func updateApplied(didDoc *docdid.Doc, opts *update.Opts) bool {
+	keys, services := documentIDs(didDoc)
+
+	for i := range opts.AddPublicKeys {
+		if !containsID(keys, opts.AddPublicKeys[i].ID) {
+			return false
+		}
+	}
+
+	for i := range opts.AddServices {
+		if !containsID(services, opts.AddServices[i].ID) {
+			return false
+		}
+	}
+
+	for _, id := range opts.RemovePublicKeys {
+		if containsID(keys, id) {
+			return false
+		}
+	}
+
+	for _, id := range opts.RemoveServices {
+		if containsID(services, id) {
+			return false
+		}
+	}
+
+	return true
+}
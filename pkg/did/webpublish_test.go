@@ -0,0 +1,144 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func TestClient_DIDWeb(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	recoveryPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	nextUpdatePubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	created := &docdid.Doc{Context: []string{docdid.ContextV1}, ID: "did:ex:123",
+		VerificationMethod: []docdid.VerificationMethod{{ID: "did:ex:123#key1", Type: doc.Ed25519VerificationKey2018,
+			Controller: "did:ex:123", Value: pubKey}}}
+
+	createdBytes, err := created.JSONBytes()
+	require.NoError(t, err)
+
+	updated := *created
+	updated.Service = []docdid.Service{{ID: "did:ex:123#svc1", Type: "type", ServiceEndpoint: "https://example.com"}}
+
+	updatedBytes, err := updated.JSONBytes()
+	require.NoError(t, err)
+
+	// the number of resolutions returning the document before the update
+	var notAnchored int32
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			atomic.StoreInt32(&notAnchored, 1)
+			fmt.Fprint(w, string(createdBytes))
+
+			return
+		}
+
+		if atomic.AddInt32(&notAnchored, -1) >= 0 {
+			fmt.Fprintf(w, `{"didDocument": %s}`, createdBytes)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"didDocument": %s}`, updatedBytes)
+	}))
+	defer serv.Close()
+
+	publisher := &mockPublisher{published: make(map[string][]byte)}
+
+	v := New(WithDIDWebPublisher(publisher))
+
+	v.configService = &mockconfig.MockConfigService{
+		GetSidetreeConfigFunc: func(string) (*models.SidetreeConfig, error) {
+			return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+		}}
+
+	updateOpts := []update.Option{update.WithSidetreeEndpoint(serv.URL), update.WithSigningKey(privKey),
+		update.WithNextUpdatePublicKey(nextUpdatePubKey), update.WithAddService(&docdid.Service{ID: "svc1", Type: "type",
+			ServiceEndpoint: "https://example.com"})}
+
+	t.Run("test create", func(t *testing.T) {
+		_, err = v.CreateDID("", create.WithSidetreeEndpoint(serv.URL), create.WithRecoveryPublicKey(recoveryPubKey),
+			create.WithUpdatePublicKey(pubKey), create.WithDIDWeb("example.com/users/alice"))
+		require.NoError(t, err)
+		require.Contains(t, string(publisher.published["example.com/users/alice"]),
+			`"id": "did:web:example.com:users:alice#key1"`)
+	})
+
+	t.Run("test update not anchored", func(t *testing.T) {
+		err = v.UpdateDID("did:ex:123", "", append(updateOpts,
+			update.WithDIDWeb("example.com/users/alice", 1, time.Millisecond))...)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resolve updated DID did:ex:123 after 1 attempts")
+		require.NotContains(t, string(publisher.published["example.com/users/alice"]), "svc1")
+	})
+
+	t.Run("test update", func(t *testing.T) {
+		err = v.UpdateDID("did:ex:123", "", append(updateOpts,
+			update.WithDIDWeb("example.com/users/alice", 2, time.Millisecond))...)
+		require.NoError(t, err)
+		require.Contains(t, string(publisher.published["example.com/users/alice"]),
+			`"id": "did:web:example.com:users:alice#svc1"`)
+	})
+
+	t.Run("test publish error", func(t *testing.T) {
+		publisher.err = errors.New("disk full")
+
+		_, err = v.CreateDID("", create.WithSidetreeEndpoint(serv.URL), create.WithRecoveryPublicKey(recoveryPubKey),
+			create.WithUpdatePublicKey(pubKey), create.WithDIDWeb("example.com/users/alice"))
+		require.EqualError(t, err, "failed to publish the did:web document of example.com/users/alice: disk full")
+	})
+
+	t.Run("test no publisher", func(t *testing.T) {
+		v = New()
+		v.configService = &mockconfig.MockConfigService{
+			GetSidetreeConfigFunc: func(string) (*models.SidetreeConfig, error) {
+				return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+			}}
+
+		_, err = v.CreateDID("", create.WithSidetreeEndpoint(serv.URL), create.WithRecoveryPublicKey(recoveryPubKey),
+			create.WithUpdatePublicKey(pubKey), create.WithDIDWeb("example.com/users/alice"))
+		require.True(t, errors.Is(err, ErrNoDIDWebPublisher))
+	})
+}
+
+type mockPublisher struct {
+	published map[string][]byte
+	err       error
+}
+
+func (p *mockPublisher) Publish(domainPath string, didJSON []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.published[domainPath] = didJSON
+
+	return nil
+}
@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.14.0
+// source: didmethod.proto
+
+package didmethodpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DIDMethodClient is the client API for DIDMethod service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DIDMethodClient interface {
+	// CreateDID creates a DID, with the recovery and update keys of the secret or else with generated keys
+	CreateDID(ctx context.Context, in *CreateDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// UpdateDID updates the DID document, signed with the update key of the secret or of the key store
+	UpdateDID(ctx context.Context, in *UpdateDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// RecoverDID replaces the DID document, signed with the recovery key of the secret or of the key store
+	RecoverDID(ctx context.Context, in *RecoverDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// DeactivateDID deactivates the DID, the deactivation token is sent in the x-deactivation-token metadata
+	DeactivateDID(ctx context.Context, in *DeactivateDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// ResolveDID resolves the DID document
+	ResolveDID(ctx context.Context, in *ResolveDIDRequest, opts ...grpc.CallOption) (*ResolveDIDResponse, error)
+}
+
+type dIDMethodClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDIDMethodClient(cc grpc.ClientConnInterface) DIDMethodClient {
+	return &dIDMethodClient{cc}
+}
+
+func (c *dIDMethodClient) CreateDID(ctx context.Context, in *CreateDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, "/trustbloc.didmethod.v1.DIDMethod/CreateDID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dIDMethodClient) UpdateDID(ctx context.Context, in *UpdateDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, "/trustbloc.didmethod.v1.DIDMethod/UpdateDID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dIDMethodClient) RecoverDID(ctx context.Context, in *RecoverDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, "/trustbloc.didmethod.v1.DIDMethod/RecoverDID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dIDMethodClient) DeactivateDID(ctx context.Context, in *DeactivateDIDRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, "/trustbloc.didmethod.v1.DIDMethod/DeactivateDID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dIDMethodClient) ResolveDID(ctx context.Context, in *ResolveDIDRequest, opts ...grpc.CallOption) (*ResolveDIDResponse, error) {
+	out := new(ResolveDIDResponse)
+	err := c.cc.Invoke(ctx, "/trustbloc.didmethod.v1.DIDMethod/ResolveDID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DIDMethodServer is the server API for DIDMethod service.
+// All implementations must embed UnimplementedDIDMethodServer
+// for forward compatibility
+type DIDMethodServer interface {
+	// CreateDID creates a DID, with the recovery and update keys of the secret or else with generated keys
+	CreateDID(context.Context, *CreateDIDRequest) (*RegisterResponse, error)
+	// UpdateDID updates the DID document, signed with the update key of the secret or of the key store
+	UpdateDID(context.Context, *UpdateDIDRequest) (*RegisterResponse, error)
+	// RecoverDID replaces the DID document, signed with the recovery key of the secret or of the key store
+	RecoverDID(context.Context, *RecoverDIDRequest) (*RegisterResponse, error)
+	// DeactivateDID deactivates the DID, the deactivation token is sent in the x-deactivation-token metadata
+	DeactivateDID(context.Context, *DeactivateDIDRequest) (*RegisterResponse, error)
+	// ResolveDID resolves the DID document
+	ResolveDID(context.Context, *ResolveDIDRequest) (*ResolveDIDResponse, error)
+	mustEmbedUnimplementedDIDMethodServer()
+}
+
+// UnimplementedDIDMethodServer must be embedded to have forward compatible implementations.
+type UnimplementedDIDMethodServer struct {
+}
+
+func (UnimplementedDIDMethodServer) CreateDID(context.Context, *CreateDIDRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDID not implemented")
+}
+func (UnimplementedDIDMethodServer) UpdateDID(context.Context, *UpdateDIDRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDID not implemented")
+}
+func (UnimplementedDIDMethodServer) RecoverDID(context.Context, *RecoverDIDRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecoverDID not implemented")
+}
+func (UnimplementedDIDMethodServer) DeactivateDID(context.Context, *DeactivateDIDRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateDID not implemented")
+}
+func (UnimplementedDIDMethodServer) ResolveDID(context.Context, *ResolveDIDRequest) (*ResolveDIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveDID not implemented")
+}
+func (UnimplementedDIDMethodServer) mustEmbedUnimplementedDIDMethodServer() {}
+
+// UnsafeDIDMethodServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DIDMethodServer will
+// result in compilation errors.
+type UnsafeDIDMethodServer interface {
+	mustEmbedUnimplementedDIDMethodServer()
+}
+
+func RegisterDIDMethodServer(s grpc.ServiceRegistrar, srv DIDMethodServer) {
+	s.RegisterService(&DIDMethod_ServiceDesc, srv)
+}
+
+func _DIDMethod_CreateDID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DIDMethodServer).CreateDID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustbloc.didmethod.v1.DIDMethod/CreateDID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DIDMethodServer).CreateDID(ctx, req.(*CreateDIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DIDMethod_UpdateDID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DIDMethodServer).UpdateDID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustbloc.didmethod.v1.DIDMethod/UpdateDID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DIDMethodServer).UpdateDID(ctx, req.(*UpdateDIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DIDMethod_RecoverDID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoverDIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DIDMethodServer).RecoverDID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustbloc.didmethod.v1.DIDMethod/RecoverDID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DIDMethodServer).RecoverDID(ctx, req.(*RecoverDIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DIDMethod_DeactivateDID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateDIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DIDMethodServer).DeactivateDID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustbloc.didmethod.v1.DIDMethod/DeactivateDID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DIDMethodServer).DeactivateDID(ctx, req.(*DeactivateDIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DIDMethod_ResolveDID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveDIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DIDMethodServer).ResolveDID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustbloc.didmethod.v1.DIDMethod/ResolveDID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DIDMethodServer).ResolveDID(ctx, req.(*ResolveDIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DIDMethod_ServiceDesc is the grpc.ServiceDesc for DIDMethod service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DIDMethod_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trustbloc.didmethod.v1.DIDMethod",
+	HandlerType: (*DIDMethodServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDID",
+			Handler:    _DIDMethod_CreateDID_Handler,
+		},
+		{
+			MethodName: "UpdateDID",
+			Handler:    _DIDMethod_UpdateDID_Handler,
+		},
+		{
+			MethodName: "RecoverDID",
+			Handler:    _DIDMethod_RecoverDID_Handler,
+		},
+		{
+			MethodName: "DeactivateDID",
+			Handler:    _DIDMethod_DeactivateDID_Handler,
+		},
+		{
+			MethodName: "ResolveDID",
+			Handler:    _DIDMethod_ResolveDID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "didmethod.proto",
+}
@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/correlation"
+)
+
+// HTTPRequest returns the request of the gRPC call as an HTTP request, with the metadata of the call as headers and
+// the address of the peer as remote address, so that the REST request checks apply to the gRPC calls
+func HTTPRequest(ctx context.Context, fullMethod string) *http.Request {
+	req := &http.Request{Method: http.MethodPost, URL: &url.URL{Path: fullMethod}, Header: make(http.Header)}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for name, values := range md {
+		// pseudo headers such as :authority are not request headers
+		if strings.HasPrefix(name, ":") {
+			continue
+		}
+
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	return req.WithContext(ctx)
+}
+
+// CorrelationInterceptor sets the correlation ID of the call, or a new one if the call has none, in the context and
+// the response header, and logs a summary of each call once it is served
+func CorrelationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(correlation.Header); len(values) > 0 {
+			id = values[0]
+		}
+
+		ctx, id = correlation.WithID(ctx, id)
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(correlation.Header, id)); err != nil {
+			log.Warnf("failed to set correlation id header: %s", err)
+		}
+
+		resp, err := handler(ctx, req)
+
+		log.WithFields(log.Fields{
+			"correlationId": id,
+			"method":        info.FullMethod,
+			"code":          status.Code(err).String(),
+		}).Info("call served")
+
+		return resp, err
+	}
+}
+
+// AuthInterceptor serves the calls authenticated by one of the authenticators, whose credentials are sent in the
+// metadata as in the headers of the REST requests, and rejects the other calls with code Unauthenticated. The
+// identity of the client is set in the context. All the calls are served if there are no authenticators.
+func AuthInterceptor(authenticators []auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if len(authenticators) == 0 {
+			return handler(ctx, req)
+		}
+
+		authCtx, err := auth.Authenticate(authenticators, HTTPRequest(ctx, info.FullMethod))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "missing or invalid credentials")
+		}
+
+		return handler(authCtx, req)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/correlation"
+)
+
+const fullMethod = "/trustbloc.didmethod.v1.DIDMethod/CreateDID"
+
+func TestHTTPRequest(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "key",
+		":authority", "localhost"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}})
+
+	req := HTTPRequest(ctx, fullMethod)
+	require.Equal(t, "key", req.Header.Get("X-API-Key"))
+	require.Empty(t, req.Header.Get(":authority"))
+	require.Equal(t, "127.0.0.1:1234", req.RemoteAddr)
+	require.Equal(t, fullMethod, req.URL.Path)
+}
+
+func TestCorrelationInterceptor(t *testing.T) {
+	interceptor := CorrelationInterceptor()
+
+	t.Run("test correlation id of the call", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-correlation-id", "id1"))
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				require.Equal(t, "id1", correlation.ID(ctx))
+
+				return nil, nil
+			})
+		require.NoError(t, err)
+	})
+
+	t.Run("test new correlation id", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: fullMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				require.NotEmpty(t, correlation.ID(ctx))
+
+				return nil, status.Error(codes.Internal, "error")
+			})
+		require.Error(t, err)
+	})
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	authenticator, err := auth.NewAPIKeyAuthenticator([]string{"client1=key1"})
+	require.NoError(t, err)
+
+	interceptor := AuthInterceptor([]auth.Authenticator{authenticator})
+
+	t.Run("test authenticated", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer key1"))
+
+		resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return auth.ClientID(ctx), nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, "client1", resp)
+	})
+
+	t.Run("test unauthenticated", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "other"))
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				require.Fail(t, "unauthenticated call served")
+
+				return nil, nil
+			})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("test authentication disabled", func(t *testing.T) {
+		resp, err := AuthInterceptor(nil)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: fullMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "served", nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, "served", resp)
+	})
+}
@@ -9,15 +9,36 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
 )
 
 // Client is the mock did bloc client
 type Client struct {
-	CreateDIDValue *did.Doc
-	CreateDIDErr   error
+	CreateDIDValue   *did.Doc
+	CreateDIDErr     error
+	UpdateDIDErr     error
+	RecoverDIDErr    error
+	DeactivateDIDErr error
 }
 
 // CreateDID create did
 func (c *Client) CreateDID(domain string, opts ...create.Option) (*did.Doc, error) {
 	return c.CreateDIDValue, c.CreateDIDErr
 }
+
+// UpdateDID update did
+func (c *Client) UpdateDID(did, domain string, opts ...update.Option) error {
+	return c.UpdateDIDErr
+}
+
+// RecoverDID recover did
+func (c *Client) RecoverDID(did, domain string, opts ...recovery.Option) error {
+	return c.RecoverDIDErr
+}
+
+// DeactivateDID deactivate did
+func (c *Client) DeactivateDID(did, domain string, opts ...deactivate.Option) error {
+	return c.DeactivateDIDErr
+}
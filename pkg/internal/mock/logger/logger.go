@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logger
+
+import (
+	"sync"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+)
+
+// Entry is a message logged by the mock logger
+type Entry struct {
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Logger is a mock logger that records the messages in memory
+type Logger struct {
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+// Debug records a debug message
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log("debug", msg, keyvals)
+}
+
+// Info records an info message
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log("info", msg, keyvals)
+}
+
+// Warn records a warning message
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log("warn", msg, keyvals)
+}
+
+// Error records an error message
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log("error", msg, keyvals)
+}
+
+// Entry returns the first message recorded with the message text, or nil
+func (l *Logger) Entry(msg string) *Entry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i := range l.entries {
+		if l.entries[i].Message == msg {
+			return &l.entries[i]
+		}
+	}
+
+	return nil
+}
+
+func (l *Logger) log(level, msg string, keyvals []interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries = append(l.entries, Entry{Level: level, Message: msg, Fields: logger.Fields(keyvals...)})
+}
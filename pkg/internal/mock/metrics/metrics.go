@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	labelDomain    = "domain"
+	labelStatus    = "status"
+	labelOperation = "operation"
+)
+
+// Provider is a mock metrics provider that records metrics in memory
+type Provider struct {
+	mutex        sync.Mutex
+	counters     map[string]int
+	observations map[string][]time.Duration
+}
+
+// NewProvider returns a new mock metrics provider
+func NewProvider() *Provider {
+	return &Provider{counters: map[string]int{}, observations: map[string][]time.Duration{}}
+}
+
+// IncrementCounter increments the named counter
+func (p *Provider) IncrementCounter(name string, labels map[string]string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.counters[key(name, labels[labelDomain], labels[labelOperation], labels[labelStatus])]++
+}
+
+// ObserveDuration records an observation in the named histogram
+func (p *Provider) ObserveDuration(name string, duration time.Duration, labels map[string]string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	k := key(name, labels[labelDomain], labels[labelOperation], labels[labelStatus])
+	p.observations[k] = append(p.observations[k], duration)
+}
+
+// Count returns the value of the named counter for the given domain and status
+func (p *Provider) Count(name, domain, status string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.counters[key(name, domain, "", status)]
+}
+
+// OperationCount returns the value of the named counter for the given domain, operation type and status
+func (p *Provider) OperationCount(name, domain, operation, status string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.counters[key(name, domain, operation, status)]
+}
+
+// Observations returns the number of observations in the named histogram for the given domain and status
+func (p *Provider) Observations(name, domain, status string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.observations[key(name, domain, "", status)])
+}
+
+// OperationObservations returns the number of observations in the named histogram for the given domain, operation
+// type and status
+func (p *Provider) OperationObservations(name, domain, operation, status string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.observations[key(name, domain, operation, status)])
+}
+
+func key(name, domain, operation, status string) string {
+	return name + "|" + domain + "|" + operation + "|" + status
+}
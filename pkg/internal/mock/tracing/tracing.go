@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+// SpanHeader is the header the mock tracer injects the name of the span of the context into
+const SpanHeader = "X-Mock-Span"
+
+type spanKey struct{}
+
+// Span is a span recorded by the mock tracer
+type Span struct {
+	Name       string
+	Parent     string
+	Attributes map[string]string
+	Err        error
+	Ended      bool
+
+	tracer *Tracer
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+
+	s.Attributes[key] = value
+}
+
+// End ends the span with the error
+func (s *Span) End(err error) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+
+	s.Err = err
+	s.Ended = true
+}
+
+// Tracer is a mock tracer that records the spans in memory
+type Tracer struct {
+	mutex sync.Mutex
+	spans []*Span
+}
+
+// Start records a span, child of the span of the context if any
+func (t *Tracer) Start(ctx context.Context, name string,
+	attributes map[string]string) (context.Context, tracing.Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s := &Span{Name: name, Attributes: map[string]string{}, tracer: t}
+
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		s.Parent = parent.Name
+	}
+
+	for key, value := range attributes {
+		s.Attributes[key] = value
+	}
+
+	t.spans = append(t.spans, s)
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Inject sets the SpanHeader header to the name of the span of the context
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	if s, ok := ctx.Value(spanKey{}).(*Span); ok {
+		header.Set(SpanHeader, s.Name)
+	}
+}
+
+// Spans returns the spans recorded with the name
+func (t *Tracer) Spans(name string) []*Span {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var spans []*Span
+
+	for _, s := range t.spans {
+		if s.Name == name {
+			spans = append(spans, s)
+		}
+	}
+
+	return spans
+}
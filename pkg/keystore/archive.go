@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
+)
+
+const archiveType = "did-key-archive"
+
+// Archive is the key material of a DID with the history of its commitments, exported in a single encrypted archive
+// to migrate the DID between machines or to restore it from a backup
+type Archive struct {
+	KeySet      *KeySet                      `json:"keySet"`
+	Commitments *commitmentstore.Commitments `json:"commitments,omitempty"`
+	Created     time.Time                    `json:"created"`
+}
+
+// ExportArchive encrypts the archive with a key derived from the password, in the JSON envelope of the keystore
+func ExportArchive(archive *Archive, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("archive password is required")
+	}
+
+	if archive.KeySet == nil {
+		return nil, errors.New("archive has no key set")
+	}
+
+	plaintext, err := json.Marshal(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal archive: %w", err)
+	}
+
+	f, err := newFile(archiveType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.seal(plaintext, password); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// ImportArchive decrypts an archive exported with ExportArchive
+func ImportArchive(data, password []byte) (*Archive, error) {
+	f := &file{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse archive: %w", err)
+	}
+
+	if f.Type != archiveType {
+		return nil, errors.New("not a key archive")
+	}
+
+	plaintext, err := f.open(password)
+	if err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+
+	archive := &Archive{}
+	if err = json.Unmarshal(plaintext, archive); err != nil {
+		return nil, fmt.Errorf("failed to parse archive content: %w", err)
+	}
+
+	if archive.KeySet == nil {
+		return nil, errors.New("archive has no key set")
+	}
+
+	return archive, nil
+}
+
+// NewArchive returns the archive of the key set, with the commitments of its DID if not nil
+func NewArchive(keySet *KeySet, commitments *commitmentstore.Commitments) *Archive {
+	return &Archive{KeySet: keySet, Commitments: commitments, Created: time.Now().UTC()}
+}
+
+// Restore adds the key set of the archive to the keystore, under the alias of the archived key set unless an alias
+// is given, and saves the keystore. It fails if the keystore has a key set with the alias or with the DID.
+func (ks *Keystore) Restore(archive *Archive, alias string) (*KeySet, error) {
+	if alias == "" {
+		alias = archive.KeySet.Alias
+	}
+
+	if ks.KeySet(alias) != nil {
+		return nil, fmt.Errorf("key alias '%s' already exists in keystore", alias)
+	}
+
+	if archive.KeySet.DID != "" && ks.KeySetByDID(archive.KeySet.DID) != nil {
+		return nil, fmt.Errorf("keys of %s already exist in keystore", archive.KeySet.DID)
+	}
+
+	keySet := &KeySet{Alias: alias, DID: archive.KeySet.DID, Keys: make(map[string]string)}
+
+	for purpose, key := range archive.KeySet.Keys {
+		keySet.Keys[purpose] = key
+
+		if _, err := keySet.PrivateKey(purpose); err != nil {
+			return nil, err
+		}
+	}
+
+	ks.addKeySet(keySet)
+
+	return keySet, ks.Save()
+}
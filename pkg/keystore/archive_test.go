@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystore
+
+import (
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/commitmentstore"
+)
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	ks, err := Create(filepath.Join(dir, "keystore.json"), []byte("password"))
+	require.NoError(t, err)
+
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, ks.AddKey("alias1", "did:ex:123", PurposeRecovery, privateKey))
+
+	commitments := &commitmentstore.Commitments{DID: "did:ex:123", MultihashAlgorithm: 18,
+		RecoveryCommitment: "c2", PreviousCommitments: []string{"c1"}}
+
+	t.Run("test export and restore", func(t *testing.T) {
+		data, err := ExportArchive(NewArchive(ks.KeySet("alias1"), commitments), []byte("archive"))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "did:ex:123")
+
+		archive, err := ImportArchive(data, []byte("archive"))
+		require.NoError(t, err)
+		require.Equal(t, commitments, archive.Commitments)
+		require.False(t, archive.Created.IsZero())
+
+		other, err := Create(filepath.Join(dir, "other.json"), []byte("password"))
+		require.NoError(t, err)
+
+		keySet, err := other.Restore(archive, "")
+		require.NoError(t, err)
+		require.Equal(t, "alias1", keySet.Alias)
+
+		other, err = Open(filepath.Join(dir, "other.json"), []byte("password"))
+		require.NoError(t, err)
+
+		key, err := other.KeySetByDID("did:ex:123").PrivateKey(PurposeRecovery)
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+
+		_, err = other.Restore(archive, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key alias 'alias1' already exists in keystore")
+
+		_, err = other.Restore(archive, "alias2")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "keys of did:ex:123 already exist in keystore")
+	})
+
+	t.Run("test import errors", func(t *testing.T) {
+		data, err := ExportArchive(NewArchive(ks.KeySet("alias1"), nil), []byte("archive"))
+		require.NoError(t, err)
+
+		_, err = ImportArchive(data, []byte("wrong"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the password may be wrong")
+
+		_, err = ImportArchive([]byte("{"), []byte("archive"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse archive")
+
+		keystoreData, err := ioutil.ReadFile(filepath.Join(dir, "keystore.json"))
+		require.NoError(t, err)
+
+		_, err = ImportArchive(keystoreData, []byte("password"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a key archive")
+
+		archivePath := filepath.Join(dir, "archive.json")
+		require.NoError(t, ioutil.WriteFile(archivePath, data, 0600))
+
+		_, err = Open(archivePath, []byte("archive"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a keystore but a did-key-archive")
+	})
+
+	t.Run("test import invalid key derivation parameters", func(t *testing.T) {
+		_, err := ImportArchive([]byte(`{"type":"did-key-archive","version":1,"kdf":"argon2id",`+
+			`"time":1,"memory":4294967295,"threads":1}`), []byte("archive"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "archive: kdf memory must be between")
+	})
+
+	t.Run("test export errors", func(t *testing.T) {
+		_, err := ExportArchive(NewArchive(ks.KeySet("alias1"), nil), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "archive password is required")
+
+		_, err = ExportArchive(NewArchive(nil, nil), []byte("archive"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "archive has no key set")
+	})
+
+	t.Run("test restore invalid key", func(t *testing.T) {
+		archive := NewArchive(&KeySet{Alias: "alias3", Keys: map[string]string{PurposeUpdate: "invalid"}}, nil)
+
+		_, err := ks.Restore(archive, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update key of alias 'alias3' not found in PEM")
+	})
+}
@@ -0,0 +1,394 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package keystore is a local store of the update, recovery and next commitment keys of DIDs, encrypted at rest
+// with an AES-GCM key derived from a password with argon2id.
+package keystore
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// PurposeUpdate is the purpose of the current update key
+	PurposeUpdate = "update"
+	// PurposeRecovery is the purpose of the current recovery key
+	PurposeRecovery = "recovery"
+	// PurposeNextUpdate is the purpose of the key committed to for the next update
+	PurposeNextUpdate = "next-update"
+	// PurposeNextRecovery is the purpose of the key committed to for the next recovery
+	PurposeNextRecovery = "next-recovery"
+
+	version     = 1
+	kdf         = "argon2id"
+	saltSize    = 16
+	keySize     = 32
+	kdfTime     = 3
+	kdfMemory   = 64 * 1024
+	kdfThreads  = 4
+	permissions = 0600
+
+	privateKeyPEMType = "PRIVATE KEY"
+)
+
+// the key derivation parameters read from the files are bounded, argon2 panics without time or threads and could
+// exhaust the memory, which is in KiB
+const (
+	maxKDFTime         = 16
+	maxKDFMemory       = 256 * 1024
+	maxKDFThreads      = 64
+	kdfMemoryPerThread = 8
+)
+
+// KeySet is the set of keys of a DID in the keystore, by purpose
+type KeySet struct {
+	Alias string            `json:"alias"`
+	DID   string            `json:"did,omitempty"`
+	Keys  map[string]string `json:"keys"`
+}
+
+// Keystore is a local store of the keys of DIDs, encrypted at rest with a key derived from a password.
+// The key sets are found by alias or by DID.
+type Keystore struct {
+	KeySets []*KeySet
+
+	path     string
+	password []byte
+	file     *file
+}
+
+// file is the JSON envelope of the keystore, and of the archives, with the parameters of the key derivation and
+// the encrypted content
+type file struct {
+	Type       string `json:"type,omitempty"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt"`
+	Time       uint32 `json:"time"`
+	Memory     uint32 `json:"memory"`
+	Threads    uint8  `json:"threads"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type content struct {
+	KeySets []*KeySet `json:"keySets"`
+}
+
+// Create creates an empty keystore file encrypted with the password. It fails if the file exists.
+func Create(path string, password []byte) (*Keystore, error) {
+	if len(password) == 0 {
+		return nil, errors.New("keystore password is required")
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keystore '%s' already exists", path)
+	}
+
+	f, err := newFile("")
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &Keystore{path: path, password: password, file: f}
+
+	if err := ks.Save(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Open reads and decrypts the keystore file
+func Open(path string, password []byte) (*Keystore, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore '%s': %w", path, err)
+	}
+
+	f := &file{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore '%s': %w", path, err)
+	}
+
+	if f.Type != "" {
+		return nil, fmt.Errorf("'%s' is not a keystore but a %s", path, f.Type)
+	}
+
+	plaintext, err := f.open(password)
+	if err != nil {
+		return nil, fmt.Errorf("keystore '%s': %w", path, err)
+	}
+
+	c := &content{}
+	if err := json.Unmarshal(plaintext, c); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore content: %w", err)
+	}
+
+	return &Keystore{KeySets: c.KeySets, path: path, password: password, file: f}, nil
+}
+
+// Save encrypts the key sets and writes them to the keystore file
+func (ks *Keystore) Save() error {
+	plaintext, err := json.Marshal(&content{KeySets: ks.KeySets})
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore content: %w", err)
+	}
+
+	if err = ks.file.seal(plaintext, ks.password); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(ks.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore: %w", err)
+	}
+
+	// write to a temporary file first, so that the keystore isn't lost if writing fails
+	tmp := ks.path + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, data, permissions); err != nil {
+		return fmt.Errorf("failed to write keystore '%s': %w", ks.path, err)
+	}
+
+	if err := os.Rename(tmp, ks.path); err != nil {
+		return fmt.Errorf("failed to write keystore '%s': %w", ks.path, err)
+	}
+
+	return nil
+}
+
+// KeySet returns the key set of the alias, or nil if there is none
+func (ks *Keystore) KeySet(alias string) *KeySet {
+	for _, keySet := range ks.KeySets {
+		if keySet.Alias == alias {
+			return keySet
+		}
+	}
+
+	return nil
+}
+
+// KeySetByDID returns the key set of the DID, or nil if there is none
+func (ks *Keystore) KeySetByDID(did string) *KeySet {
+	for _, keySet := range ks.KeySets {
+		if keySet.DID == did {
+			return keySet
+		}
+	}
+
+	return nil
+}
+
+// AddKey adds the private key with the purpose to the key set of the alias, creating the key set if needed.
+// An existing key with the same purpose is replaced. Go clients which don't name their key sets may use the DID
+// as the alias, once it is known.
+func (ks *Keystore) AddKey(alias, did, purpose string, privateKey crypto.PrivateKey) error {
+	switch purpose {
+	case PurposeUpdate, PurposeRecovery, PurposeNextUpdate, PurposeNextRecovery:
+	default:
+		return fmt.Errorf("invalid key purpose '%s'", purpose)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s key: %w", purpose, err)
+	}
+
+	keySet := ks.KeySet(alias)
+	if keySet == nil {
+		keySet = &KeySet{Alias: alias, Keys: make(map[string]string)}
+		ks.addKeySet(keySet)
+	}
+
+	if did != "" {
+		keySet.DID = did
+	}
+
+	keySet.Keys[purpose] = string(pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: der}))
+
+	return nil
+}
+
+// addKeySet adds the key set, the key sets being sorted by alias
+func (ks *Keystore) addKeySet(keySet *KeySet) {
+	ks.KeySets = append(ks.KeySets, keySet)
+
+	sort.Slice(ks.KeySets, func(i, j int) bool { return ks.KeySets[i].Alias < ks.KeySets[j].Alias })
+}
+
+// Commit records the DID of the key set, promotes the next keys of the purposes and saves the keystore, after a
+// successful operation revealed the keys of the purposes. It does nothing if the key set is nil, even on a nil
+// keystore.
+func (ks *Keystore) Commit(keySet *KeySet, did string, promotedPurposes ...string) error {
+	if keySet == nil {
+		return nil
+	}
+
+	keySet.DID = did
+
+	for _, purpose := range promotedPurposes {
+		keySet.Promote(purpose)
+	}
+
+	return ks.Save()
+}
+
+// PrivateKey returns the private key of the purpose, or nil if the key set is nil or has no key of the purpose
+func (s *KeySet) PrivateKey(purpose string) (crypto.PrivateKey, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	keyPEM, ok := s.Keys[purpose]
+	if !ok {
+		return nil, nil
+	}
+
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("%s key of alias '%s' not found in PEM", purpose, s.Alias)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s key of alias '%s': %w", purpose, s.Alias, err)
+	}
+
+	return privateKey, nil
+}
+
+// PublicKey returns the public key of the private key of the purpose, or nil if the key set is nil or has no
+// key of the purpose
+func (s *KeySet) PublicKey(purpose string) (crypto.PublicKey, error) {
+	privateKey, err := s.PrivateKey(purpose)
+	if err != nil || privateKey == nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s key of alias '%s' not supported", purpose, s.Alias)
+	}
+
+	return signer.Public(), nil
+}
+
+// Promote replaces the key of the purpose (update or recovery) with the next key of the purpose, once an operation
+// revealed the key of the purpose and committed to the next key. It does nothing if there is no next key.
+func (s *KeySet) Promote(purpose string) {
+	nextPurpose := PurposeNextUpdate
+	if purpose == PurposeRecovery {
+		nextPurpose = PurposeNextRecovery
+	}
+
+	if key, ok := s.Keys[nextPurpose]; ok {
+		s.Keys[purpose] = key
+		delete(s.Keys, nextPurpose)
+	}
+}
+
+func newFile(typ string) (*file, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return &file{Type: typ, Version: version, KDF: kdf, Salt: salt, Time: kdfTime, Memory: kdfMemory,
+		Threads: kdfThreads}, nil
+}
+
+// seal encrypts the plaintext as the content of the file, with a new nonce
+func (f *file) seal(plaintext, password []byte) error {
+	gcm, err := newCipher(f, password)
+	if err != nil {
+		return err
+	}
+
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(f.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	f.Ciphertext = gcm.Seal(nil, f.Nonce, plaintext, nil)
+
+	return nil
+}
+
+// open decrypts the content of the file
+func (f *file) open(password []byte) ([]byte, error) {
+	if f.Version != version || f.KDF != kdf {
+		return nil, fmt.Errorf("version %d with kdf '%s' not supported", f.Version, f.KDF)
+	}
+
+	err := f.validateKDF()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newCipher(f, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("nonce size must be %d", gcm.NonceSize())
+	}
+
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt, the password may be wrong")
+	}
+
+	return plaintext, nil
+}
+
+// validateKDF checks the key derivation parameters of the file before deriving its key
+func (f *file) validateKDF() error {
+	if f.Time < 1 || f.Time > maxKDFTime {
+		return fmt.Errorf("kdf time must be between 1 and %d", maxKDFTime)
+	}
+
+	if f.Threads < 1 || f.Threads > maxKDFThreads {
+		return fmt.Errorf("kdf threads must be between 1 and %d", maxKDFThreads)
+	}
+
+	minMemory := kdfMemoryPerThread * uint32(f.Threads)
+	if f.Memory < minMemory || f.Memory > maxKDFMemory {
+		return fmt.Errorf("kdf memory must be between %d and %d KiB", minMemory, maxKDFMemory)
+	}
+
+	return nil
+}
+
+func newCipher(f *file, password []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey(password, f.Salt, f.Time, f.Memory, f.Threads, keySize)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keystore cipher: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "keystore.json")
+
+	t.Run("success", func(t *testing.T) {
+		ks, err := Create(path, []byte("password"))
+		require.NoError(t, err)
+
+		_, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		ecPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		require.NoError(t, ks.AddKey("alias1", "did:ex:123", PurposeUpdate, privateKey))
+		require.NoError(t, ks.AddKey("alias1", "", PurposeNextUpdate, ecPrivateKey))
+		require.NoError(t, ks.Save())
+
+		ks, err = Open(path, []byte("password"))
+		require.NoError(t, err)
+
+		keySet := ks.KeySet("alias1")
+		require.NotNil(t, keySet)
+		require.Equal(t, "did:ex:123", keySet.DID)
+		require.Equal(t, keySet, ks.KeySetByDID("did:ex:123"))
+
+		key, err := keySet.PrivateKey(PurposeUpdate)
+		require.NoError(t, err)
+		require.Equal(t, privateKey, key)
+
+		publicKey, err := keySet.PublicKey(PurposeNextUpdate)
+		require.NoError(t, err)
+		require.Equal(t, ecPrivateKey.Public(), publicKey)
+
+		key, err = keySet.PrivateKey(PurposeRecovery)
+		require.NoError(t, err)
+		require.Nil(t, key)
+
+		require.Nil(t, ks.KeySet("alias2"))
+		require.Nil(t, ks.KeySetByDID("did:ex:456"))
+	})
+
+	t.Run("test keystore already exists", func(t *testing.T) {
+		_, err := Create(path, []byte("password"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("test password required", func(t *testing.T) {
+		_, err := Create(filepath.Join(dir, "other.json"), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "keystore password is required")
+	})
+
+	t.Run("test wrong password", func(t *testing.T) {
+		_, err := Open(path, []byte("wrong"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the password may be wrong")
+	})
+
+	t.Run("test keystore not found", func(t *testing.T) {
+		_, err := Open(filepath.Join(dir, "wrong"), []byte("password"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read keystore")
+	})
+
+	t.Run("test version not supported", func(t *testing.T) {
+		other := filepath.Join(dir, "version.json")
+		require.NoError(t, ioutil.WriteFile(other, []byte(`{"version":2,"kdf":"scrypt"}`), 0600))
+
+		_, err := Open(other, []byte("password"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "version 2 with kdf 'scrypt' not supported")
+	})
+
+	t.Run("test invalid key derivation parameters", func(t *testing.T) {
+		other := filepath.Join(dir, "kdf.json")
+
+		for params, msg := range map[string]string{
+			`"time":0,"memory":65536,"threads":4`:            "kdf time must be between 1 and 16",
+			`"time":3,"memory":65536,"threads":0`:            "kdf threads must be between 1 and 64",
+			`"time":3,"memory":4294967295,"threads":4`:       "kdf memory must be between 32 and 262144 KiB",
+			`"time":3,"memory":16,"threads":4`:               "kdf memory must be between 32 and 262144 KiB",
+			`"time":3,"memory":65536,"threads":4,"nonce":""`: "nonce size must be 12",
+		} {
+			data := []byte(`{"version":1,"kdf":"argon2id","salt":"c2FsdA==",` + params + `}`)
+			require.NoError(t, ioutil.WriteFile(other, data, 0600))
+
+			_, err := Open(other, []byte("password"))
+			require.Error(t, err, params)
+			require.Contains(t, err.Error(), msg, params)
+		}
+	})
+
+	t.Run("test invalid key purpose", func(t *testing.T) {
+		ks, err := Open(path, []byte("password"))
+		require.NoError(t, err)
+
+		err = ks.AddKey("alias1", "", "wrong", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid key purpose 'wrong'")
+	})
+
+	t.Run("test invalid key", func(t *testing.T) {
+		keySet := &KeySet{Alias: "alias1", Keys: map[string]string{PurposeUpdate: "invalid"}}
+
+		_, err := keySet.PublicKey(PurposeUpdate)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update key of alias 'alias1' not found in PEM")
+	})
+}
+
+func TestKeystore_Commit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "keystore.json")
+
+	ks, err := Create(path, []byte("password"))
+	require.NoError(t, err)
+
+	_, updateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	_, nextUpdateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, ks.AddKey("alias1", "", PurposeUpdate, updateKey))
+	require.NoError(t, ks.AddKey("alias1", "", PurposeNextUpdate, nextUpdateKey))
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ks.Commit(ks.KeySet("alias1"), "did:ex:123", PurposeUpdate, PurposeRecovery))
+
+		ks, err := Open(path, []byte("password"))
+		require.NoError(t, err)
+
+		keySet := ks.KeySetByDID("did:ex:123")
+		require.Equal(t, "alias1", keySet.Alias)
+
+		key, err := keySet.PrivateKey(PurposeUpdate)
+		require.NoError(t, err)
+		require.Equal(t, nextUpdateKey, key)
+
+		key, err = keySet.PrivateKey(PurposeNextUpdate)
+		require.NoError(t, err)
+		require.Nil(t, key)
+	})
+
+	t.Run("test no key set", func(t *testing.T) {
+		require.NoError(t, ks.Commit(nil, "did:ex:123"))
+	})
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package logger defines the logger of the DID client, the VDRI and the endpoint discovery, so that their output can
+// be routed into the logging of the host application with their WithLogger options. The messages have key/value
+// fields, such as logger.Debug("sending sidetree request", "endpoint", url).
+package logger
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	missingValue = "(MISSING)"
+	pairLength   = 2
+)
+
+// Logger logs messages with levels and key/value fields. The fields are pairs of a key, usually a string, and
+// its value.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// Default returns the logger of the packages when none is set, which logs with the standard logrus logger.
+func Default() Logger {
+	return NewLogrus(logrus.StandardLogger())
+}
+
+// NewLogrus returns a logger logging with the logrus logger, the key/value pairs being logrus fields.
+func NewLogrus(l *logrus.Logger) Logger {
+	return &logrusLogger{logger: l}
+}
+
+type logrusLogger struct {
+	logger *logrus.Logger
+}
+
+func (l *logrusLogger) Debug(msg string, keyvals ...interface{}) {
+	l.logger.WithFields(Fields(keyvals...)).Debug(msg)
+}
+
+func (l *logrusLogger) Info(msg string, keyvals ...interface{}) {
+	l.logger.WithFields(Fields(keyvals...)).Info(msg)
+}
+
+func (l *logrusLogger) Warn(msg string, keyvals ...interface{}) {
+	l.logger.WithFields(Fields(keyvals...)).Warn(msg)
+}
+
+func (l *logrusLogger) Error(msg string, keyvals ...interface{}) {
+	l.logger.WithFields(Fields(keyvals...)).Error(msg)
+}
+
+// Fields returns the key/value pairs as a map, for the loggers with map fields. The keys which aren't strings are
+// formatted with fmt, and the value of an odd key is (MISSING).
+func Fields(keyvals ...interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, (len(keyvals)+1)/pairLength)
+
+	for i := 0; i < len(keyvals); i += pairLength {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+
+		if i+1 < len(keyvals) {
+			fields[key] = keyvals[i+1]
+		} else {
+			fields[key] = missingValue
+		}
+	}
+
+	return fields
+}
+
+// Nop returns a logger which discards the messages.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+
+func (nopLogger) Info(string, ...interface{}) {}
+
+func (nopLogger) Warn(string, ...interface{}) {}
+
+func (nopLogger) Error(string, ...interface{}) {}
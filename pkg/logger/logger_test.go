@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogrus(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	l := logrus.New()
+	l.SetOutput(buf)
+	l.SetLevel(logrus.DebugLevel)
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	logger := NewLogrus(l)
+	logger.Debug("debug message", "did", "did:trustbloc:a:b")
+	logger.Info("info message", "count", 2)
+	logger.Warn("warn message")
+	logger.Error("error message", "endpoint", "https://example.com")
+
+	require.Contains(t, buf.String(), `level=debug msg="debug message" did="did:trustbloc:a:b"`)
+	require.Contains(t, buf.String(), `level=info msg="info message" count=2`)
+	require.Contains(t, buf.String(), `level=warning msg="warn message"`)
+	require.Contains(t, buf.String(), `level=error msg="error message" endpoint="https://example.com"`)
+
+	require.NotNil(t, Default())
+}
+
+func TestFields(t *testing.T) {
+	require.Equal(t, map[string]interface{}{"did": "did1", "1": 2, "odd": missingValue},
+		Fields("did", "did1", 1, 2, "odd"))
+	require.Empty(t, Fields())
+}
+
+func TestNop(t *testing.T) {
+	logger := Nop()
+	logger.Debug("message", "key", "value")
+	logger.Info("message")
+	logger.Warn("message")
+	logger.Error("message")
+}
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mnemonic derives the update and recovery keys of DIDs from a BIP39 mnemonic, so that backing up the
+// mnemonic is enough to regenerate the keys needed to update or recover the DIDs later. The keys are derived from
+// the seed of the mnemonic with SLIP-0010, along hardened derivation paths such as m/0'/1'/0'.
+package mnemonic
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+const (
+	// EntropyBits is the entropy of the generated mnemonics, 24 words
+	EntropyBits = 256
+
+	hardenedOffset = 0x80000000
+	keySize        = 32
+	pathPrefix     = "m"
+
+	ed25519Curve = "ed25519 seed"
+	p256Curve    = "Nist256p1 seed"
+
+	updateKeyPathFormat   = "m/0'/0'/%d'"
+	recoveryKeyPathFormat = "m/0'/1'/%d'"
+)
+
+// New generates a new mnemonic of 24 words.
+func New() (string, error) {
+	entropy, err := bip39.NewEntropy(EntropyBits)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("failed to create mnemonic: %w", err)
+	}
+
+	return mnemonic, nil
+}
+
+// UpdateKeyPath returns the derivation path of the update key with the index. The index is incremented for each
+// update, the next update key of an update being the key of the next index.
+func UpdateKeyPath(index uint32) string {
+	return fmt.Sprintf(updateKeyPathFormat, index)
+}
+
+// RecoveryKeyPath returns the derivation path of the recovery key with the index. The index is incremented for
+// each recovery, the next recovery key of a recovery being the key of the next index.
+func RecoveryKeyPath(index uint32) string {
+	return fmt.Sprintf(recoveryKeyPathFormat, index)
+}
+
+// DeriveKey derives the key pair of the key type (Ed25519 or P256) at the path from the mnemonic and its optional
+// passphrase. The same mnemonic, passphrase, path and key type always derive the same key pair.
+func DeriveKey(mnemonic, passphrase, path, keyType string) (crypto.PublicKey, crypto.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(normalize(mnemonic), passphrase)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case strings.EqualFold(keyType, doc.Ed25519KeyType):
+		key := deriveEd25519(seed, indexes)
+
+		privateKey := ed25519.NewKeyFromSeed(key)
+
+		return privateKey.Public(), privateKey, nil
+	case strings.EqualFold(keyType, doc.P256KeyType):
+		privateKey := deriveP256(seed, indexes)
+
+		return &privateKey.PublicKey, privateKey, nil
+	default:
+		return nil, nil, fmt.Errorf("key type not supported: %s", keyType)
+	}
+}
+
+// normalize joins the words of the mnemonic with single spaces, so that mnemonics typed with extra whitespace derive
+// the same keys.
+func normalize(mnemonic string) string {
+	return strings.Join(strings.Fields(mnemonic), " ")
+}
+
+// parsePath parses the indexes of the derivation path, which must all be hardened since ed25519 doesn't support
+// non-hardened derivation.
+func parsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != pathPrefix {
+		return nil, fmt.Errorf("invalid derivation path '%s': must start with m", path)
+	}
+
+	indexes := make([]uint32, 0, len(segments)-1)
+
+	for _, segment := range segments[1:] {
+		if !strings.HasSuffix(segment, "'") && !strings.HasSuffix(segment, "h") {
+			return nil, fmt.Errorf("invalid derivation path '%s': index '%s' is not hardened", path, segment)
+		}
+
+		index, err := strconv.ParseUint(segment[:len(segment)-1], 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path '%s': invalid index '%s'", path, segment)
+		}
+
+		indexes = append(indexes, uint32(index)+hardenedOffset)
+	}
+
+	return indexes, nil
+}
+
+// deriveEd25519 derives the private key seed at the indexes with SLIP-0010
+func deriveEd25519(seed []byte, indexes []uint32) []byte {
+	key, chainCode := split(hmacSHA512([]byte(ed25519Curve), seed))
+
+	for _, index := range indexes {
+		key, chainCode = split(hmacSHA512(chainCode, childData(0, key, index)))
+	}
+
+	return key
+}
+
+// deriveP256 derives the private key at the indexes with SLIP-0010, retrying the derivation as SLIP-0010 requires
+// in the unlikely case the derived key is not a valid P-256 private key.
+func deriveP256(seed []byte, indexes []uint32) *ecdsa.PrivateKey {
+	curve := elliptic.P256()
+	n := curve.Params().N
+
+	i := hmacSHA512([]byte(p256Curve), seed)
+	for !validP256Key(i[:keySize], n) {
+		i = hmacSHA512([]byte(p256Curve), i)
+	}
+
+	key, chainCode := split(i)
+
+	for _, index := range indexes {
+		i = hmacSHA512(chainCode, childData(0, key, index))
+
+		childKey := p256ChildKey(i[:keySize], key, n)
+		for childKey == nil {
+			i = hmacSHA512(chainCode, childData(1, i[keySize:], index))
+			childKey = p256ChildKey(i[:keySize], key, n)
+		}
+
+		key, chainCode = childKey, i[keySize:]
+	}
+
+	privateKey := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(key)}
+	privateKey.PublicKey.Curve = curve
+	privateKey.PublicKey.X, privateKey.PublicKey.Y = curve.ScalarBaseMult(key)
+
+	return privateKey
+}
+
+// p256ChildKey returns the child key IL + parent key mod n, or nil if IL >= n or the child key is 0
+func p256ChildKey(il, parentKey []byte, n *big.Int) []byte {
+	k := new(big.Int).SetBytes(il)
+	if k.Cmp(n) >= 0 {
+		return nil
+	}
+
+	k.Add(k, new(big.Int).SetBytes(parentKey))
+	k.Mod(k, n)
+
+	if k.Sign() == 0 {
+		return nil
+	}
+
+	return k.FillBytes(make([]byte, keySize))
+}
+
+func validP256Key(key []byte, n *big.Int) bool {
+	k := new(big.Int).SetBytes(key)
+
+	return k.Sign() != 0 && k.Cmp(n) < 0
+}
+
+// childData is the data of the HMAC of a hardened child key: the prefix byte, the 32 byte key and the index
+func childData(prefix byte, key []byte, index uint32) []byte {
+	data := make([]byte, 1+len(key)+4) //nolint: gomnd
+	data[0] = prefix
+	copy(data[1:], key)
+	binary.BigEndian.PutUint32(data[1+len(key):], index)
+
+	return data
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data) //nolint: errcheck,gosec
+
+	return mac.Sum(nil)
+}
+
+func split(i []byte) ([]byte, []byte) {
+	return i[:keySize], i[keySize:]
+}
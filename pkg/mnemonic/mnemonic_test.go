@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mnemonic
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestNew(t *testing.T) {
+	mnemonic, err := New()
+	require.NoError(t, err)
+	require.Len(t, strings.Fields(mnemonic), 24)
+
+	other, err := New()
+	require.NoError(t, err)
+	require.NotEqual(t, mnemonic, other)
+}
+
+func TestKeyPaths(t *testing.T) {
+	require.Equal(t, "m/0'/0'/2'", UpdateKeyPath(2))
+	require.Equal(t, "m/0'/1'/0'", RecoveryKeyPath(0))
+}
+
+func TestDeriveKey(t *testing.T) {
+	t.Run("test ed25519", func(t *testing.T) {
+		publicKey, privateKey, err := DeriveKey(testMnemonic, "", UpdateKeyPath(0), doc.Ed25519KeyType)
+		require.NoError(t, err)
+		require.Equal(t, privateKey.(ed25519.PrivateKey).Public(), publicKey)
+
+		_, again, err := DeriveKey("  "+strings.ReplaceAll(testMnemonic, " ", "\n")+" ", "", "m/0h/0h/0h",
+			doc.Ed25519KeyType)
+		require.NoError(t, err)
+		require.Equal(t, privateKey, again)
+
+		_, next, err := DeriveKey(testMnemonic, "", UpdateKeyPath(1), doc.Ed25519KeyType)
+		require.NoError(t, err)
+		require.NotEqual(t, privateKey, next)
+
+		_, other, err := DeriveKey(testMnemonic, "passphrase", UpdateKeyPath(0), doc.Ed25519KeyType)
+		require.NoError(t, err)
+		require.NotEqual(t, privateKey, other)
+	})
+
+	t.Run("test p256", func(t *testing.T) {
+		publicKey, privateKey, err := DeriveKey(testMnemonic, "", RecoveryKeyPath(0), "p256")
+		require.NoError(t, err)
+		require.Equal(t, &privateKey.(*ecdsa.PrivateKey).PublicKey, publicKey)
+		require.True(t, publicKey.(*ecdsa.PublicKey).Curve.IsOnCurve(publicKey.(*ecdsa.PublicKey).X,
+			publicKey.(*ecdsa.PublicKey).Y))
+
+		_, again, err := DeriveKey(testMnemonic, "", RecoveryKeyPath(0), doc.P256KeyType)
+		require.NoError(t, err)
+		require.Equal(t, privateKey, again)
+	})
+
+	t.Run("test invalid mnemonic", func(t *testing.T) {
+		_, _, err := DeriveKey(strings.Replace(testMnemonic, "about", "abandon", 1), "", UpdateKeyPath(0),
+			doc.Ed25519KeyType)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid mnemonic")
+	})
+
+	t.Run("test invalid path", func(t *testing.T) {
+		_, _, err := DeriveKey(testMnemonic, "", "0'/1'", doc.Ed25519KeyType)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must start with m")
+
+		_, _, err = DeriveKey(testMnemonic, "", "m/0'/1", doc.Ed25519KeyType)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "index '1' is not hardened")
+
+		_, _, err = DeriveKey(testMnemonic, "", "m/2147483648'", doc.Ed25519KeyType)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid index '2147483648''")
+	})
+
+	t.Run("test key type not supported", func(t *testing.T) {
+		_, _, err := DeriveKey(testMnemonic, "", UpdateKeyPath(0), "secp256k1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported")
+	})
+}
+
+// test vector 1 of SLIP-0010
+func TestSLIP10(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	path, err := parsePath("m/0'/1'/2'/2'/1000000000'")
+	require.NoError(t, err)
+
+	t.Run("test ed25519", func(t *testing.T) {
+		require.Equal(t, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			hex.EncodeToString(deriveEd25519(seed, nil)))
+		require.Equal(t, "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			hex.EncodeToString(deriveEd25519(seed, path[:1])))
+		require.Equal(t, "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+			hex.EncodeToString(deriveEd25519(seed, path)))
+	})
+
+	t.Run("test p256", func(t *testing.T) {
+		require.Equal(t, "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2",
+			hex.EncodeToString(deriveP256(seed, nil).D.Bytes()))
+		require.Equal(t, "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c",
+			hex.EncodeToString(deriveP256(seed, path[:1]).D.Bytes()))
+	})
+}
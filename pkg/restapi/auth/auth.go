@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	apiKeyHeader        = "X-API-Key"
+	bearerPrefix        = "Bearer "
+
+	errorCodeUnauthorized = "unauthorized"
+)
+
+// ErrUnauthenticated is returned by an authenticator when the request has no valid credentials
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator authenticates the client of a request
+type Authenticator interface {
+	// Authenticate returns the identity of the client of the request
+	Authenticate(req *http.Request) (string, error)
+}
+
+type clientIDKey struct{}
+
+// ClientID returns the identity of the authenticated client of the request context, if any
+func ClientID(ctx context.Context) string {
+	clientID, ok := ctx.Value(clientIDKey{}).(string)
+	if !ok {
+		return ""
+	}
+
+	return clientID
+}
+
+// Handler returns a handler which serves the requests authenticated by one of the authenticators with the handler,
+// and rejects the other requests with status 401. The identity of the client is set in the request context.
+func Handler(authenticators []Authenticator, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		ctx, err := Authenticate(authenticators, req)
+		if err == nil {
+			handler(rw, req.WithContext(ctx))
+
+			return
+		}
+
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="did-method"`)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusUnauthorized)
+
+		err = json.NewEncoder(rw).Encode(map[string]string{"code": errorCodeUnauthorized,
+			"message": "missing or invalid credentials"})
+		if err != nil {
+			log.Errorf("Unable to send error response, %s", err)
+		}
+	}
+}
+
+// Authenticate authenticates the request with the first authenticator accepting its credentials, and returns the
+// request context with the identity of the client, or ErrUnauthenticated if no authenticator accepts them
+func Authenticate(authenticators []Authenticator, req *http.Request) (context.Context, error) {
+	for _, authenticator := range authenticators {
+		clientID, err := authenticator.Authenticate(req)
+		if err == nil {
+			return context.WithValue(req.Context(), clientIDKey{}, clientID), nil
+		}
+
+		if !errors.Is(err, ErrUnauthenticated) {
+			log.Warnf("failed to authenticate request to %s: %s", req.URL.Path, err)
+		}
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+// APIKeyAuthenticator authenticates the requests with a static API key, in the X-API-Key header or as bearer token
+type APIKeyAuthenticator struct {
+	// clients are the client identities by API key
+	clients map[string]string
+}
+
+// NewAPIKeyAuthenticator returns an authenticator of the API keys, given as client=key pairs
+func NewAPIKeyAuthenticator(apiKeys []string) (*APIKeyAuthenticator, error) {
+	clients := make(map[string]string)
+
+	for _, apiKey := range apiKeys {
+		parts := strings.SplitN(apiKey, "=", 2) //nolint: gomnd
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid api key '%s': expected client=key", apiKey)
+		}
+
+		clients[parts[1]] = parts[0]
+	}
+
+	return &APIKeyAuthenticator{clients: clients}, nil
+}
+
+// Authenticate returns the client of the API key of the request
+func (a *APIKeyAuthenticator) Authenticate(req *http.Request) (string, error) {
+	key := req.Header.Get(apiKeyHeader)
+	if key == "" {
+		key = bearerToken(req)
+	}
+
+	if key == "" {
+		return "", ErrUnauthenticated
+	}
+
+	for apiKey, clientID := range a.clients {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+			return clientID, nil
+		}
+	}
+
+	return "", ErrUnauthenticated
+}
+
+// IntrospectionAuthenticator authenticates the requests with a bearer token, which is validated by an OAuth2 token
+// introspection endpoint (RFC 7662)
+type IntrospectionAuthenticator struct {
+	introspectionURL string
+	clientID         string
+	clientSecret     string
+	httpClient       *http.Client
+}
+
+type introspectionResponse struct {
+	Active   bool   `json:"active"`
+	Subject  string `json:"sub"`
+	ClientID string `json:"client_id"`
+	Username string `json:"username"`
+}
+
+// NewIntrospectionAuthenticator returns an authenticator calling the introspection endpoint with the credentials
+// of the service
+func NewIntrospectionAuthenticator(introspectionURL, clientID, clientSecret string,
+	httpClient *http.Client) *IntrospectionAuthenticator {
+	return &IntrospectionAuthenticator{introspectionURL: introspectionURL, clientID: clientID,
+		clientSecret: clientSecret, httpClient: httpClient}
+}
+
+// Authenticate returns the subject of the active bearer token of the request
+func (a *IntrospectionAuthenticator) Authenticate(req *http.Request) (string, error) {
+	token := bearerToken(req)
+	if token == "" {
+		return "", ErrUnauthenticated
+	}
+
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+
+	introspectionReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, a.introspectionURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create introspection request: %w", err)
+	}
+
+	introspectionReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if a.clientID != "" {
+		introspectionReq.SetBasicAuth(a.clientID, a.clientSecret)
+	}
+
+	resp, err := a.httpClient.Do(introspectionReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to send introspection request: %w", err)
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Warnf("failed to close response body: %s", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("introspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	result := introspectionResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	if !result.Active {
+		return "", ErrUnauthenticated
+	}
+
+	for _, clientID := range []string{result.Subject, result.ClientID, result.Username} {
+		if clientID != "" {
+			return clientID, nil
+		}
+	}
+
+	return "", errors.New("introspection response has no subject")
+}
+
+func bearerToken(req *http.Request) string {
+	header := req.Header.Get(authorizationHeader)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(header, bearerPrefix)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	apiKeys, err := NewAPIKeyAuthenticator([]string{"wallet=key1", "issuer=key2"})
+	require.NoError(t, err)
+
+	var clientID string
+
+	handler := Handler([]Authenticator{apiKeys}, func(rw http.ResponseWriter, req *http.Request) {
+		clientID = ClientID(req.Context())
+	})
+
+	t.Run("test api key header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.Header.Set(apiKeyHeader, "key2")
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "issuer", clientID)
+	})
+
+	t.Run("test api key bearer token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.Header.Set(authorizationHeader, "Bearer key1")
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "wallet", clientID)
+	})
+
+	t.Run("test invalid api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.Header.Set(apiKeyHeader, "key3")
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+		require.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+		require.Contains(t, rr.Body.String(), errorCodeUnauthorized)
+	})
+
+	t.Run("test no credentials", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodPost, "/1.0/create", nil))
+
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("test client ID not set", func(t *testing.T) {
+		require.Empty(t, ClientID(httptest.NewRequest(http.MethodPost, "/1.0/create", nil).Context()))
+	})
+}
+
+func TestNewAPIKeyAuthenticator(t *testing.T) {
+	_, err := NewAPIKeyAuthenticator([]string{"key1"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected client=key")
+}
+
+func TestIntrospectionAuthenticator(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "did-method" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.FormValue("token") {
+		case "active":
+			require.NoError(t, json.NewEncoder(w).Encode(introspectionResponse{Active: true, ClientID: "wallet"}))
+		case "invalid":
+			_, err := w.Write([]byte("invalid"))
+			require.NoError(t, err)
+		default:
+			require.NoError(t, json.NewEncoder(w).Encode(introspectionResponse{Active: false}))
+		}
+	}))
+	defer serv.Close()
+
+	authenticator := NewIntrospectionAuthenticator(serv.URL, "did-method", "secret", serv.Client())
+
+	authenticate := func(token string) (string, error) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		if token != "" {
+			req.Header.Set(authorizationHeader, bearerPrefix+token)
+		}
+
+		return authenticator.Authenticate(req)
+	}
+
+	t.Run("test active token", func(t *testing.T) {
+		clientID, err := authenticate("active")
+		require.NoError(t, err)
+		require.Equal(t, "wallet", clientID)
+	})
+
+	t.Run("test inactive token", func(t *testing.T) {
+		_, err := authenticate("inactive")
+		require.Equal(t, ErrUnauthenticated, err)
+	})
+
+	t.Run("test no token", func(t *testing.T) {
+		_, err := authenticate("")
+		require.Equal(t, ErrUnauthenticated, err)
+	})
+
+	t.Run("test invalid introspection response", func(t *testing.T) {
+		_, err := authenticate("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode introspection response")
+	})
+
+	t.Run("test introspection request unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.Header.Set(authorizationHeader, bearerPrefix+"active")
+
+		_, err := NewIntrospectionAuthenticator(serv.URL, "did-method", "other", serv.Client()).Authenticate(req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "introspection endpoint returned status 401")
+	})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Header is the header holding the correlation ID of a request
+const Header = "X-Correlation-ID"
+
+const (
+	idLength    = 16
+	maxIDLength = 128
+)
+
+type idKey struct{}
+
+// ID returns the correlation ID of the request context, if any
+func ID(ctx context.Context) string {
+	id, ok := ctx.Value(idKey{}).(string)
+	if !ok {
+		return ""
+	}
+
+	return id
+}
+
+// Handler returns a handler which sets the correlation ID of the request, or a new one if the request has none, in
+// the request context and the response, and logs a summary of each request once it is served
+func Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+
+		ctx, id := WithID(req.Context(), req.Header.Get(Header))
+
+		rw.Header().Set(Header, id)
+
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+		handler.ServeHTTP(recorder, req.WithContext(ctx))
+
+		log.WithFields(log.Fields{
+			"correlationId": id,
+			"method":        req.Method,
+			"path":          req.URL.Path,
+			"status":        recorder.status,
+			"bytes":         recorder.bytes,
+			"duration":      time.Since(start).String(),
+			"remoteAddr":    req.RemoteAddr,
+			"userAgent":     req.UserAgent(),
+		}).Info("request served")
+	})
+}
+
+// WithID returns the context with the correlation ID sent by the client, or a new one if the ID is missing or
+// can't be propagated, and the correlation ID
+func WithID(ctx context.Context, id string) (context.Context, string) {
+	if !validID(id) {
+		id = newID()
+	}
+
+	return context.WithValue(ctx, idKey{}, id), id
+}
+
+// validID returns true if the correlation ID sent by the client can be propagated: it is not too long, and only
+// has printable ASCII characters so that it can't forge log lines or headers
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newID() string {
+	id := make([]byte, idLength)
+
+	if _, err := rand.Read(id); err != nil {
+		log.Warnf("failed to generate correlation id: %s", err)
+
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// statusRecorder records the status and size of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err
+}
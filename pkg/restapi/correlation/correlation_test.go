@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var id string
+
+	handler := Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id = ID(req.Context())
+
+		rw.WriteHeader(http.StatusCreated)
+
+		_, err := rw.Write([]byte("created"))
+		require.NoError(t, err)
+	}))
+
+	t.Run("test correlation id propagated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.Header.Set(Header, "abc-123")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Equal(t, "abc-123", id)
+		require.Equal(t, "abc-123", rr.Header().Get(Header))
+	})
+
+	t.Run("test correlation id assigned", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/1.0/create", nil))
+
+		require.Len(t, id, 2*idLength)
+		require.Equal(t, id, rr.Header().Get(Header))
+	})
+
+	t.Run("test invalid correlation id replaced", func(t *testing.T) {
+		for _, invalid := range []string{"abc 123", "abc\n123", strings.Repeat("a", maxIDLength+1)} {
+			req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+			req.Header.Set(Header, invalid)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.NotEqual(t, invalid, id)
+			require.Len(t, id, 2*idLength)
+		}
+	})
+
+	t.Run("test no correlation id", func(t *testing.T) {
+		require.Empty(t, ID(context.Background()))
+	})
+}
+
+func TestWithID(t *testing.T) {
+	ctx, id := WithID(context.Background(), "id1")
+	require.Equal(t, "id1", id)
+	require.Equal(t, "id1", ID(ctx))
+
+	ctx, id = WithID(context.Background(), "id 1")
+	require.Len(t, id, 2*idLength)
+	require.Equal(t, id, ID(ctx))
+}
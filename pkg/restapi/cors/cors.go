@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	originHeader          = "Origin"
+	requestMethodHeader   = "Access-Control-Request-Method"
+	requestHeadersHeader  = "Access-Control-Request-Headers"
+	allowOriginHeader     = "Access-Control-Allow-Origin"
+	allowMethodsHeader    = "Access-Control-Allow-Methods"
+	allowHeadersHeader    = "Access-Control-Allow-Headers"
+	exposeHeadersHeader   = "Access-Control-Expose-Headers"
+	maxAgeHeader          = "Access-Control-Max-Age"
+	wildcard              = "*"
+	defaultPreflightCache = 10 * time.Minute
+)
+
+// DefaultAllowedMethods are the methods allowed if none are configured
+var DefaultAllowedMethods = []string{http.MethodGet, http.MethodPost} //nolint: gochecknoglobals
+
+// DefaultAllowedHeaders are the request headers allowed if none are configured
+var DefaultAllowedHeaders = []string{ //nolint: gochecknoglobals
+	"Accept", "Content-Type", "Authorization", "X-API-Key", "Idempotency-Key",
+}
+
+// DefaultExposedHeaders are the response headers exposed to the browser if none are configured
+var DefaultExposedHeaders = []string{"Location", "Retry-After", "X-Correlation-ID"} //nolint: gochecknoglobals
+
+// Config configures the cross-origin requests allowed
+type Config struct {
+	// AllowedOrigins are the origins allowed to call the service, * allows any origin
+	AllowedOrigins []string
+	// AllowedMethods are the methods allowed, DefaultAllowedMethods if not set
+	AllowedMethods []string
+	// AllowedHeaders are the request headers allowed, DefaultAllowedHeaders if not set
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers exposed to the browser, DefaultExposedHeaders if not set
+	ExposedHeaders []string
+	// MaxAge is the time browsers may cache the response to a preflight request, 10m if not set
+	MaxAge time.Duration
+}
+
+type cors struct {
+	origins       map[string]bool
+	anyOrigin     bool
+	methods       map[string]bool
+	headers       map[string]bool
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	maxAge        string
+}
+
+// Handler returns a handler which answers the preflight requests, and adds the CORS headers to the responses of
+// the handler to the allowed origins
+func Handler(config *Config, handler http.Handler) http.Handler {
+	c := newCORS(config)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get(originHeader)
+		if origin == "" {
+			handler.ServeHTTP(rw, req)
+
+			return
+		}
+
+		rw.Header().Add("Vary", originHeader)
+
+		if req.Method == http.MethodOptions && req.Header.Get(requestMethodHeader) != "" {
+			c.preflight(rw, req, origin)
+
+			return
+		}
+
+		if c.allowedOrigin(origin) {
+			rw.Header().Set(allowOriginHeader, c.allowOrigin(origin))
+			rw.Header().Set(exposeHeadersHeader, c.exposeHeaders)
+		}
+
+		handler.ServeHTTP(rw, req)
+	})
+}
+
+func newCORS(config *Config) *cors {
+	methods, headers, exposed := config.AllowedMethods, config.AllowedHeaders, config.ExposedHeaders
+
+	if len(methods) == 0 {
+		methods = DefaultAllowedMethods
+	}
+
+	if len(headers) == 0 {
+		headers = DefaultAllowedHeaders
+	}
+
+	if len(exposed) == 0 {
+		exposed = DefaultExposedHeaders
+	}
+
+	maxAge := config.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultPreflightCache
+	}
+
+	c := &cors{origins: make(map[string]bool), methods: make(map[string]bool), headers: make(map[string]bool),
+		allowMethods: strings.Join(methods, ", "), allowHeaders: strings.Join(headers, ", "),
+		exposeHeaders: strings.Join(exposed, ", "), maxAge: strconv.Itoa(int(maxAge.Seconds()))}
+
+	for _, origin := range config.AllowedOrigins {
+		if origin == wildcard {
+			c.anyOrigin = true
+		}
+
+		c.origins[strings.ToLower(origin)] = true
+	}
+
+	for _, method := range methods {
+		c.methods[strings.ToUpper(method)] = true
+	}
+
+	for _, header := range headers {
+		c.headers[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return c
+}
+
+// preflight answers the preflight request with status 204 if the origin, method and headers are allowed, and with
+// status 403 otherwise
+func (c *cors) preflight(rw http.ResponseWriter, req *http.Request, origin string) {
+	if !c.allowedOrigin(origin) || !c.methods[strings.ToUpper(req.Header.Get(requestMethodHeader))] ||
+		!c.allowedHeaders(req.Header.Get(requestHeadersHeader)) {
+		rw.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	rw.Header().Set(allowOriginHeader, c.allowOrigin(origin))
+	rw.Header().Set(allowMethodsHeader, c.allowMethods)
+	rw.Header().Set(allowHeadersHeader, c.allowHeaders)
+	rw.Header().Set(maxAgeHeader, c.maxAge)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) allowedOrigin(origin string) bool {
+	return c.anyOrigin || c.origins[strings.ToLower(origin)]
+}
+
+func (c *cors) allowOrigin(origin string) string {
+	if c.anyOrigin {
+		return wildcard
+	}
+
+	return origin
+}
+
+func (c *cors) allowedHeaders(requestHeaders string) bool {
+	for _, header := range strings.Split(requestHeaders, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !c.headers[http.CanonicalHeaderKey(header)] {
+			return false
+		}
+	}
+
+	return true
+}
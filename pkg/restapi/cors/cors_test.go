@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	handler := Handler(&Config{AllowedOrigins: []string{"https://wallet.example.com"}},
+		http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {}))
+
+	request := func(method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/1.0/create", nil)
+		if origin != "" {
+			req.Header.Set(originHeader, origin)
+		}
+
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	t.Run("test preflight", func(t *testing.T) {
+		rr := request(http.MethodOptions, "https://wallet.example.com", map[string]string{
+			requestMethodHeader: http.MethodPost, requestHeadersHeader: "content-type, authorization"})
+		require.Equal(t, http.StatusNoContent, rr.Code)
+		require.Equal(t, "https://wallet.example.com", rr.Header().Get(allowOriginHeader))
+		require.Equal(t, "GET, POST", rr.Header().Get(allowMethodsHeader))
+		require.Equal(t, "600", rr.Header().Get(maxAgeHeader))
+	})
+
+	t.Run("test preflight not allowed", func(t *testing.T) {
+		rr := request(http.MethodOptions, "https://other.example.com", map[string]string{
+			requestMethodHeader: http.MethodPost})
+		require.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = request(http.MethodOptions, "https://wallet.example.com", map[string]string{
+			requestMethodHeader: http.MethodDelete})
+		require.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = request(http.MethodOptions, "https://wallet.example.com", map[string]string{
+			requestMethodHeader: http.MethodPost, requestHeadersHeader: "X-Other"})
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("test request from allowed origin", func(t *testing.T) {
+		rr := request(http.MethodPost, "https://wallet.example.com", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "https://wallet.example.com", rr.Header().Get(allowOriginHeader))
+		require.Equal(t, "Location, Retry-After, X-Correlation-ID", rr.Header().Get(exposeHeadersHeader))
+	})
+
+	t.Run("test request from other origin", func(t *testing.T) {
+		rr := request(http.MethodPost, "https://other.example.com", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Empty(t, rr.Header().Get(allowOriginHeader))
+	})
+
+	t.Run("test request without origin", func(t *testing.T) {
+		rr := request(http.MethodPost, "", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Empty(t, rr.Header().Get("Vary"))
+	})
+}
+
+func TestHandlerAnyOrigin(t *testing.T) {
+	handler := Handler(&Config{AllowedOrigins: []string{"*"}, AllowedMethods: []string{http.MethodGet}},
+		http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/1.0/identifiers/did:trustbloc:domain:123", nil)
+	req.Header.Set(originHeader, "https://any.example.com")
+	req.Header.Set(requestMethodHeader, http.MethodGet)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	require.Equal(t, wildcard, rr.Header().Get(allowOriginHeader))
+	require.Equal(t, "GET", rr.Header().Get(allowMethodsHeader))
+}
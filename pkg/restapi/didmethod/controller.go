@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package didmethod
 
 import (
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi/didmethodpb"
 	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/didmethod/operation"
 )
 
@@ -22,15 +23,22 @@ func New(config *operation.Config) (*Controller, error) {
 
 	allHandlers = append(allHandlers, handlers...)
 
-	return &Controller{handlers: allHandlers}, nil
+	return &Controller{handlers: allHandlers, service: didMethodService, mode: config.Mode}, nil
 }
 
 // Controller contains handlers for controller
 type Controller struct {
 	handlers []operation.Handler
+	service  *operation.Operation
+	mode     string
 }
 
 // GetOperations returns all controller endpoints
 func (c *Controller) GetOperations() []operation.Handler {
 	return c.handlers
 }
+
+// GetGRPCServer returns the gRPC server of the did method operations
+func (c *Controller) GetGRPCServer() (didmethodpb.DIDMethodServer, error) {
+	return c.service.GetGRPCServer(c.mode)
+}
@@ -30,5 +30,23 @@ func TestController_GetOperations(t *testing.T) {
 	require.NotNil(t, controller)
 
 	ops := controller.GetOperations()
-	require.Equal(t, 2, len(ops))
+	require.Equal(t, 7, len(ops))
+}
+
+func TestController_GetGRPCServer(t *testing.T) {
+	controller, err := New(&operation.Config{Mode: "combined"})
+	require.NoError(t, err)
+
+	server, err := controller.GetGRPCServer()
+	require.NoError(t, err)
+	require.NotNil(t, server)
+
+	controller, err = New(&operation.Config{Mode: "public-registrar",
+		PublicRegistration: &operation.PublicRegistrationConfig{ChallengeSecret: []byte("secret"), Difficulty: 1}})
+	require.NoError(t, err)
+
+	server, err = controller.GetGRPCServer()
+	require.Error(t, err)
+	require.Nil(t, server)
+	require.Contains(t, err.Error(), "grpc api is not supported in mode")
 }
@@ -0,0 +1,343 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	operationsPath = registerBasePath + "/operations/{id}"
+
+	operationIDLength            = 16
+	defaultOperationPollInterval = 5 * time.Second
+	defaultOperationTimeout      = 10 * time.Minute
+	defaultOperationTTL          = time.Hour
+	defaultMaxOperations         = 10000
+)
+
+// ErrOperationNotFound is returned when the operation store has no operation with the ID
+var ErrOperationNotFound = errors.New("operation not found")
+
+// AsyncOperationsConfig configures the asynchronous mode, where create, update and recover requests return an
+// operation ID immediately, and the status of the operation is polled until it is anchored
+type AsyncOperationsConfig struct {
+	// Store keeps the status of the operations, in memory if not set
+	Store OperationStore
+	// PollInterval is the interval between the resolutions checking that an operation is anchored, 5s if not set
+	PollInterval time.Duration
+	// Timeout is the time after which an operation which is not anchored has failed, 10m if not set
+	Timeout time.Duration
+	// TTL is the time the operations and their secrets are kept in memory after their last update, 1h if not set
+	TTL time.Duration
+	// MaxOperations is the number of operations and of secrets kept in memory, the ones closest to expiring being
+	// evicted first, 10000 if not set
+	MaxOperations int
+}
+
+// OperationStore keeps the status of the asynchronous operations
+type OperationStore interface {
+	// Get returns the status of the operation with the ID
+	Get(id string) (*OperationStatus, error)
+	// Put stores the status of the operation, replacing its previous status
+	Put(status *OperationStatus) error
+}
+
+// MemOperationStore is an operation store keeping the operations in memory, until they expire after the TTL
+// following their last update. Once the store holds the maximum number of operations, the operations closest to
+// expiring are evicted to store new ones.
+type MemOperationStore struct {
+	operations *expiringMap
+}
+
+// NewMemOperationStore returns an empty in memory operation store
+func NewMemOperationStore(ttl time.Duration, maxOperations int) *MemOperationStore {
+	return &MemOperationStore{operations: newExpiringMap(ttl, maxOperations)}
+}
+
+// Get returns the status of the operation with the ID
+func (s *MemOperationStore) Get(id string) (*OperationStatus, error) {
+	status, ok := s.operations.get(id)
+	if !ok {
+		return nil, ErrOperationNotFound
+	}
+
+	stored := status.(OperationStatus)
+
+	return &stored, nil
+}
+
+// Put stores the status of the operation, replacing its previous status
+func (s *MemOperationStore) Put(status *OperationStatus) error {
+	s.operations.put(status.ID, *status)
+
+	return nil
+}
+
+type asyncOperations struct {
+	store        OperationStore
+	pollInterval time.Duration
+	timeout      time.Duration
+	// secrets are the secrets of the submitted operations, kept out of the store and returned once
+	secrets *expiringMap
+}
+
+func newAsyncOperations(config *AsyncOperationsConfig) *asyncOperations {
+	a := &asyncOperations{store: config.Store, pollInterval: config.PollInterval, timeout: config.Timeout}
+
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = defaultOperationTTL
+	}
+
+	maxOperations := config.MaxOperations
+	if maxOperations <= 0 {
+		maxOperations = defaultMaxOperations
+	}
+
+	a.secrets = newExpiringMap(ttl, maxOperations)
+
+	if a.store == nil {
+		a.store = NewMemOperationStore(ttl, maxOperations)
+	}
+
+	if a.pollInterval <= 0 {
+		a.pollInterval = defaultOperationPollInterval
+	}
+
+	if a.timeout <= 0 {
+		a.timeout = defaultOperationTimeout
+	}
+
+	return a
+}
+
+// submitOperation stores a pending operation, submits it in the background, and responds with status 202 and the
+// operation ID in the registrar metadata. Submit returns the DID of the operation and its secret.
+func (o *Operation) submitOperation(rw http.ResponseWriter, response RegisterResponse,
+	operationType, callbackURL string, submit func() (string, *Secret, error)) {
+	id, err := newOperationID()
+	if err != nil {
+		o.writeFailureResponse(rw, response, fmt.Errorf("failed to generate operation id: %w", err))
+
+		return
+	}
+
+	now := time.Now()
+	status := &OperationStatus{ID: id, JobID: response.JobID, Type: operationType, State: OperationStatePending,
+		Identifier: response.DIDState.Identifier, Created: now, Updated: now}
+
+	if err = o.async.store.Put(status); err != nil {
+		o.writeFailureResponse(rw, response, fmt.Errorf("failed to store operation: %w", err))
+
+		return
+	}
+
+	go o.runOperation(*status, callbackURL, submit)
+
+	response.DIDState.State = RegistrationStateWait
+	response.RegistrarMetadata = map[string]interface{}{"operationId": id}
+
+	rw.Header().Set("Location", strings.Replace(operationsPath, "{id}", id, 1))
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusAccepted)
+
+	o.writeResponse(rw, response)
+}
+
+// runOperation submits the operation, then waits until the change of the DID document is observable, and notifies
+// the webhooks of the outcome if they are enabled
+func (o *Operation) runOperation(status OperationStatus, callbackURL string, submit func() (string, *Secret, error)) {
+	before := o.documentBefore(status.Identifier)
+
+	identifier, secret, err := submit()
+	if err == nil {
+		status.Identifier = identifier
+
+		if secret != nil {
+			o.async.secrets.put(status.ID, secret)
+		}
+
+		o.putOperation(&status, OperationStatePending, nil)
+
+		err = o.waitAnchored(identifier, before, o.async.pollInterval, o.async.timeout)
+	}
+
+	o.putOperation(&status, OperationStateAnchored, err)
+
+	if o.webhooks != nil {
+		o.notifyWebhooks(&WebhookNotification{OperationID: status.ID, JobID: status.JobID, Type: status.Type,
+			Identifier: status.Identifier}, err, callbackURL)
+	}
+}
+
+// waitAnchored polls the resolution of the DID until its document differs from the document before the operation
+func (o *Operation) waitAnchored(didID string, before []byte, pollInterval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		doc, err := o.resolveDocument(didID)
+		if err == nil && !bytes.Equal(doc, before) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return &operationError{status: http.StatusGatewayTimeout, code: ErrorCodeAnchoringTimeout,
+				err: fmt.Errorf("operation on did %s is not anchored after %s", didID, timeout)}
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func (o *Operation) resolveDocument(didID string) ([]byte, error) {
+	resolved, err := o.blocVDRI.Read(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.DIDDocument.JSONBytes()
+}
+
+// putOperation stores the state of the operation, which has failed with the error if any
+func (o *Operation) putOperation(status *OperationStatus, state string, err error) {
+	status.State = state
+	status.Updated = time.Now()
+
+	if err != nil {
+		_, status.ErrorCode = errorStatus(err)
+		status.Reason = err.Error()
+		status.State = OperationStateFailed
+	}
+
+	if putErr := o.async.store.Put(status); putErr != nil {
+		log.Errorf("failed to store %s state of operation %s: %s", status.State, status.ID, putErr)
+	}
+}
+
+func (o *Operation) operationHandler(rw http.ResponseWriter, req *http.Request) {
+	status, err := o.async.store.Get(mux.Vars(req)["id"])
+	if err != nil {
+		if errors.Is(err, ErrOperationNotFound) {
+			o.writeErrorResponse(rw, http.StatusNotFound, err.Error())
+
+			return
+		}
+
+		o.writeErrorResponse(rw, http.StatusInternalServerError, fmt.Sprintf("failed to get operation: %s", err))
+
+		return
+	}
+
+	// the secret holds private keys, it is returned by the first request of the status after the submission only
+	if secret, ok := o.async.secrets.take(status.ID); ok {
+		status.Secret = secret.(*Secret)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	o.writeResponse(rw, status)
+}
+
+func newOperationID() (string, error) {
+	id := make([]byte, operationIDLength)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// expiringMap is a map whose entries expire after the TTL following their last update, holding at most maxSize
+// entries by evicting the entries closest to expiring
+type expiringMap struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]expiringEntry
+}
+
+type expiringEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+func newExpiringMap(ttl time.Duration, maxSize int) *expiringMap {
+	return &expiringMap{ttl: ttl, maxSize: maxSize, entries: make(map[string]expiringEntry)}
+}
+
+func (m *expiringMap) get(key string) (interface{}, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// take returns the value of the key and removes it
+func (m *expiringMap) take(key string) (interface{}, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	delete(m.entries, key)
+
+	if time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (m *expiringMap) put(key string, value interface{}) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+
+	if _, ok := m.entries[key]; !ok && len(m.entries) >= m.maxSize {
+		m.evict(now)
+	}
+
+	m.entries[key] = expiringEntry{value: value, expires: now.Add(m.ttl)}
+}
+
+// evict removes the expired entries, or else the entry closest to expiring
+func (m *expiringMap) evict(now time.Time) {
+	var oldest string
+
+	for key, entry := range m.entries {
+		if now.After(entry.expires) {
+			delete(m.entries, key)
+		} else if oldest == "" || entry.expires.Before(m.entries[oldest].expires) {
+			oldest = key
+		}
+	}
+
+	if len(m.entries) >= m.maxSize {
+		delete(m.entries, oldest)
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/didbloc"
+)
+
+func TestAsyncOperations(t *testing.T) {
+	t.Run("test create anchored", func(t *testing.T) {
+		svc := getAsyncOperation(&mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{"context"}}}, nil
+			}}, &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did1"}})
+
+		operationID := submitAsync(t, svc, createPath, CreateDIDRequest{JobID: "job1"})
+
+		status := waitOperation(t, svc, operationID, OperationStateAnchored)
+		require.Equal(t, "job1", status.JobID)
+		require.Equal(t, OperationTypeCreate, status.Type)
+		require.Equal(t, "did1", status.Identifier)
+		require.NotNil(t, status.Secret)
+		require.Len(t, status.Secret.Keys, 2)
+
+		// the secret is returned once
+		rr := getOperation(t, svc, operationID)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NotContains(t, rr.Body.String(), "secret")
+
+		stored, err := svc.async.store.Get(operationID)
+		require.NoError(t, err)
+		require.Nil(t, stored.Secret)
+	})
+
+	t.Run("test update anchored when document changes", func(t *testing.T) {
+		var reads int
+
+		svc := getAsyncOperation(&mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				reads++
+				if reads < 3 {
+					return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{"context"}}}, nil
+				}
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{"context"},
+					Service: []did.Service{{ID: "svc"}}}}, nil
+			}}, &didbloc.Client{})
+
+		operationID := submitAsync(t, svc, updatePath, UpdateDIDRequest{Identifier: "did1",
+			Secret: Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeUpdate)}}})
+
+		status := waitOperation(t, svc, operationID, OperationStateAnchored)
+		require.Equal(t, OperationTypeUpdate, status.Type)
+		require.Equal(t, "did1", status.Identifier)
+	})
+
+	t.Run("test recover failed", func(t *testing.T) {
+		svc := getAsyncOperation(&mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, vdr.ErrNotFound
+			}}, &didbloc.Client{})
+
+		operationID := submitAsync(t, svc, recoverPath, RecoverDIDRequest{Identifier: "did1"})
+
+		status := waitOperation(t, svc, operationID, OperationStateFailed)
+		require.Equal(t, ErrorCodeInvalidRequest, status.ErrorCode)
+		require.Contains(t, status.Reason, "secret recovery key is required")
+	})
+
+	t.Run("test create not anchored before timeout", func(t *testing.T) {
+		svc := getAsyncOperation(&mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, vdr.ErrNotFound
+			}}, &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did1"}})
+
+		operationID := submitAsync(t, svc, createPath, CreateDIDRequest{})
+
+		status := waitOperation(t, svc, operationID, OperationStateFailed)
+		require.Equal(t, ErrorCodeAnchoringTimeout, status.ErrorCode)
+	})
+
+	t.Run("test operation not found", func(t *testing.T) {
+		svc := getAsyncOperation(&mockvdr.MockVDR{}, &didbloc.Client{})
+
+		body, status, err := handleRequest(handlerLookup(t, svc, operationsPath), "/1.0/operations/123", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, status)
+		require.Contains(t, body.String(), ErrOperationNotFound.Error())
+	})
+
+	t.Run("test operation store error", func(t *testing.T) {
+		svc := New(&Config{AsyncOperations: &AsyncOperationsConfig{Store: &failingOperationStore{}}})
+
+		body, status, err := handleRequest(handlerLookup(t, svc, createPath), createPath, []byte("{}"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Contains(t, body.String(), "failed to store operation")
+
+		body, status, err = handleRequest(handlerLookup(t, svc, operationsPath), "/1.0/operations/123", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Contains(t, body.String(), "failed to get operation")
+	})
+}
+
+func TestMemOperationStore(t *testing.T) {
+	t.Run("test expired operation", func(t *testing.T) {
+		store := NewMemOperationStore(10*time.Millisecond, 10)
+
+		require.NoError(t, store.Put(&OperationStatus{ID: "op1", State: OperationStatePending}))
+
+		status, err := store.Get("op1")
+		require.NoError(t, err)
+		require.Equal(t, OperationStatePending, status.State)
+
+		time.Sleep(20 * time.Millisecond)
+
+		_, err = store.Get("op1")
+		require.True(t, errors.Is(err, ErrOperationNotFound))
+	})
+
+	t.Run("test maximum number of operations", func(t *testing.T) {
+		store := NewMemOperationStore(time.Hour, 2)
+
+		require.NoError(t, store.Put(&OperationStatus{ID: "op1"}))
+		require.NoError(t, store.Put(&OperationStatus{ID: "op2"}))
+		require.NoError(t, store.Put(&OperationStatus{ID: "op1", State: OperationStateAnchored}))
+
+		// op2 is the closest to expiring
+		require.NoError(t, store.Put(&OperationStatus{ID: "op3"}))
+
+		_, err := store.Get("op2")
+		require.True(t, errors.Is(err, ErrOperationNotFound))
+
+		status, err := store.Get("op1")
+		require.NoError(t, err)
+		require.Equal(t, OperationStateAnchored, status.State)
+
+		_, err = store.Get("op3")
+		require.NoError(t, err)
+	})
+
+	t.Run("test expired operations evicted first", func(t *testing.T) {
+		store := NewMemOperationStore(10*time.Millisecond, 2)
+
+		require.NoError(t, store.Put(&OperationStatus{ID: "op1"}))
+		require.NoError(t, store.Put(&OperationStatus{ID: "op2"}))
+
+		time.Sleep(20 * time.Millisecond)
+
+		require.NoError(t, store.Put(&OperationStatus{ID: "op3"}))
+		require.Len(t, store.operations.entries, 1)
+	})
+}
+
+func TestOperationSecret(t *testing.T) {
+	secrets := newExpiringMap(10*time.Millisecond, 10)
+
+	secrets.put("op1", &Secret{})
+	secrets.put("op2", &Secret{})
+
+	secret, ok := secrets.take("op1")
+	require.True(t, ok)
+	require.NotNil(t, secret)
+
+	_, ok = secrets.take("op1")
+	require.False(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok = secrets.take("op2")
+	require.False(t, ok)
+	require.Empty(t, secrets.entries)
+}
+
+func TestGetRESTHandlersWithAsyncOperations(t *testing.T) {
+	handlers, err := New(&Config{AsyncOperations: &AsyncOperationsConfig{}}).GetRESTHandlers(registrarMode)
+	require.NoError(t, err)
+	require.Equal(t, 6, len(handlers))
+}
+
+func getAsyncOperation(blocVDRI vdr.VDR, didBlocClient didBlocClient) *Operation {
+	svc := New(&Config{AsyncOperations: &AsyncOperationsConfig{PollInterval: time.Millisecond,
+		Timeout: 50 * time.Millisecond}})
+	svc.blocVDRI = blocVDRI
+	svc.didBlocClient = didBlocClient
+
+	return svc
+}
+
+// submitAsync submits the request to the handler of the path, and returns the ID of the accepted operation
+func submitAsync(t *testing.T, svc *Operation, path string, request interface{}) string {
+	reqBytes, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(reqBytes))
+
+	rr := httptest.NewRecorder()
+	handlerLookup(t, svc, path).Handle()(rr, req)
+
+	require.Equal(t, http.StatusAccepted, rr.Code)
+
+	response := RegisterResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Equal(t, RegistrationStateWait, response.DIDState.State)
+
+	operationID, ok := response.RegistrarMetadata["operationId"].(string)
+	require.True(t, ok)
+	require.Equal(t, "/1.0/operations/"+operationID, rr.Header().Get("Location"))
+
+	return operationID
+}
+
+// waitOperation polls the operation status until it has the state, and returns it with the secret returned by one of
+// the polls
+func waitOperation(t *testing.T, svc *Operation, operationID, state string) *OperationStatus {
+	var status *OperationStatus
+
+	var secret *Secret
+
+	require.Eventually(t, func() bool {
+		rr := getOperation(t, svc, operationID)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		status = &OperationStatus{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), status))
+
+		if status.Secret != nil {
+			require.Nil(t, secret, "secret returned twice")
+
+			secret = status.Secret
+		}
+
+		return status.State == state
+	}, time.Second, 5*time.Millisecond)
+
+	status.Secret = secret
+
+	return status
+}
+
+// getOperation requests the status of the operation
+func getOperation(t *testing.T, svc *Operation, operationID string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+
+	handler := handlerLookup(t, svc, operationsPath)
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/1.0/operations/"+operationID, nil))
+
+	return rr
+}
+
+type failingOperationStore struct{}
+
+func (s *failingOperationStore) Get(string) (*OperationStatus, error) {
+	return nil, errors.New("store error")
+}
+
+func (s *failingOperationStore) Put(*OperationStatus) error {
+	return errors.New("store error")
+}
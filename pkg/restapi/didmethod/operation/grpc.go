@@ -0,0 +1,286 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi"
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi/didmethodpb"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/correlation"
+)
+
+// errorCodeTrailer is the trailer holding the error code of a failed call
+const errorCodeTrailer = "x-error-code"
+
+// grpcCodes are the gRPC codes of the HTTP statuses of the operation errors
+var grpcCodes = map[int]codes.Code{ //nolint: gochecknoglobals
+	http.StatusBadRequest:     codes.InvalidArgument,
+	http.StatusUnauthorized:   codes.Unauthenticated,
+	http.StatusForbidden:      codes.PermissionDenied,
+	http.StatusNotFound:       codes.NotFound,
+	http.StatusGatewayTimeout: codes.DeadlineExceeded,
+}
+
+// grpcServer serves the did method operations of the mode over gRPC
+type grpcServer struct {
+	didmethodpb.UnimplementedDIDMethodServer
+	o         *Operation
+	mode      string
+	registrar bool
+	resolver  bool
+}
+
+// GetGRPCServer returns the gRPC server of the did method operations available for the mode, the operations are
+// always submitted synchronously
+func (o *Operation) GetGRPCServer(mode string) (didmethodpb.DIDMethodServer, error) {
+	s := &grpcServer{o: o, mode: mode}
+
+	switch mode {
+	case registrarMode:
+		s.registrar = true
+	case resolverMode:
+		s.resolver = true
+	case combinedMode:
+		s.registrar, s.resolver = true, true
+	default:
+		return nil, fmt.Errorf("grpc api is not supported in mode: %s", mode)
+	}
+
+	return s, nil
+}
+
+// CreateDID creates the DID
+func (s *grpcServer) CreateDID(ctx context.Context,
+	req *didmethodpb.CreateDIDRequest) (*didmethodpb.RegisterResponse, error) {
+	data := &CreateDIDRequest{JobID: req.GetJobId(), Options: req.GetOptions(), Secret: fromPBSecret(req.GetSecret()),
+		DIDDocument: fromPBDocument(req.GetDidDocument())}
+
+	return s.submit(ctx, &WebhookNotification{JobID: data.JobID, Type: OperationTypeCreate}, data.Options,
+		func() (string, *Secret, error) {
+			didDoc, secret, err := s.o.createDID(data, correlation.ID(ctx))
+			if err != nil {
+				return "", nil, err
+			}
+
+			return didDoc.ID, secret, nil
+		})
+}
+
+// UpdateDID updates the DID document, the call is authorized to sign with the key store as the REST request with the
+// same headers
+func (s *grpcServer) UpdateDID(ctx context.Context,
+	req *didmethodpb.UpdateDIDRequest) (*didmethodpb.RegisterResponse, error) {
+	data := &UpdateDIDRequest{JobID: req.GetJobId(), Identifier: req.GetIdentifier(), Options: req.GetOptions(),
+		Secret: fromPBSecret(req.GetSecret()), DIDDocumentOperation: req.GetDidDocumentOperation()}
+
+	for _, didDocument := range req.GetDidDocument() {
+		document := fromPBDocument(didDocument)
+		data.DIDDocument = append(data.DIDDocument, &document)
+	}
+
+	authorized := s.o.keyStoreAuthorized(grpcapi.HTTPRequest(ctx,
+		"/"+didmethodpb.DIDMethod_ServiceDesc.ServiceName+"/UpdateDID"))
+
+	return s.submit(ctx, &WebhookNotification{JobID: data.JobID, Type: OperationTypeUpdate,
+		Identifier: data.Identifier}, data.Options, func() (string, *Secret, error) {
+		secret, err := s.o.updateDID(data, authorized, correlation.ID(ctx))
+
+		return data.Identifier, secret, err
+	})
+}
+
+// RecoverDID replaces the DID document, the call is authorized to sign with the key store as the REST request with
+// the same headers
+func (s *grpcServer) RecoverDID(ctx context.Context,
+	req *didmethodpb.RecoverDIDRequest) (*didmethodpb.RegisterResponse, error) {
+	data := &RecoverDIDRequest{JobID: req.GetJobId(), Identifier: req.GetIdentifier(), Options: req.GetOptions(),
+		Secret: fromPBSecret(req.GetSecret()), DIDDocument: fromPBDocument(req.GetDidDocument())}
+
+	authorized := s.o.keyStoreAuthorized(grpcapi.HTTPRequest(ctx,
+		"/"+didmethodpb.DIDMethod_ServiceDesc.ServiceName+"/RecoverDID"))
+
+	return s.submit(ctx, &WebhookNotification{JobID: data.JobID, Type: OperationTypeRecover,
+		Identifier: data.Identifier}, data.Options, func() (string, *Secret, error) {
+		secret, err := s.o.recoverDID(data, authorized, correlation.ID(ctx))
+
+		return data.Identifier, secret, err
+	})
+}
+
+// DeactivateDID deactivates the DID, the call is authorized as the REST request with the same headers
+func (s *grpcServer) DeactivateDID(ctx context.Context,
+	req *didmethodpb.DeactivateDIDRequest) (*didmethodpb.RegisterResponse, error) {
+	if !s.registrar {
+		return nil, s.notServed()
+	}
+
+	data := &DeactivateDIDRequest{JobID: req.GetJobId(), Identifier: req.GetIdentifier(), Options: req.GetOptions(),
+		Secret: fromPBSecret(req.GetSecret())}
+
+	httpReq := grpcapi.HTTPRequest(ctx, "/"+didmethodpb.DIDMethod_ServiceDesc.ServiceName+"/DeactivateDID")
+
+	authorized, err := s.o.authorizeDeactivation(httpReq)
+	if err == nil {
+		err = s.o.deactivateDID(data, authorized, correlation.ID(ctx))
+	}
+
+	auditDeactivation(httpReq, data.Identifier, authorized, err)
+
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return &didmethodpb.RegisterResponse{JobId: data.JobID,
+		DidState: &didmethodpb.DIDState{Identifier: data.Identifier, State: RegistrationStateFinished}}, nil
+}
+
+// ResolveDID resolves the DID document
+func (s *grpcServer) ResolveDID(_ context.Context,
+	req *didmethodpb.ResolveDIDRequest) (*didmethodpb.ResolveDIDResponse, error) {
+	if !s.resolver {
+		return nil, s.notServed()
+	}
+
+	if !strings.HasPrefix(req.GetDid(), didMethodPrefix) {
+		return nil, status.Errorf(codes.InvalidArgument, "did method is not supported: %s", req.GetDid())
+	}
+
+	resolved, err := s.o.blocVDRI.Read(req.GetDid())
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, vdr.ErrNotFound) {
+			code = codes.NotFound
+		}
+
+		return nil, status.Errorf(code, "failed to resolve did: %s", err)
+	}
+
+	didDoc := resolved.DIDDocument
+
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal did doc: %s", err)
+	}
+
+	resp := &didmethodpb.ResolveDIDResponse{DidDocument: docBytes}
+
+	if didDoc.Created != nil {
+		resp.Created = timestamppb.New(*didDoc.Created)
+	}
+
+	if didDoc.Updated != nil {
+		resp.Updated = timestamppb.New(*didDoc.Updated)
+	}
+
+	return resp, nil
+}
+
+// submit submits the operation, and notifies the webhooks once it is anchored when they are enabled. Submit returns
+// the DID of the operation and its secret.
+func (s *grpcServer) submit(ctx context.Context, notification *WebhookNotification, options map[string]string,
+	submit func() (string, *Secret, error)) (*didmethodpb.RegisterResponse, error) {
+	if !s.registrar {
+		return nil, s.notServed()
+	}
+
+	callbackURL, err := s.o.callbackURL(options)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	watch := s.o.watchOperation(notification, callbackURL)
+
+	didID, secret, err := submit()
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	watch(didID)
+
+	return &didmethodpb.RegisterResponse{JobId: notification.JobID, DidState: &didmethodpb.DIDState{Identifier: didID,
+		State: RegistrationStateFinished, Secret: toPBSecret(secret)}}, nil
+}
+
+func (s *grpcServer) notServed() error {
+	return status.Errorf(codes.Unimplemented, "operation is not available in mode: %s", s.mode)
+}
+
+// grpcError returns the status of the operation error, and sets its error code in the trailer of the call
+func grpcError(ctx context.Context, err error) error {
+	httpStatus, errorCode := errorStatus(err)
+
+	if trailerErr := grpc.SetTrailer(ctx, metadata.Pairs(errorCodeTrailer, errorCode)); trailerErr != nil {
+		log.Debugf("failed to set error code trailer: %s", trailerErr)
+	}
+
+	code, ok := grpcCodes[httpStatus]
+	if !ok {
+		code = codes.Internal
+	}
+
+	return status.Error(code, err.Error())
+}
+
+func fromPBSecret(secret *didmethodpb.Secret) Secret {
+	var s Secret
+
+	for _, key := range secret.GetKeys() {
+		k := Key{PublicKeyBase58: key.GetPublicKeyBase58(), PrivateKeyBase58: key.GetPrivateKeyBase58(),
+			ID: key.GetId(), Purposes: key.GetPurposes()}
+
+		if key.GetPrivateKeyJwk() != "" {
+			k.PrivateKeyJWK = json.RawMessage(key.GetPrivateKeyJwk())
+		}
+
+		s.Keys = append(s.Keys, k)
+	}
+
+	return s
+}
+
+func toPBSecret(secret *Secret) *didmethodpb.Secret {
+	s := &didmethodpb.Secret{}
+
+	for _, key := range secret.Keys {
+		s.Keys = append(s.Keys, &didmethodpb.Key{PublicKeyBase58: key.PublicKeyBase58,
+			PrivateKeyBase58: key.PrivateKeyBase58, Id: key.ID, Purposes: key.Purposes,
+			PrivateKeyJwk: string(key.PrivateKeyJWK)})
+	}
+
+	return s
+}
+
+func fromPBDocument(didDocument *didmethodpb.DIDDocument) DIDDocument {
+	var d DIDDocument
+
+	for _, pk := range didDocument.GetPublicKey() {
+		d.PublicKey = append(d.PublicKey, &PublicKey{ID: pk.GetId(), Type: pk.GetType(), Value: pk.GetValue(),
+			Purposes: pk.GetPurposes(), Encoding: pk.GetEncoding(), Recovery: pk.GetRecovery(), Update: pk.GetUpdate(),
+			KeyType: pk.GetKeyType()})
+	}
+
+	for _, service := range didDocument.GetService() {
+		d.Service = append(d.Service, &Service{ID: service.GetId(), Type: service.GetType(),
+			Priority: uint(service.GetPriority()), RecipientKeys: service.GetRecipientKeys(),
+			RoutingKeys: service.GetRoutingKeys(), Endpoint: service.GetServiceEndpoint()})
+	}
+
+	return d
+}
@@ -0,0 +1,238 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi/didmethodpb"
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/didbloc"
+)
+
+func TestGetGRPCServer(t *testing.T) {
+	for _, mode := range []string{registrarMode, resolverMode, combinedMode} {
+		server, err := New(&Config{}).GetGRPCServer(mode)
+		require.NoError(t, err)
+		require.NotNil(t, server)
+	}
+
+	server, err := New(&Config{}).GetGRPCServer(publicRegistrarMode)
+	require.Error(t, err)
+	require.Nil(t, server)
+	require.Contains(t, err.Error(), "grpc api is not supported in mode")
+}
+
+func TestGRPCCreateDID(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did1"}}, registrarMode)
+
+		resp, err := server.CreateDID(context.Background(), &didmethodpb.CreateDIDRequest{JobId: "job1",
+			DidDocument: &didmethodpb.DIDDocument{Service: []*didmethodpb.Service{{Id: "svc1", Type: "type",
+				ServiceEndpoint: "http://example.com", Priority: 1}}}})
+		require.NoError(t, err)
+		require.Equal(t, "job1", resp.JobId)
+		require.Equal(t, "did1", resp.DidState.Identifier)
+		require.Equal(t, RegistrationStateFinished, resp.DidState.State)
+		require.Len(t, resp.DidState.Secret.Keys, 2)
+	})
+
+	t.Run("test error from sidetree", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{CreateDIDErr: errors.New("create error")}, registrarMode)
+
+		_, err := server.CreateDID(context.Background(), &didmethodpb.CreateDIDRequest{})
+		requireStatus(t, err, codes.Internal, "create error")
+	})
+
+	t.Run("test invalid public key", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, registrarMode)
+
+		_, err := server.CreateDID(context.Background(), &didmethodpb.CreateDIDRequest{
+			DidDocument: &didmethodpb.DIDDocument{PublicKey: []*didmethodpb.PublicKey{{Id: "key1",
+				Value: "invalid"}}}})
+		requireStatus(t, err, codes.Internal, "failed to decode public key value")
+	})
+
+	t.Run("test not available in resolver mode", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, resolverMode)
+
+		_, err := server.CreateDID(context.Background(), &didmethodpb.CreateDIDRequest{})
+		requireStatus(t, err, codes.Unimplemented, "operation is not available in mode: resolver")
+	})
+}
+
+func TestGRPCUpdateDID(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, combinedMode)
+
+		resp, err := server.UpdateDID(context.Background(), &didmethodpb.UpdateDIDRequest{Identifier: "did1",
+			Secret:               toPBSecret(&Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeUpdate)}}),
+			DidDocumentOperation: []string{DIDDocumentOperationRemove},
+			DidDocument:          []*didmethodpb.DIDDocument{{Service: []*didmethodpb.Service{{Id: "svc1"}}}}})
+		require.NoError(t, err)
+		require.Equal(t, "did1", resp.DidState.Identifier)
+		require.Equal(t, RegistrationStateFinished, resp.DidState.State)
+	})
+
+	t.Run("test missing update key", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, combinedMode)
+
+		_, err := server.UpdateDID(context.Background(), &didmethodpb.UpdateDIDRequest{Identifier: "did1"})
+		requireStatus(t, err, codes.InvalidArgument, "secret update key is required")
+	})
+
+	t.Run("test invalid callback url", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, combinedMode)
+
+		_, err := server.UpdateDID(context.Background(), &didmethodpb.UpdateDIDRequest{Identifier: "did1",
+			Options: map[string]string{CallbackURLOption: "https://example.com/callback"}})
+		requireStatus(t, err, codes.InvalidArgument, "callback urls are not enabled")
+	})
+}
+
+func TestGRPCRecoverDID(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, registrarMode)
+
+		resp, err := server.RecoverDID(context.Background(), &didmethodpb.RecoverDIDRequest{Identifier: "did1",
+			Secret: toPBSecret(&Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeRecovery)}})})
+		require.NoError(t, err)
+		require.Equal(t, "did1", resp.DidState.Identifier)
+		require.Equal(t, RegistrationStateFinished, resp.DidState.State)
+		require.NotEmpty(t, resp.DidState.Secret.Keys)
+	})
+
+	t.Run("test error from sidetree", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{RecoverDIDErr: errors.New("recover error")}, registrarMode)
+
+		_, err := server.RecoverDID(context.Background(), &didmethodpb.RecoverDIDRequest{Identifier: "did1",
+			Secret: toPBSecret(&Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeRecovery)}})})
+		requireStatus(t, err, codes.Internal, "recover error")
+	})
+}
+
+func TestGRPCDeactivateDID(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, registrarMode)
+
+		resp, err := server.DeactivateDID(context.Background(), &didmethodpb.DeactivateDIDRequest{JobId: "job1",
+			Identifier: "did1",
+			Secret:     toPBSecret(&Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeRecovery)}})})
+		require.NoError(t, err)
+		require.Equal(t, "job1", resp.JobId)
+		require.Equal(t, RegistrationStateFinished, resp.DidState.State)
+	})
+
+	t.Run("test invalid deactivation token", func(t *testing.T) {
+		svc := New(&Config{DeactivationToken: "token"})
+		svc.didBlocClient = &didbloc.Client{}
+
+		server, err := svc.GetGRPCServer(registrarMode)
+		require.NoError(t, err)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-deactivation-token", "other"))
+
+		_, err = server.DeactivateDID(ctx, &didmethodpb.DeactivateDIDRequest{Identifier: "did1"})
+		requireStatus(t, err, codes.Unauthenticated, "invalid or missing deactivation token")
+	})
+
+	t.Run("test missing identifier", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, registrarMode)
+
+		_, err := server.DeactivateDID(context.Background(), &didmethodpb.DeactivateDIDRequest{})
+		requireStatus(t, err, codes.InvalidArgument, "identifier is required")
+	})
+
+	t.Run("test not available in resolver mode", func(t *testing.T) {
+		server := getGRPCServer(t, nil, &didbloc.Client{}, resolverMode)
+
+		_, err := server.DeactivateDID(context.Background(), &didmethodpb.DeactivateDIDRequest{})
+		requireStatus(t, err, codes.Unimplemented, "operation is not available")
+	})
+}
+
+func TestGRPCResolveDID(t *testing.T) {
+	created := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("test success", func(t *testing.T) {
+		server := getGRPCServer(t, &mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{"context"}, Created: &created}}, nil
+			}}, nil, resolverMode)
+
+		resp, err := server.ResolveDID(context.Background(), &didmethodpb.ResolveDIDRequest{Did: "did:trustbloc:a:b"})
+		require.NoError(t, err)
+		require.Contains(t, string(resp.DidDocument), "did:trustbloc:a:b")
+		require.True(t, created.Equal(resp.Created.AsTime()))
+		require.Nil(t, resp.Updated)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		server := getGRPCServer(t, &mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, vdr.ErrNotFound
+			}}, nil, combinedMode)
+
+		_, err := server.ResolveDID(context.Background(), &didmethodpb.ResolveDIDRequest{Did: "did:trustbloc:a:b"})
+		requireStatus(t, err, codes.NotFound, "failed to resolve did")
+	})
+
+	t.Run("test resolve error", func(t *testing.T) {
+		server := getGRPCServer(t, &mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, errors.New("read error")
+			}}, nil, combinedMode)
+
+		_, err := server.ResolveDID(context.Background(), &didmethodpb.ResolveDIDRequest{Did: "did:trustbloc:a:b"})
+		requireStatus(t, err, codes.Internal, "read error")
+	})
+
+	t.Run("test method not supported", func(t *testing.T) {
+		server := getGRPCServer(t, &mockvdr.MockVDR{}, nil, resolverMode)
+
+		_, err := server.ResolveDID(context.Background(), &didmethodpb.ResolveDIDRequest{Did: "did:example:a"})
+		requireStatus(t, err, codes.InvalidArgument, "did method is not supported")
+	})
+
+	t.Run("test not available in registrar mode", func(t *testing.T) {
+		server := getGRPCServer(t, &mockvdr.MockVDR{}, nil, registrarMode)
+
+		_, err := server.ResolveDID(context.Background(), &didmethodpb.ResolveDIDRequest{Did: "did:trustbloc:a:b"})
+		requireStatus(t, err, codes.Unimplemented, "operation is not available in mode: registrar")
+	})
+}
+
+func getGRPCServer(t *testing.T, blocVDRI vdr.VDR, client didBlocClient,
+	mode string) didmethodpb.DIDMethodServer {
+	t.Helper()
+
+	svc := New(&Config{})
+	svc.blocVDRI = blocVDRI
+	svc.didBlocClient = client
+
+	server, err := svc.GetGRPCServer(mode)
+	require.NoError(t, err)
+
+	return server
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, msg string) {
+	t.Helper()
+
+	require.Error(t, err)
+	require.Equal(t, code, status.Code(err))
+	require.Contains(t, err.Error(), msg)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	gojose "github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/keystore"
+)
+
+// ErrKeyNotFound is returned when the key store has no key with the purpose for the DID
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyStore holds the private recovery and update keys of the DIDs managed by the service, so that requests can
+// delegate the signing of their operations to the service instead of sending the keys in their secret
+type KeyStore interface {
+	// Get returns the private key JWK with the purpose for the DID
+	Get(did, purpose string) ([]byte, error)
+	// Put stores the private key JWK with the purpose for the DID, replacing the previous key
+	Put(did, purpose string, jwk []byte) error
+}
+
+// EncryptedKeyStore is a key store keeping the keys in a keystore file encrypted with a password, in the key set
+// of the DID
+type EncryptedKeyStore struct {
+	mutex    sync.Mutex
+	keystore *keystore.Keystore
+}
+
+// NewEncryptedKeyStore opens the keystore file with the password, creating the file if it doesn't exist
+func NewEncryptedKeyStore(path string, password []byte) (*EncryptedKeyStore, error) {
+	open := keystore.Open
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		open = keystore.Create
+	}
+
+	ks, err := open(path, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key store: %w", err)
+	}
+
+	return &EncryptedKeyStore{keystore: ks}, nil
+}
+
+// Get returns the private key JWK with the purpose for the DID
+func (s *EncryptedKeyStore) Get(did, purpose string) ([]byte, error) {
+	s.mutex.Lock()
+	privateKey, err := s.keystore.KeySetByDID(did).PrivateKey(purpose)
+	s.mutex.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s key of %s: %w", purpose, did, err)
+	}
+
+	if privateKey == nil {
+		return nil, ErrKeyNotFound
+	}
+
+	jwk, err := (&gojose.JSONWebKey{Key: privateKey}).MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s key of %s: %w", purpose, did, err)
+	}
+
+	return jwk, nil
+}
+
+// Put stores the private key JWK with the purpose for the DID, replacing the previous key
+func (s *EncryptedKeyStore) Put(did, purpose string, jwk []byte) error {
+	key := gojose.JSONWebKey{}
+	if err := key.UnmarshalJSON(jwk); err != nil {
+		return fmt.Errorf("invalid %s key of %s: %w", purpose, did, err)
+	}
+
+	if key.IsPublic() {
+		return fmt.Errorf("%s key of %s is not a private key", purpose, did)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// the DID is the alias of the key sets created by the service
+	alias := did
+	if keySet := s.keystore.KeySetByDID(did); keySet != nil {
+		alias = keySet.Alias
+	}
+
+	if err := s.keystore.AddKey(alias, did, purpose, key.Key); err != nil {
+		return fmt.Errorf("failed to store %s key of %s: %w", purpose, did, err)
+	}
+
+	if err := s.keystore.Save(); err != nil {
+		return fmt.Errorf("failed to store %s key of %s: %w", purpose, did, err)
+	}
+
+	return nil
+}
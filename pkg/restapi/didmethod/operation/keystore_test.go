@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gojose "github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "keystore.json")
+
+	keyStore, err := NewEncryptedKeyStore(path, []byte("password"))
+	require.NoError(t, err)
+
+	jwk1 := secretKeyJWK(t, "", SecretPurposeUpdate).PrivateKeyJWK
+	jwk2 := secretKeyJWK(t, "", SecretPurposeUpdate).PrivateKeyJWK
+
+	t.Run("test put and get", func(t *testing.T) {
+		require.NoError(t, keyStore.Put("did:trustbloc:domain:123", SecretPurposeUpdate, jwk1))
+		require.NoError(t, keyStore.Put("did:trustbloc:domain:123", SecretPurposeUpdate, jwk2))
+
+		jwk, err := keyStore.Get("did:trustbloc:domain:123", SecretPurposeUpdate)
+		require.NoError(t, err)
+		require.JSONEq(t, string(jwk2), string(jwk))
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		_, err := keyStore.Get("did:trustbloc:domain:123", SecretPurposeRecovery)
+		require.Equal(t, ErrKeyNotFound, err)
+
+		_, err = keyStore.Get("did:trustbloc:domain:456", SecretPurposeUpdate)
+		require.Equal(t, ErrKeyNotFound, err)
+	})
+
+	t.Run("test keys encrypted at rest", func(t *testing.T) {
+		data, err := ioutil.ReadFile(filepath.Clean(path))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "did:trustbloc:domain:123")
+
+		reopened, err := NewEncryptedKeyStore(path, []byte("password"))
+		require.NoError(t, err)
+
+		jwk, err := reopened.Get("did:trustbloc:domain:123", SecretPurposeUpdate)
+		require.NoError(t, err)
+		require.JSONEq(t, string(jwk2), string(jwk))
+
+		_, err = NewEncryptedKeyStore(path, []byte("other"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open key store")
+	})
+
+	t.Run("test invalid keys", func(t *testing.T) {
+		err := keyStore.Put("did:trustbloc:domain:123", SecretPurposeUpdate, []byte("jwk"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid update key")
+
+		key := gojose.JSONWebKey{}
+		require.NoError(t, key.UnmarshalJSON(jwk1))
+
+		publicJWK, err := key.Public().MarshalJSON()
+		require.NoError(t, err)
+
+		err = keyStore.Put("did:trustbloc:domain:123", SecretPurposeUpdate, publicJWK)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a private key")
+
+		err = keyStore.Put("did:trustbloc:domain:123", "other", jwk1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid key purpose")
+	})
+
+	t.Run("test password required", func(t *testing.T) {
+		_, err := NewEncryptedKeyStore(filepath.Join(dir, "other.json"), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "password is required")
+	})
+}
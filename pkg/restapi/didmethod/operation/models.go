@@ -5,11 +5,55 @@ SPDX-License-Identifier: Apache-2.0
 
 package operation
 
+import (
+	"encoding/json"
+	"time"
+)
+
 const (
 	// RegistrationStateFinished registration state finished
 	RegistrationStateFinished = "finished"
 	// RegistrationStateFailure registration state failure
 	RegistrationStateFailure = "failure"
+	// RegistrationStateWait registration state of an operation submitted asynchronously
+	RegistrationStateWait = "wait"
+
+	// OperationStatePending state of an asynchronous operation which is submitted or not anchored yet
+	OperationStatePending = "pending"
+	// OperationStateAnchored state of an asynchronous operation whose change of the did document is observable
+	OperationStateAnchored = "anchored"
+	// OperationStateFailed state of an asynchronous operation which failed to be submitted or anchored
+	OperationStateFailed = "failed"
+
+	// OperationTypeCreate type of an asynchronous create operation
+	OperationTypeCreate = "create"
+	// OperationTypeUpdate type of an asynchronous update operation
+	OperationTypeUpdate = "update"
+	// OperationTypeRecover type of an asynchronous recover operation
+	OperationTypeRecover = "recover"
+
+	// SecretPurposeRecovery purpose of the recovery key in a secret
+	SecretPurposeRecovery = "recovery"
+	// SecretPurposeUpdate purpose of the update key in a secret
+	SecretPurposeUpdate = "update"
+	// SecretPurposeNextUpdate purpose of the next update key in a secret
+	SecretPurposeNextUpdate = "nextUpdate"
+	// SecretPurposeNextRecovery purpose of the next recovery key in a secret
+	SecretPurposeNextRecovery = "nextRecovery"
+
+	// DIDDocumentOperationAdd adds the public keys and services of the did document
+	DIDDocumentOperationAdd = "addToDidDocument"
+	// DIDDocumentOperationRemove removes the public keys and services with the IDs of the did document
+	DIDDocumentOperationRemove = "removeFromDidDocument"
+
+	// ErrorCodeInvalidRequest error code of a request which is malformed or misses its signing key
+	ErrorCodeInvalidRequest = "invalidRequest"
+	// ErrorCodeUnauthorized error code of a request which is not authorized for the operation
+	ErrorCodeUnauthorized = "unauthorized"
+	// ErrorCodeOperationFailed error code of an operation which failed to be submitted to sidetree
+	ErrorCodeOperationFailed = "operationFailed"
+	// ErrorCodeAnchoringTimeout error code of an asynchronous operation which wasn't anchored before the timeout
+	ErrorCodeAnchoringTimeout = "anchoringTimeout"
 )
 
 // RegisterDIDRequest input data for register DID
@@ -19,6 +63,49 @@ type RegisterDIDRequest struct {
 	DIDDocument DIDDocument       `json:"didDocument,omitempty"`
 }
 
+// CreateDIDRequest input data for the universal registrar driver create
+type CreateDIDRequest struct {
+	JobID   string            `json:"jobId,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+	// Secret may hold the private recovery and update keys, otherwise they are generated
+	Secret      Secret      `json:"secret,omitempty"`
+	DIDDocument DIDDocument `json:"didDocument,omitempty"`
+}
+
+// UpdateDIDRequest input data for the universal registrar driver update
+type UpdateDIDRequest struct {
+	JobID      string            `json:"jobId,omitempty"`
+	Identifier string            `json:"identifier,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+	// Secret holds the private update key, unless the service key store holds it, and may hold the next update key,
+	// otherwise it is generated
+	Secret Secret `json:"secret,omitempty"`
+	// DIDDocumentOperation is the operation applied with each did document, addToDidDocument if not set
+	DIDDocumentOperation []string       `json:"didDocumentOperation,omitempty" openapi:"enum=addToDidDocument|removeFromDidDocument"` //nolint: lll
+	DIDDocument          []*DIDDocument `json:"didDocument,omitempty"`
+}
+
+// RecoverDIDRequest input data for recover DID
+type RecoverDIDRequest struct {
+	JobID      string            `json:"jobId,omitempty"`
+	Identifier string            `json:"identifier,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+	// Secret holds the private recovery key, unless the service key store holds it, and may hold the next recovery
+	// and next update keys, otherwise they are generated
+	Secret Secret `json:"secret,omitempty"`
+	// DIDDocument is the new content of the did document
+	DIDDocument DIDDocument `json:"didDocument,omitempty"`
+}
+
+// DeactivateDIDRequest input data for the universal registrar driver deactivate
+type DeactivateDIDRequest struct {
+	JobID      string            `json:"jobId,omitempty"`
+	Identifier string            `json:"identifier,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+	// Secret holds the private recovery key
+	Secret Secret `json:"secret,omitempty"`
+}
+
 // DIDDocument did doc
 type DIDDocument struct {
 	PublicKey []*PublicKey `json:"publicKey,omitempty"`
@@ -37,8 +124,10 @@ type RegisterResponse struct {
 type DIDState struct {
 	Identifier string `json:"identifier,omitempty"`
 	Reason     string `json:"reason,omitempty"`
-	State      string `json:"state,omitempty"`
-	Secret     Secret `json:"secret,omitempty"`
+	// ErrorCode classifies the reason of a failed operation
+	ErrorCode string `json:"errorCode,omitempty"`
+	State     string `json:"state,omitempty"`
+	Secret    Secret `json:"secret,omitempty"`
 }
 
 // Secret include keys
@@ -51,7 +140,9 @@ type Key struct {
 	PublicKeyBase58  string   `json:"publicKeyBase58,omitempty"`
 	PrivateKeyBase58 string   `json:"privateKeyBase58,omitempty"`
 	ID               string   `json:"id,omitempty"`
-	Purposes         []string `json:"purposes,omitempty"`
+	Purposes         []string `json:"purposes,omitempty" openapi:"enum=recovery|update|nextUpdate|nextRecovery"`
+	// PrivateKeyJWK is the private key JWK of a recovery or update key
+	PrivateKeyJWK json.RawMessage `json:"privateKeyJwk,omitempty"`
 }
 
 // PublicKey public key
@@ -76,3 +167,39 @@ type Service struct {
 	RoutingKeys   []string `json:"routingKeys,omitempty"`
 	Endpoint      string   `json:"serviceEndpoint,omitempty"`
 }
+
+// DIDResolutionResult universal resolver driver DID resolution result
+type DIDResolutionResult struct {
+	Context               string                 `json:"@context"`
+	DIDDocument           json.RawMessage        `json:"didDocument,omitempty"`
+	DIDResolutionMetadata map[string]interface{} `json:"didResolutionMetadata"`
+	DIDDocumentMetadata   map[string]interface{} `json:"didDocumentMetadata"`
+}
+
+// OperationStatus status of an asynchronous operation
+type OperationStatus struct {
+	ID         string `json:"id"`
+	JobID      string `json:"jobId,omitempty"`
+	Type       string `json:"type"`
+	State      string `json:"state"`
+	Identifier string `json:"identifier,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	ErrorCode  string `json:"errorCode,omitempty"`
+	// Secret holds the generated keys of the operation once it is submitted, it is returned by the first request of
+	// the status after the submission only
+	Secret  *Secret   `json:"secret,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// WebhookNotification notification of the outcome of an operation, sent to the webhooks
+type WebhookNotification struct {
+	// OperationID is the ID of an asynchronous operation
+	OperationID string    `json:"operationId,omitempty"`
+	JobID       string    `json:"jobId,omitempty"`
+	Type        string    `json:"type"`
+	Identifier  string    `json:"identifier"`
+	State       string    `json:"state"`
+	Reason      string    `json:"reason,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
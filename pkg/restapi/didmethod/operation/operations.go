@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -24,8 +25,13 @@ import (
 	didclient "github.com/trustbloc/trustbloc-did-method/pkg/did"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
 	"github.com/trustbloc/trustbloc-did-method/pkg/internal/common/support"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/correlation"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 )
 
@@ -37,9 +43,10 @@ const (
 	invalidRequestErrMsg = "invalid request"
 
 	// modes
-	registrarMode = "registrar"
-	resolverMode  = "resolver"
-	combinedMode  = "combined"
+	registrarMode       = "registrar"
+	resolverMode        = "resolver"
+	combinedMode        = "combined"
+	publicRegistrarMode = "public-registrar"
 )
 
 // Handler http handler for each controller API endpoint
@@ -51,9 +58,14 @@ type Handler interface {
 
 // Operation defines handlers
 type Operation struct {
-	blocVDRI      vdr.VDR
-	didBlocClient didBlocClient
-	blocDomain    string
+	blocVDRI           vdr.VDR
+	didBlocClient      didBlocClient
+	blocDomain         string
+	publicRegistration *publicRegistration
+	keyStore           KeyStore
+	deactivationToken  string
+	async              *asyncOperations
+	webhooks           *webhooks
 }
 
 // Config defines configuration for trustbloc did method operations
@@ -64,25 +76,63 @@ type Config struct {
 	SidetreeReadToken  string
 	SidetreeWriteToken string
 	EnableSignatures   bool
+	PublicRegistration *PublicRegistrationConfig
+	// KeyStore signs the operations of requests which don't hold the signing key in their secret, if set
+	KeyStore KeyStore
+	// DeactivationToken is the bearer token authorizing deactivate requests, and the update and recover requests
+	// signed with the key store, if set
+	DeactivationToken string
+	// MetricsProvider records the metrics of the sidetree requests, if set
+	MetricsProvider metrics.Provider
+	// AsyncOperations enables the asynchronous create, update and recover operations, if set
+	AsyncOperations *AsyncOperationsConfig
+	// Webhooks enables the notifications of the outcome of the operations, if set
+	Webhooks *WebhooksConfig
+	// Resolver resolves the hosts of the consortium domain and of the sidetree endpoints, instead of the system
+	// resolver, if set
+	Resolver *net.Resolver
 }
 
 type didBlocClient interface {
 	CreateDID(domain string, opts ...create.Option) (*did.Doc, error)
+	UpdateDID(did, domain string, opts ...update.Option) error
+	RecoverDID(did, domain string, opts ...recovery.Option) error
+	DeactivateDID(did, domain string, opts ...deactivate.Option) error
 }
 
 // New returns did method operation instance
 func New(config *Config) *Operation {
+	metricsProvider := config.MetricsProvider
+	if metricsProvider == nil {
+		metricsProvider = metrics.NoopProvider{}
+	}
+
 	svc := &Operation{blocVDRI: trustbloc.New(trustbloc.WithTLSConfig(config.TLSConfig),
 		trustbloc.WithAuthToken(config.SidetreeReadToken), trustbloc.EnableSignatureVerification(config.EnableSignatures),
-		trustbloc.WithDomain(config.BlocDomain)),
+		trustbloc.WithDomain(config.BlocDomain), trustbloc.WithMetricsProvider(metricsProvider),
+		trustbloc.WithResolver(config.Resolver)),
 		didBlocClient: didclient.New(didclient.WithTLSConfig(config.TLSConfig),
-			didclient.WithAuthToken(config.SidetreeWriteToken)),
-		blocDomain: config.BlocDomain}
+			didclient.WithAuthToken(config.SidetreeWriteToken), didclient.WithMetricsProvider(metricsProvider),
+			didclient.WithResolver(config.Resolver)),
+		blocDomain: config.BlocDomain, keyStore: config.KeyStore,
+		deactivationToken: config.DeactivationToken}
+
+	if config.PublicRegistration != nil {
+		svc.publicRegistration = newPublicRegistration(config.PublicRegistration, metricsProvider)
+	}
+
+	if config.AsyncOperations != nil {
+		svc.async = newAsyncOperations(config.AsyncOperations)
+	}
+
+	if config.Webhooks != nil {
+		svc.webhooks = newWebhooks(config.Webhooks)
+	}
 
 	return svc
 }
 
-func (o *Operation) registerDIDHandler(rw http.ResponseWriter, req *http.Request) { //nolint: funlen,gocyclo
+func (o *Operation) registerDIDHandler(rw http.ResponseWriter, req *http.Request) {
 	data := RegisterDIDRequest{}
 
 	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
@@ -91,10 +141,7 @@ func (o *Operation) registerDIDHandler(rw http.ResponseWriter, req *http.Request
 		return
 	}
 
-	var opts []create.Option
-
 	registerResponse := RegisterResponse{JobID: data.JobID}
-	keysID := make(map[string][]byte)
 
 	if len(data.DIDDocument.PublicKey) == 0 {
 		registerResponse.DIDState = DIDState{Reason: "AddPublicKeys is empty",
@@ -105,79 +152,102 @@ func (o *Operation) registerDIDHandler(rw http.ResponseWriter, req *http.Request
 		return
 	}
 
-	// Add public keys
-	for _, v := range data.DIDDocument.PublicKey {
-		keyValue, err := base64.StdEncoding.DecodeString(v.Value)
-		if err != nil {
-			log.Errorf("failed to decode public key value : %s", err.Error())
+	options, err := didDocumentOptions(&data.DIDDocument)
+	if err != nil {
+		registerResponse.DIDState = DIDState{Reason: err.Error(), State: RegistrationStateFailure}
 
-			registerResponse.DIDState = DIDState{Reason: fmt.Sprintf("failed to decode public key value : %s",
-				err.Error()), State: RegistrationStateFailure}
+		o.writeResponse(rw, registerResponse)
 
-			o.writeResponse(rw, registerResponse)
+		return
+	}
 
-			return
-		}
+	if correlationID := correlation.ID(req.Context()); correlationID != "" {
+		options.opts = append(options.opts, create.WithRequestHeader(correlation.Header, correlationID))
+	}
 
-		if v.Recovery {
-			k, err := getKey(v.KeyType, keyValue)
-			if err != nil {
-				registerResponse.DIDState = DIDState{Reason: err.Error(), State: RegistrationStateFailure}
+	didDoc, err := o.didBlocClient.CreateDID(o.blocDomain, options.opts...)
+	if err != nil {
+		log.Errorf("failed to create did doc : %s", err.Error())
 
-				o.writeResponse(rw, registerResponse)
+		registerResponse.DIDState = DIDState{Reason: fmt.Sprintf("failed to create did doc : %s", err.Error()),
+			State: RegistrationStateFailure}
 
-				return
-			}
+		o.writeResponse(rw, registerResponse)
 
-			opts = append(opts, create.WithRecoveryPublicKey(k))
+		return
+	}
 
-			continue
+	registerResponse.DIDState = DIDState{Identifier: didDoc.ID, State: RegistrationStateFinished,
+		Secret: Secret{Keys: createKeys(options.keys, didDoc.ID)}}
+
+	o.writeResponse(rw, registerResponse)
+}
+
+// createOptions are the create options of a requested DID document
+type createOptions struct {
+	opts []create.Option
+	// keys are the values of the verification keys by ID
+	keys     map[string][]byte
+	recovery bool
+	update   bool
+}
+
+// didDocumentOptions converts the public keys and services of a requested DID document to create options
+func didDocumentOptions(didDocument *DIDDocument) (*createOptions, error) {
+	options := &createOptions{keys: make(map[string][]byte)}
+
+	// Add public keys
+	for _, v := range didDocument.PublicKey {
+		keyValue, err := decodePublicKey(v)
+		if err != nil {
+			return nil, err
 		}
 
-		if v.Update {
+		if v.Recovery || v.Update {
 			k, err := getKey(v.KeyType, keyValue)
 			if err != nil {
-				registerResponse.DIDState = DIDState{Reason: err.Error(), State: RegistrationStateFailure}
-
-				o.writeResponse(rw, registerResponse)
-
-				return
+				return nil, err
 			}
 
-			opts = append(opts, create.WithUpdatePublicKey(k))
+			if v.Recovery {
+				options.opts = append(options.opts, create.WithRecoveryPublicKey(k))
+				options.recovery = true
+			} else {
+				options.opts = append(options.opts, create.WithUpdatePublicKey(k))
+				options.update = true
+			}
 
 			continue
 		}
 
-		opts = append(opts, create.WithPublicKey(&doc.PublicKey{ID: v.ID, Type: v.Type, Value: keyValue,
-			Encoding: v.Encoding, Purposes: v.Purposes, KeyType: v.KeyType}))
+		options.opts = append(options.opts, create.WithPublicKey(&doc.PublicKey{ID: v.ID, Type: v.Type,
+			Value: keyValue, Encoding: v.Encoding, Purposes: v.Purposes, KeyType: v.KeyType}))
 
-		keysID[v.ID] = keyValue
+		options.keys[v.ID] = keyValue
 	}
 
 	// Add services
-	for _, service := range data.DIDDocument.Service {
-		opts = append(opts, create.WithService(&did.Service{ID: service.ID, Type: service.Type,
-			Priority: service.Priority, RecipientKeys: service.RecipientKeys, RoutingKeys: service.RoutingKeys,
-			ServiceEndpoint: service.Endpoint}))
+	for _, service := range didDocument.Service {
+		options.opts = append(options.opts, create.WithService(toService(service)))
 	}
 
-	didDoc, err := o.didBlocClient.CreateDID(o.blocDomain, opts...)
-	if err != nil {
-		log.Errorf("failed to create did doc : %s", err.Error())
-
-		registerResponse.DIDState = DIDState{Reason: fmt.Sprintf("failed to create did doc : %s", err.Error()),
-			State: RegistrationStateFailure}
+	return options, nil
+}
 
-		o.writeResponse(rw, registerResponse)
+func decodePublicKey(publicKey *PublicKey) ([]byte, error) {
+	keyValue, err := base64.StdEncoding.DecodeString(publicKey.Value)
+	if err != nil {
+		log.Errorf("failed to decode public key value : %s", err.Error())
 
-		return
+		return nil, fmt.Errorf("failed to decode public key value : %s", err.Error())
 	}
 
-	registerResponse.DIDState = DIDState{Identifier: didDoc.ID, State: RegistrationStateFinished,
-		Secret: Secret{Keys: createKeys(keysID, didDoc.ID)}}
+	return keyValue, nil
+}
 
-	o.writeResponse(rw, registerResponse)
+func toService(service *Service) *did.Service {
+	return &did.Service{ID: service.ID, Type: service.Type, Priority: service.Priority,
+		RecipientKeys: service.RecipientKeys, RoutingKeys: service.RoutingKeys, ServiceEndpoint: service.Endpoint}
 }
 
 func getKey(keyType string, value []byte) (interface{}, error) {
@@ -212,7 +282,7 @@ func (o *Operation) resolveDIDHandler(rw http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	didDoc, err := o.blocVDRI.Read(didParam[0])
+	resolved, err := o.blocVDRI.Read(didParam[0])
 	if err != nil {
 		o.writeErrorResponse(rw, http.StatusBadRequest,
 			fmt.Sprintf("failed to resolve did: %s", err.Error()))
@@ -220,6 +290,8 @@ func (o *Operation) resolveDIDHandler(rw http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	didDoc := resolved.DIDDocument
+
 	bytes, err := models.MakeDIDResolutionResult(didDoc)
 	if err != nil {
 		o.writeErrorResponse(rw, http.StatusInternalServerError,
@@ -254,13 +326,30 @@ func (o *Operation) writeResponse(rw io.Writer, v interface{}) {
 }
 
 func (o *Operation) registrarHandlers() []Handler {
-	return []Handler{
-		support.NewHTTPHandler(registerPath, http.MethodPost, o.registerDIDHandler)}
+	handlers := []Handler{
+		support.NewHTTPHandler(registerPath, http.MethodPost, o.registerDIDHandler),
+		support.NewHTTPHandler(createPath, http.MethodPost, o.createDIDHandler),
+		support.NewHTTPHandler(updatePath, http.MethodPost, o.updateDIDHandler),
+		support.NewHTTPHandler(recoverPath, http.MethodPost, o.recoverDIDHandler),
+		support.NewHTTPHandler(deactivatePath, http.MethodPost, o.deactivateDIDHandler)}
+
+	if o.async != nil {
+		handlers = append(handlers, support.NewHTTPHandler(operationsPath, http.MethodGet, o.operationHandler))
+	}
+
+	return handlers
 }
 
 func (o *Operation) resolverHandlers() []Handler {
 	return []Handler{
-		support.NewHTTPHandler(resolveDIDEndpoint, http.MethodGet, o.resolveDIDHandler)}
+		support.NewHTTPHandler(resolveDIDEndpoint, http.MethodGet, o.resolveDIDHandler),
+		support.NewHTTPHandler(identifiersPath, http.MethodGet, o.identifiersHandler)}
+}
+
+func (o *Operation) publicRegistrarHandlers() []Handler {
+	return []Handler{
+		support.NewHTTPHandler(registerChallengePath, http.MethodGet, o.registerChallengeHandler),
+		support.NewHTTPHandler(registerPath, http.MethodPost, o.publicRegisterDIDHandler)}
 }
 
 // GetRESTHandlers get all controller API handler available for this service
@@ -275,6 +364,16 @@ func (o *Operation) GetRESTHandlers(mode string) ([]Handler, error) {
 		ih := o.resolverHandlers()
 
 		return append(vh, ih...), nil
+	case publicRegistrarMode:
+		if o.publicRegistration == nil {
+			return nil, fmt.Errorf("public registration config is required for mode: %s", mode)
+		}
+
+		if err := o.publicRegistration.validate(); err != nil {
+			return nil, err
+		}
+
+		return o.publicRegistrarHandlers(), nil
 	default:
 		return nil, fmt.Errorf("invalid operation mode: %s", mode)
 	}
@@ -35,9 +35,13 @@ func TestNew(t *testing.T) {
 		handlers, err := svc.GetRESTHandlers(combinedMode)
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
-		require.Equal(t, 2, len(handlers))
+		require.Equal(t, 7, len(handlers))
 		require.Equal(t, registerPath, handlers[0].Path())
-		require.Equal(t, resolveDIDEndpoint, handlers[1].Path())
+		require.Equal(t, createPath, handlers[1].Path())
+		require.Equal(t, updatePath, handlers[2].Path())
+		require.Equal(t, recoverPath, handlers[3].Path())
+		require.Equal(t, deactivatePath, handlers[4].Path())
+		require.Equal(t, resolveDIDEndpoint, handlers[5].Path())
 	})
 
 	t.Run("test registrar mode", func(t *testing.T) {
@@ -46,7 +50,7 @@ func TestNew(t *testing.T) {
 		handlers, err := svc.GetRESTHandlers(registrarMode)
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
-		require.Equal(t, 1, len(handlers))
+		require.Equal(t, 5, len(handlers))
 		require.Equal(t, registerPath, handlers[0].Path())
 	})
 
@@ -56,7 +60,7 @@ func TestNew(t *testing.T) {
 		handlers, err := svc.GetRESTHandlers(resolverMode)
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
-		require.Equal(t, 1, len(handlers))
+		require.Equal(t, 2, len(handlers))
 		require.Equal(t, resolveDIDEndpoint, handlers[0].Path())
 	})
 
@@ -229,7 +233,7 @@ func TestResolveDIDHandler(t *testing.T) {
 
 	t.Run("test error from bloc vdri read", func(t *testing.T) {
 		handler := getHandler(t, &mockvdr.MockVDR{
-			ReadFunc: func(didID string, opts ...vdr.ResolveOpts) (doc *did.Doc, err error) {
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
 				return nil, fmt.Errorf("read error")
 			}}, nil, resolveDIDEndpoint)
 
@@ -241,8 +245,8 @@ func TestResolveDIDHandler(t *testing.T) {
 
 	t.Run("test success", func(t *testing.T) {
 		handler := getHandler(t, &mockvdr.MockVDR{
-			ReadFunc: func(didID string, opts ...vdr.ResolveOpts) (doc *did.Doc, err error) {
-				return &did.Doc{ID: "didID", Context: []string{"context"}}, nil
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: "didID", Context: []string{"context"}}}, nil
 			}}, nil, resolveDIDEndpoint)
 
 		body, status, err := handleRequest(handler, resolveDIDEndpoint+"?did=123", nil)
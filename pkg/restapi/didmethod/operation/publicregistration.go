@@ -0,0 +1,392 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/bits"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+const (
+	registerChallengePath = registerPath + "/challenge"
+
+	challengeHeader    = "X-Registration-Challenge"
+	nonceHeader        = "X-Registration-Nonce"
+	captchaTokenHeader = "X-Captcha-Token"
+
+	challengeRandomLength = 16
+	challengeExpiryLength = 8
+
+	defaultChallengeTTL    = 5 * time.Minute
+	defaultRateLimitWindow = time.Hour
+
+	// the difficulty is a number of leading zero bits of a SHA-256 hash
+	minDifficulty = 1
+	maxDifficulty = sha256.Size * 8
+)
+
+// Public registration metrics, exposed by the metrics endpoint of the service.
+const (
+	// PublicRegistrationChallenges counts the proof-of-work challenges issued
+	PublicRegistrationChallenges = "public_registration_challenges_total"
+	// PublicRegistrations counts the public registration requests, by outcome
+	PublicRegistrations = "public_registrations_total"
+
+	// LabelOutcome is the label holding the outcome of a public registration request
+	LabelOutcome = "outcome"
+
+	outcomeAccepted       = "accepted"
+	outcomeRateLimited    = "rate_limited"
+	outcomeInvalidProof   = "invalid_proof"
+	outcomeInvalidCaptcha = "invalid_captcha"
+)
+
+// PublicRegistrationConfig configures anonymous DID registration for community networks.
+// Registrants must either solve a proof-of-work challenge issued by the service or present
+// a CAPTCHA token accepted by the TokenVerifier, and are subject to a per client rate limit.
+type PublicRegistrationConfig struct {
+	// ChallengeSecret is the HMAC key used to sign proof-of-work challenges
+	ChallengeSecret []byte
+	// Difficulty is the number of leading zero bits required in the proof-of-work hash
+	Difficulty int
+	// ChallengeTTL is how long an issued challenge remains valid (default 5 minutes)
+	ChallengeTTL time.Duration
+	// TokenVerifier verifies CAPTCHA tokens. Optional, if nil only proof-of-work is accepted.
+	TokenVerifier TokenVerifier
+	// RateLimit is the maximum number of registrations per client within RateLimitWindow. Zero disables the limit.
+	RateLimit int
+	// RateLimitWindow is the rate limit window (default one hour)
+	RateLimitWindow time.Duration
+}
+
+// TokenVerifier verifies CAPTCHA tokens presented by anonymous registrants
+type TokenVerifier interface {
+	VerifyToken(token, remoteIP string) error
+}
+
+// ChallengeResponse is returned by the registration challenge endpoint
+type ChallengeResponse struct {
+	Challenge  string `json:"challenge"`
+	Difficulty int    `json:"difficulty"`
+	ExpiresAt  int64  `json:"expiresAt"`
+}
+
+type publicRegistration struct {
+	config         *PublicRegistrationConfig
+	limiter        *registrationLimiter
+	usedChallenges map[string]time.Time
+	mutex          sync.Mutex
+	metrics        metrics.Provider
+	now            func() time.Time
+}
+
+func newPublicRegistration(config *PublicRegistrationConfig, metricsProvider metrics.Provider) *publicRegistration {
+	if config.ChallengeTTL == 0 {
+		config.ChallengeTTL = defaultChallengeTTL
+	}
+
+	if config.RateLimitWindow == 0 {
+		config.RateLimitWindow = defaultRateLimitWindow
+	}
+
+	return &publicRegistration{
+		config:         config,
+		limiter:        newRegistrationLimiter(config.RateLimit, config.RateLimitWindow),
+		usedChallenges: map[string]time.Time{},
+		metrics:        metricsProvider,
+		now:            time.Now,
+	}
+}
+
+// validate checks that the proof-of-work challenges can be solved, and are not solved without work
+func (p *publicRegistration) validate() error {
+	if p.config.Difficulty < minDifficulty || p.config.Difficulty > maxDifficulty {
+		return fmt.Errorf("public registration difficulty must be between %d and %d: %d", minDifficulty,
+			maxDifficulty, p.config.Difficulty)
+	}
+
+	return nil
+}
+
+func (p *publicRegistration) newChallenge() (*ChallengeResponse, error) {
+	payload := make([]byte, challengeExpiryLength+challengeRandomLength)
+
+	expiresAt := p.now().Add(p.config.ChallengeTTL).Unix()
+	binary.BigEndian.PutUint64(payload, uint64(expiresAt))
+
+	if _, err := rand.Read(payload[challengeExpiryLength:]); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	p.metrics.IncrementCounter(PublicRegistrationChallenges, nil)
+
+	return &ChallengeResponse{
+		Challenge: base64.RawURLEncoding.EncodeToString(payload) + "." +
+			base64.RawURLEncoding.EncodeToString(p.sign(payload)),
+		Difficulty: p.config.Difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (p *publicRegistration) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.config.ChallengeSecret)
+	mac.Write(payload) //nolint: errcheck
+
+	return mac.Sum(nil)
+}
+
+// admit checks whether a registration request may proceed, returning the http status to reject it with otherwise
+func (p *publicRegistration) admit(req *http.Request) (int, error) {
+	remoteIP := clientIP(req)
+
+	if !p.limiter.allow(remoteIP, p.now()) {
+		p.record(outcomeRateLimited)
+
+		return http.StatusTooManyRequests, errors.New("registration rate limit exceeded")
+	}
+
+	if token := req.Header.Get(captchaTokenHeader); token != "" && p.config.TokenVerifier != nil {
+		if err := p.config.TokenVerifier.VerifyToken(token, remoteIP); err != nil {
+			p.record(outcomeInvalidCaptcha)
+
+			return http.StatusForbidden, fmt.Errorf("invalid captcha token: %w", err)
+		}
+
+		p.record(outcomeAccepted)
+
+		return http.StatusOK, nil
+	}
+
+	if err := p.verifyProofOfWork(req.Header.Get(challengeHeader), req.Header.Get(nonceHeader)); err != nil {
+		p.record(outcomeInvalidProof)
+
+		return http.StatusForbidden, fmt.Errorf("invalid proof of work: %w", err)
+	}
+
+	p.record(outcomeAccepted)
+
+	return http.StatusOK, nil
+}
+
+func (p *publicRegistration) verifyProofOfWork(challenge, nonce string) error {
+	if challenge == "" || nonce == "" {
+		return fmt.Errorf("%s and %s headers are required", challengeHeader, nonceHeader)
+	}
+
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 2 { //nolint: gomnd
+		return errors.New("malformed challenge")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) != challengeExpiryLength+challengeRandomLength {
+		return errors.New("malformed challenge")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return errors.New("challenge was not issued by this service")
+	}
+
+	now := p.now()
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload)), 0)
+	if !now.Before(expiresAt) {
+		return errors.New("challenge expired")
+	}
+
+	if leadingZeroBits(sha256.Sum256([]byte(challenge+nonce))) < p.config.Difficulty {
+		return errors.New("nonce does not satisfy the challenge difficulty")
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for c, expiry := range p.usedChallenges {
+		if !now.Before(expiry) {
+			delete(p.usedChallenges, c)
+		}
+	}
+
+	if _, ok := p.usedChallenges[challenge]; ok {
+		return errors.New("challenge already used")
+	}
+
+	p.usedChallenges[challenge] = expiresAt
+
+	return nil
+}
+
+func (p *publicRegistration) record(outcome string) {
+	p.metrics.IncrementCounter(PublicRegistrations, map[string]string{LabelOutcome: outcome})
+}
+
+func leadingZeroBits(hash [sha256.Size]byte) int {
+	n := 0
+
+	for _, b := range hash {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+
+		n += 8
+	}
+
+	return n
+}
+
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
+
+// registrationLimiter is a fixed window limiter keyed by client
+type registrationLimiter struct {
+	limit   int
+	window  time.Duration
+	mutex   sync.Mutex
+	clients map[string]*limiterWindow
+}
+
+type limiterWindow struct {
+	start time.Time
+	count int
+}
+
+func newRegistrationLimiter(limit int, window time.Duration) *registrationLimiter {
+	return &registrationLimiter{limit: limit, window: window, clients: map[string]*limiterWindow{}}
+}
+
+func (l *registrationLimiter) allow(client string, now time.Time) bool {
+	if l.limit <= 0 {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for c, w := range l.clients {
+		if now.Sub(w.start) >= l.window {
+			delete(l.clients, c)
+		}
+	}
+
+	w, ok := l.clients[client]
+	if !ok {
+		w = &limiterWindow{start: now}
+		l.clients[client] = w
+	}
+
+	if w.count >= l.limit {
+		return false
+	}
+
+	w.count++
+
+	return true
+}
+
+// SiteVerifyTokenVerifier verifies CAPTCHA tokens using a reCAPTCHA/hCaptcha compatible siteverify endpoint
+type SiteVerifyTokenVerifier struct {
+	verifyURL  string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewSiteVerifyTokenVerifier returns a new SiteVerifyTokenVerifier
+func NewSiteVerifyTokenVerifier(verifyURL, secret string, httpClient *http.Client) *SiteVerifyTokenVerifier {
+	return &SiteVerifyTokenVerifier{verifyURL: verifyURL, secret: secret, httpClient: httpClient}
+}
+
+// VerifyToken verifies the token with the siteverify endpoint
+func (v *SiteVerifyTokenVerifier) VerifyToken(token, remoteIP string) error {
+	resp, err := v.httpClient.PostForm(v.verifyURL, url.Values{
+		"secret":   {v.secret},
+		"response": {token},
+		"remoteip": {remoteIP},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send siteverify request: %w", err)
+	}
+
+	defer closeResponseBody(resp)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read siteverify response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("siteverify request failed: status '%d' body %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to parse siteverify response: %w", err)
+	}
+
+	if !result.Success {
+		return fmt.Errorf("token rejected: %s", strings.Join(result.ErrorCodes, ", "))
+	}
+
+	return nil
+}
+
+func closeResponseBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Errorf("Failed to close response body: %v", err)
+	}
+}
+
+func (o *Operation) registerChallengeHandler(rw http.ResponseWriter, _ *http.Request) {
+	challenge, err := o.publicRegistration.newChallenge()
+	if err != nil {
+		o.writeErrorResponse(rw, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	o.writeResponse(rw, challenge)
+}
+
+func (o *Operation) publicRegisterDIDHandler(rw http.ResponseWriter, req *http.Request) {
+	status, err := o.publicRegistration.admit(req)
+	if err != nil {
+		log.Warnf("rejected public registration from %s: %s", clientIP(req), err)
+
+		o.writeErrorResponse(rw, status, err.Error())
+
+		return
+	}
+
+	o.registerDIDHandler(rw, req)
+}
@@ -0,0 +1,345 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/didbloc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+const testDifficulty = 4
+
+func TestPublicRegistrarMode(t *testing.T) {
+	t.Run("test public registrar mode", func(t *testing.T) {
+		svc := New(&Config{PublicRegistration: &PublicRegistrationConfig{ChallengeSecret: []byte("secret"),
+			Difficulty: testDifficulty}})
+		require.NotNil(t, svc)
+		handlers, err := svc.GetRESTHandlers(publicRegistrarMode)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(handlers))
+		require.Equal(t, registerChallengePath, handlers[0].Path())
+		require.Equal(t, registerPath, handlers[1].Path())
+	})
+
+	t.Run("test invalid difficulty", func(t *testing.T) {
+		for _, difficulty := range []int{0, 257} {
+			svc := New(&Config{PublicRegistration: &PublicRegistrationConfig{ChallengeSecret: []byte("secret"),
+				Difficulty: difficulty}})
+			_, err := svc.GetRESTHandlers(publicRegistrarMode)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "public registration difficulty must be between 1 and 256")
+		}
+	})
+
+	t.Run("test public registrar mode without config", func(t *testing.T) {
+		svc := New(&Config{})
+		require.NotNil(t, svc)
+		_, err := svc.GetRESTHandlers(publicRegistrarMode)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public registration config is required")
+	})
+}
+
+func TestPublicRegisterDIDHandler(t *testing.T) {
+	t.Run("test success with proof of work", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{})
+
+		challenge := getChallenge(t, svc)
+		require.Equal(t, testDifficulty, challenge.Difficulty)
+
+		body, status := publicRegister(t, svc, map[string]string{
+			challengeHeader: challenge.Challenge,
+			nonceHeader:     solveChallenge(challenge.Challenge, challenge.Difficulty),
+		})
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, body.String(), "did:trustbloc:test")
+
+		stats := getStats(t, svc)
+		require.Contains(t, stats, PublicRegistrationChallenges+" 1\n")
+		require.Contains(t, stats, PublicRegistrations+`{outcome="accepted"} 1`)
+	})
+
+	t.Run("test missing proof of work", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{})
+
+		body, status := publicRegister(t, svc, nil)
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "headers are required")
+		require.Contains(t, getStats(t, svc), PublicRegistrations+`{outcome="invalid_proof"} 1`)
+	})
+
+	t.Run("test malformed challenge", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{})
+
+		body, status := publicRegister(t, svc, map[string]string{challengeHeader: "abc", nonceHeader: "1"})
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "malformed challenge")
+	})
+
+	t.Run("test challenge signed with another secret", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{})
+		other := newPublicRegistrationOperation(&PublicRegistrationConfig{ChallengeSecret: []byte("other")})
+
+		challenge := getChallenge(t, other)
+
+		body, status := publicRegister(t, svc, map[string]string{
+			challengeHeader: challenge.Challenge,
+			nonceHeader:     solveChallenge(challenge.Challenge, challenge.Difficulty),
+		})
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "challenge was not issued by this service")
+	})
+
+	t.Run("test expired challenge", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{ChallengeTTL: time.Minute})
+
+		challenge := getChallenge(t, svc)
+
+		svc.publicRegistration.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		body, status := publicRegister(t, svc, map[string]string{
+			challengeHeader: challenge.Challenge,
+			nonceHeader:     solveChallenge(challenge.Challenge, challenge.Difficulty),
+		})
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "challenge expired")
+	})
+
+	t.Run("test nonce does not satisfy difficulty", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{Difficulty: 256})
+
+		challenge := getChallenge(t, svc)
+
+		body, status := publicRegister(t, svc, map[string]string{
+			challengeHeader: challenge.Challenge,
+			nonceHeader:     "1",
+		})
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "nonce does not satisfy the challenge difficulty")
+	})
+
+	t.Run("test challenge replay", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{})
+
+		challenge := getChallenge(t, svc)
+		headers := map[string]string{
+			challengeHeader: challenge.Challenge,
+			nonceHeader:     solveChallenge(challenge.Challenge, challenge.Difficulty),
+		}
+
+		_, status := publicRegister(t, svc, headers)
+		require.Equal(t, http.StatusOK, status)
+
+		body, status := publicRegister(t, svc, headers)
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "challenge already used")
+	})
+
+	t.Run("test rate limit", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{RateLimit: 1})
+
+		challenge := getChallenge(t, svc)
+
+		_, status := publicRegister(t, svc, map[string]string{
+			challengeHeader: challenge.Challenge,
+			nonceHeader:     solveChallenge(challenge.Challenge, challenge.Difficulty),
+		})
+		require.Equal(t, http.StatusOK, status)
+
+		body, status := publicRegister(t, svc, nil)
+		require.Equal(t, http.StatusTooManyRequests, status)
+		require.Contains(t, body.String(), "rate limit exceeded")
+		require.Contains(t, getStats(t, svc), PublicRegistrations+`{outcome="rate_limited"} 1`)
+
+		svc.publicRegistration.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		_, status = publicRegister(t, svc, nil)
+		require.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("test captcha token", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{TokenVerifier: &mockTokenVerifier{}})
+
+		_, status := publicRegister(t, svc, map[string]string{captchaTokenHeader: "token"})
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, getStats(t, svc), PublicRegistrations+`{outcome="accepted"} 1`)
+	})
+
+	t.Run("test invalid captcha token", func(t *testing.T) {
+		svc := newPublicRegistrationOperation(&PublicRegistrationConfig{
+			TokenVerifier: &mockTokenVerifier{err: fmt.Errorf("invalid-input-response")}})
+
+		body, status := publicRegister(t, svc, map[string]string{captchaTokenHeader: "token"})
+		require.Equal(t, http.StatusForbidden, status)
+		require.Contains(t, body.String(), "invalid captcha token")
+		require.Contains(t, getStats(t, svc), PublicRegistrations+`{outcome="invalid_captcha"} 1`)
+	})
+}
+
+func TestSiteVerifyTokenVerifier(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, "secret", r.PostForm.Get("secret"))
+			require.Equal(t, "token", r.PostForm.Get("response"))
+			require.Equal(t, "127.0.0.1", r.PostForm.Get("remoteip"))
+
+			fmt.Fprint(w, `{"success": true}`)
+		}))
+		defer serv.Close()
+
+		v := NewSiteVerifyTokenVerifier(serv.URL, "secret", serv.Client())
+		require.NoError(t, v.VerifyToken("token", "127.0.0.1"))
+	})
+
+	t.Run("test token rejected", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"success": false, "error-codes": ["invalid-input-response"]}`)
+		}))
+		defer serv.Close()
+
+		err := NewSiteVerifyTokenVerifier(serv.URL, "secret", serv.Client()).VerifyToken("token", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "token rejected: invalid-input-response")
+	})
+
+	t.Run("test unexpected status", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer serv.Close()
+
+		err := NewSiteVerifyTokenVerifier(serv.URL, "secret", serv.Client()).VerifyToken("token", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "siteverify request failed")
+	})
+
+	t.Run("test invalid response", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{`)
+		}))
+		defer serv.Close()
+
+		err := NewSiteVerifyTokenVerifier(serv.URL, "secret", serv.Client()).VerifyToken("token", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse siteverify response")
+	})
+
+	t.Run("test request error", func(t *testing.T) {
+		err := NewSiteVerifyTokenVerifier("http://localhost:0", "secret", http.DefaultClient).VerifyToken("token", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send siteverify request")
+	})
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	var hash [sha256.Size]byte
+
+	require.Equal(t, 256, leadingZeroBits(hash))
+
+	hash[1] = 0x10
+	require.Equal(t, 11, leadingZeroBits(hash))
+}
+
+type mockTokenVerifier struct {
+	err error
+}
+
+func (m *mockTokenVerifier) VerifyToken(token, remoteIP string) error {
+	return m.err
+}
+
+func newPublicRegistrationOperation(config *PublicRegistrationConfig) *Operation {
+	if config.ChallengeSecret == nil {
+		config.ChallengeSecret = []byte("secret")
+	}
+
+	if config.Difficulty == 0 {
+		config.Difficulty = testDifficulty
+	}
+
+	svc := New(&Config{PublicRegistration: config, MetricsProvider: metrics.NewRegistry()})
+	svc.didBlocClient = &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did:trustbloc:test"}}
+
+	return svc
+}
+
+func publicRegistrationHandler(t *testing.T, op *Operation, path, method string) Handler {
+	handlers, err := op.GetRESTHandlers(publicRegistrarMode)
+	require.NoError(t, err)
+
+	for _, h := range handlers {
+		if h.Path() == path && h.Method() == method {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+func getChallenge(t *testing.T, op *Operation) *ChallengeResponse {
+	body, status, err := handleRequest(publicRegistrationHandler(t, op, registerChallengePath, http.MethodGet),
+		registerChallengePath, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, status)
+
+	challenge := &ChallengeResponse{}
+	require.NoError(t, json.Unmarshal(body.Bytes(), challenge))
+
+	return challenge
+}
+
+func getStats(t *testing.T, op *Operation) string {
+	stats := &bytes.Buffer{}
+	require.NoError(t, op.publicRegistration.metrics.(*metrics.Registry).WriteText(stats))
+
+	return stats.String()
+}
+
+func publicRegister(t *testing.T, op *Operation, headers map[string]string) (*bytes.Buffer, int) {
+	reqBytes, err := json.Marshal(RegisterDIDRequest{JobID: "1", DIDDocument: DIDDocument{
+		PublicKey: []*PublicKey{{ID: "key1", Type: "type", Value: base64.StdEncoding.EncodeToString([]byte("value"))}}}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, registerPath, bytes.NewBuffer(reqBytes))
+	req.RemoteAddr = "127.0.0.1:12345"
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rr := httptest.NewRecorder()
+
+	publicRegistrationHandler(t, op, registerPath, http.MethodPost).Handle().ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code
+}
+
+func solveChallenge(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+
+		if leadingZeroBits(sha256.Sum256([]byte(challenge+nonce))) >= difficulty {
+			return nonce
+		}
+	}
+}
@@ -0,0 +1,743 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	log "github.com/sirupsen/logrus"
+	gojose "github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/recovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/correlation"
+)
+
+// Universal Registrar driver endpoints, see https://github.com/decentralized-identity/universal-registrar
+const (
+	createPath     = registerBasePath + "/create"
+	updatePath     = registerBasePath + "/update"
+	recoverPath    = registerBasePath + "/recover"
+	deactivatePath = registerBasePath + "/deactivate"
+
+	authorizationHeader     = "Authorization"
+	deactivationTokenHeader = "X-Deactivation-Token"
+	bearerPrefix            = "Bearer "
+)
+
+func (o *Operation) createDIDHandler(rw http.ResponseWriter, req *http.Request) {
+	data := CreateDIDRequest{}
+
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		o.writeErrorResponse(rw, http.StatusBadRequest, fmt.Sprintf(invalidRequestErrMsg+": %s", err.Error()))
+
+		return
+	}
+
+	response := RegisterResponse{JobID: data.JobID}
+
+	callbackURL, err := o.callbackURL(data.Options)
+	if err != nil {
+		o.writeFailureResponse(rw, response, err)
+
+		return
+	}
+
+	correlationID := correlation.ID(req.Context())
+
+	if o.async != nil {
+		o.submitOperation(rw, response, OperationTypeCreate, callbackURL, func() (string, *Secret, error) {
+			didDoc, secret, createErr := o.createDID(&data, correlationID)
+			if createErr != nil {
+				return "", nil, createErr
+			}
+
+			return didDoc.ID, secret, nil
+		})
+
+		return
+	}
+
+	watch := o.watchOperation(&WebhookNotification{JobID: data.JobID, Type: OperationTypeCreate}, callbackURL)
+
+	didDoc, secret, err := o.createDID(&data, correlationID)
+	if err != nil {
+		response.DIDState = DIDState{Reason: err.Error(), State: RegistrationStateFailure}
+	} else {
+		response.DIDState = DIDState{Identifier: didDoc.ID, State: RegistrationStateFinished, Secret: *secret}
+
+		watch(didDoc.ID)
+	}
+
+	o.writeResponse(rw, response)
+}
+
+// createDID creates the DID, with the recovery and update keys of the secret or else with generated keys,
+// and returns the secret of the DID: the verification keys and the generated keys
+func (o *Operation) createDID(data *CreateDIDRequest, correlationID string) (*did.Doc, *Secret, error) {
+	options, err := didDocumentOptions(&data.DIDDocument)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret := &Secret{}
+
+	recoveryKey, err := secretPublicKey(&data.Secret, SecretPurposeRecovery, !options.recovery, secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if recoveryKey != nil {
+		options.opts = append(options.opts, create.WithRecoveryPublicKey(recoveryKey))
+	}
+
+	updateKey, err := secretPublicKey(&data.Secret, SecretPurposeUpdate, !options.update, secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if updateKey != nil {
+		options.opts = append(options.opts, create.WithUpdatePublicKey(updateKey))
+	}
+
+	if correlationID != "" {
+		options.opts = append(options.opts, create.WithRequestHeader(correlation.Header, correlationID))
+	}
+
+	didDoc, err := o.didBlocClient.CreateDID(o.blocDomain, options.opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create did doc : %w", err)
+	}
+
+	secret.Keys = append(createKeys(options.keys, didDoc.ID), secret.Keys...)
+
+	return didDoc, secret, nil
+}
+
+func (o *Operation) updateDIDHandler(rw http.ResponseWriter, req *http.Request) {
+	data := UpdateDIDRequest{}
+
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		o.writeFailureResponse(rw, RegisterResponse{}, invalidRequest(err))
+
+		return
+	}
+
+	response := RegisterResponse{JobID: data.JobID, DIDState: DIDState{Identifier: data.Identifier}}
+
+	callbackURL, err := o.callbackURL(data.Options)
+	if err != nil {
+		o.writeFailureResponse(rw, response, err)
+
+		return
+	}
+
+	correlationID := correlation.ID(req.Context())
+	authorized := o.keyStoreAuthorized(req)
+
+	if o.async != nil {
+		o.submitOperation(rw, response, OperationTypeUpdate, callbackURL, func() (string, *Secret, error) {
+			secret, submitErr := o.updateDID(&data, authorized, correlationID)
+
+			return data.Identifier, secret, submitErr
+		})
+
+		return
+	}
+
+	watch := o.watchOperation(&WebhookNotification{JobID: data.JobID, Type: OperationTypeUpdate,
+		Identifier: data.Identifier}, callbackURL)
+
+	secret, err := o.updateDID(&data, authorized, correlationID)
+	if err != nil {
+		o.writeFailureResponse(rw, response, err)
+
+		return
+	}
+
+	response.DIDState = DIDState{Identifier: data.Identifier, State: RegistrationStateFinished, Secret: *secret}
+
+	watch(data.Identifier)
+
+	o.writeResponse(rw, response)
+}
+
+// updateDID updates the DID, signed with the update key of the secret or else of the key store, which requires the
+// request to be authorized, and returns the secret of the DID: the next update key, if it was generated and not kept
+// by the key store
+func (o *Operation) updateDID(data *UpdateDIDRequest, authorized bool, correlationID string) (*Secret, error) {
+	if data.Identifier == "" {
+		return nil, invalidRequest(errors.New("identifier is required"))
+	}
+
+	opts, err := didDocumentOperations(data.DIDDocumentOperation, data.DIDDocument)
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	signingOpts, err := o.signingKey(data.Identifier, &data.Secret, SecretPurposeUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if signingOpts.stored && !authorized {
+		return nil, &operationError{status: http.StatusForbidden, code: ErrorCodeUnauthorized,
+			err: errors.New("update signed with the key store requires an authorized request")}
+	}
+
+	if signingOpts.keyID != "" {
+		opts = append(opts, update.WithSigningKeyID(signingOpts.keyID))
+	}
+
+	secret := &Secret{}
+
+	nextUpdateKey, err := secretPublicKey(&data.Secret, SecretPurposeNextUpdate, true, secret)
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	opts = append(opts, update.WithSigningKey(signingOpts.key), update.WithNextUpdatePublicKey(nextUpdateKey))
+
+	if correlationID != "" {
+		opts = append(opts, update.WithRequestHeader(correlation.Header, correlationID))
+	}
+
+	if err := o.didBlocClient.UpdateDID(data.Identifier, o.blocDomain, opts...); err != nil {
+		return nil, fmt.Errorf("failed to update did doc : %w", err)
+	}
+
+	if !signingOpts.stored {
+		return secret, nil
+	}
+
+	nextKey := secretKey(&data.Secret, SecretPurposeNextUpdate)
+	if nextKey == nil {
+		nextKey = &secret.Keys[0]
+	}
+
+	if err := o.keyStore.Put(data.Identifier, SecretPurposeUpdate, nextKey.PrivateKeyJWK); err != nil {
+		// the DID is updated already, so the next update key is returned rather than lost
+		log.Errorf("failed to store next update key of %s: %s", data.Identifier, err.Error())
+
+		return secret, nil
+	}
+
+	return &Secret{}, nil
+}
+
+// didDocumentOperations converts the did document operations of an update request to update options
+func didDocumentOperations(operations []string, didDocuments []*DIDDocument) ([]update.Option, error) {
+	if len(operations) == 0 && len(didDocuments) == 1 {
+		operations = []string{DIDDocumentOperationAdd}
+	}
+
+	if len(operations) != len(didDocuments) {
+		return nil, errors.New("didDocumentOperation and didDocument must have the same length")
+	}
+
+	var opts []update.Option
+
+	for i, op := range operations {
+		didDocument := didDocuments[i]
+		if didDocument == nil {
+			continue
+		}
+
+		switch op {
+		case DIDDocumentOperationAdd:
+			for _, v := range didDocument.PublicKey {
+				keyValue, err := decodePublicKey(v)
+				if err != nil {
+					return nil, err
+				}
+
+				opts = append(opts, update.WithAddPublicKey(&doc.PublicKey{ID: v.ID, Type: v.Type, Value: keyValue,
+					Encoding: v.Encoding, Purposes: v.Purposes, KeyType: v.KeyType}))
+			}
+
+			for _, service := range didDocument.Service {
+				opts = append(opts, update.WithAddService(toService(service)))
+			}
+		case DIDDocumentOperationRemove:
+			for _, v := range didDocument.PublicKey {
+				opts = append(opts, update.WithRemovePublicKey(v.ID))
+			}
+
+			for _, service := range didDocument.Service {
+				opts = append(opts, update.WithRemoveService(service.ID))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported didDocumentOperation: %s", op)
+		}
+	}
+
+	return opts, nil
+}
+
+func (o *Operation) recoverDIDHandler(rw http.ResponseWriter, req *http.Request) {
+	data := RecoverDIDRequest{}
+
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		o.writeFailureResponse(rw, RegisterResponse{}, invalidRequest(err))
+
+		return
+	}
+
+	response := RegisterResponse{JobID: data.JobID, DIDState: DIDState{Identifier: data.Identifier}}
+
+	callbackURL, err := o.callbackURL(data.Options)
+	if err != nil {
+		o.writeFailureResponse(rw, response, err)
+
+		return
+	}
+
+	correlationID := correlation.ID(req.Context())
+	authorized := o.keyStoreAuthorized(req)
+
+	if o.async != nil {
+		o.submitOperation(rw, response, OperationTypeRecover, callbackURL, func() (string, *Secret, error) {
+			secret, submitErr := o.recoverDID(&data, authorized, correlationID)
+
+			return data.Identifier, secret, submitErr
+		})
+
+		return
+	}
+
+	watch := o.watchOperation(&WebhookNotification{JobID: data.JobID, Type: OperationTypeRecover,
+		Identifier: data.Identifier}, callbackURL)
+
+	secret, err := o.recoverDID(&data, authorized, correlationID)
+	if err != nil {
+		o.writeFailureResponse(rw, response, err)
+
+		return
+	}
+
+	response.DIDState = DIDState{Identifier: data.Identifier, State: RegistrationStateFinished, Secret: *secret}
+
+	watch(data.Identifier)
+
+	o.writeResponse(rw, response)
+}
+
+// recoverDID replaces the content of the DID document, signed with the recovery key of the secret or else of the
+// key store, which requires the request to be authorized, and returns the secret of the DID: the verification keys and
+// the generated keys not kept by the key store
+func (o *Operation) recoverDID(data *RecoverDIDRequest, authorized bool, correlationID string) (*Secret, error) {
+	if data.Identifier == "" {
+		return nil, invalidRequest(errors.New("identifier is required"))
+	}
+
+	options, err := recoverDocumentOptions(&data.DIDDocument)
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	signingOpts, err := o.signingKey(data.Identifier, &data.Secret, SecretPurposeRecovery)
+	if err != nil {
+		return nil, err
+	}
+
+	if signingOpts.stored && !authorized {
+		return nil, &operationError{status: http.StatusForbidden, code: ErrorCodeUnauthorized,
+			err: errors.New("recovery signed with the key store requires an authorized request")}
+	}
+
+	options.opts = append(options.opts, recovery.WithSigningKey(signingOpts.key))
+
+	if signingOpts.keyID != "" {
+		options.opts = append(options.opts, recovery.WithSigningKeyID(signingOpts.keyID))
+	}
+
+	secret := &Secret{}
+
+	nextRecoveryKey, err := secretPublicKey(&data.Secret, SecretPurposeNextRecovery, !options.recovery, secret)
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	if nextRecoveryKey != nil {
+		options.opts = append(options.opts, recovery.WithNextRecoveryPublicKey(nextRecoveryKey))
+	}
+
+	nextUpdateKey, err := secretPublicKey(&data.Secret, SecretPurposeNextUpdate, !options.update, secret)
+	if err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	if nextUpdateKey != nil {
+		options.opts = append(options.opts, recovery.WithNextUpdatePublicKey(nextUpdateKey))
+	}
+
+	if correlationID != "" {
+		options.opts = append(options.opts, recovery.WithRequestHeader(correlation.Header, correlationID))
+	}
+
+	if err := o.didBlocClient.RecoverDID(data.Identifier, o.blocDomain, options.opts...); err != nil {
+		return nil, fmt.Errorf("failed to recover did doc : %w", err)
+	}
+
+	if signingOpts.stored {
+		secret.Keys = o.storeNextKeys(data.Identifier, &data.Secret, secret.Keys)
+	}
+
+	secret.Keys = append(createKeys(options.keys, data.Identifier), secret.Keys...)
+
+	return secret, nil
+}
+
+// recoverOptions are the recover options of a requested DID document
+type recoverOptions struct {
+	opts []recovery.Option
+	// keys are the values of the verification keys by ID
+	keys     map[string][]byte
+	recovery bool
+	update   bool
+}
+
+// recoverDocumentOptions converts the public keys and services of a requested DID document to recover options,
+// where the recovery and update keys are the next recovery and update keys
+func recoverDocumentOptions(didDocument *DIDDocument) (*recoverOptions, error) {
+	options := &recoverOptions{keys: make(map[string][]byte)}
+
+	for _, v := range didDocument.PublicKey {
+		keyValue, err := decodePublicKey(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.Recovery || v.Update {
+			k, err := getKey(v.KeyType, keyValue)
+			if err != nil {
+				return nil, err
+			}
+
+			if v.Recovery {
+				options.opts = append(options.opts, recovery.WithNextRecoveryPublicKey(k))
+				options.recovery = true
+			} else {
+				options.opts = append(options.opts, recovery.WithNextUpdatePublicKey(k))
+				options.update = true
+			}
+
+			continue
+		}
+
+		options.opts = append(options.opts, recovery.WithPublicKey(&doc.PublicKey{ID: v.ID, Type: v.Type,
+			Value: keyValue, Encoding: v.Encoding, Purposes: v.Purposes, KeyType: v.KeyType}))
+
+		options.keys[v.ID] = keyValue
+	}
+
+	for _, service := range didDocument.Service {
+		options.opts = append(options.opts, recovery.WithService(toService(service)))
+	}
+
+	return options, nil
+}
+
+// storeNextKeys stores the next recovery and update keys of the secret, or else generated, as the recovery and
+// update keys of the DID in the key store, and returns the generated keys which could not be stored
+func (o *Operation) storeNextKeys(didID string, secret *Secret, generated []Key) []Key {
+	var unstored []Key
+
+	for _, purpose := range []string{SecretPurposeRecovery, SecretPurposeUpdate} {
+		nextPurpose := SecretPurposeNextRecovery
+		if purpose == SecretPurposeUpdate {
+			nextPurpose = SecretPurposeNextUpdate
+		}
+
+		key := secretKey(secret, nextPurpose)
+		if key == nil {
+			key = secretKey(&Secret{Keys: generated}, purpose)
+		}
+
+		if key == nil {
+			// the next key is a public key of the DID document, so the stored key can't sign anymore
+			log.Warnf("%s key of %s is not managed by the key store anymore", purpose, didID)
+
+			continue
+		}
+
+		if err := o.keyStore.Put(didID, purpose, key.PrivateKeyJWK); err != nil {
+			// the DID is recovered already, so the next key is returned rather than lost
+			log.Errorf("failed to store next %s key of %s: %s", purpose, didID, err.Error())
+
+			if secretKey(secret, nextPurpose) == nil {
+				unstored = append(unstored, *key)
+			}
+		}
+	}
+
+	return unstored
+}
+
+func (o *Operation) deactivateDIDHandler(rw http.ResponseWriter, req *http.Request) {
+	data := DeactivateDIDRequest{}
+
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		o.writeFailureResponse(rw, RegisterResponse{}, invalidRequest(err))
+
+		return
+	}
+
+	response := RegisterResponse{JobID: data.JobID}
+
+	authorized, err := o.authorizeDeactivation(req)
+	if err == nil {
+		err = o.deactivateDID(&data, authorized, correlation.ID(req.Context()))
+	}
+
+	auditDeactivation(req, data.Identifier, authorized, err)
+
+	if err != nil {
+		response.DIDState.Identifier = data.Identifier
+
+		o.writeFailureResponse(rw, response, err)
+
+		return
+	}
+
+	response.DIDState = DIDState{Identifier: data.Identifier, State: RegistrationStateFinished}
+
+	o.writeResponse(rw, response)
+}
+
+// deactivateDID deactivates the DID, signed with the recovery key of the secret or else of the key store, which
+// requires the request to be authorized
+func (o *Operation) deactivateDID(data *DeactivateDIDRequest, authorized bool, correlationID string) error {
+	if data.Identifier == "" {
+		return invalidRequest(errors.New("identifier is required"))
+	}
+
+	signingOpts, err := o.signingKey(data.Identifier, &data.Secret, SecretPurposeRecovery)
+	if err != nil {
+		return err
+	}
+
+	if signingOpts.stored && !authorized {
+		return &operationError{status: http.StatusForbidden, code: ErrorCodeUnauthorized,
+			err: errors.New("deactivation signed with the key store requires an authorized request")}
+	}
+
+	opts := []deactivate.Option{deactivate.WithSigningKey(signingOpts.key)}
+
+	if signingOpts.keyID != "" {
+		opts = append(opts, deactivate.WithSigningKeyID(signingOpts.keyID))
+	}
+
+	if correlationID != "" {
+		opts = append(opts, deactivate.WithRequestHeader(correlation.Header, correlationID))
+	}
+
+	if err := o.didBlocClient.DeactivateDID(data.Identifier, o.blocDomain, opts...); err != nil {
+		return fmt.Errorf("failed to deactivate did doc : %w", err)
+	}
+
+	return nil
+}
+
+// authorizeDeactivation checks the deactivation token of the request, if a deactivation token is configured, and
+// returns true if the request is authorized. The token is sent in the X-Deactivation-Token header, or else as bearer
+// token when the Authorization header isn't used to authenticate the client.
+func (o *Operation) authorizeDeactivation(req *http.Request) (bool, error) {
+	if o.deactivationToken == "" {
+		return false, nil
+	}
+
+	token := req.Header.Get(deactivationTokenHeader)
+	if token == "" {
+		token = strings.TrimPrefix(req.Header.Get(authorizationHeader), bearerPrefix)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(o.deactivationToken)) != 1 {
+		return false, &operationError{status: http.StatusUnauthorized, code: ErrorCodeUnauthorized,
+			err: errors.New("invalid or missing deactivation token")}
+	}
+
+	return true, nil
+}
+
+// keyStoreAuthorized returns true if the request holds the deactivation token, which authorizes the update and recover
+// requests signed with the keys of the key store as it authorizes the deactivate requests
+func (o *Operation) keyStoreAuthorized(req *http.Request) bool {
+	authorized, err := o.authorizeDeactivation(req)
+
+	return err == nil && authorized
+}
+
+// auditDeactivation logs who requested the deactivation of the DID, and its outcome
+func auditDeactivation(req *http.Request, didID string, authorized bool, err error) {
+	outcome := "succeeded"
+	if err != nil {
+		outcome = "failed: " + err.Error()
+	}
+
+	requester := clientIP(req)
+	if clientID := auth.ClientID(req.Context()); clientID != "" {
+		requester = fmt.Sprintf("client %q from %s", clientID, requester)
+	}
+
+	log.Infof("audit: deactivation of did %q requested by %s (authorized: %t, user agent: %q) %s",
+		didID, requester, authorized, req.UserAgent(), outcome)
+}
+
+// secretSigningKey is the private key signing an operation, and its key ID
+type secretSigningKey struct {
+	key   crypto.PrivateKey
+	keyID string
+	// stored is true if the key is held by the key store rather than by the secret
+	stored bool
+}
+
+// signingKey returns the private key with the given purpose of the secret, or else of the key store
+func (o *Operation) signingKey(didID string, secret *Secret, purpose string) (*secretSigningKey, error) {
+	key := secretKey(secret, purpose)
+	stored := false
+
+	if key == nil && o.keyStore != nil {
+		jwk, err := o.keyStore.Get(didID, purpose)
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return nil, fmt.Errorf("failed to get %s key from key store: %w", purpose, err)
+		}
+
+		if err == nil {
+			key = &Key{Purposes: []string{purpose}, PrivateKeyJWK: jwk}
+			stored = true
+		}
+	}
+
+	if key == nil {
+		return nil, invalidRequest(fmt.Errorf("secret %s key is required", purpose))
+	}
+
+	jwk := gojose.JSONWebKey{}
+	if err := jwk.UnmarshalJSON(key.PrivateKeyJWK); err != nil {
+		return nil, invalidRequest(fmt.Errorf("invalid secret %s key: %w", purpose, err))
+	}
+
+	if jwk.IsPublic() {
+		return nil, invalidRequest(fmt.Errorf("secret %s key is not a private key", purpose))
+	}
+
+	keyID := key.ID
+	if keyID == "" {
+		keyID = jwk.KeyID
+	}
+
+	return &secretSigningKey{key: jwk.Key, keyID: keyID, stored: stored}, nil
+}
+
+// secretPublicKey returns the public key of the key with the given purpose of the secret. If the secret has no such
+// key, a key is generated if required, and added to the generated secret with the purpose it has for the DID
+func secretPublicKey(secret *Secret, purpose string, required bool, generated *Secret) (crypto.PublicKey, error) {
+	key := secretKey(secret, purpose)
+	if key != nil {
+		jwk := gojose.JSONWebKey{}
+		if err := jwk.UnmarshalJSON(key.PrivateKeyJWK); err != nil {
+			return nil, fmt.Errorf("invalid secret %s key: %w", purpose, err)
+		}
+
+		return jwk.Public().Key, nil
+	}
+
+	if !required {
+		return nil, nil
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk, err := (&gojose.JSONWebKey{Key: privateKey}).MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	switch purpose {
+	case SecretPurposeNextUpdate:
+		purpose = SecretPurposeUpdate
+	case SecretPurposeNextRecovery:
+		purpose = SecretPurposeRecovery
+	}
+
+	generated.Keys = append(generated.Keys, Key{Purposes: []string{purpose}, PrivateKeyJWK: jwk})
+
+	return publicKey, nil
+}
+
+func secretKey(secret *Secret, purpose string) *Key {
+	for i := range secret.Keys {
+		for _, p := range secret.Keys[i].Purposes {
+			if p == purpose {
+				return &secret.Keys[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// operationError is an error of an operation request, with its HTTP status and error code
+type operationError struct {
+	status int
+	code   string
+	err    error
+}
+
+func (e *operationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *operationError) Unwrap() error {
+	return e.err
+}
+
+func invalidRequest(err error) error {
+	return &operationError{status: http.StatusBadRequest, code: ErrorCodeInvalidRequest,
+		err: fmt.Errorf(invalidRequestErrMsg+": %w", err)}
+}
+
+// errorStatus returns the status and error code of the error, which are those of a failed sidetree operation unless
+// it is an operation error
+func errorStatus(err error) (int, string) {
+	var opErr *operationError
+	if errors.As(err, &opErr) {
+		return opErr.status, opErr.code
+	}
+
+	return http.StatusInternalServerError, ErrorCodeOperationFailed
+}
+
+// writeFailureResponse writes the failed state of the operation, with the error code and status of the error
+func (o *Operation) writeFailureResponse(rw http.ResponseWriter, response RegisterResponse, err error) {
+	status, code := errorStatus(err)
+
+	response.DIDState.Reason = err.Error()
+	response.DIDState.ErrorCode = code
+	response.DIDState.State = RegistrationStateFailure
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	o.writeResponse(rw, response)
+}
@@ -0,0 +1,591 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	gojose "github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/didbloc"
+)
+
+func TestCreateDIDHandler(t *testing.T) {
+	t.Run("test error bad request", func(t *testing.T) {
+		handler := getHandler(t, nil, nil, createPath)
+
+		body, status, err := handleRequest(handler, createPath, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body.String(), "invalid request")
+	})
+
+	t.Run("test success with generated keys", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did1"}}, createPath)
+
+		req, err := json.Marshal(CreateDIDRequest{JobID: "1", DIDDocument: DIDDocument{
+			PublicKey: []*PublicKey{{ID: "key1", Type: "type",
+				Value: base64.StdEncoding.EncodeToString([]byte("value"))}}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, createPath, req)
+		require.Equal(t, "1", response.JobID)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Equal(t, "did1", response.DIDState.Identifier)
+		require.Len(t, response.DIDState.Secret.Keys, 3)
+		require.Equal(t, "did1#key1", response.DIDState.Secret.Keys[0].ID)
+		require.Equal(t, []string{SecretPurposeRecovery}, response.DIDState.Secret.Keys[1].Purposes)
+		require.Equal(t, []string{SecretPurposeUpdate}, response.DIDState.Secret.Keys[2].Purposes)
+
+		jwk := gojose.JSONWebKey{}
+		require.NoError(t, jwk.UnmarshalJSON(response.DIDState.Secret.Keys[1].PrivateKeyJWK))
+		require.False(t, jwk.IsPublic())
+	})
+
+	t.Run("test success with secret keys", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did1"}}, createPath)
+
+		req, err := json.Marshal(CreateDIDRequest{JobID: "1", Secret: Secret{Keys: []Key{
+			secretKeyJWK(t, "", SecretPurposeRecovery), secretKeyJWK(t, "", SecretPurposeUpdate)}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, createPath, req)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Empty(t, response.DIDState.Secret.Keys)
+	})
+
+	t.Run("test error from create did", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{CreateDIDErr: fmt.Errorf("error create did")}, createPath)
+
+		req, err := json.Marshal(CreateDIDRequest{JobID: "1"})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, createPath, req)
+		require.Equal(t, RegistrationStateFailure, response.DIDState.State)
+		require.Contains(t, response.DIDState.Reason, "error create did")
+	})
+
+	t.Run("test invalid secret key", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, createPath)
+
+		req, err := json.Marshal(CreateDIDRequest{JobID: "1", Secret: Secret{Keys: []Key{
+			{Purposes: []string{SecretPurposeRecovery}, PrivateKeyJWK: []byte(`{}`)}}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, createPath, req)
+		require.Equal(t, RegistrationStateFailure, response.DIDState.State)
+		require.Contains(t, response.DIDState.Reason, "invalid secret recovery key")
+	})
+}
+
+func TestUpdateDIDHandler(t *testing.T) {
+	t.Run("test error bad request", func(t *testing.T) {
+		handler := getHandler(t, nil, nil, updatePath)
+
+		body, status, err := handleRequest(handler, updatePath, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body.String(), "invalid request")
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{JobID: "1", Identifier: "did1",
+			Secret:               Secret{Keys: []Key{secretKeyJWK(t, "update1", SecretPurposeUpdate)}},
+			DIDDocumentOperation: []string{DIDDocumentOperationAdd, DIDDocumentOperationRemove},
+			DIDDocument: []*DIDDocument{
+				{PublicKey: []*PublicKey{{ID: "key2", Value: base64.StdEncoding.EncodeToString([]byte("value"))}},
+					Service: []*Service{{ID: "service2"}}},
+				{PublicKey: []*PublicKey{{ID: "key1"}}, Service: []*Service{{ID: "service1"}}}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, updatePath, req)
+		require.Equal(t, "1", response.JobID)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Equal(t, "did1", response.DIDState.Identifier)
+		require.Len(t, response.DIDState.Secret.Keys, 1)
+		require.Equal(t, []string{SecretPurposeUpdate}, response.DIDState.Secret.Keys[0].Purposes)
+	})
+
+	t.Run("test success with next update key", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1", Secret: Secret{Keys: []Key{
+			secretKeyJWK(t, "", SecretPurposeUpdate), secretKeyJWK(t, "", SecretPurposeNextUpdate)}},
+			DIDDocument: []*DIDDocument{{Service: []*Service{{ID: "service2"}}}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, updatePath, req)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Empty(t, response.DIDState.Secret.Keys)
+	})
+
+	t.Run("test identifier missing", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, updatePath, req, http.StatusBadRequest)
+		require.Equal(t, ErrorCodeInvalidRequest, response.DIDState.ErrorCode)
+		require.Contains(t, response.DIDState.Reason, "identifier is required")
+	})
+
+	t.Run("test update key missing", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1"})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, updatePath, req, http.StatusBadRequest)
+		require.Equal(t, ErrorCodeInvalidRequest, response.DIDState.ErrorCode)
+		require.Contains(t, response.DIDState.Reason, "secret update key is required")
+	})
+
+	t.Run("test unsupported did document operation", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1", DIDDocumentOperation: []string{"setDidDocument"},
+			DIDDocument: []*DIDDocument{{}}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, updatePath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "unsupported didDocumentOperation: setDidDocument")
+	})
+
+	t.Run("test error from update did", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{UpdateDIDErr: fmt.Errorf("error update did")}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1",
+			Secret: Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeUpdate)}}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, updatePath, req, http.StatusInternalServerError)
+		require.Equal(t, ErrorCodeOperationFailed, response.DIDState.ErrorCode)
+		require.Equal(t, "did1", response.DIDState.Identifier)
+		require.Contains(t, response.DIDState.Reason, "error update did")
+	})
+}
+
+func TestUpdateDIDHandlerWithKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	keyStore, err := NewEncryptedKeyStore(filepath.Join(dir, "keystore.json"), []byte("password"))
+	require.NoError(t, err)
+
+	updateKey := secretKeyJWK(t, "", SecretPurposeUpdate)
+	require.NoError(t, keyStore.Put("did1", SecretPurposeUpdate, updateKey.PrivateKeyJWK))
+
+	t.Run("test success", func(t *testing.T) {
+		svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+		svc.didBlocClient = &didbloc.Client{}
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1",
+			DIDDocument: []*DIDDocument{{Service: []*Service{{ID: "service2"}}}}})
+		require.NoError(t, err)
+
+		rr := authorizedRequest(handlerLookup(t, svc, updatePath), updatePath, req, "Bearer token")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		response := &RegisterResponse{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), response))
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Empty(t, response.DIDState.Secret.Keys)
+
+		nextUpdateKey, err := keyStore.Get("did1", SecretPurposeUpdate)
+		require.NoError(t, err)
+		require.NotEqual(t, []byte(updateKey.PrivateKeyJWK), nextUpdateKey)
+	})
+
+	t.Run("test key store signing without authorization", func(t *testing.T) {
+		storedKey, err := keyStore.Get("did1", SecretPurposeUpdate)
+		require.NoError(t, err)
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1", Secret: Secret{Keys: []Key{
+			secretKeyJWK(t, "", SecretPurposeNextUpdate)}}})
+		require.NoError(t, err)
+
+		for _, authorization := range []string{"", "Bearer other"} {
+			svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+			svc.didBlocClient = &didbloc.Client{}
+
+			rr := authorizedRequest(handlerLookup(t, svc, updatePath), updatePath, req, authorization)
+			require.Equal(t, http.StatusForbidden, rr.Code)
+			require.Contains(t, rr.Body.String(), "update signed with the key store requires an authorized request")
+		}
+
+		handler := getKeyStoreHandler(t, keyStore, &didbloc.Client{}, updatePath)
+
+		// without a deactivation token, the update requests can't be signed with the key store
+		response := registrarFailure(t, handler, updatePath, req, http.StatusForbidden)
+		require.Equal(t, ErrorCodeUnauthorized, response.DIDState.ErrorCode)
+
+		// the next update key of the request is not stored
+		nextUpdateKey, err := keyStore.Get("did1", SecretPurposeUpdate)
+		require.NoError(t, err)
+		require.Equal(t, storedKey, nextUpdateKey)
+	})
+
+	t.Run("test key store has no key", func(t *testing.T) {
+		handler := getKeyStoreHandler(t, keyStore, &didbloc.Client{}, updatePath)
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did2"})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, updatePath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "secret update key is required")
+	})
+}
+
+func TestRecoverDIDHandler(t *testing.T) {
+	t.Run("test error bad request", func(t *testing.T) {
+		handler := getHandler(t, nil, nil, recoverPath)
+
+		body, status, err := handleRequest(handler, recoverPath, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body.String(), "invalid request")
+	})
+
+	t.Run("test success with generated keys", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{JobID: "1", Identifier: "did1",
+			Secret: Secret{Keys: []Key{secretKeyJWK(t, "recovery1", SecretPurposeRecovery)}},
+			DIDDocument: DIDDocument{PublicKey: []*PublicKey{{ID: "key1", Type: "type",
+				Value: base64.StdEncoding.EncodeToString([]byte("value"))}}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, recoverPath, req)
+		require.Equal(t, "1", response.JobID)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Equal(t, "did1", response.DIDState.Identifier)
+		require.Len(t, response.DIDState.Secret.Keys, 3)
+		require.Equal(t, "did1#key1", response.DIDState.Secret.Keys[0].ID)
+		require.Equal(t, []string{SecretPurposeRecovery}, response.DIDState.Secret.Keys[1].Purposes)
+		require.Equal(t, []string{SecretPurposeUpdate}, response.DIDState.Secret.Keys[2].Purposes)
+	})
+
+	t.Run("test success with next keys", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1", Secret: Secret{Keys: []Key{
+			secretKeyJWK(t, "", SecretPurposeRecovery), secretKeyJWK(t, "", SecretPurposeNextRecovery),
+			secretKeyJWK(t, "", SecretPurposeNextUpdate)}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, recoverPath, req)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Empty(t, response.DIDState.Secret.Keys)
+	})
+
+	t.Run("test identifier missing", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, recoverPath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "identifier is required")
+	})
+
+	t.Run("test recovery key missing", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1"})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, recoverPath, req, http.StatusBadRequest)
+		require.Equal(t, ErrorCodeInvalidRequest, response.DIDState.ErrorCode)
+		require.Contains(t, response.DIDState.Reason, "secret recovery key is required")
+	})
+
+	t.Run("test invalid key type", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1",
+			DIDDocument: DIDDocument{PublicKey: []*PublicKey{{ID: "key1", Recovery: true, KeyType: "invalid",
+				Value: base64.StdEncoding.EncodeToString([]byte("value"))}}}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, recoverPath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "invalid key type: invalid")
+	})
+
+	t.Run("test error from recover did", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{RecoverDIDErr: fmt.Errorf("error recover did")}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1",
+			Secret: Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeRecovery)}}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, recoverPath, req, http.StatusInternalServerError)
+		require.Equal(t, ErrorCodeOperationFailed, response.DIDState.ErrorCode)
+		require.Contains(t, response.DIDState.Reason, "error recover did")
+	})
+
+	t.Run("test success with key store", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		keyStore, err := NewEncryptedKeyStore(filepath.Join(dir, "keystore.json"), []byte("password"))
+		require.NoError(t, err)
+
+		recoveryKey := secretKeyJWK(t, "", SecretPurposeRecovery)
+		require.NoError(t, keyStore.Put("did1", SecretPurposeRecovery, recoveryKey.PrivateKeyJWK))
+
+		svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+		svc.didBlocClient = &didbloc.Client{}
+
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1"})
+		require.NoError(t, err)
+
+		rr := authorizedRequest(handlerLookup(t, svc, recoverPath), recoverPath, req, "Bearer token")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		response := &RegisterResponse{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), response))
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Empty(t, response.DIDState.Secret.Keys)
+
+		nextRecoveryKey, err := keyStore.Get("did1", SecretPurposeRecovery)
+		require.NoError(t, err)
+		require.NotEqual(t, []byte(recoveryKey.PrivateKeyJWK), nextRecoveryKey)
+
+		_, err = keyStore.Get("did1", SecretPurposeUpdate)
+		require.NoError(t, err)
+	})
+
+	t.Run("test key store signing without authorization", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		keyStore, err := NewEncryptedKeyStore(filepath.Join(dir, "keystore.json"), []byte("password"))
+		require.NoError(t, err)
+
+		recoveryKey := secretKeyJWK(t, "", SecretPurposeRecovery)
+		require.NoError(t, keyStore.Put("did1", SecretPurposeRecovery, recoveryKey.PrivateKeyJWK))
+
+		// the caller tries to take over the DID with its own next keys
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1", Secret: Secret{Keys: []Key{
+			secretKeyJWK(t, "", SecretPurposeNextRecovery), secretKeyJWK(t, "", SecretPurposeNextUpdate)}}})
+		require.NoError(t, err)
+
+		for _, authorization := range []string{"", "Bearer other"} {
+			svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+			svc.didBlocClient = &didbloc.Client{}
+
+			rr := authorizedRequest(handlerLookup(t, svc, recoverPath), recoverPath, req, authorization)
+			require.Equal(t, http.StatusForbidden, rr.Code)
+			require.Contains(t, rr.Body.String(), "recovery signed with the key store requires an authorized request")
+		}
+
+		// without a deactivation token, the recover requests can't be signed with the key store
+		handler := getKeyStoreHandler(t, keyStore, &didbloc.Client{}, recoverPath)
+
+		response := registrarFailure(t, handler, recoverPath, req, http.StatusForbidden)
+		require.Equal(t, ErrorCodeUnauthorized, response.DIDState.ErrorCode)
+
+		storedKey, err := keyStore.Get("did1", SecretPurposeRecovery)
+		require.NoError(t, err)
+		require.Equal(t, []byte(recoveryKey.PrivateKeyJWK), storedKey)
+
+		_, err = keyStore.Get("did1", SecretPurposeUpdate)
+		require.Error(t, err)
+	})
+}
+
+func TestDeactivateDIDHandler(t *testing.T) {
+	t.Run("test error bad request", func(t *testing.T) {
+		handler := getHandler(t, nil, nil, deactivatePath)
+
+		body, status, err := handleRequest(handler, deactivatePath, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Contains(t, body.String(), "invalid request")
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, deactivatePath)
+
+		req, err := json.Marshal(DeactivateDIDRequest{JobID: "1", Identifier: "did1",
+			Secret: Secret{Keys: []Key{secretKeyJWK(t, "recovery1", SecretPurposeRecovery)}}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handler, deactivatePath, req)
+		require.Equal(t, "1", response.JobID)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+		require.Equal(t, "did1", response.DIDState.Identifier)
+	})
+
+	t.Run("test recovery key is public", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, deactivatePath)
+
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		jwk, err := (&gojose.JSONWebKey{Key: publicKey}).MarshalJSON()
+		require.NoError(t, err)
+
+		req, err := json.Marshal(DeactivateDIDRequest{Identifier: "did1",
+			Secret: Secret{Keys: []Key{{Purposes: []string{SecretPurposeRecovery}, PrivateKeyJWK: jwk}}}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, deactivatePath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "secret recovery key is not a private key")
+	})
+
+	t.Run("test error from deactivate did", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{DeactivateDIDErr: fmt.Errorf("error deactivate did")},
+			deactivatePath)
+
+		req, err := json.Marshal(DeactivateDIDRequest{Identifier: "did1",
+			Secret: Secret{Keys: []Key{secretKeyJWK(t, "", SecretPurposeRecovery)}}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, deactivatePath, req, http.StatusInternalServerError)
+		require.Contains(t, response.DIDState.Reason, "error deactivate did")
+	})
+}
+
+func TestDeactivateDIDHandlerAuthorization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	keyStore, err := NewEncryptedKeyStore(filepath.Join(dir, "keystore.json"), []byte("password"))
+	require.NoError(t, err)
+
+	recoveryKey := secretKeyJWK(t, "", SecretPurposeRecovery)
+	require.NoError(t, keyStore.Put("did1", SecretPurposeRecovery, recoveryKey.PrivateKeyJWK))
+
+	req, err := json.Marshal(DeactivateDIDRequest{Identifier: "did1"})
+	require.NoError(t, err)
+
+	t.Run("test success with deactivation token", func(t *testing.T) {
+		svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+		svc.didBlocClient = &didbloc.Client{}
+
+		rr := authorizedRequest(handlerLookup(t, svc, deactivatePath), deactivatePath, req, "Bearer token")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Contains(t, rr.Body.String(), RegistrationStateFinished)
+	})
+
+	t.Run("test invalid deactivation token", func(t *testing.T) {
+		svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+		svc.didBlocClient = &didbloc.Client{}
+
+		rr := authorizedRequest(handlerLookup(t, svc, deactivatePath), deactivatePath, req, "Bearer other")
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+		require.Contains(t, rr.Body.String(), ErrorCodeUnauthorized)
+	})
+
+	t.Run("test success with deactivation token header", func(t *testing.T) {
+		svc := New(&Config{KeyStore: keyStore, DeactivationToken: "token"})
+		svc.didBlocClient = &didbloc.Client{}
+
+		httpReq := httptest.NewRequest(http.MethodPost, deactivatePath, bytes.NewBuffer(req))
+		httpReq.Header.Set(authorizationHeader, "Bearer client-token")
+		httpReq.Header.Set(deactivationTokenHeader, "token")
+
+		rr := httptest.NewRecorder()
+
+		handlerLookup(t, svc, deactivatePath).Handle().ServeHTTP(rr, httpReq)
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("test key store signing without deactivation token", func(t *testing.T) {
+		handler := getKeyStoreHandler(t, keyStore, &didbloc.Client{}, deactivatePath)
+
+		response := registrarFailure(t, handler, deactivatePath, req, http.StatusForbidden)
+		require.Equal(t, ErrorCodeUnauthorized, response.DIDState.ErrorCode)
+		require.Contains(t, response.DIDState.Reason, "requires an authorized request")
+	})
+}
+
+func registrarResponse(t *testing.T, handler Handler, path string, req []byte) *RegisterResponse {
+	t.Helper()
+
+	body, status, err := handleRequest(handler, path, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, status)
+
+	response := &RegisterResponse{}
+	require.NoError(t, json.Unmarshal(body.Bytes(), response))
+
+	return response
+}
+
+func authorizedRequest(handler Handler, path string, req []byte, authorization string) *httptest.ResponseRecorder {
+	httpReq := httptest.NewRequest(handler.Method(), path, bytes.NewBuffer(req))
+
+	if authorization != "" {
+		httpReq.Header.Set(authorizationHeader, authorization)
+	}
+
+	rr := httptest.NewRecorder()
+
+	handler.Handle().ServeHTTP(rr, httpReq)
+
+	return rr
+}
+
+func registrarFailure(t *testing.T, handler Handler, path string, req []byte, status int) *RegisterResponse {
+	t.Helper()
+
+	body, code, err := handleRequest(handler, path, req)
+	require.NoError(t, err)
+	require.Equal(t, status, code)
+
+	response := &RegisterResponse{}
+	require.NoError(t, json.Unmarshal(body.Bytes(), response))
+	require.Equal(t, RegistrationStateFailure, response.DIDState.State)
+
+	return response
+}
+
+func getKeyStoreHandler(t *testing.T, keyStore KeyStore, didBlocClient didBlocClient, lookup string) Handler {
+	t.Helper()
+
+	svc := New(&Config{KeyStore: keyStore})
+	svc.didBlocClient = didBlocClient
+
+	return handlerLookup(t, svc, lookup)
+}
+
+func secretKeyJWK(t *testing.T, id, purpose string) Key {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := (&gojose.JSONWebKey{Key: privateKey}).MarshalJSON()
+	require.NoError(t, err)
+
+	return Key{ID: id, Purposes: []string{purpose}, PrivateKeyJWK: jwk}
+}
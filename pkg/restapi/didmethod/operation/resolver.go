@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/representation"
+)
+
+// Universal Resolver driver endpoint, see https://github.com/decentralized-identity/universal-resolver
+const (
+	identifiersPath = registerBasePath + "/identifiers/{did}"
+
+	didResolutionContentType = `application/ld+json;profile="https://w3id.org/did-resolution"`
+	didResolutionContext     = "https://w3id.org/did-resolution/v1"
+
+	didMethodPrefix    = "did:trustbloc:"
+	didResolutionError = "error"
+)
+
+// DID resolution errors, see https://w3c-ccg.github.io/did-resolution/#errors
+const (
+	ResolutionErrorInvalidDID         = "invalidDid"
+	ResolutionErrorNotFound           = "notFound"
+	ResolutionErrorMethodNotSupported = "methodNotSupported"
+	ResolutionErrorInternal           = "internalError"
+)
+
+func (o *Operation) identifiersHandler(rw http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	didID := mux.Vars(req)["did"]
+	// the DID document is returned alone in the representation the accept header asks for, if it doesn't ask for
+	// the DID resolution result
+	documentRepresentation := representation.Negotiate(req.Header.Get("Accept"))
+
+	result := &DIDResolutionResult{Context: didResolutionContext, DIDResolutionMetadata: map[string]interface{}{},
+		DIDDocumentMetadata: map[string]interface{}{}}
+
+	if !strings.HasPrefix(didID, "did:") {
+		o.writeResolutionError(rw, http.StatusBadRequest, result, ResolutionErrorInvalidDID)
+
+		return
+	}
+
+	if !strings.HasPrefix(didID, didMethodPrefix) {
+		o.writeResolutionError(rw, http.StatusNotImplemented, result, ResolutionErrorMethodNotSupported)
+
+		return
+	}
+
+	resolved, err := o.blocVDRI.Read(didID)
+	if err != nil {
+		log.Errorf("failed to resolve did %s: %s", didID, err.Error())
+
+		status, code := http.StatusInternalServerError, ResolutionErrorInternal
+		if errors.Is(err, vdr.ErrNotFound) {
+			status, code = http.StatusNotFound, ResolutionErrorNotFound
+		}
+
+		o.writeResolutionError(rw, status, result, code)
+
+		return
+	}
+
+	didDoc := resolved.DIDDocument
+
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		log.Errorf("failed to marshal did doc: %s", err.Error())
+
+		o.writeResolutionError(rw, http.StatusInternalServerError, result, ResolutionErrorInternal)
+
+		return
+	}
+
+	if documentRepresentation != "" {
+		o.writeDocument(rw, result, docBytes, documentRepresentation)
+
+		return
+	}
+
+	result.DIDDocument = docBytes
+	result.DIDResolutionMetadata["contentType"] = didLDJson
+	result.DIDResolutionMetadata["duration"] = time.Since(start).Milliseconds()
+	result.DIDDocumentMetadata = documentMetadata(didDoc)
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		log.Errorf("failed to marshal did resolution result: %s", err.Error())
+
+		o.writeResolutionError(rw, http.StatusInternalServerError, result, ResolutionErrorInternal)
+
+		return
+	}
+
+	o.writeResolution(rw, didResolutionContentType, resultBytes)
+}
+
+// writeDocument writes the DID document alone in the representation
+func (o *Operation) writeDocument(rw http.ResponseWriter, result *DIDResolutionResult, docBytes []byte,
+	mediaType string) {
+	body, err := representation.Marshal(docBytes, mediaType)
+	if err != nil {
+		log.Errorf("failed to marshal did doc to %s: %s", mediaType, err.Error())
+
+		o.writeResolutionError(rw, http.StatusInternalServerError, result, ResolutionErrorInternal)
+
+		return
+	}
+
+	o.writeResolution(rw, mediaType, body)
+}
+
+// documentMetadata returns the DID document metadata of the resolved DID document
+func documentMetadata(didDoc *did.Doc) map[string]interface{} {
+	metadata := make(map[string]interface{})
+
+	if didDoc.Created != nil {
+		metadata["created"] = didDoc.Created
+	}
+
+	if didDoc.Updated != nil {
+		metadata["updated"] = didDoc.Updated
+	}
+
+	return metadata
+}
+
+func (o *Operation) writeResolutionError(rw http.ResponseWriter, status int, result *DIDResolutionResult,
+	code string) {
+	result.DIDResolutionMetadata[didResolutionError] = code
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		o.writeErrorResponse(rw, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	rw.Header().Set("Content-type", didResolutionContentType)
+	rw.WriteHeader(status)
+
+	if _, err := rw.Write(resultBytes); err != nil {
+		log.Errorf("Unable to send error message, %s", err)
+	}
+}
+
+func (o *Operation) writeResolution(rw http.ResponseWriter, contentType string, body []byte) {
+	rw.Header().Set("Content-type", contentType)
+	rw.WriteHeader(http.StatusOK)
+
+	if _, err := rw.Write(body); err != nil {
+		log.Errorf("Unable to send response, %s", err)
+	}
+}
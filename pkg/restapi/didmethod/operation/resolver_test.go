@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/representation"
+)
+
+const testDID = "did:trustbloc:testnet.trustbloc.local:EiA"
+
+func TestIdentifiersHandler(t *testing.T) {
+	blocVDRI := &mockvdr.MockVDR{
+		ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+			return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{did.ContextV1}}}, nil
+		}}
+
+	t.Run("test success", func(t *testing.T) {
+		handler := getHandler(t, blocVDRI, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, testDID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, didResolutionContentType, rr.Header().Get("Content-type"))
+
+		result := &DIDResolutionResult{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+		require.Equal(t, didResolutionContext, result.Context)
+		require.Equal(t, didLDJson, result.DIDResolutionMetadata["contentType"])
+		require.NotContains(t, result.DIDResolutionMetadata, didResolutionError)
+
+		didDoc, err := did.ParseDocument(result.DIDDocument)
+		require.NoError(t, err)
+		require.Equal(t, testDID, didDoc.ID)
+	})
+
+	t.Run("test success with did document accepted", func(t *testing.T) {
+		handler := getHandler(t, blocVDRI, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, testDID, didLDJson+", application/json;q=0.9")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, didLDJson, rr.Header().Get("Content-type"))
+
+		didDoc, err := did.ParseDocument(rr.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, testDID, didDoc.ID)
+	})
+
+	t.Run("test success with did document representations accepted", func(t *testing.T) {
+		handler := getHandler(t, blocVDRI, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, testDID, representation.DIDJSON)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, representation.DIDJSON, rr.Header().Get("Content-type"))
+
+		didDoc, err := did.ParseDocument(rr.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, testDID, didDoc.ID)
+
+		rr = resolveIdentifier(handler, testDID, representation.DIDCBOR+", "+didLDJson+";q=0.9")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, representation.DIDCBOR, rr.Header().Get("Content-type"))
+
+		docBytes, err := representation.Unmarshal(rr.Body.Bytes(), representation.DIDCBOR)
+		require.NoError(t, err)
+
+		didDoc, err = did.ParseDocument(docBytes)
+		require.NoError(t, err)
+		require.Equal(t, testDID, didDoc.ID)
+	})
+
+	t.Run("test success with did resolution result accepted", func(t *testing.T) {
+		handler := getHandler(t, blocVDRI, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, testDID, didResolutionContentType)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, didResolutionContentType, rr.Header().Get("Content-type"))
+	})
+
+	t.Run("test invalid did", func(t *testing.T) {
+		handler := getHandler(t, blocVDRI, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, "trustbloc", "")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		requireResolutionError(t, rr, ResolutionErrorInvalidDID)
+	})
+
+	t.Run("test method not supported", func(t *testing.T) {
+		handler := getHandler(t, blocVDRI, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, "did:example:123", "")
+		require.Equal(t, http.StatusNotImplemented, rr.Code)
+		requireResolutionError(t, rr, ResolutionErrorMethodNotSupported)
+	})
+
+	t.Run("test did not found", func(t *testing.T) {
+		handler := getHandler(t, &mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, fmt.Errorf("failed to resolve did: %w", vdr.ErrNotFound)
+			}}, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, testDID, didLDJson)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		requireResolutionError(t, rr, ResolutionErrorNotFound)
+	})
+
+	t.Run("test error from bloc vdri read", func(t *testing.T) {
+		handler := getHandler(t, &mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, fmt.Errorf("read error")
+			}}, nil, identifiersPath)
+
+		rr := resolveIdentifier(handler, testDID, "")
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		requireResolutionError(t, rr, ResolutionErrorInternal)
+	})
+}
+
+func resolveIdentifier(handler Handler, didID, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(handler.Method(), registerBasePath+"/identifiers/"+didID, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func requireResolutionError(t *testing.T, rr *httptest.ResponseRecorder, code string) {
+	t.Helper()
+
+	result := &DIDResolutionResult{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+	require.Equal(t, code, result.DIDResolutionMetadata[didResolutionError])
+	require.Empty(t, result.DIDDocument)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// CallbackURLOption is the request option holding the URL notified when the operation is anchored
+	CallbackURLOption = "callbackUrl"
+
+	signatureHeader = "X-Signature"
+	signaturePrefix = "sha256="
+	webhookAttempts = 3
+	webhookTimeout  = 10 * time.Second
+
+	defaultMaxWatchers = 1000
+)
+
+// WebhooksConfig configures the notifications sent once the change of the DID document of an operation is
+// observable, or the operation failed
+type WebhooksConfig struct {
+	// URLs are notified of all the operations, in addition to the callback URL of the request if any
+	URLs []string
+	// Secret is the HMAC-SHA256 key signing the notifications, the signature is sent in the X-Signature header
+	Secret []byte
+	// PollInterval is the interval between the resolutions checking that an operation is anchored, 5s if not set
+	PollInterval time.Duration
+	// Timeout is the time after which an operation which is not anchored has failed, 10m if not set
+	Timeout time.Duration
+	// CallbackHosts are the hosts the callback URLs of the requests may target, the callback URLs are refused if not
+	// set
+	CallbackHosts []string
+	// MaxWatchers is the number of operations watched at once until they are anchored, 1000 if not set. The
+	// notifications of the operations submitted beyond are dropped.
+	MaxWatchers int
+	// HTTPClient sends the notifications, without following redirects
+	HTTPClient *http.Client
+}
+
+type webhooks struct {
+	urls          []string
+	secret        []byte
+	pollInterval  time.Duration
+	timeout       time.Duration
+	callbackHosts map[string]bool
+	watchers      chan struct{}
+	httpClient    *http.Client
+}
+
+func newWebhooks(config *WebhooksConfig) *webhooks {
+	w := &webhooks{urls: config.URLs, secret: config.Secret, pollInterval: config.PollInterval,
+		timeout: config.Timeout, callbackHosts: make(map[string]bool)}
+
+	if w.pollInterval <= 0 {
+		w.pollInterval = defaultOperationPollInterval
+	}
+
+	if w.timeout <= 0 {
+		w.timeout = defaultOperationTimeout
+	}
+
+	for _, host := range config.CallbackHosts {
+		w.callbackHosts[strings.ToLower(host)] = true
+	}
+
+	maxWatchers := config.MaxWatchers
+	if maxWatchers <= 0 {
+		maxWatchers = defaultMaxWatchers
+	}
+
+	w.watchers = make(chan struct{}, maxWatchers)
+
+	httpClient := http.Client{Timeout: webhookTimeout}
+	if config.HTTPClient != nil {
+		httpClient = *config.HTTPClient
+	}
+
+	// a redirect would let a webhook send the notification to any host
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	w.httpClient = &httpClient
+
+	return w
+}
+
+// callbackURL returns the callback URL of the request options, which must be an https URL of a callback host
+func (o *Operation) callbackURL(options map[string]string) (string, error) {
+	callbackURL := options[CallbackURLOption]
+	if callbackURL == "" {
+		return "", nil
+	}
+
+	if o.webhooks == nil {
+		return "", invalidRequest(errors.New("callback urls are not enabled"))
+	}
+
+	u, err := url.Parse(callbackURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", invalidRequest(fmt.Errorf("callback url must be an https url: %s", callbackURL))
+	}
+
+	if !o.webhooks.callbackHosts[strings.ToLower(u.Hostname())] {
+		return "", invalidRequest(fmt.Errorf("callback url host is not allowed: %s", u.Hostname()))
+	}
+
+	return callbackURL, nil
+}
+
+// documentBefore returns the document of the DID before an operation, to tell when its change is anchored
+func (o *Operation) documentBefore(didID string) []byte {
+	if didID == "" {
+		return nil
+	}
+
+	doc, err := o.resolveDocument(didID)
+	if err != nil {
+		log.Warnf("failed to resolve did %s before operation: %s", didID, err)
+	}
+
+	return doc
+}
+
+// watchOperation returns the function to call once the operation is submitted, which notifies the webhooks in the
+// background when the operation is anchored. It does nothing if the webhooks are not enabled, and drops the
+// notification if the maximum number of operations are already watched.
+func (o *Operation) watchOperation(notification *WebhookNotification, callbackURL string) func(didID string) {
+	if o.webhooks == nil {
+		return func(string) {}
+	}
+
+	before := o.documentBefore(notification.Identifier)
+
+	return func(didID string) {
+		notification.Identifier = didID
+
+		select {
+		case o.webhooks.watchers <- struct{}{}:
+		default:
+			log.Errorf("too many operations watched, the %s of did %s is not notified", notification.Type, didID)
+
+			return
+		}
+
+		go func() {
+			defer func() { <-o.webhooks.watchers }()
+
+			err := o.waitAnchored(didID, before, o.webhooks.pollInterval, o.webhooks.timeout)
+
+			o.notifyWebhooks(notification, err, callbackURL)
+		}()
+	}
+}
+
+// notifyWebhooks sends the notification that the operation is anchored, or failed with the error, to the webhooks
+// of the service and to the callback URL if any
+func (o *Operation) notifyWebhooks(notification *WebhookNotification, err error, callbackURL string) {
+	notification.State = OperationStateAnchored
+	notification.Timestamp = time.Now()
+
+	if err != nil {
+		notification.State = OperationStateFailed
+		notification.Reason = err.Error()
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		log.Errorf("failed to marshal notification of did %s: %s", notification.Identifier, err)
+
+		return
+	}
+
+	urls := o.webhooks.urls
+	if callbackURL != "" {
+		urls = append(urls[:len(urls):len(urls)], callbackURL)
+	}
+
+	for _, webhookURL := range urls {
+		if err = o.webhooks.post(webhookURL, body); err != nil {
+			log.Errorf("failed to notify %s of did %s: %s", webhookURL, notification.Identifier, err)
+		}
+	}
+}
+
+// post sends the signed notification to the URL, retrying after a growing multiple of the poll interval when it fails
+func (w *webhooks) post(webhookURL string, body []byte) error {
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body) //nolint: errcheck,gosec
+
+	signature := signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+
+	var err error
+
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if err = w.send(webhookURL, body, signature); err == nil {
+			return nil
+		}
+
+		if attempt < webhookAttempts {
+			time.Sleep(time.Duration(attempt) * w.pollInterval)
+		}
+	}
+
+	return err
+}
+
+func (w *webhooks) send(webhookURL string, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, signature)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		log.Warnf("failed to close response body: %s", closeErr)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/didbloc"
+)
+
+func TestWebhooks(t *testing.T) {
+	secret := []byte("secret")
+	notifications := make(chan *WebhookNotification, 10)
+
+	serv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, secret)
+		_, err = mac.Write(body)
+		require.NoError(t, err)
+		require.Equal(t, signaturePrefix+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(signatureHeader))
+
+		notification := &WebhookNotification{}
+		require.NoError(t, json.Unmarshal(body, notification))
+
+		notifications <- notification
+	}))
+	defer serv.Close()
+
+	blocVDRI := &mockvdr.MockVDR{ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+		return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{"context"}}}, nil
+	}}
+
+	t.Run("test create notified", func(t *testing.T) {
+		svc := getWebhooksOperation(blocVDRI, &didbloc.Client{CreateDIDValue: &did.Doc{ID: "did1"}},
+			&WebhooksConfig{Secret: secret, CallbackHosts: []string{"127.0.0.1"}, HTTPClient: serv.Client()})
+
+		req, err := json.Marshal(CreateDIDRequest{JobID: "job1", Options: map[string]string{
+			CallbackURLOption: serv.URL}})
+		require.NoError(t, err)
+
+		response := registrarResponse(t, handlerLookup(t, svc, createPath), createPath, req)
+		require.Equal(t, RegistrationStateFinished, response.DIDState.State)
+
+		notification := receiveNotification(t, notifications)
+		require.Equal(t, "job1", notification.JobID)
+		require.Equal(t, OperationTypeCreate, notification.Type)
+		require.Equal(t, "did1", notification.Identifier)
+		require.Equal(t, OperationStateAnchored, notification.State)
+	})
+
+	t.Run("test asynchronous update failure notified to the service webhook", func(t *testing.T) {
+		svc := getWebhooksOperation(blocVDRI, &didbloc.Client{},
+			&WebhooksConfig{URLs: []string{serv.URL}, Secret: secret, HTTPClient: serv.Client()})
+		svc.async = newAsyncOperations(&AsyncOperationsConfig{PollInterval: time.Millisecond})
+
+		operationID := submitAsync(t, svc, updatePath, UpdateDIDRequest{Identifier: "did1"})
+
+		notification := receiveNotification(t, notifications)
+		require.Equal(t, operationID, notification.OperationID)
+		require.Equal(t, OperationTypeUpdate, notification.Type)
+		require.Equal(t, "did1", notification.Identifier)
+		require.Equal(t, OperationStateFailed, notification.State)
+		require.Contains(t, notification.Reason, "secret update key is required")
+	})
+
+	t.Run("test invalid callback url", func(t *testing.T) {
+		svc := getWebhooksOperation(blocVDRI, &didbloc.Client{}, &WebhooksConfig{Secret: secret})
+
+		req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1", Options: map[string]string{
+			CallbackURLOption: "http://localhost/callback"}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handlerLookup(t, svc, updatePath), updatePath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "callback url must be an https url")
+	})
+
+	t.Run("test callback url host not allowed", func(t *testing.T) {
+		svc := getWebhooksOperation(blocVDRI, &didbloc.Client{},
+			&WebhooksConfig{Secret: secret, CallbackHosts: []string{"example.com"}})
+
+		for _, callbackURL := range []string{serv.URL, "https://169.254.169.254/latest/meta-data",
+			"https://example.com.attacker.net/callback"} {
+			req, err := json.Marshal(UpdateDIDRequest{Identifier: "did1", Options: map[string]string{
+				CallbackURLOption: callbackURL}})
+			require.NoError(t, err)
+
+			response := registrarFailure(t, handlerLookup(t, svc, updatePath), updatePath, req, http.StatusBadRequest)
+			require.Contains(t, response.DIDState.Reason, "callback url host is not allowed")
+		}
+
+		_, err := svc.callbackURL(map[string]string{CallbackURLOption: "https://EXAMPLE.com:8443/callback"})
+		require.NoError(t, err)
+	})
+
+	t.Run("test maximum number of watched operations", func(t *testing.T) {
+		release := make(chan struct{})
+
+		svc := getWebhooksOperation(&mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				<-release
+
+				return &did.DocResolution{DIDDocument: &did.Doc{ID: didID, Context: []string{"context"}}}, nil
+			}}, &didbloc.Client{},
+			&WebhooksConfig{URLs: []string{serv.URL}, Secret: secret, MaxWatchers: 1, HTTPClient: serv.Client()})
+
+		svc.watchOperation(&WebhookNotification{Type: OperationTypeCreate}, "")("did1")
+		svc.watchOperation(&WebhookNotification{Type: OperationTypeCreate}, "")("did2")
+
+		close(release)
+
+		require.Equal(t, "did1", receiveNotification(t, notifications).Identifier)
+		require.Eventually(t, func() bool { return len(svc.webhooks.watchers) == 0 }, time.Second,
+			5*time.Millisecond)
+		require.Empty(t, notifications)
+
+		svc.watchOperation(&WebhookNotification{Type: OperationTypeCreate}, "")("did3")
+		require.Equal(t, "did3", receiveNotification(t, notifications).Identifier)
+	})
+
+	t.Run("test callback urls not enabled", func(t *testing.T) {
+		handler := getHandler(t, nil, &didbloc.Client{}, recoverPath)
+
+		req, err := json.Marshal(RecoverDIDRequest{Identifier: "did1", Options: map[string]string{
+			CallbackURLOption: serv.URL}})
+		require.NoError(t, err)
+
+		response := registrarFailure(t, handler, recoverPath, req, http.StatusBadRequest)
+		require.Contains(t, response.DIDState.Reason, "callback urls are not enabled")
+	})
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var attempts int
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer serv.Close()
+
+	w := newWebhooks(&WebhooksConfig{PollInterval: time.Millisecond})
+
+	err := w.post(serv.URL, []byte("{}"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "webhook returned status 500")
+	require.Equal(t, webhookAttempts, attempts)
+}
+
+func TestWebhookRedirect(t *testing.T) {
+	var redirected bool
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+
+	serv := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer serv.Close()
+
+	for _, httpClient := range []*http.Client{nil, serv.Client()} {
+		w := newWebhooks(&WebhooksConfig{PollInterval: time.Millisecond, HTTPClient: httpClient})
+
+		err := w.post(serv.URL, []byte("{}"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "webhook returned status 307")
+		require.False(t, redirected)
+	}
+}
+
+func getWebhooksOperation(blocVDRI vdr.VDR, didBlocClient didBlocClient, config *WebhooksConfig) *Operation {
+	config.PollInterval = time.Millisecond
+	config.Timeout = 50 * time.Millisecond
+
+	svc := New(&Config{Webhooks: config})
+	svc.blocVDRI = blocVDRI
+	svc.didBlocClient = didBlocClient
+
+	return svc
+}
+
+func receiveNotification(t *testing.T, notifications chan *WebhookNotification) *WebhookNotification {
+	select {
+	case notification := <-notifications:
+		return notification
+	case <-time.After(time.Second):
+		require.Fail(t, "notification not received")
+
+		return nil
+	}
+}
@@ -9,11 +9,11 @@ import (
 	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/healthcheck/operation"
 )
 
-// New returns new controller instance.
-func New() *Controller {
+// New returns new controller instance, with the readiness checks of the dependencies of the service.
+func New(readinessChecks ...operation.ReadinessCheck) *Controller {
 	var allHandlers []operation.Handler
 
-	rpService := operation.New()
+	rpService := operation.New(readinessChecks...)
 
 	handlers := rpService.GetRESTHandlers()
 
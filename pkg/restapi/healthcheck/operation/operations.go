@@ -32,19 +32,21 @@ type Handler interface {
 	Handle() http.HandlerFunc
 }
 
-// New returns CreateCredential instance.
-func New() *Operation {
-	return &Operation{}
+// New returns health check operation instance, with the readiness checks of the dependencies of the service.
+func New(readinessChecks ...ReadinessCheck) *Operation {
+	return &Operation{readinessChecks: readinessChecks}
 }
 
-// Operation defines handlers for rp operations.
+// Operation defines handlers for health check operations.
 type Operation struct {
+	readinessChecks []ReadinessCheck
 }
 
 // GetRESTHandlers get all controller API handler available for this service.
 func (o *Operation) GetRESTHandlers() []Handler {
 	return []Handler{
 		support.NewHTTPHandler(healthCheckEndpoint, http.MethodGet, o.healthCheckHandler),
+		support.NewHTTPHandler(readinessEndpoint, http.MethodGet, o.readinessHandler),
 	}
 }
 
@@ -15,7 +15,7 @@ import (
 
 func TestGetRESTHandlers(t *testing.T) {
 	c := New()
-	require.Equal(t, 1, len(c.GetRESTHandlers()))
+	require.Equal(t, 2, len(c.GetRESTHandlers()))
 }
 
 func TestHealthCheck(t *testing.T) {
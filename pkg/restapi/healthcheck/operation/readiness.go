@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/discovery/staticdiscovery"
+)
+
+const (
+	readinessEndpoint = "/readiness"
+
+	readinessTimeout = 10 * time.Second
+	checkSucceeded   = "ok"
+)
+
+// ReadinessCheck checks that a dependency of the service is reachable
+type ReadinessCheck struct {
+	Name  string
+	Check func() error
+}
+
+type readinessResp struct {
+	Status      string            `json:"status"`
+	CurrentTime time.Time         `json:"currentTime"`
+	Checks      map[string]string `json:"checks,omitempty"`
+}
+
+// NewDomainChecks returns the readiness checks of the consortium domain: the consortium config can be fetched,
+// and at least one of the sidetree endpoints of its stakeholders is reachable
+func NewDomainChecks(domain string, tlsConfig *tls.Config) []ReadinessCheck {
+	configService := httpconfig.NewService(httpconfig.WithTLSConfig(tlsConfig))
+	discoveryService := staticdiscovery.NewService(configService)
+	client := &http.Client{Timeout: readinessTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}
+
+	return []ReadinessCheck{
+		{Name: "consortium", Check: func() error {
+			_, err := configService.GetConsortium(domain, domain)
+			if err != nil {
+				return fmt.Errorf("failed to get consortium config of %s: %w", domain, err)
+			}
+
+			return nil
+		}},
+		{Name: "sidetree", Check: func() error {
+			endpoints, err := discoveryService.GetEndpoints(domain)
+			if err != nil {
+				return fmt.Errorf("failed to get sidetree endpoints of %s: %w", domain, err)
+			}
+
+			for _, endpoint := range endpoints {
+				if err = checkEndpoint(client, endpoint.URL); err == nil {
+					return nil
+				}
+
+				log.Warnf("sidetree endpoint %s is not reachable: %s", endpoint.URL, err)
+			}
+
+			return errors.New("no sidetree endpoint is reachable")
+		}},
+	}
+}
+
+func checkEndpoint(client *http.Client, url string) error {
+	resp, err := client.Get(url) //nolint: noctx
+	if err != nil {
+		return err
+	}
+
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		log.Warnf("failed to close response body: %s", closeErr)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (o *Operation) readinessHandler(rw http.ResponseWriter, _ *http.Request) {
+	resp := &readinessResp{Status: "success", CurrentTime: time.Now()}
+	status := http.StatusOK
+
+	if len(o.readinessChecks) > 0 {
+		resp.Checks = make(map[string]string)
+	}
+
+	for _, check := range o.readinessChecks {
+		if err := check.Check(); err != nil {
+			resp.Checks[check.Name] = err.Error()
+			resp.Status = "failure"
+			status = http.StatusServiceUnavailable
+
+			continue
+		}
+
+		resp.Checks[check.Name] = checkSucceeded
+	}
+
+	rw.WriteHeader(status)
+
+	if err := json.NewEncoder(rw).Encode(resp); err != nil {
+		log.Errorf("readiness response failure, %s", err)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness(t *testing.T) {
+	t.Run("test ready without checks", func(t *testing.T) {
+		c := New()
+
+		rr := httptest.NewRecorder()
+		c.readinessHandler(rr, nil)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("test ready", func(t *testing.T) {
+		c := New(ReadinessCheck{Name: "check1", Check: func() error { return nil }})
+
+		rr := httptest.NewRecorder()
+		c.readinessHandler(rr, nil)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := &readinessResp{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+		require.Equal(t, "success", resp.Status)
+		require.Equal(t, checkSucceeded, resp.Checks["check1"])
+	})
+
+	t.Run("test not ready", func(t *testing.T) {
+		c := New(ReadinessCheck{Name: "check1", Check: func() error { return nil }},
+			ReadinessCheck{Name: "check2", Check: func() error { return errors.New("unreachable") }})
+
+		rr := httptest.NewRecorder()
+		c.readinessHandler(rr, nil)
+
+		require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+		resp := &readinessResp{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), resp))
+		require.Equal(t, "failure", resp.Status)
+		require.Equal(t, checkSucceeded, resp.Checks["check1"])
+		require.Equal(t, "unreachable", resp.Checks["check2"])
+	})
+}
+
+func TestDomainChecks(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer serv.Close()
+
+	checks := NewDomainChecks(serv.URL, nil)
+	require.Len(t, checks, 2)
+
+	for _, check := range checks {
+		err := check.Check()
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "failed to get"))
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer serv.Close()
+
+	require.NoError(t, checkEndpoint(serv.Client(), serv.URL+"/sidetree/0.0.1"))
+
+	err := checkEndpoint(serv.Client(), serv.URL+"/unavailable")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status code 503")
+}
@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi"
+)
+
+// UnaryInterceptor serves the calls with an idempotency key in their metadata once per client and key, as the
+// Handler serves the REST requests. A call with the key of a call in progress is rejected with code Aborted, and a
+// call with the key of another call with code FailedPrecondition. The secret fields of the results are not stored.
+func UnaryInterceptor(s *Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		keys := md.Get(Header)
+
+		msg, ok := req.(proto.Message)
+		if len(keys) == 0 || keys[0] == "" || !ok {
+			return handler(ctx, req)
+		}
+
+		if len(keys[0]) > maxKeyLength {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key is too long")
+		}
+
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "failed to marshal request")
+		}
+
+		storeKey := client(grpcapi.HTTPRequest(ctx, info.FullMethod)) + " " + keys[0]
+
+		stored, err := s.start(storeKey, sha256.Sum256(append([]byte(info.FullMethod+"\n"), body...)))
+
+		switch {
+		case errors.Is(err, errInProgress):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, errKeyReused):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, errStoreFull):
+			return nil, status.Error(codes.Unavailable, err.Error())
+		case stored != nil:
+			if err := grpc.SetHeader(ctx, metadata.Pairs(replayedHeader, "true")); err != nil {
+				log.Warnf("failed to set replayed header: %s", err)
+			}
+
+			return stored.message, stored.err
+		default:
+			return serveCall(ctx, s, storeKey, req, handler)
+		}
+	}
+}
+
+// serveCall serves the call with the handler and stores its result, unless the call failed with a server error
+func serveCall(ctx context.Context, s *Store, key string, req interface{},
+	handler grpc.UnaryHandler) (interface{}, error) {
+	var served *response
+
+	defer func() { s.finish(key, served) }()
+
+	resp, err := handler(ctx, req)
+
+	switch status.Code(err) {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded:
+		// the call can be retried
+	default:
+		served = &response{message: withoutSecretFields(resp), err: err}
+	}
+
+	return resp, err
+}
+
+// withoutSecretFields returns a copy of the message without its secret fields
+func withoutSecretFields(resp interface{}) interface{} {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return resp
+	}
+
+	stripped := proto.Clone(msg)
+	clearSecrets(stripped.ProtoReflect())
+
+	return stripped
+}
+
+func clearSecrets(m protoreflect.Message) {
+	var secrets []protoreflect.FieldDescriptor
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Name() == secretMember:
+			secrets = append(secrets, fd)
+		case fd.Message() == nil || fd.IsMap():
+			// no secret in scalar fields, and none in the maps of the messages
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				clearSecrets(v.List().Get(i).Message())
+			}
+		default:
+			clearSecrets(v.Message())
+		}
+
+		return true
+	})
+
+	for _, fd := range secrets {
+		m.Clear(fd)
+	}
+}
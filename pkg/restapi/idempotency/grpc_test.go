@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi/didmethodpb"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	var served int
+
+	code := codes.OK
+
+	interceptor := UnaryInterceptor(New(time.Hour, 10))
+
+	call := func(key string, req interface{}) (interface{}, error) {
+		ctx := context.Background()
+		if key != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("idempotency-key", key))
+		}
+
+		return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/trustbloc.didmethod.v1.DIDMethod/CreateDID"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				served++
+
+				if code != codes.OK {
+					return nil, status.Error(code, "error")
+				}
+
+				return &didmethodpb.RegisterResponse{JobId: "job1", DidState: &didmethodpb.DIDState{
+					Identifier: "did:ex:123",
+					Secret:     &didmethodpb.Secret{Keys: []*didmethodpb.Key{{PrivateKeyBase58: "key"}}}}}, nil
+			})
+	}
+
+	t.Run("test call replayed without secret", func(t *testing.T) {
+		served = 0
+
+		resp, err := call("key1", &didmethodpb.CreateDIDRequest{JobId: "job1"})
+		require.NoError(t, err)
+		require.NotNil(t, resp.(*didmethodpb.RegisterResponse).DidState.Secret)
+
+		resp, err = call("key1", &didmethodpb.CreateDIDRequest{JobId: "job1"})
+		require.NoError(t, err)
+		require.Equal(t, "did:ex:123", resp.(*didmethodpb.RegisterResponse).DidState.Identifier)
+		require.Nil(t, resp.(*didmethodpb.RegisterResponse).DidState.Secret)
+		require.Equal(t, 1, served)
+	})
+
+	t.Run("test calls without key", func(t *testing.T) {
+		served = 0
+
+		_, err := call("", &didmethodpb.CreateDIDRequest{JobId: "job1"})
+		require.NoError(t, err)
+
+		_, err = call("", &didmethodpb.CreateDIDRequest{JobId: "job1"})
+		require.NoError(t, err)
+		require.Equal(t, 2, served)
+	})
+
+	t.Run("test key reused for another call", func(t *testing.T) {
+		_, err := call("key1", &didmethodpb.CreateDIDRequest{JobId: "job2"})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("test server error not stored", func(t *testing.T) {
+		served = 0
+		code = codes.Internal
+
+		_, err := call("key2", &didmethodpb.CreateDIDRequest{})
+		require.Equal(t, codes.Internal, status.Code(err))
+
+		code = codes.InvalidArgument
+
+		_, err = call("key2", &didmethodpb.CreateDIDRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		code = codes.OK
+
+		_, err = call("key2", &didmethodpb.CreateDIDRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Equal(t, 2, served)
+	})
+
+	t.Run("test invalid key", func(t *testing.T) {
+		_, err := call(string(make([]byte, maxKeyLength+1)), &didmethodpb.CreateDIDRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
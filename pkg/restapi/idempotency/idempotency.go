@@ -0,0 +1,347 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+)
+
+// Header is the header holding the idempotency key of a request
+const Header = "Idempotency-Key"
+
+const (
+	replayedHeader = "Idempotent-Replayed"
+	maxKeyLength   = 255
+	maxBodySize    = 1 << 20
+	secretMember   = "secret"
+
+	errorCodeInvalidKey = "invalidIdempotencyKey"
+	errorCodeInProgress = "requestInProgress"
+	errorCodeKeyReused  = "idempotencyKeyReused"
+	errorCodeStoreFull  = "tooManyIdempotencyKeys"
+)
+
+var (
+	errInProgress = errors.New("a request with the same idempotency key is in progress")
+	errKeyReused  = errors.New("the idempotency key was used for another request")
+	errStoreFull  = errors.New("too many requests with an idempotency key are in progress, retry later")
+)
+
+// Store keeps the responses to the requests with an idempotency key for the TTL, keyed by client and key, holding
+// at most maxSize responses
+type Store struct {
+	ttl       time.Duration
+	maxSize   int
+	mutex     sync.Mutex
+	responses map[string]*response
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// response is the response to an HTTP request, or the result of a gRPC call
+type response struct {
+	requestHash [sha256.Size]byte
+	done        bool
+	stored      time.Time
+	status      int
+	header      http.Header
+	body        []byte
+	message     interface{}
+	err         error
+}
+
+// New returns a store keeping at most maxSize responses for the TTL
+func New(ttl time.Duration, maxSize int) *Store {
+	return &Store{ttl: ttl, maxSize: maxSize, responses: make(map[string]*response), now: time.Now}
+}
+
+// start returns the stored response to the request with the key, or else records the request as in progress and
+// returns nil
+func (s *Store) start(key string, requestHash [sha256.Size]byte) (*response, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+
+	// forget the expired responses, at most once per TTL
+	if now.Sub(s.lastSweep) >= s.ttl {
+		for k, r := range s.responses {
+			if r.done && now.Sub(r.stored) >= s.ttl {
+				delete(s.responses, k)
+			}
+		}
+
+		s.lastSweep = now
+	}
+
+	r, ok := s.responses[key]
+	if !ok || (r.done && now.Sub(r.stored) >= s.ttl) {
+		if !ok && len(s.responses) >= s.maxSize && !s.evict(now) {
+			return nil, errStoreFull
+		}
+
+		s.responses[key] = &response{requestHash: requestHash}
+
+		return nil, nil
+	}
+
+	if r.requestHash != requestHash {
+		return nil, errKeyReused
+	}
+
+	if !r.done {
+		return nil, errInProgress
+	}
+
+	return r, nil
+}
+
+// evict removes the expired responses, or else the response closest to expiring, and returns false if all the
+// responses are of requests in progress
+func (s *Store) evict(now time.Time) bool {
+	var oldest string
+
+	for k, r := range s.responses {
+		switch {
+		case !r.done:
+			// the requests in progress are never evicted
+		case now.Sub(r.stored) >= s.ttl:
+			delete(s.responses, k)
+		case oldest == "" || r.stored.Before(s.responses[oldest].stored):
+			oldest = k
+		}
+	}
+
+	if len(s.responses) < s.maxSize {
+		return true
+	}
+
+	if oldest == "" {
+		return false
+	}
+
+	delete(s.responses, oldest)
+
+	return true
+}
+
+// finish stores the response to the request with the key, or forgets the request if there is no response to store,
+// the request having failed with a server error or the handler having panicked, so that it can be retried
+func (s *Store) finish(key string, served *response) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r, ok := s.responses[key]
+	if !ok {
+		return
+	}
+
+	if served == nil {
+		delete(s.responses, key)
+
+		return
+	}
+
+	r.done = true
+	r.stored = s.now()
+	r.status = served.status
+	r.header = served.header
+	r.body = served.body
+	r.message = served.message
+	r.err = served.err
+}
+
+// Handler returns a handler which serves the POST requests with an idempotency key once per client and key, the
+// requests retried with the same key getting the stored response instead of being served again. A request with the
+// key of a request in progress is rejected with status 409, and a request with the key of another request with
+// status 422. Clients are identified by their authenticated identity, or else by their IP address. The secret members
+// of the responses are not stored, so that the private keys of a response are returned once.
+func Handler(s *Store, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		key := req.Header.Get(Header)
+		if req.Method != http.MethodPost || key == "" {
+			handler(rw, req)
+
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			writeError(rw, http.StatusBadRequest, errorCodeInvalidKey, "idempotency key is too long")
+
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(rw, req.Body, maxBodySize))
+		if err != nil {
+			writeError(rw, http.StatusBadRequest, errorCodeInvalidKey, "failed to read request body")
+
+			return
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		storeKey := client(req) + " " + key
+
+		stored, err := s.start(storeKey, sha256.Sum256(append([]byte(req.URL.Path+"\n"), body...)))
+
+		switch {
+		case errors.Is(err, errInProgress):
+			writeError(rw, http.StatusConflict, errorCodeInProgress, err.Error())
+		case errors.Is(err, errKeyReused):
+			writeError(rw, http.StatusUnprocessableEntity, errorCodeKeyReused, err.Error())
+		case errors.Is(err, errStoreFull):
+			writeError(rw, http.StatusServiceUnavailable, errorCodeStoreFull, err.Error())
+		case stored != nil:
+			replay(rw, stored)
+		default:
+			serve(s, storeKey, handler, rw, req)
+		}
+	}
+}
+
+// serve serves the request with the handler and stores its response, unless the request failed with a server error
+func serve(s *Store, key string, handler http.HandlerFunc, rw http.ResponseWriter, req *http.Request) {
+	var served *response
+
+	defer func() { s.finish(key, served) }()
+
+	recorder := &responseRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+	handler(recorder, req)
+
+	if recorder.status < http.StatusInternalServerError {
+		served = &response{status: recorder.status, header: recorder.header,
+			body: withoutSecrets(recorder.body.Bytes())}
+
+		if served.header != nil {
+			served.header.Del("Content-Length")
+		}
+	}
+}
+
+// withoutSecrets returns the JSON body without its secret members, or the body itself if it is not JSON
+func withoutSecrets(body []byte) []byte {
+	var v interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&v); err != nil || !removeSecrets(v) {
+		return body
+	}
+
+	stripped, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	return append(stripped, '\n')
+}
+
+// removeSecrets removes the secret members of the JSON value, and returns whether there were any
+func removeSecrets(v interface{}) bool {
+	removed := false
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if _, ok := value[secretMember]; ok {
+			delete(value, secretMember)
+
+			removed = true
+		}
+
+		for _, member := range value {
+			removed = removeSecrets(member) || removed
+		}
+	case []interface{}:
+		for _, element := range value {
+			removed = removeSecrets(element) || removed
+		}
+	}
+
+	return removed
+}
+
+// replay writes the stored response, without overwriting the headers already set such as the correlation ID
+func replay(rw http.ResponseWriter, r *response) {
+	for name, values := range r.header {
+		if rw.Header().Get(name) == "" {
+			rw.Header()[name] = values
+		}
+	}
+
+	rw.Header().Set(replayedHeader, "true")
+	rw.WriteHeader(r.status)
+
+	if _, err := rw.Write(r.body); err != nil {
+		log.Errorf("Unable to send replayed response, %s", err)
+	}
+}
+
+func writeError(rw http.ResponseWriter, status int, code, message string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	err := json.NewEncoder(rw).Encode(map[string]string{"code": code, "message": message})
+	if err != nil {
+		log.Errorf("Unable to send error response, %s", err)
+	}
+}
+
+// client identifies the client of the request as the rate limiter does
+func client(req *http.Request) string {
+	if clientID := auth.ClientID(req.Context()); clientID != "" {
+		return "client:" + clientID
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return "ip:" + req.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// responseRecorder records the response while writing it
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.header = r.ResponseWriter.Header().Clone()
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var served int
+
+	status := http.StatusOK
+
+	handler := Handler(New(time.Hour, 10), func(rw http.ResponseWriter, req *http.Request) {
+		served++
+
+		rw.Header().Set("Location", "/1.0/operations/op1")
+		rw.WriteHeader(status)
+		fmt.Fprintf(rw, `{"served": %d}`, served)
+	})
+
+	request := func(method, path, key, body, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		return rr
+	}
+
+	t.Run("test request replayed", func(t *testing.T) {
+		served = 0
+
+		rr := request(http.MethodPost, "/1.0/update", "key1", "{}", "192.0.2.1:1234")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, `{"served": 1}`, rr.Body.String())
+		require.Empty(t, rr.Header().Get(replayedHeader))
+
+		rr = request(http.MethodPost, "/1.0/update", "key1", "{}", "192.0.2.1:5678")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, `{"served": 1}`, rr.Body.String())
+		require.Equal(t, "true", rr.Header().Get(replayedHeader))
+		require.Equal(t, "/1.0/operations/op1", rr.Header().Get("Location"))
+		require.Equal(t, 1, served)
+	})
+
+	t.Run("test requests without key or of other clients", func(t *testing.T) {
+		served = 0
+
+		request(http.MethodPost, "/1.0/update", "", "{}", "192.0.2.1:1234")
+		request(http.MethodPost, "/1.0/update", "", "{}", "192.0.2.1:1234")
+		request(http.MethodGet, "/1.0/operations/op1", "key2", "", "192.0.2.1:1234")
+		request(http.MethodGet, "/1.0/operations/op1", "key2", "", "192.0.2.1:1234")
+		request(http.MethodPost, "/1.0/update", "key1", "{}", "192.0.2.2:1234")
+		require.Equal(t, 5, served)
+	})
+
+	t.Run("test key reused for another request", func(t *testing.T) {
+		rr := request(http.MethodPost, "/1.0/update", "key1", `{"other": true}`, "192.0.2.1:1234")
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		require.Contains(t, rr.Body.String(), errorCodeKeyReused)
+
+		rr = request(http.MethodPost, "/1.0/recover", "key1", "{}", "192.0.2.1:1234")
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("test server error not stored", func(t *testing.T) {
+		served = 0
+		status = http.StatusInternalServerError
+
+		request(http.MethodPost, "/1.0/update", "key3", "{}", "192.0.2.1:1234")
+
+		status = http.StatusOK
+
+		rr := request(http.MethodPost, "/1.0/update", "key3", "{}", "192.0.2.1:1234")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, 2, served)
+	})
+
+	t.Run("test invalid key", func(t *testing.T) {
+		rr := request(http.MethodPost, "/1.0/update", strings.Repeat("k", maxKeyLength+1), "{}", "192.0.2.1")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), errorCodeInvalidKey)
+	})
+
+	t.Run("test body read error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/update", &failingReader{})
+		req.Header.Set(Header, "key4")
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("test body too large", func(t *testing.T) {
+		served = 0
+
+		rr := request(http.MethodPost, "/1.0/update", "key5", strings.Repeat(" ", maxBodySize+1), "192.0.2.1:1234")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Equal(t, 0, served)
+	})
+}
+
+func TestHandler_Secret(t *testing.T) {
+	handler := Handler(New(time.Hour, 10), func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Length", "92")
+		fmt.Fprint(rw, `{"didState":{"identifier":"did:ex:123","secret":{"keys":[{"privateKeyBase58":"key"}]}}}`)
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/register", strings.NewReader("{}"))
+		req.Header.Set(Header, "key1")
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		return rr
+	}
+
+	rr := request()
+	require.Contains(t, rr.Body.String(), "privateKeyBase58")
+
+	rr = request()
+	require.Equal(t, "true", rr.Header().Get(replayedHeader))
+	require.Empty(t, rr.Header().Get("Content-Length"))
+	require.Equal(t, `{"didState":{"identifier":"did:ex:123"}}`+"\n", rr.Body.String())
+}
+
+func TestHandler_Panic(t *testing.T) {
+	s := New(time.Hour, 10)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/update", strings.NewReader("{}"))
+		req.Header.Set(Header, "key1")
+
+		return req
+	}
+
+	require.Panics(t, func() {
+		Handler(s, func(http.ResponseWriter, *http.Request) {
+			panic("handler failed")
+		})(httptest.NewRecorder(), newRequest())
+	})
+
+	require.Empty(t, s.responses)
+
+	rr := httptest.NewRecorder()
+	Handler(s, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusCreated)
+	})(rr, newRequest())
+	require.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestStore_MaxSize(t *testing.T) {
+	now := time.Now()
+
+	s := New(time.Minute, 2)
+	s.now = func() time.Time { return now }
+
+	_, err := s.start("key1", [32]byte{1})
+	require.NoError(t, err)
+
+	_, err = s.start("key2", [32]byte{1})
+	require.NoError(t, err)
+
+	_, err = s.start("key3", [32]byte{1})
+	require.True(t, errors.Is(err, errStoreFull))
+
+	s.finish("key1", &response{status: http.StatusOK})
+
+	now = now.Add(time.Second)
+
+	s.finish("key2", &response{status: http.StatusOK})
+
+	// the response closest to expiring is evicted
+	_, err = s.start("key3", [32]byte{1})
+	require.NoError(t, err)
+	require.Len(t, s.responses, 2)
+	require.NotContains(t, s.responses, "key1")
+	require.Contains(t, s.responses, "key2")
+}
+
+func TestHandler_InProgress(t *testing.T) {
+	s := New(time.Hour, 10)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/update", strings.NewReader("{}"))
+		req.Header.Set(Header, "key1")
+
+		return req
+	}
+
+	// the request is retried while the first one is served
+	retried := httptest.NewRecorder()
+
+	Handler(s, func(http.ResponseWriter, *http.Request) {
+		Handler(s, func(http.ResponseWriter, *http.Request) {})(retried, newRequest())
+	})(httptest.NewRecorder(), newRequest())
+
+	require.Equal(t, http.StatusConflict, retried.Code)
+	require.Contains(t, retried.Body.String(), errorCodeInProgress)
+}
+
+func TestStore_Expiry(t *testing.T) {
+	now := time.Now()
+
+	s := New(time.Minute, 10)
+	s.now = func() time.Time { return now }
+
+	r, err := s.start("key1", [32]byte{1})
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	s.finish("key1", &response{status: http.StatusOK})
+
+	r, err = s.start("key1", [32]byte{1})
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	now = now.Add(time.Minute)
+
+	r, err = s.start("key1", [32]byte{2})
+	require.NoError(t, err)
+	require.Nil(t, r)
+	require.Len(t, s.responses, 1)
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
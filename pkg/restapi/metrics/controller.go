@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/metrics/operation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+// New returns new controller instance, exposing the metrics of the registry.
+func New(registry *metrics.Registry) *Controller {
+	var allHandlers []operation.Handler
+
+	metricsService := operation.New(registry)
+
+	handlers := metricsService.GetRESTHandlers()
+
+	allHandlers = append(allHandlers, handlers...)
+
+	return &Controller{handlers: allHandlers}
+}
+
+// Controller contains handlers for controller.
+type Controller struct {
+	handlers []operation.Handler
+}
+
+// GetOperations returns all controller endpoints.
+func (c *Controller) GetOperations() []operation.Handler {
+	return c.handlers
+}
@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+func TestController_New(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		controller := New(metrics.NewRegistry())
+		require.NotNil(t, controller)
+		ops := controller.GetOperations()
+
+		require.Equal(t, 1, len(ops))
+	})
+}
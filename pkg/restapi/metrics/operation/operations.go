@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/common/support"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+// API endpoints.
+const (
+	metricsEndpoint = "/metrics"
+
+	textFormatContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// HTTP request metrics.
+const (
+	// HTTPRequests counts the requests handled by an endpoint
+	HTTPRequests = "http_requests_total"
+	// HTTPRequestDuration observes the latency of the requests handled by an endpoint
+	HTTPRequestDuration = "http_request_duration_seconds"
+
+	// LabelPath is the label holding the path template of the endpoint
+	LabelPath = "path"
+	// LabelMethod is the label holding the HTTP method of the endpoint
+	LabelMethod = "method"
+	// LabelCode is the label holding the HTTP status code of the response
+	LabelCode = "code"
+)
+
+// Handler http handler for each controller API endpoint.
+type Handler interface {
+	Path() string
+	Method() string
+	Handle() http.HandlerFunc
+}
+
+// New returns metrics operation instance.
+func New(registry *metrics.Registry) *Operation {
+	return &Operation{registry: registry}
+}
+
+// Operation defines handlers for metrics operations.
+type Operation struct {
+	registry *metrics.Registry
+}
+
+// GetRESTHandlers get all controller API handler available for this service.
+func (o *Operation) GetRESTHandlers() []Handler {
+	return []Handler{
+		support.NewHTTPHandler(metricsEndpoint, http.MethodGet, o.metricsHandler),
+	}
+}
+
+func (o *Operation) metricsHandler(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", textFormatContentType)
+	rw.WriteHeader(http.StatusOK)
+
+	if err := o.registry.WriteText(rw); err != nil {
+		log.Errorf("metrics response failure, %s", err)
+	}
+}
+
+// InstrumentHandler records the count, status codes and latency of the requests handled by the handler of the
+// endpoint with the path template and method.
+func InstrumentHandler(p metrics.Provider, path, method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+		handler(recorder, req)
+
+		p.IncrementCounter(HTTPRequests, map[string]string{LabelPath: path, LabelMethod: method,
+			LabelCode: strconv.Itoa(recorder.status)})
+		p.ObserveDuration(HTTPRequestDuration, time.Since(start), map[string]string{LabelPath: path,
+			LabelMethod: method})
+	}
+}
+
+// statusRecorder records the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+func TestGetRESTHandlers(t *testing.T) {
+	c := New(metrics.NewRegistry())
+	require.Equal(t, 1, len(c.GetRESTHandlers()))
+}
+
+func TestInstrumentHandler(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	handler := InstrumentHandler(registry, "/1.0/identifiers/{did}", http.MethodGet,
+		func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+		})
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/1.0/identifiers/did:trustbloc:a:b", nil))
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/1.0/identifiers/did:trustbloc:a:c", nil))
+
+	c := New(registry)
+
+	rr := httptest.NewRecorder()
+	c.metricsHandler(rr, nil)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, textFormatContentType, rr.Header().Get("Content-Type"))
+	require.Contains(t, rr.Body.String(),
+		`http_requests_total{code="404",method="GET",path="/1.0/identifiers/{did}"} 2`)
+	require.Contains(t, rr.Body.String(),
+		`http_request_duration_seconds_count{method="GET",path="/1.0/identifiers/{did}"} 2`)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/openapi/operation"
+)
+
+// New returns new controller instance, serving the OpenAPI document of the REST API.
+func New() (*Controller, error) {
+	var allHandlers []operation.Handler
+
+	openAPIService, err := operation.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create openapi service: %w", err)
+	}
+
+	handlers := openAPIService.GetRESTHandlers()
+
+	allHandlers = append(allHandlers, handlers...)
+
+	return &Controller{handlers: allHandlers, openAPIService: openAPIService}, nil
+}
+
+// Controller contains handlers for controller.
+type Controller struct {
+	handlers       []operation.Handler
+	openAPIService *operation.Operation
+}
+
+// GetOperations returns all controller endpoints.
+func (c *Controller) GetOperations() []operation.Handler {
+	return c.handlers
+}
+
+// Validate returns the handler validating the requests to the endpoint against the OpenAPI document.
+func (c *Controller) Validate(path, method string, handler http.HandlerFunc) http.HandlerFunc {
+	return c.openAPIService.Validate(path, method, handler)
+}
@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_New(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		controller, err := New()
+		require.NoError(t, err)
+		require.NotNil(t, controller)
+		ops := controller.GetOperations()
+
+		require.Equal(t, 1, len(ops))
+	})
+}
+
+func TestController_Validate(t *testing.T) {
+	controller, err := New()
+	require.NoError(t, err)
+
+	handler := controller.Validate("/1.0/deactivate", http.MethodPost, func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/1.0/deactivate", strings.NewReader(`{"identifier":1}`)))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Command gen generates the OpenAPI document of the REST API, served by the service, from the request and response
+// models of the did method operations.
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/openapi/operation"
+)
+
+const (
+	outputFile = "spec.gen.go"
+
+	outputTemplate = `/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Code generated by go generate from the did method operation models. DO NOT EDIT.
+
+package operation
+
+// specJSON is the OpenAPI document of the REST API.
+const specJSON = ` + "`%s`\n"
+)
+
+func main() {
+	spec, err := operation.BuildSpec()
+	if err != nil {
+		log.Fatalf("failed to build openapi document: %s", err)
+	}
+
+	src, err := format.Source([]byte(fmt.Sprintf(outputTemplate, spec)))
+	if err != nil {
+		log.Fatalf("failed to format openapi document source: %s", err)
+	}
+
+	if err := ioutil.WriteFile(outputFile, src, 0600); err != nil { //nolint: gomnd
+		log.Fatalf("failed to write %s: %s", outputFile, err)
+	}
+}
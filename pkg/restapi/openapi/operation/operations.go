@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+//go:generate go run ./gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/common/support"
+)
+
+// API endpoints.
+const (
+	openAPIEndpoint = "/openapi.json"
+
+	errorCodeInvalidRequest = "invalidRequest"
+)
+
+// Handler http handler for each controller API endpoint.
+type Handler interface {
+	Path() string
+	Method() string
+	Handle() http.HandlerFunc
+}
+
+// ValidationError is the response to a request which does not match the schema of its endpoint.
+type ValidationError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is an error of a field of a request, the field being the path of the field in the request, such as
+// secret.keys[0].purposes
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New returns OpenAPI operation instance, serving the OpenAPI document generated at build time.
+func New() (*Operation, error) {
+	doc := &document{}
+
+	if err := json.Unmarshal([]byte(specJSON), doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %w", err)
+	}
+
+	return &Operation{doc: doc}, nil
+}
+
+// Operation defines handlers for the OpenAPI document.
+type Operation struct {
+	doc *document
+}
+
+// GetRESTHandlers get all controller API handler available for this service.
+func (o *Operation) GetRESTHandlers() []Handler {
+	return []Handler{
+		support.NewHTTPHandler(openAPIEndpoint, http.MethodGet, o.openAPIHandler),
+	}
+}
+
+func (o *Operation) openAPIHandler(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+
+	if _, err := rw.Write([]byte(specJSON)); err != nil {
+		log.Errorf("Unable to send openapi document, %s", err)
+	}
+}
+
+// Validate returns a handler which validates the JSON body of the requests to the endpoint with the path template
+// and method against the schema of the OpenAPI document, before serving them with the handler. The requests which
+// don't match the schema are rejected with status 400 and the errors of their fields. The handler is returned as is
+// if the endpoint has no request body.
+func (o *Operation) Validate(path, method string, handler http.HandlerFunc) http.HandlerFunc {
+	op := o.doc.Paths[path][strings.ToLower(method)]
+	if op == nil || op.RequestBody == nil || op.RequestBody.Content[jsonContentType] == nil {
+		return handler
+	}
+
+	bodySchema := op.RequestBody.Content[jsonContentType].Schema
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			writeValidationError(rw, &ValidationError{Message: fmt.Sprintf("failed to read request body: %s", err)})
+
+			return
+		}
+
+		var value interface{}
+
+		if err = json.Unmarshal(body, &value); err != nil {
+			writeValidationError(rw, &ValidationError{Message: fmt.Sprintf("request body is not valid JSON: %s", err)})
+
+			return
+		}
+
+		v := &validator{schemas: o.doc.Components.Schemas}
+		v.validate(value, bodySchema, "")
+
+		if len(v.errors) > 0 {
+			writeValidationError(rw, &ValidationError{Message: "request body does not match the schema",
+				Errors: v.errors})
+
+			return
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		handler(rw, req)
+	}
+}
+
+func writeValidationError(rw http.ResponseWriter, validationErr *ValidationError) {
+	validationErr.Code = errorCodeInvalidRequest
+	validationErr.Message = "invalid request: " + validationErr.Message
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusBadRequest)
+
+	if err := json.NewEncoder(rw).Encode(validationErr); err != nil {
+		log.Errorf("Unable to send error response, %s", err)
+	}
+}
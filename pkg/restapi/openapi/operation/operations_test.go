@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const createPath = "/1.0/create"
+
+func TestGetRESTHandlers(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(c.GetRESTHandlers()))
+}
+
+func TestSpecUpToDate(t *testing.T) {
+	spec, err := BuildSpec()
+	require.NoError(t, err)
+	require.Equal(t, string(spec), specJSON, "openapi document is out of date, run go generate")
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	c.openAPIHandler(rr, nil)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	doc := &document{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), doc))
+	require.Equal(t, openAPIVersion, doc.OpenAPI)
+	require.NotNil(t, doc.Paths[createPath]["post"])
+	require.NotNil(t, doc.Components.Schemas["UpdateDIDRequest"])
+}
+
+func TestValidate(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	served := func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		_, err = rw.Write(body)
+		require.NoError(t, err)
+	}
+
+	t.Run("test valid request", func(t *testing.T) {
+		body := `{"jobId":"job1","options":{"mode":"sync"},"secret":{"keys":[{"purposes":["update"],` +
+			`"jwk":{"kty":"OKP"}}]},"didDocument":{"service":[{"id":"svc1","priority":1}]}}`
+
+		rr := serve(c.Validate(createPath, http.MethodPost, served), createPath, body)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, body, rr.Body.String())
+	})
+
+	t.Run("test invalid fields", func(t *testing.T) {
+		body := `{"jobId":1,"secret":{"keys":[{"purposes":["other"]}]},"didDocument":{"service":[{"priority":-1}]}}`
+
+		rr := serve(c.Validate(createPath, http.MethodPost, served), createPath, body)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+
+		validationErr := &ValidationError{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), validationErr))
+		require.Equal(t, errorCodeInvalidRequest, validationErr.Code)
+		require.Contains(t, validationErr.Message, "request body does not match the schema")
+		require.Equal(t, []FieldError{
+			{Field: "didDocument.service[0].priority", Message: "must be greater than or equal to 0"},
+			{Field: "jobId", Message: "must be a string"},
+			{Field: "secret.keys[0].purposes[0]", Message: "must be one of recovery, update, nextUpdate, nextRecovery"},
+		}, validationErr.Errors)
+	})
+
+	t.Run("test invalid json", func(t *testing.T) {
+		rr := serve(c.Validate(createPath, http.MethodPost, served), createPath, "{")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), "request body is not valid JSON")
+	})
+
+	t.Run("test body not an object", func(t *testing.T) {
+		rr := serve(c.Validate(createPath, http.MethodPost, served), createPath, "[]")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), `{"field":"body","message":"must be an object"}`)
+	})
+
+	t.Run("test enum of update operation", func(t *testing.T) {
+		rr := serve(c.Validate("/1.0/update", http.MethodPost, served), "/1.0/update",
+			`{"identifier":"did1","didDocumentOperation":["replace"]}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		require.Contains(t, rr.Body.String(), `"field":"didDocumentOperation[0]"`)
+	})
+
+	t.Run("test endpoint without request body", func(t *testing.T) {
+		rr := serve(c.Validate("/1.0/operations/{id}", http.MethodGet, served), "/1.0/operations/id1", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func serve(handler http.HandlerFunc, path, body string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+
+	return rr
+}
@@ -0,0 +1,266 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	didmethodop "github.com/trustbloc/trustbloc-did-method/pkg/restapi/didmethod/operation"
+)
+
+const (
+	openAPIVersion  = "3.0.3"
+	apiTitle        = "TrustBloc DID Method"
+	apiVersion      = "1.0"
+	schemaRefPrefix = "#/components/schemas/"
+	jsonContentType = "application/json"
+	openAPITag      = "openapi"
+	enumTagPrefix   = "enum="
+)
+
+// document is the subset of an OpenAPI document describing the REST API
+type document struct {
+	OpenAPI    string                               `json:"openapi"`
+	Info       info                                 `json:"info"`
+	Paths      map[string]map[string]*pathOperation `json:"paths"`
+	Components components                           `json:"components"`
+}
+
+type info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type pathOperation struct {
+	Summary     string               `json:"summary"`
+	OperationID string               `json:"operationId"`
+	Parameters  []*parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type components struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+// schema is the subset of a JSON schema describing the request and response models
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+// endpoint is an endpoint of the REST API, with its request and response models
+type endpoint struct {
+	path        string
+	method      string
+	operationID string
+	summary     string
+	parameters  []*parameter
+	request     interface{}
+	response    interface{}
+}
+
+// endpoints are the endpoints of the did method service, in all modes
+func endpoints() []*endpoint {
+	return []*endpoint{
+		{path: "/1.0/register", method: http.MethodPost, operationID: "registerDID",
+			summary: "Register a DID with the public keys and services of the DID document",
+			request: didmethodop.RegisterDIDRequest{}, response: didmethodop.RegisterResponse{}},
+		{path: "/1.0/create", method: http.MethodPost, operationID: "createDID",
+			summary: "Create a DID (Universal Registrar driver)",
+			request: didmethodop.CreateDIDRequest{}, response: didmethodop.RegisterResponse{}},
+		{path: "/1.0/update", method: http.MethodPost, operationID: "updateDID",
+			summary: "Update a DID document (Universal Registrar driver)",
+			request: didmethodop.UpdateDIDRequest{}, response: didmethodop.RegisterResponse{}},
+		{path: "/1.0/recover", method: http.MethodPost, operationID: "recoverDID",
+			summary: "Recover a DID with a new DID document",
+			request: didmethodop.RecoverDIDRequest{}, response: didmethodop.RegisterResponse{}},
+		{path: "/1.0/deactivate", method: http.MethodPost, operationID: "deactivateDID",
+			summary: "Deactivate a DID (Universal Registrar driver)",
+			request: didmethodop.DeactivateDIDRequest{}, response: didmethodop.RegisterResponse{}},
+		{path: "/1.0/operations/{id}", method: http.MethodGet, operationID: "getOperation",
+			summary:    "Get the status of an asynchronous operation",
+			parameters: []*parameter{pathParameter("id")}, response: didmethodop.OperationStatus{}},
+		{path: "/1.0/identifiers/{did}", method: http.MethodGet, operationID: "resolveIdentifier",
+			summary:    "Resolve a DID (Universal Resolver driver), or its document in the Accept header representation",
+			parameters: []*parameter{pathParameter("did")}, response: didmethodop.DIDResolutionResult{}},
+		{path: "/resolveDID", method: http.MethodGet, operationID: "resolveDID",
+			summary: "Resolve a DID",
+			parameters: []*parameter{{Name: "did", In: "query", Required: true,
+				Schema: &schema{Type: "string"}}}},
+		{path: "/1.0/register/challenge", method: http.MethodGet, operationID: "getRegistrationChallenge",
+			summary:  "Get a proof-of-work challenge for a public registration",
+			response: didmethodop.ChallengeResponse{}},
+	}
+}
+
+func pathParameter(name string) *parameter {
+	return &parameter{Name: name, In: "path", Required: true, Schema: &schema{Type: "string"}}
+}
+
+// BuildSpec builds the OpenAPI document of the REST API from the request and response models of the did method
+// operations. The document served by the service is generated with it at build time, see go generate.
+func BuildSpec() ([]byte, error) {
+	b := &specBuilder{schemas: make(map[string]*schema)}
+
+	doc := &document{OpenAPI: openAPIVersion, Info: info{Title: apiTitle, Version: apiVersion},
+		Paths: make(map[string]map[string]*pathOperation)}
+
+	for _, e := range endpoints() {
+		op := &pathOperation{Summary: e.summary, OperationID: e.operationID, Parameters: e.parameters,
+			Responses: map[string]*response{"200": {Description: "OK"}}}
+
+		if e.request != nil {
+			op.RequestBody = &requestBody{Required: true,
+				Content: map[string]*mediaType{jsonContentType: {Schema: b.schema(reflect.TypeOf(e.request))}}}
+
+			op.Responses["400"] = &response{Description: "The request does not match its schema",
+				Content: map[string]*mediaType{jsonContentType: {
+					Schema: b.schema(reflect.TypeOf(ValidationError{}))}}}
+		}
+
+		if e.response != nil {
+			op.Responses["200"].Content = map[string]*mediaType{jsonContentType: {
+				Schema: b.schema(reflect.TypeOf(e.response))}}
+		}
+
+		if doc.Paths[e.path] == nil {
+			doc.Paths[e.path] = make(map[string]*pathOperation)
+		}
+
+		doc.Paths[e.path][strings.ToLower(e.method)] = op
+	}
+
+	doc.Components.Schemas = b.schemas
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// specBuilder builds the schemas of the models, the schemas of the structs being components of the document
+type specBuilder struct {
+	schemas map[string]*schema
+}
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage{}) //nolint: gochecknoglobals
+	timeType       = reflect.TypeOf(time.Time{})       //nolint: gochecknoglobals
+)
+
+func (b *specBuilder) schema(t reflect.Type) *schema {
+	switch t {
+	case rawMessageType:
+		return &schema{Type: "object"}
+	case timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() { //nolint: exhaustive
+	case reflect.Ptr:
+		return b.schema(t.Elem())
+	case reflect.Slice:
+		return &schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Interface {
+			return &schema{Type: "object"}
+		}
+
+		return &schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		return b.component(t)
+	default:
+		return primitiveSchema(t.Kind())
+	}
+}
+
+func primitiveSchema(kind reflect.Kind) *schema {
+	switch kind { //nolint: exhaustive
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		minimum := 0.0
+
+		return &schema{Type: "integer", Minimum: &minimum}
+	default:
+		return &schema{}
+	}
+}
+
+// component adds the schema of the struct to the components, and returns the reference to it
+func (b *specBuilder) component(t reflect.Type) *schema {
+	ref := &schema{Ref: schemaRefPrefix + t.Name()}
+
+	if _, ok := b.schemas[t.Name()]; ok {
+		return ref
+	}
+
+	s := &schema{Type: "object", Properties: make(map[string]*schema)}
+
+	// added before its properties, for the recursive models
+	b.schemas[t.Name()] = s
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.schema(field.Type)
+
+		if enum := strings.TrimPrefix(field.Tag.Get(openAPITag), enumTagPrefix); enum != field.Tag.Get(openAPITag) {
+			if property.Type == "array" {
+				property.Items.Enum = strings.Split(enum, "|")
+			} else {
+				property.Enum = strings.Split(enum, "|")
+			}
+		}
+
+		s.Properties[name] = property
+	}
+
+	return ref
+}
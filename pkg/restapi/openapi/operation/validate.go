@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// validator validates JSON values against the schemas of the OpenAPI document, collecting the errors of the fields
+type validator struct {
+	schemas map[string]*schema
+	errors  []FieldError
+}
+
+// validate validates the value of the field against the schema. A null value is valid for any schema, since it is
+// decoded as the zero value of the field.
+func (v *validator) validate(value interface{}, s *schema, field string) {
+	if s.Ref != "" {
+		s = v.schemas[strings.TrimPrefix(s.Ref, schemaRefPrefix)]
+		if s == nil {
+			return
+		}
+	}
+
+	if value == nil {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		v.validateObject(value, s, field)
+	case "array":
+		v.validateArray(value, s, field)
+	case "string":
+		v.validateString(value, s, field)
+	case "integer", "number":
+		v.validateNumber(value, s, field)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.addError(field, "must be a boolean")
+		}
+	}
+}
+
+func (v *validator) validateObject(value interface{}, s *schema, field string) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		v.addError(field, "must be an object")
+
+		return
+	}
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			v.addError(joinField(field, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			property = s.AdditionalProperties
+		}
+
+		if property != nil {
+			v.validate(object[name], property, joinField(field, name))
+		}
+	}
+}
+
+func (v *validator) validateArray(value interface{}, s *schema, field string) {
+	array, ok := value.([]interface{})
+	if !ok {
+		v.addError(field, "must be an array")
+
+		return
+	}
+
+	if s.Items == nil {
+		return
+	}
+
+	for i, item := range array {
+		v.validate(item, s.Items, fmt.Sprintf("%s[%d]", field, i))
+	}
+}
+
+func (v *validator) validateString(value interface{}, s *schema, field string) {
+	str, ok := value.(string)
+	if !ok {
+		v.addError(field, "must be a string")
+
+		return
+	}
+
+	if len(s.Enum) == 0 {
+		return
+	}
+
+	for _, e := range s.Enum {
+		if str == e {
+			return
+		}
+	}
+
+	v.addError(field, fmt.Sprintf("must be one of %s", strings.Join(s.Enum, ", ")))
+}
+
+func (v *validator) validateNumber(value interface{}, s *schema, field string) {
+	number, ok := value.(float64)
+	if !ok {
+		v.addError(field, "must be a number")
+
+		return
+	}
+
+	if s.Type == "integer" && number != math.Trunc(number) {
+		v.addError(field, "must be an integer")
+
+		return
+	}
+
+	if s.Minimum != nil && number < *s.Minimum {
+		v.addError(field, fmt.Sprintf("must be greater than or equal to %v", *s.Minimum))
+	}
+}
+
+func (v *validator) addError(field, message string) {
+	if field == "" {
+		field = "body"
+	}
+
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+}
+
+func joinField(field, name string) string {
+	if field == "" {
+		return name
+	}
+
+	return field + "." + name
+}
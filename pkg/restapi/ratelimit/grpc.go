@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/grpcapi"
+)
+
+// UnaryInterceptor serves the calls of the clients within their limit, and rejects the other calls with code
+// ResourceExhausted. The limit of the client is sent in the response headers as for the REST requests.
+func UnaryInterceptor(l *Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		allowed, remaining, reset := l.Allow(client(grpcapi.HTTPRequest(ctx, info.FullMethod)))
+
+		header := metadata.Pairs(limitHeader, strconv.Itoa(l.limit), remainingHeader, strconv.Itoa(remaining))
+
+		if !allowed {
+			header.Set(retryHeader, strconv.Itoa(int(math.Ceil(reset.Seconds()))))
+		}
+
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			log.Warnf("failed to set rate limit headers: %s", err)
+		}
+
+		if !allowed {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded, retry later")
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	interceptor := UnaryInterceptor(New(1, time.Minute))
+
+	call := func(ip net.IP) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: ip, Port: 1234}})
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/trustbloc.didmethod.v1.DIDMethod/CreateDID"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			})
+
+		return err
+	}
+
+	require.NoError(t, call(net.IPv4(192, 0, 2, 1)))
+
+	err := call(net.IPv4(192, 0, 2, 1))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	require.NoError(t, call(net.IPv4(192, 0, 2, 2)))
+}
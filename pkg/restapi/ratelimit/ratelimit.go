@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+)
+
+const (
+	errorCodeRateLimited = "rateLimited"
+
+	limitHeader     = "X-RateLimit-Limit"
+	remainingHeader = "X-RateLimit-Remaining"
+	retryHeader     = "Retry-After"
+)
+
+// Limiter is a fixed window rate limiter keyed by client
+type Limiter struct {
+	limit     int
+	window    time.Duration
+	mutex     sync.Mutex
+	clients   map[string]*limiterWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type limiterWindow struct {
+	start time.Time
+	count int
+}
+
+// New returns a limiter allowing each client the limit of requests per window
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, clients: make(map[string]*limiterWindow), now: time.Now}
+}
+
+// Allow counts a request of the client, and returns whether it is allowed, the number of requests the client has
+// left in the window, and the time until the window ends
+func (l *Limiter) Allow(client string) (bool, int, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+
+	// forget the clients whose window ended, at most once per window
+	if now.Sub(l.lastSweep) >= l.window {
+		for c, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, c)
+			}
+		}
+
+		l.lastSweep = now
+	}
+
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &limiterWindow{start: now}
+		l.clients[client] = w
+	}
+
+	reset := w.start.Add(l.window).Sub(now)
+
+	if w.count >= l.limit {
+		return false, 0, reset
+	}
+
+	w.count++
+
+	return true, l.limit - w.count, reset
+}
+
+// Handler returns a handler which serves the requests of the clients within their limit with the handler, and
+// rejects the other requests with status 429. Clients are identified by their authenticated identity, or else by
+// their IP address.
+func Handler(l *Limiter, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		allowed, remaining, reset := l.Allow(client(req))
+
+		rw.Header().Set(limitHeader, strconv.Itoa(l.limit))
+		rw.Header().Set(remainingHeader, strconv.Itoa(remaining))
+
+		if allowed {
+			handler(rw, req)
+
+			return
+		}
+
+		rw.Header().Set(retryHeader, strconv.Itoa(int(math.Ceil(reset.Seconds()))))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusTooManyRequests)
+
+		err := json.NewEncoder(rw).Encode(map[string]string{"code": errorCodeRateLimited,
+			"message": "rate limit exceeded, retry later"})
+		if err != nil {
+			log.Errorf("Unable to send error response, %s", err)
+		}
+	}
+}
+
+func client(req *http.Request) string {
+	if clientID := auth.ClientID(req.Context()); clientID != "" {
+		return "client:" + clientID
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return "ip:" + req.RemoteAddr
+	}
+
+	return "ip:" + host
+}
@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/auth"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	allowed, remaining, _ := l.Allow("client1")
+	require.True(t, allowed)
+	require.Equal(t, 1, remaining)
+
+	allowed, remaining, _ = l.Allow("client1")
+	require.True(t, allowed)
+	require.Equal(t, 0, remaining)
+
+	allowed, _, reset := l.Allow("client1")
+	require.False(t, allowed)
+	require.Equal(t, time.Minute, reset)
+
+	allowed, _, _ = l.Allow("client2")
+	require.True(t, allowed)
+
+	now = now.Add(time.Minute)
+
+	allowed, _, _ = l.Allow("client1")
+	require.True(t, allowed)
+	require.Len(t, l.clients, 1)
+}
+
+func TestHandler(t *testing.T) {
+	l := New(1, time.Minute)
+
+	handler := Handler(l, func(rw http.ResponseWriter, _ *http.Request) {})
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.RemoteAddr = remoteAddr
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		return rr
+	}
+
+	rr := request("10.0.0.1:1234")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "1", rr.Header().Get(limitHeader))
+	require.Equal(t, "0", rr.Header().Get(remainingHeader))
+
+	rr = request("10.0.0.1:5678")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	require.Equal(t, "60", rr.Header().Get(retryHeader))
+	require.Contains(t, rr.Body.String(), errorCodeRateLimited)
+
+	rr = request("10.0.0.2:1234")
+	require.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestHandlerWithAuthenticatedClient(t *testing.T) {
+	apiKeys, err := auth.NewAPIKeyAuthenticator([]string{"wallet=key1"})
+	require.NoError(t, err)
+
+	handler := auth.Handler([]auth.Authenticator{apiKeys},
+		Handler(New(1, time.Minute), func(rw http.ResponseWriter, _ *http.Request) {}))
+
+	for i, remoteAddr := range []string{"10.0.0.1:1234", "10.0.0.2:1234"} {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/create", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-API-Key", "key1")
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		if i == 0 {
+			require.Equal(t, http.StatusOK, rr.Code)
+		} else {
+			require.Equal(t, http.StatusTooManyRequests, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wellknown
+
+import (
+	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/wellknown/operation"
+)
+
+// New returns new controller instance, hosting the consortium and stakeholder config files of the source.
+func New(source operation.Source) *Controller {
+	var allHandlers []operation.Handler
+
+	wellKnownService := operation.New(source)
+
+	handlers := wellKnownService.GetRESTHandlers()
+
+	allHandlers = append(allHandlers, handlers...)
+
+	return &Controller{handlers: allHandlers}
+}
+
+// Controller contains handlers for controller.
+type Controller struct {
+	handlers []operation.Handler
+}
+
+// GetOperations returns all controller endpoints.
+func (c *Controller) GetOperations() []operation.Handler {
+	return c.handlers
+}
@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/internal/common/support"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+// API endpoints.
+const (
+	configFileEndpoint = "/.well-known/" + configDirectory + "/{file}"
+
+	// the config files are JWS, in the compact or the JSON serialization
+	jwsCompactContentType = "application/jose"
+	jwsJSONContentType    = "application/jose+json"
+)
+
+// Handler http handler for each controller API endpoint.
+type Handler interface {
+	Path() string
+	Method() string
+	Handle() http.HandlerFunc
+}
+
+// New returns well-known operation instance, hosting the config files of the source.
+func New(source Source) *Operation {
+	return &Operation{source: source}
+}
+
+// Operation defines handlers for the hosting of the consortium and stakeholder config files.
+type Operation struct {
+	source Source
+}
+
+// GetRESTHandlers get all controller API handler available for this service.
+func (o *Operation) GetRESTHandlers() []Handler {
+	return []Handler{
+		support.NewHTTPHandler(configFileEndpoint, http.MethodGet, o.configFileHandler),
+	}
+}
+
+// configFileHandler serves the config file, with the cache lifetime of its policy in the Cache-Control header, and
+// an ETag so that the clients checking for a new version of the file get it only if it changed
+func (o *Operation) configFileHandler(rw http.ResponseWriter, req *http.Request) {
+	fileName := mux.Vars(req)["file"]
+
+	name := strings.TrimSuffix(fileName, configSuffix)
+	if name == fileName || !validName(name) {
+		http.NotFound(rw, req)
+
+		return
+	}
+
+	file, err := o.source.File(name)
+	if err != nil {
+		if errors.Is(err, ErrFileNotFound) {
+			http.NotFound(rw, req)
+
+			return
+		}
+
+		log.Errorf("failed to get config file %s: %s", name, err.Error())
+
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	hash := sha256.Sum256(file.Content)
+
+	rw.Header().Set("Content-Type", contentType(file.Content))
+	rw.Header().Set("Cache-Control", cacheControl(file.Content))
+	rw.Header().Set("ETag", `"`+hex.EncodeToString(hash[:])+`"`)
+
+	http.ServeContent(rw, req, fileName, file.ModTime, bytes.NewReader(file.Content))
+}
+
+// validName returns whether the name is a domain name, rather than a path out of the config directory
+func validName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, ".") && !strings.ContainsAny(name, `/\`)
+}
+
+func contentType(content []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return jwsJSONContentType
+	}
+
+	return jwsCompactContentType
+}
+
+// cacheControl returns the Cache-Control header of the config file, whose clients are to check for a new version
+// once the cache lifetime of its policy expires, or at once if it has none
+func cacheControl(content []byte) string {
+	jws, err := jose.ParseSigned(string(content))
+	if err != nil {
+		return "no-cache"
+	}
+
+	var config struct {
+		Policy struct {
+			Cache models.CacheControl `json:"cache"`
+		} `json:"policy"`
+	}
+
+	if err = json.Unmarshal(jws.UnsafePayloadWithoutVerification(), &config); err != nil ||
+		config.Policy.Cache.MaxAge == 0 {
+		return "no-cache"
+	}
+
+	return fmt.Sprintf("public, max-age=%d", config.Policy.Cache.MaxAge)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSource struct {
+	err error
+}
+
+func (s *mockSource) File(name string) (*File, error) {
+	return nil, s.err
+}
+
+func TestConfigFileHandler(t *testing.T) {
+	consortium := sign(t, `{"domain":"consortium.net","policy":{"cache":{"maxAge":600}},"members":[]}`)
+
+	t.Run("test config file from memory", func(t *testing.T) {
+		rr := serve(t, NewMemorySource(map[string][]byte{"consortium.net": []byte(consortium)}),
+			"/.well-known/did-trustbloc/consortium.net.json", "")
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, consortium, rr.Body.String())
+		require.Equal(t, jwsCompactContentType, rr.Header().Get("Content-Type"))
+		require.Equal(t, "public, max-age=600", rr.Header().Get("Cache-Control"))
+		require.NotEmpty(t, rr.Header().Get("ETag"))
+		require.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+		rr = serve(t, NewMemorySource(map[string][]byte{"consortium.net": []byte(consortium)}),
+			"/.well-known/did-trustbloc/consortium.net.json", rr.Header().Get("ETag"))
+		require.Equal(t, http.StatusNotModified, rr.Code)
+	})
+
+	t.Run("test config file from directory", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "wellknown")
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, configDirectory), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, configDirectory, "stakeholder.one.json"),
+			[]byte(`{"payload":"e30","signatures":[]}`), 0600))
+
+		rr := serve(t, NewDirSource(dir), "/.well-known/did-trustbloc/stakeholder.one.json", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, jwsJSONContentType, rr.Header().Get("Content-Type"))
+		require.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+
+		rr = serve(t, NewDirSource(dir), "/.well-known/did-trustbloc/stakeholder.two.json", "")
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("test invalid file names", func(t *testing.T) {
+		source := NewMemorySource(map[string][]byte{"consortium.net": []byte(consortium)})
+
+		for _, path := range []string{"/.well-known/did-trustbloc/consortium.net",
+			"/.well-known/did-trustbloc/..json", "/.well-known/did-trustbloc/.json"} {
+			rr := serve(t, source, path, "")
+			require.Equal(t, http.StatusNotFound, rr.Code, path)
+		}
+	})
+
+	t.Run("test error from source", func(t *testing.T) {
+		rr := serve(t, &mockSource{err: errors.New("source error")},
+			"/.well-known/did-trustbloc/consortium.net.json", "")
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func serve(t *testing.T, source Source, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := mux.NewRouter()
+
+	for _, handler := range New(source).GetRESTHandlers() {
+		router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+	}
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	require.NoError(t, err)
+
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func sign(t *testing.T, payload string) string {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.EdDSA, Key: privateKey}, nil)
+	require.NoError(t, err)
+
+	jws, err := signer.Sign([]byte(payload))
+	require.NoError(t, err)
+
+	compact, err := jws.CompactSerialize()
+	require.NoError(t, err)
+
+	return compact
+}
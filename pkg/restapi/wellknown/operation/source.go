@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operation
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	configDirectory = "did-trustbloc"
+	configSuffix    = ".json"
+)
+
+// ErrFileNotFound is returned by a source which doesn't have the config file
+var ErrFileNotFound = errors.New("config file not found")
+
+// File is a consortium or stakeholder config file
+type File struct {
+	Content []byte
+	// ModTime is the modification time of the file, unknown if zero
+	ModTime time.Time
+}
+
+// Source holds the config files hosted by the server, named by the domain of their consortium or stakeholder
+type Source interface {
+	File(name string) (*File, error)
+}
+
+// NewDirSource returns a source reading the config files from a directory laid out as the .well-known directory of a
+// web server, as written by the create-config command, ie. <dir>/did-trustbloc/<domain>.json. The files are read
+// on each request, so that the configs updated in the directory are hosted without restarting the server.
+func NewDirSource(dir string) Source {
+	return &dirSource{dir: dir}
+}
+
+type dirSource struct {
+	dir string
+}
+
+func (s *dirSource) File(name string) (*File, error) {
+	path := filepath.Join(s.dir, configDirectory, name+configSuffix)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrFileNotFound
+		}
+
+		return nil, fmt.Errorf("failed to stat config file %s: %w", name, err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", name, err)
+	}
+
+	return &File{Content: content, ModTime: info.ModTime()}, nil
+}
+
+// NewMemorySource returns a source of config files generated in memory, such as by a service creating the configs
+// of its consortium at startup, given a map of the domains to their file content
+func NewMemorySource(files map[string][]byte) Source {
+	return &memorySource{files: files, modTime: time.Now()}
+}
+
+type memorySource struct {
+	files   map[string][]byte
+	modTime time.Time
+}
+
+func (s *memorySource) File(name string) (*File, error) {
+	content, ok := s.files[name]
+	if !ok {
+		return nil, ErrFileNotFound
+	}
+
+	return &File{Content: content, ModTime: s.modTime}, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package harness
+
+import (
+	"fmt"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+// KMSKeyType returns the kms key type of the Ed25519 or P256 key type
+func KMSKeyType(keyType string) (kms.KeyType, error) {
+	switch keyType {
+	case doc.Ed25519KeyType:
+		return kms.ED25519Type, nil
+	case doc.P256KeyType:
+		return kms.ECDSAP256TypeIEEEP1363, nil
+	}
+
+	return "", fmt.Errorf("key type not supported: %s", keyType)
+}
+
+// CheckDID checks the resolved DID document is the document of the DID
+func CheckDID(didDoc *docdid.Doc, did string) error {
+	if didDoc.ID != did {
+		return fmt.Errorf("resolved did %s not equal to created did %s", didDoc.ID, did)
+	}
+
+	return nil
+}
+
+// CheckService checks the first service of the resolved DID document has the service ID, relative to the DID
+func CheckService(didDoc *docdid.Doc, serviceID string) error {
+	if len(didDoc.Service) == 0 {
+		return fmt.Errorf("resolved did has no service")
+	}
+
+	if didDoc.Service[0].ID != didDoc.ID+"#"+serviceID {
+		return fmt.Errorf("resolved did service ID %s not equal to %s",
+			didDoc.Service[0].ID, didDoc.ID+"#"+serviceID)
+	}
+
+	return nil
+}
+
+// CheckPublicKey checks the resolved DID document has a single verification method, of the Ed25519 or P256 key
+// type with the signature suite, whose ID is the KID of the public key
+func CheckPublicKey(didDoc *docdid.Doc, keyType, signatureSuite string) error {
+	if len(didDoc.VerificationMethod) != 1 {
+		return fmt.Errorf("veification method size not equal one")
+	}
+
+	kt, err := KMSKeyType(keyType)
+	if err != nil {
+		return err
+	}
+
+	expectedJwkKeyType := "OKP"
+	if keyType == doc.P256KeyType {
+		expectedJwkKeyType = "EC"
+	}
+
+	jwk := didDoc.VerificationMethod[0].JSONWebKey()
+
+	jwkKeyType := ""
+	if jwk != nil {
+		jwkKeyType = jwk.Kty
+	}
+
+	if signatureSuite == doc.JWSVerificationKey2020 && expectedJwkKeyType != jwkKeyType {
+		return fmt.Errorf("jwk key type : expected=%s actual=%s", expectedJwkKeyType, jwkKeyType)
+	}
+
+	if signatureSuite == doc.Ed25519VerificationKey2018 && jwk != nil {
+		return fmt.Errorf("jwk is not nil for %s", signatureSuite)
+	}
+
+	return checkPublicKeyAndType(didDoc, kt, signatureSuite)
+}
+
+func checkPublicKeyAndType(didDoc *docdid.Doc, kt kms.KeyType, signatureSuite string) error {
+	pubKeyID, err := localkms.CreateKID(didDoc.VerificationMethod[0].Value, kt)
+	if err != nil {
+		return err
+	}
+
+	if didDoc.VerificationMethod[0].ID != didDoc.ID+"#"+pubKeyID {
+		return fmt.Errorf("resolved did public key ID %s not equal to %s",
+			didDoc.VerificationMethod[0].ID, didDoc.ID+"#"+pubKeyID)
+	}
+
+	if didDoc.VerificationMethod[0].Type != signatureSuite {
+		return fmt.Errorf("resolved did public key type %s not equal to %s",
+			didDoc.VerificationMethod[0].Type, signatureSuite)
+	}
+
+	return nil
+}
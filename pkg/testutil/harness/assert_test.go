@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package harness
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+)
+
+func TestCheckDocument(t *testing.T) {
+	const did = "did:trustbloc:domain:123"
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	kid, err := localkms.CreateKID(pubKey, kms.ED25519Type)
+	require.NoError(t, err)
+
+	didDoc := &docdid.Doc{ID: did,
+		VerificationMethod: []docdid.VerificationMethod{{ID: did + "#" + kid, Type: doc.Ed25519VerificationKey2018,
+			Controller: did, Value: pubKey}},
+		Service: []docdid.Service{{ID: did + "#service"}}}
+
+	t.Run("test success", func(t *testing.T) {
+		require.NoError(t, CheckDID(didDoc, did))
+		require.NoError(t, CheckService(didDoc, "service"))
+		require.NoError(t, CheckPublicKey(didDoc, doc.Ed25519KeyType, doc.Ed25519VerificationKey2018))
+	})
+
+	t.Run("test other DID", func(t *testing.T) {
+		require.EqualError(t, CheckDID(didDoc, "did:trustbloc:domain:456"),
+			"resolved did "+did+" not equal to created did did:trustbloc:domain:456")
+	})
+
+	t.Run("test other service", func(t *testing.T) {
+		err := CheckService(didDoc, "other")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not equal to "+did+"#other")
+
+		require.EqualError(t, CheckService(&docdid.Doc{ID: did}, "service"), "resolved did has no service")
+	})
+
+	t.Run("test other public key", func(t *testing.T) {
+		err := CheckPublicKey(didDoc, doc.Ed25519KeyType, doc.JWSVerificationKey2020)
+		require.EqualError(t, err, "jwk key type : expected=OKP actual=")
+
+		err = CheckPublicKey(didDoc, "RSA", doc.Ed25519VerificationKey2018)
+		require.EqualError(t, err, "key type not supported: RSA")
+
+		err = CheckPublicKey(&docdid.Doc{ID: did}, doc.Ed25519KeyType, doc.Ed25519VerificationKey2018)
+		require.EqualError(t, err, "veification method size not equal one")
+	})
+}
@@ -4,7 +4,7 @@ Copyright SecureKey Technologies Inc. All Rights Reserved.
 SPDX-License-Identifier: Apache-2.0
 */
 
-package dockerutil
+package harness
 
 import (
 	"crypto/rand"
@@ -14,16 +14,12 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-
-	docker "github.com/fsouza/go-dockerclient"
 )
 
 const dockerComposeCommand = "docker-compose"
 
 // Composition represents a docker-compose execution and management
 type Composition struct {
-	DockerClient     *docker.Client
-	APIContainers    []*docker.APIContainers
 	ComposeFilesYaml string
 	ProjectName      string
 	Dir              string
@@ -37,20 +33,13 @@ func NewComposition(projectName, composeFilesYaml, dir string) (composition *Com
 			projectName, composeFilesYaml, err)
 	}
 
-	endpoint := "unix:///var/run/docker.sock"
-	composition = &Composition{ComposeFilesYaml: composeFilesYaml, ProjectName: projectName, Dir: dir}
-
-	if composition.DockerClient, err = docker.NewClient(endpoint); err != nil {
-		return nil, errRetFunc()
-	}
+	composition = &Composition{ComposeFilesYaml: composeFilesYaml, ProjectName: projectName, Dir: dir,
+		DockerHelper: NewDockerCmdlineHelper()}
 
 	if _, err = composition.issueCommand([]string{"up", "--force-recreate", "-d"}, dir); err != nil {
 		return nil, errRetFunc()
 	}
 
-	composition.DockerHelper = NewDockerCmdlineHelper()
-
-	// Now parse the current system
 	return composition, nil
 }
 
@@ -81,24 +70,6 @@ func (c *Composition) GetContainerIDs(dir string) (containerIDs []string, err er
 	return containerIDs, err
 }
 
-func (c *Composition) refreshContainerList() (err error) {
-	var thisProjectsContainers []docker.APIContainers
-
-	if thisProjectsContainers, err = c.DockerClient.ListContainers(
-		docker.ListContainersOptions{
-			All:     true,
-			Filters: map[string][]string{"name": {c.ProjectName}}}); err != nil {
-		return fmt.Errorf("error refreshing container list for project '%s':  %s", c.ProjectName, err)
-	}
-
-	c.APIContainers = make([]*docker.APIContainers, len(thisProjectsContainers))
-	for i := 0; i < len(thisProjectsContainers); i++ {
-		c.APIContainers[i] = &thisProjectsContainers[i]
-	}
-
-	return nil
-}
-
 func (c *Composition) issueCommand(args []string, dir string) (_ []byte, err error) {
 	var cmdOut []byte
 
@@ -116,11 +87,6 @@ func (c *Composition) issueCommand(args []string, dir string) (_ []byte, err err
 		return cmdOut, errRetFunc()
 	}
 
-	// Reparse Container list
-	if err = c.refreshContainerList(); err != nil {
-		return nil, errRetFunc()
-	}
-
 	return cmdOut, err
 }
 
@@ -170,34 +136,26 @@ func (c *Composition) GenerateLogs(dir, logName string) error {
 	return nil
 }
 
-// GetAPIContainerForComposeService return the docker.APIContainers with the supplied composeService name.
-func (c *Composition) GetAPIContainerForComposeService(
-	composeService string) (apiContainer *docker.APIContainers, err error) {
-	for _, apiContainer := range c.APIContainers {
-		if currComposeService, ok := apiContainer.Labels["com.docker.compose.service"]; ok {
-			if currComposeService == composeService {
-				return apiContainer, nil
-			}
-		}
-	}
-
-	return nil, fmt.Errorf("could not find container with compose service '%s'", composeService)
-}
-
 // GetIPAddressForComposeService returns the IPAddress of the container with the supplied composeService name.
 func (c *Composition) GetIPAddressForComposeService(composeService string) (ipAddress string, err error) {
 	errRetFunc := func() error {
 		return fmt.Errorf("error getting IPAddress for compose service '%s':  %s", composeService, err)
 	}
 
-	var apiContainer *docker.APIContainers
+	var cmdOutput []byte
+
+	if cmdOutput, err = c.issueCommand([]string{"ps", "-q", composeService}, c.Dir); err != nil {
+		return "", errRetFunc()
+	}
+
+	containerIDs := splitDockerCommandResults(string(cmdOutput))
+	if len(containerIDs) == 0 {
+		err = fmt.Errorf("could not find container with compose service '%s'", composeService)
 
-	if apiContainer, err = c.GetAPIContainerForComposeService(composeService); err != nil {
 		return "", errRetFunc()
 	}
 
-	// Now get the IPAddress
-	return apiContainer.Networks.Networks["bridge"].IPAddress, nil
+	return c.DockerHelper.GetIPAddress(containerIDs[0])
 }
 
 // GenerateBytesUUID returns a UUID based on RFC 4122 returning the generated bytes
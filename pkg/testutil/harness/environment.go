@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package harness provides the docker-compose orchestration, the resolution retries and the DID document assertions
+// of the BDD tests, for the end-to-end tests of projects creating and resolving did:trustbloc DIDs with their own
+// docker-compose fixtures.
+package harness
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultComposeFile  = "docker-compose.yml"
+	defaultStartupDelay = 20 * time.Second
+)
+
+// Option is an environment option
+type Option func(opts *Environment)
+
+// WithProjectName sets the project name of the compositions, which defaults to a generated UUID
+func WithProjectName(projectName string) Option {
+	return func(opts *Environment) {
+		opts.projectName = projectName
+	}
+}
+
+// WithComposeFile sets the compose file of the compositions, which defaults to docker-compose.yml
+func WithComposeFile(composeFile string) Option {
+	return func(opts *Environment) {
+		opts.composeFile = composeFile
+	}
+}
+
+// WithStartupDelay sets the time waited for the containers to start after each Compose, which defaults to 20s
+func WithStartupDelay(delay time.Duration) Option {
+	return func(opts *Environment) {
+		opts.startupDelay = delay
+	}
+}
+
+// Environment is a set of docker-compose compositions started under one project name
+type Environment struct {
+	projectName  string
+	composeFile  string
+	startupDelay time.Duration
+	compositions []*Composition
+}
+
+// NewEnvironment returns a new environment, with no composition started
+func NewEnvironment(opts ...Option) *Environment {
+	// Need a unique name, but docker does not allow '-' in names
+	e := &Environment{projectName: fmt.Sprintf("%x", GenerateBytesUUID()), composeFile: defaultComposeFile,
+		startupDelay: defaultStartupDelay}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// ProjectName returns the project name of the compositions
+func (e *Environment) ProjectName() string {
+	return e.projectName
+}
+
+// Compose composes the compose file of each of the dirs in their order, then waits for the containers to start.
+// It may be called several times, to start compositions depending on the configuration generated by the former ones.
+func (e *Environment) Compose(dirs ...string) error {
+	for _, dir := range dirs {
+		composition, err := NewComposition(e.projectName, e.composeFile, dir)
+		if err != nil {
+			return fmt.Errorf("error composing system: %w", err)
+		}
+
+		e.compositions = append(e.compositions, composition)
+	}
+
+	fmt.Printf("docker-compose up ... waiting %s for containers to start ...\n", e.startupDelay)
+	time.Sleep(e.startupDelay)
+
+	return nil
+}
+
+// Decompose appends the logs of the compositions to the log file, then decomposes all the compositions
+func (e *Environment) Decompose(logName string) error {
+	var errs []string
+
+	for _, c := range e.compositions {
+		if err := c.GenerateLogs(c.Dir, logName); err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		if _, err := c.Decompose(c.Dir); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	e.compositions = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("error decomposing system: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package harness
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+)
+
+const didNotFound = "DID does not exist"
+
+// Resolver resolves DIDs, as the trustbloc VDRI does
+type Resolver interface {
+	Read(did string, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error)
+}
+
+// ResolveDID resolves the DID with up to maxRetry attempts, waiting for the interval between them while the DID is
+// not found, as a created DID only resolves once its operation is anchored. Other errors are returned at once.
+func ResolveDID(resolver Resolver, did string, maxRetry int, interval time.Duration) (*docdid.DocResolution, error) {
+	var (
+		result *docdid.DocResolution
+		err    error
+	)
+
+	for i := 1; i <= maxRetry; i++ {
+		result, err = resolver.Read(did)
+		if err == nil || !isNotFound(err) {
+			return result, err
+		}
+
+		if i < maxRetry {
+			time.Sleep(interval)
+		}
+	}
+
+	return nil, err
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, vdrapi.ErrNotFound) || strings.Contains(err.Error(), didNotFound)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package harness
+
+import (
+	"errors"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+)
+
+type mockResolver struct {
+	errs  []error
+	reads int
+}
+
+func (m *mockResolver) Read(did string, _ ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	m.reads++
+
+	if m.reads <= len(m.errs) {
+		return nil, m.errs[m.reads-1]
+	}
+
+	return &docdid.DocResolution{DIDDocument: &docdid.Doc{ID: did}}, nil
+}
+
+func TestResolveDID(t *testing.T) {
+	t.Run("test resolved once anchored", func(t *testing.T) {
+		resolver := &mockResolver{errs: []error{errors.New("DID does not exist for request"), vdrapi.ErrNotFound}}
+
+		result, err := ResolveDID(resolver, "did:trustbloc:domain:123", 3, 0)
+		require.NoError(t, err)
+		require.Equal(t, "did:trustbloc:domain:123", result.DIDDocument.ID)
+		require.Equal(t, 3, resolver.reads)
+	})
+
+	t.Run("test error from DID not found after the retries", func(t *testing.T) {
+		resolver := &mockResolver{errs: []error{vdrapi.ErrNotFound, vdrapi.ErrNotFound}}
+
+		_, err := ResolveDID(resolver, "did:trustbloc:domain:123", 2, 0)
+		require.True(t, errors.Is(err, vdrapi.ErrNotFound))
+		require.Equal(t, 2, resolver.reads)
+	})
+
+	t.Run("test error returned at once", func(t *testing.T) {
+		resolver := &mockResolver{errs: []error{errors.New("connection refused")}}
+
+		_, err := ResolveDID(resolver, "did:trustbloc:domain:123", 3, 0)
+		require.EqualError(t, err, "connection refused")
+		require.Equal(t, 1, resolver.reads)
+	})
+}
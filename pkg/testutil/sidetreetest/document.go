@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetreetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// purposes of the public keys, the verification relationships of the document they are referenced in
+var purposes = []string{"authentication", "assertionMethod", "keyAgreement", //nolint: gochecknoglobals
+	"capabilityDelegation", "capabilityInvocation"}
+
+// properties of the public keys and services added by the 0.1 protocol create patches, by patch action
+var addedProperties = map[string]string{ //nolint: gochecknoglobals
+	"add-public-keys": "publicKeys", "add-services": "services"}
+
+type delta struct {
+	Patches          []map[string]interface{} `json:"patches"`
+	UpdateCommitment string                   `json:"updateCommitment"`
+}
+
+// resolutionResult returns the resolution result of the DID created with the delta, whose document is the one of
+// its replace patch, of DIF Sidetree v1, or the one its add patches build, of the 0.1 protocol
+func resolutionResult(did string, d *delta, recoveryCommitment string) ([]byte, error) {
+	replaced, err := createdDocument(d.Patches)
+	if err != nil {
+		return nil, err
+	}
+
+	didDoc, err := document(did, replaced)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"@context":    didResolutionContext,
+		"didDocument": didDoc,
+		"methodMetadata": map[string]interface{}{
+			"published":          true,
+			"canonicalId":        did,
+			"updateCommitment":   d.UpdateCommitment,
+			"recoveryCommitment": recoveryCommitment,
+		},
+	})
+}
+
+// createdDocument returns the document the create patches build, either replaced or added public keys and services
+func createdDocument(patches []map[string]interface{}) (map[string]interface{}, error) {
+	var created map[string]interface{}
+
+	for _, patch := range patches {
+		switch patch["action"] {
+		case "replace":
+			created, _ = patch["document"].(map[string]interface{}) //nolint: errcheck
+		case "add-public-keys", "add-services":
+			name := addedProperties[patch["action"].(string)]
+
+			if created == nil {
+				created = make(map[string]interface{})
+			}
+
+			added, _ := created[name].([]interface{})  //nolint: errcheck
+			elements, _ := patch[name].([]interface{}) //nolint: errcheck
+			created[name] = append(added, elements...)
+		default:
+			return nil, fmt.Errorf("create patch action %v not supported", patch["action"])
+		}
+	}
+
+	if created == nil {
+		return nil, errors.New("create delta without replace or add patches")
+	}
+
+	return created, nil
+}
+
+// document returns the DID document of the replaced document, its public keys being verification methods
+// referenced in the verification relationships of their purposes
+func document(did string, replaced map[string]interface{}) (map[string]interface{}, error) {
+	didDoc := map[string]interface{}{"@context": []string{didContext}, "id": did}
+
+	publicKeys, err := elements(replaced, "publicKeys", "publicKey")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(publicKeys) != 0 {
+		var relationships map[string][]interface{}
+
+		didDoc["verificationMethod"], relationships = verificationMethods(did, publicKeys)
+
+		for name, references := range relationships {
+			didDoc[name] = references
+		}
+	}
+
+	services, err := elements(replaced, "services", "service")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(services) != 0 {
+		for _, service := range services {
+			service["id"] = did + "#" + fmt.Sprint(service["id"])
+		}
+
+		didDoc["service"] = services
+	}
+
+	return didDoc, nil
+}
+
+// verificationMethods returns the verification methods of the public keys, and the verification relationships
+// referencing them
+func verificationMethods(did string, publicKeys []map[string]interface{}) ([]interface{},
+	map[string][]interface{}) {
+	methods := make([]interface{}, len(publicKeys))
+	relationships := make(map[string][]interface{})
+
+	for i, publicKey := range publicKeys {
+		id := did + "#" + fmt.Sprint(publicKey["id"])
+
+		methods[i] = map[string]interface{}{"id": id, "type": publicKey["type"], "controller": did,
+			"publicKeyJwk": publicKey["publicKeyJwk"]}
+
+		keyPurposes, _ := publicKey["purposes"].([]interface{}) //nolint: errcheck
+
+		for _, purpose := range keyPurposes {
+			if name, ok := purpose.(string); ok && isPurpose(name) {
+				relationships[name] = append(relationships[name], id)
+			}
+		}
+	}
+
+	return methods, relationships
+}
+
+// elements returns the public keys or services of the document, under the first of the property names set
+func elements(replaced map[string]interface{}, names ...string) ([]map[string]interface{}, error) {
+	for _, name := range names {
+		values, ok := replaced[name].([]interface{})
+		if !ok {
+			continue
+		}
+
+		result := make([]map[string]interface{}, len(values))
+
+		for i, value := range values {
+			element, isObject := value.(map[string]interface{})
+			if !isObject || element["id"] == nil {
+				return nil, fmt.Errorf("invalid %s element without id", name)
+			}
+
+			result[i] = element
+		}
+
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+func isPurpose(name string) bool {
+	for _, purpose := range purposes {
+		if purpose == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,220 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sidetreetest provides an in-memory Sidetree-like server, for the integration tests of projects creating
+// and resolving DIDs with the did method without the docker fixtures of a Sidetree node. The server anchors the
+// created DIDs at once, and resolves them with the documents of their create operations.
+package sidetreetest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+)
+
+const (
+	// DefaultNamespace is the namespace of the DIDs created with the server, unless set with WithNamespace
+	DefaultNamespace = "did:trustbloc:testnet.trustbloc.local"
+
+	// ProtocolVersion is the sidetree protocol version the server reports
+	ProtocolVersion = "0.1.0"
+
+	multihashSHA2256 = 18
+
+	didLDJSON            = "application/did+ld+json"
+	didContext           = "https://www.w3.org/ns/did/v1"
+	didResolutionContext = "https://w3id.org/did-resolution/v1"
+
+	identifiersPath = "/identifiers/"
+)
+
+// Option is a server option
+type Option func(s *Server)
+
+// WithNamespace option sets the namespace of the DIDs created with the server, the DIDs being the namespace and the
+// unique suffix of their create operation separated by a colon
+func WithNamespace(namespace string) Option {
+	return func(s *Server) {
+		s.namespace = namespace
+	}
+}
+
+// Server is an in-memory Sidetree-like server. Its URL is the sidetree endpoint DIDs are created with, and its
+// ResolverURL the resolver URL they are resolved with.
+type Server struct {
+	*httptest.Server
+
+	namespace string
+
+	mutex sync.RWMutex
+	// documents maps the unique suffixes of the created DIDs to their resolution results
+	documents map[string][]byte
+}
+
+// NewServer starts a server, which is to be closed with Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{namespace: DefaultNamespace, documents: make(map[string][]byte)}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", s.version)
+	mux.HandleFunc("/operations", s.operations)
+	mux.HandleFunc(identifiersPath, s.identifiers)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// ResolverURL returns the URL the DIDs created with the server are resolved with, such as with the resolver URL
+// option of the VDRI
+func (s *Server) ResolverURL() string {
+	return s.URL + strings.TrimSuffix(identifiersPath, "/")
+}
+
+// DIDs returns the DIDs created with the server
+func (s *Server) DIDs() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	dids := make([]string, 0, len(s.documents))
+
+	for suffix := range s.documents {
+		dids = append(dids, s.namespace+":"+suffix)
+	}
+
+	return dids
+}
+
+func (s *Server) version(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	writeJSON(rw, "application/json",
+		map[string]interface{}{"multihashAlgorithm": multihashSHA2256, "version": ProtocolVersion})
+}
+
+// operations anchors the DID of a create request at once and returns its resolution result. The other operations
+// are rejected.
+func (s *Server) operations(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var request struct {
+		Type       string                 `json:"type"`
+		SuffixData map[string]interface{} `json:"suffixData"`
+		Delta      *delta                 `json:"delta"`
+	}
+
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+
+		return
+	}
+
+	if request.Type != "create" {
+		http.Error(rw, fmt.Sprintf("operation type %s not supported", request.Type), http.StatusBadRequest)
+
+		return
+	}
+
+	if request.SuffixData == nil || request.Delta == nil {
+		http.Error(rw, "create request without suffix data or delta", http.StatusBadRequest)
+
+		return
+	}
+
+	suffix, err := uniqueSuffix(request.SuffixData)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	recoveryCommitment, _ := request.SuffixData["recoveryCommitment"].(string) //nolint: errcheck
+
+	resolution, err := resolutionResult(s.namespace+":"+suffix, request.Delta, recoveryCommitment)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	s.mutex.Lock()
+	s.documents[suffix] = resolution
+	s.mutex.Unlock()
+
+	writeBytes(rw, "application/json", resolution)
+}
+
+// identifiers resolves a created DID, with either its short-form or its long-form DID
+func (s *Server) identifiers(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	did := strings.TrimPrefix(req.URL.Path, identifiersPath)
+	suffix := strings.SplitN(strings.TrimPrefix(did, s.namespace+":"), ":", 2)[0]
+
+	s.mutex.RLock()
+	resolution, ok := s.documents[suffix]
+	s.mutex.RUnlock()
+
+	if !ok || !strings.HasPrefix(did, s.namespace+":") {
+		http.Error(rw, "DID not found", http.StatusNotFound)
+
+		return
+	}
+
+	writeBytes(rw, didLDJSON, resolution)
+}
+
+// uniqueSuffix returns the unique suffix of the DID created with the suffix data, the multihash of its canonical JSON
+func uniqueSuffix(suffixData map[string]interface{}) (string, error) {
+	canonical, err := docutil.MarshalCanonical(suffixData)
+	if err != nil {
+		return "", fmt.Errorf("invalid suffix data: %w", err)
+	}
+
+	digest := sha256.Sum256(canonical)
+	multihash := append([]byte{multihashSHA2256, byte(len(digest))}, digest[:]...)
+
+	return base64.RawURLEncoding.EncodeToString(multihash), nil
+}
+
+func writeJSON(rw http.ResponseWriter, contentType string, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	writeBytes(rw, contentType, body)
+}
+
+func writeBytes(rw http.ResponseWriter, contentType string, body []byte) {
+	rw.Header().Set("Content-Type", contentType)
+
+	_, _ = rw.Write(body) //nolint: errcheck
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sidetreetest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"testing"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer(WithNamespace("did:trustbloc:sidetreetest"))
+	defer s.Close()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	recoveryPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test create and resolve", func(t *testing.T) {
+		created, err := did.New().CreateDID("", create.WithSidetreeEndpoint(s.URL),
+			create.WithRecoveryPublicKey(recoveryPubKey), create.WithUpdatePublicKey(pubKey),
+			create.WithPublicKey(&doc.PublicKey{ID: "key1", Type: doc.JWSVerificationKey2020,
+				Encoding: doc.PublicKeyEncodingJwk, KeyType: doc.Ed25519KeyType, Value: pubKey,
+				Purposes: []string{doc.KeyPurposeAuthentication}}),
+			create.WithService(&docdid.Service{ID: "svc1", Type: "type", ServiceEndpoint: "http://www.example.com"}))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(created.ID, "did:trustbloc:sidetreetest:"))
+		require.Equal(t, []string{created.ID}, s.DIDs())
+
+		resolved, err := trustbloc.New(trustbloc.WithResolverURL(s.ResolverURL())).Read(created.ID)
+		require.NoError(t, err)
+
+		didDoc := resolved.DIDDocument
+		require.Equal(t, created.ID, didDoc.ID)
+		require.Len(t, didDoc.VerificationMethod, 1)
+		require.Equal(t, created.ID+"#key1", didDoc.VerificationMethod[0].ID)
+		require.Len(t, didDoc.Authentication, 1)
+		require.Len(t, didDoc.Service, 1)
+		require.Equal(t, created.ID+"#svc1", didDoc.Service[0].ID)
+	})
+
+	t.Run("test DID not found", func(t *testing.T) {
+		_, err := trustbloc.New(trustbloc.WithResolverURL(s.ResolverURL())).Read("did:trustbloc:sidetreetest:EiA")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DID does not exist")
+	})
+
+	t.Run("test operation not supported", func(t *testing.T) {
+		resp, err := http.Post(s.URL+"/operations", "application/json", //nolint: noctx
+			strings.NewReader(`{"type":"update","didSuffix":"EiA"}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("test invalid create request", func(t *testing.T) {
+		resp, err := http.Post(s.URL+"/operations", "application/json", //nolint: noctx
+			strings.NewReader(`{"type":"create","suffixData":{},"delta":{"patches":[{"action":"remove-public-keys"}]}}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
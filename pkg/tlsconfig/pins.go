@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tlsconfig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const pinPrefix = "sha256/"
+
+// ErrPinMismatch is returned by the TLS handshakes with a pinned host, when none of the certificates presented by
+// the host has a pinned key
+var ErrPinMismatch = errors.New("no certificate matches the pinned keys")
+
+// Pins are the SHA-256 hashes of the SubjectPublicKeyInfo of the certificates pinned per host
+type Pins map[string][][]byte
+
+// WithPins refuses the connections to the hosts of the pins unless a verified chain of the host has a certificate,
+// leaf, intermediate or root, whose key is pinned, so that a compromised CA can't be used to impersonate them. When the
+// verification is skipped, only the key of the leaf certificate is matched. Several keys can be pinned for a host, for
+// example the key of the current certificate and a backup key. The connections to the other hosts are not affected.
+// The hosts are matched with the server name sent by the TLS handshakes, so pins of IP addresses have no effect.
+func WithPins(pins Pins) Option {
+	normalized := make(Pins, len(pins))
+
+	for host, hashes := range pins {
+		host = strings.ToLower(host)
+		normalized[host] = append(normalized[host], hashes...)
+	}
+
+	return func(opts *tls.Config) {
+		opts.VerifyConnection = normalized.verifyConnection
+	}
+}
+
+// ParsePins returns the pins of the values, formatted as <host>=sha256/<base64 SHA-256 hash of the
+// SubjectPublicKeyInfo>, which is the format of the pins of curl --pinnedpubkey prefixed with the host
+func ParsePins(values []string) (Pins, error) {
+	pins := make(Pins)
+
+	for _, value := range values {
+		parts := strings.SplitN(strings.TrimSpace(value), "=", 2) //nolint: gomnd
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid pin %s, expected <host>=sha256/<base64 hash>", value)
+		}
+
+		hash, err := parseHash(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pin %s: %w", value, err)
+		}
+
+		host := strings.ToLower(parts[0])
+		pins[host] = append(pins[host], hash)
+	}
+
+	return pins, nil
+}
+
+// Pin returns the pin of the certificate, as sha256/<base64 SHA-256 hash of the SubjectPublicKeyInfo>
+func Pin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	return pinPrefix + base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func parseHash(pin string) ([]byte, error) {
+	if !strings.HasPrefix(pin, pinPrefix) {
+		return nil, fmt.Errorf("pin is not prefixed with %s", pinPrefix)
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, pinPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("decode pin: %w", err)
+	}
+
+	if len(hash) != sha256.Size {
+		return nil, fmt.Errorf("pin is not a SHA-256 hash")
+	}
+
+	return hash, nil
+}
+
+func (p Pins) verifyConnection(state tls.ConnectionState) error {
+	hashes, ok := p[strings.ToLower(state.ServerName)]
+	if !ok {
+		return nil
+	}
+
+	// the certificates presented by the host are not verified by themselves, a pinned certificate appended by the host
+	// to its chain proves nothing, so only the verified chains are matched or else, when the verification is
+	// skipped, the leaf certificate
+	if len(state.VerifiedChains) == 0 {
+		if len(state.PeerCertificates) > 0 && matches(state.PeerCertificates[:1], hashes) {
+			return nil
+		}
+
+		return fmt.Errorf("host %s: %w", state.ServerName, ErrPinMismatch)
+	}
+
+	for _, chain := range state.VerifiedChains {
+		if matches(chain, hashes) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("host %s: %w", state.ServerName, ErrPinMismatch)
+}
+
+func matches(certs []*x509.Certificate, hashes [][]byte) bool {
+	for _, cert := range certs {
+		hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+		for _, pinned := range hashes {
+			if bytes.Equal(hash[:], pinned) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPins(t *testing.T) {
+	serv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer serv.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(serv.Certificate())
+
+	// the certificate of the test server is for example.com
+	get := func(pins Pins) error {
+		tlsConfig := New(WithRootCAs(rootCAs), WithPins(pins))
+		tlsConfig.ServerName = "example.com"
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+		resp, err := client.Get(serv.URL)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	hash := sha256.Sum256(serv.Certificate().RawSubjectPublicKeyInfo)
+
+	t.Run("test pinned key", func(t *testing.T) {
+		require.NoError(t, get(Pins{"example.com": {make([]byte, sha256.Size), hash[:]}}))
+	})
+
+	t.Run("test key not pinned", func(t *testing.T) {
+		err := get(Pins{"example.com": {make([]byte, sha256.Size)}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrPinMismatch))
+	})
+
+	t.Run("test host not pinned", func(t *testing.T) {
+		require.NoError(t, get(Pins{"other.example.com": {make([]byte, sha256.Size)}}))
+	})
+
+	t.Run("test host case", func(t *testing.T) {
+		v := New(WithPins(Pins{"Example.COM": {hash[:]}}))
+
+		require.NoError(t, v.VerifyConnection(tls.ConnectionState{ServerName: "example.com",
+			VerifiedChains: [][]*x509.Certificate{{serv.Certificate()}}}))
+		require.True(t, errors.Is(v.VerifyConnection(tls.ConnectionState{ServerName: "EXAMPLE.com"}), ErrPinMismatch))
+	})
+}
+
+func TestParsePins(t *testing.T) {
+	cert := &x509.Certificate{RawSubjectPublicKeyInfo: []byte("spki")}
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	t.Run("test pins", func(t *testing.T) {
+		pins, err := ParsePins([]string{"Example.com=" + Pin(cert), "example.com=" + Pin(cert),
+			"other.com=" + Pin(cert)})
+		require.NoError(t, err)
+		require.Equal(t, Pins{"example.com": {hash[:], hash[:]}, "other.com": {hash[:]}}, pins)
+	})
+
+	t.Run("test invalid pins", func(t *testing.T) {
+		for _, value := range []string{
+			"example.com", "=" + Pin(cert), "example.com=" + Pin(cert)[len(pinPrefix):],
+			"example.com=sha256/!", "example.com=sha256/c3BraQ==",
+		} {
+			_, err := ParsePins([]string{value})
+			require.Error(t, err, value)
+		}
+	})
+}
+
+func TestWithPinsAppendedCertificate(t *testing.T) {
+	leafKey, leaf := newCertificate(t)
+	_, pinned := newCertificate(t)
+
+	// the server appends the pinned certificate to the chain of its unpinned certificate
+	serv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serv.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leaf.Raw, pinned.Raw}, PrivateKey: leafKey}}}
+	serv.StartTLS()
+
+	defer serv.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(leaf)
+
+	get := func(opts ...Option) error {
+		tlsConfig := New(opts...)
+		tlsConfig.ServerName = "example.com"
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+		resp, err := client.Get(serv.URL)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	pinnedHash := sha256.Sum256(pinned.RawSubjectPublicKeyInfo)
+	leafHash := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+
+	t.Run("test appended certificate not in the verified chain", func(t *testing.T) {
+		err := get(WithRootCAs(rootCAs), WithPins(Pins{"example.com": {pinnedHash[:]}}))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrPinMismatch))
+
+		require.NoError(t, get(WithRootCAs(rootCAs), WithPins(Pins{"example.com": {leafHash[:]}})))
+	})
+
+	t.Run("test appended certificate with the verification skipped", func(t *testing.T) {
+		err := get(WithInsecureSkipVerify(true), WithPins(Pins{"example.com": {pinnedHash[:]}}))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrPinMismatch))
+
+		require.NoError(t, get(WithInsecureSkipVerify(true), WithPins(Pins{"example.com": {leafHash[:]}})))
+	})
+}
+
+func newCertificate(t *testing.T) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "example.com"},
+		DNSNames:              []string{"example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return key, cert
+}
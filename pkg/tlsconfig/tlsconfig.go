@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tlsconfig builds the TLS configs of the did client, the VDRI, the CLI and the REST server from the same
+// settings, so that the minimum version, the cipher suites and the certificates are applied the same way by all of
+// them.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// Option is a TLS config option
+type Option func(opts *tls.Config)
+
+// WithRootCAs sets the root CAs the server certificates are verified with, which default to the system pool
+func WithRootCAs(rootCAs *x509.CertPool) Option {
+	return func(opts *tls.Config) {
+		opts.RootCAs = rootCAs
+	}
+}
+
+// WithClientCertificates adds the client certificates presented to the servers which require mutual TLS
+func WithClientCertificates(certs ...tls.Certificate) Option {
+	return func(opts *tls.Config) {
+		opts.Certificates = append(opts.Certificates, certs...)
+	}
+}
+
+// WithMinVersion sets the minimum TLS version, which defaults to TLS 1.2
+func WithMinVersion(version uint16) Option {
+	return func(opts *tls.Config) {
+		opts.MinVersion = version
+	}
+}
+
+// WithCipherSuites sets the cipher suites of the TLS 1.2 connections, which default to the secure cipher suites of
+// the Go version. The cipher suites of the TLS 1.3 connections are not configurable.
+func WithCipherSuites(suites ...uint16) Option {
+	return func(opts *tls.Config) {
+		opts.CipherSuites = suites
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificates. For development only, as the
+// connections are then open to man-in-the-middle attacks.
+func WithInsecureSkipVerify(insecure bool) Option {
+	return func(opts *tls.Config) {
+		opts.InsecureSkipVerify = insecure //nolint: gosec
+	}
+}
+
+// New returns a TLS config with TLS 1.2 as minimum version and the options
+func New(opts ...Option) *tls.Config {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	for _, opt := range opts {
+		opt(tlsConfig)
+	}
+
+	return tlsConfig
+}
+
+// Apply returns a copy of the TLS config, or a new TLS config if nil, with the options. The TLS config is returned
+// as is if there are no options, and is never modified.
+func Apply(tlsConfig *tls.Config, opts ...Option) *tls.Config {
+	if len(opts) == 0 {
+		return tlsConfig
+	}
+
+	if tlsConfig == nil {
+		return New(opts...)
+	}
+
+	tlsConfig = tlsConfig.Clone()
+
+	for _, opt := range opts {
+		opt(tlsConfig)
+	}
+
+	return tlsConfig
+}
+
+// ParseVersion returns the TLS version of 1.2 or 1.3, the versions older than TLS 1.2 not being supported
+func ParseVersion(version string) (uint16, error) {
+	switch strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(version)), "TLS")) {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+
+	return 0, fmt.Errorf("unsupported TLS version %s, expected 1.2 or 1.3", version)
+}
+
+// ParseCipherSuites returns the IDs of the cipher suites of the names, such as
+// TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256. The insecure cipher suites are not supported.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	ids := make(map[string]uint16)
+
+	for _, suite := range tls.CipherSuites() {
+		ids[suite.Name] = suite.ID
+	}
+
+	suites := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, ok := ids[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported cipher suite %s", name)
+		}
+
+		suites = append(suites, id)
+	}
+
+	return suites, nil
+}
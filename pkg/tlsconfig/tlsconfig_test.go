@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test default", func(t *testing.T) {
+		tlsConfig := New()
+		require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		require.Nil(t, tlsConfig.RootCAs)
+		require.Empty(t, tlsConfig.CipherSuites)
+		require.False(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("test options", func(t *testing.T) {
+		rootCAs := x509.NewCertPool()
+		cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+
+		tlsConfig := New(WithRootCAs(rootCAs), WithClientCertificates(cert), WithMinVersion(tls.VersionTLS13),
+			WithCipherSuites(tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256), WithInsecureSkipVerify(true))
+		require.Same(t, rootCAs, tlsConfig.RootCAs)
+		require.Equal(t, []tls.Certificate{cert}, tlsConfig.Certificates)
+		require.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+		require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+		require.True(t, tlsConfig.InsecureSkipVerify)
+	})
+}
+
+func TestApply(t *testing.T) {
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+
+	t.Run("test no options", func(t *testing.T) {
+		tlsConfig := &tls.Config{ServerName: "test", MinVersion: tls.VersionTLS12}
+		require.Same(t, tlsConfig, Apply(tlsConfig))
+		require.Nil(t, Apply(nil))
+	})
+
+	t.Run("test options applied to a copy", func(t *testing.T) {
+		tlsConfig := &tls.Config{ServerName: "test", MinVersion: tls.VersionTLS12}
+
+		applied := Apply(tlsConfig, WithClientCertificates(cert), WithMinVersion(tls.VersionTLS13))
+		require.Equal(t, "test", applied.ServerName)
+		require.Equal(t, []tls.Certificate{cert}, applied.Certificates)
+		require.Equal(t, uint16(tls.VersionTLS13), applied.MinVersion)
+		require.Empty(t, tlsConfig.Certificates)
+		require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	})
+
+	t.Run("test options applied to a new tls config", func(t *testing.T) {
+		applied := Apply(nil, WithClientCertificates(cert))
+		require.Equal(t, uint16(tls.VersionTLS12), applied.MinVersion)
+		require.Equal(t, []tls.Certificate{cert}, applied.Certificates)
+	})
+}
+
+func TestParseVersion(t *testing.T) {
+	for version, expected := range map[string]uint16{
+		"1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13, "TLS1.2": tls.VersionTLS12, "tls 1.3": tls.VersionTLS13,
+	} {
+		v, err := ParseVersion(version)
+		require.NoError(t, err)
+		require.Equal(t, expected, v)
+	}
+
+	_, err := ParseVersion("1.1")
+	require.EqualError(t, err, "unsupported TLS version 1.1, expected 1.2 or 1.3")
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	t.Run("test cipher suites", func(t *testing.T) {
+		suites, err := ParseCipherSuites([]string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			" TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"})
+		require.NoError(t, err)
+		require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, suites)
+	})
+
+	t.Run("test insecure cipher suite", func(t *testing.T) {
+		_, err := ParseCipherSuites([]string{"TLS_RSA_WITH_RC4_128_SHA"})
+		require.EqualError(t, err, "unsupported cipher suite TLS_RSA_WITH_RC4_128_SHA")
+	})
+
+	t.Run("test unknown cipher suite", func(t *testing.T) {
+		_, err := ParseCipherSuites([]string{"unknown"})
+		require.EqualError(t, err, "unsupported cipher suite unknown")
+	})
+}
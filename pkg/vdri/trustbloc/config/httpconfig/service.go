@@ -12,10 +12,10 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"path/filepath"
 	"strings"
 
-	log "github.com/sirupsen/logrus"
-
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 )
 
@@ -23,24 +23,36 @@ const (
 	// default hashes for sidetree
 	sha2_256 = 18 // multihash
 	maxAge   = 3600
+	// maxIDLength is the maximum length of the IDs of the public keys and services sidetree nodes accept, unless
+	// their config sets another
+	maxIDLength = 50
+
+	ipfsScheme         = "ipfs://"
+	fileScheme         = "file://"
+	defaultIPFSGateway = "https://ipfs.io"
 )
 
 // ConfigService fetches consortium and stakeholder configs over http
 type ConfigService struct {
-	httpClient *http.Client
-	tlsConfig  *tls.Config
-	authToken  string
+	httpClient  *http.Client
+	tlsConfig   *tls.Config
+	authToken   string
+	ipfsGateway string
+	logger      logger.Logger
 }
 
 // NewService create new ConfigService
 func NewService(opts ...Option) *ConfigService {
-	configService := &ConfigService{httpClient: &http.Client{}}
+	configService := &ConfigService{ipfsGateway: defaultIPFSGateway, logger: logger.Default()}
 
 	for _, opt := range opts {
 		opt(configService)
 	}
 
-	configService.httpClient.Transport = &http.Transport{TLSClientConfig: configService.tlsConfig}
+	if configService.httpClient == nil {
+		configService.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: configService.tlsConfig,
+			Proxy: http.ProxyFromEnvironment}}
+	}
 
 	return configService
 }
@@ -59,24 +71,11 @@ func configURL(urlDomain, consortiumDomain string) string {
 
 // GetConsortium fetches and parses the consortium file at the given domain
 func (cs *ConfigService) GetConsortium(url, domain string) (*models.ConsortiumFileData, error) {
-	res, err := cs.httpClient.Get(configURL(url, domain))
-	if err != nil {
-		return nil, err
-	}
-
-	// nolint: errcheck
-	defer res.Body.Close()
-
-	body, err := ioutil.ReadAll(res.Body)
+	body, err := cs.getConfigFile(url, domain, "consortium")
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		// TODO retry https://github.com/trustbloc/trustbloc-did-method/issues/159
-		return nil, fmt.Errorf("consortium config request failed: error %d, `%s`", res.StatusCode, string(body))
-	}
-
 	return models.ParseConsortium(body)
 }
 
@@ -100,18 +99,19 @@ func (cs *ConfigService) GetSidetreeConfig(url string) (*models.SidetreeConfig,
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	defer closeResponseBody(resp.Body)
+	defer cs.closeResponseBody(resp.Body)
 
 	responseBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response : %s", err)
 	}
 
-	config := models.SidetreeConfig{MultiHashAlgorithm: sha2_256, MaxAge: maxAge}
+	config := models.SidetreeConfig{MultiHashAlgorithm: sha2_256, MaxIDLength: maxIDLength, MaxAge: maxAge}
 
 	if resp.StatusCode != http.StatusOK {
-		log.Warnf("return unexpected response from %s status '%d' body %s, will return default sidetree config",
-			url, resp.StatusCode, responseBytes)
+		cs.logger.Warn("unexpected sidetree config response, will return default sidetree config", "url", url,
+			"status", resp.StatusCode, "body", string(responseBytes))
+
 		return &config, nil
 	}
 
@@ -124,13 +124,38 @@ func (cs *ConfigService) GetSidetreeConfig(url string) (*models.SidetreeConfig,
 
 // GetStakeholder fetches and parses a stakeholder file under the given url with the given domain
 func (cs *ConfigService) GetStakeholder(url, domain string) (*models.StakeholderFileData, error) {
-	res, err := cs.httpClient.Get(configURL(url, domain))
+	body, err := cs.getConfigFile(url, domain, "stakeholder")
 	if err != nil {
 		return nil, err
 	}
 
-	// nolint: errcheck
-	defer res.Body.Close()
+	return models.ParseStakeholder(body)
+}
+
+// getConfigFile fetches a config file from the well-known path of the given url, or from the IPFS gateway
+// if either the domain or the url is an ipfs:// URI. A CAS URI addresses the config file itself,
+// so a CAS domain takes precedence over the url it is requested from.
+// A file:// url reads the config file from the well-known path of a local directory.
+func (cs *ConfigService) getConfigFile(url, domain, fileType string) ([]byte, error) {
+	if isFileURI(url) && !isIPFSURI(domain) {
+		return readLocalConfigFile(url, domain, fileType)
+	}
+
+	fileURL := configURL(url, domain)
+
+	switch {
+	case isIPFSURI(domain):
+		fileURL = cs.ipfsURL(domain)
+	case isIPFSURI(url):
+		fileURL = cs.ipfsURL(url)
+	}
+
+	res, err := cs.httpClient.Get(fileURL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cs.closeResponseBody(res.Body)
 
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
@@ -139,10 +164,40 @@ func (cs *ConfigService) GetStakeholder(url, domain string) (*models.Stakeholder
 
 	if res.StatusCode != http.StatusOK {
 		// TODO retry https://github.com/trustbloc/trustbloc-did-method/issues/159
-		return nil, fmt.Errorf("stakeholder config request failed: error %d, `%s`", res.StatusCode, string(body))
+		return nil, fmt.Errorf("%s config request failed: error %d, `%s`", fileType, res.StatusCode, string(body))
 	}
 
-	return models.ParseStakeholder(body)
+	return body, nil
+}
+
+// readLocalConfigFile reads the config file for the domain from a directory laid out like a web server root,
+// ie. <dir>/.well-known/did-trustbloc/<domain>.json. A file:// domain is named by its last path element.
+func readLocalConfigFile(dirURI, domain, fileType string) ([]byte, error) {
+	name := domain
+	if isFileURI(domain) {
+		name = filepath.Base(strings.TrimPrefix(domain, fileScheme))
+	}
+
+	path := filepath.Join(strings.TrimPrefix(dirURI, fileScheme), consortiumURLInfix, name+consortiumURLSuffix)
+
+	body, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s config file read failed: %w", fileType, err)
+	}
+
+	return body, nil
+}
+
+func isFileURI(uri string) bool {
+	return strings.HasPrefix(uri, fileScheme)
+}
+
+func isIPFSURI(uri string) bool {
+	return strings.HasPrefix(uri, ipfsScheme)
+}
+
+func (cs *ConfigService) ipfsURL(uri string) string {
+	return strings.TrimSuffix(cs.ipfsGateway, "/") + "/ipfs/" + strings.TrimPrefix(uri, ipfsScheme)
 }
 
 // Option is a config service instance option
@@ -155,6 +210,14 @@ func WithTLSConfig(tlsConfig *tls.Config) Option {
 	}
 }
 
+// WithHTTPClient option sets the HTTP client of the service, such as a client sharing its transport with other
+// requests to pool their connections. The TLS config of WithTLSConfig is then not used.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *ConfigService) {
+		opts.httpClient = client
+	}
+}
+
 // WithAuthToken add auth token
 func WithAuthToken(authToken string) Option {
 	return func(opts *ConfigService) {
@@ -162,9 +225,23 @@ func WithAuthToken(authToken string) Option {
 	}
 }
 
-func closeResponseBody(respBody io.Closer) {
+// WithIPFSGateway sets the HTTP gateway used to fetch config files referenced by ipfs:// URIs
+func WithIPFSGateway(gatewayURL string) Option {
+	return func(opts *ConfigService) {
+		opts.ipfsGateway = gatewayURL
+	}
+}
+
+// WithLogger sets the logger of the service. Defaults to logger.Default.
+func WithLogger(l logger.Logger) Option {
+	return func(opts *ConfigService) {
+		opts.logger = l
+	}
+}
+
+func (cs *ConfigService) closeResponseBody(respBody io.Closer) {
 	e := respBody.Close()
 	if e != nil {
-		log.Errorf("Failed to close response body: %v", e)
+		cs.logger.Error("failed to close response body", "error", e)
 	}
 }
@@ -9,8 +9,11 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -92,11 +95,12 @@ func TestConfigService_GetSidetreeConfig(t *testing.T) {
 		c, err := cs.GetSidetreeConfig(serv.URL)
 		require.NoError(t, err)
 		require.Equal(t, uint(sha2_256), c.MultiHashAlgorithm)
+		require.Equal(t, uint(maxIDLength), c.MaxIDLength)
 	})
 
 	t.Run("success", func(t *testing.T) {
 		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			bytes, err := json.Marshal(models.SidetreeConfig{MultiHashAlgorithm: 10})
+			bytes, err := json.Marshal(models.SidetreeConfig{MultiHashAlgorithm: 10, MaxOperationSize: 2000})
 			require.NoError(t, err)
 
 			fmt.Fprint(w, string(bytes))
@@ -108,6 +112,8 @@ func TestConfigService_GetSidetreeConfig(t *testing.T) {
 		c, err := cs.GetSidetreeConfig(serv.URL)
 		require.NoError(t, err)
 		require.Equal(t, uint(10), c.MultiHashAlgorithm)
+		require.Equal(t, uint(2000), c.MaxOperationSize)
+		require.Equal(t, uint(maxIDLength), c.MaxIDLength)
 	})
 
 	t.Run("test failed to unmarshal response", func(t *testing.T) {
@@ -125,6 +131,14 @@ func TestConfigService_GetSidetreeConfig(t *testing.T) {
 	})
 }
 
+func TestConfigService_WithHTTPClient(t *testing.T) {
+	client := &http.Client{}
+
+	cs := NewService(WithHTTPClient(client), WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	require.Same(t, client, cs.httpClient)
+	require.Nil(t, client.Transport)
+}
+
 func TestConfigService_GetStakeholder(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		stakeholder := mockmodels.DummyStakeholder("foo.bar", []string{
@@ -182,6 +196,113 @@ func TestConfigService_GetStakeholder(t *testing.T) {
 	})
 }
 
+func TestConfigService_IPFS(t *testing.T) {
+	consortium := mockmodels.DummyConsortium("foo.bar", []*models.StakeholderListElement{{Domain: "bar.baz"}})
+
+	consortiumFile, err := mockmodels.WrapConsortium(consortium)
+	require.NoError(t, err)
+
+	stakeholderFile, err := mockmodels.DummyStakeholderJSON("bar.baz", []string{"https://bar.baz/webapi"})
+	require.NoError(t, err)
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipfs/QmConsortium":
+			fmt.Fprint(w, consortiumFile)
+		case "/ipfs/QmStakeholder":
+			fmt.Fprint(w, stakeholderFile)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gateway.Close()
+
+	cs := NewService(WithIPFSGateway(gateway.URL + "/"))
+
+	t.Run("success: consortium at CAS domain", func(t *testing.T) {
+		conf, err := cs.GetConsortium("ipfs://QmConsortium", "ipfs://QmConsortium")
+		require.NoError(t, err)
+		require.Equal(t, "foo.bar", conf.Config.Domain)
+	})
+
+	t.Run("success: stakeholder copy of consortium at CAS domain", func(t *testing.T) {
+		conf, err := cs.GetConsortium("https://bar.baz", "ipfs://QmConsortium")
+		require.NoError(t, err)
+		require.Equal(t, "foo.bar", conf.Config.Domain)
+	})
+
+	t.Run("success: stakeholder at CAS url", func(t *testing.T) {
+		conf, err := cs.GetStakeholder("ipfs://QmStakeholder", "ipfs://QmStakeholder")
+		require.NoError(t, err)
+		require.Equal(t, "bar.baz", conf.Config.Domain)
+	})
+
+	t.Run("failure: CID not found", func(t *testing.T) {
+		_, err := cs.GetStakeholder("ipfs://QmMissing", "ipfs://QmMissing")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stakeholder config request failed: error 404")
+	})
+
+	t.Run("test default gateway", func(t *testing.T) {
+		require.Equal(t, "https://ipfs.io/ipfs/QmConsortium", NewService().ipfsURL("ipfs://QmConsortium"))
+	})
+}
+
+func TestConfigService_LocalFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	consortium := mockmodels.DummyConsortium("consortium.local", []*models.StakeholderListElement{
+		{Domain: "file://" + filepath.Join(dir, "stakeholder.local")}})
+
+	consortiumFile, err := mockmodels.WrapConsortium(consortium)
+	require.NoError(t, err)
+
+	stakeholderFile, err := mockmodels.DummyStakeholderJSON("stakeholder.local", []string{"https://localhost/sidetree"})
+	require.NoError(t, err)
+
+	writeConfig := func(root, name, content string) {
+		wellKnown := filepath.Join(dir, root, ".well-known", "did-trustbloc")
+		require.NoError(t, os.MkdirAll(wellKnown, 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(wellKnown, name+".json"), []byte(content), 0600))
+	}
+
+	writeConfig("consortium.local", "consortium.local", consortiumFile)
+	writeConfig("stakeholder.local", "consortium.local", consortiumFile)
+	writeConfig("stakeholder.local", "stakeholder.local", stakeholderFile)
+
+	consortiumURI := "file://" + filepath.Join(dir, "consortium.local")
+	stakeholderURI := "file://" + filepath.Join(dir, "stakeholder.local")
+
+	cs := NewService()
+
+	t.Run("success: consortium", func(t *testing.T) {
+		conf, err := cs.GetConsortium(consortiumURI, consortiumURI)
+		require.NoError(t, err)
+		require.Equal(t, "consortium.local", conf.Config.Domain)
+	})
+
+	t.Run("success: stakeholder copy of consortium", func(t *testing.T) {
+		conf, err := cs.GetConsortium(stakeholderURI, consortiumURI)
+		require.NoError(t, err)
+		require.Equal(t, "consortium.local", conf.Config.Domain)
+	})
+
+	t.Run("success: stakeholder", func(t *testing.T) {
+		conf, err := cs.GetStakeholder(stakeholderURI, stakeholderURI)
+		require.NoError(t, err)
+		require.Equal(t, "stakeholder.local", conf.Config.Domain)
+	})
+
+	t.Run("failure: file not found", func(t *testing.T) {
+		_, err := cs.GetStakeholder(consortiumURI, stakeholderURI)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stakeholder config file read failed")
+	})
+}
+
 func Test_configURL(t *testing.T) {
 	tests := [][2]string{ // first element is the test value, second is the correct value
 		{
@@ -0,0 +1,52 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsconfig
+
+import (
+	"time"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+type config interface {
+	GetConsortium(string, string) (*models.ConsortiumFileData, error)
+	GetStakeholder(string, string) (*models.StakeholderFileData, error)
+	GetSidetreeConfig(url string) (*models.SidetreeConfig, error)
+}
+
+// ConfigService fetches configs using a wrapped config service, recording sidetree config fetch metrics
+type ConfigService struct {
+	config   config
+	provider metrics.Provider
+}
+
+// NewService create new ConfigService
+func NewService(config config, provider metrics.Provider) *ConfigService {
+	return &ConfigService{config: config, provider: provider}
+}
+
+// GetConsortium fetches and parses the consortium file at the given domain
+func (cs *ConfigService) GetConsortium(url, domain string) (*models.ConsortiumFileData, error) {
+	return cs.config.GetConsortium(url, domain)
+}
+
+// GetStakeholder returns the stakeholder config file fetched by the wrapped config service
+func (cs *ConfigService) GetStakeholder(url, domain string) (*models.StakeholderFileData, error) {
+	return cs.config.GetStakeholder(url, domain)
+}
+
+// GetSidetreeConfig returns the sidetree config, recording the request count and latency
+func (cs *ConfigService) GetSidetreeConfig(url string) (*models.SidetreeConfig, error) {
+	start := time.Now()
+
+	conf, err := cs.config.GetSidetreeConfig(url)
+
+	metrics.Record(cs.provider, metrics.SidetreeConfigRequests, metrics.SidetreeConfigDuration,
+		metrics.DomainFromURL(url), start, err)
+
+	return conf, err
+}
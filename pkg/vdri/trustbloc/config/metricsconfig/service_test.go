@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metricsconfig
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
+	mockmetrics "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/metrics"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+func TestConfigService_GetSidetreeConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		p := mockmetrics.NewProvider()
+
+		cs := NewService(&mockconfig.MockConfigService{
+			GetSidetreeConfigFunc: func(url string) (*models.SidetreeConfig, error) {
+				return &models.SidetreeConfig{MultiHashAlgorithm: 18}, nil
+			}}, p)
+
+		conf, err := cs.GetSidetreeConfig("https://foo.bar/sidetree/0.0.1")
+		require.NoError(t, err)
+		require.Equal(t, uint(18), conf.MultiHashAlgorithm)
+
+		require.Equal(t, 1, p.Count(metrics.SidetreeConfigRequests, "foo.bar", metrics.StatusSuccess))
+		require.Equal(t, 1, p.Observations(metrics.SidetreeConfigDuration, "foo.bar", metrics.StatusSuccess))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		p := mockmetrics.NewProvider()
+
+		cs := NewService(&mockconfig.MockConfigService{
+			GetSidetreeConfigFunc: func(url string) (*models.SidetreeConfig, error) {
+				return nil, fmt.Errorf("sidetree config error")
+			}}, p)
+
+		_, err := cs.GetSidetreeConfig("https://foo.bar/sidetree/0.0.1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sidetree config error")
+
+		require.Equal(t, 1, p.Count(metrics.SidetreeConfigRequests, "foo.bar", metrics.StatusFailure))
+		require.Equal(t, 0, p.Count(metrics.SidetreeConfigRequests, "foo.bar", metrics.StatusSuccess))
+	})
+}
+
+func TestConfigService_PassThrough(t *testing.T) {
+	cs := NewService(&mockconfig.MockConfigService{
+		GetConsortiumFunc: func(url, domain string) (*models.ConsortiumFileData, error) {
+			return &models.ConsortiumFileData{}, nil
+		},
+		GetStakeholderFunc: func(url, domain string) (*models.StakeholderFileData, error) {
+			return &models.StakeholderFileData{}, nil
+		}}, metrics.NoopProvider{})
+
+	c, err := cs.GetConsortium("foo.bar", "foo.bar")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	s, err := cs.GetStakeholder("foo.bar", "foo.bar")
+	require.NoError(t, err)
+	require.NotNil(t, s)
+}
@@ -17,10 +17,7 @@ import (
 
 // VerifyConsortiumSignatures verifies signatures on a consortium file, against stakeholder keys of a consortium config
 func VerifyConsortiumSignatures(signedData *models.ConsortiumFileData, signerConsortium *models.Consortium) error {
-	n := signerConsortium.Policy.NumQueries
-	if n == 0 || n > len(signerConsortium.Members) {
-		n = len(signerConsortium.Members)
-	}
+	n := signerConsortium.NumQueries()
 
 	perm := rand.Perm(len(signerConsortium.Members))
 	verifiedCount := 0
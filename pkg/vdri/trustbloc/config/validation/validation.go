@@ -0,0 +1,310 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/square/go-jose/v3"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+// Severity is the severity of a config problem
+type Severity string
+
+const (
+	// SeverityError is a problem that prevents the config from being used
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem that does not prevent the config from being used
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	// CodeInvalidJWS means the config file is not a valid JWS, or its signatures do not verify
+	CodeInvalidJWS = "invalid-jws"
+	// CodeInvalidPayload means the JWS payload is not a valid config object
+	CodeInvalidPayload = "invalid-payload"
+	// CodeMissingField means a required field is missing
+	CodeMissingField = "missing-field"
+	// CodeInvalidField means a field has an invalid value
+	CodeInvalidField = "invalid-field"
+	// CodePolicyViolation means the config violates, or configures, a problematic policy
+	CodePolicyViolation = "policy-violation"
+	// CodeUnreachable means a referenced stakeholder or endpoint could not be reached
+	CodeUnreachable = "unreachable"
+)
+
+// Problem is a problem found while validating a config file
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Field    string   `json:"field,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Problems is the list of problems found while validating a config file
+type Problems []Problem
+
+// HasErrors returns true if any of the problems is an error
+func (p Problems) HasErrors() bool {
+	for i := range p {
+		if p[i].Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Problems) add(severity Severity, code, field, format string, args ...interface{}) {
+	*p = append(*p, Problem{Severity: severity, Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+type stakeholderConfig interface {
+	GetStakeholder(url, domain string) (*models.StakeholderFileData, error)
+}
+
+type options struct {
+	checkReachability bool
+	httpClient        *http.Client
+	configService     stakeholderConfig
+}
+
+// Option is a validation option
+type Option func(opts *options)
+
+// CheckReachability enables checking that consortium members serve their stakeholder config
+// and that stakeholder endpoints respond, using the given TLS config
+func CheckReachability(tlsConfig *tls.Config) Option {
+	return func(opts *options) {
+		opts.checkReachability = true
+		opts.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig,
+			Proxy: http.ProxyFromEnvironment}}
+		opts.configService = httpconfig.NewService(httpconfig.WithTLSConfig(tlsConfig))
+	}
+}
+
+func getOptions(opts []Option) *options {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// ValidateConsortiumConfig validates a consortium config file, returning the problems found
+func ValidateConsortiumConfig(data []byte, opts ...Option) Problems {
+	o := getOptions(opts)
+
+	var problems Problems
+
+	jws, err := jose.ParseSigned(string(data))
+	if err != nil {
+		problems.add(SeverityError, CodeInvalidJWS, "", "consortium config is not a valid JWS: %s", err)
+
+		return problems
+	}
+
+	consortium := &models.Consortium{}
+
+	if err := json.Unmarshal(jws.UnsafePayloadWithoutVerification(), consortium); err != nil {
+		problems.add(SeverityError, CodeInvalidPayload, "", "consortium config payload is invalid: %s", err)
+
+		return problems
+	}
+
+	if consortium.Domain == "" {
+		problems.add(SeverityError, CodeMissingField, "domain", "consortium domain is required")
+	}
+
+	if len(consortium.Members) == 0 {
+		problems.add(SeverityError, CodeMissingField, "members", "consortium must have at least one member")
+	}
+
+	verified := validateMembers(consortium, jws, o, &problems)
+
+	validateConsortiumPolicy(consortium, verified, &problems)
+
+	return problems
+}
+
+// validateMembers validates the consortium members and returns the number of members whose key verifies the JWS
+func validateMembers(consortium *models.Consortium, jws *jose.JSONWebSignature, o *options, problems *Problems) int {
+	domains := map[string]bool{}
+	verified := 0
+
+	for i, member := range consortium.Members {
+		field := fmt.Sprintf("members[%d]", i)
+
+		if member == nil {
+			problems.add(SeverityError, CodeMissingField, field, "member is empty")
+
+			continue
+		}
+
+		switch {
+		case member.Domain == "":
+			problems.add(SeverityError, CodeMissingField, field+".domain", "member domain is required")
+		case domains[member.Domain]:
+			problems.add(SeverityError, CodeInvalidField, field+".domain", "duplicate member domain %s", member.Domain)
+		default:
+			domains[member.Domain] = true
+		}
+
+		if member.DID == "" {
+			problems.add(SeverityError, CodeMissingField, field+".did", "member DID is required")
+		}
+
+		if memberKeyVerifies(member, jws, field, problems) {
+			verified++
+		}
+
+		if o.checkReachability && member.Domain != "" {
+			if _, err := o.configService.GetStakeholder(member.Domain, member.Domain); err != nil {
+				problems.add(SeverityError, CodeUnreachable, field+".domain",
+					"failed to fetch stakeholder config from %s: %s", member.Domain, err)
+			}
+		}
+	}
+
+	return verified
+}
+
+func memberKeyVerifies(member *models.StakeholderListElement, jws *jose.JSONWebSignature, field string,
+	problems *Problems) bool {
+	if len(member.PublicKey.JWK) == 0 {
+		problems.add(SeverityError, CodeMissingField, field+".publicKey.jwk", "member public key is required")
+
+		return false
+	}
+
+	key := jose.JSONWebKey{}
+
+	if err := key.UnmarshalJSON(member.PublicKey.JWK); err != nil {
+		problems.add(SeverityError, CodeInvalidField, field+".publicKey.jwk", "member public key is invalid: %s", err)
+
+		return false
+	}
+
+	if _, _, _, err := jws.VerifyMulti(key); err != nil {
+		problems.add(SeverityWarning, CodeInvalidJWS, field+".publicKey.jwk",
+			"consortium config is not signed by member %s", member.Domain)
+
+		return false
+	}
+
+	return true
+}
+
+func validateConsortiumPolicy(consortium *models.Consortium, verified int, problems *Problems) {
+	switch {
+	case consortium.Policy.NumQueries < 0:
+		problems.add(SeverityError, CodePolicyViolation, "policy.numQueries", "numQueries must not be negative")
+	case consortium.Policy.NumQueries > len(consortium.Members):
+		problems.add(SeverityWarning, CodePolicyViolation, "policy.numQueries",
+			"numQueries %d is larger than the number of members %d, all members will be queried",
+			consortium.Policy.NumQueries, len(consortium.Members))
+	}
+
+	if required := consortium.NumQueries(); verified < required {
+		problems.add(SeverityError, CodePolicyViolation, "", "consortium config is signed by %d members, %d required",
+			verified, required)
+	}
+
+	if consortium.Policy.Cache.MaxAge == 0 {
+		problems.add(SeverityWarning, CodePolicyViolation, "policy.cache.maxAge",
+			"cache maxAge is 0, the config will be fetched again on every request")
+	}
+}
+
+// ValidateStakeholderConfig validates a stakeholder config file, returning the problems found
+func ValidateStakeholderConfig(data []byte, opts ...Option) Problems {
+	o := getOptions(opts)
+
+	var problems Problems
+
+	jws, err := jose.ParseSigned(string(data))
+	if err != nil {
+		problems.add(SeverityError, CodeInvalidJWS, "", "stakeholder config is not a valid JWS: %s", err)
+
+		return problems
+	}
+
+	stakeholder := &models.Stakeholder{}
+
+	if err := json.Unmarshal(jws.UnsafePayloadWithoutVerification(), stakeholder); err != nil {
+		problems.add(SeverityError, CodeInvalidPayload, "", "stakeholder config payload is invalid: %s", err)
+
+		return problems
+	}
+
+	if stakeholder.Domain == "" {
+		problems.add(SeverityError, CodeMissingField, "domain", "stakeholder domain is required")
+	}
+
+	if stakeholder.DID == "" {
+		problems.add(SeverityError, CodeMissingField, "did", "stakeholder DID is required")
+	}
+
+	if len(stakeholder.Endpoints) == 0 {
+		problems.add(SeverityError, CodeMissingField, "endpoints", "stakeholder must have at least one endpoint")
+	}
+
+	validateEndpoints(stakeholder.Endpoints, o, &problems)
+
+	if stakeholder.Policy.Cache.MaxAge == 0 {
+		problems.add(SeverityWarning, CodePolicyViolation, "policy.cache.maxAge",
+			"cache maxAge is 0, the config will be fetched again on every request")
+	}
+
+	return problems
+}
+
+func validateEndpoints(endpoints []string, o *options, problems *Problems) {
+	seen := map[string]bool{}
+
+	for i, endpoint := range endpoints {
+		field := fmt.Sprintf("endpoints[%d]", i)
+
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems.add(SeverityError, CodeInvalidField, field, "endpoint %s is not a valid http(s) URL", endpoint)
+
+			continue
+		}
+
+		if seen[endpoint] {
+			problems.add(SeverityWarning, CodeInvalidField, field, "duplicate endpoint %s", endpoint)
+
+			continue
+		}
+
+		seen[endpoint] = true
+
+		if o.checkReachability {
+			checkEndpoint(o.httpClient, endpoint, field, problems)
+		}
+	}
+}
+
+func checkEndpoint(client *http.Client, endpoint, field string, problems *Problems) {
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		problems.add(SeverityError, CodeUnreachable, field, "endpoint %s is unreachable: %s", endpoint, err)
+
+		return
+	}
+
+	resp.Body.Close() //nolint: errcheck,gosec
+}
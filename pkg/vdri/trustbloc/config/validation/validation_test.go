@@ -0,0 +1,225 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/square/go-jose/v3"
+	"github.com/stretchr/testify/require"
+
+	mockmodels "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/models"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+)
+
+type member struct {
+	element *models.StakeholderListElement
+	key     ed25519.PrivateKey
+}
+
+func newMember(t *testing.T, domain string) *member {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := (&jose.JSONWebKey{Key: pub}).MarshalJSON()
+	require.NoError(t, err)
+
+	return &member{
+		element: &models.StakeholderListElement{Domain: domain, DID: "did:trustbloc:" + domain + ":123",
+			PublicKey: models.PublicKey{ID: "did:trustbloc:" + domain + ":123#key", JWK: jwk}},
+		key: priv,
+	}
+}
+
+func signConsortium(t *testing.T, consortium *models.Consortium, signers ...*member) []byte {
+	var keys []jose.SigningKey
+
+	for _, s := range signers {
+		keys = append(keys, jose.SigningKey{Key: s.key, Algorithm: jose.EdDSA})
+	}
+
+	signer, err := jose.NewMultiSigner(keys, nil)
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(consortium)
+	require.NoError(t, err)
+
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+
+	return []byte(jws.FullSerialize())
+}
+
+func codes(problems Problems) []string {
+	var c []string
+
+	for _, p := range problems {
+		c = append(c, p.Code+":"+p.Field)
+	}
+
+	return c
+}
+
+func TestValidateConsortiumConfig(t *testing.T) {
+	m1 := newMember(t, "bar.baz")
+	m2 := newMember(t, "baz.qux")
+
+	t.Run("success", func(t *testing.T) {
+		consortium := &models.Consortium{Domain: "foo.bar", Members: []*models.StakeholderListElement{
+			m1.element, m2.element}, Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 600}}}
+
+		problems := ValidateConsortiumConfig(signConsortium(t, consortium, m1, m2))
+		require.Empty(t, problems)
+		require.False(t, problems.HasErrors())
+	})
+
+	t.Run("success - numQueries signatures", func(t *testing.T) {
+		consortium := &models.Consortium{Domain: "foo.bar", Members: []*models.StakeholderListElement{
+			m1.element, m2.element}, Policy: models.ConsortiumPolicy{NumQueries: 1,
+			Cache: models.CacheControl{MaxAge: 600}}}
+
+		problems := ValidateConsortiumConfig(signConsortium(t, consortium, m1))
+		require.False(t, problems.HasErrors())
+		require.Equal(t, []string{CodeInvalidJWS + ":members[1].publicKey.jwk"}, codes(problems))
+		require.Equal(t, SeverityWarning, problems[0].Severity)
+	})
+
+	t.Run("failure - insufficient signatures", func(t *testing.T) {
+		consortium := &models.Consortium{Domain: "foo.bar", Members: []*models.StakeholderListElement{
+			m1.element, m2.element}, Policy: models.ConsortiumPolicy{Cache: models.CacheControl{MaxAge: 600}}}
+
+		problems := ValidateConsortiumConfig(signConsortium(t, consortium, m1))
+		require.True(t, problems.HasErrors())
+		require.Contains(t, codes(problems), CodePolicyViolation+":")
+	})
+
+	t.Run("failure - not a JWS", func(t *testing.T) {
+		problems := ValidateConsortiumConfig([]byte("{"))
+		require.Equal(t, []string{CodeInvalidJWS + ":"}, codes(problems))
+		require.True(t, problems.HasErrors())
+	})
+
+	t.Run("failure - invalid payload", func(t *testing.T) {
+		problems := ValidateConsortiumConfig([]byte(mockmodels.DummyJWSWrap(`[]`)))
+		require.Equal(t, []string{CodeInvalidPayload + ":"}, codes(problems))
+	})
+
+	t.Run("failure - missing fields and bad policy", func(t *testing.T) {
+		consortium := &models.Consortium{Members: []*models.StakeholderListElement{
+			{Domain: "bar.baz", PublicKey: models.PublicKey{JWK: []byte(`[]`)}},
+			{Domain: "bar.baz"},
+			nil,
+		}, Policy: models.ConsortiumPolicy{NumQueries: -1}}
+
+		problems := ValidateConsortiumConfig(signConsortium(t, consortium, m1))
+		require.Equal(t, []string{
+			CodeMissingField + ":domain",
+			CodeMissingField + ":members[0].did",
+			CodeInvalidField + ":members[0].publicKey.jwk",
+			CodeInvalidField + ":members[1].domain",
+			CodeMissingField + ":members[1].did",
+			CodeMissingField + ":members[1].publicKey.jwk",
+			CodeMissingField + ":members[2]",
+			CodePolicyViolation + ":policy.numQueries",
+			CodePolicyViolation + ":",
+			CodePolicyViolation + ":policy.cache.maxAge",
+		}, codes(problems))
+	})
+
+	t.Run("failure - no members and numQueries too large", func(t *testing.T) {
+		consortium := &models.Consortium{Domain: "foo.bar", Policy: models.ConsortiumPolicy{NumQueries: 2,
+			Cache: models.CacheControl{MaxAge: 600}}}
+
+		problems := ValidateConsortiumConfig(signConsortium(t, consortium, m1))
+		require.Equal(t, []string{
+			CodeMissingField + ":members",
+			CodePolicyViolation + ":policy.numQueries",
+		}, codes(problems))
+	})
+
+	t.Run("failure - unreachable member", func(t *testing.T) {
+		stakeholderFile, err := mockmodels.DummyStakeholderJSON("bar.baz", []string{"https://bar.baz/sidetree"})
+		require.NoError(t, err)
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, stakeholderFile)
+		}))
+		defer serv.Close()
+
+		reachable := newMember(t, serv.URL)
+		unreachable := newMember(t, "https://0.0.0.0:0")
+
+		consortium := &models.Consortium{Domain: "foo.bar", Members: []*models.StakeholderListElement{
+			reachable.element, unreachable.element}, Policy: models.ConsortiumPolicy{
+			Cache: models.CacheControl{MaxAge: 600}}}
+
+		problems := ValidateConsortiumConfig(signConsortium(t, consortium, reachable, unreachable),
+			CheckReachability(&tls.Config{MinVersion: tls.VersionTLS12}))
+		require.Equal(t, []string{CodeUnreachable + ":members[1].domain"}, codes(problems))
+	})
+}
+
+func TestValidateStakeholderConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		data, err := mockmodels.WrapStakeholder(&models.Stakeholder{Domain: "bar.baz", DID: "did:trustbloc:bar.baz:123",
+			Endpoints: []string{"https://bar.baz/sidetree"}, Policy: models.StakeholderSettings{
+				Cache: models.CacheControl{MaxAge: 600}}})
+		require.NoError(t, err)
+
+		require.Empty(t, ValidateStakeholderConfig([]byte(data)))
+	})
+
+	t.Run("failure - not a JWS", func(t *testing.T) {
+		problems := ValidateStakeholderConfig([]byte("{"))
+		require.Equal(t, []string{CodeInvalidJWS + ":"}, codes(problems))
+	})
+
+	t.Run("failure - invalid payload", func(t *testing.T) {
+		problems := ValidateStakeholderConfig([]byte(mockmodels.DummyJWSWrap(`[]`)))
+		require.Equal(t, []string{CodeInvalidPayload + ":"}, codes(problems))
+	})
+
+	t.Run("failure - missing fields", func(t *testing.T) {
+		data, err := mockmodels.WrapStakeholder(&models.Stakeholder{})
+		require.NoError(t, err)
+
+		problems := ValidateStakeholderConfig([]byte(data))
+		require.Equal(t, []string{
+			CodeMissingField + ":domain",
+			CodeMissingField + ":did",
+			CodeMissingField + ":endpoints",
+			CodePolicyViolation + ":policy.cache.maxAge",
+		}, codes(problems))
+	})
+
+	t.Run("failure - invalid endpoints", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer serv.Close()
+
+		data, err := mockmodels.WrapStakeholder(&models.Stakeholder{Domain: "bar.baz", DID: "did:trustbloc:bar.baz:123",
+			Endpoints: []string{serv.URL, "ws://bar.baz", "%zz", serv.URL, "https://0.0.0.0:0"},
+			Policy:    models.StakeholderSettings{Cache: models.CacheControl{MaxAge: 600}}})
+		require.NoError(t, err)
+
+		problems := ValidateStakeholderConfig([]byte(data), CheckReachability(nil))
+		require.Equal(t, []string{
+			CodeInvalidField + ":endpoints[1]",
+			CodeInvalidField + ":endpoints[2]",
+			CodeInvalidField + ":endpoints[3]",
+			CodeUnreachable + ":endpoints[4]",
+		}, codes(problems))
+		require.Equal(t, SeverityWarning, problems[2].Severity)
+	})
+}
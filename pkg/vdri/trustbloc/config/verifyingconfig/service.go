@@ -10,8 +10,7 @@ import (
 	"fmt"
 	"math/rand"
 
-	log "github.com/sirupsen/logrus"
-
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 )
 
@@ -24,12 +23,28 @@ type config interface {
 // ConfigService fetches consortium and stakeholder configs over http
 type ConfigService struct {
 	config config
+	logger logger.Logger
+}
+
+// Option is a config service instance option
+type Option func(opts *ConfigService)
+
+// WithLogger sets the logger of the service. Defaults to logger.Default.
+func WithLogger(l logger.Logger) Option {
+	return func(opts *ConfigService) {
+		opts.logger = l
+	}
 }
 
 // NewService create new ConfigService
-func NewService(config config) *ConfigService {
+func NewService(config config, opts ...Option) *ConfigService {
 	configService := &ConfigService{
 		config: config,
+		logger: logger.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(configService)
 	}
 
 	return configService
@@ -47,12 +62,7 @@ func (cs *ConfigService) GetConsortium(url, domain string) (*models.ConsortiumFi
 		return nil, fmt.Errorf("consortium is nil")
 	}
 
-	n := consortium.Policy.NumQueries
-
-	// if ds.numStakeholders is 0, then we use all stakeholders
-	if n == 0 {
-		n = len(consortium.Members)
-	}
+	n := consortium.NumQueries()
 
 	perm := rand.Perm(len(consortium.Members))
 
@@ -67,7 +77,8 @@ func (cs *ConfigService) GetConsortium(url, domain string) (*models.ConsortiumFi
 		file, err := cs.config.GetConsortium(stakeholder, domain)
 		if err != nil {
 			msg := "stakeholder peer failed to return consortium config: " + err.Error()
-			log.Warn(msg)
+			cs.logger.Warn("stakeholder peer failed to return consortium config", "stakeholder", stakeholder,
+				"domain", domain, "error", err)
 			verificationErrors += msg + ", "
 
 			continue // skip failed stakeholders
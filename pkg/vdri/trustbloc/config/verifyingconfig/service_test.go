@@ -139,6 +139,26 @@ func TestConfigService_GetConsortium(t *testing.T) {
 		require.Contains(t, err.Error(), "endorsement")
 	})
 
+	t.Run("success - numQueries larger than the number of members", func(t *testing.T) {
+		consortium := mockmodels.DummyConsortium("foo.bar", []*models.StakeholderListElement{{Domain: "bar.baz"}})
+		consortium.Policy.NumQueries = 5
+
+		consortiumFile, err := mockmodels.WrapConsortium(consortium)
+		require.NoError(t, err)
+
+		consortiumData, err := models.ParseConsortium([]byte(consortiumFile))
+		require.NoError(t, err)
+
+		cs := NewService(&mockconfig.MockConfigService{
+			GetConsortiumFunc: func(u string, d string) (*models.ConsortiumFileData, error) {
+				return consortiumData, nil
+			}})
+
+		conf, err := cs.GetConsortium("foo.bar", "foo.bar")
+		require.NoError(t, err)
+		require.Equal(t, "foo.bar", conf.Config.Domain)
+	})
+
 	t.Run("failure - errors fetching consortium", func(t *testing.T) {
 		cs := NewService(&mockconfig.MockConfigService{
 			GetConsortiumFunc: func(u string, d string) (*models.ConsortiumFileData, error) {
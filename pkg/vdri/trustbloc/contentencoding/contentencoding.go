@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contentencoding
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// AcceptEncoding is the Accept-Encoding header of the requests whose response body is read with ReadBody
+const AcceptEncoding = "gzip, deflate"
+
+// SetAcceptEncoding sets the Accept-Encoding header of the request, so that large DID documents can be sent
+// compressed. Setting the header disables the transparent gzip decompression of the transport, the response body is
+// then to be read with ReadBody.
+func SetAcceptEncoding(req *http.Request) {
+	req.Header.Set("Accept-Encoding", AcceptEncoding)
+}
+
+// NewReader returns a reader of the body of the response decompressing it as it is read, according to its
+// Content-Encoding header, so that a large body is never held both compressed and decompressed. A deflate body is
+// read as zlib data as per RFC 7230, or else as raw deflate data, as sent by some servers. Closing the reader doesn't
+// close the body.
+func NewReader(resp *http.Response) (io.ReadCloser, error) {
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+		return ioutil.NopCloser(resp.Body), nil
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip body: %w", err)
+		}
+
+		return r, nil
+	case "deflate":
+		body := bufio.NewReader(resp.Body)
+		if !isZlib(body) {
+			return flate.NewReader(body), nil
+		}
+
+		r, err := zlib.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read deflate body: %w", err)
+		}
+
+		return r, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %s", encoding)
+	}
+}
+
+// ReadBody reads the body of the response, decompressed as by NewReader
+func ReadBody(resp *http.Response) ([]byte, error) {
+	r, err := NewReader(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close() //nolint: errcheck
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	return body, nil
+}
+
+const (
+	zlibHeaderLength = 2
+	zlibMethodMask   = 0x0f
+	zlibMethodFlate  = 8
+	zlibCheckDivisor = 31
+)
+
+// isZlib returns whether the data starts with a zlib header, which has the deflate compression method and a check
+// value making it a multiple of 31
+func isZlib(r *bufio.Reader) bool {
+	header, err := r.Peek(zlibHeaderLength)
+	if err != nil {
+		return false
+	}
+
+	return header[0]&zlibMethodMask == zlibMethodFlate && binary.BigEndian.Uint16(header)%zlibCheckDivisor == 0
+}
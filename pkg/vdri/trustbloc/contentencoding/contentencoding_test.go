@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contentencoding
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const doc = `{"id": "did:trustbloc:testnet:123"}`
+
+func TestSetAcceptEncoding(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	SetAcceptEncoding(req)
+	require.Equal(t, "gzip, deflate", req.Header.Get("Accept-Encoding"))
+}
+
+func TestReadBody(t *testing.T) {
+	t.Run("test encodings", func(t *testing.T) {
+		for encoding, body := range map[string][]byte{
+			"":         []byte(doc),
+			"identity": []byte(doc),
+			"gzip":     compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }),
+			"x-gzip":   compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }),
+			"Deflate":  compress(t, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }),
+			"deflate": compress(t, func(w io.Writer) io.WriteCloser {
+				fw, err := flate.NewWriter(w, flate.DefaultCompression)
+				require.NoError(t, err)
+
+				return fw
+			}),
+		} {
+			read, err := ReadBody(response(encoding, body))
+			require.NoError(t, err, encoding)
+			require.Equal(t, doc, string(read), encoding)
+		}
+	})
+
+	t.Run("test unsupported encoding", func(t *testing.T) {
+		_, err := ReadBody(response("br", []byte(doc)))
+		require.EqualError(t, err, "unsupported content encoding br")
+	})
+
+	t.Run("test invalid compressed body", func(t *testing.T) {
+		_, err := ReadBody(response("gzip", []byte(doc)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read gzip body")
+
+		_, err = ReadBody(response("deflate", []byte{0x78, 0xbb}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read deflate body")
+
+		_, err = ReadBody(response("deflate", []byte(doc)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read body")
+	})
+
+	t.Run("test read error", func(t *testing.T) {
+		resp := response("", nil)
+		resp.Body = ioutil.NopCloser(&failingReader{})
+
+		_, err := ReadBody(resp)
+		require.EqualError(t, err, "failed to read body: read error")
+	})
+}
+
+func TestNewReader(t *testing.T) {
+	resp := response("gzip", compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }))
+
+	r, err := NewReader(resp)
+	require.NoError(t, err)
+
+	var decoded map[string]string
+	require.NoError(t, json.NewDecoder(r).Decode(&decoded))
+	require.NoError(t, r.Close())
+	require.Equal(t, "did:trustbloc:testnet:123", decoded["id"])
+}
+
+func response(encoding string, body []byte) *http.Response {
+	resp := &http.Response{Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewReader(body))}
+	if encoding != "" {
+		resp.Header.Set("Content-Encoding", encoding)
+	}
+
+	return resp
+}
+
+func compress(t *testing.T, newWriter func(w io.Writer) io.WriteCloser) []byte {
+	var buf bytes.Buffer
+
+	w := newWriter(&buf)
+
+	_, err := w.Write([]byte(doc))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dialer
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// New returns a dialer with the timeout and TCP keep-alive interval, resolving the hosts with the resolver, or with
+// the default resolver if nil. If allowed networks are given, the dialer refuses to connect to the addresses outside
+// of them, which are checked once resolved, so that a host can't be made to resolve to another address.
+func New(timeout, keepAlive time.Duration, resolver *net.Resolver, allowed []*net.IPNet) *net.Dialer {
+	d := &net.Dialer{Timeout: timeout, KeepAlive: keepAlive, Resolver: resolver}
+
+	if len(allowed) != 0 {
+		d.Control = allowNetworks(allowed)
+	}
+
+	return d
+}
+
+func allowNetworks(allowed []*net.IPNet) func(network, address string, _ syscall.RawConn) error {
+	return func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+
+		if ip := net.ParseIP(host); ip != nil {
+			for _, n := range allowed {
+				if n.Contains(ip) {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("address %s is not in the allowed networks", address)
+	}
+}
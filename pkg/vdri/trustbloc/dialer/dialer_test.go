@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dialer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, listener.Close()) }()
+
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	_, private, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	t.Run("test options", func(t *testing.T) {
+		resolver := &net.Resolver{}
+
+		d := New(time.Second, time.Minute, resolver, nil)
+		require.Equal(t, time.Second, d.Timeout)
+		require.Equal(t, time.Minute, d.KeepAlive)
+		require.Same(t, resolver, d.Resolver)
+		require.Nil(t, d.Control)
+	})
+
+	t.Run("test allowed address", func(t *testing.T) {
+		conn, err := New(time.Second, 0, nil, []*net.IPNet{private, loopback}).Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	})
+
+	t.Run("test address not allowed", func(t *testing.T) {
+		_, err := New(time.Second, 0, nil, []*net.IPNet{private}).Dial("tcp", listener.Addr().String())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not in the allowed networks")
+	})
+
+	t.Run("test invalid address", func(t *testing.T) {
+		err := allowNetworks([]*net.IPNet{private})("tcp", "10.0.0.1", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing port")
+	})
+
+	t.Run("test resolver", func(t *testing.T) {
+		resolver := &net.Resolver{PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return nil, errors.New("dns error")
+			}}
+
+		_, err := New(time.Second, 0, resolver, nil).Dial("tcp", "sidetree.example.com:443")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "dns error")
+	})
+}
@@ -27,15 +27,16 @@ type Service struct {
 
 // NewService create new didconfiguration Service
 func NewService(opts ...Option) *Service {
-	service := &Service{
-		httpClient: &http.Client{},
-	}
+	service := &Service{}
 
 	for _, opt := range opts {
 		opt(service)
 	}
 
-	service.httpClient.Transport = &http.Transport{TLSClientConfig: service.tlsConfig}
+	if service.httpClient == nil {
+		service.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: service.tlsConfig,
+			Proxy: http.ProxyFromEnvironment}}
+	}
 
 	return service
 }
@@ -102,3 +103,11 @@ func WithTLSConfig(tlsConfig *tls.Config) Option {
 		opts.tlsConfig = tlsConfig
 	}
 }
+
+// WithHTTPClient option sets the HTTP client of the service, such as a client sharing its transport with other
+// requests. The TLS config of WithTLSConfig is then not used.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *Service) {
+		opts.httpClient = client
+	}
+}
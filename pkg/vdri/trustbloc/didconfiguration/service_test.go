@@ -122,3 +122,11 @@ func TestOpts(t *testing.T) {
 		require.Equal(t, "test", s.tlsConfig.ServerName)
 	})
 }
+
+func TestService_WithHTTPClient(t *testing.T) {
+	client := &http.Client{}
+
+	s := NewService(WithHTTPClient(client), WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	require.Same(t, client, s.httpClient)
+	require.Nil(t, client.Transport)
+}
@@ -9,6 +9,7 @@ package staticdiscovery
 import (
 	"fmt"
 
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 )
 
@@ -20,12 +21,28 @@ type config interface {
 // DiscoveryService fetches endpoints for a consortium
 type DiscoveryService struct {
 	config config
+	logger logger.Logger
+}
+
+// Option is a discovery service instance option
+type Option func(opts *DiscoveryService)
+
+// WithLogger sets the logger of the service. Defaults to logger.Default.
+func WithLogger(l logger.Logger) Option {
+	return func(opts *DiscoveryService) {
+		opts.logger = l
+	}
 }
 
 // NewService create new DiscoveryService
-func NewService(c config) *DiscoveryService {
+func NewService(c config, opts ...Option) *DiscoveryService {
 	endpointService := &DiscoveryService{
 		config: c,
+		logger: logger.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(endpointService)
 	}
 
 	return endpointService
@@ -48,7 +65,12 @@ func (ds *DiscoveryService) GetEndpoints(consortiumDomain string) ([]*models.End
 		return nil, fmt.Errorf("stakeholder config: %w", err)
 	}
 
-	return ds.getEndpointsFromStakeholders(stakeholders), nil
+	endpoints := ds.getEndpointsFromStakeholders(stakeholders)
+
+	ds.logger.Debug("discovered sidetree endpoints", "domain", consortiumDomain, "stakeholders", len(stakeholders),
+		"endpoints", len(endpoints))
+
+	return endpoints, nil
 }
 
 // getStakeholderConfigs gets the list of stakeholder configs
@@ -14,6 +14,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	mocklogger "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/logger"
 	mockmodels "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/models"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
@@ -54,10 +55,19 @@ func TestDiscoveryService_GetEndpoints(t *testing.T) {
 		}))
 		defer stakeholderServ2.Close()
 
-		s := NewService(httpconfig.NewService(httpconfig.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})))
+		l := &mocklogger.Logger{}
+
+		s := NewService(httpconfig.NewService(httpconfig.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})),
+			WithLogger(l))
 		endpoints, err := s.GetEndpoints(consortiumServ.URL)
 		require.NoError(t, err)
 		require.Len(t, endpoints, 4)
+
+		entry := l.Entry("discovered sidetree endpoints")
+		require.NotNil(t, entry)
+		require.Equal(t, "debug", entry.Level)
+		require.Equal(t, consortiumServ.URL, entry.Fields["domain"])
+		require.Equal(t, 4, entry.Fields["endpoints"])
 	})
 
 	t.Run("failure: stakeholder server failure", func(t *testing.T) {
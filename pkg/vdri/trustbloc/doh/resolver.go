@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package doh provides a DNS resolver querying a trusted DNS-over-HTTPS server, for the WithResolver options of the
+// did client and the VDRI, so that the consortium domain and the sidetree endpoints can't be redirected by spoofing
+// the DNS answers of the local network.
+package doh
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
+)
+
+const (
+	dnsMessageType = "application/dns-message"
+
+	headerSize       = 12
+	maxMessageSize   = 65535
+	lengthPrefixSize = 2
+	// the AD (authenticated data) bit is in the fourth byte of the header of the DNS messages
+	flagsByte = 3
+	adFlag    = 0x20
+
+	defaultTimeout = 10 * time.Second
+)
+
+// ErrNotAuthenticated is returned by the lookups of the names of the domains of WithDNSSEC, when the DNS-over-HTTPS
+// server does not answer them as authenticated with DNSSEC
+var ErrNotAuthenticated = errors.New("answer not authenticated with DNSSEC")
+
+// Option is a DNS-over-HTTPS resolver option
+type Option func(opts *resolver)
+
+// WithHTTPClient sets the HTTP client of the queries to the DNS-over-HTTPS server, which defaults to a client with a
+// 10 seconds timeout verifying the server certificate against the system certificate pool
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *resolver) {
+		opts.httpClient = client
+	}
+}
+
+// WithDNSSEC requires the answers of the names of the domains and of their subdomains, such as the consortium
+// domain, to be authenticated with DNSSEC. The DNS-over-HTTPS server must then validate DNSSEC, which it reports with
+// the AD bit of its answers, and the zones of the domains must be signed.
+func WithDNSSEC(domains ...string) Option {
+	return func(opts *resolver) {
+		for _, domain := range domains {
+			opts.dnssecDomains = append(opts.dnssecDomains, strings.TrimSuffix(strings.ToLower(domain), "."))
+		}
+	}
+}
+
+type resolver struct {
+	serverURL     string
+	httpClient    *http.Client
+	dnssecDomains []string
+}
+
+// NewResolver returns a resolver sending its DNS queries to the DNS-over-HTTPS server, such as
+// https://1.1.1.1/dns-query, as specified by RFC 8484. The host of the server URL is resolved by the system resolver,
+// unless it is an IP address.
+func NewResolver(serverURL string, opts ...Option) *net.Resolver {
+	r := &resolver{
+		serverURL: serverURL,
+		httpClient: &http.Client{Timeout: defaultTimeout, Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsconfig.New(),
+		}},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return &conn{ctx: ctx, resolver: r}, nil
+		},
+	}
+}
+
+// exchange sends the DNS query to the DNS-over-HTTPS server and returns its answer
+func (r *resolver) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	if len(query) < headerSize {
+		return nil, fmt.Errorf("invalid DNS query of %d bytes", len(query))
+	}
+
+	name := questionName(query)
+	dnssec := r.requiresDNSSEC(name)
+
+	if dnssec {
+		// asks the server for the AD bit, as specified by RFC 6840
+		query[flagsByte] |= adFlag
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.serverURL, bytes.NewReader(query))
+	if err != nil {
+		return nil, fmt.Errorf("create DNS-over-HTTPS request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", dnsMessageType)
+	req.Header.Set("Accept", dnsMessageType)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("DNS-over-HTTPS request: %w", err)
+	}
+
+	// nolint: errcheck
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DNS-over-HTTPS server answered with status %d", resp.StatusCode)
+	}
+
+	answer, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("read DNS-over-HTTPS answer: %w", err)
+	}
+
+	err = checkAnswer(answer, name, dnssec)
+	if err != nil {
+		return nil, err
+	}
+
+	return answer, nil
+}
+
+// checkAnswer checks the answer is a DNS message, authenticated with DNSSEC if required
+func checkAnswer(answer []byte, name string, dnssec bool) error {
+	if len(answer) < headerSize {
+		return fmt.Errorf("invalid DNS answer of %d bytes", len(answer))
+	}
+
+	if dnssec && answer[flagsByte]&adFlag == 0 {
+		return fmt.Errorf("%s: %w", name, ErrNotAuthenticated)
+	}
+
+	return nil
+}
+
+func (r *resolver) requiresDNSSEC(name string) bool {
+	for _, domain := range r.dnssecDomains {
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// questionName returns the lower case name of the question of the DNS query, without the trailing dot
+func questionName(query []byte) string {
+	var labels []string
+
+	for i := headerSize; i < len(query); {
+		length := int(query[i])
+		if length == 0 || i+1+length > len(query) {
+			break
+		}
+
+		labels = append(labels, string(query[i+1:i+1+length]))
+		i += 1 + length
+	}
+
+	return strings.ToLower(strings.Join(labels, "."))
+}
+
+// conn is the connection the Go resolver sends its DNS queries to, as to a TCP name server: each query written is
+// prefixed by its length, and exchanged with the DNS-over-HTTPS server for the answer read next
+type conn struct {
+	ctx      context.Context
+	resolver *resolver
+	answer   bytes.Buffer
+}
+
+func (c *conn) Write(b []byte) (int, error) {
+	if len(b) < lengthPrefixSize || int(binary.BigEndian.Uint16(b)) != len(b)-lengthPrefixSize {
+		return 0, fmt.Errorf("invalid DNS query of %d bytes", len(b))
+	}
+
+	answer, err := c.resolver.exchange(c.ctx, append([]byte(nil), b[lengthPrefixSize:]...))
+	if err != nil {
+		return 0, err
+	}
+
+	c.answer.Reset()
+
+	prefix := make([]byte, lengthPrefixSize)
+	binary.BigEndian.PutUint16(prefix, uint16(len(answer)))
+
+	c.answer.Write(prefix)
+	c.answer.Write(answer)
+
+	return len(b), nil
+}
+
+func (c *conn) Read(b []byte) (int, error) {
+	return c.answer.Read(b)
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+// the deadlines of the lookups are the deadlines of the contexts of the DNS-over-HTTPS requests
+
+func (c *conn) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (c *conn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *conn) SetWriteDeadline(time.Time) error {
+	return nil
+}
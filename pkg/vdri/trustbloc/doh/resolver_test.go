@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package doh
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const typeA = 1
+
+func TestNewResolver(t *testing.T) {
+	var (
+		authenticated bool
+		adQueried     bool
+		status        = http.StatusOK
+	)
+
+	serv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, dnsMessageType, r.Header.Get("Content-Type"))
+
+		query, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		adQueried = query[flagsByte]&adFlag != 0
+
+		w.Header().Set("Content-Type", dnsMessageType)
+		w.WriteHeader(status)
+
+		_, err = w.Write(answer(query, authenticated))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	reset := func(auth bool) {
+		authenticated, adQueried, status = auth, false, http.StatusOK
+	}
+
+	t.Run("test lookup", func(t *testing.T) {
+		reset(false)
+
+		addrs, err := NewResolver(serv.URL, WithHTTPClient(serv.Client())).LookupHost(context.Background(),
+			"consortium.example.com")
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.0.1"}, addrs)
+		require.False(t, adQueried)
+	})
+
+	t.Run("test lookup authenticated with DNSSEC", func(t *testing.T) {
+		reset(true)
+
+		addrs, err := NewResolver(serv.URL, WithHTTPClient(serv.Client()), WithDNSSEC("Example.com.")).LookupHost(
+			context.Background(), "consortium.example.com")
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.0.1"}, addrs)
+		require.True(t, adQueried)
+	})
+
+	t.Run("test lookup not authenticated with DNSSEC", func(t *testing.T) {
+		reset(false)
+
+		_, err := NewResolver(serv.URL, WithHTTPClient(serv.Client()), WithDNSSEC("example.com")).LookupHost(
+			context.Background(), "consortium.example.com")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "answer not authenticated with DNSSEC")
+	})
+
+	t.Run("test lookup of another domain than the DNSSEC domains", func(t *testing.T) {
+		reset(false)
+
+		addrs, err := NewResolver(serv.URL, WithHTTPClient(serv.Client()), WithDNSSEC("example.com")).LookupHost(
+			context.Background(), "consortium.example.org")
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.0.1"}, addrs)
+		require.False(t, adQueried)
+	})
+
+	t.Run("test server error", func(t *testing.T) {
+		reset(false)
+		status = http.StatusInternalServerError
+
+		_, err := NewResolver(serv.URL, WithHTTPClient(serv.Client())).LookupHost(context.Background(),
+			"consortium.example.com")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DNS-over-HTTPS server answered with status 500")
+	})
+
+	t.Run("test server certificate not trusted", func(t *testing.T) {
+		reset(false)
+
+		_, err := NewResolver(serv.URL).LookupHost(context.Background(), "consortium.example.com")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DNS-over-HTTPS request")
+	})
+}
+
+func TestConn(t *testing.T) {
+	c := &conn{ctx: context.Background(), resolver: &resolver{serverURL: "https://localhost"}}
+
+	_, err := c.Write([]byte{0, 10, 1})
+	require.EqualError(t, err, "invalid DNS query of 3 bytes")
+
+	_, err = c.Write([]byte{0, 1, 1})
+	require.EqualError(t, err, "invalid DNS query of 1 bytes")
+
+	require.NoError(t, c.Close())
+	require.NotNil(t, c.LocalAddr())
+	require.NotNil(t, c.RemoteAddr())
+}
+
+func TestQuestionName(t *testing.T) {
+	require.Equal(t, "consortium.example.com", questionName(query("Consortium.Example.COM")))
+	require.Empty(t, questionName(make([]byte, headerSize)))
+	truncated := append(make([]byte, headerSize), 7)
+	truncated = append(truncated, "example"...)
+	require.Equal(t, "example", questionName(append(truncated, 10, 'c')))
+}
+
+// answer returns the answer to the query, with the 10.0.0.1 address for the A queries and no address for the others
+func answer(query []byte, authenticated bool) []byte {
+	// the type and the class of the question follow its name
+	i := headerSize
+	for query[i] != 0 {
+		i += 1 + int(query[i])
+	}
+
+	// the answer has the question of the query but not its additional records, such as an EDNS0 record
+	a := append([]byte(nil), query[:i+5]...)
+	binary.BigEndian.PutUint16(a[10:], 0) // ARCOUNT
+
+	a[2] |= 0x80 // QR
+	a[3] = 0x80  // RA
+
+	if authenticated {
+		a[3] |= adFlag
+	}
+
+	if binary.BigEndian.Uint16(query[i+1:]) != typeA {
+		return a
+	}
+
+	binary.BigEndian.PutUint16(a[6:], 1) // ANCOUNT
+
+	// name pointer to the question, type A, class IN, TTL, length and address
+	return append(a, 0xc0, headerSize, 0, typeA, 0, 1, 0, 0, 0, 60, 0, 4, 10, 0, 0, 1)
+}
+
+// query returns the A query of the name
+func query(name string) []byte {
+	q := make([]byte, headerSize)
+
+	for _, label := range strings.Split(name, ".") {
+		q = append(q, byte(len(label)))
+		q = append(q, label...)
+	}
+
+	return append(q, 0, 0, typeA, 0, 1)
+}
@@ -8,7 +8,9 @@ package endpoint
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 )
 
@@ -24,13 +26,29 @@ type selection interface {
 type EndpointService struct { // nolint: golint
 	discovery discovery
 	selection selection
+	metrics   metrics.Provider
+}
+
+// Option is an option for EndpointService
+type Option func(opts *EndpointService)
+
+// WithMetricsProvider option records endpoint discovery metrics with the given provider
+func WithMetricsProvider(p metrics.Provider) Option {
+	return func(opts *EndpointService) {
+		opts.metrics = p
+	}
 }
 
 // NewService create new EndpointService
-func NewService(d discovery, s selection) *EndpointService {
+func NewService(d discovery, s selection, opts ...Option) *EndpointService {
 	endpointService := &EndpointService{
 		discovery: d,
 		selection: s,
+		metrics:   metrics.NoopProvider{},
+	}
+
+	for _, opt := range opts {
+		opt(endpointService)
 	}
 
 	return endpointService
@@ -38,6 +56,17 @@ func NewService(d discovery, s selection) *EndpointService {
 
 // GetEndpoints get a list of endpoints to use from a consortium at a given domain
 func (es *EndpointService) GetEndpoints(domain string) ([]*models.Endpoint, error) {
+	start := time.Now()
+
+	out, err := es.getEndpoints(domain)
+
+	metrics.Record(es.metrics, metrics.EndpointDiscoveryRequests, metrics.EndpointDiscoveryDuration,
+		domain, start, err)
+
+	return out, err
+}
+
+func (es *EndpointService) getEndpoints(domain string) ([]*models.Endpoint, error) {
 	eps, err := es.discovery.GetEndpoints(domain)
 	if err != nil {
 		return nil, fmt.Errorf("discovery: %w", err)
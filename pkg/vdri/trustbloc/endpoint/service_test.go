@@ -17,10 +17,12 @@ import (
 
 	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
 	mockdiscovery "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/discovery"
+	mockmetrics "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/metrics"
 	mockmodels "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/models"
 	mockselection "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/selection"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/discovery/staticdiscovery"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/selection/staticselection"
 )
@@ -114,3 +116,34 @@ func TestEndpointService_GetEndpoints(t *testing.T) {
 		require.Contains(t, err.Error(), "selection error")
 	})
 }
+
+func TestEndpointService_Metrics(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		p := mockmetrics.NewProvider()
+
+		endpointService := NewService(&mockdiscovery.MockDiscoveryService{}, &mockselection.MockSelectionService{},
+			WithMetricsProvider(p))
+
+		_, err := endpointService.GetEndpoints("foo.bar")
+		require.NoError(t, err)
+
+		require.Equal(t, 1, p.Count(metrics.EndpointDiscoveryRequests, "foo.bar", metrics.StatusSuccess))
+		require.Equal(t, 1, p.Observations(metrics.EndpointDiscoveryDuration, "foo.bar", metrics.StatusSuccess))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		p := mockmetrics.NewProvider()
+
+		endpointService := NewService(&mockdiscovery.MockDiscoveryService{
+			GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+				return nil, fmt.Errorf("discovery error")
+			},
+		}, &mockselection.MockSelectionService{}, WithMetricsProvider(p))
+
+		_, err := endpointService.GetEndpoints("foo.bar")
+		require.Error(t, err)
+
+		require.Equal(t, 1, p.Count(metrics.EndpointDiscoveryRequests, "foo.bar", metrics.StatusFailure))
+		require.Equal(t, 1, p.Observations(metrics.EndpointDiscoveryDuration, "foo.bar", metrics.StatusFailure))
+	})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustbloc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/contentencoding"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/representation"
+)
+
+// didResolution is a resolution result, whose document is kept raw to be parsed once, without unmarshalling and
+// marshalling it again, as large documents would then be held several times in memory
+type didResolution struct {
+	DIDDocument json.RawMessage `json:"didDocument"`
+}
+
+// httpResolver resolves DIDs with a sidetree node as the aries http binding VDR does, but with the http client of
+// the VDRI, so that the resolutions go through its transport and proxy
+type httpResolver struct {
+	endpointURL string
+	client      *http.Client
+	authToken   string
+	// accept is the representation of the DID documents asked for
+	accept string
+	logger logger.Logger
+}
+
+func newHTTPResolver(endpointURL string, client *http.Client, authToken, accept string,
+	l logger.Logger) (*httpResolver, error) {
+	if _, err := url.ParseRequestURI(endpointURL); err != nil {
+		return nil, fmt.Errorf("base URL invalid: %w", err)
+	}
+
+	return &httpResolver{endpointURL: endpointURL, client: client, authToken: authToken, accept: accept, logger: l},
+		nil
+}
+
+// Read resolves the did with the sidetree node, which returns either the document or its resolution result, in any
+// of the representations of DID documents whatever the one asked for. The response is decompressed as it is read
+// into a single buffer, the document is then parsed from it.
+func (r *httpResolver) Read(didID string, _ ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	reqURL, err := url.ParseRequestURI(r.endpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("url parse request uri failed: %w", err)
+	}
+
+	reqURL.Path = path.Join(reqURL.Path, didID)
+
+	data, err := r.resolveDID(reqURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, vdrapi.ErrNotFound
+	}
+
+	var resolution didResolution
+	if err = json.Unmarshal(data, &resolution); err != nil {
+		return nil, fmt.Errorf("unmarshal data return from http binding resolver %w", err)
+	}
+
+	if len(resolution.DIDDocument) != 0 && string(resolution.DIDDocument) != "null" {
+		data = resolution.DIDDocument
+	}
+
+	doc, err := docdid.ParseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &docdid.DocResolution{DIDDocument: doc}, nil
+}
+
+func (r *httpResolver) resolveDID(uri string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP create get request failed: %w", err)
+	}
+
+	req.Header.Add("Accept", r.accept)
+	contentencoding.SetAcceptEncoding(req)
+
+	if r.authToken != "" {
+		req.Header.Add("Authorization", "Bearer "+r.authToken)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP Get request failed: %w", err)
+	}
+
+	defer r.closeResponseBody(resp.Body)
+
+	body, err := contentencoding.ReadBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body failed: %w", err)
+	}
+
+	mediaType := representation.MediaType(resp.Header.Get("Content-type"))
+
+	switch {
+	case resp.StatusCode == http.StatusOK && mediaType != "":
+		return representation.Unmarshal(body, mediaType)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("DID does not exist for request: %s", uri)
+	}
+
+	return nil, fmt.Errorf("unsupported response from DID resolver [%v] header [%s] body [%s]",
+		resp.StatusCode, resp.Header.Get("Content-type"), body)
+}
+
+func (r *httpResolver) closeResponseBody(respBody io.Closer) {
+	e := respBody.Close()
+	if e != nil {
+		r.logger.Error("failed to close response body", "error", e)
+	}
+}
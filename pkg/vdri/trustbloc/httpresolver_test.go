@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustbloc
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/contentencoding"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/representation"
+)
+
+const resolvedDoc = `{"@context": ["https://www.w3.org/ns/did/v1"], "id": "did:trustbloc:testnet:123"}`
+
+func TestHTTPResolver_Read(t *testing.T) {
+	t.Run("test invalid endpoint url", func(t *testing.T) {
+		_, err := newHTTPResolver("", &http.Client{}, "", representation.DIDLDJSON, logger.Default())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "base URL invalid")
+	})
+
+	t.Run("test document and resolution result", func(t *testing.T) {
+		for _, body := range []string{resolvedDoc, `{"didDocument": ` + resolvedDoc + `}`} {
+			var authorization string
+
+			serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/identifiers/did:trustbloc:testnet:123", r.URL.Path)
+				require.Equal(t, representation.DIDLDJSON, r.Header.Get("Accept"))
+
+				authorization = r.Header.Get("Authorization")
+
+				w.Header().Set("Content-type", representation.DIDLDJSON)
+				fmt.Fprint(w, body)
+			}))
+
+			r, err := newHTTPResolver(serv.URL+"/identifiers", serv.Client(), "tk1", representation.DIDLDJSON,
+				logger.Default())
+			require.NoError(t, err)
+
+			result, err := r.Read("did:trustbloc:testnet:123")
+			require.NoError(t, err)
+			require.Equal(t, "did:trustbloc:testnet:123", result.DIDDocument.ID)
+			require.Equal(t, "Bearer tk1", authorization)
+
+			serv.Close()
+		}
+	})
+
+	t.Run("test cbor representation", func(t *testing.T) {
+		docCBOR, err := representation.Marshal([]byte(resolvedDoc), representation.DIDCBOR)
+		require.NoError(t, err)
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, representation.DIDCBOR, r.Header.Get("Accept"))
+
+			w.Header().Set("Content-type", representation.DIDCBOR)
+			_, err = w.Write(docCBOR)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		r, err := newHTTPResolver(serv.URL, serv.Client(), "", representation.DIDCBOR, logger.Default())
+		require.NoError(t, err)
+
+		result, err := r.Read("did:trustbloc:testnet:123")
+		require.NoError(t, err)
+		require.Equal(t, "did:trustbloc:testnet:123", result.DIDDocument.ID)
+	})
+
+	t.Run("test compressed response", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, contentencoding.AcceptEncoding, r.Header.Get("Accept-Encoding"))
+
+			w.Header().Set("Content-type", representation.DIDLDJSON)
+			w.Header().Set("Content-Encoding", "gzip")
+
+			gw := gzip.NewWriter(w)
+			fmt.Fprint(gw, resolvedDoc)
+			require.NoError(t, gw.Close())
+		}))
+		defer serv.Close()
+
+		r, err := newHTTPResolver(serv.URL, serv.Client(), "", representation.DIDLDJSON, logger.Default())
+		require.NoError(t, err)
+
+		result, err := r.Read("did:trustbloc:testnet:123")
+		require.NoError(t, err)
+		require.Equal(t, "did:trustbloc:testnet:123", result.DIDDocument.ID)
+	})
+
+	t.Run("test error responses", func(t *testing.T) {
+		status := http.StatusOK
+		contentType := representation.DIDLDJSON
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-type", contentType)
+			w.WriteHeader(status)
+		}))
+		defer serv.Close()
+
+		r, err := newHTTPResolver(serv.URL, serv.Client(), "", representation.DIDLDJSON, logger.Default())
+		require.NoError(t, err)
+
+		_, err = r.Read("did:trustbloc:testnet:123")
+		require.Equal(t, vdrapi.ErrNotFound, err)
+
+		status = http.StatusNotFound
+
+		_, err = r.Read("did:trustbloc:testnet:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DID does not exist")
+
+		status = http.StatusOK
+		contentType = "text/plain"
+
+		_, err = r.Read("did:trustbloc:testnet:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported response from DID resolver")
+	})
+
+	t.Run("test request error", func(t *testing.T) {
+		r, err := newHTTPResolver("http://localhost:0", &http.Client{}, "", representation.DIDLDJSON,
+			logger.Default())
+		require.NoError(t, err)
+
+		_, err = r.Read("did:trustbloc:testnet:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "HTTP Get request failed")
+	})
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"net/url"
+	"time"
+)
+
+const (
+	// SidetreeConfigRequests counts sidetree config fetches
+	SidetreeConfigRequests = "sidetree_config_requests_total"
+	// SidetreeConfigDuration observes the latency of sidetree config fetches
+	SidetreeConfigDuration = "sidetree_config_duration_seconds"
+	// EndpointDiscoveryRequests counts endpoint discovery requests
+	EndpointDiscoveryRequests = "endpoint_discovery_requests_total"
+	// EndpointDiscoveryDuration observes the latency of endpoint discovery requests
+	EndpointDiscoveryDuration = "endpoint_discovery_duration_seconds"
+	// SidetreeOperationRequests counts sidetree operation requests
+	SidetreeOperationRequests = "sidetree_operation_requests_total"
+	// SidetreeOperationDuration observes the latency of sidetree operation requests
+	SidetreeOperationDuration = "sidetree_operation_duration_seconds"
+	// SidetreeResolveRequests counts sidetree resolution requests
+	SidetreeResolveRequests = "sidetree_resolve_requests_total"
+	// SidetreeResolveDuration observes the latency of sidetree resolution requests
+	SidetreeResolveDuration = "sidetree_resolve_duration_seconds"
+
+	// LabelDomain is the label holding the domain a request was made for
+	LabelDomain = "domain"
+	// LabelStatus is the label holding the outcome of a request
+	LabelStatus = "status"
+	// LabelOperation is the label holding the type of a sidetree operation: create, update, recover or deactivate
+	LabelOperation = "operation"
+
+	// StatusSuccess is the status label value of a successful request
+	StatusSuccess = "success"
+	// StatusFailure is the status label value of a failed request
+	StatusFailure = "failure"
+)
+
+// Provider records counters and latency histograms. Implementations must be safe for concurrent use.
+type Provider interface {
+	// IncrementCounter increments the named counter
+	IncrementCounter(name string, labels map[string]string)
+	// ObserveDuration records an observation in the named latency histogram
+	ObserveDuration(name string, duration time.Duration, labels map[string]string)
+}
+
+// NoopProvider is a Provider that discards all metrics
+type NoopProvider struct{}
+
+// IncrementCounter does nothing
+func (NoopProvider) IncrementCounter(string, map[string]string) {}
+
+// ObserveDuration does nothing
+func (NoopProvider) ObserveDuration(string, time.Duration, map[string]string) {}
+
+// Record increments the counter and observes the latency of a request made for the given domain
+func Record(p Provider, counter, histogram, domain string, start time.Time, err error) {
+	labels := map[string]string{LabelDomain: domain, LabelStatus: status(err)}
+
+	p.IncrementCounter(counter, labels)
+	p.ObserveDuration(histogram, time.Since(start), labels)
+}
+
+// RecordOperation increments the sidetree operation counter and observes the latency of a sidetree operation request
+// of the given type sent to the endpoint, labelled with the host of the endpoint
+func RecordOperation(p Provider, operation, endpointURL string, start time.Time, err error) {
+	labels := map[string]string{LabelDomain: DomainFromURL(endpointURL), LabelOperation: operation,
+		LabelStatus: status(err)}
+
+	p.IncrementCounter(SidetreeOperationRequests, labels)
+	p.ObserveDuration(SidetreeOperationDuration, time.Since(start), labels)
+}
+
+func status(err error) string {
+	if err != nil {
+		return StatusFailure
+	}
+
+	return StatusSuccess
+}
+
+// DomainFromURL returns the host of the given url, or the url itself if it has no host
+func DomainFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	return u.Host
+}
@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	mockmetrics "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/metrics"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+func TestRecord(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		p := mockmetrics.NewProvider()
+
+		metrics.Record(p, "requests", "duration", "foo.bar", time.Now(), nil)
+
+		require.Equal(t, 1, p.Count("requests", "foo.bar", metrics.StatusSuccess))
+		require.Equal(t, 1, p.Observations("duration", "foo.bar", metrics.StatusSuccess))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		p := mockmetrics.NewProvider()
+
+		metrics.Record(p, "requests", "duration", "foo.bar", time.Now(), errors.New("error"))
+
+		require.Equal(t, 1, p.Count("requests", "foo.bar", metrics.StatusFailure))
+		require.Equal(t, 0, p.Count("requests", "foo.bar", metrics.StatusSuccess))
+	})
+
+	t.Run("noop", func(t *testing.T) {
+		require.NotPanics(t, func() {
+			metrics.Record(metrics.NoopProvider{}, "requests", "duration", "foo.bar", time.Now(), nil)
+		})
+	})
+}
+
+func TestRecordOperation(t *testing.T) {
+	p := mockmetrics.NewProvider()
+
+	metrics.RecordOperation(p, "create", "https://foo.bar/sidetree/0.0.1", time.Now(), nil)
+	metrics.RecordOperation(p, "update", "https://foo.bar/sidetree/0.0.1", time.Now(), errors.New("error"))
+
+	require.Equal(t, 1, p.OperationCount(metrics.SidetreeOperationRequests, "foo.bar", "create",
+		metrics.StatusSuccess))
+	require.Equal(t, 1, p.OperationObservations(metrics.SidetreeOperationDuration, "foo.bar", "create",
+		metrics.StatusSuccess))
+	require.Equal(t, 1, p.OperationCount(metrics.SidetreeOperationRequests, "foo.bar", "update",
+		metrics.StatusFailure))
+	require.Equal(t, 0, p.OperationCount(metrics.SidetreeOperationRequests, "foo.bar", "update",
+		metrics.StatusSuccess))
+}
+
+func TestDomainFromURL(t *testing.T) {
+	require.Equal(t, "foo.bar:8080", metrics.DomainFromURL("https://foo.bar:8080/sidetree/0.0.1"))
+	require.Equal(t, "foo.bar", metrics.DomainFromURL("foo.bar"))
+	require.Equal(t, "%zz", metrics.DomainFromURL("%zz"))
+}
@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package promadapter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+// help are the help texts of the metrics recorded by the client and the VDRI
+var help = map[string]string{ //nolint: gochecknoglobals
+	metrics.SidetreeConfigRequests:    "Count of sidetree config fetches.",
+	metrics.SidetreeConfigDuration:    "Latency of sidetree config fetches.",
+	metrics.EndpointDiscoveryRequests: "Count of endpoint discovery requests.",
+	metrics.EndpointDiscoveryDuration: "Latency of endpoint discovery requests.",
+	metrics.SidetreeOperationRequests: "Count of sidetree operation requests.",
+	metrics.SidetreeOperationDuration: "Latency of sidetree operation requests.",
+	metrics.SidetreeResolveRequests:   "Count of sidetree resolution requests.",
+	metrics.SidetreeResolveDuration:   "Latency of sidetree resolution requests.",
+}
+
+// Provider is a metrics.Provider registering the counters and latency histograms with a Prometheus registerer, for
+// applications exposing their metrics with the Prometheus client rather than with metrics.Registry. The label names
+// of a metric are the ones it is first recorded with.
+type Provider struct {
+	registerer prometheus.Registerer
+	buckets    []float64
+	logger     logger.Logger
+
+	mutex      sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+// Option is an option for Provider
+type Option func(opts *Provider)
+
+// WithBuckets option sets the upper bounds in seconds of the latency histogram buckets. Defaults to
+// metrics.DefaultBuckets.
+func WithBuckets(buckets ...float64) Option {
+	return func(opts *Provider) {
+		opts.buckets = buckets
+	}
+}
+
+// WithLogger option sets the logger reporting the metrics which could not be registered or recorded. Defaults to
+// logger.Default.
+func WithLogger(l logger.Logger) Option {
+	return func(opts *Provider) {
+		opts.logger = l
+	}
+}
+
+// New returns a provider registering the metrics with the registerer, such as prometheus.DefaultRegisterer
+func New(registerer prometheus.Registerer, opts ...Option) *Provider {
+	p := &Provider{
+		registerer: registerer,
+		buckets:    metrics.DefaultBuckets,
+		logger:     logger.Default(),
+		counters:   make(map[string]*prometheus.CounterVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// IncrementCounter increments the named counter
+func (p *Provider) IncrementCounter(name string, labels map[string]string) {
+	counter, err := p.counter(name, labels)
+	if err == nil {
+		var c prometheus.Counter
+
+		c, err = counter.GetMetricWith(labels)
+		if err == nil {
+			c.Inc()
+
+			return
+		}
+	}
+
+	p.logger.Warn("failed to record metric", "metric", name, "error", err)
+}
+
+// ObserveDuration records an observation in the named latency histogram
+func (p *Provider) ObserveDuration(name string, duration time.Duration, labels map[string]string) {
+	histogram, err := p.histogram(name, labels)
+	if err == nil {
+		var o prometheus.Observer
+
+		o, err = histogram.GetMetricWith(labels)
+		if err == nil {
+			o.Observe(duration.Seconds())
+
+			return
+		}
+	}
+
+	p.logger.Warn("failed to record metric", "metric", name, "error", err)
+}
+
+func (p *Provider) counter(name string, labels map[string]string) (*prometheus.CounterVec, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if c, ok := p.counters[name]; ok {
+		return c, nil
+	}
+
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: helpText(name)}, labelNames(labels))
+
+	collector, err := p.register(c)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := collector.(*prometheus.CounterVec)
+	if !ok {
+		return nil, fmt.Errorf("metric %s is registered with another type", name)
+	}
+
+	p.counters[name] = c
+
+	return c, nil
+}
+
+func (p *Provider) histogram(name string, labels map[string]string) (*prometheus.HistogramVec, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if h, ok := p.histograms[name]; ok {
+		return h, nil
+	}
+
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: helpText(name), Buckets: p.buckets},
+		labelNames(labels))
+
+	collector, err := p.register(h)
+	if err != nil {
+		return nil, err
+	}
+
+	h, ok := collector.(*prometheus.HistogramVec)
+	if !ok {
+		return nil, fmt.Errorf("metric %s is registered with another type", name)
+	}
+
+	p.histograms[name] = h
+
+	return h, nil
+}
+
+// register registers the collector, returning the collector already registered with the same descriptor if any,
+// such as by another provider of the same registerer
+func (p *Provider) register(c prometheus.Collector) (prometheus.Collector, error) {
+	err := p.registerer.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return alreadyRegistered.ExistingCollector, nil
+	}
+
+	return nil, fmt.Errorf("failed to register metric: %w", err)
+}
+
+func helpText(name string) string {
+	if h, ok := help[name]; ok {
+		return h
+	}
+
+	return name
+}
+
+func labelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
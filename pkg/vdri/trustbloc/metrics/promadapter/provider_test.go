@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package promadapter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	mocklogger "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+func TestProvider(t *testing.T) {
+	labels := map[string]string{metrics.LabelDomain: "foo.bar", metrics.LabelOperation: "create",
+		metrics.LabelStatus: metrics.StatusSuccess}
+
+	t.Run("test counter and histogram", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		p := New(registry, WithBuckets(0.1, 1))
+
+		p.IncrementCounter(metrics.SidetreeOperationRequests, labels)
+		p.IncrementCounter(metrics.SidetreeOperationRequests, labels)
+		p.ObserveDuration(metrics.SidetreeOperationDuration, 500*time.Millisecond, labels)
+
+		require.Equal(t, 2.0, testutil.ToFloat64(p.counters[metrics.SidetreeOperationRequests].With(labels)))
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, families, 2)
+
+		require.Equal(t, metrics.SidetreeOperationDuration, families[0].GetName())
+		require.Equal(t, "Latency of sidetree operation requests.", families[0].GetHelp())
+
+		histogram := families[0].GetMetric()[0].GetHistogram()
+		require.Equal(t, uint64(1), histogram.GetSampleCount())
+		require.Equal(t, 0.5, histogram.GetSampleSum())
+		require.Len(t, histogram.GetBucket(), 2)
+		require.Equal(t, uint64(0), histogram.GetBucket()[0].GetCumulativeCount())
+		require.Equal(t, uint64(1), histogram.GetBucket()[1].GetCumulativeCount())
+	})
+
+	t.Run("test metrics registered by another provider", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+
+		New(registry).IncrementCounter(metrics.SidetreeOperationRequests, labels)
+		New(registry).IncrementCounter(metrics.SidetreeOperationRequests, labels)
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+		require.Equal(t, 2.0, families[0].GetMetric()[0].GetCounter().GetValue())
+	})
+
+	t.Run("test different label names", func(t *testing.T) {
+		l := &mocklogger.Logger{}
+		p := New(prometheus.NewRegistry(), WithLogger(l))
+
+		p.IncrementCounter("requests", labels)
+		p.IncrementCounter("requests", map[string]string{metrics.LabelDomain: "foo.bar"})
+		p.ObserveDuration("duration", time.Second, labels)
+		p.ObserveDuration("duration", time.Second, map[string]string{metrics.LabelDomain: "foo.bar"})
+
+		entry := l.Entry("failed to record metric")
+		require.NotNil(t, entry)
+		require.Equal(t, "requests", entry.Fields["metric"])
+	})
+
+	t.Run("test metric registered with another type", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		l := &mocklogger.Logger{}
+
+		New(registry).IncrementCounter("requests", labels)
+		New(registry, WithLogger(l)).ObserveDuration("requests", time.Second, labels)
+
+		entry := l.Entry("failed to record metric")
+		require.NotNil(t, entry)
+		require.Contains(t, fmt.Sprint(entry.Fields["error"]), "registered with another type")
+	})
+
+	t.Run("test registration error", func(t *testing.T) {
+		l := &mocklogger.Logger{}
+		p := New(prometheus.NewRegistry(), WithLogger(l))
+
+		p.IncrementCounter("invalid name", labels)
+		p.ObserveDuration("invalid name", time.Second, labels)
+
+		entry := l.Entry("failed to record metric")
+		require.NotNil(t, entry)
+		require.Contains(t, fmt.Sprint(entry.Fields["error"]), "failed to register metric")
+	})
+}
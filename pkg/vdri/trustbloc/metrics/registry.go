@@ -0,0 +1,177 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histogram buckets, as in the Prometheus client
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10} //nolint: gochecknoglobals
+
+// Registry is a Provider keeping the metrics in memory, which it writes in the Prometheus text exposition format
+type Registry struct {
+	mutex    sync.Mutex
+	buckets  []float64
+	families map[string]*family
+}
+
+// family is a named counter or histogram, with a series for each set of labels
+type family struct {
+	histogram bool
+	series    map[string]*series
+}
+
+type series struct {
+	labels map[string]string
+	// counts are the cumulative bucket counts of a histogram
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewRegistry returns a registry with latency histograms of the given buckets, or of the default buckets if none
+func NewRegistry(buckets ...float64) *Registry {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	return &Registry{buckets: buckets, families: make(map[string]*family)}
+}
+
+// IncrementCounter increments the named counter
+func (r *Registry) IncrementCounter(name string, labels map[string]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.series(name, false, labels).count++
+}
+
+// ObserveDuration records an observation in the named latency histogram
+func (r *Registry) ObserveDuration(name string, duration time.Duration, labels map[string]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s := r.series(name, true, labels)
+	seconds := duration.Seconds()
+
+	for i, upperBound := range r.buckets {
+		if seconds <= upperBound {
+			s.counts[i]++
+		}
+	}
+
+	s.count++
+	s.sum += seconds
+}
+
+func (r *Registry) series(name string, histogram bool, labels map[string]string) *series {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{histogram: histogram, series: make(map[string]*series)}
+		r.families[name] = f
+	}
+
+	key := labelString(labels)
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		if histogram {
+			s.counts = make([]uint64, len(r.buckets))
+		}
+
+		f.series[key] = s
+	}
+
+	return s
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	b := bufio.NewWriter(w)
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := r.families[name]
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		if !f.histogram {
+			fmt.Fprintf(b, "# TYPE %s counter\n", name)
+
+			for _, key := range keys {
+				fmt.Fprintf(b, "%s%s %d\n", name, key, f.series[key].count)
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+
+		for _, key := range keys {
+			s := f.series[key]
+
+			for i, upperBound := range r.buckets {
+				fmt.Fprintf(b, "%s_bucket%s %d\n", name,
+					labelString(s.labels, "le", strconv.FormatFloat(upperBound, 'g', -1, 64)), s.counts[i])
+			}
+
+			fmt.Fprintf(b, "%s_bucket%s %d\n", name, labelString(s.labels, "le", "+Inf"), s.count)
+			fmt.Fprintf(b, "%s_sum%s %s\n", name, key, strconv.FormatFloat(s.sum, 'g', -1, 64))
+			fmt.Fprintf(b, "%s_count%s %d\n", name, key, s.count)
+		}
+	}
+
+	return b.Flush()
+}
+
+// labelString returns the labels in the Prometheus format, sorted by name, followed by the extra label if given
+func labelString(labels map[string]string, extra ...string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names)+1)
+
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+
+	if len(extra) == 2 { //nolint: gomnd
+		pairs = append(pairs, fmt.Sprintf("%s=%q", extra[0], extra[1]))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("test counters and histograms", func(t *testing.T) {
+		r := metrics.NewRegistry(0.1, 1)
+
+		metrics.Record(r, "requests_total", "duration_seconds", "foo.bar", time.Now(), nil)
+		metrics.Record(r, "requests_total", "duration_seconds", "foo.bar", time.Now(), errors.New("error"))
+		r.ObserveDuration("duration_seconds", 500*time.Millisecond,
+			map[string]string{metrics.LabelDomain: "foo.bar", metrics.LabelStatus: metrics.StatusSuccess})
+
+		b := &bytes.Buffer{}
+		require.NoError(t, r.WriteText(b))
+
+		text := b.String()
+		require.Contains(t, text, "# TYPE requests_total counter\n")
+		require.Contains(t, text, `requests_total{domain="foo.bar",status="success"} 1`)
+		require.Contains(t, text, `requests_total{domain="foo.bar",status="failure"} 1`)
+		require.Contains(t, text, "# TYPE duration_seconds histogram\n")
+		require.Contains(t, text, `duration_seconds_bucket{domain="foo.bar",status="success",le="0.1"} 1`)
+		require.Contains(t, text, `duration_seconds_bucket{domain="foo.bar",status="success",le="1"} 2`)
+		require.Contains(t, text, `duration_seconds_bucket{domain="foo.bar",status="success",le="+Inf"} 2`)
+		require.Contains(t, text, `duration_seconds_count{domain="foo.bar",status="success"} 2`)
+	})
+
+	t.Run("test no labels", func(t *testing.T) {
+		r := metrics.NewRegistry()
+
+		r.IncrementCounter("requests_total", nil)
+		r.IncrementCounter("requests_total", nil)
+
+		b := &bytes.Buffer{}
+		require.NoError(t, r.WriteText(b))
+		require.Contains(t, b.String(), "requests_total 2\n")
+	})
+
+	t.Run("test label values escaped", func(t *testing.T) {
+		r := metrics.NewRegistry()
+
+		r.IncrementCounter("requests_total", map[string]string{metrics.LabelDomain: `foo"bar`})
+
+		b := &bytes.Buffer{}
+		require.NoError(t, r.WriteText(b))
+		require.Contains(t, b.String(), `requests_total{domain="foo\"bar"} 1`)
+	})
+}
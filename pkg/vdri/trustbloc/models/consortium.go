@@ -40,8 +40,15 @@ type Consortium struct {
 
 // ConsortiumPolicy holds consortium policy configuration
 type ConsortiumPolicy struct {
-	Cache      CacheControl `json:"cache"`
-	NumQueries int          `json:"numQueries"`
+	Cache      CacheControl    `json:"cache"`
+	NumQueries int             `json:"numQueries"`
+	Sidetree   *SidetreePolicy `json:"sidetree,omitempty"`
+}
+
+// SidetreePolicy holds the sidetree parameters the stakeholders of the consortium agree on. Optional.
+type SidetreePolicy struct {
+	// MultiHashAlgorithm is the multihash code of the hash algorithm of sidetree operations
+	MultiHashAlgorithm uint `json:"multihashAlgorithm,omitempty"`
 }
 
 // CacheControl holds cache settings for this file,
@@ -68,6 +75,17 @@ type PublicKey struct {
 	JWK json.RawMessage `json:"jwk,omitempty"`
 }
 
+// NumQueries returns the number of stakeholders to query as defined by the consortium policy.
+// A policy value of zero, or one larger than the number of members, means that all members are queried.
+func (c *Consortium) NumQueries() int {
+	n := c.Policy.NumQueries
+	if n <= 0 || n > len(c.Members) {
+		n = len(c.Members)
+	}
+
+	return n
+}
+
 // ConsortiumFileData holds the data within a consortium config file
 type ConsortiumFileData struct {
 	Config *Consortium
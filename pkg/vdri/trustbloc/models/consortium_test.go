@@ -98,3 +98,22 @@ func TestConsortiumFileData_CacheLifetime(t *testing.T) {
 		require.Contains(t, err.Error(), "missing config")
 	})
 }
+
+func TestConsortium_NumQueries(t *testing.T) {
+	members := []*StakeholderListElement{{Domain: "bar.baz"}, {Domain: "baz.qux"}, {Domain: "qux.quux"}}
+
+	t.Run("policy value within member count", func(t *testing.T) {
+		c := Consortium{Policy: ConsortiumPolicy{NumQueries: 2}, Members: members}
+		require.Equal(t, 2, c.NumQueries())
+	})
+
+	t.Run("zero means all members", func(t *testing.T) {
+		c := Consortium{Members: members}
+		require.Equal(t, 3, c.NumQueries())
+	})
+
+	t.Run("capped at member count", func(t *testing.T) {
+		c := Consortium{Policy: ConsortiumPolicy{NumQueries: 5}, Members: members}
+		require.Equal(t, 3, c.NumQueries())
+	})
+}
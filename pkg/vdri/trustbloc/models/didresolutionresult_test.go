@@ -16,7 +16,7 @@ import (
 )
 
 func TestMakeDIDResolutionResult(t *testing.T) {
-	mockdoc := mockdiddoc.GetMockDIDDoc()
+	mockdoc := mockdiddoc.GetMockDIDDoc(t)
 
 	resultBytes, err := MakeDIDResolutionResult(mockdoc)
 	require.NoError(t, err)
@@ -10,10 +10,20 @@ import (
 	"time"
 )
 
-// SidetreeConfig sidetree configuration
+// SidetreeConfig sidetree configuration. The limits which are zero are not checked.
 type SidetreeConfig struct {
 	MultiHashAlgorithm uint `json:"multihashAlgorithm"`
-	MaxAge             uint `json:"-"`
+	// Version is the version of the sidetree protocol the endpoint implements, if it reports it
+	Version string `json:"version,omitempty"`
+	// MaxOperationSize is the maximum size in bytes of an operation request
+	MaxOperationSize uint `json:"maxOperationSize,omitempty"`
+	// MaxPublicKeys is the maximum number of public keys of a document
+	MaxPublicKeys uint `json:"maxPublicKeys,omitempty"`
+	// MaxServices is the maximum number of services of a document
+	MaxServices uint `json:"maxServices,omitempty"`
+	// MaxIDLength is the maximum length of the IDs of the public keys and services
+	MaxIDLength uint `json:"maxIdLength,omitempty"`
+	MaxAge      uint `json:"-"`
 }
 
 // CacheLifetime returns the cache lifetime of the sidetree config file before it needs to be checked for an update
@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustbloc
+
+import (
+	"fmt"
+	"strings"
+
+	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+
+	didclient "github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/migrate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+// names of the options of the VDRI, set with vdrapi.WithOption
+const (
+	// VersionIDOpt is the version ID of the document to resolve
+	VersionIDOpt = "versionID"
+	// VersionTimeOpt is the time (RFC3339) at which the version of the document to resolve was valid
+	VersionTimeOpt = "versionTime"
+	// DomainOpt is the consortium domain of a created, updated or deactivated DID. Defaults to the domain of the
+	// VDRI, or else, for updates and deactivations, to the domain of the DID.
+	DomainOpt = "domain"
+	// RecoveryPublicKeyOpt is the crypto.PublicKey the recovery commitment of a created DID is calculated from
+	RecoveryPublicKeyOpt = "recoveryPublicKey"
+	// UpdatePublicKeyOpt is the crypto.PublicKey the update commitment of a created DID is calculated from
+	UpdatePublicKeyOpt = "updatePublicKey"
+	// NextUpdatePublicKeyOpt is the crypto.PublicKey the next update commitment of an updated DID is calculated from
+	NextUpdatePublicKeyOpt = "nextUpdatePublicKey"
+	// SigningKeyOpt is the crypto.PrivateKey signing an update, the update key, or a deactivation, the recovery key
+	SigningKeyOpt = "signingKey"
+	// SigningKeyIDOpt is the ID of the signing key
+	SigningKeyIDOpt = "signingKeyID"
+)
+
+// Create creates a DID with the public keys and services of the document, in the consortium of the DomainOpt option
+// or of the VDRI, with the commitments calculated from the RecoveryPublicKeyOpt and UpdatePublicKeyOpt options.
+// Only Ed25519 and P-256 public keys can be created.
+func (v *VDRI) Create(didDoc *docdid.Doc, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	didMethodOpts := getDIDMethodOpts(opts)
+
+	domain, err := stringOpt(didMethodOpts, DomainOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	if domain == "" {
+		domain = v.domain
+	}
+
+	if domain == "" {
+		return nil, fmt.Errorf("%s option is required", DomainOpt)
+	}
+
+	plan, err := newPlan(didDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	createOpts := plan.CreateOptions()
+	createOpts = append(createOpts,
+		create.WithRecoveryPublicKey(didMethodOpts.Values[RecoveryPublicKeyOpt]),
+		create.WithUpdatePublicKey(didMethodOpts.Values[UpdatePublicKeyOpt]))
+
+	created, err := v.client.CreateDID(domain, createOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create did: %w", err)
+	}
+
+	return &docdid.DocResolution{DIDDocument: created}, nil
+}
+
+// Update updates the DID of the document so that its public keys and services are the ones of the document, signed
+// with the SigningKeyOpt option, with the next update commitment calculated from the NextUpdatePublicKeyOpt option.
+// No update is sent if the resolved document of the DID already has them.
+func (v *VDRI) Update(didDoc *docdid.Doc, opts ...vdrapi.DIDMethodOption) error {
+	didMethodOpts := getDIDMethodOpts(opts)
+
+	domain, err := v.operationDomain(didDoc.ID, didMethodOpts)
+	if err != nil {
+		return err
+	}
+
+	signingKeyID, err := stringOpt(didMethodOpts, SigningKeyIDOpt)
+	if err != nil {
+		return err
+	}
+
+	plan, err := newPlan(didDoc)
+	if err != nil {
+		return err
+	}
+
+	current, err := v.Read(didDoc.ID)
+	if err != nil {
+		return err
+	}
+
+	updateOpts := plan.UpdateOptions(current.DIDDocument)
+	if len(updateOpts) == 0 {
+		return nil
+	}
+
+	updateOpts = append(updateOpts,
+		update.WithSigningKey(didMethodOpts.Values[SigningKeyOpt]),
+		update.WithSigningKeyID(signingKeyID),
+		update.WithNextUpdatePublicKey(didMethodOpts.Values[NextUpdatePublicKeyOpt]))
+
+	err = v.client.UpdateDID(didDoc.ID, domain, updateOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to update did: %w", err)
+	}
+
+	return nil
+}
+
+// Deactivate deactivates the DID, signed with the SigningKeyOpt option
+func (v *VDRI) Deactivate(did string, opts ...vdrapi.DIDMethodOption) error {
+	didMethodOpts := getDIDMethodOpts(opts)
+
+	domain, err := v.operationDomain(did, didMethodOpts)
+	if err != nil {
+		return err
+	}
+
+	signingKeyID, err := stringOpt(didMethodOpts, SigningKeyIDOpt)
+	if err != nil {
+		return err
+	}
+
+	err = v.client.DeactivateDID(did, domain, deactivate.WithSigningKey(didMethodOpts.Values[SigningKeyOpt]),
+		deactivate.WithSigningKeyID(signingKeyID))
+	if err != nil {
+		return fmt.Errorf("failed to deactivate did: %w", err)
+	}
+
+	return nil
+}
+
+// clientOptions returns the options of the DID client of the operations, which goes through the transport and the
+// proxy of the VDRI as its resolutions do
+func (v *VDRI) clientOptions() []didclient.Option {
+	opts := []didclient.Option{
+		didclient.WithTLSConfig(v.tlsConfig),
+		didclient.WithTLSOptions(v.tlsOpts...),
+		didclient.WithProxyURL(v.proxyURL),
+		didclient.WithDialTimeout(v.dialTimeout),
+		didclient.WithResolver(v.resolver),
+		didclient.WithAllowedNetworks(v.allowedNetworks...),
+		didclient.WithMetricsProvider(v.metrics),
+		didclient.WithIPFSGateway(v.ipfsGateway),
+		didclient.WithLogger(v.logger),
+		didclient.WithTracer(v.tracer),
+	}
+
+	if v.writeToken != "" {
+		opts = append(opts, didclient.WithAuthToken(v.writeToken))
+	}
+
+	return opts
+}
+
+// operationDomain returns the domain of the DomainOpt option if set, or else the domain of the did
+func (v *VDRI) operationDomain(did string, opts *vdrapi.DIDMethodOpts) (string, error) {
+	domain, err := stringOpt(opts, DomainOpt)
+	if err != nil || domain != "" {
+		return domain, err
+	}
+
+	return v.didDomain(did)
+}
+
+// newPlan returns the plan of the public keys and services of the document, which fails if some of its public keys
+// can't be taken by the sidetree operations
+func newPlan(didDoc *docdid.Doc) (*migrate.Plan, error) {
+	plan := migrate.NewPlan(didDoc)
+	if len(plan.Skipped) > 0 {
+		return nil, fmt.Errorf("unsupported public keys, only Ed25519 and P-256 keys are supported: %s",
+			strings.Join(plan.Skipped, ", "))
+	}
+
+	return plan, nil
+}
+
+func getDIDMethodOpts(opts []vdrapi.DIDMethodOption) *vdrapi.DIDMethodOpts {
+	didMethodOpts := &vdrapi.DIDMethodOpts{Values: map[string]interface{}{}}
+
+	for _, opt := range opts {
+		opt(didMethodOpts)
+	}
+
+	return didMethodOpts
+}
+
+func stringOpt(opts *vdrapi.DIDMethodOpts, name string) (string, error) {
+	value, ok := opts.Values[name]
+	if !ok {
+		return "", nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s option must be a string", name)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustbloc
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+)
+
+const testDID = "did:trustbloc:testnet:123"
+
+func TestVDRI_Create(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		client := &mockDIDClient{}
+
+		v := New(WithDomain("testnet"))
+		v.client = client
+
+		result, err := v.Create(newTestDoc("", pubKey), vdrapi.WithOption(RecoveryPublicKeyOpt, pubKey),
+			vdrapi.WithOption(UpdatePublicKeyOpt, pubKey))
+		require.NoError(t, err)
+		require.Equal(t, testDID, result.DIDDocument.ID)
+
+		require.Equal(t, "testnet", client.domain)
+		require.Len(t, client.createOpts.PublicKeys, 1)
+		require.Equal(t, "key1", client.createOpts.PublicKeys[0].ID)
+		require.Len(t, client.createOpts.Services, 1)
+		require.Equal(t, pubKey, client.createOpts.RecoveryPublicKey)
+		require.Equal(t, pubKey, client.createOpts.UpdatePublicKey)
+
+		_, err = v.Create(newTestDoc("", pubKey), vdrapi.WithOption(DomainOpt, "example.com"))
+		require.NoError(t, err)
+		require.Equal(t, "example.com", client.domain)
+	})
+
+	t.Run("test domain is missing", func(t *testing.T) {
+		_, err := New().Create(newTestDoc("", pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "domain option is required")
+
+		_, err = New().Create(newTestDoc("", pubKey), vdrapi.WithOption(DomainOpt, 1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "domain option must be a string")
+	})
+
+	t.Run("test unsupported public key", func(t *testing.T) {
+		doc := newTestDoc("", pubKey)
+		doc.VerificationMethod = append(doc.VerificationMethod,
+			*did.NewVerificationMethodFromBytes("#key2", "X25519KeyAgreementKey2019", "", make([]byte, 32)))
+
+		_, err := New(WithDomain("testnet")).Create(doc)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported public keys, only Ed25519 and P-256 keys are supported: key2")
+	})
+
+	t.Run("test create error", func(t *testing.T) {
+		v := New(WithDomain("testnet"))
+		v.client = &mockDIDClient{err: fmt.Errorf("create error")}
+
+		_, err := v.Create(newTestDoc("", pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create did: create error")
+	})
+}
+
+func TestVDRI_Update(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		client := &mockDIDClient{}
+
+		v := New(WithResolverURL("url"))
+		v.client = client
+		v.getHTTPVDRI = httpVdriFunc(newTestDoc(testDID, otherKey), nil)
+
+		err := v.Update(newTestDoc(testDID, pubKey), vdrapi.WithOption(SigningKeyOpt, privKey),
+			vdrapi.WithOption(SigningKeyIDOpt, "key1"), vdrapi.WithOption(NextUpdatePublicKeyOpt, pubKey))
+		require.NoError(t, err)
+
+		require.Equal(t, testDID, client.did)
+		require.Equal(t, "testnet", client.domain)
+		require.Len(t, client.updateOpts.AddPublicKeys, 1)
+		require.Equal(t, []byte(pubKey), client.updateOpts.AddPublicKeys[0].Value)
+		require.Empty(t, client.updateOpts.AddServices)
+		require.Equal(t, privKey, client.updateOpts.SigningKey)
+		require.Equal(t, "key1", client.updateOpts.SigningKeyID)
+		require.Equal(t, pubKey, client.updateOpts.NextUpdatePublicKey)
+	})
+
+	t.Run("test document already up to date", func(t *testing.T) {
+		client := &mockDIDClient{}
+
+		v := New(WithResolverURL("url"))
+		v.client = client
+		v.getHTTPVDRI = httpVdriFunc(newTestDoc(testDID, pubKey), nil)
+
+		require.NoError(t, v.Update(newTestDoc(testDID, pubKey)))
+		require.Nil(t, client.updateOpts)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		v := New(WithResolverURL("url"))
+		v.client = &mockDIDClient{err: fmt.Errorf("update error")}
+		v.getHTTPVDRI = httpVdriFunc(newTestDoc(testDID, otherKey), nil)
+
+		err := v.Update(newTestDoc("did:1223", pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "wrong did did:1223")
+
+		err = v.Update(newTestDoc(testDID, pubKey), vdrapi.WithOption(SigningKeyIDOpt, 1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "signingKeyID option must be a string")
+
+		err = v.Update(newTestDoc(testDID, pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to update did: update error")
+
+		v.getHTTPVDRI = httpVdriFunc(nil, fmt.Errorf("read error"))
+
+		err = v.Update(newTestDoc(testDID, pubKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read error")
+	})
+}
+
+func TestVDRI_Deactivate(t *testing.T) {
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		client := &mockDIDClient{}
+
+		v := New()
+		v.client = client
+
+		require.NoError(t, v.Deactivate(testDID, vdrapi.WithOption(SigningKeyOpt, privKey),
+			vdrapi.WithOption(DomainOpt, "example.com")))
+
+		require.Equal(t, testDID, client.did)
+		require.Equal(t, "example.com", client.domain)
+		require.Equal(t, privKey, client.deactivateOpts.SigningKey)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		v := New()
+		v.client = &mockDIDClient{err: fmt.Errorf("deactivate error")}
+
+		err := v.Deactivate("did:1223")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "wrong did did:1223")
+
+		err = v.Deactivate(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to deactivate did: deactivate error")
+	})
+}
+
+// newTestDoc returns a document with an Ed25519 public key, key1, and a service
+func newTestDoc(id string, pubKey ed25519.PublicKey) *did.Doc {
+	key := did.VerificationMethod{ID: id + "#key1", Type: "Ed25519VerificationKey2018", Controller: id,
+		Value: pubKey}
+
+	return &did.Doc{ID: id, VerificationMethod: []did.VerificationMethod{key},
+		Authentication: []did.Verification{{VerificationMethod: key, Relationship: did.Authentication}},
+		Service:        []did.Service{{ID: id + "#svc1", Type: "type", ServiceEndpoint: "https://example.com"}},
+	}
+}
+
+type mockDIDClient struct {
+	domain         string
+	did            string
+	createOpts     *create.Opts
+	updateOpts     *update.Opts
+	deactivateOpts *deactivate.Opts
+	err            error
+}
+
+func (c *mockDIDClient) CreateDID(domain string, opts ...create.Option) (*did.Doc, error) {
+	c.domain = domain
+	c.createOpts = &create.Opts{}
+
+	for _, opt := range opts {
+		opt(c.createOpts)
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &did.Doc{ID: testDID}, nil
+}
+
+func (c *mockDIDClient) UpdateDID(didID, domain string, opts ...update.Option) error {
+	c.did, c.domain = didID, domain
+	c.updateOpts = &update.Opts{}
+
+	for _, opt := range opts {
+		opt(c.updateOpts)
+	}
+
+	return c.err
+}
+
+func (c *mockDIDClient) DeactivateDID(didID, domain string, opts ...deactivate.Option) error {
+	c.did, c.domain = didID, domain
+	c.deactivateOpts = &deactivate.Opts{}
+
+	for _, opt := range opts {
+		opt(c.deactivateOpts)
+	}
+
+	return c.err
+}
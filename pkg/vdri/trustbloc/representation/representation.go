@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package representation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// media types of the representations of DID documents, see https://www.w3.org/TR/did-core/#representations
+const (
+	DIDLDJSON = "application/did+ld+json"
+	DIDJSON   = "application/did+json"
+	DIDCBOR   = "application/did+cbor"
+)
+
+// MediaType returns the representation of the media type of a Content-Type header or of an Accept media range, or
+// an empty string if it's not a supported representation of DID documents
+func MediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case DIDLDJSON, DIDJSON, DIDCBOR:
+		return mediaType
+	default:
+		return ""
+	}
+}
+
+// Negotiate returns the representation asked for by the first media range of the Accept header, or an empty string
+// if it asks for another media type, such as the one of the DID resolution result
+func Negotiate(accept string) string {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		if _, _, err := mime.ParseMediaType(mediaRange); err != nil {
+			continue
+		}
+
+		return MediaType(mediaRange)
+	}
+
+	return ""
+}
+
+// Marshal returns the JSON of the DID document in the representation. The JSON representations keep the JSON as is,
+// the CBOR representation is the canonical CBOR encoding of the JSON data model, integers being encoded as integers.
+func Marshal(docJSON []byte, mediaType string) ([]byte, error) {
+	if mediaType != DIDCBOR {
+		return docJSON, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(docJSON))
+	d.UseNumber()
+
+	var value interface{}
+
+	if err := d.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal did document: %w", err)
+	}
+
+	encMode, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cbor encoder: %w", err)
+	}
+
+	docCBOR, err := encMode.Marshal(fromJSON(value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal did document to cbor: %w", err)
+	}
+
+	return docCBOR, nil
+}
+
+// Unmarshal returns the JSON of the DID document, or of the DID resolution result, in the representation
+func Unmarshal(body []byte, mediaType string) ([]byte, error) {
+	if mediaType != DIDCBOR || len(body) == 0 {
+		return body, nil
+	}
+
+	var value interface{}
+
+	if err := cbor.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cbor did document: %w", err)
+	}
+
+	value, err := toJSON(value)
+	if err != nil {
+		return nil, err
+	}
+
+	docJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal did document: %w", err)
+	}
+
+	return docJSON, nil
+}
+
+// fromJSON converts the numbers of the JSON value to integers, or to floats if they are not integers
+func fromJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = fromJSON(element)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = fromJSON(element)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := v.Float64() //nolint: errcheck
+
+		return f
+	}
+
+	return value
+}
+
+// toJSON converts the maps of the CBOR value, decoded with keys of any type, to JSON objects
+func toJSON(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+
+		for key, element := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("cbor map key %v is not a string", key)
+			}
+
+			var err error
+
+			if object[name], err = toJSON(element); err != nil {
+				return nil, err
+			}
+		}
+
+		return object, nil
+	case []interface{}:
+		for i, element := range v {
+			var err error
+
+			if v[i], err = toJSON(element); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return value, nil
+}
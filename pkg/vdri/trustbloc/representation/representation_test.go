@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package representation
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const docJSON = `{"@context":["https://www.w3.org/ns/did/v1"],"id":"did:trustbloc:testnet:123",` +
+	`"service":[{"id":"#svc1","priority":0,"weight":0.5}]}`
+
+func TestMediaType(t *testing.T) {
+	require.Equal(t, DIDLDJSON, MediaType(DIDLDJSON))
+	require.Equal(t, DIDJSON, MediaType(DIDJSON+"; charset=utf-8"))
+	require.Equal(t, DIDCBOR, MediaType(DIDCBOR))
+	require.Empty(t, MediaType("application/json"))
+	require.Empty(t, MediaType(""))
+}
+
+func TestNegotiate(t *testing.T) {
+	require.Equal(t, DIDCBOR, Negotiate(DIDCBOR+", "+DIDLDJSON+";q=0.9"))
+	require.Equal(t, DIDJSON, Negotiate("invalid/;;, "+DIDJSON))
+	require.Empty(t, Negotiate(`application/ld+json;profile="https://w3id.org/did-resolution", `+DIDCBOR))
+	require.Empty(t, Negotiate(""))
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("test json representations", func(t *testing.T) {
+		for _, mediaType := range []string{DIDLDJSON, DIDJSON} {
+			body, err := Marshal([]byte(docJSON), mediaType)
+			require.NoError(t, err)
+			require.Equal(t, docJSON, string(body))
+
+			body, err = Unmarshal(body, mediaType)
+			require.NoError(t, err)
+			require.Equal(t, docJSON, string(body))
+		}
+	})
+
+	t.Run("test cbor representation", func(t *testing.T) {
+		body, err := Marshal([]byte(docJSON), DIDCBOR)
+		require.NoError(t, err)
+
+		var value map[string]interface{}
+		require.NoError(t, cbor.Unmarshal(body, &value))
+		require.Equal(t, "did:trustbloc:testnet:123", value["id"])
+
+		service, ok := value["service"].([]interface{})
+		require.True(t, ok)
+		require.Equal(t, map[interface{}]interface{}{"id": "#svc1", "priority": uint64(0), "weight": 0.5},
+			service[0])
+
+		body, err = Unmarshal(body, DIDCBOR)
+		require.NoError(t, err)
+		require.JSONEq(t, docJSON, string(body))
+	})
+
+	t.Run("test invalid documents", func(t *testing.T) {
+		_, err := Marshal([]byte("{"), DIDCBOR)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal did document")
+
+		_, err = Unmarshal([]byte("{"), DIDCBOR)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal cbor did document")
+
+		body, err := cbor.Marshal(map[int]string{1: "id"})
+		require.NoError(t, err)
+
+		_, err = Unmarshal(body, DIDCBOR)
+		require.EqualError(t, err, "cbor map key 1 is not a string")
+	})
+}
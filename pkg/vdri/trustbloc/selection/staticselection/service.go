@@ -55,16 +55,11 @@ func (ds *SelectionService) SelectEndpoints(consortiumDomain string, endpoints [
 
 	consortium := consortiumData.Config
 
-	n := 0
-	if consortium != nil {
+	n := len(d)
+	if consortium != nil && consortium.Policy.NumQueries > 0 {
 		n = consortium.Policy.NumQueries
 	}
 
-	// if ds.numStakeholders is 0, then we use all stakeholders
-	if n == 0 {
-		n = len(d)
-	}
-
 	perm := mathrand.Perm(len(d))
 
 	for i := 0; i < n && i < len(d); i++ {
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oteltracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+// InstrumentationName is the name of the OpenTelemetry tracer the spans are started with
+const InstrumentationName = "github.com/trustbloc/trustbloc-did-method"
+
+// Tracer is a tracing.Tracer starting OpenTelemetry spans
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// Option is an option for Tracer
+type Option func(opts *Tracer)
+
+// WithPropagator option sets the propagator writing the trace context to the headers of the outbound requests.
+// Defaults to the W3C Trace Context propagator.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(opts *Tracer) {
+		opts.propagator = p
+	}
+}
+
+// New returns a tracer starting its spans with a tracer of the provider, such as the provider of the
+// OpenTelemetry SDK or otel.GetTracerProvider()
+func New(provider trace.TracerProvider, opts ...Option) *Tracer {
+	t := &Tracer{tracer: provider.Tracer(InstrumentationName), propagator: propagation.TraceContext{}}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Start starts a span with the attributes, as a child of the span of the context if any
+func (t *Tracer) Start(ctx context.Context, name string,
+	attributes map[string]string) (context.Context, tracing.Span) {
+	kvs := make([]attribute.KeyValue, 0, len(attributes))
+
+	for key, value := range attributes {
+		kvs = append(kvs, attribute.String(key, value))
+	}
+
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(kvs...))
+
+	return ctx, &span{span: s}
+}
+
+// Inject writes the trace context of the span of the context to the headers with the propagator
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttribute(key, value string) {
+	s.span.SetAttributes(attribute.String(key, value))
+}
+
+func (s *span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package oteltracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	t.Run("test spans", func(t *testing.T) {
+		ctx, parent := tracer.Start(context.Background(), tracing.SpanResolve,
+			map[string]string{tracing.AttributeDID: "did:trustbloc:a:b"})
+		parent.SetAttribute(tracing.AttributeDomain, "a")
+
+		_, child := tracer.Start(ctx, tracing.SpanSidetreeResolve, nil)
+		child.End(errors.New("resolve error"))
+
+		parent.End(nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+
+		require.Equal(t, tracing.SpanSidetreeResolve, spans[0].Name())
+		require.Equal(t, codes.Error, spans[0].Status().Code)
+		require.Equal(t, "resolve error", spans[0].Status().Description)
+		require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+		require.Equal(t, tracing.SpanResolve, spans[1].Name())
+		require.Equal(t, codes.Unset, spans[1].Status().Code)
+		require.ElementsMatch(t, []attribute.KeyValue{attribute.String(tracing.AttributeDID, "did:trustbloc:a:b"),
+			attribute.String(tracing.AttributeDomain, "a")}, spans[1].Attributes())
+	})
+
+	t.Run("test inject", func(t *testing.T) {
+		ctx, s := tracer.Start(context.Background(), tracing.SpanSidetreeOperation, nil)
+		defer s.End(nil)
+
+		header := http.Header{}
+		tracer.Inject(ctx, header)
+		require.Contains(t, header.Get("Traceparent"), s.(*span).span.SpanContext().TraceID().String())
+	})
+
+	t.Run("test inject with propagator", func(t *testing.T) {
+		ctx, s := tracer.Start(context.Background(), tracing.SpanSidetreeOperation, nil)
+		defer s.End(nil)
+
+		header := http.Header{}
+		New(sdktrace.NewTracerProvider(), WithPropagator(propagation.Baggage{})).Inject(ctx, header)
+		require.Empty(t, header.Get("Traceparent"))
+	})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// SpanDIDOperation spans a DID operation of the client, from endpoint discovery to the sidetree request
+	SpanDIDOperation = "trustbloc.did_operation"
+	// SpanResolve spans a DID resolution of the VDRI
+	SpanResolve = "trustbloc.resolve"
+	// SpanValidateConsortium spans the validation of the config and endorsement of a consortium
+	SpanValidateConsortium = "trustbloc.validate_consortium"
+	// SpanEndpointDiscovery spans the discovery of the sidetree endpoints of a domain
+	SpanEndpointDiscovery = "trustbloc.endpoint_discovery"
+	// SpanConfigFetch spans the fetch of a consortium or sidetree config
+	SpanConfigFetch = "trustbloc.config_fetch"
+	// SpanBuildOperation spans the build of a sidetree operation request
+	SpanBuildOperation = "trustbloc.build_operation"
+	// SpanSidetreeOperation spans a sidetree operation request
+	SpanSidetreeOperation = "trustbloc.sidetree_operation"
+	// SpanSidetreeResolve spans a sidetree resolution request
+	SpanSidetreeResolve = "trustbloc.sidetree_resolve"
+	// SpanVerifyDocument spans the verification of the anchored document of a created DID
+	SpanVerifyDocument = "trustbloc.verify_document"
+
+	// AttributeDID is the attribute holding the DID of a span
+	AttributeDID = "did"
+	// AttributeDomain is the attribute holding the consortium domain of a span
+	AttributeDomain = "domain"
+	// AttributeOperation is the attribute holding the type of a DID operation: create, update, recover or deactivate
+	AttributeOperation = "operation"
+	// AttributeURL is the attribute holding the url a request was sent to
+	AttributeURL = "url"
+)
+
+// Tracer starts the spans of the requests made by the client and the VDRI. Implementations must be safe for
+// concurrent use.
+type Tracer interface {
+	// Start starts a span with the attributes, as a child of the span of the context if any, and returns a context
+	// holding the new span
+	Start(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+	// Inject writes the trace context of the span of the context to the headers of an outbound request
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a span started by a Tracer
+type Span interface {
+	// SetAttribute sets an attribute known once the span started, such as the DID of a created document
+	SetAttribute(key, value string)
+	// End ends the span, marking it as failed if the error is not nil
+	End(err error)
+}
+
+// NoopTracer is a Tracer that discards all spans
+type NoopTracer struct{}
+
+// Start returns the context as is and a span that does nothing
+func (NoopTracer) Start(ctx context.Context, _ string, _ map[string]string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// Inject does nothing
+func (NoopTracer) Inject(context.Context, http.Header) {}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, string) {}
+
+func (noopSpan) End(error) {}
@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
+)
+
+func TestNoopTracer(t *testing.T) {
+	ctx := context.Background()
+
+	spanCtx, span := tracing.NoopTracer{}.Start(ctx, tracing.SpanResolve,
+		map[string]string{tracing.AttributeDID: "did:trustbloc:a:b"})
+	require.Equal(t, ctx, spanCtx)
+
+	require.NotPanics(t, func() {
+		span.SetAttribute(tracing.AttributeDomain, "a")
+		span.End(errors.New("error"))
+	})
+
+	header := http.Header{}
+	tracing.NoopTracer{}.Inject(spanCtx, header)
+	require.Empty(t, header)
+}
@@ -0,0 +1,40 @@
+//go:build !js
+// +build !js
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustbloc
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/dialer"
+)
+
+const keepAlive = 30 * time.Second
+
+// newTransport returns the transport of the resolutions and of the config and DID configuration fetches
+func (v *VDRI) newTransport() *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if v.proxyURL != nil {
+		proxy = http.ProxyURL(v.proxyURL)
+	}
+
+	return &http.Transport{
+		Proxy:           proxy,
+		DialContext:     dialer.New(v.dialTimeout, keepAlive, v.resolver, v.allowedNetworks).DialContext,
+		TLSClientConfig: v.transportTLSConfig(),
+	}
+}
+
+// transportTLSConfig returns the TLS config of WithTLSConfig, with the TLS options of WithClientCertificates and
+// WithTLSOptions
+func (v *VDRI) transportTLSConfig() *tls.Config {
+	return tlsconfig.Apply(v.tlsConfig, v.tlsOpts...)
+}
@@ -0,0 +1,20 @@
+//go:build js
+// +build js
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustbloc
+
+import (
+	"net/http"
+)
+
+// newTransport returns the transport of the resolutions and of the config and DID configuration fetches in a browser,
+// which sends the requests with the Fetch API. The transport only uses the Fetch API without a dialer, so the dialer,
+// proxy and TLS options don't apply.
+func (v *VDRI) newTransport() *http.Transport {
+	return &http.Transport{}
+}
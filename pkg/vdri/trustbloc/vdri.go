@@ -7,6 +7,7 @@ package trustbloc
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/tls"
 	"encoding/json"
@@ -14,25 +15,37 @@ import (
 	"fmt"
 	"math/big"
 	mathrand "math/rand"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	docdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
-	"github.com/hyperledger/aries-framework-go/pkg/vdr/httpbinding"
-	log "github.com/sirupsen/logrus"
 
+	didclient "github.com/trustbloc/trustbloc-did-method/pkg/did"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/create"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/deactivate"
+	"github.com/trustbloc/trustbloc-did-method/pkg/did/option/update"
+	"github.com/trustbloc/trustbloc-did-method/pkg/logger"
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/httpconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/memorycacheconfig"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/metricsconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/signatureconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/updatevalidationconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/config/verifyingconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/didconfiguration"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/discovery/staticdiscovery"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/endpoint"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/representation"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/selection/staticselection"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
 )
 
 type configService interface {
@@ -48,9 +61,14 @@ type didConfigService interface {
 	VerifyStakeholder(domain string, doc *docdid.Doc) error
 }
 
+type didClient interface {
+	CreateDID(domain string, opts ...create.Option) (*docdid.Doc, error)
+	UpdateDID(did, domain string, opts ...update.Option) error
+	DeactivateDID(did, domain string, opts ...deactivate.Option) error
+}
+
 type vdri interface {
-	Build(pubKey *vdrapi.PubKey, opts ...vdrapi.DocOpts) (*docdid.Doc, error)
-	Read(did string, opts ...vdrapi.ResolveOpts) (*docdid.Doc, error)
+	Read(did string, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error)
 }
 
 // VDRI bloc
@@ -60,11 +78,32 @@ type VDRI struct {
 	configService    configService
 	endpointService  endpointService
 	didConfigService didConfigService
-	getHTTPVDRI      func(url string) (vdri, error) // needed for unit test
+	getHTTPVDRI      func(endpointURL string) (vdri, error) // needed for unit test
+	client           didClient
+	writeToken       string
 	tlsConfig        *tls.Config
+	tlsOpts          []tlsconfig.Option
+	proxyURL         *url.URL
+	dialTimeout      time.Duration
+	resolver         *net.Resolver
+	allowedNetworks  []*net.IPNet
+	httpClient       *http.Client
 	authToken        string
-
-	validatedConsortium map[string]bool
+	metrics          metrics.Provider
+	ipfsGateway      string
+	logger           logger.Logger
+	tracer           tracing.Tracer
+	// hedgedRequests is the maximum number of endpoints a hedged resolution is raced against, zero if the
+	// resolutions are not hedged
+	hedgedRequests int
+	hedgeDelay     time.Duration
+	// representation is the representation of the DID documents asked for to the sidetree nodes and resolvers
+	representation string
+
+	// validatedConsortium maps a consortium domain to the time its validation expires,
+	// as set by the consortium's cache policy
+	validatedConsortium map[string]time.Time
+	validatedMutex      sync.Mutex
 
 	enableSignatureVerification bool
 
@@ -81,37 +120,48 @@ type genesisFileData struct {
 
 // New creates new bloc vdri
 func New(opts ...Option) *VDRI {
-	v := &VDRI{}
+	v := &VDRI{metrics: metrics.NoopProvider{}, logger: logger.Default(), tracer: tracing.NoopTracer{},
+		dialTimeout: defaultDialTimeout, representation: representation.DIDLDJSON}
 
 	for _, opt := range opts {
 		opt(v)
 	}
 
-	v.getHTTPVDRI = func(url string) (vdri, error) {
-		return httpbinding.New(url,
-			httpbinding.WithTLSConfig(v.tlsConfig), httpbinding.WithResolveAuthToken(v.authToken))
+	v.httpClient = &http.Client{Transport: v.newTransport()}
+
+	v.getHTTPVDRI = func(endpointURL string) (vdri, error) {
+		return newHTTPResolver(endpointURL, v.httpClient, v.authToken, v.representation, v.logger)
 	}
 
-	configService := httpconfig.NewService(httpconfig.WithTLSConfig(v.tlsConfig))
+	httpConfigOpts := []httpconfig.Option{httpconfig.WithHTTPClient(v.httpClient), httpconfig.WithLogger(v.logger)}
+	if v.ipfsGateway != "" {
+		httpConfigOpts = append(httpConfigOpts, httpconfig.WithIPFSGateway(v.ipfsGateway))
+	}
+
+	configService := metricsconfig.NewService(httpconfig.NewService(httpConfigOpts...), v.metrics)
+	verifyingConfigService := verifyingconfig.NewService(configService, verifyingconfig.WithLogger(v.logger))
 
 	switch {
 	case v.useUpdateValidation:
-		v.updateValidationService = updatevalidationconfig.NewService(verifyingconfig.NewService(configService))
+		v.updateValidationService = updatevalidationconfig.NewService(verifyingConfigService)
 		v.configService = memorycacheconfig.NewService(v.updateValidationService)
 	case v.enableSignatureVerification:
-		verifyingService := signatureconfig.NewService(verifyingconfig.NewService(configService))
+		verifyingService := signatureconfig.NewService(verifyingConfigService)
 		v.configService = memorycacheconfig.NewService(verifyingService)
 	default:
-		v.configService = memorycacheconfig.NewService(verifyingconfig.NewService(configService))
+		v.configService = memorycacheconfig.NewService(verifyingConfigService)
 	}
 
 	v.endpointService = endpoint.NewService(
-		staticdiscovery.NewService(v.configService),
-		staticselection.NewService(v.configService))
+		staticdiscovery.NewService(v.configService, staticdiscovery.WithLogger(v.logger)),
+		staticselection.NewService(v.configService),
+		endpoint.WithMetricsProvider(v.metrics))
+
+	v.didConfigService = didconfiguration.NewService(didconfiguration.WithHTTPClient(v.httpClient))
 
-	v.didConfigService = didconfiguration.NewService(didconfiguration.WithTLSConfig(v.tlsConfig))
+	v.validatedConsortium = map[string]time.Time{}
 
-	v.validatedConsortium = map[string]bool{}
+	v.client = didclient.New(v.clientOptions()...)
 
 	return v
 }
@@ -126,16 +176,6 @@ func (v *VDRI) Close() error {
 	return nil
 }
 
-// Store did doc
-func (v *VDRI) Store(doc *docdid.Doc, by *[]vdrapi.ModifiedBy) error {
-	return nil
-}
-
-// Build did doc
-func (v *VDRI) Build(pubKey *vdrapi.PubKey, opts ...vdrapi.DocOpts) (*docdid.Doc, error) {
-	return nil, fmt.Errorf("build method not supported for did bloc")
-}
-
 func (v *VDRI) loadGenesisFiles() error {
 	for _, genesisFile := range v.genesisFiles {
 		err := v.updateValidationService.AddGenesisFile(genesisFile.url, genesisFile.domain, genesisFile.fileData)
@@ -149,58 +189,84 @@ func (v *VDRI) loadGenesisFiles() error {
 	return nil
 }
 
-func (v *VDRI) sidetreeResolve(url, did string, opts ...vdrapi.ResolveOpts) (*docdid.Doc, error) {
-	resolver, err := v.getHTTPVDRI(url)
+func (v *VDRI) sidetreeResolve(ctx context.Context, endpointURL, did string,
+	opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	resolver, err := v.getHTTPVDRI(endpointURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new sidetree vdri: %w", err)
 	}
 
-	doc, err := resolver.Read(did, opts...)
+	start := time.Now()
+
+	// the trace context is not propagated, the vdri not taking a context
+	_, span := v.tracer.Start(ctx, tracing.SpanSidetreeResolve,
+		map[string]string{tracing.AttributeDID: did, tracing.AttributeURL: endpointURL})
+
+	result, err := resolver.Read(did, opts...)
+
+	span.End(err)
+
+	metrics.Record(v.metrics, metrics.SidetreeResolveRequests, metrics.SidetreeResolveDuration,
+		metrics.DomainFromURL(endpointURL), start, err)
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve did: %w", err)
 	}
 
-	return doc, nil
+	return result, nil
 }
 
 const (
 	expectedTrustblocDIDParts = 4
 	domainDIDPart             = 2
+
+	defaultDialTimeout = 30 * time.Second
 )
 
-func (v *VDRI) Read(did string, opts ...vdrapi.ResolveOpts) (*docdid.Doc, error) { //nolint: gocyclo,funlen
+// Read resolves the did
+func (v *VDRI) Read(did string, opts ...vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	ctx, span := v.tracer.Start(context.Background(), tracing.SpanResolve, map[string]string{tracing.AttributeDID: did})
+
+	result, err := v.read(ctx, did, opts)
+
+	span.End(err)
+
+	return result, err
+}
+
+func (v *VDRI) read(ctx context.Context, did string, //nolint: gocyclo,funlen
+	opts []vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
 	err := v.loadGenesisFiles()
 	if err != nil {
 		return nil, fmt.Errorf("invalid genesis file: %w", err)
 	}
 
 	if v.resolverURL != "" {
-		return v.sidetreeResolve(v.resolverURL, did, opts...)
+		return v.sidetreeResolve(ctx, v.resolverURL, did, opts...)
 	}
 
-	// parse did
-	didParts := strings.Split(did, ":")
-	if len(didParts) != expectedTrustblocDIDParts {
-		return nil, fmt.Errorf("wrong did %s", did)
-	}
-
-	domain := didParts[domainDIDPart]
-	if v.domain != "" {
-		domain = v.domain
+	domain, err := v.didDomain(did)
+	if err != nil {
+		return nil, err
 	}
 
 	if v.enableSignatureVerification {
-		if _, ok := v.validatedConsortium[domain]; !ok {
-			_, err = v.ValidateConsortium(domain)
-			if err != nil {
-				return nil, fmt.Errorf("invalid consortium: %w", err)
+		if !v.consortiumValidated(domain) {
+			lifetime, e := v.validateConsortium(ctx, domain)
+			if e != nil {
+				return nil, fmt.Errorf("invalid consortium: %w", e)
 			}
 
-			v.validatedConsortium[domain] = true
+			v.setConsortiumValidated(domain, *lifetime)
 		}
 	}
 
+	_, span := v.tracer.Start(ctx, tracing.SpanEndpointDiscovery, map[string]string{tracing.AttributeDomain: domain})
+
 	endpoints, err := v.endpointService.GetEndpoints(domain)
+
+	span.End(err)
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to get endpoints: %w", err)
 	}
@@ -209,37 +275,150 @@ func (v *VDRI) Read(did string, opts ...vdrapi.ResolveOpts) (*docdid.Doc, error)
 		return nil, errors.New("list of endpoints is empty")
 	}
 
-	var doc *docdid.Doc
+	if v.hedgedRequests > 0 {
+		return v.hedgedResolve(ctx, endpoints, did, opts)
+	}
+
+	var result *docdid.DocResolution
 
 	var docBytes []byte
 
 	for _, e := range endpoints {
-		resp, err := v.sidetreeResolve(e.URL+"/identifiers", did, opts...)
+		resp, err := v.sidetreeResolve(ctx, e.URL+"/identifiers", did, opts...)
 		if err != nil {
 			return nil, err
 		}
 
-		respBytes, err := canonicalizeDoc(resp)
+		respBytes, err := canonicalizeDoc(resp.DIDDocument)
 		if err != nil {
 			return nil, fmt.Errorf("cannot canonicalize resolved doc: %w", err)
 		}
 
-		if doc != nil && !bytes.Equal(docBytes, respBytes) {
-			log.Debugf("mismatch in document contents for did %s. Doc 1: %s, Doc 2: %s",
-				did, string(docBytes), string(respBytes))
+		if result != nil && !bytes.Equal(docBytes, respBytes) {
+			v.logger.Debug("mismatch in document contents", "did", did, "doc1", string(docBytes),
+				"doc2", string(respBytes))
 		}
 
-		doc = resp
+		result = resp
 		docBytes = respBytes
 	}
 
-	return doc, nil
+	return result, nil
+}
+
+// didDomain returns the domain of the VDRI if set, or else the consortium domain of the did
+func (v *VDRI) didDomain(did string) (string, error) {
+	didParts := strings.Split(did, ":")
+	if len(didParts) != expectedTrustblocDIDParts {
+		return "", fmt.Errorf("wrong did %s", did)
+	}
+
+	if v.domain != "" {
+		return v.domain, nil
+	}
+
+	return didParts[domainDIDPart], nil
+}
+
+type resolveResult struct {
+	result *docdid.DocResolution
+	err    error
+}
+
+// hedgedResolve resolves the did with the first endpoint, then with the next one each time the hedge delay elapses
+// or a request fails, up to the maximum number of hedged requests, and returns the first document resolved
+func (v *VDRI) hedgedResolve(ctx context.Context, endpoints []*models.Endpoint, did string,
+	opts []vdrapi.DIDMethodOption) (*docdid.DocResolution, error) {
+	n := v.hedgedRequests
+	if n > len(endpoints) {
+		n = len(endpoints)
+	}
+
+	// buffered for all the requests, so that the ones still running once a document is returned don't block
+	results := make(chan resolveResult, n)
+
+	started := 0
+	startNext := func() {
+		e := endpoints[started]
+		started++
+
+		go func() {
+			result, err := v.sidetreeResolve(ctx, e.URL+"/identifiers", did, opts...)
+			results <- resolveResult{result: result, err: err}
+		}()
+	}
+
+	startNext()
+
+	timer := time.NewTimer(v.hedgeDelay)
+	defer timer.Stop()
+
+	for failed := 0; ; {
+		select {
+		case r := <-results:
+			if r.err == nil {
+				return r.result, nil
+			}
+
+			failed++
+			if failed == n {
+				return nil, r.err
+			}
+
+			if started < n {
+				startNext()
+			}
+		case <-timer.C:
+			if started < n {
+				startNext()
+				timer.Reset(v.hedgeDelay)
+			}
+		}
+	}
 }
 
 // ValidateConsortium validate the config and endorsement of a consortium and its stakeholders
 // returns the duration after which the consortium config expires and needs re-validation
 func (v *VDRI) ValidateConsortium(consortiumDomain string) (*time.Duration, error) {
+	return v.validateConsortium(context.Background(), consortiumDomain)
+}
+
+// consortiumValidated returns true if the validation of the consortium of the domain has not expired. The reads are
+// concurrent, so the validations are guarded by a mutex.
+func (v *VDRI) consortiumValidated(domain string) bool {
+	v.validatedMutex.Lock()
+	defer v.validatedMutex.Unlock()
+
+	expiry, ok := v.validatedConsortium[domain]
+
+	return ok && time.Now().Before(expiry)
+}
+
+func (v *VDRI) setConsortiumValidated(domain string, lifetime time.Duration) {
+	v.validatedMutex.Lock()
+	defer v.validatedMutex.Unlock()
+
+	v.validatedConsortium[domain] = time.Now().Add(lifetime)
+}
+
+func (v *VDRI) validateConsortium(ctx context.Context, consortiumDomain string) (*time.Duration, error) {
+	ctx, span := v.tracer.Start(ctx, tracing.SpanValidateConsortium,
+		map[string]string{tracing.AttributeDomain: consortiumDomain})
+
+	lifetime, err := v.validateConsortiumConfig(ctx, consortiumDomain)
+
+	span.End(err)
+
+	return lifetime, err
+}
+
+func (v *VDRI) validateConsortiumConfig(ctx context.Context, consortiumDomain string) (*time.Duration, error) {
+	_, span := v.tracer.Start(ctx, tracing.SpanConfigFetch, map[string]string{tracing.AttributeDomain: consortiumDomain})
+
 	consortiumConfig, err := v.configService.GetConsortium(consortiumDomain, consortiumDomain)
+
+	span.End(err)
+
 	if err != nil {
 		return nil, fmt.Errorf("consortium invalid: %w", err)
 	}
@@ -249,17 +428,14 @@ func (v *VDRI) ValidateConsortium(consortiumDomain string) (*time.Duration, erro
 		return nil, fmt.Errorf("failed to fetch stakeholders: %w", err)
 	}
 
-	n := consortiumConfig.Config.Policy.NumQueries
-	if n == 0 || n > len(consortiumConfig.Config.Members) {
-		n = len(consortiumConfig.Config.Members)
-	}
+	n := consortiumConfig.Config.NumQueries()
 
 	numVerifications := 0
 
 	verificationErrors := ""
 
 	for _, sfd := range stakeholders {
-		e := v.verifyStakeholder(consortiumConfig, sfd)
+		e := v.verifyStakeholder(ctx, consortiumConfig, sfd)
 		if e != nil {
 			verificationErrors += e.Error() + ", "
 			continue
@@ -280,7 +456,8 @@ func (v *VDRI) ValidateConsortium(consortiumDomain string) (*time.Duration, erro
 	return &lifetime, nil
 }
 
-func (v *VDRI) verifyStakeholder(cfd *models.ConsortiumFileData, sfd *models.StakeholderFileData) error {
+func (v *VDRI) verifyStakeholder(ctx context.Context, cfd *models.ConsortiumFileData,
+	sfd *models.StakeholderFileData) error {
 	s := sfd.Config
 	if s == nil {
 		return fmt.Errorf("stakeholder has nil config")
@@ -293,11 +470,13 @@ func (v *VDRI) verifyStakeholder(cfd *models.ConsortiumFileData, sfd *models.Sta
 
 	ep := s.Endpoints[n.Uint64()]
 
-	doc, e := v.sidetreeResolve(ep+"/identifiers", s.DID)
+	result, e := v.sidetreeResolve(ctx, ep+"/identifiers", s.DID)
 	if e != nil {
 		return fmt.Errorf("can't resolve stakeholder DID: %w", e)
 	}
 
+	doc := result.DIDDocument
+
 	// verify did configuration
 	e = v.didConfigService.VerifyStakeholder(s.Domain, doc)
 	if e != nil {
@@ -319,10 +498,7 @@ func (v *VDRI) verifyStakeholder(cfd *models.ConsortiumFileData, sfd *models.Sta
 
 // select n random stakeholders from the consortium (where n is the consortium's numQueries policy parameter)
 func (v *VDRI) selectStakeholders(consortium *models.Consortium) ([]*models.StakeholderFileData, error) {
-	n := consortium.Policy.NumQueries
-	if n == 0 || n > len(consortium.Members) {
-		n = len(consortium.Members)
-	}
+	n := consortium.NumQueries()
 
 	perm := mathrand.Perm(len(consortium.Members))
 
@@ -393,6 +569,54 @@ func WithTLSConfig(tlsConfig *tls.Config) Option {
 	}
 }
 
+// WithClientCertificates option presents the client certificates to the sidetree endpoints and the consortium domains
+// which require mutual TLS, in addition to the certificates of the tls.Config of WithTLSConfig
+func WithClientCertificates(certs ...tls.Certificate) Option {
+	return func(opts *VDRI) {
+		opts.tlsOpts = append(opts.tlsOpts, tlsconfig.WithClientCertificates(certs...))
+	}
+}
+
+// WithTLSOptions option applies the TLS settings, such as the minimum version or the cipher suites, to the
+// tls.Config of WithTLSConfig, or to a tls.Config with TLS 1.2 as minimum version if not set
+func WithTLSOptions(tlsOpts ...tlsconfig.Option) Option {
+	return func(opts *VDRI) {
+		opts.tlsOpts = append(opts.tlsOpts, tlsOpts...)
+	}
+}
+
+// WithProxyURL option sends the requests of the VDRI through the HTTP proxy, instead of the proxy set by the
+// HTTPS_PROXY and HTTP_PROXY environment variables if any
+func WithProxyURL(proxyURL *url.URL) Option {
+	return func(opts *VDRI) {
+		opts.proxyURL = proxyURL
+	}
+}
+
+// WithDialTimeout option sets how long the VDRI waits for a connection to be established. Defaults to 30 seconds.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(opts *VDRI) {
+		opts.dialTimeout = timeout
+	}
+}
+
+// WithResolver option sets the DNS resolver of the hosts the VDRI connects to, such as a resolver querying the name
+// servers of a locked-down environment. Defaults to the resolver of the system.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(opts *VDRI) {
+		opts.resolver = resolver
+	}
+}
+
+// WithAllowedNetworks option restricts the addresses the VDRI connects to, for its resolutions and its config
+// fetches, to the networks, such as the networks of the consortium domains. The addresses are checked once the hosts
+// are resolved. With a proxy, the address of the proxy is checked instead.
+func WithAllowedNetworks(networks ...*net.IPNet) Option {
+	return func(opts *VDRI) {
+		opts.allowedNetworks = networks
+	}
+}
+
 // WithAuthToken add auth token
 func WithAuthToken(authToken string) Option {
 	return func(opts *VDRI) {
@@ -400,6 +624,67 @@ func WithAuthToken(authToken string) Option {
 	}
 }
 
+// WithWriteToken option sets the sidetree write token of the Create, Update and Deactivate operations
+func WithWriteToken(writeToken string) Option {
+	return func(opts *VDRI) {
+		opts.writeToken = writeToken
+	}
+}
+
+// WithMetricsProvider option records sidetree config, endpoint discovery and sidetree resolution metrics with the
+// given provider. See package promadapter for a provider registering the metrics with the Prometheus client.
+func WithMetricsProvider(p metrics.Provider) Option {
+	return func(opts *VDRI) {
+		opts.metrics = p
+	}
+}
+
+// WithIPFSGateway option sets the HTTP gateway used to fetch consortium and stakeholder configs
+// referenced by ipfs:// URIs
+func WithIPFSGateway(gatewayURL string) Option {
+	return func(opts *VDRI) {
+		opts.ipfsGateway = gatewayURL
+	}
+}
+
+// WithLogger option sets the logger of the VDRI, its config services and its endpoint discovery, to route their
+// output into the logging of the host application. Defaults to logger.Default.
+func WithLogger(l logger.Logger) Option {
+	return func(opts *VDRI) {
+		opts.logger = l
+	}
+}
+
+// WithTracer option traces the resolutions with the tracer: each resolution is a span, with child spans for the
+// consortium validation, the endpoint discovery and the sidetree resolution requests. See package oteltracing for an
+// OpenTelemetry tracer.
+func WithTracer(t tracing.Tracer) Option {
+	return func(opts *VDRI) {
+		opts.tracer = t
+	}
+}
+
+// WithHedgedResolution option resolves the DIDs with the first endpoint of their domain, then races the request
+// against the next endpoints, up to the given number of requests, each time the delay elapses without a response or
+// a request fails, and returns the first document resolved. It cuts the latency of the resolutions when a
+// stakeholder node is slow, for 2 or 3 requests after a delay around the usual latency of a resolution. The
+// documents resolved by the endpoints are then not compared with each other.
+func WithHedgedResolution(delay time.Duration, requests int) Option {
+	return func(opts *VDRI) {
+		opts.hedgeDelay = delay
+		opts.hedgedRequests = requests
+	}
+}
+
+// WithRepresentation option sets the representation of the DID documents asked for in the Accept header of the
+// resolutions, application/did+ld+json, application/did+json or application/did+cbor. It defaults to
+// application/did+ld+json. The documents are read in any of these representations whatever the one asked for.
+func WithRepresentation(mediaType string) Option {
+	return func(opts *VDRI) {
+		opts.representation = mediaType
+	}
+}
+
 // EnableSignatureVerification enables signature verification
 func EnableSignatureVerification(enable bool) Option {
 	return func(opts *VDRI) {
@@ -408,10 +693,10 @@ func EnableSignatureVerification(enable bool) Option {
 }
 
 // UseGenesisFile adds a consortium genesis file to the VDRI and enables consortium config update validation
-func UseGenesisFile(url, domain string, genesisFile []byte) Option {
+func UseGenesisFile(genesisURL, domain string, genesisFile []byte) Option {
 	return func(opts *VDRI) {
 		opts.genesisFiles = append(opts.genesisFiles, genesisFileData{
-			url:      url,
+			url:      genesisURL,
 			domain:   domain,
 			fileData: genesisFile,
 		})
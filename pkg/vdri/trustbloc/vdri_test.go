@@ -6,13 +6,19 @@ SPDX-License-Identifier: Apache-2.0
 package trustbloc
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
@@ -23,8 +29,14 @@ import (
 	mockconfig "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/config"
 	mockdidconf "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/didconfiguration"
 	mockendpoint "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/endpoint"
+	mockmetrics "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/metrics"
+	mocktracing "github.com/trustbloc/trustbloc-did-method/pkg/internal/mock/tracing"
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/didconfiguration"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/metrics"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/models"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/representation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc/tracing"
 )
 
 func TestVDRI_Accept(t *testing.T) {
@@ -39,28 +51,247 @@ func TestVDRI_Accept(t *testing.T) {
 	})
 }
 
-func TestVDRI_Store(t *testing.T) {
-	t.Run("test error", func(t *testing.T) {
-		v := New()
-		err := v.Store(nil, nil)
+func TestVDRI_WithMetricsProvider(t *testing.T) {
+	p := mockmetrics.NewProvider()
+
+	v := New(WithMetricsProvider(p))
+	require.Equal(t, p, v.metrics)
+
+	_, err := v.endpointService.GetEndpoints("https://localhost:0")
+	require.Error(t, err)
+	require.Equal(t, 1, p.Count(metrics.EndpointDiscoveryRequests, "https://localhost:0", metrics.StatusFailure))
+}
+
+func TestVDRI_WithTracer(t *testing.T) {
+	t.Run("test spans of a resolution", func(t *testing.T) {
+		tracer := &mocktracing.Tracer{}
+
+		v := New(WithTracer(tracer))
+
+		v.endpointService = &mockendpoint.MockEndpointService{
+			GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+				return []*models.Endpoint{{URL: "url1"}, {URL: "url2"}}, nil
+			}}
+
+		v.getHTTPVDRI = httpVdriFunc(&did.Doc{ID: "did:trustbloc:testnet:123"}, nil)
+
+		_, err := v.Read("did:trustbloc:testnet:123")
 		require.NoError(t, err)
+
+		spans := tracer.Spans(tracing.SpanResolve)
+		require.Len(t, spans, 1)
+		require.True(t, spans[0].Ended)
+		require.NoError(t, spans[0].Err)
+		require.Equal(t, "did:trustbloc:testnet:123", spans[0].Attributes[tracing.AttributeDID])
+
+		spans = tracer.Spans(tracing.SpanEndpointDiscovery)
+		require.Len(t, spans, 1)
+		require.Equal(t, tracing.SpanResolve, spans[0].Parent)
+		require.Equal(t, "testnet", spans[0].Attributes[tracing.AttributeDomain])
+
+		spans = tracer.Spans(tracing.SpanSidetreeResolve)
+		require.Len(t, spans, 2)
+		require.Equal(t, tracing.SpanResolve, spans[0].Parent)
+		require.Equal(t, "url1/identifiers", spans[0].Attributes[tracing.AttributeURL])
+		require.Equal(t, "url2/identifiers", spans[1].Attributes[tracing.AttributeURL])
+	})
+
+	t.Run("test spans of a failed consortium validation", func(t *testing.T) {
+		tracer := &mocktracing.Tracer{}
+
+		v := New(WithTracer(tracer), EnableSignatureVerification(true))
+
+		v.configService = &mockconfig.MockConfigService{
+			GetConsortiumFunc: func(url, domain string) (*models.ConsortiumFileData, error) {
+				return nil, fmt.Errorf("consortium error")
+			}}
+
+		_, err := v.Read("did:trustbloc:testnet:123")
+		require.Error(t, err)
+
+		spans := tracer.Spans(tracing.SpanResolve)
+		require.Len(t, spans, 1)
+		require.Equal(t, err, spans[0].Err)
+
+		spans = tracer.Spans(tracing.SpanValidateConsortium)
+		require.Len(t, spans, 1)
+		require.Equal(t, tracing.SpanResolve, spans[0].Parent)
+		require.Error(t, spans[0].Err)
+
+		spans = tracer.Spans(tracing.SpanConfigFetch)
+		require.Len(t, spans, 1)
+		require.Equal(t, tracing.SpanValidateConsortium, spans[0].Parent)
+		require.EqualError(t, spans[0].Err, "consortium error")
+		require.Empty(t, tracer.Spans(tracing.SpanEndpointDiscovery))
 	})
 }
 
-func TestVDRI_Build(t *testing.T) {
-	t.Run("test error", func(t *testing.T) {
-		v := New()
-		_, err := v.Build(nil)
+func TestVDRI_WithHedgedResolution(t *testing.T) {
+	endpointService := &mockendpoint.MockEndpointService{
+		GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+			return []*models.Endpoint{{URL: "url1"}, {URL: "url2"}, {URL: "url3"}}, nil
+		}}
+
+	// hedgedVdriFunc resolves the did after the delay of the endpoint, with its error if any
+	hedgedVdriFunc := func(delays map[string]time.Duration, errs map[string]error,
+		requests *int32) func(endpointURL string) (v vdri, err error) {
+		return func(endpointURL string) (v vdri, e error) {
+			return &mockvdr.MockVDR{
+				ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+					atomic.AddInt32(requests, 1)
+					time.Sleep(delays[endpointURL])
+
+					if errs[endpointURL] != nil {
+						return nil, errs[endpointURL]
+					}
+
+					return &did.DocResolution{DIDDocument: &did.Doc{ID: endpointURL}}, nil
+				}}, nil
+		}
+	}
+
+	t.Run("test slow endpoint", func(t *testing.T) {
+		var requests int32
+
+		v := New(WithHedgedResolution(10*time.Millisecond, 2))
+		v.endpointService = endpointService
+		v.getHTTPVDRI = hedgedVdriFunc(map[string]time.Duration{"url1/identifiers": time.Second}, nil, &requests)
+
+		start := time.Now()
+		result, err := v.Read("did:trustbloc:testnet:123")
+		require.NoError(t, err)
+		require.Equal(t, "url2/identifiers", result.DIDDocument.ID)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("test fast endpoint", func(t *testing.T) {
+		var requests int32
+
+		v := New(WithHedgedResolution(time.Second, 2))
+		v.endpointService = endpointService
+		v.getHTTPVDRI = hedgedVdriFunc(nil, nil, &requests)
+
+		result, err := v.Read("did:trustbloc:testnet:123")
+		require.NoError(t, err)
+		require.Equal(t, "url1/identifiers", result.DIDDocument.ID)
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("test failed endpoint", func(t *testing.T) {
+		var requests int32
+
+		v := New(WithHedgedResolution(time.Second, 3))
+		v.endpointService = endpointService
+		v.getHTTPVDRI = hedgedVdriFunc(nil, map[string]error{"url1/identifiers": fmt.Errorf("read error")},
+			&requests)
+
+		start := time.Now()
+		result, err := v.Read("did:trustbloc:testnet:123")
+		require.NoError(t, err)
+		require.Equal(t, "url2/identifiers", result.DIDDocument.ID)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("test all endpoints failed", func(t *testing.T) {
+		var requests int32
+
+		v := New(WithHedgedResolution(time.Millisecond, 5))
+		v.endpointService = endpointService
+		v.getHTTPVDRI = hedgedVdriFunc(map[string]time.Duration{"url1/identifiers": 10 * time.Millisecond},
+			map[string]error{
+				"url1/identifiers": fmt.Errorf("read error"),
+				"url2/identifiers": fmt.Errorf("read error"),
+				"url3/identifiers": fmt.Errorf("read error"),
+			}, &requests)
+
+		result, err := v.Read("did:trustbloc:testnet:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read error")
+		require.Nil(t, result)
+		require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestVDRI_WithProxyURL(t *testing.T) {
+	var requestURI string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+
+		w.Header().Set("Content-type", representation.DIDLDJSON)
+		fmt.Fprint(w, resolvedDoc)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	v := New(WithProxyURL(proxyURL))
+
+	v.endpointService = &mockendpoint.MockEndpointService{
+		GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+			return []*models.Endpoint{{URL: "http://sidetree.example.com"}}, nil
+		}}
+
+	result, err := v.Read("did:trustbloc:testnet:123")
+	require.NoError(t, err)
+	require.Equal(t, "did:trustbloc:testnet:123", result.DIDDocument.ID)
+	require.Equal(t, "http://sidetree.example.com/identifiers/did:trustbloc:testnet:123", requestURI)
+}
+
+func TestVDRI_WithAllowedNetworks(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-type", representation.DIDLDJSON)
+		fmt.Fprint(w, resolvedDoc)
+	}))
+	defer serv.Close()
+
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+
+	_, private, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	endpointService := &mockendpoint.MockEndpointService{
+		GetEndpointsFunc: func(domain string) ([]*models.Endpoint, error) {
+			return []*models.Endpoint{{URL: serv.URL}}, nil
+		}}
+
+	t.Run("test allowed network", func(t *testing.T) {
+		v := New(WithAllowedNetworks(loopback), WithDialTimeout(time.Second), WithResolver(&net.Resolver{}))
+		v.endpointService = endpointService
+
+		result, err := v.Read("did:trustbloc:testnet:123")
+		require.NoError(t, err)
+		require.Equal(t, "did:trustbloc:testnet:123", result.DIDDocument.ID)
+	})
+
+	t.Run("test network not allowed", func(t *testing.T) {
+		v := New(WithAllowedNetworks(private))
+		v.endpointService = endpointService
+
+		_, err := v.Read("did:trustbloc:testnet:123")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "build method not supported for did bloc")
+		require.Contains(t, err.Error(), "is not in the allowed networks")
 	})
 }
 
+func TestVDRI_WithIPFSGateway(t *testing.T) {
+	v := New(WithIPFSGateway("https://gateway.example.com"))
+	require.Equal(t, "https://gateway.example.com", v.ipfsGateway)
+}
+
 func httpVdriFunc(doc *did.Doc, err error) func(url string) (v vdri, err error) {
 	return func(url string) (v vdri, e error) {
 		return &mockvdr.MockVDR{
-			ReadFunc: func(didID string, opts ...vdrapi.ResolveOpts) (*did.Doc, error) {
-				return doc, err
+			ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				if err != nil {
+					return nil, err
+				}
+
+				return &did.DocResolution{DIDDocument: doc}, nil
 			}}, nil
 	}
 }
@@ -172,10 +403,10 @@ func TestVDRI_Read(t *testing.T) {
 			return nil, fmt.Errorf("get http vdri error")
 		}
 
-		doc, err := v.Read("did")
+		result, err := v.Read("did")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "get http vdri error")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 	})
 
 	t.Run("test error from http vdri build for resolver url", func(t *testing.T) {
@@ -183,10 +414,10 @@ func TestVDRI_Read(t *testing.T) {
 
 		v.getHTTPVDRI = httpVdriFunc(nil, fmt.Errorf("read error"))
 
-		doc, err := v.Read("did")
+		result, err := v.Read("did")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "read error")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 	})
 
 	t.Run("test success for resolver url", func(t *testing.T) {
@@ -194,9 +425,9 @@ func TestVDRI_Read(t *testing.T) {
 
 		v.getHTTPVDRI = httpVdriFunc(&did.Doc{ID: "did"}, nil)
 
-		doc, err := v.Read("did")
+		result, err := v.Read("did")
 		require.NoError(t, err)
-		require.Equal(t, "did", doc.ID)
+		require.Equal(t, "did", result.DIDDocument.ID)
 	})
 
 	t.Run("test error parsing did", func(t *testing.T) {
@@ -206,10 +437,10 @@ func TestVDRI_Read(t *testing.T) {
 			return nil, nil
 		}
 
-		doc, err := v.Read("did:1223")
+		result, err := v.Read("did:1223")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "wrong did did:1223")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 	})
 
 	t.Run("test error from get endpoints", func(t *testing.T) {
@@ -220,32 +451,32 @@ func TestVDRI_Read(t *testing.T) {
 				return nil, fmt.Errorf("discover error")
 			}}
 
-		v.validatedConsortium["testnet"] = true
+		v.validatedConsortium["testnet"] = time.Now().Add(time.Hour)
 
-		doc, err := v.Read("did:trustbloc:testnet:123")
+		result, err := v.Read("did:trustbloc:testnet:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "discover error")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 
 		v.endpointService = &mockendpoint.MockEndpointService{
 			GetEndpointsFunc: func(domain string) (endpoints []*models.Endpoint, err error) {
 				return nil, fmt.Errorf("select error")
 			}}
 
-		doc, err = v.Read("did:trustbloc:testnet:123")
+		result, err = v.Read("did:trustbloc:testnet:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "select error")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 
 		v.endpointService = &mockendpoint.MockEndpointService{
 			GetEndpointsFunc: func(domain string) (endpoints []*models.Endpoint, err error) {
 				return nil, nil
 			}}
 
-		doc, err = v.Read("did:trustbloc:testnet:123")
+		result, err = v.Read("did:trustbloc:testnet:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "list of endpoints is empty")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 	})
 
 	t.Run("test error from get http vdri", func(t *testing.T) {
@@ -260,12 +491,12 @@ func TestVDRI_Read(t *testing.T) {
 			return nil, fmt.Errorf("get http vdri error")
 		}
 
-		v.validatedConsortium["testnet"] = true
+		v.validatedConsortium["testnet"] = time.Now().Add(time.Hour)
 
-		doc, err := v.Read("did:trustbloc:testnet:123")
+		result, err := v.Read("did:trustbloc:testnet:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "get http vdri error")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 	})
 
 	t.Run("test error from http vdri read", func(t *testing.T) {
@@ -278,12 +509,52 @@ func TestVDRI_Read(t *testing.T) {
 
 		v.getHTTPVDRI = httpVdriFunc(nil, fmt.Errorf("read error"))
 
-		v.validatedConsortium["testnet"] = true
+		v.validatedConsortium["testnet"] = time.Now().Add(time.Hour)
 
-		doc, err := v.Read("did:trustbloc:testnet:123")
+		result, err := v.Read("did:trustbloc:testnet:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "read error")
-		require.Nil(t, doc)
+		require.Nil(t, result)
+	})
+
+	t.Run("test consortium is validated again once its cache lifetime expires", func(t *testing.T) {
+		v := New(EnableSignatureVerification(true))
+
+		v.configService = &mockconfig.MockConfigService{
+			GetConsortiumFunc: func(u string, d string) (*models.ConsortiumFileData, error) {
+				return nil, fmt.Errorf("consortium error")
+			}}
+
+		v.validatedConsortium["testnet"] = time.Now().Add(-time.Minute)
+
+		result, err := v.Read("did:trustbloc:testnet:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid consortium")
+		require.Nil(t, result)
+	})
+
+	t.Run("test concurrent reads of the validated consortiums", func(t *testing.T) {
+		v := New(EnableSignatureVerification(true))
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func(domain string) {
+				defer wg.Done()
+
+				if !v.consortiumValidated(domain) {
+					v.setConsortiumValidated(domain, time.Hour)
+				}
+			}(fmt.Sprintf("domain%d", i%3))
+		}
+
+		wg.Wait()
+
+		require.Len(t, v.validatedConsortium, 3)
+		require.True(t, v.consortiumValidated("domain1"))
+		require.False(t, v.consortiumValidated("domain3"))
 	})
 
 	//nolint:gocritic
@@ -338,9 +609,9 @@ func TestVDRI_Read(t *testing.T) {
 			},
 		}
 
-		doc, err := v.Read("did:trustbloc:testnet:123")
+		result, err := v.Read("did:trustbloc:testnet:123")
 		require.NoError(t, err)
-		require.Equal(t, "did:trustbloc:testnet:123", doc.ID)
+		require.Equal(t, "did:trustbloc:testnet:123", result.DIDDocument.ID)
 	})
 }
 
@@ -379,10 +650,10 @@ func TestVDRI_loadGenesisFiles(t *testing.T) {
 
 		v := New(UseGenesisFile("url", "domain", []byte(confFile)))
 
-		doc, err := v.Read("blah blah")
+		result, err := v.Read("blah blah")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error loading consortium genesis config")
-		require.Nil(t, doc)
+		require.Nil(t, result)
 	})
 }
 
@@ -770,7 +1041,7 @@ func Test_verifyStakeholder(t *testing.T) {
 				},
 			}
 
-			err = v.verifyStakeholder(cfd, sfd)
+			err = v.verifyStakeholder(context.Background(), cfd, sfd)
 
 			if test.isErr {
 				require.Error(t, err)
@@ -1012,4 +1283,25 @@ func TestOpts(t *testing.T) {
 
 		require.Equal(t, true, v.enableSignatureVerification)
 	})
+
+	t.Run("test client certificates", func(t *testing.T) {
+		cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+
+		v := &VDRI{}
+		require.Nil(t, v.transportTLSConfig())
+
+		WithTLSConfig(&tls.Config{ServerName: "test", MinVersion: tls.VersionTLS12})(v)
+		WithClientCertificates(cert)(v)
+
+		tlsConfig := v.transportTLSConfig()
+		require.Equal(t, "test", tlsConfig.ServerName)
+		require.Equal(t, []tls.Certificate{cert}, tlsConfig.Certificates)
+		require.Empty(t, v.tlsConfig.Certificates)
+
+		WithTLSOptions(tlsconfig.WithInsecureSkipVerify(true))(v)
+
+		tlsConfig = v.transportTLSConfig()
+		require.True(t, tlsConfig.InsecureSkipVerify)
+		require.Equal(t, []tls.Certificate{cert}, tlsConfig.Certificates)
+	})
 }
@@ -13,13 +13,12 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
-	"strings"
 	"testing"
 	"time"
 
 	"github.com/cucumber/godog"
 
-	"github.com/trustbloc/trustbloc-did-method/test/bdd/dockerutil"
+	"github.com/trustbloc/trustbloc-did-method/pkg/testutil/harness"
 	"github.com/trustbloc/trustbloc-did-method/test/bdd/pkg/cli"
 	"github.com/trustbloc/trustbloc-did-method/test/bdd/pkg/common"
 	bddctx "github.com/trustbloc/trustbloc-did-method/test/bdd/pkg/context"
@@ -50,67 +49,38 @@ func TestMain(m *testing.M) {
 	os.Exit(status)
 }
 
-// nolint: gocognit,gocyclo
 func runBDDTests(tags, format string) int {
 	return godog.RunWithOptions("godogs", func(s *godog.Suite) {
-		var composition []*dockerutil.Composition
+		var environment *harness.Environment
 		var composeFiles = []string{"./fixtures/did-method-rest", "./fixtures/universalresolver",
 			"./fixtures/sidetree-mock",
 			"./fixtures/universal-registrar"}
 		var discoveryServers = []string{"./fixtures/discovery-server", "./fixtures/stakeholder-server"}
 
 		s.BeforeSuite(func() {
-			if os.Getenv("DISABLE_COMPOSITION") != "true" { // nolint: nestif
-				// Need a unique name, but docker does not allow '-' in names
-				composeProjectName := strings.ReplaceAll(generateUUID(), "-", "")
-
-				for _, v := range composeFiles {
-					newComposition, err := dockerutil.NewComposition(composeProjectName, "docker-compose.yml", v)
-					if err != nil {
-						panic(fmt.Sprintf("Error composing system in BDD context: %s", err))
-					}
-					composition = append(composition, newComposition)
-				}
-				fmt.Println("docker-compose up ... waiting for containers to start ...")
-				testSleep := 20
-				if os.Getenv("TEST_SLEEP") != "" {
-					var e error
-
-					testSleep, e = strconv.Atoi(os.Getenv("TEST_SLEEP"))
-					if e != nil {
-						panic(fmt.Sprintf("Invalid value found in 'TEST_SLEEP': %s", e))
-					}
-				}
-				fmt.Printf("*** testSleep=%d \n", testSleep)
-				time.Sleep(time.Second * time.Duration(testSleep))
+			if os.Getenv("DISABLE_COMPOSITION") == "true" {
+				return
+			}
 
-				// create config files
-				_, err := execCMD("./generate_config.sh")
-				if err != nil {
-					panic(err.Error())
-				}
+			environment = harness.NewEnvironment(harness.WithStartupDelay(testSleep()))
 
-				for _, v := range discoveryServers {
-					newComposition, err := dockerutil.NewComposition(composeProjectName, "docker-compose.yml", v)
-					if err != nil {
-						panic(fmt.Sprintf("Error composing system in BDD context: %s", err))
-					}
-					composition = append(composition, newComposition)
-				}
+			if err := environment.Compose(composeFiles...); err != nil {
+				panic(fmt.Sprintf("Error composing system in BDD context: %s", err))
+			}
 
-				fmt.Printf("*** testSleep=%d", testSleep)
-				time.Sleep(time.Second * time.Duration(testSleep))
+			// create config files
+			if _, err := execCMD("./generate_config.sh"); err != nil {
+				panic(err.Error())
+			}
+
+			if err := environment.Compose(discoveryServers...); err != nil {
+				panic(fmt.Sprintf("Error composing system in BDD context: %s", err))
 			}
 		})
 		s.AfterSuite(func() {
-			for _, c := range composition {
-				if c != nil {
-					if err := c.GenerateLogs(c.Dir, "docker-compose.log"); err != nil {
-						panic(err)
-					}
-					if _, err := c.Decompose(c.Dir); err != nil {
-						panic(err)
-					}
+			if environment != nil {
+				if err := environment.Decompose("docker-compose.log"); err != nil {
+					panic(err)
 				}
 			}
 
@@ -162,10 +132,22 @@ func getCmdArg(argName string) string {
 	return ""
 }
 
-// generateUUID returns a UUID based on RFC 4122
-func generateUUID() string {
-	id := dockerutil.GenerateBytesUUID()
-	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+// testSleep returns the time waited for the containers to start, from TEST_SLEEP in seconds
+func testSleep() time.Duration {
+	testSleep := 20
+
+	if os.Getenv("TEST_SLEEP") != "" {
+		var e error
+
+		testSleep, e = strconv.Atoi(os.Getenv("TEST_SLEEP"))
+		if e != nil {
+			panic(fmt.Sprintf("Invalid value found in 'TEST_SLEEP': %s", e))
+		}
+	}
+
+	fmt.Printf("*** testSleep=%d \n", testSleep)
+
+	return time.Second * time.Duration(testSleep)
 }
 
 func FeatureContext(s *godog.Suite) {
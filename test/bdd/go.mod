@@ -12,14 +12,14 @@ go 1.15
 
 require (
 	github.com/cucumber/godog v0.9.0
-	github.com/fsouza/go-dockerclient v1.6.0
 	github.com/google/uuid v1.1.2
-	github.com/hyperledger/aries-framework-go v0.1.5-0.20201110161050-249e1c428734
+	github.com/hyperledger/aries-framework-go v0.1.7-0.20210603210127-e57b8c94e3cf
+	github.com/hyperledger/aries-framework-go/component/storageutil v0.0.0-20210520055214-ae429bb89bf7
+	github.com/hyperledger/aries-framework-go/spi v0.0.0-20210520055214-ae429bb89bf7
 	github.com/sirupsen/logrus v1.6.0
-	github.com/tidwall/gjson v1.6.0
+	github.com/tidwall/gjson v1.6.7
 	github.com/trustbloc/edge-core v0.1.5-0.20201106164919-76ecfeca954f
 	github.com/trustbloc/trustbloc-did-method v0.0.0
-	gotest.tools/v3 v3.0.3 // indirect
 )
 
 // https://github.com/ory/dockertest/issues/208#issuecomment-686820414
@@ -50,12 +50,12 @@ func (e *Steps) resolveDID(did string) (*ariesdid.Doc, error) {
 	blocVDRI := trustbloc.New(trustbloc.WithTLSConfig(e.bddContext.TLSConfig),
 		trustbloc.WithAuthToken("rw_token"), trustbloc.WithDomain("testnet.trustbloc.local"))
 
-	var doc *ariesdid.Doc
+	var result *ariesdid.DocResolution
 
 	var err error
 
 	for i := 1; i <= maxRetry; i++ {
-		doc, err = blocVDRI.Read(did)
+		result, err = blocVDRI.Read(did)
 
 		if err != nil && (!strings.Contains(err.Error(), "DID does not exist") || i == maxRetry) {
 			return nil, err
@@ -64,7 +64,7 @@ func (e *Steps) resolveDID(did string) (*ariesdid.Doc, error) {
 		time.Sleep(1 * time.Second)
 	}
 
-	return doc, nil
+	return result.DIDDocument, nil
 }
 
 func (e *Steps) checkCreatedDID() error {
@@ -212,7 +212,7 @@ func (e *Steps) updateDID(domain, sidetreeURL string) error {
 		"--sidetree-write-token", "rw_token", "--signingkey-file", "./fixtures/keys/update/key_encrypted.pem",
 		"--signingkey-password", "123", "--nextupdatekey-file", "./fixtures/keys/update2/public.pem",
 		"--remove-publickey-id", "key1", "--remove-service-id", "svc1", "--remove-service-id", "svc2",
-		"--add-service-file", "fixtures/did-services/update/services.json")
+		"--add-service-file", "fixtures/did-services/update/services.json", "--wait", "true")
 
 	value, err := execCMD(args...)
 
@@ -267,7 +267,7 @@ func (e *Steps) deactivateDID(domain, sidetreeURL string) error {
 
 	args = append(args, "deactivate-did", "--did-uri", e.createdDID.ID, "--signingkey-password", "123",
 		"--tls-cacerts", "fixtures/keys/tls/ec-cacert.pem", "--sidetree-write-token", "rw_token",
-		"--signingkey-file", "./fixtures/keys/recover2/key_encrypted.pem")
+		"--signingkey-file", "./fixtures/keys/recover2/key_encrypted.pem", "--yes", "true")
 
 	value, err := execCMD(args...)
 
@@ -294,7 +294,8 @@ func (e *Steps) createDID(domain, sidetreeURL string) error {
 	args = append(args, "create-did",
 		"--tls-cacerts", "fixtures/keys/tls/ec-cacert.pem", "--publickey-file", "fixtures/did-keys/create/publickeys.json",
 		"--sidetree-write-token", "rw_token", "--service-file", "fixtures/did-services/create/services.json",
-		"--recoverykey-file", "./fixtures/keys/recover/public.pem", "--updatekey-file", "./fixtures/keys/update/public.pem")
+		"--recoverykey-file", "./fixtures/keys/recover/public.pem", "--updatekey-file", "./fixtures/keys/update/public.pem",
+		"--wait", "true")
 
 	value, err := execCMD(args...)
 
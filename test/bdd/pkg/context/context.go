@@ -9,13 +9,15 @@ import (
 	"crypto/tls"
 	"fmt"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	ariescontext "github.com/hyperledger/aries-framework-go/pkg/framework/context"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
-	"github.com/hyperledger/aries-framework-go/pkg/storage"
-	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/trustbloc-did-method/pkg/tlsconfig"
 )
 
 const masterKeyURI = "local-lock://custom/master/key/"
@@ -38,7 +40,7 @@ func NewBDDContext(caCertPaths ...string) (*BDDContext, error) {
 		return nil, err
 	}
 
-	return &BDDContext{TLSConfig: &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}, LocalKMS: km}, nil
+	return &BDDContext{TLSConfig: tlsconfig.New(tlsconfig.WithRootCAs(rootCAs)), LocalKMS: km}, nil
 }
 
 func createKMS(s storage.Provider) (kms.KeyManager, error) {
@@ -12,17 +12,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/cucumber/godog"
 	"github.com/google/uuid"
-	ariesdid "github.com/hyperledger/aries-framework-go/pkg/doc/did"
-	"github.com/hyperledger/aries-framework-go/pkg/kms"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
 
 	"github.com/trustbloc/trustbloc-did-method/pkg/did/doc"
 	"github.com/trustbloc/trustbloc-did-method/pkg/restapi/didmethod/operation"
+	"github.com/trustbloc/trustbloc-did-method/pkg/testutil/harness"
 	"github.com/trustbloc/trustbloc-did-method/pkg/vdri/trustbloc"
 	"github.com/trustbloc/trustbloc-did-method/test/bdd/pkg/context"
 )
@@ -30,8 +27,6 @@ import (
 const (
 	maxRetry  = 10
 	serviceID = "service"
-	// P256KeyType EC P-256 key type
-	P256KeyType = "P256"
 )
 
 // Steps is steps for VC BDD tests
@@ -128,95 +123,29 @@ func (e *Steps) resolveCreatedDID(url, keyType, signatureSuite string) error {
 	blocVDRI := trustbloc.New(trustbloc.WithResolverURL(url), trustbloc.WithTLSConfig(e.bddContext.TLSConfig),
 		trustbloc.WithAuthToken("rw_token"), trustbloc.WithDomain("testnet.trustbloc.local"))
 
-	var didDoc *ariesdid.Doc
-
-	for i := 1; i <= maxRetry; i++ {
-		var err error
-		didDoc, err = blocVDRI.Read(e.createdDID)
-
-		if err != nil && (!strings.Contains(err.Error(), "DID does not exist") || i == maxRetry) {
-			return err
-		}
-
-		time.Sleep(1 * time.Second)
+	result, err := harness.ResolveDID(blocVDRI, e.createdDID, maxRetry, time.Second)
+	if err != nil {
+		return err
 	}
 
-	if didDoc.ID != e.createdDID {
-		return fmt.Errorf("resolved did %s not equal to created did %s", didDoc.ID, e.createdDID)
-	}
+	didDoc := result.DIDDocument
 
-	if didDoc.Service[0].ID != didDoc.ID+"#"+serviceID {
-		return fmt.Errorf("resolved did service ID %s not equal to %s",
-			didDoc.Service[0].ID, didDoc.ID+"#"+serviceID)
+	if err = harness.CheckDID(didDoc, e.createdDID); err != nil {
+		return err
 	}
 
-	if err := validatePublicKey(didDoc, keyType, signatureSuite); err != nil {
+	if err = harness.CheckService(didDoc, serviceID); err != nil {
 		return err
 	}
 
-	return nil
+	return harness.CheckPublicKey(didDoc, keyType, signatureSuite)
 }
 
 func (e *Steps) getPublicKey(keyType string) (string, []byte, error) {
-	var kt kms.KeyType
-
-	switch keyType {
-	case doc.Ed25519KeyType:
-		kt = kms.ED25519Type
-	case P256KeyType:
-		kt = kms.ECDSAP256TypeIEEEP1363
-	}
-
-	return e.bddContext.LocalKMS.CreateAndExportPubKeyBytes(kt)
-}
-
-func validatePublicKey(didDoc *ariesdid.Doc, keyType, signatureSuite string) error {
-	if len(didDoc.VerificationMethod) != 1 {
-		return fmt.Errorf("veification method size not equal one")
-	}
-
-	expectedJwkKeyType := ""
-
-	var kt kms.KeyType
-
-	switch keyType {
-	case doc.Ed25519KeyType:
-		expectedJwkKeyType = "OKP"
-		kt = kms.ED25519Type
-	case P256KeyType:
-		expectedJwkKeyType = "EC"
-		kt = kms.ECDSAP256TypeIEEEP1363
-	}
-
-	if signatureSuite == doc.JWSVerificationKey2020 &&
-		expectedJwkKeyType != didDoc.VerificationMethod[0].JSONWebKey().Kty {
-		return fmt.Errorf("jwk key type : expected=%s actual=%s", expectedJwkKeyType,
-			didDoc.VerificationMethod[0].JSONWebKey().Kty)
-	}
-
-	if signatureSuite == doc.Ed25519VerificationKey2018 &&
-		didDoc.VerificationMethod[0].JSONWebKey() != nil {
-		return fmt.Errorf("jwk is not nil for %s", signatureSuite)
-	}
-
-	return verifyPublicKeyAndType(didDoc, kt, signatureSuite)
-}
-
-func verifyPublicKeyAndType(didDoc *ariesdid.Doc, kt kms.KeyType, signatureSuite string) error {
-	pubKeyID, err := localkms.CreateKID(didDoc.VerificationMethod[0].Value, kt)
+	kt, err := harness.KMSKeyType(keyType)
 	if err != nil {
-		return err
-	}
-
-	if didDoc.VerificationMethod[0].ID != didDoc.ID+"#"+pubKeyID {
-		return fmt.Errorf("resolved did public key ID %s not equal to %s",
-			didDoc.VerificationMethod[0].ID, didDoc.ID+"#"+pubKeyID)
+		return "", nil, err
 	}
 
-	if didDoc.VerificationMethod[0].Type != signatureSuite {
-		return fmt.Errorf("resolved did public key type %s not equal to %s",
-			didDoc.VerificationMethod[0].Type, signatureSuite)
-	}
-
-	return nil
+	return e.bddContext.LocalKMS.CreateAndExportPubKeyBytes(kt)
 }